@@ -3,18 +3,24 @@ package main
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 
 	"nutrient_be/internal/config"
 	"nutrient_be/internal/database"
+	"nutrient_be/internal/dto/request"
 	"nutrient_be/internal/pkg/logger"
+	"nutrient_be/internal/pkg/textnorm"
 )
 
 // Migrate flags
@@ -46,28 +52,51 @@ Examples:
   nutrient-api migrate --dry-run
 
   # Force run (skip confirmation prompts)
-  nutrient-api migrate --force`,
+  nutrient-api migrate --force
+
+  # Show applied migrations and missing indexes
+  nutrient-api migrate status`,
 	Run: func(cmd *cobra.Command, args []string) {
 		runMigrations()
 	},
 }
 
+var migrateStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show applied migrations and index status",
+	Long: `List the applied and pending migrations, and each collection's current indexes
+and whether the indexes the application expects are present.
+
+Exits with status 1 when a migration is pending or an expected index is missing.
+
+Examples:
+  nutrient-api migrate status
+  nutrient-api migrate status --env=prod`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if !runMigrateStatus() {
+			os.Exit(1)
+		}
+	},
+}
+
 func init() {
 	rootCmd.AddCommand(migrateCmd)
 
-	// Migration flags
-	migrateCmd.Flags().StringVarP(&migrateConfigPath, "config", "c", "", "Path to configuration file")
-	migrateCmd.Flags().StringVarP(&migrateEnvironment, "env", "e", "", "Environment (dev, staging, prod)")
-	migrateCmd.Flags().StringVarP(&migrateDbURI, "db-uri", "", "", "MongoDB connection URI (overrides config)")
-	migrateCmd.Flags().StringVarP(&migrateDbName, "db-name", "", "", "MongoDB database name (overrides config)")
+	migrateCmd.AddCommand(migrateStatusCmd)
+
+	// Migration flags; connection flags are shared with the status subcommand
+	migrateCmd.PersistentFlags().StringVarP(&migrateConfigPath, "config", "c", "", "Path to configuration file")
+	migrateCmd.PersistentFlags().StringVarP(&migrateEnvironment, "env", "e", "", "Environment (dev, staging, prod)")
+	migrateCmd.PersistentFlags().StringVarP(&migrateDbURI, "db-uri", "", "", "MongoDB connection URI (overrides config)")
+	migrateCmd.PersistentFlags().StringVarP(&migrateDbName, "db-name", "", "", "MongoDB database name (overrides config)")
 	migrateCmd.Flags().BoolVarP(&migrateDryRun, "dry-run", "", false, "Show what would be done without executing")
 	migrateCmd.Flags().BoolVarP(&migrateForce, "force", "f", false, "Skip confirmation prompts")
 
 	// Bind flags to viper
-	viper.BindPFlag("migrate.config", migrateCmd.Flags().Lookup("config"))
-	viper.BindPFlag("migrate.env", migrateCmd.Flags().Lookup("env"))
-	viper.BindPFlag("migrate.db_uri", migrateCmd.Flags().Lookup("db-uri"))
-	viper.BindPFlag("migrate.db_name", migrateCmd.Flags().Lookup("db-name"))
+	viper.BindPFlag("migrate.config", migrateCmd.PersistentFlags().Lookup("config"))
+	viper.BindPFlag("migrate.env", migrateCmd.PersistentFlags().Lookup("env"))
+	viper.BindPFlag("migrate.db_uri", migrateCmd.PersistentFlags().Lookup("db-uri"))
+	viper.BindPFlag("migrate.db_name", migrateCmd.PersistentFlags().Lookup("db-name"))
 	viper.BindPFlag("migrate.dry_run", migrateCmd.Flags().Lookup("dry-run"))
 	viper.BindPFlag("migrate.force", migrateCmd.Flags().Lookup("force"))
 	viper.BindPFlag("migrate.force", migrateCmd.Flags().Lookup("force"))
@@ -128,11 +157,79 @@ func runMigrations() {
 	log.Info(context.Background(), "Migrations completed successfully")
 }
 
+// runMigrateStatus prints the applied migrations and compares each collection's indexes with the expected ones
+// It reports whether every migration is applied and every expected index exists
+func runMigrateStatus() bool {
+	cfg, err := loadMigrateConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load config: %v\n", err)
+		os.Exit(1)
+	}
+
+	log, err := logger.NewZapLogger(cfg.Logger.Development)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to initialize logger: %v\n", err)
+		os.Exit(1)
+	}
+
+	mongoDB, err := database.NewMongoDB(&cfg.Database, log)
+	if err != nil {
+		log.Fatal(context.Background(), "Failed to connect to MongoDB", logger.Error(err))
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := mongoDB.Close(ctx); err != nil {
+			log.Error(context.Background(), "Failed to close MongoDB connection", logger.Error(err))
+		}
+	}()
+
+	ctx := context.Background()
+	applied, pending, err := mongoDB.MigrationStatus(ctx)
+	if err != nil {
+		log.Fatal(ctx, "Failed to read migration status", logger.Error(err))
+	}
+	indexes, err := mongoDB.IndexStatus(ctx)
+	if err != nil {
+		log.Fatal(ctx, "Failed to read index status", logger.Error(err))
+	}
+
+	return printMigrateStatus(os.Stdout, cfg.Database.Database, applied, pending, indexes)
+}
+
+// printMigrateStatus writes the migration and index status report and reports whether everything is in place
+func printMigrateStatus(w io.Writer, dbName string, applied, pending []string, indexes []database.CollectionIndexStatus) bool {
+	upToDate := len(pending) == 0
+
+	fmt.Fprintf(w, "Database: %s\n\n", dbName)
+	fmt.Fprintln(w, "Migrations:")
+	for _, id := range applied {
+		fmt.Fprintf(w, "  [applied] %s\n", id)
+	}
+	for _, id := range pending {
+		fmt.Fprintf(w, "  [pending] %s\n", id)
+	}
+
+	for _, status := range indexes {
+		fmt.Fprintf(w, "\nCollection %s\n", status.Collection)
+		fmt.Fprintf(w, "  Current indexes: %s\n", strings.Join(status.Existing, ", "))
+		for _, expected := range status.Expected {
+			state := "present"
+			if !expected.Present {
+				state = "missing"
+				upToDate = false
+			}
+			fmt.Fprintf(w, "  [%s] %s\n", state, expected.Name)
+		}
+	}
+	return upToDate
+}
+
 func loadMigrateConfig() (*config.Config, error) {
 	// Set default values
 	viper.SetDefault("env", "dev")
 	viper.SetDefault("logger.level", "info")
-	viper.SetDefault("logger.format", "console")
+	viper.SetDefault("logger.encoding", "console")
 
 	// Determine config file path
 	if migrateConfigPath == "" {
@@ -160,7 +257,7 @@ func loadMigrateConfig() (*config.Config, error) {
 		viper.Set("database.uri", migrateDbURI)
 	}
 	if migrateDbName != "" {
-		viper.Set("database.name", migrateDbName)
+		viper.Set("database.database", migrateDbName)
 	}
 
 	// Unmarshal config
@@ -173,70 +270,195 @@ func loadMigrateConfig() (*config.Config, error) {
 }
 
 func runDatabaseMigrations(mongoDB *database.MongoDB, log logger.Logger) error {
-	// Create indexes
-	collections := []string{"users", "foods", "meal_templates", "meal_plans", "shopping_lists"}
-
-	for _, collectionName := range collections {
-		collection := mongoDB.GetCollection(collectionName)
-
+	// Create indexes; ones that already exist are skipped so migrations can be re-run
+	for _, collectionName := range database.IndexCollections() {
 		if migrateDryRun {
 			log.Info(context.Background(), "DRY RUN: Would create indexes for collection", logger.String("collection", collectionName))
 			continue
 		}
 
-		// Create indexes based on collection
-		switch collectionName {
-		case "users":
-			_, err := collection.Indexes().CreateOne(context.Background(), mongo.IndexModel{
-				Keys:    bson.M{"email": 1},
-				Options: options.Index().SetUnique(true),
-			})
-			if err != nil {
-				return fmt.Errorf("failed to create users index: %w", err)
-			}
-		case "foods":
-			indexes := []mongo.IndexModel{
-				{Keys: bson.M{"searchTerms": "text"}},
-				{Keys: bson.M{"createdBy": 1, "visibility": 1}},
-				{Keys: bson.M{"category": 1}},
-				{Keys: bson.M{"source": 1}},
-			}
-			_, err := collection.Indexes().CreateMany(context.Background(), indexes)
-			if err != nil {
-				return fmt.Errorf("failed to create foods indexes: %w", err)
-			}
-		case "meal_templates":
-			indexes := []mongo.IndexModel{
-				{Keys: bson.M{"userId": 1, "mealType": 1}},
-				{Keys: bson.M{"userId": 1, "isPublic": 1}},
-			}
-			_, err := collection.Indexes().CreateMany(context.Background(), indexes)
-			if err != nil {
-				return fmt.Errorf("failed to create meal_templates indexes: %w", err)
-			}
-		case "meal_plans":
-			indexes := []mongo.IndexModel{
-				{Keys: bson.M{"userId": 1, "startDate": -1}},
-				{Keys: bson.M{"userId": 1, "planType": 1}},
-				{Keys: bson.M{"userId": 1, "status": 1}},
-			}
-			_, err := collection.Indexes().CreateMany(context.Background(), indexes)
-			if err != nil {
-				return fmt.Errorf("failed to create meal_plans indexes: %w", err)
-			}
-		case "shopping_lists":
-			indexes := []mongo.IndexModel{
-				{Keys: bson.M{"userId": 1, "mealPlanId": 1}},
-				{Keys: bson.M{"userId": 1, "status": 1}},
-			}
-			_, err := collection.Indexes().CreateMany(context.Background(), indexes)
-			if err != nil {
-				return fmt.Errorf("failed to create shopping_lists indexes: %w", err)
+		created, err := mongoDB.EnsureIndexes(context.Background(), collectionName)
+		if err != nil {
+			return err
+		}
+
+		log.Info(context.Background(), "Ensured indexes for collection",
+			logger.String("collection", collectionName),
+			logger.Int("created", len(created)))
+	}
+
+	if migrateDryRun {
+		return nil
+	}
+
+	// Record the migration so readiness can report the schema as up to date
+	if err := mongoDB.RecordMigration(context.Background(), "0001_create_indexes"); err != nil {
+		return err
+	}
+
+	if err := normalizeFoodSearchTerms(mongoDB, log); err != nil {
+		return err
+	}
+	if err := mongoDB.RecordMigration(context.Background(), "0002_normalize_search_terms"); err != nil {
+		return err
+	}
+
+	if err := normalizeUserEmails(mongoDB, log); err != nil {
+		return err
+	}
+	if err := mongoDB.RecordMigration(context.Background(), "0003_normalize_user_emails"); err != nil {
+		return err
+	}
+
+	if err := backfillShoppingItemIDs(mongoDB, log); err != nil {
+		return err
+	}
+	if err := mongoDB.RecordMigration(context.Background(), "0004_backfill_shopping_item_ids"); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// normalizeFoodSearchTerms rewrites every food's search terms without diacritics and adds its names,
+// matching what the food service stores, so accent-insensitive search finds foods created earlier
+func normalizeFoodSearchTerms(mongoDB *database.MongoDB, log logger.Logger) error {
+	ctx := context.Background()
+	collection := mongoDB.GetCollection("foods")
+
+	cursor, err := collection.Find(ctx, bson.M{}, options.Find().SetProjection(bson.M{"name": 1, "searchTerms": 1}))
+	if err != nil {
+		return fmt.Errorf("failed to list foods: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	updated := 0
+	for cursor.Next(ctx) {
+		var food struct {
+			ID          interface{}       `bson:"_id"`
+			Name        map[string]string `bson:"name"`
+			SearchTerms []string          `bson:"searchTerms"`
+		}
+		if err := cursor.Decode(&food); err != nil {
+			return fmt.Errorf("failed to decode food: %w", err)
+		}
+
+		langs := make([]string, 0, len(food.Name))
+		for lang := range food.Name {
+			langs = append(langs, lang)
+		}
+		sort.Strings(langs)
+		terms := food.SearchTerms
+		for _, lang := range langs {
+			terms = append(terms, food.Name[lang])
+		}
+
+		if _, err := collection.UpdateByID(ctx, food.ID, bson.M{"$set": bson.M{"searchTerms": textnorm.SearchTerms(terms...)}}); err != nil {
+			return fmt.Errorf("failed to update food search terms: %w", err)
+		}
+		updated++
+	}
+	if err := cursor.Err(); err != nil {
+		return fmt.Errorf("failed to iterate foods: %w", err)
+	}
+
+	log.Info(ctx, "Normalized food search terms", logger.Int("foods", updated))
+	return nil
+}
+
+// normalizeUserEmails rewrites every user's email in the lower-cased, trimmed form new accounts are stored in,
+// so accounts registered before emails were normalized can still log in. An email that would collide with
+// another account's is left as it was and logged, since merging accounts needs a person to decide.
+func normalizeUserEmails(mongoDB *database.MongoDB, log logger.Logger) error {
+	ctx := context.Background()
+	collection := mongoDB.GetCollection("users")
+
+	cursor, err := collection.Find(ctx, bson.M{}, options.Find().SetProjection(bson.M{"email": 1}))
+	if err != nil {
+		return fmt.Errorf("failed to list users: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	updated := 0
+	for cursor.Next(ctx) {
+		var user struct {
+			ID    primitive.ObjectID `bson:"_id"`
+			Email string             `bson:"email"`
+		}
+		if err := cursor.Decode(&user); err != nil {
+			return fmt.Errorf("failed to decode user: %w", err)
+		}
+
+		email := request.NormalizeEmail(user.Email)
+		if email == user.Email {
+			continue
+		}
+		if _, err := collection.UpdateByID(ctx, user.ID, bson.M{"$set": bson.M{"email": email}}); err != nil {
+			if mongo.IsDuplicateKeyError(err) {
+				log.Warn(ctx, "Skipped normalizing an email already used by another account",
+					logger.String("user_id", user.ID.Hex()))
+				continue
 			}
+			return fmt.Errorf("failed to update user email: %w", err)
 		}
+		updated++
+	}
+	if err := cursor.Err(); err != nil {
+		return fmt.Errorf("failed to iterate users: %w", err)
+	}
+
+	log.Info(ctx, "Normalized user emails", logger.Int("users", updated))
+	return nil
+}
+
+// backfillShoppingItemIDs gives every shopping list item stored without an ID a new one,
+// so items of lists generated before items had IDs can be checked off or removed
+func backfillShoppingItemIDs(mongoDB *database.MongoDB, log logger.Logger) error {
+	ctx := context.Background()
+	collection := mongoDB.GetCollection("shopping_lists")
 
-		log.Info(context.Background(), "Created indexes for collection", logger.String("collection", collectionName))
+	cursor, err := collection.Find(ctx, bson.M{}, options.Find().SetProjection(bson.M{"items": 1}))
+	if err != nil {
+		return fmt.Errorf("failed to list shopping lists: %w", err)
 	}
+	defer cursor.Close(ctx)
+
+	updated := 0
+	for cursor.Next(ctx) {
+		var list struct {
+			ID    primitive.ObjectID `bson:"_id"`
+			Items []bson.M           `bson:"items"`
+		}
+		if err := cursor.Decode(&list); err != nil {
+			return fmt.Errorf("failed to decode shopping list: %w", err)
+		}
 
+		if !assignMissingItemIDs(list.Items) {
+			continue
+		}
+		if _, err := collection.UpdateByID(ctx, list.ID, bson.M{"$set": bson.M{"items": list.Items}}); err != nil {
+			return fmt.Errorf("failed to update shopping list items: %w", err)
+		}
+		updated++
+	}
+	if err := cursor.Err(); err != nil {
+		return fmt.Errorf("failed to iterate shopping lists: %w", err)
+	}
+
+	log.Info(ctx, "Backfilled shopping item IDs", logger.Int("shopping_lists", updated))
 	return nil
 }
+
+// assignMissingItemIDs sets a new ID on each item whose ID is missing or zero and reports whether any was set
+// Items are kept as raw documents so fields this build does not know about survive the rewrite
+func assignMissingItemIDs(items []bson.M) bool {
+	assigned := false
+	for _, item := range items {
+		if id, ok := item["id"].(primitive.ObjectID); ok && !id.IsZero() {
+			continue
+		}
+		item["id"] = primitive.NewObjectID()
+		assigned = true
+	}
+	return assigned
+}
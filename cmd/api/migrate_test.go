@@ -0,0 +1,32 @@
+package main
+
+import (
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestAssignMissingItemIDs(t *testing.T) {
+	existing := primitive.NewObjectID()
+	items := []bson.M{
+		{"id": existing, "foodName": "Rice"},
+		{"foodName": "Eggs"},
+		{"id": primitive.NilObjectID, "foodName": "Milk"},
+	}
+
+	if !assignMissingItemIDs(items) {
+		t.Fatal("Expected missing IDs to be assigned")
+	}
+	if items[0]["id"] != existing {
+		t.Errorf("Expected an existing ID to be kept, got: %v", items[0]["id"])
+	}
+	for _, item := range items[1:] {
+		if id, ok := item["id"].(primitive.ObjectID); !ok || id.IsZero() {
+			t.Errorf("Expected %v to get an ID, got: %v", item["foodName"], item["id"])
+		}
+	}
+	if assignMissingItemIDs(items) {
+		t.Error("Expected nothing to be assigned once every item has an ID")
+	}
+}
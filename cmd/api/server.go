@@ -5,8 +5,6 @@ import (
 	"fmt"
 	"net/http"
 	"os"
-	"os/signal"
-	"syscall"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -15,8 +13,18 @@ import (
 
 	"nutrient_be/internal/config"
 	"nutrient_be/internal/database"
+	"nutrient_be/internal/dto/response"
+	"nutrient_be/internal/handler/middleware"
 	"nutrient_be/internal/handler/rest"
+	"nutrient_be/internal/pkg/cache"
+	"nutrient_be/internal/pkg/calculator"
+	"nutrient_be/internal/pkg/circuitbreaker"
+	"nutrient_be/internal/pkg/events"
+	"nutrient_be/internal/pkg/jwtkeys"
+	"nutrient_be/internal/pkg/lifecycle"
 	"nutrient_be/internal/pkg/logger"
+	"nutrient_be/internal/pkg/storage"
+	"nutrient_be/internal/pkg/validator"
 	"nutrient_be/internal/repository/mongodb"
 	"nutrient_be/internal/service"
 )
@@ -84,10 +92,10 @@ func init() {
 	viper.BindPFlag("server.host", serverCmd.Flags().Lookup("host"))
 	viper.BindPFlag("debug", serverCmd.Flags().Lookup("debug"))
 	viper.BindPFlag("logger.level", serverCmd.Flags().Lookup("log-level"))
-	viper.BindPFlag("logger.format", serverCmd.Flags().Lookup("log-format"))
+	viper.BindPFlag("logger.encoding", serverCmd.Flags().Lookup("log-format"))
 	viper.BindPFlag("database.uri", serverCmd.Flags().Lookup("db-uri"))
-	viper.BindPFlag("database.name", serverCmd.Flags().Lookup("db-name"))
-	viper.BindPFlag("auth.secret", serverCmd.Flags().Lookup("jwt-secret"))
+	viper.BindPFlag("database.database", serverCmd.Flags().Lookup("db-name"))
+	viper.BindPFlag("auth.jwt_secret", serverCmd.Flags().Lookup("jwt-secret"))
 	viper.BindPFlag("server.shutdown_timeout", serverCmd.Flags().Lookup("shutdown-timeout"))
 }
 
@@ -105,45 +113,108 @@ func startServer() {
 		fmt.Fprintf(os.Stderr, "Failed to initialize logger: %v\n", err)
 		os.Exit(1)
 	}
+	setLogLevel(log, cfg.Logger.Level)
 
-	// Log configuration info
+	// Log configuration info; secrets are masked
+	redacted := cfg.Redacted()
 	log.Info(context.Background(), "Starting server with configuration",
 		logger.String("config_path", configPath),
 		logger.String("environment", environment),
-		logger.String("host", cfg.Server.Host),
-		logger.Int("port", cfg.Server.Port),
+		logger.String("host", redacted.Server.Host),
+		logger.Int("port", redacted.Server.Port),
+		logger.String("mode", redacted.Server.Mode),
 		logger.Bool("debug", debug),
-		logger.String("log_level", cfg.Logger.Level),
-		logger.String("db_name", cfg.Database.Database))
+		logger.String("log_level", redacted.Logger.Level),
+		logger.String("db_uri", redacted.Database.URI),
+		logger.String("db_name", redacted.Database.Database),
+		logger.String("jwt_algorithm", redacted.Auth.JWTAlgorithm),
+		logger.String("jwt_secret", redacted.Auth.JWTSecret),
+		logger.Bool("nats_enabled", redacted.NATS.Enabled),
+		logger.String("nats_url", redacted.NATS.URL),
+		logger.Bool("cache_enabled", redacted.Cache.Enabled),
+		logger.Bool("read_only", redacted.Maintenance.ReadOnly),
+		logger.String("user_delete_mode", redacted.User.DeleteMode))
+
+	// Register meal types supported on top of the defaults
+	for _, mealType := range cfg.Meal.AdditionalTypes {
+		if err := validator.RegisterMealType(mealType); err != nil {
+			log.Fatal(context.Background(), "Invalid meal type in config", logger.Error(err))
+		}
+	}
+	for mealType, t := range cfg.Meal.DefaultTimes {
+		if err := validator.SetDefaultMealTime(mealType, t); err != nil {
+			log.Fatal(context.Background(), "Invalid default meal time in config", logger.Error(err))
+		}
+	}
+
+	// Register food categories supported on top of the defaults
+	for _, category := range cfg.Food.AdditionalCategories {
+		if err := validator.RegisterFoodCategory(category); err != nil {
+			log.Fatal(context.Background(), "Invalid food category in config", logger.Error(err))
+		}
+	}
+
+	// Convert between units of the same kind when a food lacks the requested one
+	conversions := calculator.DefaultUnitConversions()
+	if len(cfg.Food.UnitConversions.Mass) > 0 {
+		conversions.Mass = cfg.Food.UnitConversions.Mass
+	}
+	if len(cfg.Food.UnitConversions.Volume) > 0 {
+		conversions.Volume = cfg.Food.UnitConversions.Volume
+	}
+	if err := validator.SetUnitConversions(conversions); err != nil {
+		log.Fatal(context.Background(), "Invalid unit conversions in config", logger.Error(err))
+	}
+
+	// Round calories in responses to the configured precision
+	if err := response.SetCaloriePrecision(cfg.Display.CaloriePrecision); err != nil {
+		log.Fatal(context.Background(), "Invalid calorie precision in config", logger.Error(err))
+	}
+
+	// Load the keys tokens are signed and verified with
+	tokenKeys, err := jwtkeys.Load(cfg.Auth.JWTAlgorithm, cfg.Auth.JWTSecret, cfg.Auth.JWTPrivateKeyPath, cfg.Auth.JWTPublicKeyPath)
+	if err != nil {
+		log.Fatal(context.Background(), "Failed to load JWT keys", logger.Error(err))
+	}
 
 	// Initialize MongoDB
 	mongoDB, err := database.NewMongoDB(&cfg.Database, log)
 	if err != nil {
 		log.Fatal(context.Background(), "Failed to connect to MongoDB", logger.Error(err))
 	}
-	defer func() {
-		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-		defer cancel()
-		if err := mongoDB.Close(ctx); err != nil {
-			log.Error(context.Background(), "Failed to close MongoDB connection", logger.Error(err))
-		}
-	}()
+
+	// Closers run in reverse order of registration, so MongoDB is closed after everything using it
+	shutdown := lifecycle.NewManager(log)
+	shutdown.Register("mongodb", mongoDB.Close)
 
 	// Initialize repositories
-	userRepo := mongodb.NewUserRepository(mongoDB.Database)
-	foodRepo := mongodb.NewFoodRepository(mongoDB.Database)
-	mealTemplateRepo := mongodb.NewMealTemplateRepository(mongoDB.Database)
-	mealPlanRepo := mongodb.NewMealPlanRepository(mongoDB.Database)
-	shoppingRepo := mongodb.NewShoppingListRepository(mongoDB.Database)
+	retryPolicy := mongodb.RetryPolicy{
+		MaxAttempts:    cfg.Database.RetryAttempts,
+		InitialBackoff: cfg.Database.RetryInitialBackoff * time.Millisecond,
+		MaxBackoff:     cfg.Database.RetryMaxBackoff * time.Millisecond,
+	}
+	// Shared by every repository so requests fail fast with 503 while MongoDB is unreachable
+	dbBreaker := circuitbreaker.New(cfg.Database.BreakerThreshold, cfg.Database.BreakerOpenDuration*time.Millisecond)
+	userRepo := mongodb.NewUserRepository(mongoDB.Database, retryPolicy, dbBreaker)
+	foodRepo := mongodb.NewFoodRepository(mongoDB.Database, retryPolicy, dbBreaker)
+	mealTemplateRepo := mongodb.NewMealTemplateRepository(mongoDB.Database, retryPolicy, dbBreaker)
+	mealPlanRepo := mongodb.NewMealPlanRepository(mongoDB.Database, retryPolicy, dbBreaker)
+	shoppingRepo := mongodb.NewShoppingListRepository(mongoDB.Database, retryPolicy, dbBreaker)
 
 	// Initialize services
-	authService := service.NewAuthService(userRepo, cfg.Auth, log)
-	userService := service.NewUserService(userRepo, log)
-	foodService := service.NewFoodService(foodRepo, log)
-	mealService := service.NewMealService(mealTemplateRepo, foodRepo, log)
-	mealPlanService := service.NewMealPlanService(mealPlanRepo, mealTemplateRepo, log)
-	shoppingService := service.NewShoppingService(shoppingRepo, mealPlanRepo, log)
-	reportService := service.NewReportService(mealPlanRepo, log)
+	authService := service.NewAuthService(userRepo, tokenKeys, cfg.Auth, cfg.User, log)
+	userService := service.NewUserService(userRepo, foodRepo, mealTemplateRepo, mealPlanRepo, shoppingRepo, cfg.User, log)
+	imageStorage := storage.NewLocalStorage(cfg.Storage.LocalDir)
+	foodService := service.NewFoodService(foodRepo, mealTemplateRepo, mealPlanRepo, mongoDB, newFoodCache(cfg.Cache), imageStorage, cfg.Storage.MaxImageSizeKB*1024, cfg.Food, log)
+	mealService := service.NewMealService(mealTemplateRepo, foodRepo, mongoDB, cfg.Meal, log)
+	eventBus := events.NewMemoryBus()
+	// Stop accepting events and let in-flight handlers finish once nothing publishes anymore
+	shutdown.Register("event bus", eventBus.Close)
+	mealPlanService := service.NewMealPlanService(mealPlanRepo, mealTemplateRepo, shoppingRepo, userRepo, cfg.User, eventBus, log)
+	shoppingService := service.NewShoppingService(shoppingRepo, mealPlanRepo, foodRepo, log)
+	reportService := service.NewReportService(mealPlanRepo, userRepo, cfg.DailyValues, cfg.Report, log)
+	suggestionService := service.NewMealSuggestionService(foodRepo, mealTemplateRepo, mealPlanRepo, userRepo, log)
+	dashboardService := service.NewDashboardService(foodRepo, mealTemplateRepo, mealPlanRepo, userRepo, log)
 
 	// Initialize handlers
 	handlers := rest.NewHandlers(
@@ -154,7 +225,12 @@ func startServer() {
 		mealPlanService,
 		shoppingService,
 		reportService,
-		mongoDB.Client,
+		suggestionService,
+		dashboardService,
+		mongoDB,
+		dbBreaker,
+		rest.BuildInfo{Version: getVersion(), Commit: getGitCommit(), BuildTime: getBuildTime()},
+		tokenKeys,
 		log,
 		*cfg,
 	)
@@ -167,7 +243,39 @@ func startServer() {
 	router := gin.New()
 
 	// Setup routes
-	rest.SetupRoutes(router, handlers)
+	corsOrigins := middleware.NewCORSOrigins(cfg.CORS.AllowedOrigins)
+	rest.SetupRoutes(router, handlers, rest.RouteOptions{
+		CORSOrigins: corsOrigins,
+		// Bodies are only logged in debug mode, never by default in production
+		LogBodies:       cfg.Logger.LogBodies && cfg.Logger.Level == "debug",
+		BodyMaxLength:   cfg.Logger.BodyMaxLength,
+		Routing:         cfg.Server.Routing,
+		RequestIDHeader: cfg.Server.RequestIDHeader,
+		DBBreaker:       dbBreaker,
+		// Only honoured outside release mode, checked at startup
+		AllowDefaultUser: cfg.Auth.AllowDefaultUser,
+	})
+
+	// Apply log level and CORS changes from the config file without a restart
+	reloader := config.NewReloader(cfg, log)
+	reloader.OnReload(func(reloaded *config.Config) {
+		setLogLevel(log, reloaded.Logger.Level)
+		corsOrigins.Set(reloaded.CORS.AllowedOrigins)
+	})
+	reloader.Watch()
+
+	// Keep shopping lists in sync with their meal plans
+	var shoppingSync *service.ShoppingSyncConsumer
+	if cfg.Shopping.AutoRegenerate {
+		shoppingSync = service.NewShoppingSyncConsumer(shoppingService, eventBus, cfg.Shopping, log)
+		if err := shoppingSync.Start(); err != nil {
+			log.Fatal(context.Background(), "Failed to start shopping list sync consumer", logger.Error(err))
+		}
+		shutdown.Register("shopping list sync consumer", func(ctx context.Context) error {
+			shoppingSync.Stop()
+			return nil
+		})
+	}
 
 	// Start server
 	server := &http.Server{
@@ -188,35 +296,36 @@ func startServer() {
 		}
 	}()
 
-	// Wait for interrupt signal to gracefully shutdown the server
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
-
-	log.Info(context.Background(), "Shutting down server...")
+	// Drain in-flight requests first so nothing publishes events or queries MongoDB afterwards
+	shutdown.Register("http server", server.Shutdown)
 
-	// Shutdown server with timeout
-	ctx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout*time.Second)
-	defer cancel()
-
-	if err := server.Shutdown(ctx); err != nil {
-		log.Error(context.Background(), "Server forced to shutdown", logger.Error(err))
+	// Wait for SIGINT or SIGTERM and close everything within the shutdown timeout
+	if err := shutdown.WaitForSignal(cfg.Server.ShutdownTimeout * time.Second); err != nil {
+		log.Error(context.Background(), "Server did not shut down cleanly", logger.Error(err))
 	}
 
 	log.Info(context.Background(), "Server exited")
 }
 
+// setLogLevel applies the configured level to loggers that support runtime level changes
+func setLogLevel(log logger.Logger, level string) {
+	levels, ok := log.(logger.LevelController)
+	if !ok {
+		return
+	}
+	if err := levels.SetLevel(level); err != nil {
+		log.Warn(context.Background(), "Failed to apply log level", logger.String("level", level), logger.Error(err))
+	}
+}
+
 // loadConfigWithFlags loads configuration with command line flags override
 func loadConfigWithFlags() (*config.Config, error) {
-	// Set default values
+	// Set default values; the server binds to localhost and logs at info unless configured otherwise
+	config.SetDefaults()
 	viper.SetDefault("env", "dev")
 	viper.SetDefault("debug", false)
 	viper.SetDefault("logger.level", "info")
-	viper.SetDefault("logger.format", "console")
 	viper.SetDefault("server.host", "localhost")
-	viper.SetDefault("server.port", 8080)
-	viper.SetDefault("server.mode", "debug")
-	viper.SetDefault("server.shutdown_timeout", 30)
 
 	// Determine config file path
 	if configPath == "" {
@@ -255,16 +364,16 @@ func loadConfigWithFlags() (*config.Config, error) {
 		viper.Set("logger.level", logLevel)
 	}
 	if logFormat != "" {
-		viper.Set("logger.format", logFormat)
+		viper.Set("logger.encoding", logFormat)
 	}
 	if dbURI != "" {
 		viper.Set("database.uri", dbURI)
 	}
 	if dbName != "" {
-		viper.Set("database.name", dbName)
+		viper.Set("database.database", dbName)
 	}
 	if jwtSecret != "" {
-		viper.Set("auth.secret", jwtSecret)
+		viper.Set("auth.jwt_secret", jwtSecret)
 	}
 	if shutdownTimeout > 0 {
 		viper.Set("server.shutdown_timeout", shutdownTimeout)
@@ -279,5 +388,17 @@ func loadConfigWithFlags() (*config.Config, error) {
 	// Set logger development mode based on debug flag or environment
 	cfg.Logger.Development = debug || cfg.Logger.Level == "debug"
 
+	if err := config.Validate(&cfg); err != nil {
+		return nil, fmt.Errorf("config validation failed: %w", err)
+	}
+
 	return &cfg, nil
 }
+
+// newFoodCache creates the cache used for public food reads
+func newFoodCache(cfg config.CacheConfig) cache.Cache {
+	if !cfg.Enabled {
+		return cache.NewNoopCache()
+	}
+	return cache.NewLRUCache(cfg.Size, cfg.TTL*time.Second)
+}
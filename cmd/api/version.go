@@ -6,6 +6,8 @@ import (
 	"runtime"
 
 	"github.com/spf13/cobra"
+
+	"nutrient_be/internal/config"
 )
 
 var versionCmd = &cobra.Command{
@@ -69,7 +71,7 @@ func showInfo() {
 	fmt.Println()
 
 	fmt.Println("Database:")
-	fmt.Printf("  URI:  %s\n", getDatabaseURI())
+	fmt.Printf("  URI:  %s\n", config.RedactURI(getDatabaseURI()))
 	fmt.Printf("  Name: %s\n", getDatabaseName())
 	fmt.Println()
 
@@ -82,8 +84,11 @@ func showInfo() {
 	fmt.Println()
 }
 
-// Version information - these would typically be set at build time
+// Version information - set at build time through -ldflags, falling back to the environment
 func getVersion() string {
+	if version != "" {
+		return version
+	}
 	if version := os.Getenv("APP_VERSION"); version != "" {
 		return version
 	}
@@ -91,6 +96,9 @@ func getVersion() string {
 }
 
 func getBuildTime() string {
+	if date != "" {
+		return date
+	}
 	if buildTime := os.Getenv("BUILD_TIME"); buildTime != "" {
 		return buildTime
 	}
@@ -98,6 +106,9 @@ func getBuildTime() string {
 }
 
 func getGitCommit() string {
+	if commit != "" {
+		return commit
+	}
 	if gitCommit := os.Getenv("GIT_COMMIT"); gitCommit != "" {
 		return gitCommit
 	}
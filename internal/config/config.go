@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"math"
 	"strings"
 	"time"
 
@@ -10,11 +11,23 @@ import (
 
 // Config represents the application configuration
 type Config struct {
-	Server   ServerConfig   `mapstructure:"server"`
-	Database DatabaseConfig `mapstructure:"database"`
-	Auth     AuthConfig     `mapstructure:"auth"`
-	NATS     NATSConfig     `mapstructure:"nats"`
-	Logger   LoggerConfig   `mapstructure:"logger"`
+	Server      ServerConfig         `mapstructure:"server"`
+	Database    DatabaseConfig       `mapstructure:"database"`
+	Auth        AuthConfig           `mapstructure:"auth"`
+	NATS        NATSConfig           `mapstructure:"nats"`
+	Logger      LoggerConfig         `mapstructure:"logger"`
+	User        UserConfig           `mapstructure:"user"`
+	Cache       CacheConfig          `mapstructure:"cache"`
+	CORS        CORSConfig           `mapstructure:"cors"`
+	Meal        MealConfig           `mapstructure:"meal"`
+	Storage     StorageConfig        `mapstructure:"storage"`
+	Shopping    ShoppingConfig       `mapstructure:"shopping"`
+	Report      ReportConfig         `mapstructure:"report"`
+	Food        FoodConfig           `mapstructure:"food"`
+	Pagination  PaginationConfig     `mapstructure:"pagination"`
+	Maintenance MaintenanceConfig    `mapstructure:"maintenance"`
+	DailyValues DailyReferenceValues `mapstructure:"daily_values"`
+	Display     DisplayConfig        `mapstructure:"display"`
 }
 
 // ServerConfig contains server-related configuration
@@ -25,22 +38,46 @@ type ServerConfig struct {
 	ReadTimeout     time.Duration `mapstructure:"read_timeout"`
 	WriteTimeout    time.Duration `mapstructure:"write_timeout"`
 	ShutdownTimeout time.Duration `mapstructure:"shutdown_timeout"`
+	Routing         RoutingConfig `mapstructure:"routing"`
+	RequestIDHeader string        `mapstructure:"request_id_header"` // Header the request ID is read from and echoed in, e.g. X-Correlation-ID
+}
+
+// RoutingConfig controls how request paths that do not exactly match a route are handled
+// Matching paths are answered with a redirect (301 for GET, 307 otherwise) to the registered route
+type RoutingConfig struct {
+	RedirectTrailingSlash bool `mapstructure:"redirect_trailing_slash"` // Redirect /api/v1/foods/ to /api/v1/foods
+	CaseInsensitive       bool `mapstructure:"case_insensitive"`        // Redirect /api/v1/Foods to /api/v1/foods
 }
 
 // DatabaseConfig contains database-related configuration
+// Timeouts are expressed in seconds
 type DatabaseConfig struct {
-	URI            string        `mapstructure:"uri"`
-	Database       string        `mapstructure:"database"`
-	MaxPoolSize    uint64        `mapstructure:"max_pool_size"`
-	MinPoolSize    uint64        `mapstructure:"min_pool_size"`
-	ConnectTimeout time.Duration `mapstructure:"connect_timeout"`
+	URI                    string        `mapstructure:"uri"`
+	Database               string        `mapstructure:"database"`
+	MaxPoolSize            uint64        `mapstructure:"max_pool_size"`
+	MinPoolSize            uint64        `mapstructure:"min_pool_size"`
+	MaxConnIdleTime        time.Duration `mapstructure:"max_conn_idle_time"`       // 0 keeps idle connections open
+	ConnectTimeout         time.Duration `mapstructure:"connect_timeout"`          // Bounds the initial connect and ping
+	ServerSelectionTimeout time.Duration `mapstructure:"server_selection_timeout"` // How long to wait for a usable server
+	SocketTimeout          time.Duration `mapstructure:"socket_timeout"`           // 0 means no socket timeout
+	OperationTimeout       time.Duration `mapstructure:"operation_timeout"`        // Default per-operation timeout, 0 disables it
+	RetryAttempts          int           `mapstructure:"retry_attempts"`           // Attempts for reads hitting transient errors, 1 disables retries
+	RetryInitialBackoff    time.Duration `mapstructure:"retry_initial_backoff_ms"` // Milliseconds, doubled after each retry
+	RetryMaxBackoff        time.Duration `mapstructure:"retry_max_backoff_ms"`     // Milliseconds
+	BreakerThreshold       int           `mapstructure:"breaker_threshold"`        // Consecutive failures that open the circuit breaker, 0 disables it
+	BreakerOpenDuration    time.Duration `mapstructure:"breaker_open_ms"`          // Milliseconds the breaker stays open before probing again
 }
 
 // AuthConfig contains authentication-related configuration
 type AuthConfig struct {
-	JWTSecret         string        `mapstructure:"jwt_secret"`
+	JWTAlgorithm      string        `mapstructure:"jwt_algorithm"`        // HS256, RS256
+	JWTSecret         string        `mapstructure:"jwt_secret"`           // Shared secret for HS256
+	JWTPrivateKeyPath string        `mapstructure:"jwt_private_key_path"` // PEM RSA private key that signs RS256 tokens
+	JWTPublicKeyPath  string        `mapstructure:"jwt_public_key_path"`  // PEM RSA public key that verifies RS256 tokens
 	JWTExpiration     time.Duration `mapstructure:"jwt_expiration"`
 	RefreshExpiration time.Duration `mapstructure:"refresh_expiration"`
+	MinPasswordLength int           `mapstructure:"min_password_length"` // Shortest password accepted on registration and password change
+	AllowDefaultUser  bool          `mapstructure:"allow_default_user"`  // Dev only: treat every request as the default user without a token
 }
 
 // NATSConfig contains NATS-related configuration
@@ -49,11 +86,203 @@ type NATSConfig struct {
 	Enabled bool   `mapstructure:"enabled"`
 }
 
+// UserConfig contains defaults applied to user profiles and account lifecycle settings
+type UserConfig struct {
+	DefaultGoal          string `mapstructure:"default_goal"`           // weight_loss, muscle_gain, maintenance
+	DefaultActivityLevel string `mapstructure:"default_activity_level"` // sedentary, light, moderate, active, very_active
+	DeleteMode           string `mapstructure:"delete_mode"`            // cascade, anonymize
+	// Safe range calculated calorie targets are clamped to; 0 disables a limit
+	MinCalorieTargetFemale float64 `mapstructure:"min_calorie_target_female"` // Floor for every gender other than male
+	MinCalorieTargetMale   float64 `mapstructure:"min_calorie_target_male"`
+	MaxCalorieTarget       float64 `mapstructure:"max_calorie_target"`
+	// Accepted profile measurements on profile updates and target previews; 0 uses the default limit
+	MinAge    int     `mapstructure:"min_age"`
+	MaxAge    int     `mapstructure:"max_age"`
+	MinHeight float64 `mapstructure:"min_height"` // cm
+	MaxHeight float64 `mapstructure:"max_height"`
+	MinWeight float64 `mapstructure:"min_weight"` // kg
+	MaxWeight float64 `mapstructure:"max_weight"`
+	// How macro targets are derived: per_kg keeps the fixed per-goal coefficients, split shares the calorie target by MacroSplits
+	MacroModel  string                `mapstructure:"macro_model"`
+	MacroSplits map[string]MacroSplit `mapstructure:"macro_splits"` // Per goal; every goal needs one with the split model
+}
+
+// Macro target models
+const (
+	MacroModelPerKg = "per_kg"
+	MacroModelSplit = "split"
+)
+
+// Default accepted profile measurements
+const (
+	DefaultMinAge    = 1
+	DefaultMaxAge    = 120
+	DefaultMinHeight = 50.0 // cm
+	DefaultMaxHeight = 300.0
+	DefaultMinWeight = 20.0 // kg
+	DefaultMaxWeight = 500.0
+)
+
+// ProfileRange is the accepted range of one profile measurement, bounds included
+type ProfileRange struct {
+	Min float64
+	Max float64
+}
+
+// Contains reports whether value is within the range; NaN never is
+func (r ProfileRange) Contains(value float64) bool {
+	return value >= r.Min && value <= r.Max
+}
+
+// AgeRange returns the accepted ages, with the default for a limit left at 0
+func (c UserConfig) AgeRange() ProfileRange {
+	return profileRange(float64(c.MinAge), float64(c.MaxAge), DefaultMinAge, DefaultMaxAge)
+}
+
+// HeightRange returns the accepted heights in cm, with the default for a limit left at 0
+func (c UserConfig) HeightRange() ProfileRange {
+	return profileRange(c.MinHeight, c.MaxHeight, DefaultMinHeight, DefaultMaxHeight)
+}
+
+// WeightRange returns the accepted weights in kg, with the default for a limit left at 0
+func (c UserConfig) WeightRange() ProfileRange {
+	return profileRange(c.MinWeight, c.MaxWeight, DefaultMinWeight, DefaultMaxWeight)
+}
+
+func profileRange(min, max, defaultMin, defaultMax float64) ProfileRange {
+	if min == 0 {
+		min = defaultMin
+	}
+	if max == 0 {
+		max = defaultMax
+	}
+	return ProfileRange{Min: min, Max: max}
+}
+
+// MacroSplit is the percentage of the calorie target given to each macro; the three sum to 100
+type MacroSplit struct {
+	Protein       float64 `mapstructure:"protein"`
+	Carbohydrates float64 `mapstructure:"carbohydrates"`
+	Fat           float64 `mapstructure:"fat"`
+}
+
+// CacheConfig contains configuration for the in-memory cache of public foods
+type CacheConfig struct {
+	Enabled bool          `mapstructure:"enabled"`
+	Size    int           `mapstructure:"size"` // Maximum number of cached entries
+	TTL     time.Duration `mapstructure:"ttl"`  // Seconds before an entry expires
+}
+
+// CORSConfig contains cross-origin request settings
+type CORSConfig struct {
+	AllowedOrigins []string `mapstructure:"allowed_origins"` // "*" allows any origin
+}
+
+// FoodConfig contains food validation settings
+type FoodConfig struct {
+	ServingTolerance float64 `mapstructure:"serving_tolerance"` // How far a gram serving's amount may be from its gram equivalent and still count as equal
+	RequireGramBase  bool    `mapstructure:"require_gram_base"` // Reject foods without a 100g serving instead of only logging a warning
+	// Calories above which a single non-gram serving is logged as a likely data-entry error; 0 disables the check
+	MaxServingCalories float64 `mapstructure:"max_serving_calories"`
+	// Hosts food image URLs may point at, subdomains included; empty allows any host
+	AllowedImageHosts []string `mapstructure:"allowed_image_hosts"`
+	// Food categories supported on top of protein, vegetable, fruit, dairy and grain
+	AdditionalCategories []string `mapstructure:"additional_categories"`
+	// Conversions used when a food lacks the requested unit but defines another of the same kind
+	UnitConversions UnitConversionsConfig `mapstructure:"unit_conversions"`
+}
+
+// UnitConversionsConfig gives the size of each convertible unit in grams (mass) or millilitres (volume)
+// A kind left empty keeps the built-in table; count units such as piece and box are never converted
+type UnitConversionsConfig struct {
+	Mass   map[string]float64 `mapstructure:"mass"`
+	Volume map[string]float64 `mapstructure:"volume"`
+}
+
+// MealConfig contains meal template settings
+type MealConfig struct {
+	MaxTemplateCalories  float64  `mapstructure:"max_template_calories"`   // Templates above this total get a warning, 0 disables the check
+	MaxTemplateFoodItems int      `mapstructure:"max_template_food_items"` // Most food items a template may hold
+	MaxTemplateTags      int      `mapstructure:"max_template_tags"`       // Most distinct tags a template may carry
+	MaxTagLength         int      `mapstructure:"max_tag_length"`          // Longest template tag, in bytes
+	MaxItemAmount        float64  `mapstructure:"max_item_amount"`         // Largest amount of one food item, in its own serving unit
+	MaxItemGrams         float64  `mapstructure:"max_item_grams"`          // Heaviest serving of one food item once converted to grams
+	CheckServingUnits    bool     `mapstructure:"check_serving_units"`     // Reject food items whose serving unit is neither registered nor convertible
+	AdditionalTypes      []string `mapstructure:"additional_types"`        // Meal types supported on top of breakfast, lunch, dinner and snack
+	// Time (HH:MM) given to meals created without one, by meal type; unset types keep the built-in default
+	DefaultTimes map[string]string `mapstructure:"default_times"`
+}
+
+// StorageConfig contains settings for uploaded files such as food images
+type StorageConfig struct {
+	Driver         string `mapstructure:"driver"`            // local
+	LocalDir       string `mapstructure:"local_dir"`         // Directory files are written to by the local driver
+	MaxImageSizeKB int64  `mapstructure:"max_image_size_kb"` // Largest accepted image upload
+}
+
+// ShoppingConfig contains shopping list settings
+type ShoppingConfig struct {
+	AutoRegenerate      bool          `mapstructure:"auto_regenerate"`          // Regenerate a plan's shopping list in the background when the plan is updated
+	RetryAttempts       int           `mapstructure:"retry_attempts"`           // Attempts for a regeneration hitting transient errors
+	RetryInitialBackoff time.Duration `mapstructure:"retry_initial_backoff_ms"` // Milliseconds, doubled after each retry
+	RetryMaxBackoff     time.Duration `mapstructure:"retry_max_backoff_ms"`     // Milliseconds
+}
+
+// ReportConfig contains report settings
+type ReportConfig struct {
+	StreakTolerancePercent float64 `mapstructure:"streak_tolerance_percent"` // A day counts towards the streak when its calories are within this percentage of the target
+}
+
+// MaintenanceConfig contains settings for maintenance windows and the admin endpoints
+type MaintenanceConfig struct {
+	ReadOnly   bool   `mapstructure:"read_only"`   // Start with writes blocked; can be toggled at runtime through the admin endpoint
+	AdminToken string `mapstructure:"admin_token"` // Required in the X-Admin-Token header of admin endpoints, empty disables them
+}
+
+// DailyReferenceValues contains the reference daily intake used to compute %DV
+// Defaults are the FDA values for adults; users can override them in their preferences
+type DailyReferenceValues struct {
+	Calories      float64 `mapstructure:"calories"`      // kcal
+	Protein       float64 `mapstructure:"protein"`       // g
+	Carbohydrates float64 `mapstructure:"carbohydrates"` // g
+	Fat           float64 `mapstructure:"fat"`           // g
+	Fiber         float64 `mapstructure:"fiber"`         // g
+	Sugar         float64 `mapstructure:"sugar"`         // g, added sugars
+	VitaminA      float64 `mapstructure:"vitamin_a"`     // mcg RAE
+	VitaminC      float64 `mapstructure:"vitamin_c"`     // mg
+	Calcium       float64 `mapstructure:"calcium"`       // mg
+	Iron          float64 `mapstructure:"iron"`          // mg
+	Sodium        float64 `mapstructure:"sodium"`        // mg
+	Potassium     float64 `mapstructure:"potassium"`     // mg
+}
+
+// DisplayConfig contains settings for how values are formatted in responses
+type DisplayConfig struct {
+	CaloriePrecision int `mapstructure:"calorie_precision"` // Decimals calories are rounded to in responses, 0-4; storage keeps full precision
+}
+
+// PaginationConfig contains page sizes for each list endpoint
+type PaginationConfig struct {
+	FoodSearch    PageSizeConfig `mapstructure:"food_search"`
+	FoodList      PageSizeConfig `mapstructure:"food_list"`
+	MealTemplates PageSizeConfig `mapstructure:"meal_templates"`
+	MealPlans     PageSizeConfig `mapstructure:"meal_plans"`
+	ShoppingLists PageSizeConfig `mapstructure:"shopping_lists"`
+}
+
+// PageSizeConfig contains the page sizes of a single list endpoint
+type PageSizeConfig struct {
+	Default int `mapstructure:"default"` // Used when no limit is requested
+	Max     int `mapstructure:"max"`     // Larger requested limits are rejected
+}
+
 // LoggerConfig contains logging-related configuration
 type LoggerConfig struct {
-	Level       string `mapstructure:"level"`       // debug, info, warn, error
-	Development bool   `mapstructure:"development"` // true for development mode
-	Encoding    string `mapstructure:"encoding"`    // json, console
+	Level         string `mapstructure:"level"`           // debug, info, warn, error
+	Development   bool   `mapstructure:"development"`     // true for development mode
+	Encoding      string `mapstructure:"encoding"`        // json, console
+	LogBodies     bool   `mapstructure:"log_bodies"`      // Log redacted non-GET request/response bodies; only when level is debug
+	BodyMaxLength int    `mapstructure:"body_max_length"` // Bytes of each body to log
 }
 
 // Load loads configuration from file and environment variables
@@ -70,7 +299,7 @@ func Load(configPath string) (*Config, error) {
 	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
 
 	// Set default values
-	setDefaults()
+	SetDefaults()
 
 	// Read config file
 	if err := viper.ReadInConfig(); err != nil {
@@ -83,15 +312,16 @@ func Load(configPath string) (*Config, error) {
 	}
 
 	// Validate configuration
-	if err := validate(&config); err != nil {
+	if err := Validate(&config); err != nil {
 		return nil, fmt.Errorf("config validation failed: %w", err)
 	}
 
 	return &config, nil
 }
 
-// setDefaults sets default configuration values
-func setDefaults() {
+// SetDefaults sets default configuration values
+// Load and the server's flag-aware loader share it, so a default is only declared here
+func SetDefaults() {
 	// Server defaults
 	viper.SetDefault("server.host", "0.0.0.0")
 	viper.SetDefault("server.port", 8080)
@@ -99,17 +329,32 @@ func setDefaults() {
 	viper.SetDefault("server.read_timeout", 10)
 	viper.SetDefault("server.write_timeout", 10)
 	viper.SetDefault("server.shutdown_timeout", 30)
+	viper.SetDefault("server.routing.redirect_trailing_slash", true)
+	viper.SetDefault("server.routing.case_insensitive", false)
+	viper.SetDefault("server.request_id_header", "X-Request-ID")
 
 	// Database defaults
 	viper.SetDefault("database.uri", "mongodb://localhost:27017")
 	viper.SetDefault("database.database", "nutrient_db")
 	viper.SetDefault("database.max_pool_size", 100)
 	viper.SetDefault("database.min_pool_size", 10)
+	viper.SetDefault("database.max_conn_idle_time", 300)
 	viper.SetDefault("database.connect_timeout", 10)
+	viper.SetDefault("database.server_selection_timeout", 5)
+	viper.SetDefault("database.socket_timeout", 30)
+	viper.SetDefault("database.operation_timeout", 10)
+	viper.SetDefault("database.retry_attempts", 3)
+	viper.SetDefault("database.retry_initial_backoff_ms", 100)
+	viper.SetDefault("database.retry_max_backoff_ms", 1000)
+	viper.SetDefault("database.breaker_threshold", 5)
+	viper.SetDefault("database.breaker_open_ms", 10000)
 
 	// Auth defaults
+	viper.SetDefault("auth.jwt_algorithm", "HS256")
 	viper.SetDefault("auth.jwt_expiration", 3600)
 	viper.SetDefault("auth.refresh_expiration", 604800)
+	viper.SetDefault("auth.min_password_length", 6)
+	viper.SetDefault("auth.allow_default_user", false)
 
 	// NATS defaults
 	viper.SetDefault("nats.url", "nats://localhost:4222")
@@ -119,10 +364,108 @@ func setDefaults() {
 	viper.SetDefault("logger.level", "debug")
 	viper.SetDefault("logger.development", true)
 	viper.SetDefault("logger.encoding", "console")
+	viper.SetDefault("logger.log_bodies", false)
+	viper.SetDefault("logger.body_max_length", 2048)
+
+	// User defaults
+	viper.SetDefault("user.default_goal", "maintenance")
+	viper.SetDefault("user.default_activity_level", "sedentary")
+	viper.SetDefault("user.delete_mode", "cascade")
+	viper.SetDefault("user.min_calorie_target_female", 1200)
+	viper.SetDefault("user.min_calorie_target_male", 1500)
+	viper.SetDefault("user.max_calorie_target", 5000)
+	viper.SetDefault("user.min_age", DefaultMinAge)
+	viper.SetDefault("user.max_age", DefaultMaxAge)
+	viper.SetDefault("user.min_height", DefaultMinHeight)
+	viper.SetDefault("user.max_height", DefaultMaxHeight)
+	viper.SetDefault("user.min_weight", DefaultMinWeight)
+	viper.SetDefault("user.max_weight", DefaultMaxWeight)
+	viper.SetDefault("user.macro_model", MacroModelPerKg)
+	viper.SetDefault("user.macro_splits", DefaultMacroSplits())
+
+	// Cache defaults
+	viper.SetDefault("cache.enabled", true)
+	viper.SetDefault("cache.size", 1000)
+	viper.SetDefault("cache.ttl", 300)
+
+	// CORS defaults
+	viper.SetDefault("cors.allowed_origins", []string{"*"})
+
+	// Meal defaults
+	viper.SetDefault("meal.max_template_calories", 5000)
+	viper.SetDefault("meal.max_template_food_items", 50)
+	viper.SetDefault("meal.max_template_tags", 20)
+	viper.SetDefault("meal.max_tag_length", 50)
+	viper.SetDefault("meal.max_item_amount", 10000)
+	viper.SetDefault("meal.max_item_grams", 5000)
+	viper.SetDefault("meal.check_serving_units", true)
+	viper.SetDefault("meal.additional_types", []string{})
+	viper.SetDefault("meal.default_times", map[string]string{"breakfast": "07:00", "lunch": "12:00", "dinner": "19:00", "snack": "15:00"})
+
+	// Storage defaults
+	viper.SetDefault("storage.driver", "local")
+	viper.SetDefault("storage.local_dir", "./data/uploads")
+	viper.SetDefault("storage.max_image_size_kb", 2048)
+
+	// Shopping defaults
+	viper.SetDefault("shopping.auto_regenerate", false)
+	viper.SetDefault("shopping.retry_attempts", 3)
+	viper.SetDefault("shopping.retry_initial_backoff_ms", 500)
+	viper.SetDefault("shopping.retry_max_backoff_ms", 5000)
+
+	// Report defaults
+	viper.SetDefault("report.streak_tolerance_percent", 10)
+
+	// Food defaults
+	viper.SetDefault("food.serving_tolerance", 0.001)
+	viper.SetDefault("food.require_gram_base", false)
+	viper.SetDefault("food.max_serving_calories", 2000)
+	viper.SetDefault("food.allowed_image_hosts", []string{})
+	viper.SetDefault("food.additional_categories", []string{})
+
+	// Maintenance defaults
+	viper.SetDefault("maintenance.read_only", false)
+	viper.SetDefault("maintenance.admin_token", "")
+
+	// Daily value defaults (FDA reference values)
+	viper.SetDefault("daily_values.calories", 2000)
+	viper.SetDefault("daily_values.protein", 50)
+	viper.SetDefault("daily_values.carbohydrates", 275)
+	viper.SetDefault("daily_values.fat", 78)
+	viper.SetDefault("daily_values.fiber", 28)
+	viper.SetDefault("daily_values.sugar", 50)
+	viper.SetDefault("daily_values.vitamin_a", 900)
+	viper.SetDefault("daily_values.vitamin_c", 90)
+	viper.SetDefault("daily_values.calcium", 1300)
+	viper.SetDefault("daily_values.iron", 18)
+	viper.SetDefault("daily_values.sodium", 2300)
+	viper.SetDefault("daily_values.potassium", 4700)
+	viper.SetDefault("display.calorie_precision", 1)
+
+	// Pagination defaults
+	viper.SetDefault("pagination.food_search.default", 20)
+	viper.SetDefault("pagination.food_search.max", 100)
+	viper.SetDefault("pagination.food_list.default", 50)
+	viper.SetDefault("pagination.food_list.max", 100)
+	viper.SetDefault("pagination.meal_templates.default", 20)
+	viper.SetDefault("pagination.meal_templates.max", 100)
+	viper.SetDefault("pagination.meal_plans.default", 20)
+	viper.SetDefault("pagination.meal_plans.max", 100)
+	viper.SetDefault("pagination.shopping_lists.default", 20)
+	viper.SetDefault("pagination.shopping_lists.max", 100)
 }
 
-// validate validates the configuration
-func validate(config *Config) error {
+// DefaultMacroSplits returns the macro splits used by the split model when none are configured
+func DefaultMacroSplits() map[string]interface{} {
+	return map[string]interface{}{
+		"weight_loss": map[string]interface{}{"protein": 40, "carbohydrates": 30, "fat": 30},
+		"muscle_gain": map[string]interface{}{"protein": 30, "carbohydrates": 45, "fat": 25},
+		"maintenance": map[string]interface{}{"protein": 30, "carbohydrates": 40, "fat": 30},
+	}
+}
+
+// Validate validates the configuration
+func Validate(config *Config) error {
 	if err := validateServer(config); err != nil {
 		return err
 	}
@@ -139,6 +482,46 @@ func validate(config *Config) error {
 		return err
 	}
 
+	if err := validateUser(config); err != nil {
+		return err
+	}
+
+	if err := validateCache(config); err != nil {
+		return err
+	}
+
+	if err := validateMeal(config); err != nil {
+		return err
+	}
+
+	if err := validateStorage(config); err != nil {
+		return err
+	}
+
+	if err := validateShopping(config); err != nil {
+		return err
+	}
+
+	if err := validateReport(config); err != nil {
+		return err
+	}
+
+	if err := validateFood(config); err != nil {
+		return err
+	}
+
+	if err := validatePagination(config); err != nil {
+		return err
+	}
+
+	if err := validateDailyValues(config); err != nil {
+		return err
+	}
+
+	if err := validateDisplay(config); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -156,6 +539,10 @@ func validateServer(config *Config) error {
 		return fmt.Errorf("invalid server mode: %s", config.Server.Mode)
 	}
 
+	if strings.TrimSpace(config.Server.RequestIDHeader) == "" {
+		return fmt.Errorf("server request ID header is required")
+	}
+
 	return nil
 }
 
@@ -169,27 +556,75 @@ func validateDatabase(config *Config) error {
 		return fmt.Errorf("database name is required")
 	}
 
+	if config.Database.MaxPoolSize > 0 && config.Database.MinPoolSize > config.Database.MaxPoolSize {
+		return fmt.Errorf("database min pool size (%d) exceeds max pool size (%d)", config.Database.MinPoolSize, config.Database.MaxPoolSize)
+	}
+
+	if config.Database.ConnectTimeout <= 0 {
+		return fmt.Errorf("database connect timeout must be greater than 0")
+	}
+
+	if config.Database.ServerSelectionTimeout < 0 || config.Database.SocketTimeout < 0 || config.Database.OperationTimeout < 0 {
+		return fmt.Errorf("database timeouts cannot be negative")
+	}
+
+	if config.Database.RetryAttempts < 0 || config.Database.RetryInitialBackoff < 0 || config.Database.RetryMaxBackoff < 0 {
+		return fmt.Errorf("database retry settings cannot be negative")
+	}
+
+	if config.Database.BreakerThreshold < 0 {
+		return fmt.Errorf("database breaker threshold cannot be negative")
+	}
+
+	if config.Database.BreakerThreshold > 0 && config.Database.BreakerOpenDuration <= 0 {
+		return fmt.Errorf("database breaker open duration must be greater than 0 when the breaker is enabled")
+	}
+
 	return nil
 }
 
 // validateAuth validates authentication configuration
 func validateAuth(config *Config) error {
-	if config.Auth.JWTSecret == "" {
-		return fmt.Errorf("JWT secret is required")
+	switch config.Auth.JWTAlgorithm {
+	case "HS256":
+		if config.Auth.JWTSecret == "" {
+			return fmt.Errorf("JWT secret is required")
+		}
+	case "RS256":
+		if config.Auth.JWTPrivateKeyPath == "" || config.Auth.JWTPublicKeyPath == "" {
+			return fmt.Errorf("JWT private and public key paths are required for RS256")
+		}
+	default:
+		return fmt.Errorf("invalid JWT algorithm: %s (must be HS256 or RS256)", config.Auth.JWTAlgorithm)
+	}
+
+	if config.Auth.MinPasswordLength < 1 {
+		return fmt.Errorf("invalid auth min password length: %d (must be positive)", config.Auth.MinPasswordLength)
 	}
 
+	return CheckDefaultUser(config)
+}
+
+// CheckDefaultUser refuses default-user authentication in release mode
+// The mode skips token checks entirely, so it must never reach a deployed server
+func CheckDefaultUser(config *Config) error {
+	if config.Auth.AllowDefaultUser && config.Server.Mode == "release" {
+		return fmt.Errorf("auth allow_default_user cannot be enabled in release mode")
+	}
 	return nil
 }
 
+// validLoggerLevels lists the accepted logger levels
+var validLoggerLevels = map[string]bool{
+	"debug": true,
+	"info":  true,
+	"warn":  true,
+	"error": true,
+}
+
 // validateLogger validates logger configuration
 func validateLogger(config *Config) error {
-	validLevels := map[string]bool{
-		"debug": true,
-		"info":  true,
-		"warn":  true,
-		"error": true,
-	}
-	if !validLevels[config.Logger.Level] {
+	if !validLoggerLevels[config.Logger.Level] {
 		return fmt.Errorf("invalid logger level: %s", config.Logger.Level)
 	}
 
@@ -201,5 +636,252 @@ func validateLogger(config *Config) error {
 		return fmt.Errorf("invalid logger encoding: %s", config.Logger.Encoding)
 	}
 
+	if config.Logger.LogBodies && config.Logger.BodyMaxLength <= 0 {
+		return fmt.Errorf("invalid logger body max length: %d (must be greater than 0)", config.Logger.BodyMaxLength)
+	}
+
+	return nil
+}
+
+// validateUser validates user default configuration
+func validateUser(config *Config) error {
+	validGoals := map[string]bool{
+		"weight_loss": true,
+		"muscle_gain": true,
+		"maintenance": true,
+	}
+	if config.User.DefaultGoal != "" && !validGoals[config.User.DefaultGoal] {
+		return fmt.Errorf("invalid default goal: %s", config.User.DefaultGoal)
+	}
+
+	validActivityLevels := map[string]bool{
+		"sedentary":   true,
+		"light":       true,
+		"moderate":    true,
+		"active":      true,
+		"very_active": true,
+	}
+	if config.User.DefaultActivityLevel != "" && !validActivityLevels[config.User.DefaultActivityLevel] {
+		return fmt.Errorf("invalid default activity level: %s", config.User.DefaultActivityLevel)
+	}
+
+	if config.User.DeleteMode != "" && config.User.DeleteMode != "cascade" && config.User.DeleteMode != "anonymize" {
+		return fmt.Errorf("invalid delete mode: %s (must be cascade or anonymize)", config.User.DeleteMode)
+	}
+
+	user := config.User
+	if user.MinCalorieTargetFemale < 0 || user.MinCalorieTargetMale < 0 || user.MaxCalorieTarget < 0 {
+		return fmt.Errorf("calorie target limits must not be negative")
+	}
+	if user.MaxCalorieTarget > 0 && (user.MaxCalorieTarget < user.MinCalorieTargetFemale || user.MaxCalorieTarget < user.MinCalorieTargetMale) {
+		return fmt.Errorf("max calorie target (%.0f) must not be below the minimum calorie targets", user.MaxCalorieTarget)
+	}
+
+	if user.MinAge < 0 || user.MaxAge < 0 || user.MinHeight < 0 || user.MaxHeight < 0 || user.MinWeight < 0 || user.MaxWeight < 0 {
+		return fmt.Errorf("profile measurement limits must not be negative")
+	}
+	for _, measurement := range []struct {
+		name  string
+		valid ProfileRange
+	}{{"age", user.AgeRange()}, {"height", user.HeightRange()}, {"weight", user.WeightRange()}} {
+		if measurement.valid.Min > measurement.valid.Max {
+			return fmt.Errorf("min %s (%g) must not be above max %s (%g)", measurement.name, measurement.valid.Min, measurement.name, measurement.valid.Max)
+		}
+	}
+
+	switch user.MacroModel {
+	case "", MacroModelPerKg:
+	case MacroModelSplit:
+		for goal := range validGoals {
+			if _, ok := user.MacroSplits[goal]; !ok {
+				return fmt.Errorf("macro split for goal %s is required by the split macro model", goal)
+			}
+		}
+		for goal, split := range user.MacroSplits {
+			if !validGoals[goal] {
+				return fmt.Errorf("invalid macro split goal: %s", goal)
+			}
+			if split.Protein < 0 || split.Carbohydrates < 0 || split.Fat < 0 {
+				return fmt.Errorf("macro split for %s must not be negative", goal)
+			}
+			if sum := split.Protein + split.Carbohydrates + split.Fat; math.Abs(sum-100) > 0.01 {
+				return fmt.Errorf("macro split for %s must sum to 100, got %.2f", goal, sum)
+			}
+		}
+	default:
+		return fmt.Errorf("invalid macro model: %s (must be %s or %s)", user.MacroModel, MacroModelPerKg, MacroModelSplit)
+	}
+
+	return nil
+}
+
+// validateCache validates cache configuration
+func validateCache(config *Config) error {
+	if !config.Cache.Enabled {
+		return nil
+	}
+
+	if config.Cache.Size <= 0 {
+		return fmt.Errorf("invalid cache size: %d (must be greater than 0)", config.Cache.Size)
+	}
+
+	if config.Cache.TTL <= 0 {
+		return fmt.Errorf("invalid cache ttl: %d (must be greater than 0)", config.Cache.TTL)
+	}
+
+	return nil
+}
+
+// validateMeal validates meal configuration
+func validateMeal(config *Config) error {
+	if config.Meal.MaxTemplateCalories < 0 {
+		return fmt.Errorf("invalid meal max template calories: %v (cannot be negative)", config.Meal.MaxTemplateCalories)
+	}
+
+	if config.Meal.MaxTemplateFoodItems <= 0 {
+		return fmt.Errorf("invalid meal max template food items: %d (must be positive)", config.Meal.MaxTemplateFoodItems)
+	}
+
+	if config.Meal.MaxTemplateTags <= 0 {
+		return fmt.Errorf("invalid meal max template tags: %d (must be positive)", config.Meal.MaxTemplateTags)
+	}
+
+	if config.Meal.MaxTagLength <= 0 {
+		return fmt.Errorf("invalid meal max tag length: %d (must be positive)", config.Meal.MaxTagLength)
+	}
+
+	if config.Meal.MaxItemAmount <= 0 {
+		return fmt.Errorf("invalid meal max item amount: %v (must be positive)", config.Meal.MaxItemAmount)
+	}
+
+	if config.Meal.MaxItemGrams <= 0 {
+		return fmt.Errorf("invalid meal max item grams: %v (must be positive)", config.Meal.MaxItemGrams)
+	}
+
+	return nil
+}
+
+// validateStorage validates file storage configuration
+func validateStorage(config *Config) error {
+	if config.Storage.Driver != "local" {
+		return fmt.Errorf("invalid storage driver: %s (must be local)", config.Storage.Driver)
+	}
+
+	if config.Storage.LocalDir == "" {
+		return fmt.Errorf("storage local dir is required")
+	}
+
+	if config.Storage.MaxImageSizeKB <= 0 {
+		return fmt.Errorf("invalid storage max image size: %d (must be greater than 0)", config.Storage.MaxImageSizeKB)
+	}
+
+	return nil
+}
+
+// validateShopping validates shopping list configuration
+func validateShopping(config *Config) error {
+	if config.Shopping.RetryAttempts < 0 || config.Shopping.RetryInitialBackoff < 0 || config.Shopping.RetryMaxBackoff < 0 {
+		return fmt.Errorf("shopping retry settings cannot be negative")
+	}
+
+	return nil
+}
+
+// validateReport validates report configuration
+func validateReport(config *Config) error {
+	if config.Report.StreakTolerancePercent <= 0 || config.Report.StreakTolerancePercent > 100 {
+		return fmt.Errorf("invalid report streak tolerance percent: %v (must be greater than 0 and at most 100)", config.Report.StreakTolerancePercent)
+	}
+
+	return nil
+}
+
+// validateFood validates food configuration
+func validateFood(config *Config) error {
+	if config.Food.ServingTolerance < 0 || config.Food.ServingTolerance > 1 {
+		return fmt.Errorf("invalid food serving tolerance: %v (must be between 0 and 1 gram)", config.Food.ServingTolerance)
+	}
+
+	if config.Food.MaxServingCalories < 0 {
+		return fmt.Errorf("invalid food max serving calories: %v (must not be negative)", config.Food.MaxServingCalories)
+	}
+
+	for _, table := range []map[string]float64{config.Food.UnitConversions.Mass, config.Food.UnitConversions.Volume} {
+		for unit, factor := range table {
+			if !(factor > 0) || math.IsInf(factor, 0) {
+				return fmt.Errorf("invalid food unit conversion for %q: %v (must be positive)", unit, factor)
+			}
+		}
+	}
+
+	for _, host := range config.Food.AllowedImageHosts {
+		if strings.TrimSpace(host) == "" || strings.ContainsAny(host, "/:") {
+			return fmt.Errorf("invalid food allowed image host: %q (must be a bare host name)", host)
+		}
+	}
+
+	return nil
+}
+
+// validatePagination validates the page sizes of every list endpoint
+func validatePagination(config *Config) error {
+	endpoints := []struct {
+		name  string
+		sizes PageSizeConfig
+	}{
+		{"food_search", config.Pagination.FoodSearch},
+		{"food_list", config.Pagination.FoodList},
+		{"meal_templates", config.Pagination.MealTemplates},
+		{"meal_plans", config.Pagination.MealPlans},
+		{"shopping_lists", config.Pagination.ShoppingLists},
+	}
+
+	for _, endpoint := range endpoints {
+		if endpoint.sizes.Default <= 0 || endpoint.sizes.Max <= 0 {
+			return fmt.Errorf("invalid pagination for %s: default and max must be greater than 0", endpoint.name)
+		}
+		if endpoint.sizes.Default > endpoint.sizes.Max {
+			return fmt.Errorf("invalid pagination for %s: default %d exceeds max %d", endpoint.name, endpoint.sizes.Default, endpoint.sizes.Max)
+		}
+	}
+
+	return nil
+}
+
+// validateDailyValues validates the reference daily values
+func validateDailyValues(config *Config) error {
+	refs := config.DailyValues
+	values := []struct {
+		name  string
+		value float64
+	}{
+		{"calories", refs.Calories},
+		{"protein", refs.Protein},
+		{"carbohydrates", refs.Carbohydrates},
+		{"fat", refs.Fat},
+		{"fiber", refs.Fiber},
+		{"sugar", refs.Sugar},
+		{"vitamin_a", refs.VitaminA},
+		{"vitamin_c", refs.VitaminC},
+		{"calcium", refs.Calcium},
+		{"iron", refs.Iron},
+		{"sodium", refs.Sodium},
+		{"potassium", refs.Potassium},
+	}
+
+	for _, v := range values {
+		if v.value <= 0 {
+			return fmt.Errorf("invalid daily value for %s: %v (must be greater than 0)", v.name, v.value)
+		}
+	}
+
+	return nil
+}
+
+// validateDisplay validates the response formatting configuration
+func validateDisplay(config *Config) error {
+	if config.Display.CaloriePrecision < 0 || config.Display.CaloriePrecision > 4 {
+		return fmt.Errorf("invalid display calorie_precision: %d (must be between 0 and 4)", config.Display.CaloriePrecision)
+	}
 	return nil
 }
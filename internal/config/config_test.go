@@ -0,0 +1,112 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func newAuthTestConfig(mode string, allowDefaultUser bool) *Config {
+	cfg := newReloadTestConfig()
+	cfg.Server.Mode = mode
+	cfg.Auth = AuthConfig{
+		JWTAlgorithm:      "HS256",
+		JWTSecret:         "secret",
+		MinPasswordLength: 6,
+		AllowDefaultUser:  allowDefaultUser,
+	}
+	return cfg
+}
+
+func TestValidateAuth_RejectsDefaultUserInReleaseMode(t *testing.T) {
+	if err := validateAuth(newAuthTestConfig("release", true)); err == nil {
+		t.Error("Expected an error when default-user auth is enabled in release mode")
+	}
+}
+
+func TestValidateAuth_AllowsDefaultUserInDebugMode(t *testing.T) {
+	if err := validateAuth(newAuthTestConfig("debug", true)); err != nil {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+}
+
+func TestValidateAuth_AllowsReleaseModeWithoutDefaultUser(t *testing.T) {
+	if err := validateAuth(newAuthTestConfig("release", false)); err != nil {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+}
+
+func TestValidateUser_MacroSplits(t *testing.T) {
+	splits := func() map[string]MacroSplit {
+		return map[string]MacroSplit{
+			"weight_loss": {Protein: 40, Carbohydrates: 30, Fat: 30},
+			"muscle_gain": {Protein: 30, Carbohydrates: 45, Fat: 25},
+			"maintenance": {Protein: 30, Carbohydrates: 40, Fat: 30},
+		}
+	}
+	newConfig := func(model string, splits map[string]MacroSplit) *Config {
+		cfg := newReloadTestConfig()
+		cfg.User = UserConfig{MacroModel: model, MacroSplits: splits}
+		return cfg
+	}
+
+	if err := validateUser(newConfig(MacroModelSplit, splits())); err != nil {
+		t.Errorf("Expected valid splits to pass, got: %v", err)
+	}
+	if err := validateUser(newConfig(MacroModelPerKg, nil)); err != nil {
+		t.Errorf("Expected the per-kg model not to need splits, got: %v", err)
+	}
+
+	unbalanced := splits()
+	unbalanced["weight_loss"] = MacroSplit{Protein: 40, Carbohydrates: 40, Fat: 30}
+	missing := splits()
+	delete(missing, "muscle_gain")
+	for name, cfg := range map[string]*Config{
+		"split not summing to 100": newConfig(MacroModelSplit, unbalanced),
+		"goal without a split":     newConfig(MacroModelSplit, missing),
+		"unknown model":            newConfig("ratio", splits()),
+	} {
+		if err := validateUser(cfg); err == nil {
+			t.Errorf("Expected an error for %s", name)
+		}
+	}
+}
+
+func TestValidateUser_ProfileRanges(t *testing.T) {
+	newConfig := func(user UserConfig) *Config {
+		cfg := newReloadTestConfig()
+		cfg.User = user
+		return cfg
+	}
+
+	if err := validateUser(newConfig(UserConfig{})); err != nil {
+		t.Errorf("Expected unset limits to use the defaults, got: %v", err)
+	}
+	if err := validateUser(newConfig(UserConfig{MinAge: 18, MaxAge: 99, MinWeight: 30})); err != nil {
+		t.Errorf("Expected valid limits to pass, got: %v", err)
+	}
+	for name, user := range map[string]UserConfig{
+		"negative limit":        {MinHeight: -1},
+		"min above max":         {MinAge: 50, MaxAge: 40},
+		"min above the default": {MinWeight: 600},
+	} {
+		if err := validateUser(newConfig(user)); err == nil {
+			t.Errorf("Expected an error for %s", name)
+		}
+	}
+}
+
+func TestValidate_AcceptsDefaults(t *testing.T) {
+	viper.Reset()
+	defer viper.Reset()
+	SetDefaults()
+	viper.Set("auth.jwt_secret", "secret")
+
+	var cfg Config
+	if err := viper.Unmarshal(&cfg); err != nil {
+		t.Fatalf("Expected the defaults to unmarshal, got: %v", err)
+	}
+	if err := Validate(&cfg); err != nil {
+		t.Errorf("Expected the defaults to be valid, got: %v", err)
+	}
+}
@@ -0,0 +1,132 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/viper"
+
+	"nutrient_be/internal/pkg/logger"
+)
+
+// ReloadHook is called with the effective configuration after a reload
+type ReloadHook func(cfg *Config)
+
+// Reloader applies config file changes that are safe at runtime
+// Only the logger level and CORS origins are reloadable; other changes are logged and ignored until restart
+type Reloader struct {
+	mu      sync.Mutex
+	current Config
+	hooks   []ReloadHook
+	logger  logger.Logger
+}
+
+// NewReloader creates a reloader starting from the loaded configuration
+func NewReloader(cfg *Config, log logger.Logger) *Reloader {
+	return &Reloader{
+		current: *cfg,
+		logger:  log,
+	}
+}
+
+// OnReload registers a hook that is called after every applied reload
+func (r *Reloader) OnReload(hook ReloadHook) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.hooks = append(r.hooks, hook)
+}
+
+// Current returns a copy of the effective configuration
+func (r *Reloader) Current() Config {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.current
+}
+
+// Watch starts watching the config file viper was loaded from
+func (r *Reloader) Watch() {
+	viper.OnConfigChange(func(e fsnotify.Event) {
+		var next Config
+		if err := viper.Unmarshal(&next); err != nil {
+			r.logger.Error(context.Background(), "Failed to unmarshal reloaded config", logger.Error(err))
+			return
+		}
+		if err := r.Apply(&next); err != nil {
+			r.logger.Error(context.Background(), "Reloaded config rejected, keeping current settings",
+				logger.String("file", e.Name), logger.Error(err))
+		}
+	})
+	viper.WatchConfig()
+}
+
+// Apply takes the reloadable settings from next and runs the reload hooks
+// Changed settings that require a restart are logged and left as they are
+func (r *Reloader) Apply(next *Config) error {
+	if !validLoggerLevels[next.Logger.Level] {
+		return fmt.Errorf("invalid logger level: %s", next.Logger.Level)
+	}
+
+	r.mu.Lock()
+	if ignored := restartRequiredChanges(&r.current, next); len(ignored) > 0 {
+		r.logger.Warn(context.Background(), "Config changes require a restart and were ignored",
+			logger.String("sections", strings.Join(ignored, ", ")))
+	}
+	r.current.Logger.Level = next.Logger.Level
+	r.current.CORS.AllowedOrigins = append([]string(nil), next.CORS.AllowedOrigins...)
+	effective := r.current
+	hooks := append([]ReloadHook(nil), r.hooks...)
+	r.mu.Unlock()
+
+	r.logger.Info(context.Background(), "Config reloaded",
+		logger.String("log_level", effective.Logger.Level),
+		logger.String("cors_allowed_origins", strings.Join(effective.CORS.AllowedOrigins, ", ")))
+
+	for _, hook := range hooks {
+		hook(&effective)
+	}
+	return nil
+}
+
+// restartRequiredChanges lists the config sections that changed in ways that are not reloadable
+func restartRequiredChanges(current, next *Config) []string {
+	// Blank out the reloadable settings so they do not count as changes
+	// Development mode is derived from the level at startup, so it is not a setting of its own
+	a, b := *current, *next
+	a.Logger.Level, b.Logger.Level = "", ""
+	a.Logger.Development, b.Logger.Development = false, false
+	a.CORS, b.CORS = CORSConfig{}, CORSConfig{}
+
+	sections := []struct {
+		name         string
+		current, new interface{}
+	}{
+		{"server", a.Server, b.Server},
+		{"database", a.Database, b.Database},
+		{"auth", a.Auth, b.Auth},
+		{"nats", a.NATS, b.NATS},
+		{"logger", a.Logger, b.Logger},
+		{"user", a.User, b.User},
+		{"cache", a.Cache, b.Cache},
+		{"meal", a.Meal, b.Meal},
+		{"storage", a.Storage, b.Storage},
+		{"shopping", a.Shopping, b.Shopping},
+		{"report", a.Report, b.Report},
+		{"food", a.Food, b.Food},
+		{"pagination", a.Pagination, b.Pagination},
+		{"maintenance", a.Maintenance, b.Maintenance},
+		{"daily_values", a.DailyValues, b.DailyValues},
+		{"display", a.Display, b.Display},
+	}
+
+	var changed []string
+	for _, section := range sections {
+		if !reflect.DeepEqual(section.current, section.new) {
+			changed = append(changed, section.name)
+		}
+	}
+	return changed
+}
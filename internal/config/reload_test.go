@@ -0,0 +1,109 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+
+	"nutrient_be/internal/pkg/logger"
+)
+
+func newReloadTestConfig() *Config {
+	return &Config{
+		Server: ServerConfig{Host: "localhost", Port: 8080, Mode: "release"},
+		Logger: LoggerConfig{Level: "info", Encoding: "json"},
+		CORS:   CORSConfig{AllowedOrigins: []string{"*"}},
+	}
+}
+
+func TestReloader_Apply_ChangesLogLevel(t *testing.T) {
+	log, err := logger.NewZapLogger(false)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	levels := log.(logger.LevelController)
+	if err := levels.SetLevel("info"); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	reloader := NewReloader(newReloadTestConfig(), logger.NewNoopLogger())
+	reloader.OnReload(func(cfg *Config) {
+		if err := levels.SetLevel(cfg.Logger.Level); err != nil {
+			t.Errorf("Expected no error, got: %v", err)
+		}
+	})
+
+	next := newReloadTestConfig()
+	next.Logger.Level = "debug"
+	if err := reloader.Apply(next); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if got := levels.Level(); got != "debug" {
+		t.Errorf("Expected effective log level debug, got: %s", got)
+	}
+	if got := reloader.Current().Logger.Level; got != "debug" {
+		t.Errorf("Expected current config level debug, got: %s", got)
+	}
+}
+
+func TestReloader_Apply_IgnoresRestartRequiredSettings(t *testing.T) {
+	reloader := NewReloader(newReloadTestConfig(), logger.NewNoopLogger())
+
+	var applied *Config
+	reloader.OnReload(func(cfg *Config) { applied = cfg })
+
+	next := newReloadTestConfig()
+	next.Server.Port = 9090
+	next.Database.URI = "mongodb://other:27017"
+	next.Report.StreakTolerancePercent = 5
+	next.CORS.AllowedOrigins = []string{"https://app.example.com"}
+	if err := reloader.Apply(next); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if applied == nil {
+		t.Fatal("Expected reload hook to be called")
+	}
+	if applied.Server.Port != 8080 || applied.Database.URI != "" || applied.Report.StreakTolerancePercent != 0 {
+		t.Errorf("Expected restart-required settings to be kept, got port %d, uri %q, streak tolerance %v",
+			applied.Server.Port, applied.Database.URI, applied.Report.StreakTolerancePercent)
+	}
+	if !reflect.DeepEqual(applied.CORS.AllowedOrigins, []string{"https://app.example.com"}) {
+		t.Errorf("Expected CORS origins to be reloaded, got: %v", applied.CORS.AllowedOrigins)
+	}
+	if got := restartRequiredChanges(newReloadTestConfig(), next); !reflect.DeepEqual(got, []string{"server", "database", "report"}) {
+		t.Errorf("Expected server, database and report to require a restart, got: %v", got)
+	}
+}
+
+func TestReloader_Apply_RejectsInvalidLevel(t *testing.T) {
+	reloader := NewReloader(newReloadTestConfig(), logger.NewNoopLogger())
+	reloader.OnReload(func(cfg *Config) { t.Error("Expected hook not to be called") })
+
+	next := newReloadTestConfig()
+	next.Logger.Level = "verbose"
+	if err := reloader.Apply(next); err == nil {
+		t.Error("Expected invalid level to be rejected")
+	}
+	if got := reloader.Current().Logger.Level; got != "info" {
+		t.Errorf("Expected level to stay info, got: %s", got)
+	}
+}
+
+func TestRestartRequiredChanges_LoggerLevelOnly(t *testing.T) {
+	// The server derives development mode from the level, while a reloaded file carries its own value
+	current := newReloadTestConfig()
+	current.Logger.Level = "debug"
+	current.Logger.Development = true
+
+	next := newReloadTestConfig()
+	next.Logger.Level = "warn"
+	if got := restartRequiredChanges(current, next); len(got) != 0 {
+		t.Errorf("Expected a level change not to require a restart, got: %v", got)
+	}
+
+	next.Logger.Encoding = "console"
+	if got := restartRequiredChanges(current, next); !reflect.DeepEqual(got, []string{"logger"}) {
+		t.Errorf("Expected an encoding change to require a restart, got: %v", got)
+	}
+}
@@ -0,0 +1,215 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Server error codes returned when an index with the same name or keys already exists with other options
+const (
+	errCodeIndexOptionsConflict  = 85
+	errCodeIndexKeySpecsConflict = 86
+)
+
+// ExpectedIndex is an index the repositories rely on
+type ExpectedIndex struct {
+	Collection string
+	Keys       bson.D
+	Unique     bool
+}
+
+// Name returns the index name MongoDB generates for the keys, e.g. userId_1_status_1
+func (i ExpectedIndex) Name() string {
+	return indexSignature(i.Keys)
+}
+
+// ExpectedIndexes lists every index created by the migrations, grouped by collection
+// Compound keys use bson.D because the order of the fields is part of the index
+var ExpectedIndexes = []ExpectedIndex{
+	{Collection: "users", Keys: bson.D{{Key: "email", Value: 1}}, Unique: true},
+	{Collection: "foods", Keys: bson.D{{Key: "searchTerms", Value: "text"}}},
+	{Collection: "foods", Keys: bson.D{{Key: "createdBy", Value: 1}, {Key: "visibility", Value: 1}}},
+	{Collection: "foods", Keys: bson.D{{Key: "category", Value: 1}}},
+	{Collection: "foods", Keys: bson.D{{Key: "source", Value: 1}}},
+	{Collection: "meal_templates", Keys: bson.D{{Key: "userId", Value: 1}, {Key: "mealType", Value: 1}}},
+	{Collection: "meal_templates", Keys: bson.D{{Key: "userId", Value: 1}, {Key: "isPublic", Value: 1}}},
+	{Collection: "meal_plans", Keys: bson.D{{Key: "userId", Value: 1}, {Key: "startDate", Value: -1}}},
+	{Collection: "meal_plans", Keys: bson.D{{Key: "userId", Value: 1}, {Key: "planType", Value: 1}}},
+	{Collection: "meal_plans", Keys: bson.D{{Key: "userId", Value: 1}, {Key: "status", Value: 1}}},
+	{Collection: "shopping_lists", Keys: bson.D{{Key: "userId", Value: 1}, {Key: "mealPlanId", Value: 1}}},
+	{Collection: "shopping_lists", Keys: bson.D{{Key: "userId", Value: 1}, {Key: "status", Value: 1}}},
+}
+
+// IndexCollections returns the collections with expected indexes, in the order they are first listed
+func IndexCollections() []string {
+	var collections []string
+	seen := make(map[string]bool)
+	for _, index := range ExpectedIndexes {
+		if !seen[index.Collection] {
+			seen[index.Collection] = true
+			collections = append(collections, index.Collection)
+		}
+	}
+	return collections
+}
+
+// ExistingIndex is an index as reported by listIndexes
+type ExistingIndex struct {
+	Name    string `bson:"name"`
+	Key     bson.D `bson:"key"`
+	Weights bson.D `bson:"weights,omitempty"` // Fields of a text index, whose key only holds _fts and _ftsx
+	Unique  bool   `bson:"unique,omitempty"`
+}
+
+// Signature identifies the index by its keys, in the same form as ExpectedIndex.Name
+func (i ExistingIndex) Signature() string {
+	if len(i.Weights) > 0 {
+		keys := make(bson.D, len(i.Weights))
+		for j, weight := range i.Weights {
+			keys[j] = bson.E{Key: weight.Key, Value: "text"}
+		}
+		return indexSignature(keys)
+	}
+	return indexSignature(i.Key)
+}
+
+// ExpectedIndexStatus reports whether an expected index exists
+type ExpectedIndexStatus struct {
+	Name    string
+	Present bool
+}
+
+// CollectionIndexStatus compares the indexes of a collection with the expected ones
+type CollectionIndexStatus struct {
+	Collection string
+	Existing   []string // Names of the indexes currently on the collection
+	Expected   []ExpectedIndexStatus
+}
+
+// Missing returns the names of the expected indexes that do not exist
+func (s CollectionIndexStatus) Missing() []string {
+	var missing []string
+	for _, expected := range s.Expected {
+		if !expected.Present {
+			missing = append(missing, expected.Name)
+		}
+	}
+	return missing
+}
+
+// EnsureIndexes creates the expected indexes of a collection
+// Indexes that already exist, under any name, are left alone, so running it again is safe
+// It returns the names of the indexes it created
+func (m *MongoDB) EnsureIndexes(ctx context.Context, collection string) ([]string, error) {
+	existing, err := m.ListIndexes(ctx, collection)
+	if err != nil {
+		return nil, err
+	}
+	present := make(map[string]bool, len(existing))
+	for _, index := range existing {
+		present[index.Signature()] = true
+	}
+
+	var created []string
+	indexes := m.GetCollection(collection).Indexes()
+	for _, index := range ExpectedIndexes {
+		if index.Collection != collection || present[index.Name()] {
+			continue
+		}
+
+		opts := options.Index().SetName(index.Name())
+		if index.Unique {
+			opts.SetUnique(true)
+		}
+		if _, err := indexes.CreateOne(ctx, mongo.IndexModel{Keys: index.Keys, Options: opts}); err != nil {
+			// An equivalent index created concurrently, or under another name, counts as present
+			if isIndexConflict(err) {
+				continue
+			}
+			return created, fmt.Errorf("failed to create %s index %s: %w", collection, index.Name(), err)
+		}
+		created = append(created, index.Name())
+	}
+	return created, nil
+}
+
+// ListIndexes returns the indexes currently on a collection
+func (m *MongoDB) ListIndexes(ctx context.Context, collection string) ([]ExistingIndex, error) {
+	cursor, err := m.GetCollection(collection).Indexes().List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s indexes: %w", collection, err)
+	}
+	defer cursor.Close(ctx)
+
+	var indexes []ExistingIndex
+	if err := cursor.All(ctx, &indexes); err != nil {
+		return nil, fmt.Errorf("failed to decode %s indexes: %w", collection, err)
+	}
+	return indexes, nil
+}
+
+// IndexStatus compares the indexes of every collection with the expected ones
+func (m *MongoDB) IndexStatus(ctx context.Context) ([]CollectionIndexStatus, error) {
+	existing := make(map[string][]ExistingIndex)
+	for _, collection := range IndexCollections() {
+		indexes, err := m.ListIndexes(ctx, collection)
+		if err != nil {
+			return nil, err
+		}
+		existing[collection] = indexes
+	}
+	return compareIndexes(ExpectedIndexes, existing), nil
+}
+
+// compareIndexes reports, per collection, the existing indexes and which expected ones are present
+// Indexes are matched by their keys, so an expected index created under another name still counts
+func compareIndexes(expected []ExpectedIndex, existing map[string][]ExistingIndex) []CollectionIndexStatus {
+	var statuses []CollectionIndexStatus
+	positions := make(map[string]int)
+	for _, index := range expected {
+		pos, ok := positions[index.Collection]
+		if !ok {
+			status := CollectionIndexStatus{Collection: index.Collection, Existing: []string{}}
+			for _, current := range existing[index.Collection] {
+				status.Existing = append(status.Existing, current.Name)
+			}
+			statuses = append(statuses, status)
+			pos = len(statuses) - 1
+			positions[index.Collection] = pos
+		}
+
+		present := false
+		for _, current := range existing[index.Collection] {
+			if current.Signature() == index.Name() {
+				present = true
+				break
+			}
+		}
+		statuses[pos].Expected = append(statuses[pos].Expected, ExpectedIndexStatus{Name: index.Name(), Present: present})
+	}
+	return statuses
+}
+
+// indexSignature joins index fields and directions the way MongoDB names indexes by default
+func indexSignature(keys bson.D) string {
+	parts := make([]string, 0, len(keys)*2)
+	for _, key := range keys {
+		parts = append(parts, key.Key, fmt.Sprint(key.Value))
+	}
+	return strings.Join(parts, "_")
+}
+
+// isIndexConflict reports whether err means an index with the same name or keys already exists
+func isIndexConflict(err error) bool {
+	var cmdErr mongo.CommandError
+	if errors.As(err, &cmdErr) {
+		return cmdErr.Code == errCodeIndexOptionsConflict || cmdErr.Code == errCodeIndexKeySpecsConflict
+	}
+	return false
+}
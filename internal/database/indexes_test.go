@@ -0,0 +1,69 @@
+package database
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestCompareIndexes_ReportsMissingExpectedIndex(t *testing.T) {
+	expected := []ExpectedIndex{
+		{Collection: "foods", Keys: bson.D{{Key: "searchTerms", Value: "text"}}},
+		{Collection: "foods", Keys: bson.D{{Key: "category", Value: 1}}},
+		{Collection: "meal_plans", Keys: bson.D{{Key: "userId", Value: 1}, {Key: "startDate", Value: -1}}},
+	}
+	existing := map[string][]ExistingIndex{
+		"foods": {
+			{Name: "_id_", Key: bson.D{{Key: "_id", Value: int32(1)}}},
+			// Text indexes list their fields as weights, and this one was created under a custom name
+			{Name: "food_search", Key: bson.D{{Key: "_fts", Value: "text"}, {Key: "_ftsx", Value: int32(1)}}, Weights: bson.D{{Key: "searchTerms", Value: int32(1)}}},
+		},
+		"meal_plans": {
+			{Name: "_id_", Key: bson.D{{Key: "_id", Value: int32(1)}}},
+			{Name: "userId_1_startDate_-1", Key: bson.D{{Key: "userId", Value: int32(1)}, {Key: "startDate", Value: int32(-1)}}},
+		},
+	}
+
+	statuses := compareIndexes(expected, existing)
+	if len(statuses) != 2 {
+		t.Fatalf("Expected 2 collections, got: %d", len(statuses))
+	}
+
+	foods := statuses[0]
+	if foods.Collection != "foods" || !reflect.DeepEqual(foods.Existing, []string{"_id_", "food_search"}) {
+		t.Errorf("Expected the current foods indexes, got: %+v", foods)
+	}
+	if missing := foods.Missing(); !reflect.DeepEqual(missing, []string{"category_1"}) {
+		t.Errorf("Expected category_1 to be missing, got: %v", missing)
+	}
+	if missing := statuses[1].Missing(); len(missing) != 0 {
+		t.Errorf("Expected no missing meal_plans index, got: %v", missing)
+	}
+}
+
+func TestCompareIndexes_CollectionWithoutIndexes(t *testing.T) {
+	expected := []ExpectedIndex{{Collection: "users", Keys: bson.D{{Key: "email", Value: 1}}, Unique: true}}
+
+	statuses := compareIndexes(expected, map[string][]ExistingIndex{})
+	if len(statuses) != 1 || len(statuses[0].Existing) != 0 {
+		t.Fatalf("Expected users with no current indexes, got: %+v", statuses)
+	}
+	if missing := statuses[0].Missing(); !reflect.DeepEqual(missing, []string{"email_1"}) {
+		t.Errorf("Expected email_1 to be missing, got: %v", missing)
+	}
+}
+
+func TestIsIndexConflict(t *testing.T) {
+	if !isIndexConflict(fmt.Errorf("create: %w", mongo.CommandError{Code: errCodeIndexOptionsConflict})) {
+		t.Error("Expected an options conflict to count as an existing index")
+	}
+	if !isIndexConflict(mongo.CommandError{Code: errCodeIndexKeySpecsConflict}) {
+		t.Error("Expected a key specs conflict to count as an existing index")
+	}
+	if isIndexConflict(mongo.CommandError{Code: 13}) {
+		t.Error("Expected an unauthorized error not to count as an existing index")
+	}
+}
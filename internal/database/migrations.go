@@ -0,0 +1,82 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// migrationsCollection stores one document per applied migration
+const migrationsCollection = "schema_migrations"
+
+// Migrations lists the migrations this build expects, in the order they are applied
+var Migrations = []string{
+	"0001_create_indexes",
+	"0002_normalize_search_terms",
+	"0003_normalize_user_emails",
+	"0004_backfill_shopping_item_ids",
+}
+
+// RecordMigration marks a migration as applied
+func (m *MongoDB) RecordMigration(ctx context.Context, id string) error {
+	_, err := m.GetCollection(migrationsCollection).UpdateOne(ctx,
+		bson.M{"_id": id},
+		bson.M{"$setOnInsert": bson.M{"appliedAt": time.Now()}},
+		options.Update().SetUpsert(true))
+	if err != nil {
+		return fmt.Errorf("failed to record migration %s: %w", id, err)
+	}
+	return nil
+}
+
+// MigrationStatus returns the applied migrations and the expected ones that have not been applied
+func (m *MongoDB) MigrationStatus(ctx context.Context) ([]string, []string, error) {
+	cursor, err := m.GetCollection(migrationsCollection).Find(ctx, bson.M{}, options.Find().SetSort(bson.M{"_id": 1}))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list migrations: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var records []struct {
+		ID string `bson:"_id"`
+	}
+	if err := cursor.All(ctx, &records); err != nil {
+		return nil, nil, fmt.Errorf("failed to decode migrations: %w", err)
+	}
+
+	applied := make([]string, len(records))
+	for i, record := range records {
+		applied[i] = record.ID
+	}
+	return applied, pendingMigrations(applied), nil
+}
+
+// ServerVersion returns the MongoDB server version
+func (m *MongoDB) ServerVersion(ctx context.Context) (string, error) {
+	var info struct {
+		Version string `bson:"version"`
+	}
+	if err := m.Database.RunCommand(ctx, bson.D{{Key: "buildInfo", Value: 1}}).Decode(&info); err != nil {
+		return "", fmt.Errorf("failed to get server version: %w", err)
+	}
+	return info.Version, nil
+}
+
+// pendingMigrations returns the expected migrations missing from applied
+func pendingMigrations(applied []string) []string {
+	done := make(map[string]bool, len(applied))
+	for _, id := range applied {
+		done[id] = true
+	}
+
+	pending := []string{}
+	for _, id := range Migrations {
+		if !done[id] {
+			pending = append(pending, id)
+		}
+	}
+	return pending
+}
@@ -18,35 +18,50 @@ type MongoDB struct {
 	Client   *mongo.Client
 	Database *mongo.Database
 	logger   logger.Logger
+
+	transactions transactionSupport
 }
 
+// defaultConnectTimeout is used when no connect timeout is configured
+const defaultConnectTimeout = 10 * time.Second
+
 // NewMongoDB creates a new MongoDB connection
+// The initial connect and ping are bounded by ConnectTimeout so an unreachable server fails fast
 func NewMongoDB(cfg *config.DatabaseConfig, log logger.Logger) (*MongoDB, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), cfg.ConnectTimeout*time.Second)
-	defer cancel()
+	connectTimeout := cfg.ConnectTimeout * time.Second
+	if connectTimeout <= 0 {
+		connectTimeout = defaultConnectTimeout
+	}
 
-	// Set client options
-	clientOptions := options.Client().
-		ApplyURI(cfg.URI).
-		SetMaxPoolSize(cfg.MaxPoolSize).
-		SetMinPoolSize(cfg.MinPoolSize)
+	ctx, cancel := context.WithTimeout(context.Background(), connectTimeout)
+	defer cancel()
 
 	// Connect to MongoDB
-	client, err := mongo.Connect(ctx, clientOptions)
+	client, err := mongo.Connect(ctx, clientOptions(cfg, connectTimeout))
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to MongoDB: %w", err)
 	}
 
 	// Ping the database to verify connection
 	if err := client.Ping(ctx, readpref.Primary()); err != nil {
-		return nil, fmt.Errorf("failed to ping MongoDB: %w", err)
+		disconnectCtx, disconnectCancel := context.WithTimeout(context.Background(), time.Second)
+		defer disconnectCancel()
+		_ = client.Disconnect(disconnectCtx)
+		return nil, fmt.Errorf("failed to ping MongoDB within %s: %w", connectTimeout, err)
 	}
 
 	database := client.Database(cfg.Database)
 
 	log.Info(ctx, "Successfully connected to MongoDB",
 		logger.String("database", cfg.Database),
-		logger.String("uri", cfg.URI))
+		logger.String("uri", config.RedactURI(cfg.URI)),
+		logger.Int("max_pool_size", int(cfg.MaxPoolSize)),
+		logger.Int("min_pool_size", int(cfg.MinPoolSize)),
+		logger.String("max_conn_idle_time", (cfg.MaxConnIdleTime*time.Second).String()),
+		logger.String("connect_timeout", connectTimeout.String()),
+		logger.String("server_selection_timeout", (cfg.ServerSelectionTimeout*time.Second).String()),
+		logger.String("socket_timeout", (cfg.SocketTimeout*time.Second).String()),
+		logger.String("operation_timeout", (cfg.OperationTimeout*time.Second).String()))
 
 	return &MongoDB{
 		Client:   client,
@@ -55,6 +70,31 @@ func NewMongoDB(cfg *config.DatabaseConfig, log logger.Logger) (*MongoDB, error)
 	}, nil
 }
 
+// clientOptions builds the client options from the database configuration
+// Zero timeouts are left unset so the driver defaults apply
+func clientOptions(cfg *config.DatabaseConfig, connectTimeout time.Duration) *options.ClientOptions {
+	opts := options.Client().
+		ApplyURI(cfg.URI).
+		SetMaxPoolSize(cfg.MaxPoolSize).
+		SetMinPoolSize(cfg.MinPoolSize).
+		SetConnectTimeout(connectTimeout)
+
+	if cfg.MaxConnIdleTime > 0 {
+		opts.SetMaxConnIdleTime(cfg.MaxConnIdleTime * time.Second)
+	}
+	if cfg.ServerSelectionTimeout > 0 {
+		opts.SetServerSelectionTimeout(cfg.ServerSelectionTimeout * time.Second)
+	}
+	if cfg.SocketTimeout > 0 {
+		opts.SetSocketTimeout(cfg.SocketTimeout * time.Second)
+	}
+	if cfg.OperationTimeout > 0 {
+		opts.SetTimeout(cfg.OperationTimeout * time.Second)
+	}
+
+	return opts
+}
+
 // Close closes the MongoDB connection
 func (m *MongoDB) Close(ctx context.Context) error {
 	if err := m.Client.Disconnect(ctx); err != nil {
@@ -0,0 +1,77 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"nutrient_be/internal/config"
+	"nutrient_be/internal/pkg/logger"
+)
+
+func TestNewMongoDB_UnreachableURIFailsWithinConnectTimeout(t *testing.T) {
+	cfg := &config.DatabaseConfig{
+		// Nothing listens on port 1; the driver keeps retrying until a timeout fires
+		URI:                    "mongodb://127.0.0.1:1/?directConnection=true",
+		Database:               "nutrient_test",
+		MaxPoolSize:            10,
+		ConnectTimeout:         1,
+		ServerSelectionTimeout: 30, // Longer than ConnectTimeout, which must win
+	}
+
+	start := time.Now()
+	db, err := NewMongoDB(cfg, logger.NewNoopLogger())
+	elapsed := time.Since(start)
+
+	if err == nil {
+		db.Close(t.Context())
+		t.Fatal("Expected error for unreachable URI, got nil")
+	}
+	if elapsed > 3*time.Second {
+		t.Errorf("Expected failure within connect timeout (1s), took: %s", elapsed)
+	}
+}
+
+func TestClientOptions_AppliesPoolAndTimeouts(t *testing.T) {
+	cfg := &config.DatabaseConfig{
+		URI:                    "mongodb://localhost:27017",
+		MaxPoolSize:            50,
+		MinPoolSize:            5,
+		MaxConnIdleTime:        60,
+		ServerSelectionTimeout: 5,
+		SocketTimeout:          30,
+		OperationTimeout:       10,
+	}
+
+	opts := clientOptions(cfg, 2*time.Second)
+
+	if opts.MaxPoolSize == nil || *opts.MaxPoolSize != 50 {
+		t.Errorf("Expected max pool size 50, got: %v", opts.MaxPoolSize)
+	}
+	if opts.MinPoolSize == nil || *opts.MinPoolSize != 5 {
+		t.Errorf("Expected min pool size 5, got: %v", opts.MinPoolSize)
+	}
+	if opts.MaxConnIdleTime == nil || *opts.MaxConnIdleTime != time.Minute {
+		t.Errorf("Expected max idle time 1m, got: %v", opts.MaxConnIdleTime)
+	}
+	if opts.ConnectTimeout == nil || *opts.ConnectTimeout != 2*time.Second {
+		t.Errorf("Expected connect timeout 2s, got: %v", opts.ConnectTimeout)
+	}
+	if opts.ServerSelectionTimeout == nil || *opts.ServerSelectionTimeout != 5*time.Second {
+		t.Errorf("Expected server selection timeout 5s, got: %v", opts.ServerSelectionTimeout)
+	}
+	if opts.SocketTimeout == nil || *opts.SocketTimeout != 30*time.Second {
+		t.Errorf("Expected socket timeout 30s, got: %v", opts.SocketTimeout)
+	}
+	if opts.Timeout == nil || *opts.Timeout != 10*time.Second {
+		t.Errorf("Expected operation timeout 10s, got: %v", opts.Timeout)
+	}
+}
+
+func TestPendingMigrations(t *testing.T) {
+	if got := pendingMigrations(nil); len(got) != len(Migrations) {
+		t.Errorf("Expected all %d migrations pending, got: %v", len(Migrations), got)
+	}
+	if got := pendingMigrations(Migrations); len(got) != 0 {
+		t.Errorf("Expected no pending migrations, got: %v", got)
+	}
+}
@@ -0,0 +1,65 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"nutrient_be/internal/pkg/logger"
+)
+
+// transactionSupport caches whether the connected deployment supports multi-document transactions
+type transactionSupport struct {
+	mu        sync.Mutex
+	checked   bool
+	supported bool
+}
+
+// WithTransaction runs fn inside a MongoDB transaction so its writes are applied together or not at all
+// fn must pass the context it receives to the repositories; it may run again when the transaction is retried.
+// Standalone servers do not support transactions, so there fn runs directly and a failing step can leave earlier writes applied
+func (m *MongoDB) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if !m.supportsTransactions(ctx) {
+		return fn(ctx)
+	}
+
+	session, err := m.Client.StartSession()
+	if err != nil {
+		return fmt.Errorf("failed to start session: %w", err)
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sessionCtx mongo.SessionContext) (interface{}, error) {
+		return nil, fn(sessionCtx)
+	})
+	return err
+}
+
+// supportsTransactions reports whether the deployment is a replica set or sharded cluster
+// The answer is cached once the server has been asked successfully
+func (m *MongoDB) supportsTransactions(ctx context.Context) bool {
+	m.transactions.mu.Lock()
+	defer m.transactions.mu.Unlock()
+	if m.transactions.checked {
+		return m.transactions.supported
+	}
+
+	var hello struct {
+		SetName string `bson:"setName"`
+		Msg     string `bson:"msg"`
+	}
+	if err := m.Database.RunCommand(ctx, bson.D{{Key: "hello", Value: 1}}).Decode(&hello); err != nil {
+		m.logger.Warn(ctx, "Failed to check transaction support, running without a transaction", logger.Error(err))
+		return false
+	}
+
+	m.transactions.checked = true
+	m.transactions.supported = hello.SetName != "" || hello.Msg == "isdbgrid"
+	if !m.transactions.supported {
+		m.logger.Warn(ctx, "MongoDB is a standalone server, multi-step writes run without transactions")
+	}
+	return m.transactions.supported
+}
@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Sort orders for food queries
+const (
+	FoodSortNewest        = "newest"         // Most recently created first
+	FoodSortImportedFirst = "imported_first" // Curated imported foods first, then newest first
+)
+
+// NutrientRange bounds a per-100g nutrient value; a nil bound is open
+type NutrientRange struct {
+	Min *float64
+	Max *float64
+}
+
+// IsSet reports whether the range constrains anything
+func (r NutrientRange) IsSet() bool {
+	return r.Min != nil || r.Max != nil
+}
+
+// FoodFilter selects food items; every field left empty matches all foods
+// Set fields are combined, so a food must satisfy all of them
+type FoodFilter struct {
+	Query            string              // Matched against search terms without diacritics and against names, ignoring case
+	Categories       []string            // Any of these categories
+	Tags             []string            // Any of these search terms
+	ExcludeAllergens []string            // None of these allergens
+	DietTags         []string            // All of these diet tags
+	Source           string              // "user" or "imported"
+	Visibility       string              // "public" or "private"
+	CreatedBy        *primitive.ObjectID // Only foods created by this user
+	AccessibleTo     *primitive.ObjectID // Public foods and the private foods of this user; the nil ID only sees public foods
+	Calories         NutrientRange
+	Protein          NutrientRange
+	Carbohydrates    NutrientRange
+	Fat              NutrientRange
+	Fiber            NutrientRange
+}
+
+// Page selects a window of a result list
+// After continues the newest-first order past a previous page instead of skipping Offset results,
+// so foods created or deleted meanwhile cannot shift the window
+type Page struct {
+	Limit  int // 0 returns every result
+	Offset int
+	After  *PageCursor
+}
+
+// PageCursor is the position of the last result of a page in the newest-first order
+type PageCursor struct {
+	CreatedAt time.Time
+	ID        primitive.ObjectID
+}
+
+// FoodPageCursor returns the position of food in the newest-first order
+func FoodPageCursor(food *FoodItem) *PageCursor {
+	return &PageCursor{CreatedAt: food.CreatedAt, ID: food.ID}
+}
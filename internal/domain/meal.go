@@ -34,6 +34,12 @@ type MealTemplate struct {
 	UpdatedAt     time.Time              `bson:"updatedAt" json:"updatedAt"`
 }
 
+// TagCount is a template tag and how many templates carry it
+type TagCount struct {
+	Tag   string `bson:"_id" json:"tag"`
+	Count int64  `bson:"count" json:"count"`
+}
+
 // MealFoodItem represents a food item in a meal
 type MealFoodItem struct {
 	FoodItemID   primitive.ObjectID `bson:"foodItemId" json:"foodItemId"`
@@ -69,6 +75,13 @@ type DailyMeal struct {
 	IsCompleted   bool           `bson:"isCompleted" json:"isCompleted"`
 }
 
+// Meal plan statuses
+const (
+	MealPlanStatusDraft     = "draft"
+	MealPlanStatusActive    = "active"
+	MealPlanStatusCompleted = "completed"
+)
+
 // MealPlan represents a complete eating schedule for a time period
 type MealPlan struct {
 	ID             primitive.ObjectID `bson:"_id,omitempty" json:"id"`
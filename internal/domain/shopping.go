@@ -7,22 +7,36 @@ import (
 )
 
 // ShoppingItem represents an item in a shopping list
+// Items added manually may be free text without a food reference
 type ShoppingItem struct {
-	FoodItemID  primitive.ObjectID `bson:"foodItemId" json:"foodItemId"`
-	FoodName    string             `bson:"foodName" json:"foodName"`
-	TotalAmount float64            `bson:"totalAmount" json:"totalAmount"`
-	Unit        string             `bson:"unit" json:"unit"`
-	Checked     bool               `bson:"checked" json:"checked"`
+	ID          primitive.ObjectID  `bson:"id" json:"id"`
+	FoodItemID  *primitive.ObjectID `bson:"foodItemId,omitempty" json:"foodItemId,omitempty"`
+	FoodName    string              `bson:"foodName" json:"foodName"`
+	TotalAmount float64             `bson:"totalAmount" json:"totalAmount"` // In Unit, for display
+	Unit        string              `bson:"unit" json:"unit"`
+	TotalGrams  float64             `bson:"totalGrams,omitempty" json:"totalGrams,omitempty"` // Canonical amount
+	Display     string              `bson:"display,omitempty" json:"display,omitempty"`       // e.g. "3 pieces"
+	Cost        float64             `bson:"cost,omitempty" json:"cost,omitempty"`             // Optional
+	Checked     bool                `bson:"checked" json:"checked"`
+	IsManual    bool                `bson:"isManual,omitempty" json:"isManual,omitempty"`
 }
 
-// ShoppingList represents a shopping list generated from a meal plan
+// Shopping list statuses
+const (
+	ShoppingListStatusActive    = "active"
+	ShoppingListStatusCompleted = "completed"
+)
+
+// ShoppingList represents a shopping list generated from a meal plan, or merged from other lists
 type ShoppingList struct {
-	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	UserID     primitive.ObjectID `bson:"userId" json:"userId"`
-	MealPlanID primitive.ObjectID `bson:"mealPlanId" json:"mealPlanId"`
-	Items      []ShoppingItem     `bson:"items" json:"items"`
-	TotalCost  float64            `bson:"totalCost,omitempty" json:"totalCost,omitempty"` // Optional
-	Status     string             `bson:"status" json:"status"`                           // "pending", "completed"
-	CreatedAt  time.Time          `bson:"createdAt" json:"createdAt"`
-	UpdatedAt  time.Time          `bson:"updatedAt" json:"updatedAt"`
+	ID            primitive.ObjectID   `bson:"_id,omitempty" json:"id"`
+	UserID        primitive.ObjectID   `bson:"userId" json:"userId"`
+	MealPlanID    primitive.ObjectID   `bson:"mealPlanId,omitempty" json:"mealPlanId,omitempty"` // Unset on merged lists
+	Name          string               `bson:"name,omitempty" json:"name,omitempty"`
+	SourceListIDs []primitive.ObjectID `bson:"sourceListIds,omitempty" json:"sourceListIds,omitempty"` // Lists a merged list was built from
+	Items         []ShoppingItem       `bson:"items" json:"items"`
+	TotalCost     float64              `bson:"totalCost,omitempty" json:"totalCost,omitempty"` // Optional
+	Status        string               `bson:"status" json:"status"`                           // "active", "completed"
+	CreatedAt     time.Time            `bson:"createdAt" json:"createdAt"`
+	UpdatedAt     time.Time            `bson:"updatedAt" json:"updatedAt"`
 }
@@ -11,13 +11,14 @@ import (
 
 // User represents a user in the system
 type User struct {
-	ID           primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	Email        string             `bson:"email" json:"email"`
-	PasswordHash string             `bson:"passwordHash" json:"-"`
-	Profile      UserProfile        `bson:"profile" json:"profile"`
-	Preferences  UserPreferences    `bson:"preferences" json:"preferences"`
-	CreatedAt    time.Time          `bson:"createdAt" json:"createdAt"`
-	UpdatedAt    time.Time          `bson:"updatedAt" json:"updatedAt"`
+	ID            primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Email         string             `bson:"email" json:"email"`
+	PasswordHash  string             `bson:"passwordHash" json:"-"`
+	Profile       UserProfile        `bson:"profile" json:"profile"`
+	Preferences   UserPreferences    `bson:"preferences" json:"preferences"`
+	WeightHistory []WeightEntry      `bson:"weightHistory,omitempty" json:"weightHistory,omitempty"` // Oldest first
+	CreatedAt     time.Time          `bson:"createdAt" json:"createdAt"`
+	UpdatedAt     time.Time          `bson:"updatedAt" json:"updatedAt"`
 }
 
 // UserProfile contains user profile information
@@ -28,13 +29,49 @@ type UserProfile struct {
 	Height float64 `bson:"height" json:"height"`
 	Gender string  `bson:"gender" json:"gender"`
 	Goal   string  `bson:"goal" json:"goal"` // "weight_loss", "muscle_gain", "maintenance"
+	// "sedentary", "light", "moderate", "active", "very_active"
+	ActivityLevel string `bson:"activityLevel,omitempty" json:"activityLevel,omitempty"`
 }
 
+// WeightEntry is a single body weight measurement in kg
+type WeightEntry struct {
+	Weight float64   `bson:"weight" json:"weight"`
+	Date   time.Time `bson:"date" json:"date"`
+}
+
+// Units macro targets are stored in
+const (
+	MacroUnitGramsPerKg  = "g_per_kg"  // Grams per kg of body weight, as calculated by the per_kg macro model
+	MacroUnitGramsPerDay = "g_per_day" // Daily grams, as calculated by the split model or entered by the user
+)
+
 // UserPreferences contains user preferences
 type UserPreferences struct {
 	Language      string         `bson:"language" json:"language"`
 	CalorieTarget float64        `bson:"calorieTarget" json:"calorieTarget"`
 	MacroTargets  MacroNutrients `bson:"macroTargets" json:"macroTargets"`
+	// Unit of MacroTargets; unset for targets stored before it existed, which follow the configured macro model
+	MacroTargetsUnit string `bson:"macroTargetsUnit,omitempty" json:"macroTargetsUnit,omitempty"`
+	WeekStart        string `bson:"weekStart,omitempty" json:"weekStart,omitempty"` // "monday" (default) or "sunday"
+	// Overrides of the configured reference daily values, zero fields use the configured value
+	DailyValues DailyReferenceValues `bson:"dailyValues,omitempty" json:"dailyValues,omitempty"`
+}
+
+// DailyReferenceValues contains the reference daily intake %DV is computed against
+// Energy is in kcal, macros in grams, vitamin A in mcg RAE and other micros in mg
+type DailyReferenceValues struct {
+	Calories      float64 `bson:"calories,omitempty" json:"calories,omitempty"`
+	Protein       float64 `bson:"protein,omitempty" json:"protein,omitempty"`
+	Carbohydrates float64 `bson:"carbohydrates,omitempty" json:"carbohydrates,omitempty"`
+	Fat           float64 `bson:"fat,omitempty" json:"fat,omitempty"`
+	Fiber         float64 `bson:"fiber,omitempty" json:"fiber,omitempty"`
+	Sugar         float64 `bson:"sugar,omitempty" json:"sugar,omitempty"`
+	VitaminA      float64 `bson:"vitaminA,omitempty" json:"vitaminA,omitempty"`
+	VitaminC      float64 `bson:"vitaminC,omitempty" json:"vitaminC,omitempty"`
+	Calcium       float64 `bson:"calcium,omitempty" json:"calcium,omitempty"`
+	Iron          float64 `bson:"iron,omitempty" json:"iron,omitempty"`
+	Sodium        float64 `bson:"sodium,omitempty" json:"sodium,omitempty"`
+	Potassium     float64 `bson:"potassium,omitempty" json:"potassium,omitempty"`
 }
 
 // MacroNutrients represents macronutrient values
@@ -64,23 +101,33 @@ type ServingSize struct {
 	GramEquivalent float64 `bson:"gramEquivalent" json:"gramEquivalent"`               // Convert to grams
 }
 
+// Food sources, imported foods come from curated datasets
+const (
+	FoodSourceUser     = "user"
+	FoodSourceImported = "imported"
+)
+
 // FoodItem represents a food item in the database
 type FoodItem struct {
-	ID           primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	Name         map[string]string  `bson:"name" json:"name"` // Multi-language support
-	SearchTerms  []string           `bson:"searchTerms" json:"searchTerms"`
-	Description  map[string]string  `bson:"description,omitempty" json:"description,omitempty"`
-	Category     string             `bson:"category" json:"category"` // "protein", "vegetable", "fruit", "dairy", "grain"
-	Macros       MacroNutrients     `bson:"macros" json:"macros"`
-	Micros       MicroNutrients     `bson:"micros" json:"micros"`
-	ServingSizes []ServingSize      `bson:"servingSizes" json:"servingSizes"`
-	Calories     float64            `bson:"calories" json:"calories"` // Base calories per 100g
-	CreatedBy    primitive.ObjectID `bson:"createdBy" json:"createdBy"`
-	Visibility   string             `bson:"visibility" json:"visibility"` // "public" or "private"
-	Source       string             `bson:"source" json:"source"`         // "user" or "imported"
-	ImageURL     string             `bson:"imageUrl,omitempty" json:"imageUrl,omitempty"`
-	CreatedAt    time.Time          `bson:"createdAt" json:"createdAt"`
-	UpdatedAt    time.Time          `bson:"updatedAt" json:"updatedAt"`
+	ID           primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	Name         map[string]string   `bson:"name" json:"name"` // Multi-language support
+	SearchTerms  []string            `bson:"searchTerms" json:"searchTerms"`
+	Description  map[string]string   `bson:"description,omitempty" json:"description,omitempty"`
+	Category     string              `bson:"category" json:"category"` // "protein", "vegetable", "fruit", "dairy", "grain"
+	Macros       MacroNutrients      `bson:"macros" json:"macros"`
+	Micros       MicroNutrients      `bson:"micros" json:"micros"`
+	ServingSizes []ServingSize       `bson:"servingSizes" json:"servingSizes"`
+	Calories     float64             `bson:"calories" json:"calories"` // Base calories per 100g
+	CreatedBy    primitive.ObjectID  `bson:"createdBy" json:"createdBy"`
+	Visibility   string              `bson:"visibility" json:"visibility"` // "public" or "private"
+	Source       string              `bson:"source" json:"source"`         // "user" or "imported"
+	ImageURL     string              `bson:"imageUrl,omitempty" json:"imageUrl,omitempty"`
+	Allergens    []string            `bson:"allergens,omitempty" json:"allergens,omitempty"` // Lowercased, e.g. "peanut"
+	DietTags     []string            `bson:"dietTags,omitempty" json:"dietTags,omitempty"`   // Lowercased, e.g. "vegan"
+	MergedInto   *primitive.ObjectID `bson:"mergedInto,omitempty" json:"-"`                  // Set with DeletedAt when merged into another food
+	DeletedAt    *time.Time          `bson:"deletedAt,omitempty" json:"-"`                   // Soft-deleted foods are hidden from every read
+	CreatedAt    time.Time           `bson:"createdAt" json:"createdAt"`
+	UpdatedAt    time.Time           `bson:"updatedAt" json:"updatedAt"`
 }
 
 func FoodItemFromRequest(ctx context.Context, req *request.CreateFoodRequest, userID string) *FoodItem {
@@ -120,11 +167,13 @@ func FoodItemFromRequest(ctx context.Context, req *request.CreateFoodRequest, us
 			Potassium: req.Micros.Potassium,
 		},
 		ServingSizes: servingSizes,
-		Calories:     req.Calories,
+		Calories:     req.CaloriesValue(),
 		CreatedBy:    userIDObj,
 		Visibility:   req.Visibility,
-		Source:       "user",
+		Source:       FoodSourceUser,
 		ImageURL:     req.ImageURL,
+		Allergens:    request.NormalizeFoodLabels(req.Allergens),
+		DietTags:     request.NormalizeFoodLabels(req.DietTags),
 		CreatedAt:    time.Now(),
 		UpdatedAt:    time.Now(),
 	}
@@ -0,0 +1,6 @@
+package request
+
+// SetReadOnlyRequest represents a request to turn read-only mode on or off
+type SetReadOnlyRequest struct {
+	Enabled *bool `json:"enabled" validate:"required"`
+}
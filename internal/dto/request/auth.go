@@ -1,10 +1,29 @@
 package request
 
-// RegisterRequest represents a basic user registration request (email and password only)
-// Profile information should be set separately via user endpoints
+import "strings"
+
+// RegisterRequest represents a user registration request
+// Only email and password are required; profile information can also be set later via user endpoints.
+// When weight, height and age are provided, initial calorie and macro targets are calculated immediately.
 type RegisterRequest struct {
-	Email    string `json:"email" validate:"required,email"`
-	Password string `json:"password" validate:"required,min=6"`
+	Email         string   `json:"email" validate:"required,email"`
+	Password      string   `json:"password" validate:"required,password"` // Minimum length comes from auth.min_password_length
+	Name          string   `json:"name,omitempty"`
+	Age           *int     `json:"age,omitempty" validate:"omitempty,min=1,max=120"`
+	Weight        *float64 `json:"weight,omitempty" validate:"omitempty,min=1"`
+	Height        *float64 `json:"height,omitempty" validate:"omitempty,min=1"`
+	Gender        string   `json:"gender,omitempty" validate:"omitempty,oneof=male female other"`
+	Goal          string   `json:"goal,omitempty" validate:"omitempty,oneof=weight_loss muscle_gain maintenance"`
+	ActivityLevel string   `json:"activityLevel,omitempty" validate:"omitempty,oneof=sedentary light moderate active very_active"`
+}
+
+// Normalize lowercases and trims the email and enum fields so "Male " is accepted as "male"
+// It must run before struct validation
+func (r *RegisterRequest) Normalize() {
+	r.Email = NormalizeEmail(r.Email)
+	normalizeEnum(&r.Gender)
+	normalizeEnum(&r.Goal)
+	normalizeEnum(&r.ActivityLevel)
 }
 
 // LoginRequest represents a user login request
@@ -13,6 +32,17 @@ type LoginRequest struct {
 	Password string `json:"password" validate:"required"`
 }
 
+// Normalize lowercases and trims the email so it matches the stored form
+// It must run before struct validation
+func (r *LoginRequest) Normalize() {
+	r.Email = NormalizeEmail(r.Email)
+}
+
+// NormalizeEmail returns the form emails are stored and looked up in, so "User@X.com " and "user@x.com" are one account
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
 // RefreshTokenRequest represents a token refresh request
 type RefreshTokenRequest struct {
 	RefreshToken string `json:"refreshToken" validate:"required"`
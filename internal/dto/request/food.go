@@ -3,6 +3,7 @@ package request
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 )
 
 // CreateFoodRequest represents a request to create a food item
@@ -10,13 +11,23 @@ type CreateFoodRequest struct {
 	Name         MultiLanguage         `json:"name" validate:"required"`
 	SearchTerms  []string              `json:"searchTerms"`
 	Description  MultiLanguage         `json:"description,omitempty"`
-	Category     string                `json:"category" validate:"required,oneof=protein vegetable fruit dairy grain"`
+	Category     string                `json:"category" validate:"required,food_category"`
 	Macros       MacroNutrientsRequest `json:"macros" validate:"required"`
 	Micros       MicroNutrientsRequest `json:"micros,omitempty"`
 	ServingSizes []ServingSizeRequest  `json:"servingSizes" validate:"required,min=1"`
-	Calories     float64               `json:"calories" validate:"required,min=0"`
+	Calories     *float64              `json:"calories,omitempty" validate:"omitempty,finite,min=0"` // Estimated from the macros when omitted
 	Visibility   string                `json:"visibility" validate:"required,oneof=public private"`
 	ImageURL     string                `json:"imageUrl,omitempty"`
+	Allergens    []string              `json:"allergens,omitempty" validate:"omitempty,max=20,dive,min=1,max=50"` // e.g. "peanut", "gluten"
+	DietTags     []string              `json:"dietTags,omitempty" validate:"omitempty,max=20,dive,min=1,max=50"`  // e.g. "vegan", "keto"
+}
+
+// CaloriesValue returns the requested calories, or 0 when they were omitted
+func (r *CreateFoodRequest) CaloriesValue() float64 {
+	if r.Calories == nil {
+		return 0
+	}
+	return *r.Calories
 }
 
 // UpdateFoodRequest represents a request to update a food item
@@ -24,47 +35,103 @@ type UpdateFoodRequest struct {
 	Name         MultiLanguage          `json:"name,omitempty"`
 	SearchTerms  []string               `json:"searchTerms,omitempty"`
 	Description  MultiLanguage          `json:"description,omitempty"`
-	Category     string                 `json:"category,omitempty"`
+	Category     string                 `json:"category,omitempty" validate:"omitempty,food_category"`
 	Macros       *MacroNutrientsRequest `json:"macros,omitempty"`
 	Micros       *MicroNutrientsRequest `json:"micros,omitempty"`
 	ServingSizes []ServingSizeRequest   `json:"servingSizes,omitempty"`
-	Calories     *float64               `json:"calories,omitempty"`
-	Visibility   string                 `json:"visibility,omitempty"`
+	Calories     *float64               `json:"calories,omitempty" validate:"omitempty,finite"`
+	Visibility   string                 `json:"visibility,omitempty" validate:"omitempty,oneof=public private"`
 	ImageURL     string                 `json:"imageUrl,omitempty"`
+	Allergens    []string               `json:"allergens,omitempty" validate:"omitempty,max=20,dive,min=1,max=50"`
+	DietTags     []string               `json:"dietTags,omitempty" validate:"omitempty,max=20,dive,min=1,max=50"`
+}
+
+// NormalizeFoodLabels returns allergens or diet tags in the form they are stored and filtered in:
+// trimmed, lowercased and without empty or duplicate entries
+func NormalizeFoodLabels(labels []string) []string {
+	var normalized []string
+	seen := make(map[string]bool, len(labels))
+	for _, label := range labels {
+		label = strings.ToLower(strings.TrimSpace(label))
+		if label == "" || seen[label] {
+			continue
+		}
+		seen[label] = true
+		normalized = append(normalized, label)
+	}
+	return normalized
+}
+
+// CloneFoodRequest represents a request to copy a food into the user's own catalog
+// The body is optional; without a suffix the copy keeps the source's names
+type CloneFoodRequest struct {
+	NameSuffix string `json:"nameSuffix,omitempty" validate:"max=50"` // Appended to every name translation, e.g. " (copy)"
+}
+
+// MergeFoodsRequest represents an admin request to merge a duplicate food into another
+type MergeFoodsRequest struct {
+	KeepID  string `json:"keepId" validate:"required"`  // Food that remains
+	MergeID string `json:"mergeId" validate:"required"` // Duplicate whose references move to KeepID before it is deleted
+}
+
+// BulkSetFoodVisibilityRequest sets the visibility of every food of the user matching the filter
+// At least one criterion is required; tags match foods having any of them among their search terms
+type BulkSetFoodVisibilityRequest struct {
+	Category   string   `json:"category,omitempty" validate:"omitempty,food_category"`
+	Tags       []string `json:"tags,omitempty"`
+	Visibility string   `json:"visibility" validate:"required,oneof=public private"`
 }
 
 // SearchFoodRequest represents a request to search food items
+// Categories and tags match any of the given values, diet tags must all be present and excluded allergens must all be absent
+// The nutrient bounds are per 100g and inclusive
 type SearchFoodRequest struct {
-	Query  string `form:"query" validate:"required"`
-	Limit  int    `form:"limit,default=20"`
-	Offset int    `form:"offset,default=0"`
+	Query            string   `form:"query" validate:"required,min=2,max=100"`
+	Categories       []string `form:"category" validate:"omitempty,max=10,dive,food_category"`
+	Tags             []string `form:"tag" validate:"omitempty,max=10,dive,min=1,max=50"`
+	ExcludeAllergens []string `form:"excludeAllergen" validate:"omitempty,max=10,dive,min=1,max=50"` // Drop foods containing any of these allergens
+	DietTags         []string `form:"diet" validate:"omitempty,max=10,dive,min=1,max=50"`            // Only foods having all of these diet tags
+	Source           string   `form:"source" validate:"omitempty,oneof=user imported"`               // Only return foods from this source
+	ImportedFirst    bool     `form:"importedFirst"`                                                 // Sort imported foods before user-submitted ones
+	MinCalories      *float64 `form:"minCalories" validate:"omitempty,finite,min=0"`
+	MaxCalories      *float64 `form:"maxCalories" validate:"omitempty,finite,min=0"`
+	MinProtein       *float64 `form:"minProtein" validate:"omitempty,finite,min=0"`
+	MaxProtein       *float64 `form:"maxProtein" validate:"omitempty,finite,min=0"`
+	MinCarbohydrates *float64 `form:"minCarbohydrates" validate:"omitempty,finite,min=0"`
+	MaxCarbohydrates *float64 `form:"maxCarbohydrates" validate:"omitempty,finite,min=0"`
+	MinFat           *float64 `form:"minFat" validate:"omitempty,finite,min=0"`
+	MaxFat           *float64 `form:"maxFat" validate:"omitempty,finite,min=0"`
+	MinFiber         *float64 `form:"minFiber" validate:"omitempty,finite,min=0"`
+	MaxFiber         *float64 `form:"maxFiber" validate:"omitempty,finite,min=0"`
+	Limit            int      `form:"limit"` // Resolved by the handler against the food_search page sizes
+	Offset           int      `form:"offset"`
 }
 
 // MacroNutrientsRequest represents macronutrient values in requests
 type MacroNutrientsRequest struct {
-	Protein       float64 `json:"protein" validate:"min=0"`
-	Carbohydrates float64 `json:"carbohydrates" validate:"min=0"`
-	Fat           float64 `json:"fat" validate:"min=0"`
-	Fiber         float64 `json:"fiber" validate:"min=0"`
-	Sugar         float64 `json:"sugar,omitempty" validate:"min=0"`
+	Protein       float64 `json:"protein" validate:"finite,min=0"`
+	Carbohydrates float64 `json:"carbohydrates" validate:"finite,min=0"`
+	Fat           float64 `json:"fat" validate:"finite,min=0"`
+	Fiber         float64 `json:"fiber" validate:"finite,min=0"`
+	Sugar         float64 `json:"sugar,omitempty" validate:"finite,min=0"`
 }
 
 // MicroNutrientsRequest represents micronutrient values in requests
 type MicroNutrientsRequest struct {
-	VitaminA  float64 `json:"vitaminA,omitempty" validate:"min=0"`
-	VitaminC  float64 `json:"vitaminC,omitempty" validate:"min=0"`
-	Calcium   float64 `json:"calcium,omitempty" validate:"min=0"`
-	Iron      float64 `json:"iron,omitempty" validate:"min=0"`
-	Sodium    float64 `json:"sodium,omitempty" validate:"min=0"`
-	Potassium float64 `json:"potassium,omitempty" validate:"min=0"`
+	VitaminA  float64 `json:"vitaminA,omitempty" validate:"finite,min=0"`
+	VitaminC  float64 `json:"vitaminC,omitempty" validate:"finite,min=0"`
+	Calcium   float64 `json:"calcium,omitempty" validate:"finite,min=0"`
+	Iron      float64 `json:"iron,omitempty" validate:"finite,min=0"`
+	Sodium    float64 `json:"sodium,omitempty" validate:"finite,min=0"`
+	Potassium float64 `json:"potassium,omitempty" validate:"finite,min=0"`
 }
 
 // ServingSizeRequest represents a serving size in requests
 type ServingSizeRequest struct {
 	Unit           string  `json:"unit" validate:"required"`
-	Amount         float64 `json:"amount" validate:"required,min=0"`
+	Amount         float64 `json:"amount" validate:"required,finite,min=0"`
 	Description    string  `json:"description,omitempty"`
-	GramEquivalent float64 `json:"gramEquivalent" validate:"required,min=0"`
+	GramEquivalent float64 `json:"gramEquivalent" validate:"required,finite,min=0"`
 }
 
 var (
@@ -74,6 +141,11 @@ var (
 	}
 )
 
+// IsSupportedLanguage reports whether multi-language values may be written in lang
+func IsSupportedLanguage(lang string) bool {
+	return supportedLanguages[lang]
+}
+
 type MultiLanguage map[string]string
 
 func (m MultiLanguage) Get(lang string) string {
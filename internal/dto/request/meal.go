@@ -4,7 +4,7 @@ package request
 type CreateMealTemplateRequest struct {
 	Name        string                     `json:"name" validate:"required"`
 	Description string                     `json:"description,omitempty"`
-	MealType    string                     `json:"mealType" validate:"required,oneof=breakfast lunch dinner snack"`
+	MealType    string                     `json:"mealType" validate:"required"` // Checked against the registered meal types by MealValidator
 	FoodItems   []MealTemplateFoodItemRequest `json:"foodItems" validate:"required,min=1"`
 	Tags        []string                   `json:"tags,omitempty"`
 	IsPublic    bool                       `json:"isPublic"`
@@ -32,3 +32,10 @@ type AddFoodToTemplateRequest struct {
 	FoodItems []MealTemplateFoodItemRequest `json:"foodItems" validate:"required,min=1"`
 }
 
+
+// BulkDeleteTemplatesRequest selects the user's templates to delete
+// At least one criterion is required; tags match templates carrying any of them
+type BulkDeleteTemplatesRequest struct {
+	MealType string   `json:"mealType,omitempty"`
+	Tags     []string `json:"tags,omitempty"`
+}
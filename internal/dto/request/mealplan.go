@@ -10,18 +10,30 @@ type CreateMealPlanRequest struct {
 	EndDate       time.Time `json:"endDate" validate:"required"`
 	PlanType      string    `json:"planType" validate:"required,oneof=weekly monthly"`
 	Goal          string    `json:"goal" validate:"required,oneof=weight_loss muscle_gain maintenance"`
-	TargetCalories float64  `json:"targetCalories" validate:"required,min=0"`
+	// Required unless UseProfileTargets is set, which takes omitted targets from the user's preferences
+	TargetCalories    float64                `json:"targetCalories,omitempty" validate:"omitempty,finite,min=0"`
+	TargetMacros      *MacroNutrientsRequest `json:"targetMacros,omitempty"`
+	UseProfileTargets bool                   `json:"useProfileTargets,omitempty"`
 }
 
-// UpdateMealPlanRequest represents a request to update a meal plan
+// UpdateMealPlanRequest represents a partial update of a meal plan
+// Only provided fields are changed
 type UpdateMealPlanRequest struct {
-	Name          string    `json:"name,omitempty"`
-	Description   string    `json:"description,omitempty"`
-	StartDate     *time.Time `json:"startDate,omitempty"`
-	EndDate       *time.Time `json:"endDate,omitempty"`
-	PlanType      string    `json:"planType,omitempty"`
-	Goal          string    `json:"goal,omitempty"`
-	TargetCalories *float64 `json:"targetCalories,omitempty"`
-	Status        string    `json:"status,omitempty"`
+	Name           *string  `json:"name,omitempty" validate:"omitempty,min=1"`
+	Description    *string  `json:"description,omitempty"`
+	Goal           *string  `json:"goal,omitempty" validate:"omitempty,oneof=weight_loss muscle_gain maintenance"`
+	TargetCalories *float64 `json:"targetCalories,omitempty" validate:"omitempty,finite,min=0"`
+}
+
+// UpdateMealPlanTargetsRequest represents a request to change the daily targets of a meal plan
+// AutoGenerate rescales the portions of meals not yet completed to the new calorie target
+type UpdateMealPlanTargetsRequest struct {
+	TargetCalories float64                `json:"targetCalories" validate:"required,finite,min=0"`
+	TargetMacros   *MacroNutrientsRequest `json:"targetMacros,omitempty"`
+	AutoGenerate   bool                   `json:"autoGenerate,omitempty"`
 }
 
+// UpdateNotesRequest represents a request to update notes on a day or meal in a meal plan
+type UpdateNotesRequest struct {
+	Notes string `json:"notes" validate:"max=500"`
+}
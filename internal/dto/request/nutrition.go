@@ -0,0 +1,19 @@
+package request
+
+// CalculateNutritionRequest represents a request to calculate nutrients for an ad-hoc food list
+// Items are checked one by one by the service, so a bad entry does not fail the whole batch
+type CalculateNutritionRequest struct {
+	Items []NutritionItemRequest `json:"items" validate:"required,min=1,max=100"`
+}
+
+// NutritionItemRequest represents a food serving in a nutrition calculation request
+type NutritionItemRequest struct {
+	FoodID      string  `json:"foodId"`
+	ServingUnit string  `json:"servingUnit"`
+	Amount      float64 `json:"amount"`
+}
+
+// EstimateCaloriesRequest represents a request to compute calories from macros
+type EstimateCaloriesRequest struct {
+	Macros MacroNutrientsRequest `json:"macros" validate:"required"`
+}
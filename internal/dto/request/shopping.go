@@ -0,0 +1,33 @@
+package request
+
+// AddShoppingItemRequest represents a request to add an item to a shopping list
+// FoodItemID is optional so free-text items like "paper towels" can be added
+type AddShoppingItemRequest struct {
+	FoodItemID string   `json:"foodItemId,omitempty"`
+	Name       string   `json:"name" validate:"required,min=1,max=200"`
+	Amount     float64  `json:"amount,omitempty" validate:"omitempty,min=0"`
+	Unit       string   `json:"unit,omitempty" validate:"omitempty,max=50"`
+	Cost       *float64 `json:"cost,omitempty" validate:"omitempty,min=0"`
+}
+
+// MergeShoppingListsRequest represents a request to combine several shopping lists into a new one
+type MergeShoppingListsRequest struct {
+	ListIDs []string `json:"listIds" validate:"required,min=2,max=20,dive,required"`
+	Name    string   `json:"name,omitempty" validate:"omitempty,max=200"`
+}
+
+// ToggleShoppingItemRequest represents a request to set the checked state of a shopping list item
+type ToggleShoppingItemRequest struct {
+	Checked *bool `json:"checked" validate:"required"`
+}
+
+// ToggleShoppingItemsRequest represents a request to set the checked state of several shopping list items at once
+type ToggleShoppingItemsRequest struct {
+	Items []ShoppingItemToggle `json:"items" validate:"required,min=1,max=200,dive"`
+}
+
+// ShoppingItemToggle is the desired checked state of one shopping list item
+type ShoppingItemToggle struct {
+	ItemID  string `json:"itemId" validate:"required"`
+	Checked *bool  `json:"checked" validate:"required"`
+}
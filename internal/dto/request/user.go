@@ -1,24 +1,90 @@
 package request
 
+import "strings"
+
 // UpdateProfileRequest represents a request to update user profile
+// Its fields are the whitelist of what a profile update may change: other JSON keys, such as "email"
+// or "role", are dropped when binding, and the service copies these fields onto the profile only
 type UpdateProfileRequest struct {
-	Name   *string  `json:"name,omitempty" validate:"omitempty,min=1"`
-	Age    *int     `json:"age,omitempty" validate:"omitempty,min=1,max=120"`
-	Weight *float64 `json:"weight,omitempty" validate:"omitempty,min=1"`
-	Height *float64 `json:"height,omitempty" validate:"omitempty,min=1"`
+	Name *string `json:"name,omitempty" validate:"omitempty,min=1"`
+	// Age, weight and height are checked against the configured ranges by the service
+	Age    *int     `json:"age,omitempty"`
+	Weight *float64 `json:"weight,omitempty"`
+	Height *float64 `json:"height,omitempty"`
 	Gender *string  `json:"gender,omitempty" validate:"omitempty,oneof=male female other"`
 	Goal   *string  `json:"goal,omitempty" validate:"omitempty,oneof=weight_loss muscle_gain maintenance"`
+	// Activity level used for the TDEE multiplier
+	ActivityLevel *string `json:"activityLevel,omitempty" validate:"omitempty,oneof=sedentary light moderate active very_active"`
+}
+
+// Normalize lowercases and trims the enum fields so "Male " is accepted as "male"
+// It must run before struct validation
+func (r *UpdateProfileRequest) Normalize() {
+	normalizeEnum(r.Gender)
+	normalizeEnum(r.Goal)
+	normalizeEnum(r.ActivityLevel)
+}
+
+// normalizeEnum lowercases and trims an optional enum value in place
+func normalizeEnum(value *string) {
+	if value != nil {
+		*value = strings.ToLower(strings.TrimSpace(*value))
+	}
+}
+
+// CalculateTargetsRequest represents a profile to preview calorie and macro targets for
+// Age, weight and height are checked against the configured ranges by the service, like UpdateProfileRequest,
+// so a missing one is reported as out of range with the others
+type CalculateTargetsRequest struct {
+	Weight float64 `json:"weight"` // kg
+	Height float64 `json:"height"` // cm
+	Age    int     `json:"age"`
+	Gender string  `json:"gender" validate:"required,oneof=male female other"`
+	Goal   string  `json:"goal" validate:"required,oneof=weight_loss muscle_gain maintenance"`
+	// Activity level used for the TDEE multiplier, empty means sedentary
+	ActivityLevel string `json:"activityLevel,omitempty" validate:"omitempty,oneof=sedentary light moderate active very_active"`
+}
+
+// Normalize lowercases and trims the enum fields, like UpdateProfileRequest.Normalize
+func (r *CalculateTargetsRequest) Normalize() {
+	normalizeEnum(&r.Gender)
+	normalizeEnum(&r.Goal)
+	normalizeEnum(&r.ActivityLevel)
 }
 
 // UpdatePreferencesRequest represents a request to update user preferences
 type UpdatePreferencesRequest struct {
 	Language      *string                `json:"language,omitempty" validate:"omitempty,oneof=en vi"`
-	CalorieTarget *float64               `json:"calorieTarget,omitempty" validate:"omitempty,min=0"`
+	CalorieTarget *float64               `json:"calorieTarget,omitempty" validate:"omitempty,finite,min=0"`
 	MacroTargets  *MacroNutrientsRequest `json:"macroTargets,omitempty"`
+	WeekStart     *string                `json:"weekStart,omitempty" validate:"omitempty,oneof=sunday monday"`
+	// Replaces the user's reference daily values for %DV, zero fields use the configured value
+	DailyValues *DailyValuesRequest `json:"dailyValues,omitempty"`
+}
+
+// DailyValuesRequest represents reference daily values in requests
+type DailyValuesRequest struct {
+	Calories      float64 `json:"calories,omitempty" validate:"finite,min=0"`
+	Protein       float64 `json:"protein,omitempty" validate:"finite,min=0"`
+	Carbohydrates float64 `json:"carbohydrates,omitempty" validate:"finite,min=0"`
+	Fat           float64 `json:"fat,omitempty" validate:"finite,min=0"`
+	Fiber         float64 `json:"fiber,omitempty" validate:"finite,min=0"`
+	Sugar         float64 `json:"sugar,omitempty" validate:"finite,min=0"`
+	VitaminA      float64 `json:"vitaminA,omitempty" validate:"finite,min=0"`
+	VitaminC      float64 `json:"vitaminC,omitempty" validate:"finite,min=0"`
+	Calcium       float64 `json:"calcium,omitempty" validate:"finite,min=0"`
+	Iron          float64 `json:"iron,omitempty" validate:"finite,min=0"`
+	Sodium        float64 `json:"sodium,omitempty" validate:"finite,min=0"`
+	Potassium     float64 `json:"potassium,omitempty" validate:"finite,min=0"`
 }
 
 // ChangePasswordRequest represents a request to change password
 type ChangePasswordRequest struct {
 	CurrentPassword string `json:"currentPassword" validate:"required"`
-	NewPassword     string `json:"newPassword" validate:"required,min=6"`
+	NewPassword     string `json:"newPassword" validate:"required,password"`
+}
+
+// DeleteAccountRequest represents a request to delete the current user's account
+type DeleteAccountRequest struct {
+	Password string `json:"password" validate:"required"`
 }
@@ -0,0 +1,19 @@
+package response
+
+import "time"
+
+// DashboardResponse summarizes a user's data for the dashboard
+type DashboardResponse struct {
+	FoodCount       int64                  `json:"foodCount"`       // Foods the user created
+	TemplateCount   int64                  `json:"templateCount"`   // Meal templates the user owns
+	ActivePlanCount int64                  `json:"activePlanCount"` // Meal plans with status "active"
+	Today           DashboardTodayResponse `json:"today"`
+}
+
+// DashboardTodayResponse reports today's calorie budget
+type DashboardTodayResponse struct {
+	Date              time.Time `json:"date"`
+	TargetCalories    float64   `json:"targetCalories"`
+	ConsumedCalories  float64   `json:"consumedCalories"`  // Completed meals only
+	RemainingCalories float64   `json:"remainingCalories"` // Never below zero
+}
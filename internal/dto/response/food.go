@@ -17,8 +17,12 @@ type FoodItemResponse struct {
 	Visibility   string                 `json:"visibility"`
 	Source       string                 `json:"source"`
 	ImageURL     string                 `json:"imageUrl,omitempty"`
+	Allergens    []string               `json:"allergens,omitempty"`
+	DietTags     []string               `json:"dietTags,omitempty"`
 	CreatedAt    time.Time              `json:"createdAt"`
 	UpdatedAt    time.Time              `json:"updatedAt"`
+	// Suspicious values that did not prevent saving the food; only set when it was just updated
+	Warnings []FoodValidationIssueResponse `json:"warnings,omitempty"`
 }
 
 // MacroNutrientsResponse represents macronutrient values in API responses
@@ -47,3 +51,71 @@ type ServingSizeResponse struct {
 	Description    string  `json:"description,omitempty"`
 	GramEquivalent float64 `json:"gramEquivalent"`
 }
+
+// BulkSetFoodVisibilityResponse reports how many foods a bulk visibility change updated
+type BulkSetFoodVisibilityResponse struct {
+	Updated int64 `json:"updated"`
+}
+
+// FoodValidationResponse is the outcome of validating a food request without creating it
+// A request failing validation is still answered with 200; valid and errors tell the outcome
+type FoodValidationResponse struct {
+	Valid    bool                          `json:"valid"`
+	Errors   []FoodValidationIssueResponse `json:"errors"`
+	Warnings []FoodValidationIssueResponse `json:"warnings"`
+	Calories FoodCaloriesCheckResponse     `json:"calories"`
+}
+
+// FoodValidationIssueResponse is a failed rule or a warning, with a code clients can switch on
+type FoodValidationIssueResponse struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// FoodCaloriesCheckResponse compares the stated calories with the ones implied by the macros
+type FoodCaloriesCheckResponse struct {
+	Stated    float64 `json:"stated"`
+	Expected  float64 `json:"expected"`
+	Delta     float64 `json:"delta"`     // Stated minus expected
+	Tolerance float64 `json:"tolerance"` // Largest delta accepted either way
+}
+
+// FoodMergeResponse summarizes a merge of two duplicate foods
+type FoodMergeResponse struct {
+	KeptFoodID       string `json:"keptFoodId"`
+	MergedFoodID     string `json:"mergedFoodId"`
+	TemplatesUpdated int64  `json:"templatesUpdated"`
+	MealPlansUpdated int64  `json:"mealPlansUpdated"`
+}
+
+// CalorieRecalculationResponse summarizes a recalculation of stored food calories from macros
+type CalorieRecalculationResponse struct {
+	DryRun        bool                        `json:"dryRun"`
+	Scanned       int                         `json:"scanned"`
+	Changed       int                         `json:"changed"`
+	TotalDelta    float64                     `json:"totalDelta"`    // Sum of new minus old calories per 100g
+	MaxAbsDelta   float64                     `json:"maxAbsDelta"`   // Largest change of a single food
+	Changes       []FoodCalorieChangeResponse `json:"changes"`       // At most the first 100 changes
+	ChangesCapped bool                        `json:"changesCapped"` // More foods changed than are listed
+}
+
+// FoodCalorieChangeResponse is the stored calories of one food before and after recalculation
+type FoodCalorieChangeResponse struct {
+	FoodID      string  `json:"foodId"`
+	OldCalories float64 `json:"oldCalories"`
+	NewCalories float64 `json:"newCalories"`
+}
+
+// FoodMacroBreakdownResponse is the percentage of a food's calories per 100g coming from each macro
+// Percentages are relative to the stored calories; caloriesMismatch is set when those disagree with the macros
+type FoodMacroBreakdownResponse struct {
+	FoodID               string  `json:"foodId"`
+	Calories             float64 `json:"calories"`
+	MacroCalories        float64 `json:"macroCalories"`
+	ProteinPercent       float64 `json:"proteinPercent"`
+	CarbohydratesPercent float64 `json:"carbohydratesPercent"`
+	FatPercent           float64 `json:"fatPercent"`
+	FiberCalories        float64 `json:"fiberCalories"`
+	FiberPercent         float64 `json:"fiberPercent"`
+	CaloriesMismatch     bool    `json:"caloriesMismatch"`
+}
@@ -17,6 +17,8 @@ type MealTemplateResponse struct {
 	IsPublic      bool                           `json:"isPublic"`
 	CreatedAt     time.Time                      `json:"createdAt"`
 	UpdatedAt     time.Time                      `json:"updatedAt"`
+	Warnings      []string                       `json:"warnings,omitempty"` // Plausibility warnings on create, deleted food warnings on get
+	Units         string                         `json:"units,omitempty"`    // "imperial" when gram amounts were converted to ounces on get
 }
 
 // MealTemplateFoodItemResponse represents a food item in a meal template response
@@ -28,5 +30,20 @@ type MealTemplateFoodItemResponse struct {
 	Calories    float64                `json:"calories"`
 	Macros      MacroNutrientsResponse `json:"macros"`
 	Micros      MicroNutrientsResponse `json:"micros,omitempty"`
+	Display     string                 `json:"display,omitempty"`     // Amount in the food's preferred unit, e.g. "≈1.3 pieces"
+	Food        *FoodItemResponse      `json:"food,omitempty"`        // Current food details, only with expand=foods
+	FoodDeleted bool                   `json:"foodDeleted,omitempty"` // The food no longer exists, set on get
+	FoodPrivate bool                   `json:"foodPrivate,omitempty"` // The food exists but is private to another user, set on get
 }
 
+
+// BulkDeleteTemplatesResponse reports how many templates a bulk delete removed
+type BulkDeleteTemplatesResponse struct {
+	Deleted int64 `json:"deleted"`
+}
+
+// TagCountResponse is a template tag and how many of the user's templates carry it
+type TagCountResponse struct {
+	Tag   string `json:"tag"`
+	Count int64  `json:"count"`
+}
@@ -28,6 +28,7 @@ type DailyMealResponse struct {
 	Meals         []MealResponse      `json:"meals"`
 	TotalCalories float64              `json:"totalCalories"`
 	TotalMacros   MacroNutrientsResponse `json:"totalMacros"`
+	CalorieDifference float64          `json:"calorieDifference"` // TotalCalories minus the plan's daily target
 	Notes         string               `json:"notes,omitempty"`
 	IsCompleted   bool                `json:"isCompleted"`
 }
@@ -56,3 +57,22 @@ type MealFoodItemResponse struct {
 	Macros       MacroNutrientsResponse  `json:"macros"`
 }
 
+
+// RegeneratedMealPlanResponse is a meal plan after a day was regenerated, with the slots no template could fill
+type RegeneratedMealPlanResponse struct {
+	MealPlanResponse
+	UnfilledSlots []UnfilledSlotResponse `json:"unfilledSlots"`
+}
+
+// UnfilledSlotResponse is a meal slot regeneration left as it was
+type UnfilledSlotResponse struct {
+	Date     time.Time `json:"date"`
+	MealType string    `json:"mealType"`
+	Reason   string    `json:"reason"` // no_templates: the user has no template of this meal type
+}
+
+// MealSwapResponse is a template that could replace a meal in a plan
+type MealSwapResponse struct {
+	Template     MealTemplateResponse `json:"template"`
+	CalorieDelta float64              `json:"calorieDelta"` // Template calories minus the meal's
+}
@@ -0,0 +1,7 @@
+package response
+
+// ServingUnitResponse represents a supported serving unit in API responses
+type ServingUnitResponse struct {
+	Name string `json:"name"`
+	Kind string `json:"kind"` // mass, volume or count
+}
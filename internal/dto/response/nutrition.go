@@ -0,0 +1,28 @@
+package response
+
+// NutritionCalculationResponse represents calculated nutrients for an ad-hoc food list
+// Totals only include the items without an error
+type NutritionCalculationResponse struct {
+	Items         []NutritionItemResponse `json:"items"`
+	TotalCalories float64                 `json:"totalCalories"`
+	TotalMacros   MacroNutrientsResponse  `json:"totalMacros"`
+	TotalMicros   MicroNutrientsResponse  `json:"totalMicros"`
+}
+
+// NutritionItemResponse represents the calculated nutrients of one requested food serving
+// Error is set instead of the nutrient values when the entry could not be calculated
+type NutritionItemResponse struct {
+	FoodID      string                  `json:"foodId"`
+	FoodName    string                  `json:"foodName,omitempty"`
+	ServingUnit string                  `json:"servingUnit"`
+	Amount      float64                 `json:"amount"`
+	Calories    float64                 `json:"calories"`
+	Macros      *MacroNutrientsResponse `json:"macros,omitempty"`
+	Micros      *MicroNutrientsResponse `json:"micros,omitempty"`
+	Error       string                  `json:"error,omitempty"`
+}
+
+// CalorieEstimateResponse represents the calories computed from a set of macros
+type CalorieEstimateResponse struct {
+	Calories float64 `json:"calories"`
+}
@@ -0,0 +1,36 @@
+package response
+
+import (
+	"fmt"
+	"math"
+	"sync/atomic"
+)
+
+// DefaultCaloriePrecision is the number of decimals calories are rounded to when not configured
+const DefaultCaloriePrecision = 1
+
+// MaxCaloriePrecision is the largest supported number of decimals
+const MaxCaloriePrecision = 4
+
+// caloriePrecision is the number of decimals calories are rounded to in responses
+var caloriePrecision atomic.Int32
+
+func init() {
+	caloriePrecision.Store(DefaultCaloriePrecision)
+}
+
+// SetCaloriePrecision sets the number of decimals calories are rounded to in responses
+// Stored values keep full precision; only the response boundary is rounded
+func SetCaloriePrecision(decimals int) error {
+	if decimals < 0 || decimals > MaxCaloriePrecision {
+		return fmt.Errorf("invalid calorie precision %d: must be between 0 and %d", decimals, MaxCaloriePrecision)
+	}
+	caloriePrecision.Store(int32(decimals))
+	return nil
+}
+
+// RoundCalories rounds a calculated calorie value to the configured display precision
+func RoundCalories(calories float64) float64 {
+	scale := math.Pow(10, float64(caloriePrecision.Load()))
+	return math.Round(calories*scale) / scale
+}
@@ -0,0 +1,37 @@
+package response
+
+import "testing"
+
+func TestRoundCalories_UsesConfiguredPrecision(t *testing.T) {
+	defer SetCaloriePrecision(DefaultCaloriePrecision)
+
+	if err := SetCaloriePrecision(1); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if got := RoundCalories(156.4000001); got != 156.4 {
+		t.Errorf("Expected 156.4, got: %v", got)
+	}
+	if got := RoundCalories(156.39999999); got != 156.4 {
+		t.Errorf("Expected 156.4, got: %v", got)
+	}
+
+	if err := SetCaloriePrecision(0); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if got := RoundCalories(156.5); got != 157 {
+		t.Errorf("Expected 157, got: %v", got)
+	}
+}
+
+func TestSetCaloriePrecision_RejectsOutOfRange(t *testing.T) {
+	defer SetCaloriePrecision(DefaultCaloriePrecision)
+
+	for _, decimals := range []int{-1, MaxCaloriePrecision + 1} {
+		if err := SetCaloriePrecision(decimals); err == nil {
+			t.Errorf("Expected error for precision %d, got: nil", decimals)
+		}
+	}
+	if got := RoundCalories(1.25); got != 1.3 {
+		t.Errorf("Expected the previous precision to be kept, got: %v", got)
+	}
+}
@@ -0,0 +1,17 @@
+package response
+
+import "time"
+
+// PrintableMealPlanResponse represents a meal plan laid out for printing, one page per week
+type PrintableMealPlanResponse struct {
+	Plan  MealPlanResponse        `json:"plan"`
+	Weeks []PrintableWeekResponse `json:"weeks"`
+}
+
+// PrintableWeekResponse represents one printed week of a meal plan
+type PrintableWeekResponse struct {
+	Number    int                 `json:"number"`
+	StartDate time.Time           `json:"startDate"`
+	EndDate   time.Time           `json:"endDate"`
+	Days      []DailyMealResponse `json:"days"`
+}
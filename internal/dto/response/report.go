@@ -0,0 +1,155 @@
+package response
+
+import "time"
+
+// DailyReportResponse represents one day of a report
+type DailyReportResponse struct {
+	Date             time.Time              `json:"date"`
+	DayOfWeek        string                 `json:"dayOfWeek"`
+	PlannedCalories  float64                `json:"plannedCalories"`
+	ConsumedCalories float64                `json:"consumedCalories"` // From completed meals
+	ConsumedMacros   MacroNutrientsResponse `json:"consumedMacros"`
+	TotalMeals       int                    `json:"totalMeals"`
+	CompletedMeals   int                    `json:"completedMeals"`
+	// Share of the consumed macro calories from protein, carbohydrates and fat
+	MacroDistribution MacroDistributionResponse `json:"macroDistribution"`
+}
+
+// WeeklyReportResponse represents a weekly nutrition report
+type WeeklyReportResponse struct {
+	WeekStart            time.Time              `json:"weekStart"`
+	WeekEnd              time.Time              `json:"weekEnd"` // Inclusive
+	StartsOn             string                 `json:"startsOn"`
+	TargetCalories       float64                `json:"targetCalories"` // Daily target
+	Days                 []DailyReportResponse  `json:"days"`
+	PlannedCalories      float64                `json:"plannedCalories"`
+	ConsumedCalories     float64                `json:"consumedCalories"`
+	ConsumedMacros       MacroNutrientsResponse `json:"consumedMacros"`
+	AverageDailyCalories float64                `json:"averageDailyCalories"`
+	// Average daily consumption as a percentage of the user's reference daily values
+	AveragePercentDailyValues PercentDailyValuesResponse `json:"averagePercentDailyValues"`
+	// Macro split of everything consumed during the week, so days are weighted by their calories
+	AverageMacroDistribution MacroDistributionResponse `json:"averageMacroDistribution"`
+}
+
+// WeekSummaryResponse represents one week bucket of a monthly report
+type WeekSummaryResponse struct {
+	WeekStart        time.Time `json:"weekStart"`
+	WeekEnd          time.Time `json:"weekEnd"` // Inclusive, clipped to the month
+	Days             int       `json:"days"`
+	PlannedCalories  float64   `json:"plannedCalories"`
+	ConsumedCalories float64   `json:"consumedCalories"`
+}
+
+// MonthlyReportResponse represents a monthly nutrition report
+type MonthlyReportResponse struct {
+	Month                string                 `json:"month"` // "2025-01"
+	StartsOn             string                 `json:"startsOn"`
+	TargetCalories       float64                `json:"targetCalories"` // Daily target
+	Weeks                []WeekSummaryResponse  `json:"weeks"`
+	PlannedCalories      float64                `json:"plannedCalories"`
+	ConsumedCalories     float64                `json:"consumedCalories"`
+	ConsumedMacros       MacroNutrientsResponse `json:"consumedMacros"`
+	AverageDailyCalories float64                `json:"averageDailyCalories"`
+	// Average daily consumption as a percentage of the user's reference daily values
+	AveragePercentDailyValues PercentDailyValuesResponse `json:"averagePercentDailyValues"`
+}
+
+// PercentDailyValuesResponse represents intake as a percentage of the reference daily values (%DV)
+type PercentDailyValuesResponse struct {
+	Calories      float64 `json:"calories"`
+	Protein       float64 `json:"protein"`
+	Carbohydrates float64 `json:"carbohydrates"`
+	Fat           float64 `json:"fat"`
+	Fiber         float64 `json:"fiber"`
+	Sugar         float64 `json:"sugar"`
+}
+
+// MacroDistributionResponse represents the percentage of macro calories from each macro
+// All values are 0 when no calories were consumed
+type MacroDistributionResponse struct {
+	Protein       float64 `json:"protein"`
+	Carbohydrates float64 `json:"carbohydrates"`
+	Fat           float64 `json:"fat"`
+}
+
+// GoalProgressResponse represents progress towards a weight goal over a date range
+type GoalProgressResponse struct {
+	StartDate             time.Time `json:"startDate"`
+	EndDate               time.Time `json:"endDate"`
+	Goal                  string    `json:"goal"`
+	SufficientData        bool      `json:"sufficientData"`
+	Message               string    `json:"message"`
+	WeightEntries         int       `json:"weightEntries"`
+	StartWeight           float64   `json:"startWeight,omitempty"`
+	EndWeight             float64   `json:"endWeight,omitempty"`
+	WeightChange          float64   `json:"weightChange"`          // kg, negative means weight lost
+	MaintenanceCalories   float64   `json:"maintenanceCalories"`   // Estimated TDEE, 0 when the profile is incomplete
+	LoggedDays            int       `json:"loggedDays"`            // Days with at least one completed meal
+	AverageDailyCalories  float64   `json:"averageDailyCalories"`  // Over logged days
+	AverageCalorieBalance float64   `json:"averageCalorieBalance"` // Negative is a deficit, positive a surplus
+	OnTrack               *bool     `json:"onTrack"`               // Nil when it cannot be estimated
+}
+
+// RangeSummaryResponse represents aggregate nutrition over a custom date range
+type RangeSummaryResponse struct {
+	StartDate            time.Time              `json:"startDate"`
+	EndDate              time.Time              `json:"endDate"` // Inclusive
+	Days                 int                    `json:"days"`
+	LoggedDays           int                    `json:"loggedDays"` // Days with at least one completed meal
+	TotalCalories        float64                `json:"totalCalories"`
+	TotalMacros          MacroNutrientsResponse `json:"totalMacros"`
+	AverageDailyCalories float64                `json:"averageDailyCalories"` // Over logged days, 0 when none were logged
+	AverageDailyMacros   MacroNutrientsResponse `json:"averageDailyMacros"`   // Over logged days
+}
+
+// CalorieTrendResponse represents consumed calories over a date range as a series of days or weeks, for charting
+type CalorieTrendResponse struct {
+	StartDate   time.Time                   `json:"startDate"`
+	EndDate     time.Time                   `json:"endDate"`     // Inclusive
+	Granularity string                      `json:"granularity"` // "day" or "week"
+	Points      []CalorieTrendPointResponse `json:"points"`      // In date order, with no gaps
+}
+
+// CalorieTrendPointResponse represents the calories consumed in one day or week of a calorie trend
+type CalorieTrendPointResponse struct {
+	Date     time.Time `json:"date"`     // First day of the period inside the range
+	Days     int       `json:"days"`     // Days of the period inside the range; weeks at the edges may have fewer than 7
+	Calories float64   `json:"calories"` // From completed meals, 0 when none were completed
+}
+
+// PlanAdherenceDayResponse represents one plan day's planned nutrition against the plan's daily targets
+// Variances are planned minus target, so negative values are under target
+type PlanAdherenceDayResponse struct {
+	Date            time.Time              `json:"date"`
+	DayOfWeek       string                 `json:"dayOfWeek"`
+	PlannedCalories float64                `json:"plannedCalories"`
+	PlannedMacros   MacroNutrientsResponse `json:"plannedMacros"`
+	CalorieVariance float64                `json:"calorieVariance"`
+	MacroVariance   MacroNutrientsResponse `json:"macroVariance"`
+}
+
+// PlanAdherenceResponse represents how closely a meal plan's planned meals hit its targets, regardless of completion
+type PlanAdherenceResponse struct {
+	PlanID                 string                     `json:"planId"`
+	StartDate              time.Time                  `json:"startDate"`
+	EndDate                time.Time                  `json:"endDate"`
+	TargetCalories         float64                    `json:"targetCalories"` // Daily target
+	TargetMacros           MacroNutrientsResponse     `json:"targetMacros"`   // Daily target
+	Days                   []PlanAdherenceDayResponse `json:"days"`
+	AverageDailyCalories   float64                    `json:"averageDailyCalories"`
+	AverageDailyMacros     MacroNutrientsResponse     `json:"averageDailyMacros"`
+	AverageCalorieVariance float64                    `json:"averageCalorieVariance"`
+	AverageMacroVariance   MacroNutrientsResponse     `json:"averageMacroVariance"`
+	DaysUnderTarget        int                        `json:"daysUnderTarget"` // Days planned below the calorie target
+	DaysOverTarget         int                        `json:"daysOverTarget"`  // Days planned above the calorie target
+}
+
+// AdherenceStreakResponse represents the runs of consecutive days whose consumed calories landed near the calorie target
+type AdherenceStreakResponse struct {
+	CurrentStreak    int       `json:"currentStreak"`    // Days up to yesterday, or today once today is on target
+	LongestStreak    int       `json:"longestStreak"`    // Longest run since Since, the current one included
+	TargetCalories   float64   `json:"targetCalories"`   // Daily target
+	TolerancePercent float64   `json:"tolerancePercent"` // A day is on target within this percentage of the target
+	Since            time.Time `json:"since"`            // First day looked at
+}
@@ -0,0 +1,33 @@
+package response
+
+import "time"
+
+// ShoppingListResponse represents a shopping list in API responses
+type ShoppingListResponse struct {
+	ID            string                 `json:"id"`
+	UserID        string                 `json:"userId"`
+	MealPlanID    string                 `json:"mealPlanId,omitempty"` // Empty on merged lists
+	Name          string                 `json:"name,omitempty"`
+	SourceListIDs []string               `json:"sourceListIds,omitempty"`
+	Items         []ShoppingItemResponse `json:"items"`
+	ItemCount     int                    `json:"itemCount"`
+	CheckedCount  int                    `json:"checkedCount"`
+	TotalCost     float64                `json:"totalCost,omitempty"`
+	Status        string                 `json:"status"`
+	CreatedAt     time.Time              `json:"createdAt"`
+	UpdatedAt     time.Time              `json:"updatedAt"`
+}
+
+// ShoppingItemResponse represents a shopping list item in API responses
+type ShoppingItemResponse struct {
+	ID          string  `json:"id"`
+	FoodItemID  string  `json:"foodItemId,omitempty"`
+	FoodName    string  `json:"foodName"`
+	TotalAmount float64 `json:"totalAmount"`
+	Unit        string  `json:"unit"`
+	TotalGrams  float64 `json:"totalGrams,omitempty"`
+	Display     string  `json:"display,omitempty"`
+	Cost        float64 `json:"cost,omitempty"`
+	Checked     bool    `json:"checked"`
+	IsManual    bool    `json:"isManual,omitempty"`
+}
@@ -0,0 +1,23 @@
+package response
+
+import "time"
+
+// MealSuggestionResponse represents a food or template suggested to fill the remaining budget
+type MealSuggestionResponse struct {
+	Type          string                 `json:"type"` // "food" or "template"
+	ID            string                 `json:"id"`
+	Name          string                 `json:"name"`
+	ServingUnit   string                 `json:"servingUnit,omitempty"` // Foods only
+	ServingAmount float64                `json:"servingAmount,omitempty"`
+	Calories      float64                `json:"calories"`
+	Macros        MacroNutrientsResponse `json:"macros"`
+	ProteinFill   float64                `json:"proteinFill"` // Percentage of the remaining protein covered
+}
+
+// MealSuggestionsResponse represents the remaining budget for a day and the ranked suggestions
+type MealSuggestionsResponse struct {
+	Date              time.Time                `json:"date"`
+	RemainingCalories float64                  `json:"remainingCalories"`
+	RemainingMacros   MacroNutrientsResponse   `json:"remainingMacros"`
+	Suggestions       []MealSuggestionResponse `json:"suggestions"`
+}
@@ -14,12 +14,13 @@ type UserResponse struct {
 
 // UserProfileResponse represents user profile in API responses
 type UserProfileResponse struct {
-	Name   string  `json:"name"`
-	Age    int     `json:"age"`
-	Weight float64 `json:"weight"`
-	Height float64 `json:"height"`
-	Gender string  `json:"gender"`
-	Goal   string  `json:"goal"`
+	Name          string  `json:"name"`
+	Age           int     `json:"age"`
+	Weight        float64 `json:"weight"`
+	Height        float64 `json:"height"`
+	Gender        string  `json:"gender"`
+	Goal          string  `json:"goal"`
+	ActivityLevel string  `json:"activityLevel,omitempty"`
 }
 
 // UserPreferencesResponse represents user preferences in API responses
@@ -27,4 +28,34 @@ type UserPreferencesResponse struct {
 	Language      string                 `json:"language"`
 	CalorieTarget float64                `json:"calorieTarget"`
 	MacroTargets  MacroNutrientsResponse `json:"macroTargets"`
+	// "g_per_kg" or "g_per_day"; omitted for targets stored before the unit was recorded
+	MacroTargetsUnit string `json:"macroTargetsUnit,omitempty"`
+	WeekStart        string `json:"weekStart"`
+	// The user's own reference daily values, unset fields use the configured value
+	DailyValues DailyValuesResponse `json:"dailyValues"`
+}
+
+// CalorieTargetsResponse represents previewed calorie and macro targets for a profile
+type CalorieTargetsResponse struct {
+	BMR                 float64 `json:"bmr"`
+	MaintenanceCalories float64 `json:"maintenanceCalories"` // TDEE, BMR times the activity factor
+	CalorieTarget       float64 `json:"calorieTarget"`
+	// Grams per kg of body weight, as stored in preferences
+	MacroTargets MacroNutrientsResponse `json:"macroTargets"`
+}
+
+// DailyValuesResponse represents reference daily values in API responses
+type DailyValuesResponse struct {
+	Calories      float64 `json:"calories,omitempty"`
+	Protein       float64 `json:"protein,omitempty"`
+	Carbohydrates float64 `json:"carbohydrates,omitempty"`
+	Fat           float64 `json:"fat,omitempty"`
+	Fiber         float64 `json:"fiber,omitempty"`
+	Sugar         float64 `json:"sugar,omitempty"`
+	VitaminA      float64 `json:"vitaminA,omitempty"`
+	VitaminC      float64 `json:"vitaminC,omitempty"`
+	Calcium       float64 `json:"calcium,omitempty"`
+	Iron          float64 `json:"iron,omitempty"`
+	Sodium        float64 `json:"sodium,omitempty"`
+	Potassium     float64 `json:"potassium,omitempty"`
 }
@@ -7,16 +7,19 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
 
-	"nutrient_be/internal/config"
+	"nutrient_be/internal/pkg/jwtkeys"
 	"nutrient_be/internal/pkg/logger"
 )
 
 const (
-	DefaultUserID = "abc-xyz-123"
+	// DefaultUserID is the user every request runs as under DefaultUserAuthMiddleware
+	// It is a valid ObjectID so services accept it like a real user ID
+	DefaultUserID = "000000000000000000000001"
 )
 
 // AuthMiddleware validates JWT tokens
-func AuthMiddleware(log logger.Logger, cfg config.AuthConfig) gin.HandlerFunc {
+// Signatures are verified with tokenKeys, which only accept tokens using the configured algorithm
+func AuthMiddleware(log logger.Logger, tokenKeys *jwtkeys.Keys) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Get token from Authorization header
 		authHeader := c.GetHeader("Authorization")
@@ -37,15 +40,7 @@ func AuthMiddleware(log logger.Logger, cfg config.AuthConfig) gin.HandlerFunc {
 		tokenString := strings.TrimPrefix(authHeader, "Bearer ")
 
 		// Parse and validate token
-		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-			// Validate signing method
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, jwt.ErrSignatureInvalid
-			}
-
-			return []byte(cfg.JWTSecret), nil
-		})
-
+		token, err := tokenKeys.Parse(tokenString)
 		if err != nil {
 			// Get context from Gin context
 			ctx := GetContext(c)
@@ -86,8 +81,9 @@ func AuthMiddleware(log logger.Logger, cfg config.AuthConfig) gin.HandlerFunc {
 			return
 		}
 
-		// Set user ID in context
+		// Set user ID in context; ContextMiddleware ran before authentication, so the enriched context gets it here
 		c.Set("userID", userID)
+		withContextValue(c, logger.UserIDKey, userID)
 		c.Next()
 	}
 }
@@ -107,10 +103,13 @@ func GetUserIDFromContext(c *gin.Context) (string, bool) {
 	return userIDStr, true
 }
 
+// DefaultUserAuthMiddleware authenticates every request as DefaultUserID without checking a token
+// It is for local development only and is refused in release mode; see auth.allow_default_user
 func DefaultUserAuthMiddleware(log logger.Logger) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		log.Warn(c.Request.Context(), "Default user authentication middleware", logger.String("user_id", DefaultUserID))
 		c.Set("userID", DefaultUserID)
+		withContextValue(c, logger.UserIDKey, DefaultUserID)
 		c.Next()
 	}
 }
@@ -0,0 +1,94 @@
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"nutrient_be/internal/pkg/logger"
+)
+
+// maxCapturedBodyBytes bounds how much of each body is buffered for logging
+const maxCapturedBodyBytes = 64 << 10
+
+// sensitiveFieldPattern matches JSON string fields whose name mentions a password, token or secret
+// The value may be unterminated when the captured body was cut off
+var sensitiveFieldPattern = regexp.MustCompile(`(?i)("[^"]*(?:password|token|secret)[^"]*"\s*:\s*)"(?:[^"\\]|\\.)*(?:"|$)`)
+
+// BodyLoggingMiddleware logs request and response bodies of non-GET requests at debug level
+// Only JSON bodies are logged; sensitive fields are redacted and bodies are truncated to maxLength bytes
+func BodyLoggingMiddleware(log logger.Logger, maxLength int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			c.Next()
+			return
+		}
+
+		var requestBody []byte
+		if c.Request.Body != nil && isJSONContent(c.ContentType()) {
+			body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxCapturedBodyBytes))
+			if err == nil {
+				requestBody = body
+			}
+			// Put back what was read in front of any unread remainder
+			c.Request.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), c.Request.Body))
+		}
+
+		writer := &bodyCaptureWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+
+		c.Next()
+
+		responseBody := ""
+		if isJSONContent(writer.Header().Get("Content-Type")) {
+			responseBody = redactBody(writer.body.Bytes(), maxLength)
+		}
+
+		log.Debug(GetContext(c), "HTTP bodies",
+			logger.String("method", c.Request.Method),
+			logger.String("path", c.Request.URL.Path),
+			logger.Int("status", writer.Status()),
+			logger.String("request_body", redactBody(requestBody, maxLength)),
+			logger.String("response_body", responseBody),
+		)
+	}
+}
+
+// bodyCaptureWriter copies the start of the response body while still writing it to the client
+type bodyCaptureWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+// Write sends the data to the client and keeps a bounded copy
+func (w *bodyCaptureWriter) Write(data []byte) (int, error) {
+	if remaining := maxCapturedBodyBytes - w.body.Len(); remaining > 0 {
+		w.body.Write(data[:min(len(data), remaining)])
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+// WriteString sends the string to the client and keeps a bounded copy
+func (w *bodyCaptureWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// redactBody masks sensitive fields and truncates the body to maxLength bytes
+// Redaction runs before truncation so a cut cannot expose part of a secret
+func redactBody(body []byte, maxLength int) string {
+	redacted := sensitiveFieldPattern.ReplaceAllString(string(body), `${1}"[REDACTED]"`)
+	if maxLength > 0 && len(redacted) > maxLength {
+		return redacted[:maxLength] + "...(truncated)"
+	}
+	return redacted
+}
+
+// isJSONContent reports whether a content type is JSON
+func isJSONContent(contentType string) bool {
+	return strings.Contains(contentType, "application/json")
+}
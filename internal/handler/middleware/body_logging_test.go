@@ -0,0 +1,88 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"nutrient_be/internal/pkg/logger"
+)
+
+// recordingLogger keeps the fields of debug entries and ignores everything else
+type recordingLogger struct {
+	logger.Logger
+	fields map[string]interface{}
+}
+
+func (l *recordingLogger) Debug(ctx context.Context, msg string, fields ...logger.Field) {
+	for _, field := range fields {
+		l.fields[field.Key] = field.Value
+	}
+}
+
+func TestBodyLoggingMiddleware_RedactsSensitiveFields(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	log := &recordingLogger{Logger: logger.NewNoopLogger(), fields: make(map[string]interface{})}
+
+	router := gin.New()
+	router.Use(BodyLoggingMiddleware(log, 1024))
+	router.POST("/login", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"accessToken": "eyJhbGciOi.secret", "email": "jane@example.com"})
+	})
+
+	body := `{"email":"jane@example.com","password":"hunter2","newPassword":"hunter3"}`
+	req := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if !strings.Contains(rec.Body.String(), "eyJhbGciOi.secret") {
+		t.Errorf("Expected client to receive the unredacted response, got: %s", rec.Body.String())
+	}
+
+	requestBody, _ := log.fields["request_body"].(string)
+	if strings.Contains(requestBody, "hunter2") || strings.Contains(requestBody, "hunter3") {
+		t.Errorf("Expected passwords to be redacted, got: %s", requestBody)
+	}
+	if !strings.Contains(requestBody, `"password":"[REDACTED]"`) || !strings.Contains(requestBody, "jane@example.com") {
+		t.Errorf("Expected only sensitive fields to be redacted, got: %s", requestBody)
+	}
+
+	responseBody, _ := log.fields["response_body"].(string)
+	if strings.Contains(responseBody, "eyJhbGciOi") {
+		t.Errorf("Expected token to be redacted in the log, got: %s", responseBody)
+	}
+}
+
+func TestRedactBody_Truncates(t *testing.T) {
+	got := redactBody([]byte(`{"refreshToken":"abcdefghijklmnop","note":"0123456789"}`), 20)
+	if strings.Contains(got, "abcdef") {
+		t.Errorf("Expected token to be redacted before truncation, got: %s", got)
+	}
+	if !strings.HasSuffix(got, "...(truncated)") || len(got) != 20+len("...(truncated)") {
+		t.Errorf("Expected body truncated to 20 bytes, got: %s", got)
+	}
+
+	if got := redactBody([]byte(`{"password":"hunt`), 0); strings.Contains(got, "hunt") {
+		t.Errorf("Expected unterminated secret to be redacted, got: %s", got)
+	}
+}
+
+func TestBodyLoggingMiddleware_SkipsGet(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	log := &recordingLogger{Logger: logger.NewNoopLogger(), fields: make(map[string]interface{})}
+
+	router := gin.New()
+	router.Use(BodyLoggingMiddleware(log, 1024))
+	router.GET("/foods", func(c *gin.Context) { c.JSON(http.StatusOK, gin.H{"ok": true}) })
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/foods", nil))
+
+	if len(log.fields) != 0 {
+		t.Errorf("Expected GET requests not to be logged, got: %v", log.fields)
+	}
+}
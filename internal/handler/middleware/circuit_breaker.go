@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"nutrient_be/internal/pkg/circuitbreaker"
+)
+
+// CircuitBreakerMiddleware rejects requests with 503 while the database circuit breaker is open
+// Requests to exemptPaths, such as the health probes, always pass. A nil breaker disables the check.
+func CircuitBreakerMiddleware(breaker *circuitbreaker.Breaker, exemptPaths ...string) gin.HandlerFunc {
+	exempt := make(map[string]bool, len(exemptPaths))
+	for _, path := range exemptPaths {
+		exempt[path] = true
+	}
+
+	return func(c *gin.Context) {
+		if breaker == nil || exempt[c.Request.URL.Path] || breaker.State() != circuitbreaker.StateOpen {
+			c.Next()
+			return
+		}
+
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": "The database is temporarily unavailable, please try again later",
+		})
+		c.Abort()
+	}
+}
@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"nutrient_be/internal/pkg/circuitbreaker"
+)
+
+func newCircuitBreakerTestRouter(breaker *circuitbreaker.Breaker) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(CircuitBreakerMiddleware(breaker, "/health/readiness"))
+
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	router.GET("/foods", ok)
+	router.GET("/health/readiness", ok)
+	return router
+}
+
+func TestCircuitBreakerMiddleware_RejectsWhileOpen(t *testing.T) {
+	breaker := circuitbreaker.New(1, time.Hour)
+	router := newCircuitBreakerTestRouter(breaker)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/foods", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200 while closed, got: %d", rec.Code)
+	}
+
+	breaker.Record(true)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/foods", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected 503 while open, got: %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health/readiness", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("Expected exempt path to pass while open, got: %d", rec.Code)
+	}
+}
@@ -11,10 +11,14 @@ import (
 )
 
 // ContextMiddleware creates middleware that injects context information
-func ContextMiddleware(log logger.Logger) gin.HandlerFunc {
+// The request ID is read from and echoed in requestIDHeader; empty uses DefaultRequestIDHeader
+func ContextMiddleware(log logger.Logger, requestIDHeader string) gin.HandlerFunc {
+	if requestIDHeader == "" {
+		requestIDHeader = DefaultRequestIDHeader
+	}
 	return func(c *gin.Context) {
 		// Generate request ID if not present
-		requestID := c.GetHeader("X-Request-ID")
+		requestID := c.GetHeader(requestIDHeader)
 		if requestID == "" {
 			requestID = uuid.New().String()
 		}
@@ -43,11 +47,12 @@ func ContextMiddleware(log logger.Logger) gin.HandlerFunc {
 		ctx = context.WithValue(ctx, logger.UserAgentKey, c.GetHeader("User-Agent"))
 		ctx = context.WithValue(ctx, logger.StartTimeKey, time.Now().Format(time.RFC3339))
 
-		// Store context in Gin context
+		// Store context in Gin context and on the request, so every log line and the response meta share the IDs
 		c.Set("context", ctx)
+		c.Request = c.Request.WithContext(ctx)
 
 		// Set response headers
-		c.Header("X-Request-ID", requestID)
+		c.Header(requestIDHeader, requestID)
 		c.Header("X-Trace-ID", traceID)
 
 		// Continue to next handler
@@ -55,6 +60,20 @@ func ContextMiddleware(log logger.Logger) gin.HandlerFunc {
 	}
 }
 
+// withContextValue adds a value to the enriched context set by ContextMiddleware
+// Without ContextMiddleware the request context is extended instead
+func withContextValue(c *gin.Context, key logger.ContextKey, value string) {
+	ctx := c.Request.Context()
+	if ctxValue, exists := c.Get("context"); exists {
+		if enriched, ok := ctxValue.(context.Context); ok {
+			ctx = enriched
+		}
+	}
+	ctx = context.WithValue(ctx, key, value)
+	c.Set("context", ctx)
+	c.Request = c.Request.WithContext(ctx)
+}
+
 // GetContext extracts context from Gin context
 func GetContext(c *gin.Context) context.Context {
 	if ctxValue, exists := c.Get("context"); exists {
@@ -0,0 +1,141 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"nutrient_be/internal/pkg/logger"
+)
+
+// idRecordingLogger keeps the request and trace IDs of every Info call
+type idRecordingLogger struct {
+	logger.Logger
+	requestIDs []string
+	traceIDs   []string
+}
+
+func (l *idRecordingLogger) Info(ctx context.Context, msg string, fields ...logger.Field) {
+	l.requestIDs = append(l.requestIDs, getStringValueFromContext(ctx, logger.RequestIDKey))
+	l.traceIDs = append(l.traceIDs, getStringValueFromContext(ctx, logger.TraceIDKey))
+}
+
+func TestContextMiddleware_GeneratedIDsMatchLogsAndResponseMeta(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	log := &idRecordingLogger{Logger: logger.NewNoopLogger()}
+
+	router := gin.New()
+	router.Use(ContextMiddleware(log, DefaultRequestIDHeader))
+	router.Use(ResponseMiddleware(log))
+	router.GET("/foods", func(c *gin.Context) {
+		log.Info(GetContext(c), "Listing foods")
+		log.Info(c.Request.Context(), "Listed foods")
+		NewResponseHelper().Success(c, nil)
+	})
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/foods", nil))
+
+	var body ResponseFormat
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Expected a JSON body, got: %v", err)
+	}
+	if body.Meta == nil || body.Meta.RequestID == "" || body.Meta.TraceID == "" {
+		t.Fatalf("Expected generated IDs in the response meta, got: %+v", body.Meta)
+	}
+	if got := rec.Header().Get("X-Request-ID"); got != body.Meta.RequestID {
+		t.Errorf("Expected the X-Request-ID header %q to match the meta, got: %q", body.Meta.RequestID, got)
+	}
+	if len(log.requestIDs) != 2 {
+		t.Fatalf("Expected 2 log lines, got: %d", len(log.requestIDs))
+	}
+	for i := range log.requestIDs {
+		if log.requestIDs[i] != body.Meta.RequestID || log.traceIDs[i] != body.Meta.TraceID {
+			t.Errorf("Expected log line %d to carry request %q and trace %q, got: %q and %q",
+				i, body.Meta.RequestID, body.Meta.TraceID, log.requestIDs[i], log.traceIDs[i])
+		}
+	}
+}
+
+func TestContextMiddleware_KeepsClientRequestID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	log := logger.NewNoopLogger()
+
+	router := gin.New()
+	router.Use(ContextMiddleware(log, DefaultRequestIDHeader))
+	router.Use(ResponseMiddleware(log))
+	router.GET("/foods", func(c *gin.Context) { NewResponseHelper().Success(c, nil) })
+
+	req := httptest.NewRequest(http.MethodGet, "/foods", nil)
+	req.Header.Set("X-Request-ID", "client-request-1")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	var body ResponseFormat
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Expected a JSON body, got: %v", err)
+	}
+	if body.Meta == nil || body.Meta.RequestID != "client-request-1" {
+		t.Errorf("Expected the client's request ID in the meta, got: %+v", body.Meta)
+	}
+}
+
+func TestContextMiddleware_ConfiguredRequestIDHeader(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	log := &idRecordingLogger{Logger: logger.NewNoopLogger()}
+
+	router := gin.New()
+	router.Use(ContextMiddleware(log, "X-Correlation-ID"))
+	router.Use(ResponseMiddleware(log))
+	router.GET("/foods", func(c *gin.Context) {
+		log.Info(GetContext(c), "Listing foods")
+		NewResponseHelper().Success(c, nil)
+	})
+
+	t.Run("honors and echoes the configured header", func(t *testing.T) {
+		log.requestIDs = nil
+		req := httptest.NewRequest(http.MethodGet, "/foods", nil)
+		req.Header.Set("X-Correlation-ID", "proxy-correlation-1")
+		req.Header.Set("X-Request-ID", "ignored")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		var body ResponseFormat
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatalf("Expected a JSON body, got: %v", err)
+		}
+		if body.Meta == nil || body.Meta.RequestID != "proxy-correlation-1" {
+			t.Errorf("Expected the correlation ID in the meta, got: %+v", body.Meta)
+		}
+		if got := rec.Header().Get("X-Correlation-ID"); got != "proxy-correlation-1" {
+			t.Errorf("Expected the correlation ID to be echoed back, got: %q", got)
+		}
+		if got := rec.Header().Get("X-Request-ID"); got != "" {
+			t.Errorf("Expected no X-Request-ID header, got: %q", got)
+		}
+		if len(log.requestIDs) != 1 || log.requestIDs[0] != "proxy-correlation-1" {
+			t.Errorf("Expected the log line to carry the correlation ID, got: %v", log.requestIDs)
+		}
+	})
+
+	t.Run("generates an ID when the header is absent", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/foods", nil))
+
+		generated := rec.Header().Get("X-Correlation-ID")
+		if generated == "" {
+			t.Fatal("Expected a generated ID in the configured header")
+		}
+		var body ResponseFormat
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatalf("Expected a JSON body, got: %v", err)
+		}
+		if body.Meta == nil || body.Meta.RequestID != generated {
+			t.Errorf("Expected the meta to carry the generated ID %q, got: %+v", generated, body.Meta)
+		}
+	})
+}
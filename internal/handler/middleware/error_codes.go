@@ -0,0 +1,54 @@
+package middleware
+
+import "net/http"
+
+// Machine-readable error codes returned in the errorCode field of error responses
+// Generic codes follow the HTTP status; specific ones are set by handlers when they map service errors
+const (
+	ErrorCodeBadRequest         = "BAD_REQUEST"
+	ErrorCodeValidationFailed   = "VALIDATION_FAILED"
+	ErrorCodeUnauthorized       = "UNAUTHORIZED"
+	ErrorCodeInvalidCredentials = "INVALID_CREDENTIALS"
+	ErrorCodeForbidden          = "FORBIDDEN"
+	ErrorCodeNotFound           = "NOT_FOUND"
+	ErrorCodeConflict           = "CONFLICT"
+	ErrorCodePayloadTooLarge    = "PAYLOAD_TOO_LARGE"
+	ErrorCodeInternal           = "INTERNAL_ERROR"
+	ErrorCodeServiceUnavailable = "SERVICE_UNAVAILABLE"
+
+	ErrorCodeDuplicateEmail       = "DUPLICATE_EMAIL"
+	ErrorCodeUserNotFound         = "USER_NOT_FOUND"
+	ErrorCodeFoodNotFound         = "FOOD_NOT_FOUND"
+	ErrorCodeMealTemplateNotFound = "MEAL_TEMPLATE_NOT_FOUND"
+	ErrorCodeMealPlanNotFound     = "MEAL_PLAN_NOT_FOUND"
+	ErrorCodeMealNotFound         = "MEAL_NOT_FOUND"
+	ErrorCodeDayNotFound          = "DAY_NOT_FOUND"
+	ErrorCodeShoppingListNotFound = "SHOPPING_LIST_NOT_FOUND"
+	ErrorCodeShoppingItemNotFound = "SHOPPING_ITEM_NOT_FOUND"
+)
+
+// getDefaultErrorCode returns the generic error code for an HTTP status, or "" for statuses without one
+func getDefaultErrorCode(statusCode int) string {
+	switch statusCode {
+	case http.StatusBadRequest:
+		return ErrorCodeBadRequest
+	case http.StatusUnauthorized:
+		return ErrorCodeUnauthorized
+	case http.StatusForbidden:
+		return ErrorCodeForbidden
+	case http.StatusNotFound:
+		return ErrorCodeNotFound
+	case http.StatusConflict:
+		return ErrorCodeConflict
+	case http.StatusRequestEntityTooLarge:
+		return ErrorCodePayloadTooLarge
+	case http.StatusUnprocessableEntity:
+		return ErrorCodeValidationFailed
+	case http.StatusInternalServerError:
+		return ErrorCodeInternal
+	case http.StatusServiceUnavailable:
+		return ErrorCodeServiceUnavailable
+	default:
+		return ""
+	}
+}
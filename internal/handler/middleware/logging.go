@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"context"
+	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -11,12 +12,17 @@ import (
 )
 
 const (
-	RequestIDHeader = "X-Request-ID"
+	// DefaultRequestIDHeader carries the request ID unless server.request_id_header names another header
+	DefaultRequestIDHeader = "X-Request-ID"
 )
 
 // LoggingMiddleware logs HTTP requests
 // Note: This middleware should be placed after ContextMiddleware to use enriched context
-func LoggingMiddleware(log logger.Logger) gin.HandlerFunc {
+// Without it the request ID is read from requestIDHeader; empty uses DefaultRequestIDHeader
+func LoggingMiddleware(log logger.Logger, requestIDHeader string) gin.HandlerFunc {
+	if requestIDHeader == "" {
+		requestIDHeader = DefaultRequestIDHeader
+	}
 	return gin.LoggerWithFormatter(func(param gin.LogFormatterParams) string {
 		// Try to get enriched context from Gin context (set by ContextMiddleware)
 		// If not available, create a basic context with request ID
@@ -27,14 +33,14 @@ func LoggingMiddleware(log logger.Logger) gin.HandlerFunc {
 			} else {
 				// Fallback: create basic context
 				ctx = context.Background()
-				ctx = context.WithValue(ctx, logger.RequestIDKey, param.Request.Header.Get(RequestIDHeader))
+				ctx = context.WithValue(ctx, logger.RequestIDKey, param.Request.Header.Get(requestIDHeader))
 				ctx = context.WithValue(ctx, logger.IPAddressKey, param.ClientIP)
 				ctx = context.WithValue(ctx, logger.UserAgentKey, param.Request.UserAgent())
 			}
 		} else {
 			// Fallback: create basic context
 			ctx = context.Background()
-			ctx = context.WithValue(ctx, logger.RequestIDKey, param.Request.Header.Get(RequestIDHeader))
+			ctx = context.WithValue(ctx, logger.RequestIDKey, param.Request.Header.Get(requestIDHeader))
 			ctx = context.WithValue(ctx, logger.IPAddressKey, param.ClientIP)
 			ctx = context.WithValue(ctx, logger.UserAgentKey, param.Request.UserAgent())
 		}
@@ -66,10 +72,55 @@ func RecoveryMiddleware(log logger.Logger) gin.HandlerFunc {
 	})
 }
 
-// CORSMiddleware handles CORS
-func CORSMiddleware() gin.HandlerFunc {
+// CORSOrigins holds the allowed CORS origins and can be replaced at runtime
+type CORSOrigins struct {
+	mu      sync.RWMutex
+	origins map[string]bool
+}
+
+// NewCORSOrigins creates the allowed origin set; "*" allows any origin
+func NewCORSOrigins(origins []string) *CORSOrigins {
+	o := &CORSOrigins{}
+	o.Set(origins)
+	return o
+}
+
+// Set replaces the allowed origins
+func (o *CORSOrigins) Set(origins []string) {
+	allowed := make(map[string]bool, len(origins))
+	for _, origin := range origins {
+		allowed[origin] = true
+	}
+
+	o.mu.Lock()
+	o.origins = allowed
+	o.mu.Unlock()
+}
+
+// allowOrigin returns the Access-Control-Allow-Origin value for a request origin, or "" if it is not allowed
+func (o *CORSOrigins) allowOrigin(origin string) string {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+
+	if o.origins["*"] {
+		return "*"
+	}
+	if origin != "" && o.origins[origin] {
+		return origin
+	}
+	return ""
+}
+
+// CORSMiddleware handles CORS for the allowed origins
+func CORSMiddleware(origins *CORSOrigins) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		c.Header("Access-Control-Allow-Origin", "*")
+		allowOrigin := origins.allowOrigin(c.GetHeader("Origin"))
+		if allowOrigin != "" {
+			c.Header("Access-Control-Allow-Origin", allowOrigin)
+		}
+		if allowOrigin != "*" {
+			c.Header("Vary", "Origin")
+		}
 		c.Header("Access-Control-Allow-Credentials", "true")
 		c.Header("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With")
 		c.Header("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE")
@@ -92,14 +143,19 @@ func RequestTimeoutMiddleware(timeout time.Duration) gin.HandlerFunc {
 	}
 }
 
-func RequestIDMiddleware() gin.HandlerFunc {
+// RequestIDMiddleware echoes the request ID in requestIDHeader, generating one when the client sent none
+// Empty uses DefaultRequestIDHeader
+func RequestIDMiddleware(requestIDHeader string) gin.HandlerFunc {
+	if requestIDHeader == "" {
+		requestIDHeader = DefaultRequestIDHeader
+	}
 	return func(c *gin.Context) {
-		requestID := c.GetHeader(RequestIDHeader)
+		requestID := c.GetHeader(requestIDHeader)
 		if requestID == "" {
 			requestID = uuid.New().String()
 		}
-		c.Set(RequestIDHeader, requestID)
-		c.Header(RequestIDHeader, requestID)
+		c.Set(requestIDHeader, requestID)
+		c.Header(requestIDHeader, requestID)
 		c.Next()
 	}
 }
@@ -0,0 +1,450 @@
+package middleware
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Message IDs handlers pass as the response message, translated by the response middleware
+// Messages that are not IDs are sent unchanged, so a handler may still pass literal text
+const (
+	MsgStatusOK                   = "status.ok"
+	MsgStatusCreated              = "status.created"
+	MsgStatusBadRequest           = "status.bad_request"
+	MsgStatusUnauthorized         = "status.unauthorized"
+	MsgStatusForbidden            = "status.forbidden"
+	MsgStatusNotFound             = "status.not_found"
+	MsgStatusConflict             = "status.conflict"
+	MsgStatusPayloadTooLarge      = "status.payload_too_large"
+	MsgValidationFailed           = "error.validation_failed"
+	MsgStatusInternalError        = "status.internal_error"
+	MsgStatusServiceUnavailable   = "status.service_unavailable"
+	MsgStatusUnknown              = "status.unknown"
+	MsgAuthenticationRequired     = "error.authentication_required"
+	MsgAuthorizationRequired      = "error.authorization_required"
+	MsgInvalidAuthorizationHeader = "error.invalid_authorization_header"
+	MsgInvalidToken               = "error.invalid_token"
+	MsgInvalidCredentials         = "error.invalid_credentials"
+	MsgInvalidRequestBody         = "error.invalid_request_body"
+	MsgInvalidRequest             = "error.invalid_request"
+	MsgInvalidDate                = "error.invalid_date"
+	MsgInvalidDateRange           = "error.invalid_date_range"
+	MsgInvalidMonth               = "error.invalid_month"
+	MsgInvalidPagination          = "error.invalid_pagination"
+	MsgInvalidSearchParameters    = "error.invalid_search_parameters"
+	MsgInvalidMaxCalories         = "error.invalid_max_calories"
+	MsgInvalidLanguage            = "error.invalid_language"
+	MsgInvalidExpand              = "error.invalid_expand"
+	MsgInvalidUnitSystem          = "error.invalid_unit_system"
+	MsgInvalidCheckedValue        = "error.invalid_checked_value"
+	MsgInvalidImageUpload         = "error.invalid_image_upload"
+	MsgImageFileRequired          = "error.image_file_required"
+	MsgImageTooLarge              = "error.image_too_large"
+	MsgOperationFailed            = "error.operation_failed"
+	MsgDatabaseUnavailable        = "error.database_unavailable"
+	MsgRegistrationFailed         = "error.registration_failed"
+	MsgPasswordConfirmationFailed = "error.password_confirmation_failed"
+	MsgRouteNotFound              = "error.route_not_found"
+	MsgUserNotFound               = "error.user_not_found"
+	MsgFoodNotFound               = "error.food_not_found"
+	MsgFoodImageNotFound          = "error.food_image_not_found"
+	MsgFoodIDRequired             = "error.food_id_required"
+	MsgMealTemplateNotFound       = "error.meal_template_not_found"
+	MsgTemplateIDRequired         = "error.template_id_required"
+	MsgMealPlanNotFound           = "error.meal_plan_not_found"
+	MsgMealPlanIDRequired         = "error.meal_plan_id_required"
+	MsgMealNotFound               = "error.meal_not_found"
+	MsgDayNotFound                = "error.day_not_found"
+	MsgShoppingListNotFound       = "error.shopping_list_not_found"
+	MsgShoppingListIDRequired     = "error.shopping_list_id_required"
+	MsgShoppingItemNotFound       = "error.shopping_item_not_found"
+	MsgFoodCreateFailed           = "error.food_create_failed"
+	MsgFoodGetFailed              = "error.food_get_failed"
+	MsgProfileGetFailed           = "error.profile_get_failed"
+	MsgProfileUpdateFailed        = "error.profile_update_failed"
+	MsgPreferencesUpdateFailed    = "error.preferences_update_failed"
+	MsgPasswordChangeFailed       = "error.password_change_failed"
+	MsgAccountDeleteFailed        = "error.account_delete_failed"
+	MsgUserDataExportFailed       = "error.user_data_export_failed"
+	MsgMealPlanRenderFailed       = "error.meal_plan_render_failed"
+	MsgUserRegistered             = "auth.registered"
+	MsgLoginSuccessful            = "auth.logged_in"
+	MsgTokenRefreshed             = "auth.token_refreshed"
+	MsgLoggedOut                  = "auth.logged_out"
+	MsgTokenValid                 = "auth.token_valid"
+	MsgReadOnlyRetrieved          = "admin.read_only_retrieved"
+	MsgReadOnlyUpdated            = "admin.read_only_updated"
+	MsgFoodsMerged                = "admin.foods_merged"
+	MsgFoodCaloriesRecalculated   = "admin.food_calories_recalculated"
+	MsgFoodCreated                = "food.created"
+	MsgFoodValidated              = "food.validated"
+	MsgFoodSearched               = "food.searched"
+	MsgFoodRetrieved              = "food.retrieved"
+	MsgFoodBreakdownRetrieved     = "food.breakdown_retrieved"
+	MsgPublicFoodsListed          = "food.public_listed"
+	MsgFoodUpdated                = "food.updated"
+	MsgFoodCloned                 = "food.cloned"
+	MsgFoodVisibilityUpdated      = "food.visibility_updated"
+	MsgCaloriesEstimated          = "food.calories_estimated"
+	MsgFoodDeleted                = "food.deleted"
+	MsgFoodImageUploaded          = "food.image_uploaded"
+	MsgMealTemplateCreated        = "meal_template.created"
+	MsgMealTemplatesListed        = "meal_template.listed"
+	MsgFittingMealTemplatesListed = "meal_template.fitting_listed"
+	MsgMealTemplateTagsListed     = "meal_template.tags_listed"
+	MsgMealTemplateRetrieved      = "meal_template.retrieved"
+	MsgMealTemplateFoodsAdded     = "meal_template.foods_added"
+	MsgMealTemplateUpdated        = "meal_template.updated"
+	MsgMealTemplateDeleted        = "meal_template.deleted"
+	MsgMealTemplatesDeleted       = "meal_template.bulk_deleted"
+	MsgNutritionCalculated        = "nutrition.calculated"
+	MsgMealPlanCreated            = "meal_plan.created"
+	MsgMealPlansListed            = "meal_plan.listed"
+	MsgMealPlanRetrieved          = "meal_plan.retrieved"
+	MsgMealPlanUpdated            = "meal_plan.updated"
+	MsgMealPlanTargetsUpdated     = "meal_plan.targets_updated"
+	MsgMealPlanDeleted            = "meal_plan.deleted"
+	MsgMealNotesUpdated           = "meal_plan.meal_notes_updated"
+	MsgDayNotesUpdated            = "meal_plan.day_notes_updated"
+	MsgMealPlanDayRegenerated     = "meal_plan.day_regenerated"
+	MsgMealSwapsSuggested         = "meal_plan.swaps_suggested"
+	MsgServingUnitsRetrieved      = "metadata.units_retrieved"
+	MsgFoodCategoriesRetrieved    = "metadata.categories_retrieved"
+	MsgWeeklyReportGenerated      = "report.weekly_generated"
+	MsgMonthlyReportGenerated     = "report.monthly_generated"
+	MsgProgressReportGenerated    = "report.progress_generated"
+	MsgRangeSummaryGenerated      = "report.range_generated"
+	MsgAdherenceReportGenerated   = "report.adherence_generated"
+	MsgCalorieTrendGenerated      = "report.calorie_trend_generated"
+	MsgAdherenceStreakGenerated   = "report.streak_generated"
+	MsgShoppingListGenerated      = "shopping_list.generated"
+	MsgShoppingListsMerged        = "shopping_list.merged"
+	MsgShoppingListsListed        = "shopping_list.listed"
+	MsgShoppingItemToggled        = "shopping_list.item_toggled"
+	MsgShoppingItemsToggled       = "shopping_list.items_toggled"
+	MsgShoppingItemAdded          = "shopping_list.item_added"
+	MsgShoppingItemRemoved        = "shopping_list.item_removed"
+	MsgMealSuggestionsGenerated   = "suggestion.generated"
+	MsgDashboardRetrieved         = "dashboard.retrieved"
+	MsgProfileRetrieved           = "user.profile_retrieved"
+	MsgProfileUpdated             = "user.profile_updated"
+	MsgTargetsCalculated          = "user.targets_calculated"
+	MsgPreferencesUpdated         = "user.preferences_updated"
+	MsgPasswordChanged            = "user.password_changed"
+	MsgAccountDeleted             = "user.account_deleted"
+)
+
+// Languages response messages are available in
+const (
+	LanguageEnglish    = "en"
+	LanguageVietnamese = "vi"
+)
+
+// DefaultLanguage is used when the client accepts no supported language, and for missing translations
+const DefaultLanguage = LanguageEnglish
+
+// messageCatalog holds the text of each message ID, by language
+var messageCatalog = map[string]map[string]string{
+	LanguageEnglish: {
+		MsgStatusOK:                   "Success",
+		MsgStatusCreated:              "Created successfully",
+		MsgStatusBadRequest:           "Bad request",
+		MsgStatusUnauthorized:         "Unauthorized",
+		MsgStatusForbidden:            "Forbidden",
+		MsgStatusNotFound:             "Not found",
+		MsgStatusConflict:             "Conflict",
+		MsgStatusPayloadTooLarge:      "Payload too large",
+		MsgValidationFailed:           "Validation failed",
+		MsgStatusInternalError:        "Internal server error",
+		MsgStatusServiceUnavailable:   "Service unavailable",
+		MsgStatusUnknown:              "Unknown error",
+		MsgAuthenticationRequired:     "Authentication required",
+		MsgAuthorizationRequired:      "Authorization required",
+		MsgInvalidAuthorizationHeader: "Invalid authorization header",
+		MsgInvalidToken:               "Invalid token",
+		MsgInvalidCredentials:         "Invalid credentials",
+		MsgInvalidRequestBody:         "Invalid request body",
+		MsgInvalidRequest:             "Invalid request",
+		MsgInvalidDate:                "Invalid date",
+		MsgInvalidDateRange:           "Invalid date range",
+		MsgInvalidMonth:               "Invalid month",
+		MsgInvalidPagination:          "Invalid pagination",
+		MsgInvalidSearchParameters:    "Invalid search parameters",
+		MsgInvalidMaxCalories:         "Invalid maxCalories",
+		MsgInvalidLanguage:            "Invalid language",
+		MsgInvalidExpand:              "Invalid expand",
+		MsgInvalidUnitSystem:          "Invalid unit system",
+		MsgInvalidCheckedValue:        "Invalid checked value",
+		MsgInvalidImageUpload:         "Invalid image upload",
+		MsgImageFileRequired:          "An image file is required in the image field",
+		MsgImageTooLarge:              "The image exceeds the maximum upload size",
+		MsgOperationFailed:            "Operation failed",
+		MsgDatabaseUnavailable:        "The database is temporarily unavailable, please try again later",
+		MsgRegistrationFailed:         "Registration failed",
+		MsgPasswordConfirmationFailed: "Password confirmation failed",
+		MsgRouteNotFound:              "Route not found",
+		MsgUserNotFound:               "User not found",
+		MsgFoodNotFound:               "Food item not found",
+		MsgFoodImageNotFound:          "Food image not found",
+		MsgFoodIDRequired:             "Food ID is required",
+		MsgMealTemplateNotFound:       "Meal template not found",
+		MsgTemplateIDRequired:         "Template ID is required",
+		MsgMealPlanNotFound:           "Meal plan not found",
+		MsgMealPlanIDRequired:         "Meal plan ID is required",
+		MsgMealNotFound:               "Meal not found",
+		MsgDayNotFound:                "Day not found",
+		MsgShoppingListNotFound:       "Shopping list not found",
+		MsgShoppingListIDRequired:     "Shopping list ID is required",
+		MsgShoppingItemNotFound:       "Item not found",
+		MsgFoodCreateFailed:           "Failed to create food",
+		MsgFoodGetFailed:              "Failed to get food",
+		MsgProfileGetFailed:           "Failed to get user profile",
+		MsgProfileUpdateFailed:        "Failed to update user profile",
+		MsgPreferencesUpdateFailed:    "Failed to update user preferences",
+		MsgPasswordChangeFailed:       "Failed to change password",
+		MsgAccountDeleteFailed:        "Failed to delete account",
+		MsgUserDataExportFailed:       "Failed to export user data",
+		MsgMealPlanRenderFailed:       "Failed to render meal plan",
+		MsgUserRegistered:             "User registered successfully",
+		MsgLoginSuccessful:            "Login successful",
+		MsgTokenRefreshed:             "Token refreshed successfully",
+		MsgLoggedOut:                  "Logged out successfully",
+		MsgTokenValid:                 "Token is valid",
+		MsgReadOnlyRetrieved:          "Read-only mode retrieved successfully",
+		MsgReadOnlyUpdated:            "Read-only mode updated successfully",
+		MsgFoodsMerged:                "Foods merged successfully",
+		MsgFoodCaloriesRecalculated:   "Food calories recalculated successfully",
+		MsgFoodCreated:                "Food created successfully",
+		MsgFoodValidated:              "Food validated",
+		MsgFoodSearched:               "Food search successful",
+		MsgFoodRetrieved:              "Food retrieved successfully",
+		MsgFoodBreakdownRetrieved:     "Food macro breakdown retrieved successfully",
+		MsgPublicFoodsListed:          "Public foods listed successfully",
+		MsgFoodUpdated:                "Food updated successfully",
+		MsgFoodCloned:                 "Food cloned successfully",
+		MsgFoodVisibilityUpdated:      "Food visibility updated successfully",
+		MsgCaloriesEstimated:          "Calories estimated successfully",
+		MsgFoodDeleted:                "Food deleted successfully",
+		MsgFoodImageUploaded:          "Food image uploaded successfully",
+		MsgMealTemplateCreated:        "Meal template created successfully",
+		MsgMealTemplatesListed:        "Meal templates listed successfully",
+		MsgFittingMealTemplatesListed: "Fitting meal templates listed successfully",
+		MsgMealTemplateTagsListed:     "Meal template tags listed successfully",
+		MsgMealTemplateRetrieved:      "Meal template retrieved successfully",
+		MsgMealTemplateFoodsAdded:     "Food items added to template successfully",
+		MsgMealTemplateUpdated:        "Meal template updated successfully",
+		MsgMealTemplateDeleted:        "Meal template deleted successfully",
+		MsgMealTemplatesDeleted:       "Meal templates deleted successfully",
+		MsgNutritionCalculated:        "Nutrition calculated successfully",
+		MsgMealPlanCreated:            "Meal plan created successfully",
+		MsgMealPlansListed:            "Meal plans listed successfully",
+		MsgMealPlanRetrieved:          "Meal plan retrieved successfully",
+		MsgMealPlanUpdated:            "Meal plan updated successfully",
+		MsgMealPlanTargetsUpdated:     "Meal plan targets updated successfully",
+		MsgMealPlanDeleted:            "Meal plan deleted successfully",
+		MsgMealNotesUpdated:           "Meal notes updated successfully",
+		MsgDayNotesUpdated:            "Day notes updated successfully",
+		MsgMealPlanDayRegenerated:     "Meal plan day regenerated successfully",
+		MsgMealSwapsSuggested:         "Meal swaps suggested successfully",
+		MsgServingUnitsRetrieved:      "Serving units retrieved successfully",
+		MsgFoodCategoriesRetrieved:    "Food categories retrieved successfully",
+		MsgWeeklyReportGenerated:      "Weekly report generated successfully",
+		MsgMonthlyReportGenerated:     "Monthly report generated successfully",
+		MsgProgressReportGenerated:    "Goal progress report generated successfully",
+		MsgRangeSummaryGenerated:      "Range summary generated successfully",
+		MsgAdherenceReportGenerated:   "Plan adherence report generated successfully",
+		MsgCalorieTrendGenerated:      "Calorie trend generated successfully",
+		MsgAdherenceStreakGenerated:   "Adherence streak generated successfully",
+		MsgShoppingListGenerated:      "Shopping list generated successfully",
+		MsgShoppingListsMerged:        "Shopping lists merged successfully",
+		MsgShoppingListsListed:        "Shopping lists listed successfully",
+		MsgShoppingItemToggled:        "Shopping item toggled successfully",
+		MsgShoppingItemsToggled:       "Shopping items toggled successfully",
+		MsgShoppingItemAdded:          "Shopping item added successfully",
+		MsgShoppingItemRemoved:        "Shopping item removed successfully",
+		MsgMealSuggestionsGenerated:   "Meal suggestions generated successfully",
+		MsgDashboardRetrieved:         "Dashboard retrieved successfully",
+		MsgProfileRetrieved:           "User profile retrieved successfully",
+		MsgProfileUpdated:             "User profile updated successfully",
+		MsgTargetsCalculated:          "Targets calculated successfully",
+		MsgPreferencesUpdated:         "User preferences updated successfully",
+		MsgPasswordChanged:            "Password changed successfully",
+		MsgAccountDeleted:             "Account deleted successfully",
+	},
+	LanguageVietnamese: {
+		MsgStatusOK:                   "Thành công",
+		MsgStatusCreated:              "Tạo thành công",
+		MsgStatusBadRequest:           "Yêu cầu không hợp lệ",
+		MsgStatusUnauthorized:         "Chưa xác thực",
+		MsgStatusForbidden:            "Không có quyền truy cập",
+		MsgStatusNotFound:             "Không tìm thấy",
+		MsgStatusConflict:             "Xung đột dữ liệu",
+		MsgStatusPayloadTooLarge:      "Dữ liệu gửi lên quá lớn",
+		MsgValidationFailed:           "Dữ liệu không hợp lệ",
+		MsgStatusInternalError:        "Lỗi máy chủ nội bộ",
+		MsgStatusServiceUnavailable:   "Dịch vụ tạm thời không khả dụng",
+		MsgStatusUnknown:              "Lỗi không xác định",
+		MsgAuthenticationRequired:     "Yêu cầu đăng nhập",
+		MsgAuthorizationRequired:      "Yêu cầu xác thực",
+		MsgInvalidAuthorizationHeader: "Header xác thực không hợp lệ",
+		MsgInvalidToken:               "Token không hợp lệ",
+		MsgInvalidCredentials:         "Email hoặc mật khẩu không đúng",
+		MsgInvalidRequestBody:         "Nội dung yêu cầu không hợp lệ",
+		MsgInvalidRequest:             "Yêu cầu không hợp lệ",
+		MsgInvalidDate:                "Ngày không hợp lệ",
+		MsgInvalidDateRange:           "Khoảng thời gian không hợp lệ",
+		MsgInvalidMonth:               "Tháng không hợp lệ",
+		MsgInvalidPagination:          "Tham số phân trang không hợp lệ",
+		MsgInvalidSearchParameters:    "Tham số tìm kiếm không hợp lệ",
+		MsgInvalidMaxCalories:         "Giá trị maxCalories không hợp lệ",
+		MsgInvalidLanguage:            "Ngôn ngữ không hợp lệ",
+		MsgInvalidExpand:              "Tham số expand không hợp lệ",
+		MsgInvalidUnitSystem:          "Hệ đơn vị không hợp lệ",
+		MsgInvalidCheckedValue:        "Giá trị checked không hợp lệ",
+		MsgInvalidImageUpload:         "Ảnh tải lên không hợp lệ",
+		MsgImageFileRequired:          "Cần có tệp ảnh trong trường image",
+		MsgImageTooLarge:              "Ảnh vượt quá kích thước tải lên tối đa",
+		MsgOperationFailed:            "Thao tác thất bại",
+		MsgDatabaseUnavailable:        "Cơ sở dữ liệu tạm thời không khả dụng, vui lòng thử lại sau",
+		MsgRegistrationFailed:         "Đăng ký thất bại",
+		MsgPasswordConfirmationFailed: "Xác nhận mật khẩu thất bại",
+		MsgRouteNotFound:              "Không tìm thấy đường dẫn",
+		MsgUserNotFound:               "Không tìm thấy người dùng",
+		MsgFoodNotFound:               "Không tìm thấy món ăn",
+		MsgFoodImageNotFound:          "Không tìm thấy ảnh món ăn",
+		MsgFoodIDRequired:             "Thiếu ID món ăn",
+		MsgMealTemplateNotFound:       "Không tìm thấy mẫu bữa ăn",
+		MsgTemplateIDRequired:         "Thiếu ID mẫu bữa ăn",
+		MsgMealPlanNotFound:           "Không tìm thấy kế hoạch ăn uống",
+		MsgMealPlanIDRequired:         "Thiếu ID kế hoạch ăn uống",
+		MsgMealNotFound:               "Không tìm thấy bữa ăn",
+		MsgDayNotFound:                "Không tìm thấy ngày",
+		MsgShoppingListNotFound:       "Không tìm thấy danh sách mua sắm",
+		MsgShoppingListIDRequired:     "Thiếu ID danh sách mua sắm",
+		MsgShoppingItemNotFound:       "Không tìm thấy mặt hàng",
+		MsgFoodCreateFailed:           "Không thể tạo món ăn",
+		MsgFoodGetFailed:              "Không thể lấy món ăn",
+		MsgProfileGetFailed:           "Không thể lấy hồ sơ người dùng",
+		MsgProfileUpdateFailed:        "Không thể cập nhật hồ sơ người dùng",
+		MsgPreferencesUpdateFailed:    "Không thể cập nhật tùy chọn người dùng",
+		MsgPasswordChangeFailed:       "Không thể đổi mật khẩu",
+		MsgAccountDeleteFailed:        "Không thể xóa tài khoản",
+		MsgUserDataExportFailed:       "Không thể xuất dữ liệu người dùng",
+		MsgMealPlanRenderFailed:       "Không thể hiển thị kế hoạch ăn uống",
+		MsgUserRegistered:             "Đăng ký thành công",
+		MsgLoginSuccessful:            "Đăng nhập thành công",
+		MsgTokenRefreshed:             "Làm mới token thành công",
+		MsgLoggedOut:                  "Đăng xuất thành công",
+		MsgTokenValid:                 "Token hợp lệ",
+		MsgReadOnlyRetrieved:          "Lấy trạng thái chế độ chỉ đọc thành công",
+		MsgReadOnlyUpdated:            "Cập nhật chế độ chỉ đọc thành công",
+		MsgFoodsMerged:                "Gộp món ăn thành công",
+		MsgFoodCaloriesRecalculated:   "Tính lại calo món ăn thành công",
+		MsgFoodCreated:                "Tạo món ăn thành công",
+		MsgFoodValidated:              "Đã kiểm tra món ăn",
+		MsgFoodSearched:               "Tìm kiếm món ăn thành công",
+		MsgFoodRetrieved:              "Lấy món ăn thành công",
+		MsgFoodBreakdownRetrieved:     "Lấy tỷ lệ dinh dưỡng đa lượng của món ăn thành công",
+		MsgPublicFoodsListed:          "Lấy danh sách món ăn công khai thành công",
+		MsgFoodUpdated:                "Cập nhật món ăn thành công",
+		MsgFoodCloned:                 "Sao chép món ăn thành công",
+		MsgFoodVisibilityUpdated:      "Cập nhật chế độ hiển thị món ăn thành công",
+		MsgCaloriesEstimated:          "Ước tính calo thành công",
+		MsgFoodDeleted:                "Xóa món ăn thành công",
+		MsgFoodImageUploaded:          "Tải ảnh món ăn lên thành công",
+		MsgMealTemplateCreated:        "Tạo mẫu bữa ăn thành công",
+		MsgMealTemplatesListed:        "Lấy danh sách mẫu bữa ăn thành công",
+		MsgFittingMealTemplatesListed: "Lấy danh sách mẫu bữa ăn phù hợp thành công",
+		MsgMealTemplateTagsListed:     "Lấy danh sách thẻ mẫu bữa ăn thành công",
+		MsgMealTemplateRetrieved:      "Lấy mẫu bữa ăn thành công",
+		MsgMealTemplateFoodsAdded:     "Thêm món ăn vào mẫu bữa ăn thành công",
+		MsgMealTemplateUpdated:        "Cập nhật mẫu bữa ăn thành công",
+		MsgMealTemplateDeleted:        "Xóa mẫu bữa ăn thành công",
+		MsgMealTemplatesDeleted:       "Xóa các mẫu bữa ăn thành công",
+		MsgNutritionCalculated:        "Tính toán dinh dưỡng thành công",
+		MsgMealPlanCreated:            "Tạo kế hoạch ăn uống thành công",
+		MsgMealPlansListed:            "Lấy danh sách kế hoạch ăn uống thành công",
+		MsgMealPlanRetrieved:          "Lấy kế hoạch ăn uống thành công",
+		MsgMealPlanUpdated:            "Cập nhật kế hoạch ăn uống thành công",
+		MsgMealPlanTargetsUpdated:     "Cập nhật mục tiêu của kế hoạch ăn uống thành công",
+		MsgMealPlanDeleted:            "Xóa kế hoạch ăn uống thành công",
+		MsgMealNotesUpdated:           "Cập nhật ghi chú bữa ăn thành công",
+		MsgDayNotesUpdated:            "Cập nhật ghi chú ngày thành công",
+		MsgMealPlanDayRegenerated:     "Tạo lại thực đơn trong ngày thành công",
+		MsgMealSwapsSuggested:         "Gợi ý món thay thế thành công",
+		MsgServingUnitsRetrieved:      "Lấy danh sách đơn vị khẩu phần thành công",
+		MsgFoodCategoriesRetrieved:    "Lấy danh sách nhóm món ăn thành công",
+		MsgWeeklyReportGenerated:      "Tạo báo cáo tuần thành công",
+		MsgMonthlyReportGenerated:     "Tạo báo cáo tháng thành công",
+		MsgProgressReportGenerated:    "Tạo báo cáo tiến độ mục tiêu thành công",
+		MsgRangeSummaryGenerated:      "Tạo báo cáo theo khoảng thời gian thành công",
+		MsgAdherenceReportGenerated:   "Tạo báo cáo mức độ tuân thủ kế hoạch thành công",
+		MsgCalorieTrendGenerated:      "Tạo biểu đồ xu hướng calo thành công",
+		MsgAdherenceStreakGenerated:   "Lấy chuỗi ngày đạt mục tiêu thành công",
+		MsgShoppingListGenerated:      "Tạo danh sách mua sắm thành công",
+		MsgShoppingListsMerged:        "Gộp danh sách mua sắm thành công",
+		MsgShoppingListsListed:        "Lấy danh sách mua sắm thành công",
+		MsgShoppingItemToggled:        "Cập nhật trạng thái mặt hàng thành công",
+		MsgShoppingItemsToggled:       "Cập nhật trạng thái các mặt hàng thành công",
+		MsgShoppingItemAdded:          "Thêm mặt hàng thành công",
+		MsgShoppingItemRemoved:        "Xóa mặt hàng thành công",
+		MsgMealSuggestionsGenerated:   "Tạo gợi ý bữa ăn thành công",
+		MsgDashboardRetrieved:         "Lấy tổng quan thành công",
+		MsgProfileRetrieved:           "Lấy hồ sơ người dùng thành công",
+		MsgProfileUpdated:             "Cập nhật hồ sơ người dùng thành công",
+		MsgTargetsCalculated:          "Tính toán mục tiêu thành công",
+		MsgPreferencesUpdated:         "Cập nhật tùy chọn người dùng thành công",
+		MsgPasswordChanged:            "Đổi mật khẩu thành công",
+		MsgAccountDeleted:             "Xóa tài khoản thành công",
+	},
+}
+
+// LocalizeMessage returns the text of a message ID in lang, falling back to English when it has no translation
+// A message that is not an ID is returned unchanged
+func LocalizeMessage(message, lang string) string {
+	if text, ok := messageCatalog[lang][message]; ok {
+		return text
+	}
+	if text, ok := messageCatalog[DefaultLanguage][message]; ok {
+		return text
+	}
+	return message
+}
+
+// PreferredLanguage returns the supported language ranked highest by an Accept-Language header,
+// e.g. "vi-VN,vi;q=0.9,en;q=0.8" gives "vi". Regions are ignored and ties keep header order.
+// It returns DefaultLanguage when the header names no supported language.
+func PreferredLanguage(acceptLanguage string) string {
+	type candidate struct {
+		lang    string
+		quality float64
+	}
+
+	var candidates []candidate
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		lang, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+		if _, ok := messageCatalog[lang]; !ok {
+			continue
+		}
+
+		quality := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			quality = parsed
+		}
+		if quality > 0 {
+			candidates = append(candidates, candidate{lang: lang, quality: quality})
+		}
+	}
+	if len(candidates) == 0 {
+		return DefaultLanguage
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].quality > candidates[j].quality })
+	return candidates[0].lang
+}
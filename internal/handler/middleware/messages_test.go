@@ -0,0 +1,59 @@
+package middleware
+
+import "testing"
+
+func TestPreferredLanguage(t *testing.T) {
+	tests := []struct {
+		header   string
+		expected string
+	}{
+		{"", LanguageEnglish},
+		{"vi", LanguageVietnamese},
+		{"VI-vn", LanguageVietnamese},
+		{"en-US,vi;q=0.9", LanguageEnglish},
+		{"en;q=0.4,vi;q=0.8", LanguageVietnamese},
+		{"fr-FR,de;q=0.9", LanguageEnglish},
+		{"fr,vi;q=0.5", LanguageVietnamese},
+		{"vi;q=0,en;q=0.1", LanguageEnglish},
+		{"vi;q=abc", LanguageEnglish},
+	}
+
+	for _, tt := range tests {
+		if got := PreferredLanguage(tt.header); got != tt.expected {
+			t.Errorf("PreferredLanguage(%q): expected %q, got: %q", tt.header, tt.expected, got)
+		}
+	}
+}
+
+func TestLocalizeMessage_FallsBack(t *testing.T) {
+	if got := LocalizeMessage(MsgFoodCreated, LanguageVietnamese); got != "Tạo món ăn thành công" {
+		t.Errorf("Expected the Vietnamese message, got: %q", got)
+	}
+	if got := LocalizeMessage(MsgFoodCreated, "fr"); got != "Food created successfully" {
+		t.Errorf("Expected English for an unsupported language, got: %q", got)
+	}
+	if got := LocalizeMessage("Something custom happened", LanguageVietnamese); got != "Something custom happened" {
+		t.Errorf("Expected literal text unchanged, got: %q", got)
+	}
+
+	messageCatalog[LanguageEnglish]["test.untranslated"] = "Only in English"
+	defer delete(messageCatalog[LanguageEnglish], "test.untranslated")
+	if got := LocalizeMessage("test.untranslated", LanguageVietnamese); got != "Only in English" {
+		t.Errorf("Expected English for a missing translation, got: %q", got)
+	}
+}
+
+func TestMessageCatalog_TranslationsMatchEnglish(t *testing.T) {
+	for lang, messages := range messageCatalog {
+		for id := range messages {
+			if _, ok := messageCatalog[LanguageEnglish][id]; !ok {
+				t.Errorf("Message %s in %s has no English text", id, lang)
+			}
+		}
+	}
+	for id := range messageCatalog[LanguageEnglish] {
+		if _, ok := messageCatalog[LanguageVietnamese][id]; !ok {
+			t.Errorf("Message %s has no Vietnamese translation", id)
+		}
+	}
+}
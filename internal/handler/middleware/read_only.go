@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminTokenHeader is the header carrying the admin token for admin endpoints
+const AdminTokenHeader = "X-Admin-Token"
+
+// ReadOnlyMode holds whether writes are blocked and can be toggled at runtime
+type ReadOnlyMode struct {
+	enabled atomic.Bool
+}
+
+// NewReadOnlyMode creates the read-only toggle in its initial state
+func NewReadOnlyMode(enabled bool) *ReadOnlyMode {
+	m := &ReadOnlyMode{}
+	m.enabled.Store(enabled)
+	return m
+}
+
+// Enabled reports whether writes are currently blocked
+func (m *ReadOnlyMode) Enabled() bool {
+	return m.enabled.Load()
+}
+
+// Set turns read-only mode on or off
+func (m *ReadOnlyMode) Set(enabled bool) {
+	m.enabled.Store(enabled)
+}
+
+// isWriteMethod reports whether a request method changes data
+func isWriteMethod(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// ReadOnlyMiddleware rejects write requests with 503 while read-only mode is enabled
+// Reads always pass, as do requests to exemptPaths such as the endpoint that turns the mode off
+func ReadOnlyMiddleware(mode *ReadOnlyMode, exemptPaths ...string) gin.HandlerFunc {
+	exempt := make(map[string]bool, len(exemptPaths))
+	for _, path := range exemptPaths {
+		exempt[path] = true
+	}
+
+	return func(c *gin.Context) {
+		if !mode.Enabled() || !isWriteMethod(c.Request.Method) || exempt[c.Request.URL.Path] {
+			c.Next()
+			return
+		}
+
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": "The service is in read-only mode for maintenance; changes are temporarily disabled, please try again later",
+		})
+		c.Abort()
+	}
+}
+
+// AdminTokenMiddleware requires the configured admin token in the X-Admin-Token header
+// Admin endpoints are disabled when no token is configured
+func AdminTokenMiddleware(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token == "" {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Admin endpoints are disabled"})
+			c.Abort()
+			return
+		}
+
+		if subtle.ConstantTimeCompare([]byte(c.GetHeader(AdminTokenHeader)), []byte(token)) != 1 {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid admin token"})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,114 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newReadOnlyTestRouter(mode *ReadOnlyMode) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(ReadOnlyMiddleware(mode, "/admin/read-only"))
+
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	router.GET("/foods", ok)
+	router.HEAD("/foods", ok)
+	router.POST("/foods", ok)
+	router.PUT("/foods/1", ok)
+	router.PATCH("/foods/1", ok)
+	router.DELETE("/foods/1", ok)
+	router.PUT("/admin/read-only", ok)
+	return router
+}
+
+func serveReadOnly(router *gin.Engine, method, path string) int {
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(method, path, nil))
+	return rec.Code
+}
+
+func TestReadOnlyMiddleware_BlocksWritesWhenEnabled(t *testing.T) {
+	router := newReadOnlyTestRouter(NewReadOnlyMode(true))
+
+	writes := []struct{ method, path string }{
+		{http.MethodPost, "/foods"},
+		{http.MethodPut, "/foods/1"},
+		{http.MethodPatch, "/foods/1"},
+		{http.MethodDelete, "/foods/1"},
+	}
+	for _, w := range writes {
+		if code := serveReadOnly(router, w.method, w.path); code != http.StatusServiceUnavailable {
+			t.Errorf("Expected %s %s to return 503, got: %d", w.method, w.path, code)
+		}
+	}
+}
+
+func TestReadOnlyMiddleware_AllowsReadsWhenEnabled(t *testing.T) {
+	router := newReadOnlyTestRouter(NewReadOnlyMode(true))
+
+	for _, method := range []string{http.MethodGet, http.MethodHead} {
+		if code := serveReadOnly(router, method, "/foods"); code != http.StatusOK {
+			t.Errorf("Expected %s to return 200, got: %d", method, code)
+		}
+	}
+	if code := serveReadOnly(router, http.MethodPut, "/admin/read-only"); code != http.StatusOK {
+		t.Errorf("Expected the read-only toggle to stay reachable, got: %d", code)
+	}
+}
+
+func TestReadOnlyMiddleware_RuntimeToggle(t *testing.T) {
+	mode := NewReadOnlyMode(false)
+	router := newReadOnlyTestRouter(mode)
+
+	if code := serveReadOnly(router, http.MethodPost, "/foods"); code != http.StatusOK {
+		t.Fatalf("Expected writes to pass while disabled, got: %d", code)
+	}
+
+	mode.Set(true)
+	if code := serveReadOnly(router, http.MethodPost, "/foods"); code != http.StatusServiceUnavailable {
+		t.Errorf("Expected writes to be blocked after enabling, got: %d", code)
+	}
+
+	mode.Set(false)
+	if code := serveReadOnly(router, http.MethodPost, "/foods"); code != http.StatusOK {
+		t.Errorf("Expected writes to pass after disabling, got: %d", code)
+	}
+}
+
+func TestAdminTokenMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name       string
+		configured string
+		sent       string
+		want       int
+	}{
+		{name: "valid token", configured: "s3cret", sent: "s3cret", want: http.StatusOK},
+		{name: "wrong token", configured: "s3cret", sent: "guess", want: http.StatusUnauthorized},
+		{name: "missing token", configured: "s3cret", sent: "", want: http.StatusUnauthorized},
+		{name: "no token configured", configured: "", sent: "", want: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.Use(AdminTokenMiddleware(tt.configured))
+			router.GET("/admin", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+			req := httptest.NewRequest(http.MethodGet, "/admin", nil)
+			if tt.sent != "" {
+				req.Header.Set(AdminTokenHeader, tt.sent)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Errorf("Expected status %d, got: %d", tt.want, rec.Code)
+			}
+		})
+	}
+}
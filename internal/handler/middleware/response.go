@@ -11,11 +11,12 @@ import (
 
 // ResponseFormat defines the standard response structure
 type ResponseFormat struct {
-	Code    int         `json:"code"`
-	Message string      `json:"message"`
-	Data    interface{} `json:"data,omitempty"`
-	Error   interface{} `json:"error,omitempty"`
-	Meta    *Meta       `json:"meta,omitempty"`
+	Code      int         `json:"code"`
+	Message   string      `json:"message"`
+	ErrorCode string      `json:"errorCode,omitempty"` // Machine-readable code, only set on errors
+	Data      interface{} `json:"data,omitempty"`
+	Error     interface{} `json:"error,omitempty"`
+	Meta      *Meta       `json:"meta,omitempty"`
 }
 
 // Meta contains additional response metadata
@@ -57,6 +58,11 @@ func ResponseMiddleware(log logger.Logger) gin.HandlerFunc {
 			message = getDefaultMessage(statusCode)
 		}
 
+		// Translate message IDs into the language the client prefers
+		lang := PreferredLanguage(c.GetHeader("Accept-Language"))
+		message = LocalizeMessage(message, lang)
+		c.Header("Content-Language", lang)
+
 		// Create standardized response
 		response := ResponseFormat{
 			Code:    statusCode,
@@ -76,6 +82,12 @@ func ResponseMiddleware(log logger.Logger) gin.HandlerFunc {
 		if statusCode >= 400 {
 			response.Error = responseData
 			response.Data = nil
+			response.ErrorCode = getDefaultErrorCode(statusCode)
+			if code, exists := c.Get("response_error_code"); exists {
+				if codeStr, ok := code.(string); ok {
+					response.ErrorCode = codeStr
+				}
+			}
 		}
 
 		// Send standardized response
@@ -84,6 +96,7 @@ func ResponseMiddleware(log logger.Logger) gin.HandlerFunc {
 }
 
 // ResponseHelper provides helper functions for setting response data
+// The optional message is a message ID from the catalog in messages.go, or literal text sent as is
 type ResponseHelper struct{}
 
 // Success sets success response data
@@ -149,6 +162,15 @@ func (rh *ResponseHelper) Conflict(c *gin.Context, error interface{}, message ..
 	c.Status(http.StatusConflict)
 }
 
+// PayloadTooLarge sets payload too large response
+func (rh *ResponseHelper) PayloadTooLarge(c *gin.Context, error interface{}, message ...string) {
+	c.Set("response_data", error)
+	if len(message) > 0 {
+		c.Set("response_message", message[0])
+	}
+	c.Status(http.StatusRequestEntityTooLarge)
+}
+
 // InternalError sets internal server error response
 func (rh *ResponseHelper) InternalError(c *gin.Context, error interface{}, message ...string) {
 	c.Set("response_data", error)
@@ -158,6 +180,15 @@ func (rh *ResponseHelper) InternalError(c *gin.Context, error interface{}, messa
 	c.Status(http.StatusInternalServerError)
 }
 
+// ServiceUnavailable sets service unavailable response
+func (rh *ResponseHelper) ServiceUnavailable(c *gin.Context, error interface{}, message ...string) {
+	c.Set("response_data", error)
+	if len(message) > 0 {
+		c.Set("response_message", message[0])
+	}
+	c.Status(http.StatusServiceUnavailable)
+}
+
 // ValidationError sets validation error response
 func (rh *ResponseHelper) ValidationError(c *gin.Context, errors interface{}, message ...string) {
 	c.Set("response_data", errors)
@@ -167,36 +198,43 @@ func (rh *ResponseHelper) ValidationError(c *gin.Context, errors interface{}, me
 	c.Status(http.StatusUnprocessableEntity)
 }
 
+// ErrorCode sets a specific error code, replacing the generic one for the response status
+func (rh *ResponseHelper) ErrorCode(c *gin.Context, code string) {
+	c.Set("response_error_code", code)
+}
+
 // NewResponseHelper creates a new response helper
 func NewResponseHelper() *ResponseHelper {
 	return &ResponseHelper{}
 }
 
-// getDefaultMessage returns default message for HTTP status codes
+// getDefaultMessage returns the default message ID for HTTP status codes
 func getDefaultMessage(statusCode int) string {
 	switch statusCode {
 	case http.StatusOK:
-		return "Success"
+		return MsgStatusOK
 	case http.StatusCreated:
-		return "Created successfully"
+		return MsgStatusCreated
 	case http.StatusBadRequest:
-		return "Bad request"
+		return MsgStatusBadRequest
 	case http.StatusUnauthorized:
-		return "Unauthorized"
+		return MsgStatusUnauthorized
 	case http.StatusForbidden:
-		return "Forbidden"
+		return MsgStatusForbidden
 	case http.StatusNotFound:
-		return "Not found"
+		return MsgStatusNotFound
 	case http.StatusConflict:
-		return "Conflict"
+		return MsgStatusConflict
+	case http.StatusRequestEntityTooLarge:
+		return MsgStatusPayloadTooLarge
 	case http.StatusUnprocessableEntity:
-		return "Validation failed"
+		return MsgValidationFailed
 	case http.StatusInternalServerError:
-		return "Internal server error"
+		return MsgStatusInternalError
 	case http.StatusServiceUnavailable:
-		return "Service unavailable"
+		return MsgStatusServiceUnavailable
 	default:
-		return "Unknown error"
+		return MsgStatusUnknown
 	}
 }
 
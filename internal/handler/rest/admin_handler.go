@@ -0,0 +1,58 @@
+package rest
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+
+	"nutrient_be/internal/dto/request"
+	"nutrient_be/internal/handler/middleware"
+	"nutrient_be/internal/pkg/logger"
+)
+
+// AdminHandler handles operational endpoints guarded by the admin token
+type AdminHandler struct {
+	readOnly       *middleware.ReadOnlyMode
+	adminToken     string
+	validator      *validator.Validate
+	logger         logger.Logger
+	responseHelper *middleware.ResponseHelper
+}
+
+// NewAdminHandler creates a new admin handler
+func NewAdminHandler(readOnly *middleware.ReadOnlyMode, adminToken string, log logger.Logger) *AdminHandler {
+	return &AdminHandler{
+		readOnly:       readOnly,
+		adminToken:     adminToken,
+		validator:      validator.New(),
+		logger:         log,
+		responseHelper: middleware.NewResponseHelper(),
+	}
+}
+
+// GetReadOnly reports whether read-only mode is enabled
+func (h *AdminHandler) GetReadOnly(c *gin.Context) {
+	h.responseHelper.Success(c, gin.H{"readOnly": h.readOnly.Enabled()}, middleware.MsgReadOnlyRetrieved)
+}
+
+// SetReadOnly turns read-only mode on or off
+func (h *AdminHandler) SetReadOnly(c *gin.Context) {
+	ctx := middleware.GetContext(c)
+
+	var req request.SetReadOnlyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error(ctx, "Failed to bind read-only request", logger.Error(err))
+		h.responseHelper.BadRequest(c, gin.H{"details": err.Error()}, middleware.MsgInvalidRequestBody)
+		return
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		h.logger.Error(ctx, "Read-only request validation failed", logger.Error(err))
+		h.responseHelper.ValidationError(c, gin.H{"validation_errors": err.Error()}, middleware.MsgValidationFailed)
+		return
+	}
+
+	h.readOnly.Set(*req.Enabled)
+
+	h.logger.Warn(ctx, "Read-only mode changed", logger.Bool("read_only", *req.Enabled))
+	h.responseHelper.Success(c, gin.H{"readOnly": *req.Enabled}, middleware.MsgReadOnlyUpdated)
+}
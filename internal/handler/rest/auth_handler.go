@@ -1,7 +1,10 @@
 package rest
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
+	"reflect"
 	"strings"
 
 	"github.com/gin-gonic/gin"
@@ -10,7 +13,9 @@ import (
 	"nutrient_be/internal/config"
 	"nutrient_be/internal/dto/request"
 	"nutrient_be/internal/handler/middleware"
+	"nutrient_be/internal/pkg/jwtkeys"
 	"nutrient_be/internal/pkg/logger"
+	authValidator "nutrient_be/internal/pkg/validator"
 	"nutrient_be/internal/service"
 )
 
@@ -20,17 +25,27 @@ type AuthHandler struct {
 	validator      *validator.Validate
 	logger         logger.Logger
 	config         config.AuthConfig
+	tokenKeys      *jwtkeys.Keys
 	responseHelper *middleware.ResponseHelper
 }
 
 // NewAuthHandler creates a new auth handler
-func NewAuthHandler(authService *service.AuthService, log logger.Logger, cfg config.AuthConfig) *AuthHandler {
+// tokenKeys verify the tokens of protected routes
+// Passwords shorter than cfg.MinPasswordLength are rejected at bind time
+func NewAuthHandler(authService *service.AuthService, log logger.Logger, cfg config.AuthConfig, tokenKeys *jwtkeys.Keys) *AuthHandler {
+	structValidator := validator.New()
+	structValidator.RegisterTagNameFunc(jsonFieldName)
+	if err := authValidator.RegisterPasswordTag(structValidator, cfg.MinPasswordLength); err != nil {
+		panic(fmt.Sprintf("failed to register password validation: %v", err))
+	}
+
 	return &AuthHandler{
 		authService:    authService,
-		validator:      validator.New(),
+		validator:      structValidator,
 		logger:         log,
 		responseHelper: middleware.NewResponseHelper(),
 		config:         cfg,
+		tokenKeys:      tokenKeys,
 	}
 }
 
@@ -42,14 +57,15 @@ func (h *AuthHandler) Register(c *gin.Context) {
 	var req request.RegisterRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		h.logger.Error(ctx, "Failed to bind register request", logger.Error(err))
-		h.responseHelper.BadRequest(c, gin.H{"details": err.Error()}, "Invalid request body")
+		h.responseHelper.BadRequest(c, gin.H{"details": err.Error()}, middleware.MsgInvalidRequestBody)
 		return
 	}
 
 	// Validate request
+	req.Normalize()
 	if err := h.validator.Struct(&req); err != nil {
 		h.logger.Error(ctx, "Register request validation failed", logger.Error(err))
-		h.responseHelper.ValidationError(c, gin.H{"validation_errors": err.Error()}, "Validation failed")
+		h.responseHelper.ValidationError(c, gin.H{"validation_errors": err.Error(), "fields": h.fieldErrors(err)}, middleware.MsgValidationFailed)
 		return
 	}
 
@@ -57,13 +73,18 @@ func (h *AuthHandler) Register(c *gin.Context) {
 	response, err := h.authService.Register(c.Request.Context(), &req)
 	if err != nil {
 		h.logger.Error(ctx, "Failed to register user", logger.Error(err))
-		h.responseHelper.Conflict(c, gin.H{"details": err.Error()}, "Registration failed")
+		if errors.Is(err, service.ErrDuplicateEmail) {
+			h.responseHelper.ErrorCode(c, middleware.ErrorCodeDuplicateEmail)
+			h.responseHelper.Conflict(c, gin.H{"details": err.Error()}, middleware.MsgRegistrationFailed)
+			return
+		}
+		h.responseHelper.InternalError(c, gin.H{"details": err.Error()}, middleware.MsgRegistrationFailed)
 		return
 	}
 
 	// Log success with context information automatically included
 	h.logger.Info(ctx, "User registered successfully", logger.String("email", req.Email))
-	h.responseHelper.Created(c, response, "User registered successfully")
+	h.responseHelper.Created(c, response, middleware.MsgUserRegistered)
 }
 
 // Login handles user login
@@ -74,14 +95,15 @@ func (h *AuthHandler) Login(c *gin.Context) {
 	var req request.LoginRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		h.logger.Error(ctx, "Failed to bind login request", logger.Error(err))
-		h.responseHelper.BadRequest(c, gin.H{"details": err.Error()}, "Invalid request body")
+		h.responseHelper.BadRequest(c, gin.H{"details": err.Error()}, middleware.MsgInvalidRequestBody)
 		return
 	}
 
 	// Validate request
+	req.Normalize()
 	if err := h.validator.Struct(&req); err != nil {
 		h.logger.Error(ctx, "Login request validation failed", logger.Error(err))
-		h.responseHelper.ValidationError(c, gin.H{"validation_errors": err.Error()}, "Validation failed")
+		h.responseHelper.ValidationError(c, gin.H{"validation_errors": err.Error(), "fields": h.fieldErrors(err)}, middleware.MsgValidationFailed)
 		return
 	}
 
@@ -89,13 +111,14 @@ func (h *AuthHandler) Login(c *gin.Context) {
 	response, err := h.authService.Login(c.Request.Context(), &req)
 	if err != nil {
 		h.logger.Error(ctx, "Failed to login user", logger.Error(err))
-		h.responseHelper.Unauthorized(c, gin.H{"details": err.Error()}, "Invalid credentials")
+		h.responseHelper.ErrorCode(c, middleware.ErrorCodeInvalidCredentials)
+		h.responseHelper.Unauthorized(c, gin.H{"details": err.Error()}, middleware.MsgInvalidCredentials)
 		return
 	}
 
 	// Log success with context information automatically included
 	h.logger.Info(ctx, "User logged in successfully", logger.String("email", req.Email))
-	h.responseHelper.Success(c, response, "Login successful")
+	h.responseHelper.Success(c, response, middleware.MsgLoginSuccessful)
 }
 
 // Refresh handles token refresh
@@ -129,7 +152,7 @@ func (h *AuthHandler) Refresh(c *gin.Context) {
 	}
 
 	h.logger.Info(ctx, "Token refreshed successfully")
-	h.responseHelper.Success(c, response, "Token refreshed successfully")
+	h.responseHelper.Success(c, response, middleware.MsgTokenRefreshed)
 }
 
 // Logout handles user logout (token invalidation would be handled by token blacklist in production)
@@ -141,7 +164,7 @@ func (h *AuthHandler) Logout(c *gin.Context) {
 	// Token invalidation can be implemented with a token blacklist or by reducing token lifetime
 
 	h.logger.Info(ctx, "User logged out successfully")
-	h.responseHelper.Success(c, gin.H{"message": "Logged out successfully"}, "Logged out successfully")
+	h.responseHelper.Success(c, gin.H{"message": "Logged out successfully"}, middleware.MsgLoggedOut)
 }
 
 // Validate handles token validation
@@ -151,13 +174,13 @@ func (h *AuthHandler) Validate(c *gin.Context) {
 	// Get token from Authorization header
 	authHeader := c.GetHeader("Authorization")
 	if authHeader == "" {
-		h.responseHelper.Unauthorized(c, gin.H{"error": "Authorization header required"}, "Authorization required")
+		h.responseHelper.Unauthorized(c, gin.H{"error": "Authorization header required"}, middleware.MsgAuthorizationRequired)
 		return
 	}
 
 	// Check if header starts with "Bearer "
 	if !strings.HasPrefix(authHeader, "Bearer ") {
-		h.responseHelper.Unauthorized(c, gin.H{"error": "Invalid authorization header format"}, "Invalid authorization header")
+		h.responseHelper.Unauthorized(c, gin.H{"error": "Invalid authorization header format"}, middleware.MsgInvalidAuthorizationHeader)
 		return
 	}
 
@@ -168,10 +191,42 @@ func (h *AuthHandler) Validate(c *gin.Context) {
 	userID, err := h.authService.ValidateToken(c.Request.Context(), tokenString)
 	if err != nil {
 		h.logger.Error(ctx, "Token validation failed", logger.Error(err))
-		h.responseHelper.Unauthorized(c, gin.H{"error": "Invalid token"}, "Invalid token")
+		h.responseHelper.Unauthorized(c, gin.H{"error": "Invalid token"}, middleware.MsgInvalidToken)
 		return
 	}
 
 	h.logger.Info(ctx, "Token validated successfully", logger.String("userID", userID))
-	h.responseHelper.Success(c, gin.H{"valid": true, "userID": userID}, "Token is valid")
+	h.responseHelper.Success(c, gin.H{"valid": true, "userID": userID}, middleware.MsgTokenValid)
+}
+
+// fieldErrors describes each failed field of a validation error by its JSON name, e.g. {"email": "must be a valid email address"}
+func (h *AuthHandler) fieldErrors(err error) map[string]string {
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		return nil
+	}
+
+	fields := make(map[string]string, len(validationErrors))
+	for _, fieldErr := range validationErrors {
+		switch fieldErr.Tag() {
+		case "required":
+			fields[fieldErr.Field()] = "is required"
+		case "email":
+			fields[fieldErr.Field()] = "must be a valid email address"
+		case "password":
+			fields[fieldErr.Field()] = fmt.Sprintf("must be at least %d characters", authValidator.MinPasswordLength(h.config.MinPasswordLength))
+		default:
+			fields[fieldErr.Field()] = fmt.Sprintf("failed the %s check", fieldErr.Tag())
+		}
+	}
+	return fields
+}
+
+// jsonFieldName names struct fields in validation errors by their JSON key
+func jsonFieldName(field reflect.StructField) string {
+	name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+	if name == "" || name == "-" {
+		return field.Name
+	}
+	return name
 }
@@ -0,0 +1,128 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"nutrient_be/internal/config"
+	"nutrient_be/internal/domain"
+	"nutrient_be/internal/handler/middleware"
+	"nutrient_be/internal/pkg/jwtkeys"
+	"nutrient_be/internal/pkg/logger"
+	"nutrient_be/internal/service"
+)
+
+// registrationUserRepo stores users by email and embeds the interface so only registration needs implementing
+type registrationUserRepo struct {
+	service.UserRepository
+	users map[string]*domain.User
+}
+
+func (r *registrationUserRepo) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
+	if user, ok := r.users[email]; ok {
+		return user, nil
+	}
+	return nil, fmt.Errorf("user not found")
+}
+
+func (r *registrationUserRepo) Create(ctx context.Context, user *domain.User) error {
+	r.users[user.Email] = user
+	return nil
+}
+
+func newRegisterRouter() *gin.Engine {
+	return newRegisterRouterWithMinPassword(6)
+}
+
+func newRegisterRouterWithMinPassword(minPasswordLength int) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	log := logger.NewNoopLogger()
+	authCfg := config.AuthConfig{JWTSecret: "test-secret", JWTExpiration: time.Hour, RefreshExpiration: 24 * time.Hour, MinPasswordLength: minPasswordLength}
+	keys := jwtkeys.NewHS256Keys(authCfg.JWTSecret)
+	repo := &registrationUserRepo{users: make(map[string]*domain.User)}
+	handler := NewAuthHandler(service.NewAuthService(repo, keys, authCfg, config.UserConfig{}, log), log, authCfg, keys)
+
+	router := gin.New()
+	router.Use(middleware.ResponseMiddleware(log))
+	router.POST("/auth/register", handler.Register)
+	return router
+}
+
+func register(router *gin.Engine, body string) (*httptest.ResponseRecorder, middleware.ResponseFormat) {
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/auth/register", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(rec, req)
+
+	var resp middleware.ResponseFormat
+	_ = json.Unmarshal(rec.Body.Bytes(), &resp)
+	return rec, resp
+}
+
+func TestAuthHandler_Register_DuplicateEmailErrorCode(t *testing.T) {
+	router := newRegisterRouter()
+	body := `{"email":"user@example.com","password":"secret123"}`
+
+	rec, resp := register(router, body)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got: %d", rec.Code)
+	}
+	if resp.ErrorCode != "" {
+		t.Errorf("Expected no error code on success, got: %q", resp.ErrorCode)
+	}
+
+	rec, resp = register(router, body)
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("Expected status 409, got: %d", rec.Code)
+	}
+	if resp.ErrorCode != middleware.ErrorCodeDuplicateEmail {
+		t.Errorf("Expected error code %s, got: %q", middleware.ErrorCodeDuplicateEmail, resp.ErrorCode)
+	}
+}
+
+func TestAuthHandler_Register_ValidationErrorCode(t *testing.T) {
+	rec, resp := register(newRegisterRouter(), `{"email":"not-an-email","password":"secret123"}`)
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("Expected status 422, got: %d", rec.Code)
+	}
+	if resp.ErrorCode != middleware.ErrorCodeValidationFailed {
+		t.Errorf("Expected error code %s, got: %q", middleware.ErrorCodeValidationFailed, resp.ErrorCode)
+	}
+}
+
+func TestAuthHandler_Register_EnforcesConfiguredPasswordLength(t *testing.T) {
+	router := newRegisterRouterWithMinPassword(10)
+
+	rec, resp := register(router, `{"email":"user@example.com","password":"secret123"}`)
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("Expected status 422 for a 9 character password, got: %d", rec.Code)
+	}
+	details, _ := resp.Error.(map[string]interface{})
+	fields, _ := details["fields"].(map[string]interface{})
+	if fields["password"] != "must be at least 10 characters" {
+		t.Errorf("Expected a password field error, got: %v", resp.Error)
+	}
+
+	if rec, _ := register(router, `{"email":"user@example.com","password":"secret1234"}`); rec.Code != http.StatusCreated {
+		t.Errorf("Expected status 201 for a 10 character password, got: %d", rec.Code)
+	}
+}
+
+func TestAuthHandler_Register_MixedCaseEmailIsDuplicate(t *testing.T) {
+	router := newRegisterRouter()
+
+	if rec, _ := register(router, `{"email":"User@Example.com","password":"secret123"}`); rec.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got: %d", rec.Code)
+	}
+	if rec, _ := register(router, `{"email":" user@example.COM ","password":"secret123"}`); rec.Code != http.StatusConflict {
+		t.Errorf("Expected status 409 for the same email in another case, got: %d", rec.Code)
+	}
+}
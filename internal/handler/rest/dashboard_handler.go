@@ -0,0 +1,94 @@
+package rest
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"nutrient_be/internal/handler/middleware"
+	"nutrient_be/internal/pkg/logger"
+	"nutrient_be/internal/service"
+)
+
+// DashboardHandler handles the dashboard endpoint
+type DashboardHandler struct {
+	dashboardService *service.DashboardService
+	logger           logger.Logger
+	responseHelper   *middleware.ResponseHelper
+}
+
+// NewDashboardHandler creates a new dashboard handler
+func NewDashboardHandler(dashboardService *service.DashboardService, log logger.Logger) *DashboardHandler {
+	return &DashboardHandler{
+		dashboardService: dashboardService,
+		logger:           log,
+		responseHelper:   middleware.NewResponseHelper(),
+	}
+}
+
+// getUserIDFromContext extracts user ID from context or returns error response
+// Returns userID and true if successful, false if error response was sent
+func (h *DashboardHandler) getUserIDFromContext(c *gin.Context, ctx context.Context) (string, bool) {
+	userIDStr, exists := middleware.GetUserIDFromContext(c)
+	if !exists {
+		h.logger.Error(ctx, "User ID not found in context")
+		h.responseHelper.Unauthorized(c, gin.H{"error": "User not authenticated"}, middleware.MsgAuthenticationRequired)
+		return "", false
+	}
+	return userIDStr, true
+}
+
+// handleServiceError handles service errors and sends appropriate response
+// Returns true if error was handled, false if no error
+func (h *DashboardHandler) handleServiceError(c *gin.Context, ctx context.Context, err error, operation string) bool {
+	if err == nil {
+		return false
+	}
+
+	h.logger.Error(ctx, "Service operation failed", logger.String("operation", operation), logger.Error(err))
+
+	if handleDatabaseUnavailable(c, h.responseHelper, err) {
+		return true
+	}
+
+	if strings.HasPrefix(err.Error(), "user not found") {
+		h.responseHelper.ErrorCode(c, middleware.ErrorCodeUserNotFound)
+		h.responseHelper.NotFound(c, gin.H{"error": err.Error()}, middleware.MsgUserNotFound)
+		return true
+	}
+
+	h.responseHelper.InternalError(c, gin.H{"details": err.Error()}, middleware.MsgOperationFailed)
+	return true
+}
+
+// Get handles the dashboard summary
+// The "date" query param (YYYY-MM-DD, default today) selects the day whose calories are reported
+func (h *DashboardHandler) Get(c *gin.Context) {
+	ctx := middleware.GetContext(c)
+
+	userIDStr, ok := h.getUserIDFromContext(c, ctx)
+	if !ok {
+		return
+	}
+
+	date := time.Now().UTC()
+	if dateStr := c.Query("date"); dateStr != "" {
+		parsed, err := time.Parse("2006-01-02", dateStr)
+		if err != nil {
+			h.logger.Error(ctx, "Invalid date format", logger.Error(err))
+			h.responseHelper.BadRequest(c, gin.H{"error": "Date must be in YYYY-MM-DD format"}, middleware.MsgInvalidDate)
+			return
+		}
+		date = parsed
+	}
+
+	dashboard, err := h.dashboardService.Summary(ctx, userIDStr, date)
+	if h.handleServiceError(c, ctx, err, "get dashboard") {
+		return
+	}
+
+	h.logger.Info(ctx, "Dashboard retrieved successfully")
+	h.responseHelper.Success(c, dashboard, middleware.MsgDashboardRetrieved)
+}
@@ -1,32 +1,62 @@
 package rest
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
 
+	"nutrient_be/internal/config"
 	"nutrient_be/internal/domain"
 	"nutrient_be/internal/dto/request"
 	"nutrient_be/internal/dto/response"
 	"nutrient_be/internal/handler/middleware"
 	"nutrient_be/internal/pkg/logger"
+	foodValidator "nutrient_be/internal/pkg/validator"
 	"nutrient_be/internal/service"
 )
 
+// multipartOverhead is the room allowed for multipart headers and boundaries on top of the image size
+const multipartOverhead = 64 * 1024
+
+// allLanguages is the lang value that keeps every language of food names and descriptions
+const allLanguages = "all"
+
 // FoodHandler handles food-related endpoints
 type FoodHandler struct {
 	foodService     *service.FoodService
+	userService     *service.UserService
 	structValidator *validator.Validate
+	maxImageSize    int64
+	pagination      config.PaginationConfig
 	logger          logger.Logger
 	responseHelper  *middleware.ResponseHelper
 }
 
 // NewFoodHandler creates a new food handler
-func NewFoodHandler(foodService *service.FoodService, log logger.Logger) *FoodHandler {
+// Image uploads are read up to maxImageSize bytes; search and list page sizes come from pagination
+// userService provides the preferred language food names are returned in
+func NewFoodHandler(foodService *service.FoodService, userService *service.UserService, maxImageSize int64, pagination config.PaginationConfig, log logger.Logger) *FoodHandler {
+	structValidator := validator.New()
+	if err := foodValidator.RegisterFoodCategoryTag(structValidator); err != nil {
+		panic(fmt.Sprintf("failed to register food category validation: %v", err))
+	}
+	if err := foodValidator.RegisterFiniteTag(structValidator); err != nil {
+		panic(fmt.Sprintf("failed to register finite validation: %v", err))
+	}
+
 	return &FoodHandler{
 		foodService:     foodService,
-		structValidator: validator.New(),
+		userService:     userService,
+		structValidator: structValidator,
+		maxImageSize:    maxImageSize,
+		pagination:      pagination,
 		logger:          log,
 		responseHelper:  middleware.NewResponseHelper(),
 	}
@@ -40,7 +70,7 @@ func (h *FoodHandler) Create(c *gin.Context) {
 	userIDStr, exists := middleware.GetUserIDFromContext(c)
 	if !exists {
 		h.logger.Error(ctx, "User ID not found in context")
-		h.responseHelper.Unauthorized(c, gin.H{"error": "User not authenticated"}, "Authentication required")
+		h.responseHelper.Unauthorized(c, gin.H{"error": "User not authenticated"}, middleware.MsgAuthenticationRequired)
 		return
 	}
 
@@ -48,26 +78,30 @@ func (h *FoodHandler) Create(c *gin.Context) {
 	var req request.CreateFoodRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		h.logger.Error(ctx, "Failed to bind create food request", logger.Error(err))
-		h.responseHelper.BadRequest(c, gin.H{"details": err.Error()}, "Invalid request body")
+		h.responseHelper.BadRequest(c, gin.H{"details": err.Error()}, middleware.MsgInvalidRequestBody)
 		return
 	}
 
 	// Format/structure validation (struct tags)
 	if err := h.structValidator.Struct(&req); err != nil {
 		h.logger.Error(ctx, "Food request validation failed", logger.Error(err))
-		h.responseHelper.ValidationError(c, gin.H{"validation_errors": err.Error()}, "Validation failed")
+		h.responseHelper.ValidationError(c, gin.H{"validation_errors": err.Error()}, middleware.MsgValidationFailed)
 		return
 	}
 
 	// Call service - use enriched context for consistent logging and context propagation
-	if err := h.foodService.CreateFood(ctx, userIDStr, &req); err != nil {
+	warnings, err := h.foodService.CreateFood(ctx, userIDStr, &req)
+	if err != nil {
 		h.logger.Error(ctx, "Failed to create food", logger.Error(err))
-		h.responseHelper.InternalError(c, gin.H{"details": err.Error()}, "Failed to create food")
+		h.responseHelper.InternalError(c, gin.H{"details": err.Error()}, middleware.MsgFoodCreateFailed)
 		return
 	}
 
-	h.logger.Info(ctx, "Food created successfully")
-	h.responseHelper.Created(c, gin.H{"message": "Food created successfully"}, "Food created successfully")
+	h.logger.Info(ctx, "Food created successfully", logger.Int("warnings", len(warnings)))
+	h.responseHelper.Created(c, gin.H{
+		"message":  "Food created successfully",
+		"warnings": foodValidationIssuesToResponse(warnings),
+	}, middleware.MsgFoodCreated)
 }
 
 // Search handles food search
@@ -76,15 +110,31 @@ func (h *FoodHandler) Search(c *gin.Context) {
 	ctx := middleware.GetContext(c)
 	if err := c.ShouldBindQuery(&req); err != nil {
 		h.logger.Error(ctx, "Failed to bind search food request", logger.Error(err))
-		h.responseHelper.BadRequest(c, gin.H{"details": err.Error()}, "Invalid request body")
+		h.responseHelper.BadRequest(c, gin.H{"details": err.Error()}, middleware.MsgInvalidRequestBody)
+		return
+	}
+	if err := h.structValidator.Struct(&req); err != nil {
+		h.logger.Error(ctx, "Search request validation failed", logger.Error(err))
+		h.responseHelper.BadRequest(c, gin.H{"validation_errors": err.Error()}, middleware.MsgInvalidSearchParameters)
+		return
+	}
+
+	limit, offset, err := parsePagination(c, h.pagination.FoodSearch)
+	if err != nil {
+		h.logger.Error(ctx, "Invalid pagination", logger.Error(err))
+		h.responseHelper.BadRequest(c, gin.H{"error": err.Error()}, middleware.MsgInvalidPagination)
+		return
+	}
+	req.Limit, req.Offset = limit, offset
+
+	lang, ok := h.responseLanguage(c, ctx)
+	if !ok {
 		return
 	}
 
 	// Call service - service will do business logic validation
 	foods, err := h.foodService.SearchFood(ctx, &req)
-	if err != nil {
-		h.logger.Error(ctx, "Failed to search food", logger.Error(err))
-		h.responseHelper.InternalError(c, gin.H{"details": err.Error()}, "Failed to search food")
+	if h.handleServiceError(c, ctx, err, "search food") {
 		return
 	}
 	h.logger.Info(ctx, "Food search successful asdasda")
@@ -92,11 +142,11 @@ func (h *FoodHandler) Search(c *gin.Context) {
 	// Convert domain entities to response DTOs
 	foodResponses := make([]response.FoodItemResponse, len(foods))
 	for i, food := range foods {
-		foodResponses[i] = foodItemToResponse(food)
+		foodResponses[i] = localizeFoodResponse(foodItemToResponse(food), lang)
 	}
 
 	h.logger.Info(ctx, "Food search successful")
-	h.responseHelper.Success(c, foodResponses, "Food search successful")
+	h.responseHelper.Success(c, foodResponses, middleware.MsgFoodSearched)
 }
 
 // Get handles getting a food item
@@ -107,7 +157,12 @@ func (h *FoodHandler) Get(c *gin.Context) {
 	// Validate food ID format
 	if foodID == "" {
 		h.logger.Error(ctx, "Food ID is required")
-		h.responseHelper.BadRequest(c, gin.H{"error": "Food ID is required"}, "Food ID is required")
+		h.responseHelper.BadRequest(c, gin.H{"error": "Food ID is required"}, middleware.MsgFoodIDRequired)
+		return
+	}
+
+	lang, ok := h.responseLanguage(c, ctx)
+	if !ok {
 		return
 	}
 
@@ -117,28 +172,465 @@ func (h *FoodHandler) Get(c *gin.Context) {
 		h.logger.Error(ctx, "Failed to get food by ID", logger.Error(err))
 		// Check if it's a not found error
 		if err.Error() == "food item not found" {
-			h.responseHelper.NotFound(c, gin.H{"error": "Food item not found"}, "Food item not found")
+			h.responseHelper.ErrorCode(c, middleware.ErrorCodeFoodNotFound)
+			h.responseHelper.NotFound(c, gin.H{"error": "Food item not found"}, middleware.MsgFoodNotFound)
 			return
 		}
-		h.responseHelper.InternalError(c, gin.H{"details": err.Error()}, "Failed to get food")
+		h.responseHelper.InternalError(c, gin.H{"details": err.Error()}, middleware.MsgFoodGetFailed)
 		return
 	}
 
 	// Convert domain entity to response DTO
-	foodResponse := foodItemToResponse(food)
+	foodResponse := localizeFoodResponse(foodItemToResponse(food), lang)
 
 	h.logger.Info(ctx, "Food retrieved successfully")
-	h.responseHelper.Success(c, foodResponse, "Food retrieved successfully")
+	h.responseHelper.Success(c, foodResponse, middleware.MsgFoodRetrieved)
+}
+
+// Breakdown handles reporting the share of a food's calories coming from each macro
+func (h *FoodHandler) Breakdown(c *gin.Context) {
+	ctx := middleware.GetContext(c)
+
+	userIDStr, exists := middleware.GetUserIDFromContext(c)
+	if !exists {
+		h.logger.Error(ctx, "User ID not found in context")
+		h.responseHelper.Unauthorized(c, gin.H{"error": "User not authenticated"}, middleware.MsgAuthenticationRequired)
+		return
+	}
+
+	breakdown, err := h.foodService.MacroBreakdown(ctx, userIDStr, c.Param("id"))
+	if h.handleServiceError(c, ctx, err, "food macro breakdown") {
+		return
+	}
+
+	h.responseHelper.Success(c, response.FoodMacroBreakdownResponse{
+		FoodID:               breakdown.FoodID,
+		Calories:             response.RoundCalories(breakdown.Calories),
+		MacroCalories:        response.RoundCalories(breakdown.MacroCalories),
+		ProteinPercent:       breakdown.ProteinPercent,
+		CarbohydratesPercent: breakdown.CarbohydratesPercent,
+		FatPercent:           breakdown.FatPercent,
+		FiberCalories:        response.RoundCalories(breakdown.FiberCalories),
+		FiberPercent:         breakdown.FiberPercent,
+		CaloriesMismatch:     breakdown.CaloriesMismatch,
+	}, middleware.MsgFoodBreakdownRetrieved)
+}
+
+// List handles listing public food items
+func (h *FoodHandler) List(c *gin.Context) {
+	ctx := middleware.GetContext(c)
+
+	// Get and validate query parameters
+	limit, offset, err := parsePagination(c, h.pagination.FoodList)
+	if err != nil {
+		h.logger.Error(ctx, "Invalid pagination", logger.Error(err))
+		h.responseHelper.BadRequest(c, gin.H{"error": err.Error()}, middleware.MsgInvalidPagination)
+		return
+	}
+
+	lang, ok := h.responseLanguage(c, ctx)
+	if !ok {
+		return
+	}
+
+	foods, err := h.foodService.ListPublicFoods(ctx, limit, offset)
+	if h.handleServiceError(c, ctx, err, "list public foods") {
+		return
+	}
+
+	foodResponses := make([]response.FoodItemResponse, len(foods))
+	for i, food := range foods {
+		foodResponses[i] = localizeFoodResponse(foodItemToResponse(food), lang)
+	}
+
+	h.logger.Info(ctx, "Public foods listed successfully")
+	h.responseHelper.Success(c, foodResponses, middleware.MsgPublicFoodsListed)
 }
 
 // Update handles food update
 func (h *FoodHandler) Update(c *gin.Context) {
-	c.JSON(http.StatusNotImplemented, gin.H{"message": "Food update not implemented yet"})
+	ctx := middleware.GetContext(c)
+
+	userIDStr, exists := middleware.GetUserIDFromContext(c)
+	if !exists {
+		h.logger.Error(ctx, "User ID not found in context")
+		h.responseHelper.Unauthorized(c, gin.H{"error": "User not authenticated"}, middleware.MsgAuthenticationRequired)
+		return
+	}
+
+	var req request.UpdateFoodRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error(ctx, "Failed to bind update food request", logger.Error(err))
+		h.responseHelper.BadRequest(c, gin.H{"details": err.Error()}, middleware.MsgInvalidRequestBody)
+		return
+	}
+
+	if err := h.structValidator.Struct(&req); err != nil {
+		h.logger.Error(ctx, "Food request validation failed", logger.Error(err))
+		h.responseHelper.ValidationError(c, gin.H{"validation_errors": err.Error()}, middleware.MsgValidationFailed)
+		return
+	}
+
+	food, warnings, err := h.foodService.UpdateFood(ctx, userIDStr, c.Param("id"), &req)
+	if h.handleServiceError(c, ctx, err, "update food") {
+		return
+	}
+
+	h.logger.Info(ctx, "Food updated successfully", logger.Int("warnings", len(warnings)))
+	foodResponse := foodItemToResponse(food)
+	foodResponse.Warnings = foodValidationIssuesToResponse(warnings)
+	h.responseHelper.Success(c, foodResponse, middleware.MsgFoodUpdated)
+}
+
+// Clone handles copying a food into the user's own catalog as a new private food
+// The JSON body with a name suffix is optional
+func (h *FoodHandler) Clone(c *gin.Context) {
+	ctx := middleware.GetContext(c)
+
+	userIDStr, exists := middleware.GetUserIDFromContext(c)
+	if !exists {
+		h.logger.Error(ctx, "User ID not found in context")
+		h.responseHelper.Unauthorized(c, gin.H{"error": "User not authenticated"}, middleware.MsgAuthenticationRequired)
+		return
+	}
+
+	var req request.CloneFoodRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		h.logger.Error(ctx, "Failed to bind clone food request", logger.Error(err))
+		h.responseHelper.BadRequest(c, gin.H{"details": err.Error()}, middleware.MsgInvalidRequestBody)
+		return
+	}
+
+	if err := h.structValidator.Struct(&req); err != nil {
+		h.logger.Error(ctx, "Clone food request validation failed", logger.Error(err))
+		h.responseHelper.ValidationError(c, gin.H{"validation_errors": err.Error()}, middleware.MsgValidationFailed)
+		return
+	}
+
+	food, err := h.foodService.CloneFood(ctx, userIDStr, c.Param("id"), req.NameSuffix)
+	if h.handleServiceError(c, ctx, err, "clone food") {
+		return
+	}
+
+	h.logger.Info(ctx, "Food cloned successfully")
+	h.responseHelper.Created(c, foodItemToResponse(food), middleware.MsgFoodCloned)
+}
+
+// BulkSetVisibility handles setting the visibility of every food of the user matching a filter
+func (h *FoodHandler) BulkSetVisibility(c *gin.Context) {
+	ctx := middleware.GetContext(c)
+
+	userIDStr, exists := middleware.GetUserIDFromContext(c)
+	if !exists {
+		h.logger.Error(ctx, "User ID not found in context")
+		h.responseHelper.Unauthorized(c, gin.H{"error": "User not authenticated"}, middleware.MsgAuthenticationRequired)
+		return
+	}
+
+	var req request.BulkSetFoodVisibilityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error(ctx, "Failed to bind bulk visibility request", logger.Error(err))
+		h.responseHelper.BadRequest(c, gin.H{"details": err.Error()}, middleware.MsgInvalidRequestBody)
+		return
+	}
+
+	if err := h.structValidator.Struct(&req); err != nil {
+		h.logger.Error(ctx, "Bulk visibility request validation failed", logger.Error(err))
+		h.responseHelper.ValidationError(c, gin.H{"validation_errors": err.Error()}, middleware.MsgValidationFailed)
+		return
+	}
+
+	updated, err := h.foodService.BulkSetVisibility(ctx, userIDStr, &req)
+	if h.handleServiceError(c, ctx, err, "bulk set food visibility") {
+		return
+	}
+
+	h.logger.Info(ctx, "Food visibility bulk updated successfully", logger.Int("updated", int(updated)))
+	h.responseHelper.Success(c, response.BulkSetFoodVisibilityResponse{Updated: updated}, middleware.MsgFoodVisibilityUpdated)
+}
+
+// EstimateCalories handles computing calories from macros without saving anything
+func (h *FoodHandler) EstimateCalories(c *gin.Context) {
+	ctx := middleware.GetContext(c)
+
+	var req request.EstimateCaloriesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error(ctx, "Failed to bind estimate calories request", logger.Error(err))
+		h.responseHelper.BadRequest(c, gin.H{"details": err.Error()}, middleware.MsgInvalidRequestBody)
+		return
+	}
+
+	if err := h.structValidator.Struct(&req); err != nil {
+		h.logger.Error(ctx, "Estimate calories request validation failed", logger.Error(err))
+		h.responseHelper.ValidationError(c, gin.H{"validation_errors": err.Error()}, middleware.MsgValidationFailed)
+		return
+	}
+
+	calories := h.foodService.EstimateCalories(req.Macros)
+	h.responseHelper.Success(c, response.CalorieEstimateResponse{Calories: response.RoundCalories(calories)}, middleware.MsgCaloriesEstimated)
+}
+
+// Validate handles checking a food request against the creation rules without saving it
+func (h *FoodHandler) Validate(c *gin.Context) {
+	ctx := middleware.GetContext(c)
+
+	var req request.CreateFoodRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error(ctx, "Failed to bind validate food request", logger.Error(err))
+		h.responseHelper.BadRequest(c, gin.H{"details": err.Error()}, middleware.MsgInvalidRequestBody)
+		return
+	}
+
+	// Format errors come back the same way as on creation, so forms can share the handling
+	if err := h.structValidator.Struct(&req); err != nil {
+		h.logger.Error(ctx, "Food request validation failed", logger.Error(err))
+		h.responseHelper.ValidationError(c, gin.H{"validation_errors": err.Error()}, middleware.MsgValidationFailed)
+		return
+	}
+
+	report := h.foodService.ValidateFood(ctx, &req)
+	h.responseHelper.Success(c, response.FoodValidationResponse{
+		Valid:    report.Valid,
+		Errors:   foodValidationIssuesToResponse(report.Errors),
+		Warnings: foodValidationIssuesToResponse(report.Warnings),
+		Calories: response.FoodCaloriesCheckResponse{
+			Stated:    report.Calories,
+			Expected:  response.RoundCalories(report.ExpectedCalories),
+			Delta:     response.RoundCalories(report.CaloriesDelta),
+			Tolerance: report.CaloriesTolerance,
+		},
+	}, middleware.MsgFoodValidated)
+}
+
+// foodValidationIssuesToResponse converts validation errors or warnings to their response form
+func foodValidationIssuesToResponse(issues []foodValidator.FoodValidationIssue) []response.FoodValidationIssueResponse {
+	converted := make([]response.FoodValidationIssueResponse, len(issues))
+	for i, issue := range issues {
+		converted[i] = response.FoodValidationIssueResponse{Code: issue.Code, Message: issue.Message}
+	}
+	return converted
 }
 
 // Delete handles food deletion
 func (h *FoodHandler) Delete(c *gin.Context) {
-	c.JSON(http.StatusNotImplemented, gin.H{"message": "Food deletion not implemented yet"})
+	ctx := middleware.GetContext(c)
+
+	userIDStr, exists := middleware.GetUserIDFromContext(c)
+	if !exists {
+		h.logger.Error(ctx, "User ID not found in context")
+		h.responseHelper.Unauthorized(c, gin.H{"error": "User not authenticated"}, middleware.MsgAuthenticationRequired)
+		return
+	}
+
+	err := h.foodService.DeleteFood(ctx, userIDStr, c.Param("id"))
+	if h.handleServiceError(c, ctx, err, "delete food") {
+		return
+	}
+
+	h.logger.Info(ctx, "Food deleted successfully")
+	h.responseHelper.Success(c, gin.H{"message": "Food deleted successfully"}, middleware.MsgFoodDeleted)
+}
+
+// Merge handles merging a duplicate food into another (admin only)
+func (h *FoodHandler) Merge(c *gin.Context) {
+	ctx := middleware.GetContext(c)
+
+	adminID, exists := middleware.GetUserIDFromContext(c)
+	if !exists {
+		h.logger.Error(ctx, "User ID not found in context")
+		h.responseHelper.Unauthorized(c, gin.H{"error": "User not authenticated"}, middleware.MsgAuthenticationRequired)
+		return
+	}
+
+	var req request.MergeFoodsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error(ctx, "Failed to bind merge foods request", logger.Error(err))
+		h.responseHelper.BadRequest(c, gin.H{"details": err.Error()}, middleware.MsgInvalidRequestBody)
+		return
+	}
+
+	if err := h.structValidator.Struct(&req); err != nil {
+		h.logger.Error(ctx, "Merge foods request validation failed", logger.Error(err))
+		h.responseHelper.ValidationError(c, gin.H{"validation_errors": err.Error()}, middleware.MsgValidationFailed)
+		return
+	}
+
+	result, err := h.foodService.Merge(ctx, adminID, req.KeepID, req.MergeID)
+	if h.handleServiceError(c, ctx, err, "merge foods") {
+		return
+	}
+
+	h.logger.Info(ctx, "Foods merged successfully")
+	h.responseHelper.Success(c, response.FoodMergeResponse{
+		KeptFoodID:       result.KeptFoodID,
+		MergedFoodID:     result.MergedFoodID,
+		TemplatesUpdated: result.TemplatesUpdated,
+		MealPlansUpdated: result.MealPlansUpdated,
+	}, middleware.MsgFoodsMerged)
+}
+
+// RecalculateCalories handles recomputing every food's stored calories from its macros (admin only)
+// With the "dryRun=true" query param the changes are reported without being saved
+func (h *FoodHandler) RecalculateCalories(c *gin.Context) {
+	ctx := middleware.GetContext(c)
+
+	adminID, exists := middleware.GetUserIDFromContext(c)
+	if !exists {
+		h.logger.Error(ctx, "User ID not found in context")
+		h.responseHelper.Unauthorized(c, gin.H{"error": "User not authenticated"}, middleware.MsgAuthenticationRequired)
+		return
+	}
+
+	dryRun := false
+	if raw := c.Query("dryRun"); raw != "" {
+		parsed, err := strconv.ParseBool(raw)
+		if err != nil {
+			h.logger.Error(ctx, "Invalid dryRun parameter", logger.Error(err))
+			h.responseHelper.BadRequest(c, gin.H{"error": "dryRun must be true or false"}, middleware.MsgInvalidRequest)
+			return
+		}
+		dryRun = parsed
+	}
+
+	h.logger.Info(ctx, "Recalculating food calories", logger.String("admin_id", adminID))
+	result, err := h.foodService.RecalculateAllCalories(ctx, dryRun)
+	if h.handleServiceError(c, ctx, err, "recalculate food calories") {
+		return
+	}
+
+	changes := make([]response.FoodCalorieChangeResponse, len(result.Changes))
+	for i, change := range result.Changes {
+		changes[i] = response.FoodCalorieChangeResponse{
+			FoodID:      change.FoodID,
+			OldCalories: change.OldCalories,
+			NewCalories: change.NewCalories,
+		}
+	}
+
+	h.logger.Info(ctx, "Food calories recalculated successfully")
+	h.responseHelper.Success(c, response.CalorieRecalculationResponse{
+		DryRun:        result.DryRun,
+		Scanned:       result.Scanned,
+		Changed:       result.Changed,
+		TotalDelta:    response.RoundCalories(result.TotalDelta),
+		MaxAbsDelta:   response.RoundCalories(result.MaxAbsDelta),
+		Changes:       changes,
+		ChangesCapped: result.ChangesCapped,
+	}, middleware.MsgFoodCaloriesRecalculated)
+}
+
+// handleServiceError handles service errors and sends appropriate response
+// Returns true if error was handled, false if no error
+func (h *FoodHandler) handleServiceError(c *gin.Context, ctx context.Context, err error, operation string) bool {
+	if err == nil {
+		return false
+	}
+
+	h.logger.Error(ctx, "Service operation failed", logger.String("operation", operation), logger.Error(err))
+
+	if handleDatabaseUnavailable(c, h.responseHelper, err) {
+		return true
+	}
+
+	errMsg := err.Error()
+	if errMsg == "food item not found or access denied" {
+		h.responseHelper.ErrorCode(c, middleware.ErrorCodeFoodNotFound)
+		h.responseHelper.NotFound(c, gin.H{"error": "Food item not found"}, middleware.MsgFoodNotFound)
+		return true
+	}
+	if strings.HasPrefix(errMsg, "validation failed:") || strings.HasPrefix(errMsg, "invalid ") {
+		if strings.HasPrefix(errMsg, "validation failed:") {
+			h.responseHelper.ErrorCode(c, middleware.ErrorCodeValidationFailed)
+		}
+		h.responseHelper.BadRequest(c, gin.H{"details": errMsg}, middleware.MsgInvalidRequest)
+		return true
+	}
+
+	h.responseHelper.InternalError(c, gin.H{"details": errMsg}, middleware.MsgOperationFailed)
+	return true
+}
+
+// UploadImage handles uploading a food image as the multipart "image" field
+func (h *FoodHandler) UploadImage(c *gin.Context) {
+	ctx := middleware.GetContext(c)
+
+	userIDStr, exists := middleware.GetUserIDFromContext(c)
+	if !exists {
+		h.logger.Error(ctx, "User ID not found in context")
+		h.responseHelper.Unauthorized(c, gin.H{"error": "User not authenticated"}, middleware.MsgAuthenticationRequired)
+		return
+	}
+
+	// Bound the whole request so oversized uploads are not buffered to disk
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.maxImageSize+multipartOverhead)
+
+	fileHeader, err := c.FormFile("image")
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		h.logger.Warn(ctx, "Rejected oversized image upload", logger.Int64("limit", maxBytesErr.Limit))
+		h.responseHelper.PayloadTooLarge(c, gin.H{"details": err.Error()}, middleware.MsgImageTooLarge)
+		return
+	}
+	if err != nil {
+		h.logger.Error(ctx, "Failed to read image upload", logger.Error(err))
+		h.responseHelper.BadRequest(c, gin.H{"details": err.Error()}, middleware.MsgImageFileRequired)
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		h.logger.Error(ctx, "Failed to open image upload", logger.Error(err))
+		h.responseHelper.BadRequest(c, gin.H{"details": err.Error()}, middleware.MsgInvalidImageUpload)
+		return
+	}
+	defer file.Close()
+
+	// Read one byte past the limit so the service can reject oversized files without loading them whole
+	data, err := io.ReadAll(io.LimitReader(file, h.maxImageSize+1))
+	if err != nil {
+		h.logger.Error(ctx, "Failed to read image upload", logger.Error(err))
+		h.responseHelper.BadRequest(c, gin.H{"details": err.Error()}, middleware.MsgInvalidImageUpload)
+		return
+	}
+
+	food, err := h.foodService.UploadImage(ctx, userIDStr, c.Param("id"), data)
+	if errors.Is(err, service.ErrImageTooLarge) {
+		h.logger.Warn(ctx, "Rejected oversized image upload", logger.Error(err))
+		h.responseHelper.PayloadTooLarge(c, gin.H{"details": err.Error()}, middleware.MsgImageTooLarge)
+		return
+	}
+	if h.handleServiceError(c, ctx, err, "upload food image") {
+		return
+	}
+
+	h.logger.Info(ctx, "Food image uploaded successfully")
+	h.responseHelper.Success(c, foodItemToResponse(food), middleware.MsgFoodImageUploaded)
+}
+
+// GetImage serves the uploaded image of a food the user can see
+func (h *FoodHandler) GetImage(c *gin.Context) {
+	ctx := middleware.GetContext(c)
+
+	userIDStr, exists := middleware.GetUserIDFromContext(c)
+	if !exists {
+		h.logger.Error(ctx, "User ID not found in context")
+		h.responseHelper.Unauthorized(c, gin.H{"error": "User not authenticated"}, middleware.MsgAuthenticationRequired)
+		return
+	}
+
+	data, contentType, err := h.foodService.GetImage(ctx, userIDStr, c.Param("id"))
+	if err != nil && err.Error() == "food image not found" {
+		h.logger.Warn(ctx, "Food has no image", logger.String("food_id", c.Param("id")))
+		h.responseHelper.NotFound(c, gin.H{"error": "Food image not found"}, middleware.MsgFoodImageNotFound)
+		return
+	}
+	if h.handleServiceError(c, ctx, err, "get food image") {
+		return
+	}
+
+	// Private images must not be kept by shared caches
+	c.Header("Cache-Control", "private, max-age=300")
+	c.Data(http.StatusOK, contentType, data)
 }
 
 // ImportExcel handles Excel import
@@ -146,6 +638,61 @@ func (h *FoodHandler) ImportExcel(c *gin.Context) {
 	c.JSON(http.StatusNotImplemented, gin.H{"message": "Excel import not implemented yet"})
 }
 
+// responseLanguage resolves the language food names and descriptions are returned in
+// The lang query parameter wins over the user's preferred language; an empty result keeps every language
+// Returns false if an error response was sent
+func (h *FoodHandler) responseLanguage(c *gin.Context, ctx context.Context) (string, bool) {
+	lang := strings.ToLower(strings.TrimSpace(c.Query("lang")))
+	if lang == allLanguages {
+		return "", true
+	}
+	if lang != "" {
+		if !request.IsSupportedLanguage(lang) {
+			h.logger.Error(ctx, "Unsupported response language", logger.String("lang", lang))
+			h.responseHelper.BadRequest(c, gin.H{"error": fmt.Sprintf("unsupported language: %s", lang)}, middleware.MsgInvalidLanguage)
+			return "", false
+		}
+		return lang, true
+	}
+
+	userIDStr, exists := middleware.GetUserIDFromContext(c)
+	if !exists {
+		return "", true
+	}
+	profile, err := h.userService.GetProfile(ctx, userIDStr)
+	if err != nil {
+		// Falling back to every language keeps the food readable, so this is not worth failing the request
+		h.logger.Warn(ctx, "Failed to load preferred language", logger.Error(err))
+		return "", true
+	}
+	if !request.IsSupportedLanguage(profile.Preferences.Language) {
+		return "", true
+	}
+	return profile.Preferences.Language, true
+}
+
+// localizeFoodResponse collapses the name and description of a food response to lang
+// An empty lang keeps every language
+func localizeFoodResponse(food response.FoodItemResponse, lang string) response.FoodItemResponse {
+	if lang == "" {
+		return food
+	}
+	food.Name = pickLanguage(food.Name, lang)
+	food.Description = pickLanguage(food.Description, lang)
+	return food
+}
+
+// pickLanguage keeps only the lang entry of a multi-language value, falling back to English
+// The entry stays keyed by the language actually returned; values with neither are left unchanged
+func pickLanguage(values map[string]string, lang string) map[string]string {
+	for _, candidate := range []string{lang, "en"} {
+		if value := values[candidate]; value != "" {
+			return map[string]string{candidate: value}
+		}
+	}
+	return values
+}
+
 // foodItemToResponse converts a domain FoodItem to a response FoodItemResponse
 func foodItemToResponse(food *domain.FoodItem) response.FoodItemResponse {
 	// Convert serving sizes
@@ -182,11 +729,13 @@ func foodItemToResponse(food *domain.FoodItem) response.FoodItemResponse {
 			Potassium: food.Micros.Potassium,
 		},
 		ServingSizes: servingSizes,
-		Calories:     food.Calories,
+		Calories:     response.RoundCalories(food.Calories),
 		CreatedBy:    food.CreatedBy.Hex(),
 		Visibility:   food.Visibility,
 		Source:       food.Source,
 		ImageURL:     food.ImageURL,
+		Allergens:    food.Allergens,
+		DietTags:     food.DietTags,
 		CreatedAt:    food.CreatedAt,
 		UpdatedAt:    food.UpdatedAt,
 	}
@@ -0,0 +1,269 @@
+package rest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"nutrient_be/internal/config"
+	"nutrient_be/internal/domain"
+	"nutrient_be/internal/dto/response"
+	"nutrient_be/internal/handler/middleware"
+	"nutrient_be/internal/pkg/cache"
+	"nutrient_be/internal/pkg/circuitbreaker"
+	"nutrient_be/internal/pkg/logger"
+	"nutrient_be/internal/service"
+)
+
+// searchResultFoodRepo returns the same foods for every search
+type searchResultFoodRepo struct {
+	service.FoodRepository
+	foods []*domain.FoodItem
+}
+
+func (r *searchResultFoodRepo) FindFoods(ctx context.Context, filter domain.FoodFilter, page domain.Page, sortBy string) ([]*domain.FoodItem, error) {
+	return r.foods, nil
+}
+
+func newLanguageRouter(preferredLanguage string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	log := logger.NewNoopLogger()
+	foods := &searchResultFoodRepo{foods: []*domain.FoodItem{
+		{
+			ID:          primitive.NewObjectID(),
+			Name:        map[string]string{"en": "Chicken breast", "vi": "Ức gà"},
+			Description: map[string]string{"en": "Skinless", "vi": "Không da"},
+		},
+		{
+			ID:   primitive.NewObjectID(),
+			Name: map[string]string{"en": "Quinoa"},
+		},
+	}}
+	user := &domain.User{ID: primitive.NewObjectID(), Preferences: domain.UserPreferences{Language: preferredLanguage}}
+	users := service.NewUserService(&profileUserRepo{user: user}, nil, nil, nil, nil, config.UserConfig{}, log)
+	handler := NewFoodHandler(service.NewFoodService(foods, nil, nil, service.NewNoopTransactor(), cache.NewNoopCache(), nil, 0, config.FoodConfig{}, log), users, 0, testPaginationConfig(), log)
+
+	router := gin.New()
+	router.Use(middleware.ResponseMiddleware(log))
+	router.Use(func(c *gin.Context) { c.Set("userID", user.ID.Hex()) })
+	router.GET("/foods/search", handler.Search)
+	router.POST("/foods/validate", handler.Validate)
+	return router
+}
+
+func searchFoodNames(t *testing.T, router *gin.Engine, path string) (int, []response.FoodItemResponse) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+
+	var body struct {
+		Data []response.FoodItemResponse `json:"data"`
+	}
+	if rec.Code == http.StatusOK {
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatalf("Expected a JSON body, got: %v", err)
+		}
+	}
+	return rec.Code, body.Data
+}
+
+func TestFoodSearch_CollapsesLanguage(t *testing.T) {
+	code, foods := searchFoodNames(t, newLanguageRouter("en"), "/foods/search?query=food&lang=vi")
+	if code != http.StatusOK {
+		t.Fatalf("Expected status 200, got: %d", code)
+	}
+	if len(foods) != 2 {
+		t.Fatalf("Expected 2 foods, got: %d", len(foods))
+	}
+
+	if len(foods[0].Name) != 1 || foods[0].Name["vi"] != "Ức gà" {
+		t.Errorf("Expected only the Vietnamese name, got: %v", foods[0].Name)
+	}
+	if len(foods[0].Description) != 1 || foods[0].Description["vi"] != "Không da" {
+		t.Errorf("Expected only the Vietnamese description, got: %v", foods[0].Description)
+	}
+	if len(foods[1].Name) != 1 || foods[1].Name["en"] != "Quinoa" {
+		t.Errorf("Expected the English name as fallback, got: %v", foods[1].Name)
+	}
+}
+
+func TestFoodSearch_LanguageDefaultsToPreference(t *testing.T) {
+	_, foods := searchFoodNames(t, newLanguageRouter("vi"), "/foods/search?query=food")
+	if len(foods) != 2 || len(foods[0].Name) != 1 || foods[0].Name["vi"] != "Ức gà" {
+		t.Errorf("Expected the preferred Vietnamese name, got: %v", foods)
+	}
+
+	_, foods = searchFoodNames(t, newLanguageRouter("vi"), "/foods/search?query=food&lang=all")
+	if len(foods) != 2 || len(foods[0].Name) != 2 {
+		t.Errorf("Expected every language with lang=all, got: %v", foods)
+	}
+
+	if code, _ := searchFoodNames(t, newLanguageRouter("vi"), "/foods/search?query=food&lang=fr"); code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for an unsupported language, got: %d", code)
+	}
+}
+
+func TestFoodItemToResponse_RoundsCalories(t *testing.T) {
+	defer response.SetCaloriePrecision(response.DefaultCaloriePrecision)
+	if err := response.SetCaloriePrecision(1); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	food := &domain.FoodItem{ID: primitive.NewObjectID(), Calories: 156.4000001}
+	got := foodItemToResponse(food)
+	if got.Calories != 156.4 {
+		t.Errorf("Expected calories 156.4, got: %v", got.Calories)
+	}
+	if food.Calories != 156.4000001 {
+		t.Errorf("Expected the stored value to keep full precision, got: %v", food.Calories)
+	}
+}
+
+func TestFoodSearch_LocalizesMessage(t *testing.T) {
+	tests := []struct {
+		acceptLanguage string
+		expected       string
+	}{
+		{"", "Food search successful"},
+		{"vi", "Tìm kiếm món ăn thành công"},
+		{"vi-VN,vi;q=0.9,en;q=0.8", "Tìm kiếm món ăn thành công"},
+		{"fr,en;q=0.5", "Food search successful"},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/foods/search?query=food", nil)
+		if tt.acceptLanguage != "" {
+			req.Header.Set("Accept-Language", tt.acceptLanguage)
+		}
+		rec := httptest.NewRecorder()
+		newLanguageRouter("en").ServeHTTP(rec, req)
+
+		var body middleware.ResponseFormat
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatalf("Expected a JSON body, got: %v", err)
+		}
+		if body.Message != tt.expected {
+			t.Errorf("Accept-Language %q: expected message %q, got: %q", tt.acceptLanguage, tt.expected, body.Message)
+		}
+	}
+}
+
+func validateFood(t *testing.T, calories string) response.FoodValidationResponse {
+	t.Helper()
+	body := `{"name":{"en":"Chicken breast"},"category":"protein","visibility":"public",` +
+		`"macros":{"protein":31,"carbohydrates":0,"fat":3.6,"fiber":0},` +
+		`"servingSizes":[{"unit":"gram","amount":100,"gramEquivalent":100}]` + calories + `}`
+	rec := httptest.NewRecorder()
+	newLanguageRouter("en").ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/foods/validate", strings.NewReader(body)))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got: %d (%s)", rec.Code, rec.Body.String())
+	}
+
+	var result struct {
+		Data response.FoodValidationResponse `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &result); err != nil {
+		t.Fatalf("Expected a JSON body, got: %v", err)
+	}
+	return result.Data
+}
+
+func TestFoodValidate_InconsistentCalories(t *testing.T) {
+	result := validateFood(t, `,"calories":200`)
+	if result.Valid {
+		t.Fatal("Expected the food to be reported invalid")
+	}
+	if len(result.Errors) != 1 || result.Errors[0].Code != "calories_consistency" {
+		t.Fatalf("Expected a calories consistency error, got: %+v", result.Errors)
+	}
+	if result.Calories.Expected != 156.4 || result.Calories.Delta != 43.6 {
+		t.Errorf("Expected expected 156.4 and delta 43.6, got: %+v", result.Calories)
+	}
+}
+
+func TestFoodValidate_OmittedCaloriesAreEstimated(t *testing.T) {
+	result := validateFood(t, "")
+	if !result.Valid || len(result.Errors) != 0 || len(result.Warnings) != 0 {
+		t.Errorf("Expected a valid food without warnings, got: %+v", result)
+	}
+	if result.Calories.Stated != 156.4 || result.Calories.Delta != 0 {
+		t.Errorf("Expected the estimated calories, got: %+v", result.Calories)
+	}
+}
+
+func uploadImage(t *testing.T, maxImageSize int64, size int) *httptest.ResponseRecorder {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	log := logger.NewNoopLogger()
+	foods := service.NewFoodService(&searchResultFoodRepo{}, nil, nil, service.NewNoopTransactor(), cache.NewNoopCache(), nil, maxImageSize, config.FoodConfig{}, log)
+	handler := NewFoodHandler(foods, nil, maxImageSize, testPaginationConfig(), log)
+
+	router := gin.New()
+	router.Use(middleware.ResponseMiddleware(log))
+	router.Use(func(c *gin.Context) { c.Set("userID", primitive.NewObjectID().Hex()) })
+	router.POST("/foods/:id/image", handler.UploadImage)
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, err := form.CreateFormFile("image", "big.png")
+	if err != nil {
+		t.Fatalf("Failed to create form file: %v", err)
+	}
+	part.Write(bytes.Repeat([]byte{0}, size))
+	form.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/foods/"+primitive.NewObjectID().Hex()+"/image", &body)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestFoodUploadImage_OversizedIsPayloadTooLarge(t *testing.T) {
+	// Past the request limit the body is cut off while parsing; just above the image limit the service rejects it
+	for _, size := range []int{multipartOverhead + 2048, 1025} {
+		rec := uploadImage(t, 1024, size)
+		if rec.Code != http.StatusRequestEntityTooLarge {
+			t.Errorf("Expected status 413 for %d bytes, got: %d (%s)", size, rec.Code, rec.Body.String())
+		}
+		if !strings.Contains(rec.Body.String(), middleware.ErrorCodePayloadTooLarge) {
+			t.Errorf("Expected error code %s, got: %s", middleware.ErrorCodePayloadTooLarge, rec.Body.String())
+		}
+	}
+}
+
+// unavailableFoodRepo fails every search like a repository behind an open circuit breaker
+type unavailableFoodRepo struct {
+	service.FoodRepository
+}
+
+func (r *unavailableFoodRepo) FindFoods(ctx context.Context, filter domain.FoodFilter, page domain.Page, sortBy string) ([]*domain.FoodItem, error) {
+	return nil, circuitbreaker.ErrOpen
+}
+
+func TestFoodSearch_OpenBreakerIsServiceUnavailable(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	log := logger.NewNoopLogger()
+	user := &domain.User{ID: primitive.NewObjectID()}
+	users := service.NewUserService(&profileUserRepo{user: user}, nil, nil, nil, nil, config.UserConfig{}, log)
+	handler := NewFoodHandler(service.NewFoodService(&unavailableFoodRepo{}, nil, nil, service.NewNoopTransactor(), cache.NewNoopCache(), nil, 0, config.FoodConfig{}, log), users, 0, testPaginationConfig(), log)
+
+	router := gin.New()
+	router.Use(middleware.ResponseMiddleware(log))
+	router.Use(func(c *gin.Context) { c.Set("userID", user.ID.Hex()) })
+	router.GET("/foods/search", handler.Search)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/foods/search?query=rice", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected status 503, got: %d", rec.Code)
+	}
+}
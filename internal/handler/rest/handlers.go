@@ -1,23 +1,32 @@
 package rest
 
 import (
-	"go.mongodb.org/mongo-driver/mongo"
+	"errors"
+
+	"github.com/gin-gonic/gin"
 
 	"nutrient_be/internal/config"
+	"nutrient_be/internal/handler/middleware"
+	"nutrient_be/internal/pkg/circuitbreaker"
+	"nutrient_be/internal/pkg/jwtkeys"
 	"nutrient_be/internal/pkg/logger"
 	"nutrient_be/internal/service"
 )
 
 // Handlers contains all HTTP handlers
 type Handlers struct {
-	Auth     *AuthHandler
-	User     *UserHandler
-	Health   *HealthHandler
-	Food     *FoodHandler
-	Meal     *MealHandler
-	MealPlan *MealPlanHandler
-	Shopping *ShoppingHandler
-	Report   *ReportHandler
+	Auth       *AuthHandler
+	User       *UserHandler
+	Health     *HealthHandler
+	Food       *FoodHandler
+	Meal       *MealHandler
+	MealPlan   *MealPlanHandler
+	Shopping   *ShoppingHandler
+	Report     *ReportHandler
+	Suggestion *SuggestionHandler
+	Dashboard  *DashboardHandler
+	Admin      *AdminHandler
+	Metadata   *MetadataHandler
 }
 
 // NewHandlers creates a new handlers instance
@@ -29,18 +38,38 @@ func NewHandlers(
 	mealPlanService *service.MealPlanService,
 	shoppingService *service.ShoppingService,
 	reportService *service.ReportService,
-	db *mongo.Client,
+	suggestionService *service.MealSuggestionService,
+	dashboardService *service.DashboardService,
+	db DependencyChecker,
+	dbBreaker *circuitbreaker.Breaker,
+	buildInfo BuildInfo,
+	tokenKeys *jwtkeys.Keys,
 	log logger.Logger,
 	cfg config.Config,
 ) *Handlers {
 	return &Handlers{
-		Auth:     NewAuthHandler(authService, log, cfg.Auth),
-		User:     NewUserHandler(userService, log),
-		Health:   NewHealthHandler(db, log),
-		Food:     NewFoodHandler(foodService, log),
-		Meal:     NewMealHandler(mealService, log),
-		MealPlan: NewMealPlanHandler(mealPlanService, log),
-		Shopping: NewShoppingHandler(shoppingService, log),
-		Report:   NewReportHandler(reportService, log),
+		Auth:       NewAuthHandler(authService, log, cfg.Auth, tokenKeys),
+		User:       NewUserHandler(userService, cfg.Auth.MinPasswordLength, log),
+		Health:     NewHealthHandler(db, dbBreaker, buildInfo, log),
+		Food:       NewFoodHandler(foodService, userService, cfg.Storage.MaxImageSizeKB*1024, cfg.Pagination, log),
+		Meal:       NewMealHandler(mealService, cfg.Pagination.MealTemplates, cfg.Meal, log),
+		MealPlan:   NewMealPlanHandler(mealPlanService, cfg.Pagination.MealPlans, log),
+		Shopping:   NewShoppingHandler(shoppingService, cfg.Pagination.ShoppingLists, log),
+		Report:     NewReportHandler(reportService, log),
+		Suggestion: NewSuggestionHandler(suggestionService, log),
+		Dashboard:  NewDashboardHandler(dashboardService, log),
+		Metadata:   NewMetadataHandler(log),
+		Admin:      NewAdminHandler(middleware.NewReadOnlyMode(cfg.Maintenance.ReadOnly), cfg.Maintenance.AdminToken, log),
+	}
+}
+
+// handleDatabaseUnavailable sends a 503 when err comes from the open database circuit breaker
+// The breaker can open after CircuitBreakerMiddleware let a request through, so services may still return ErrOpen
+// Returns true if a response was sent
+func handleDatabaseUnavailable(c *gin.Context, responseHelper *middleware.ResponseHelper, err error) bool {
+	if !errors.Is(err, circuitbreaker.ErrOpen) {
+		return false
 	}
+	responseHelper.ServiceUnavailable(c, gin.H{"error": "Database temporarily unavailable"}, middleware.MsgDatabaseUnavailable)
+	return true
 }
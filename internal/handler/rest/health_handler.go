@@ -3,28 +3,76 @@ package rest
 import (
 	"context"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"go.mongodb.org/mongo-driver/mongo"
 
+	"nutrient_be/internal/pkg/circuitbreaker"
 	"nutrient_be/internal/pkg/logger"
 )
 
+// BuildInfo describes the running build, as set at link time
+type BuildInfo struct {
+	Version   string
+	Commit    string
+	BuildTime string
+}
+
+// readinessCacheTTL is how long a readiness result is reused
+const readinessCacheTTL = 5 * time.Second
+
+// DependencyChecker reports the state of the database for readiness checks
+type DependencyChecker interface {
+	Ping(ctx context.Context) error
+	ServerVersion(ctx context.Context) (string, error)
+	MigrationStatus(ctx context.Context) (applied []string, pending []string, err error)
+}
+
+// readinessResult is a cached readiness response
+type readinessResult struct {
+	status    int
+	body      gin.H
+	checkedAt time.Time
+}
+
 // HealthHandler handles health check endpoints
 type HealthHandler struct {
-	db     *mongo.Client
-	logger logger.Logger
+	db        DependencyChecker
+	breaker   *circuitbreaker.Breaker
+	buildInfo BuildInfo
+	logger    logger.Logger
+
+	mu        sync.Mutex
+	readiness *readinessResult
+	now       func() time.Time
 }
 
 // NewHealthHandler creates a new health handler
-func NewHealthHandler(db *mongo.Client, log logger.Logger) *HealthHandler {
+// breaker is the database circuit breaker reported by readiness; nil leaves it out
+func NewHealthHandler(db DependencyChecker, breaker *circuitbreaker.Breaker, buildInfo BuildInfo, log logger.Logger) *HealthHandler {
 	return &HealthHandler{
-		db:     db,
-		logger: log,
+		db:        db,
+		breaker:   breaker,
+		buildInfo: buildInfo,
+		logger:    log,
+		now:       time.Now,
 	}
 }
 
+// Ping handles the lightweight diagnostic endpoint
+// It reports the build and server time without touching dependencies
+func (h *HealthHandler) Ping(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"status":     "UP",
+		"service":    "nutrient-api",
+		"version":    h.buildInfo.Version,
+		"commit":     h.buildInfo.Commit,
+		"buildTime":  h.buildInfo.BuildTime,
+		"serverTime": time.Now().UTC().Format(time.RFC3339),
+	})
+}
+
 // Liveness handles liveness probe
 func (h *HealthHandler) Liveness(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{
@@ -35,30 +83,95 @@ func (h *HealthHandler) Liveness(c *gin.Context) {
 }
 
 // Readiness handles readiness probe
+// The result is cached for readinessCacheTTL so frequent probes do not hit the database.
+// Pending migrations report DEGRADED with status 200; an unreachable database reports DOWN with 503.
+// An open database circuit breaker reports DOWN with 503 right away, bypassing the cache.
 func (h *HealthHandler) Readiness(c *gin.Context) {
-	// Check database connection
-	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
-	defer cancel()
-
-	if err := h.db.Ping(ctx, nil); err != nil {
-		h.logger.Error(ctx, "Database health check failed", logger.Error(err))
+	if h.breaker != nil && h.breaker.State() == circuitbreaker.StateOpen {
 		c.JSON(http.StatusServiceUnavailable, gin.H{
 			"status":    "DOWN",
-			"timestamp": time.Now().Unix(),
+			"timestamp": h.now().Unix(),
 			"checks": gin.H{
-				"database": "DOWN",
+				"database":       "DOWN",
+				"circuitBreaker": string(circuitbreaker.StateOpen),
 			},
-			"error": err.Error(),
 		})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"status":    "UP",
-		"timestamp": time.Now().Unix(),
-		"checks": gin.H{
-			"database": "UP",
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.readiness == nil || h.now().Sub(h.readiness.checkedAt) >= readinessCacheTTL {
+		h.readiness = h.checkReadiness()
+	}
+
+	c.JSON(h.readiness.status, h.readiness.body)
+}
+
+// checkReadiness queries the database for connectivity, server version and migration status
+func (h *HealthHandler) checkReadiness() *readinessResult {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	checkedAt := h.now()
+	if err := h.db.Ping(ctx); err != nil {
+		h.logger.Error(ctx, "Database health check failed", logger.Error(err))
+		return &readinessResult{
+			status:    http.StatusServiceUnavailable,
+			checkedAt: checkedAt,
+			body: gin.H{
+				"status":    "DOWN",
+				"timestamp": checkedAt.Unix(),
+				"checks": gin.H{
+					"database": "DOWN",
+				},
+				"error": err.Error(),
+			},
+		}
+	}
+
+	version, err := h.db.ServerVersion(ctx)
+	if err != nil {
+		h.logger.Warn(ctx, "Failed to get MongoDB server version", logger.Error(err))
+		version = "unknown"
+	}
+
+	status := "UP"
+	migrations := gin.H{}
+	applied, pending, err := h.db.MigrationStatus(ctx)
+	if err != nil {
+		h.logger.Warn(ctx, "Failed to get migration status", logger.Error(err))
+		status = "DEGRADED"
+		migrations["error"] = err.Error()
+	} else {
+		migrations["applied"] = applied
+		migrations["pending"] = pending
+		if len(pending) > 0 {
+			status = "DEGRADED"
+		}
+	}
+
+	checks := gin.H{
+		"database":   "UP",
+		"migrations": migrations,
+	}
+	if h.breaker != nil {
+		// Half-open still counts as ready so traffic can reach the probe that closes the breaker
+		checks["circuitBreaker"] = string(h.breaker.State())
+	}
+
+	return &readinessResult{
+		status:    http.StatusOK,
+		checkedAt: checkedAt,
+		body: gin.H{
+			"status":    status,
+			"timestamp": checkedAt.Unix(),
+			"checks":    checks,
+			"dependencies": gin.H{
+				"mongodb": version,
+			},
+			"service": "nutrient-api",
 		},
-		"service": "nutrient-api",
-	})
+	}
 }
@@ -0,0 +1,146 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"nutrient_be/internal/pkg/circuitbreaker"
+	"nutrient_be/internal/pkg/logger"
+)
+
+func TestHealthHandler_Ping(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewHealthHandler(nil, nil, BuildInfo{Version: "1.4.2", Commit: "abc1234", BuildTime: "2025-01-01T00:00:00Z"}, logger.NewNoopLogger())
+
+	router := gin.New()
+	router.GET("/ping", h.Ping)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ping", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got: %d", rec.Code)
+	}
+
+	var body map[string]string
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Expected JSON body, got: %v", err)
+	}
+	if body["version"] != "1.4.2" {
+		t.Errorf("Expected version 1.4.2, got: %q", body["version"])
+	}
+	if body["commit"] != "abc1234" {
+		t.Errorf("Expected commit abc1234, got: %q", body["commit"])
+	}
+	if body["serverTime"] == "" {
+		t.Error("Expected server time to be set")
+	}
+}
+
+// fakeDependencyChecker reports fixed dependency state and counts pings
+type fakeDependencyChecker struct {
+	pingErr error
+	applied []string
+	pending []string
+	pings   int
+}
+
+func (f *fakeDependencyChecker) Ping(ctx context.Context) error {
+	f.pings++
+	return f.pingErr
+}
+
+func (f *fakeDependencyChecker) ServerVersion(ctx context.Context) (string, error) {
+	return "7.0.4", nil
+}
+
+func (f *fakeDependencyChecker) MigrationStatus(ctx context.Context) ([]string, []string, error) {
+	return f.applied, f.pending, nil
+}
+
+func getReadiness(t *testing.T, h *HealthHandler) (int, map[string]interface{}) {
+	t.Helper()
+	router := gin.New()
+	router.GET("/health/readiness", h.Readiness)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health/readiness", nil))
+
+	var body map[string]interface{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Expected JSON body, got: %v", err)
+	}
+	return rec.Code, body
+}
+
+func TestHealthHandler_Readiness_DegradedWhenMigrationsPending(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := &fakeDependencyChecker{applied: []string{}, pending: []string{"0001_create_indexes"}}
+	h := NewHealthHandler(db, nil, BuildInfo{}, logger.NewNoopLogger())
+
+	code, body := getReadiness(t, h)
+
+	if code != http.StatusOK {
+		t.Errorf("Expected status 200, got: %d", code)
+	}
+	if body["status"] != "DEGRADED" {
+		t.Errorf("Expected DEGRADED, got: %v", body["status"])
+	}
+	migrations := body["checks"].(map[string]interface{})["migrations"].(map[string]interface{})
+	if pending := migrations["pending"].([]interface{}); len(pending) != 1 || pending[0] != "0001_create_indexes" {
+		t.Errorf("Expected pending migration to be reported, got: %v", pending)
+	}
+	if version := body["dependencies"].(map[string]interface{})["mongodb"]; version != "7.0.4" {
+		t.Errorf("Expected MongoDB version 7.0.4, got: %v", version)
+	}
+}
+
+func TestHealthHandler_Readiness_Cached(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := &fakeDependencyChecker{applied: []string{"0001_create_indexes"}, pending: []string{}}
+	h := NewHealthHandler(db, nil, BuildInfo{}, logger.NewNoopLogger())
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	h.now = func() time.Time { return now }
+
+	_, body := getReadiness(t, h)
+	if body["status"] != "UP" {
+		t.Errorf("Expected UP, got: %v", body["status"])
+	}
+	getReadiness(t, h)
+	if db.pings != 1 {
+		t.Errorf("Expected cached result within TTL, got %d pings", db.pings)
+	}
+
+	now = now.Add(readinessCacheTTL)
+	getReadiness(t, h)
+	if db.pings != 2 {
+		t.Errorf("Expected a new check after TTL, got %d pings", db.pings)
+	}
+}
+
+func TestHealthHandler_Readiness_DownWhileBreakerOpen(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := &fakeDependencyChecker{applied: []string{"0001_create_indexes"}, pending: []string{}}
+	breaker := circuitbreaker.New(1, time.Hour)
+	h := NewHealthHandler(db, breaker, BuildInfo{}, logger.NewNoopLogger())
+
+	_, body := getReadiness(t, h)
+	if body["status"] != "UP" {
+		t.Fatalf("Expected UP while the breaker is closed, got: %v", body["status"])
+	}
+
+	breaker.Record(true)
+	code, body := getReadiness(t, h)
+	if code != http.StatusServiceUnavailable {
+		t.Errorf("Expected status 503 while the breaker is open, got: %d", code)
+	}
+	if breakerState := body["checks"].(map[string]interface{})["circuitBreaker"]; breakerState != "open" {
+		t.Errorf("Expected the open breaker to be reported, got: %v", breakerState)
+	}
+}
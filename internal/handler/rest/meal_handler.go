@@ -2,15 +2,19 @@ package rest
 
 import (
 	"context"
+	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
 
+	"nutrient_be/internal/config"
 	"nutrient_be/internal/domain"
 	"nutrient_be/internal/dto/request"
 	"nutrient_be/internal/dto/response"
 	"nutrient_be/internal/handler/middleware"
+	"nutrient_be/internal/pkg/calculator"
 	"nutrient_be/internal/pkg/logger"
 	mealValidator "nutrient_be/internal/pkg/validator"
 	"nutrient_be/internal/service"
@@ -21,16 +25,26 @@ type MealHandler struct {
 	mealService     *service.MealService
 	structValidator *validator.Validate
 	mealValidator   *mealValidator.MealValidator
+	pageSize        config.PageSizeConfig
 	logger          logger.Logger
 	responseHelper  *middleware.ResponseHelper
 }
 
 // NewMealHandler creates a new meal handler
-func NewMealHandler(mealService *service.MealService, log logger.Logger) *MealHandler {
+// cfg caps the food items, item amounts and tags accepted per request, values below 1 keep the validator defaults
+func NewMealHandler(mealService *service.MealService, pageSize config.PageSizeConfig, cfg config.MealConfig, log logger.Logger) *MealHandler {
+	templateValidator := mealValidator.NewMealValidator(log)
+	templateValidator.SetMaxFoodItems(cfg.MaxTemplateFoodItems)
+	templateValidator.SetMaxTags(cfg.MaxTemplateTags)
+	templateValidator.SetMaxTagLength(cfg.MaxTagLength)
+	templateValidator.SetMaxItemAmount(cfg.MaxItemAmount)
+	templateValidator.SetServingUnitCheck(cfg.CheckServingUnits)
+
 	return &MealHandler{
 		mealService:     mealService,
 		structValidator: validator.New(),
-		mealValidator:   mealValidator.NewMealValidator(log),
+		mealValidator:   templateValidator,
+		pageSize:        pageSize,
 		logger:          log,
 		responseHelper:  middleware.NewResponseHelper(),
 	}
@@ -42,7 +56,7 @@ func (h *MealHandler) getUserIDFromContext(c *gin.Context, ctx context.Context)
 	userIDStr, exists := middleware.GetUserIDFromContext(c)
 	if !exists {
 		h.logger.Error(ctx, "User ID not found in context")
-		h.responseHelper.Unauthorized(c, gin.H{"error": "User not authenticated"}, "Authentication required")
+		h.responseHelper.Unauthorized(c, gin.H{"error": "User not authenticated"}, middleware.MsgAuthenticationRequired)
 		return "", false
 	}
 	return userIDStr, true
@@ -54,7 +68,7 @@ func (h *MealHandler) getTemplateIDFromParams(c *gin.Context, ctx context.Contex
 	templateID := c.Param("id")
 	if templateID == "" {
 		h.logger.Error(ctx, "Template ID is required")
-		h.responseHelper.BadRequest(c, gin.H{"error": "Template ID is required"}, "Template ID is required")
+		h.responseHelper.BadRequest(c, gin.H{"error": "Template ID is required"}, middleware.MsgTemplateIDRequired)
 		return "", false
 	}
 	return templateID, true
@@ -65,7 +79,7 @@ func (h *MealHandler) getTemplateIDFromParams(c *gin.Context, ctx context.Contex
 func (h *MealHandler) bindRequest(c *gin.Context, ctx context.Context, req interface{}, requestType string) bool {
 	if err := c.ShouldBindJSON(req); err != nil {
 		h.logger.Error(ctx, "Failed to bind request", logger.String("type", requestType), logger.Error(err))
-		h.responseHelper.BadRequest(c, gin.H{"details": err.Error()}, "Invalid request body")
+		h.responseHelper.BadRequest(c, gin.H{"details": err.Error()}, middleware.MsgInvalidRequestBody)
 		return false
 	}
 	return true
@@ -77,7 +91,7 @@ func (h *MealHandler) validateRequest(c *gin.Context, ctx context.Context, req i
 	// Format/structure validation (struct tags)
 	if err := h.structValidator.Struct(req); err != nil {
 		h.logger.Error(ctx, "Request validation failed", logger.String("type", requestType), logger.Error(err))
-		h.responseHelper.ValidationError(c, gin.H{"validation_errors": err.Error()}, "Validation failed")
+		h.responseHelper.ValidationError(c, gin.H{"validation_errors": err.Error()}, middleware.MsgValidationFailed)
 		return false
 	}
 	return true
@@ -88,7 +102,7 @@ func (h *MealHandler) validateRequest(c *gin.Context, ctx context.Context, req i
 func (h *MealHandler) validateBusinessLogic(c *gin.Context, ctx context.Context, err error, requestType string) bool {
 	if err != nil {
 		h.logger.Error(ctx, "Business validation failed", logger.String("type", requestType), logger.Error(err))
-		h.responseHelper.ValidationError(c, gin.H{"validation_errors": err.Error()}, "Validation failed")
+		h.responseHelper.ValidationError(c, gin.H{"validation_errors": err.Error()}, middleware.MsgValidationFailed)
 		return false
 	}
 	return true
@@ -108,15 +122,29 @@ func (h *MealHandler) handleServiceError(
 
 	h.logger.Error(ctx, "Service operation failed", logger.String("operation", operation), logger.Error(err))
 
+	if handleDatabaseUnavailable(c, h.responseHelper, err) {
+		return true
+	}
+
 	// Check for specific error types
 	errMsg := err.Error()
 	if errMsg == "template not found or access denied" {
-		h.responseHelper.NotFound(c, gin.H{"error": "Meal template not found"}, "Meal template not found")
+		h.responseHelper.ErrorCode(c, middleware.ErrorCodeMealTemplateNotFound)
+		h.responseHelper.NotFound(c, gin.H{"error": "Meal template not found"}, middleware.MsgMealTemplateNotFound)
+		return true
+	}
+	if strings.HasPrefix(errMsg, "validation failed:") {
+		h.responseHelper.ErrorCode(c, middleware.ErrorCodeValidationFailed)
+		h.responseHelper.BadRequest(c, gin.H{"details": errMsg}, middleware.MsgValidationFailed)
+		return true
+	}
+	if strings.HasPrefix(errMsg, "invalid ") {
+		h.responseHelper.BadRequest(c, gin.H{"details": errMsg}, middleware.MsgInvalidRequest)
 		return true
 	}
 
 	// Default to internal error
-	h.responseHelper.InternalError(c, gin.H{"details": errMsg}, "Operation failed")
+	h.responseHelper.InternalError(c, gin.H{"details": errMsg}, middleware.MsgOperationFailed)
 	return true
 }
 
@@ -147,15 +175,16 @@ func (h *MealHandler) CreateTemplate(c *gin.Context) {
 	}
 
 	// Call service
-	template, err := h.mealService.CreateTemplate(ctx, userIDStr, &req)
+	template, warnings, err := h.mealService.CreateTemplate(ctx, userIDStr, &req)
 	if h.handleServiceError(c, ctx, err, "create meal template") {
 		return
 	}
 
 	// Convert to response and send success
 	templateResponse := mealTemplateToResponse(template)
+	templateResponse.Warnings = warnings
 	h.logger.Info(ctx, "Meal template created successfully")
-	h.responseHelper.Created(c, templateResponse, "Meal template created successfully")
+	h.responseHelper.Created(c, templateResponse, middleware.MsgMealTemplateCreated)
 }
 
 // ListTemplates handles listing meal templates
@@ -170,17 +199,11 @@ func (h *MealHandler) ListTemplates(c *gin.Context) {
 
 	// Get and validate query parameters
 	mealType := c.Query("mealType")
-	limitStr := c.DefaultQuery("limit", "20")
-	offsetStr := c.DefaultQuery("offset", "0")
-
-	limit, err := strconv.Atoi(limitStr)
-	if err != nil || limit < 1 || limit > 100 {
-		limit = 20
-	}
-
-	offset, err := strconv.Atoi(offsetStr)
-	if err != nil || offset < 0 {
-		offset = 0
+	limit, offset, err := parsePagination(c, h.pageSize)
+	if err != nil {
+		h.logger.Error(ctx, "Invalid pagination", logger.Error(err))
+		h.responseHelper.BadRequest(c, gin.H{"error": err.Error()}, middleware.MsgInvalidPagination)
+		return
 	}
 
 	// Call service
@@ -196,7 +219,65 @@ func (h *MealHandler) ListTemplates(c *gin.Context) {
 	}
 
 	h.logger.Info(ctx, "Meal templates listed successfully")
-	h.responseHelper.Success(c, templateResponses, "Meal templates listed successfully")
+	h.responseHelper.Success(c, templateResponses, middleware.MsgMealTemplatesListed)
+}
+
+// ListFittingTemplates handles listing templates that fit within a remaining calorie budget
+func (h *MealHandler) ListFittingTemplates(c *gin.Context) {
+	ctx := middleware.GetContext(c)
+
+	// Get user ID from context
+	userIDStr, ok := h.getUserIDFromContext(c, ctx)
+	if !ok {
+		return
+	}
+
+	// Get and validate query parameters
+	maxCalories, err := strconv.ParseFloat(c.Query("maxCalories"), 64)
+	if err != nil {
+		h.logger.Error(ctx, "Invalid maxCalories", logger.Error(err))
+		h.responseHelper.BadRequest(c, gin.H{"error": "maxCalories must be a number"}, middleware.MsgInvalidMaxCalories)
+		return
+	}
+	mealType := c.Query("mealType")
+
+	// Call service
+	templates, err := h.mealService.ListFittingTemplates(ctx, userIDStr, maxCalories, mealType)
+	if h.handleServiceError(c, ctx, err, "list fitting meal templates") {
+		return
+	}
+
+	// Convert to response
+	templateResponses := make([]response.MealTemplateResponse, len(templates))
+	for i, template := range templates {
+		templateResponses[i] = mealTemplateToResponse(template)
+	}
+
+	h.logger.Info(ctx, "Fitting meal templates listed successfully")
+	h.responseHelper.Success(c, templateResponses, middleware.MsgFittingMealTemplatesListed)
+}
+
+// ListTags handles listing the distinct tags on the user's meal templates with their counts
+func (h *MealHandler) ListTags(c *gin.Context) {
+	ctx := middleware.GetContext(c)
+
+	userIDStr, ok := h.getUserIDFromContext(c, ctx)
+	if !ok {
+		return
+	}
+
+	tags, err := h.mealService.ListTags(ctx, userIDStr)
+	if h.handleServiceError(c, ctx, err, "list meal template tags") {
+		return
+	}
+
+	tagResponses := make([]response.TagCountResponse, len(tags))
+	for i, tag := range tags {
+		tagResponses[i] = response.TagCountResponse{Tag: tag.Tag, Count: tag.Count}
+	}
+
+	h.logger.Info(ctx, "Meal template tags listed successfully")
+	h.responseHelper.Success(c, tagResponses, middleware.MsgMealTemplateTagsListed)
 }
 
 // GetTemplate handles getting a meal template
@@ -215,6 +296,22 @@ func (h *MealHandler) GetTemplate(c *gin.Context) {
 		return
 	}
 
+	// Only foods can be expanded
+	expand := c.Query("expand")
+	if expand != "" && expand != "foods" {
+		h.logger.Error(ctx, "Invalid expand", logger.String("expand", expand))
+		h.responseHelper.BadRequest(c, gin.H{"error": "expand must be foods"}, middleware.MsgInvalidExpand)
+		return
+	}
+
+	// Amounts are stored in metric; imperial only changes how they are presented
+	units := c.DefaultQuery("units", calculator.UnitSystemMetric)
+	if units != calculator.UnitSystemMetric && units != calculator.UnitSystemImperial {
+		h.logger.Error(ctx, "Invalid units", logger.String("units", units))
+		h.responseHelper.BadRequest(c, gin.H{"error": "units must be metric or imperial"}, middleware.MsgInvalidUnitSystem)
+		return
+	}
+
 	// Call service
 	template, err := h.mealService.GetTemplate(ctx, userIDStr, templateID)
 	if h.handleServiceError(c, ctx, err, "get meal template") {
@@ -222,9 +319,18 @@ func (h *MealHandler) GetTemplate(c *gin.Context) {
 	}
 
 	// Convert to response and send success
+	// The current foods are always loaded so deleted ones can be flagged; the stored totals still hold
 	templateResponse := mealTemplateToResponse(template)
+	foods, err := h.mealService.ExpandTemplateFoods(ctx, userIDStr, template)
+	if h.handleServiceError(c, ctx, err, "expand meal template foods") {
+		return
+	}
+	expandTemplateFoods(&templateResponse, template, foods, expand == "foods")
+	if units == calculator.UnitSystemImperial {
+		templateToImperial(&templateResponse)
+	}
 	h.logger.Info(ctx, "Meal template retrieved successfully")
-	h.responseHelper.Success(c, templateResponse, "Meal template retrieved successfully")
+	h.responseHelper.Success(c, templateResponse, middleware.MsgMealTemplateRetrieved)
 }
 
 // AddFoodToTemplate handles adding food items to a meal template
@@ -268,7 +374,7 @@ func (h *MealHandler) AddFoodToTemplate(c *gin.Context) {
 	// Convert to response and send success
 	templateResponse := mealTemplateToResponse(template)
 	h.logger.Info(ctx, "Food items added to template successfully")
-	h.responseHelper.Success(c, templateResponse, "Food items added to template successfully")
+	h.responseHelper.Success(c, templateResponse, middleware.MsgMealTemplateFoodsAdded)
 }
 
 // UpdateTemplate handles meal template update
@@ -312,7 +418,7 @@ func (h *MealHandler) UpdateTemplate(c *gin.Context) {
 	// Convert to response and send success
 	templateResponse := mealTemplateToResponse(template)
 	h.logger.Info(ctx, "Meal template updated successfully")
-	h.responseHelper.Success(c, templateResponse, "Meal template updated successfully")
+	h.responseHelper.Success(c, templateResponse, middleware.MsgMealTemplateUpdated)
 }
 
 // DeleteTemplate handles meal template deletion
@@ -338,7 +444,155 @@ func (h *MealHandler) DeleteTemplate(c *gin.Context) {
 
 	// Send success response
 	h.logger.Info(ctx, "Meal template deleted successfully")
-	h.responseHelper.Success(c, gin.H{"message": "Meal template deleted successfully"}, "Meal template deleted successfully")
+	h.responseHelper.Success(c, gin.H{"message": "Meal template deleted successfully"}, middleware.MsgMealTemplateDeleted)
+}
+
+// BulkDeleteTemplates handles deleting the user's templates matching a meal type and/or tags
+func (h *MealHandler) BulkDeleteTemplates(c *gin.Context) {
+	ctx := middleware.GetContext(c)
+
+	// Get user ID from context
+	userIDStr, ok := h.getUserIDFromContext(c, ctx)
+	if !ok {
+		return
+	}
+
+	// Bind request
+	var req request.BulkDeleteTemplatesRequest
+	if !h.bindRequest(c, ctx, &req, "BulkDeleteTemplatesRequest") {
+		return
+	}
+
+	// Call service
+	deleted, err := h.mealService.BulkDelete(ctx, userIDStr, &req)
+	if h.handleServiceError(c, ctx, err, "bulk delete meal templates") {
+		return
+	}
+
+	h.logger.Info(ctx, "Meal templates bulk deleted successfully", logger.Int("deleted", int(deleted)))
+	h.responseHelper.Success(c, response.BulkDeleteTemplatesResponse{Deleted: deleted}, middleware.MsgMealTemplatesDeleted)
+}
+
+// CalculateNutrition handles calculating nutrients for an ad-hoc food list without saving it
+// Entries that cannot be calculated carry their own error and the rest are still returned
+func (h *MealHandler) CalculateNutrition(c *gin.Context) {
+	ctx := middleware.GetContext(c)
+
+	userIDStr, ok := h.getUserIDFromContext(c, ctx)
+	if !ok {
+		return
+	}
+
+	var req request.CalculateNutritionRequest
+	if !h.bindRequest(c, ctx, &req, "calculate nutrition") {
+		return
+	}
+	if !h.validateRequest(c, ctx, &req, "calculate nutrition") {
+		return
+	}
+
+	result, err := h.mealService.CalculateNutrition(ctx, userIDStr, &req)
+	if h.handleServiceError(c, ctx, err, "calculate nutrition") {
+		return
+	}
+
+	h.logger.Info(ctx, "Nutrition calculated successfully")
+	h.responseHelper.Success(c, nutritionCalculationToResponse(result), middleware.MsgNutritionCalculated)
+}
+
+// nutritionCalculationToResponse converts a nutrition calculation to a response NutritionCalculationResponse
+func nutritionCalculationToResponse(result *service.NutritionCalculation) response.NutritionCalculationResponse {
+	items := make([]response.NutritionItemResponse, len(result.Items))
+	for i, item := range result.Items {
+		items[i] = response.NutritionItemResponse{
+			FoodID:      item.Request.FoodID,
+			ServingUnit: item.Request.ServingUnit,
+			Amount:      item.Request.Amount,
+		}
+		if item.Err != nil {
+			items[i].Error = item.Err.Error()
+			continue
+		}
+
+		macros := macrosToResponse(item.FoodItem.Macros)
+		micros := microsToResponse(item.FoodItem.Micros)
+		items[i].FoodName = item.FoodItem.FoodName
+		items[i].Calories = response.RoundCalories(item.FoodItem.Calories)
+		items[i].Macros = &macros
+		items[i].Micros = &micros
+	}
+
+	return response.NutritionCalculationResponse{
+		Items:         items,
+		TotalCalories: response.RoundCalories(result.TotalCalories),
+		TotalMacros:   macrosToResponse(result.TotalMacros),
+		TotalMicros:   microsToResponse(result.TotalMicros),
+	}
+}
+
+// expandTemplateFoods flags the items of a template response whose food no longer exists, with a warning
+// that editing them requires a replacement, and those whose food is private to another user, without one.
+// Accessible items get their amount in the food's preferred unit; with attach their current food is included too.
+func expandTemplateFoods(templateResponse *response.MealTemplateResponse, template *domain.MealTemplate, foods *service.TemplateFoods, attach bool) {
+	for i, item := range template.FoodItems {
+		if foods.Inaccessible[item.FoodItemID] {
+			templateResponse.FoodItems[i].FoodPrivate = true
+			continue
+		}
+		food, ok := foods.Foods[item.FoodItemID]
+		if !ok {
+			templateResponse.FoodItems[i].FoodDeleted = true
+			templateResponse.Warnings = append(templateResponse.Warnings,
+				fmt.Sprintf("food %q was deleted; replace it with another food to edit this template's food items", item.FoodName))
+			continue
+		}
+		if grams, err := calculator.ConvertToGrams(food, item.ServingUnit, item.Amount); err == nil {
+			templateResponse.FoodItems[i].Display = calculator.FormatDisplayAmount(food, grams)
+		}
+		if attach {
+			foodResponse := foodItemToResponse(food)
+			templateResponse.FoodItems[i].Food = &foodResponse
+		}
+	}
+}
+
+// templateToImperial shows the gram amounts of a template response in ounces
+// Items served in grams and every macro are converted; micros stay in mg/µg, and the
+// display amounts and expanded foods keep their own units
+func templateToImperial(templateResponse *response.MealTemplateResponse) {
+	for i := range templateResponse.FoodItems {
+		item := &templateResponse.FoodItems[i]
+		if calculator.IsGramUnit(item.ServingUnit) {
+			item.Amount = calculator.GramsToOunces(item.Amount)
+			item.ServingUnit = "oz"
+		}
+		item.Macros = macrosToOunces(item.Macros)
+	}
+	templateResponse.TotalMacros = macrosToOunces(templateResponse.TotalMacros)
+	templateResponse.Units = calculator.UnitSystemImperial
+}
+
+// macrosToOunces converts macro weights from grams to ounces
+func macrosToOunces(macros response.MacroNutrientsResponse) response.MacroNutrientsResponse {
+	return response.MacroNutrientsResponse{
+		Protein:       calculator.GramsToOunces(macros.Protein),
+		Carbohydrates: calculator.GramsToOunces(macros.Carbohydrates),
+		Fat:           calculator.GramsToOunces(macros.Fat),
+		Fiber:         calculator.GramsToOunces(macros.Fiber),
+		Sugar:         calculator.GramsToOunces(macros.Sugar),
+	}
+}
+
+// microsToResponse converts domain MicroNutrients to a response MicroNutrientsResponse
+func microsToResponse(micros domain.MicroNutrients) response.MicroNutrientsResponse {
+	return response.MicroNutrientsResponse{
+		VitaminA:  micros.VitaminA,
+		VitaminC:  micros.VitaminC,
+		Calcium:   micros.Calcium,
+		Iron:      micros.Iron,
+		Sodium:    micros.Sodium,
+		Potassium: micros.Potassium,
+	}
 }
 
 // mealTemplateToResponse converts a domain MealTemplate to a response MealTemplateResponse
@@ -351,7 +605,7 @@ func mealTemplateToResponse(template *domain.MealTemplate) response.MealTemplate
 			FoodName:    foodItem.FoodName,
 			ServingUnit: foodItem.ServingUnit,
 			Amount:      foodItem.Amount,
-			Calories:    foodItem.Calories,
+			Calories:    response.RoundCalories(foodItem.Calories),
 			Macros: response.MacroNutrientsResponse{
 				Protein:       foodItem.Macros.Protein,
 				Carbohydrates: foodItem.Macros.Carbohydrates,
@@ -378,7 +632,7 @@ func mealTemplateToResponse(template *domain.MealTemplate) response.MealTemplate
 		Description:   template.Description,
 		MealType:      template.MealType,
 		FoodItems:     foodItems,
-		TotalCalories: template.TotalCalories,
+		TotalCalories: response.RoundCalories(template.TotalCalories),
 		TotalMacros: response.MacroNutrientsResponse{
 			Protein:       template.TotalMacros.Protein,
 			Carbohydrates: template.TotalMacros.Carbohydrates,
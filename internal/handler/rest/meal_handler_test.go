@@ -0,0 +1,300 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"nutrient_be/internal/config"
+	"nutrient_be/internal/domain"
+	"nutrient_be/internal/dto/response"
+	"nutrient_be/internal/handler/middleware"
+	"nutrient_be/internal/pkg/logger"
+	"nutrient_be/internal/service"
+)
+
+// singleTemplateRepo serves one meal template by ID
+type singleTemplateRepo struct {
+	service.MealTemplateRepository
+	template *domain.MealTemplate
+}
+
+func (r *singleTemplateRepo) GetByID(ctx context.Context, id primitive.ObjectID) (*domain.MealTemplate, error) {
+	if id != r.template.ID {
+		return nil, fmt.Errorf("meal template not found")
+	}
+	return r.template, nil
+}
+
+// batchFoodRepo serves foods by ID in one batch, counting the batches
+type batchFoodRepo struct {
+	service.MealFoodRepository
+	foods   map[primitive.ObjectID]*domain.FoodItem
+	batches int
+}
+
+func (r *batchFoodRepo) GetByIDs(ctx context.Context, ids []primitive.ObjectID) ([]*domain.FoodItem, error) {
+	r.batches++
+	var foods []*domain.FoodItem
+	for _, id := range ids {
+		if f, ok := r.foods[id]; ok {
+			foods = append(foods, f)
+		}
+	}
+	return foods, nil
+}
+
+func newTemplateRouter(userID primitive.ObjectID, template *domain.MealTemplate, foods *batchFoodRepo) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	log := logger.NewNoopLogger()
+	handler := NewMealHandler(service.NewMealService(&singleTemplateRepo{template: template}, foods, service.NewNoopTransactor(), config.MealConfig{}, log), testPaginationConfig().MealTemplates, config.MealConfig{}, log)
+
+	router := gin.New()
+	router.Use(middleware.ResponseMiddleware(log))
+	router.Use(func(c *gin.Context) { c.Set("userID", userID.Hex()) })
+	router.GET("/meal-templates/:id", handler.GetTemplate)
+	return router
+}
+
+func TestGetTemplate_ExpandFoods(t *testing.T) {
+	userID := primitive.NewObjectID()
+
+	rice := &domain.FoodItem{ID: primitive.NewObjectID(), Name: map[string]string{"en": "Rice"}, Category: "grain", ImageURL: "/uploads/rice.png", Visibility: "public"}
+	chicken := &domain.FoodItem{ID: primitive.NewObjectID(), Name: map[string]string{"en": "Chicken"}, Category: "protein", ImageURL: "/uploads/chicken.png", CreatedBy: userID, Visibility: "private"}
+	deletedID := primitive.NewObjectID()
+	template := &domain.MealTemplate{
+		ID:     primitive.NewObjectID(),
+		UserID: userID,
+		FoodItems: []domain.MealTemplateFoodItem{
+			{FoodItemID: rice.ID, FoodName: "Rice"},
+			{FoodItemID: chicken.ID, FoodName: "Chicken"},
+			{FoodItemID: deletedID, FoodName: "Tofu"},
+		},
+	}
+	foods := &batchFoodRepo{foods: map[primitive.ObjectID]*domain.FoodItem{rice.ID: rice, chicken.ID: chicken}}
+	router := newTemplateRouter(userID, template, foods)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/meal-templates/"+template.ID.Hex()+"?expand=foods", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got: %d", rec.Code)
+	}
+	var body struct {
+		Data response.MealTemplateResponse `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Expected a JSON body, got: %v", err)
+	}
+
+	if foods.batches != 1 {
+		t.Errorf("Expected the foods to be loaded in 1 batch, got: %d", foods.batches)
+	}
+	items := body.Data.FoodItems
+	if len(items) != 3 {
+		t.Fatalf("Expected 3 food items, got: %d", len(items))
+	}
+	for i, want := range []*domain.FoodItem{rice, chicken} {
+		if items[i].Food == nil {
+			t.Fatalf("Expected item %d to be expanded, got: nil", i)
+		}
+		if items[i].Food.Category != want.Category || items[i].Food.ImageURL != want.ImageURL {
+			t.Errorf("Expected category %q and image %q, got: %q and %q", want.Category, want.ImageURL, items[i].Food.Category, items[i].Food.ImageURL)
+		}
+		if items[i].FoodDeleted {
+			t.Errorf("Expected item %d not to be flagged as deleted", i)
+		}
+	}
+	if items[2].Food != nil || !items[2].FoodDeleted {
+		t.Errorf("Expected the deleted food to be flagged and not expanded, got: %+v", items[2])
+	}
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/meal-templates/"+template.ID.Hex(), nil))
+	var plain struct {
+		Data response.MealTemplateResponse `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &plain); err != nil {
+		t.Fatalf("Expected a JSON body, got: %v", err)
+	}
+	if plain.Data.FoodItems[0].Food != nil {
+		t.Errorf("Expected foods not to be expanded without expand=foods")
+	}
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/meal-templates/"+template.ID.Hex()+"?expand=tags", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for an unknown expand, got: %d", rec.Code)
+	}
+}
+
+func TestGetTemplate_DeletedFoodKeepsTotalsAndWarns(t *testing.T) {
+	userID := primitive.NewObjectID()
+	oats := &domain.FoodItem{ID: primitive.NewObjectID(), Visibility: "public"}
+	template := &domain.MealTemplate{
+		ID:            primitive.NewObjectID(),
+		UserID:        userID,
+		TotalCalories: 450,
+		TotalMacros:   domain.MacroNutrients{Protein: 30},
+		FoodItems: []domain.MealTemplateFoodItem{
+			{FoodItemID: oats.ID, FoodName: "Oats", Calories: 300},
+			{FoodItemID: primitive.NewObjectID(), FoodName: "Whey", Calories: 150},
+		},
+	}
+	router := newTemplateRouter(userID, template, &batchFoodRepo{foods: map[primitive.ObjectID]*domain.FoodItem{oats.ID: oats}})
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/meal-templates/"+template.ID.Hex(), nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got: %d", rec.Code)
+	}
+	var body struct {
+		Data response.MealTemplateResponse `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Expected a JSON body, got: %v", err)
+	}
+
+	if body.Data.TotalCalories != 450 || body.Data.TotalMacros.Protein != 30 {
+		t.Errorf("Expected the stored totals, got: %v kcal, %v g protein", body.Data.TotalCalories, body.Data.TotalMacros.Protein)
+	}
+	if body.Data.FoodItems[0].FoodDeleted || !body.Data.FoodItems[1].FoodDeleted {
+		t.Errorf("Expected only the second item to be flagged as deleted, got: %+v", body.Data.FoodItems)
+	}
+	if len(body.Data.Warnings) != 1 || !strings.Contains(body.Data.Warnings[0], "Whey") {
+		t.Errorf("Expected a deletion warning for Whey, got: %v", body.Data.Warnings)
+	}
+}
+
+func TestGetTemplate_OtherUsersPrivateFoodIsNotReportedDeleted(t *testing.T) {
+	userID := primitive.NewObjectID()
+	foreign := &domain.FoodItem{ID: primitive.NewObjectID(), Visibility: "private", CreatedBy: primitive.NewObjectID()}
+	template := &domain.MealTemplate{
+		ID:        primitive.NewObjectID(),
+		UserID:    foreign.CreatedBy,
+		IsPublic:  true,
+		FoodItems: []domain.MealTemplateFoodItem{{FoodItemID: foreign.ID, FoodName: "Grandma's stew"}},
+	}
+	router := newTemplateRouter(userID, template, &batchFoodRepo{foods: map[primitive.ObjectID]*domain.FoodItem{foreign.ID: foreign}})
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/meal-templates/"+template.ID.Hex()+"?expand=foods", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got: %d", rec.Code)
+	}
+	var body struct {
+		Data response.MealTemplateResponse `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Expected a JSON body, got: %v", err)
+	}
+
+	item := body.Data.FoodItems[0]
+	if item.FoodDeleted || !item.FoodPrivate || item.Food != nil {
+		t.Errorf("Expected the food to be flagged private and not expanded, got: %+v", item)
+	}
+	if len(body.Data.Warnings) != 0 {
+		t.Errorf("Expected no deletion warning, got: %v", body.Data.Warnings)
+	}
+}
+
+func TestGetTemplate_ImperialUnits(t *testing.T) {
+	userID := primitive.NewObjectID()
+	oats := &domain.FoodItem{ID: primitive.NewObjectID(), Visibility: "public"}
+	eggs := &domain.FoodItem{ID: primitive.NewObjectID(), Visibility: "public"}
+	template := &domain.MealTemplate{
+		ID:          primitive.NewObjectID(),
+		UserID:      userID,
+		TotalMacros: domain.MacroNutrients{Protein: 28.349523125},
+		TotalMicros: domain.MicroNutrients{Sodium: 140},
+		FoodItems: []domain.MealTemplateFoodItem{
+			{FoodItemID: oats.ID, FoodName: "Oats", ServingUnit: "gram", Amount: 100, Macros: domain.MacroNutrients{Carbohydrates: 100}},
+			{FoodItemID: eggs.ID, FoodName: "Eggs", ServingUnit: "piece", Amount: 2},
+		},
+	}
+	router := newTemplateRouter(userID, template, &batchFoodRepo{foods: map[primitive.ObjectID]*domain.FoodItem{oats.ID: oats, eggs.ID: eggs}})
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/meal-templates/"+template.ID.Hex()+"?units=imperial", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got: %d", rec.Code)
+	}
+	var body struct {
+		Data response.MealTemplateResponse `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Expected a JSON body, got: %v", err)
+	}
+
+	items := body.Data.FoodItems
+	if items[0].Amount != 3.53 || items[0].ServingUnit != "oz" || items[0].Macros.Carbohydrates != 3.53 {
+		t.Errorf("Expected 100 g of oats as 3.53 oz, got: %+v", items[0])
+	}
+	if items[1].Amount != 2 || items[1].ServingUnit != "piece" {
+		t.Errorf("Expected pieces to stay as they are, got: %+v", items[1])
+	}
+	if body.Data.TotalMacros.Protein != 1 || body.Data.TotalMicros.Sodium != 140 || body.Data.Units != "imperial" {
+		t.Errorf("Expected protein in ounces and sodium in mg, got: %+v", body.Data)
+	}
+	if template.FoodItems[0].Amount != 100 {
+		t.Errorf("Expected the stored amount to stay metric, got: %v", template.FoodItems[0].Amount)
+	}
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/meal-templates/"+template.ID.Hex(), nil))
+	var metric struct {
+		Data response.MealTemplateResponse `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &metric); err != nil {
+		t.Fatalf("Expected a JSON body, got: %v", err)
+	}
+	if metric.Data.FoodItems[0].Amount != 100 || metric.Data.Units != "" {
+		t.Errorf("Expected metric by default, got: %+v", metric.Data.FoodItems[0])
+	}
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/meal-templates/"+template.ID.Hex()+"?units=us", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for an unknown unit system, got: %d", rec.Code)
+	}
+}
+
+func TestGetTemplate_DisplayAmount(t *testing.T) {
+	userID := primitive.NewObjectID()
+	apple := &domain.FoodItem{ID: primitive.NewObjectID(), Visibility: "public", ServingSizes: []domain.ServingSize{
+		{Unit: "gram", Amount: 100, GramEquivalent: 100},
+		{Unit: "piece", Amount: 1, GramEquivalent: 118},
+	}}
+	template := &domain.MealTemplate{
+		ID:     primitive.NewObjectID(),
+		UserID: userID,
+		FoodItems: []domain.MealTemplateFoodItem{
+			{FoodItemID: apple.ID, FoodName: "Apple", ServingUnit: "gram", Amount: 236},
+			{FoodItemID: primitive.NewObjectID(), FoodName: "Deleted", ServingUnit: "gram", Amount: 100},
+		},
+	}
+	router := newTemplateRouter(userID, template, &batchFoodRepo{foods: map[primitive.ObjectID]*domain.FoodItem{apple.ID: apple}})
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/meal-templates/"+template.ID.Hex(), nil))
+	var body struct {
+		Data response.MealTemplateResponse `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Expected a JSON body, got: %v", err)
+	}
+
+	items := body.Data.FoodItems
+	if items[0].Display != "2 pieces" || items[0].Amount != 236 || items[0].ServingUnit != "gram" {
+		t.Errorf("Expected 236 gram shown as \"2 pieces\", got: %+v", items[0])
+	}
+	if items[1].Display != "" {
+		t.Errorf("Expected no display without the food, got: %q", items[1].Display)
+	}
+}
@@ -1,49 +1,574 @@
 package rest
 
 import (
+	"bytes"
+	"context"
+	"fmt"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
 
+	"nutrient_be/internal/config"
+	"nutrient_be/internal/domain"
+	"nutrient_be/internal/dto/request"
+	"nutrient_be/internal/dto/response"
+	"nutrient_be/internal/handler/middleware"
 	"nutrient_be/internal/pkg/logger"
+	planValidator "nutrient_be/internal/pkg/validator"
 	"nutrient_be/internal/service"
 )
 
 // MealPlanHandler handles meal plan endpoints
 type MealPlanHandler struct {
 	mealPlanService *service.MealPlanService
+	structValidator *validator.Validate
+	pageSize        config.PageSizeConfig
 	logger          logger.Logger
+	responseHelper  *middleware.ResponseHelper
 }
 
 // NewMealPlanHandler creates a new meal plan handler
-func NewMealPlanHandler(mealPlanService *service.MealPlanService, log logger.Logger) *MealPlanHandler {
+func NewMealPlanHandler(mealPlanService *service.MealPlanService, pageSize config.PageSizeConfig, log logger.Logger) *MealPlanHandler {
+	structValidator := validator.New()
+	if err := planValidator.RegisterFiniteTag(structValidator); err != nil {
+		panic(fmt.Sprintf("failed to register finite validation: %v", err))
+	}
+
 	return &MealPlanHandler{
 		mealPlanService: mealPlanService,
+		structValidator: structValidator,
+		pageSize:        pageSize,
 		logger:          log,
+		responseHelper:  middleware.NewResponseHelper(),
+	}
+}
+
+// getUserIDFromContext extracts user ID from context or returns error response
+// Returns userID and true if successful, false if error response was sent
+func (h *MealPlanHandler) getUserIDFromContext(c *gin.Context, ctx context.Context) (string, bool) {
+	userIDStr, exists := middleware.GetUserIDFromContext(c)
+	if !exists {
+		h.logger.Error(ctx, "User ID not found in context")
+		h.responseHelper.Unauthorized(c, gin.H{"error": "User not authenticated"}, middleware.MsgAuthenticationRequired)
+		return "", false
+	}
+	return userIDStr, true
+}
+
+// getPlanIDFromParams extracts meal plan ID from URL params or returns error response
+// Returns planID and true if successful, false if error response was sent
+func (h *MealPlanHandler) getPlanIDFromParams(c *gin.Context, ctx context.Context) (string, bool) {
+	planID := c.Param("id")
+	if planID == "" {
+		h.logger.Error(ctx, "Meal plan ID is required")
+		h.responseHelper.BadRequest(c, gin.H{"error": "Meal plan ID is required"}, middleware.MsgMealPlanIDRequired)
+		return "", false
+	}
+	return planID, true
+}
+
+// bindAndValidate binds a JSON request and validates it using struct tags
+// Returns true if successful, false if error response was sent
+func (h *MealPlanHandler) bindAndValidate(c *gin.Context, ctx context.Context, req interface{}, requestType string) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		h.logger.Error(ctx, "Failed to bind request", logger.String("type", requestType), logger.Error(err))
+		h.responseHelper.BadRequest(c, gin.H{"details": err.Error()}, middleware.MsgInvalidRequestBody)
+		return false
+	}
+	if err := h.structValidator.Struct(req); err != nil {
+		h.logger.Error(ctx, "Request validation failed", logger.String("type", requestType), logger.Error(err))
+		h.responseHelper.ValidationError(c, gin.H{"validation_errors": err.Error()}, middleware.MsgValidationFailed)
+		return false
+	}
+	return true
+}
+
+// handleServiceError handles service errors and sends appropriate response
+// Returns true if error was handled, false if no error
+func (h *MealPlanHandler) handleServiceError(c *gin.Context, ctx context.Context, err error, operation string) bool {
+	if err == nil {
+		return false
+	}
+
+	h.logger.Error(ctx, "Service operation failed", logger.String("operation", operation), logger.Error(err))
+
+	if handleDatabaseUnavailable(c, h.responseHelper, err) {
+		return true
+	}
+
+	// Check for specific error types
+	errMsg := err.Error()
+	switch errMsg {
+	case "meal plan not found or access denied":
+		h.responseHelper.ErrorCode(c, middleware.ErrorCodeMealPlanNotFound)
+		h.responseHelper.NotFound(c, gin.H{"error": "Meal plan not found"}, middleware.MsgMealPlanNotFound)
+		return true
+	case "meal not found in meal plan":
+		h.responseHelper.ErrorCode(c, middleware.ErrorCodeMealNotFound)
+		h.responseHelper.NotFound(c, gin.H{"error": "Meal not found"}, middleware.MsgMealNotFound)
+		return true
+	case "day not found in meal plan":
+		h.responseHelper.ErrorCode(c, middleware.ErrorCodeDayNotFound)
+		h.responseHelper.NotFound(c, gin.H{"error": "Day not found"}, middleware.MsgDayNotFound)
+		return true
 	}
+	if strings.HasPrefix(errMsg, "validation failed:") {
+		h.responseHelper.ErrorCode(c, middleware.ErrorCodeValidationFailed)
+		h.responseHelper.BadRequest(c, gin.H{"details": errMsg}, middleware.MsgValidationFailed)
+		return true
+	}
+
+	// Default to internal error
+	h.responseHelper.InternalError(c, gin.H{"details": errMsg}, middleware.MsgOperationFailed)
+	return true
 }
 
 // Create handles meal plan creation
 func (h *MealPlanHandler) Create(c *gin.Context) {
-	c.JSON(http.StatusNotImplemented, gin.H{"message": "Meal plan creation not implemented yet"})
+	ctx := middleware.GetContext(c)
+
+	userIDStr, ok := h.getUserIDFromContext(c, ctx)
+	if !ok {
+		return
+	}
+
+	var req request.CreateMealPlanRequest
+	if !h.bindAndValidate(c, ctx, &req, "CreateMealPlanRequest") {
+		return
+	}
+
+	plan, err := h.mealPlanService.Create(ctx, userIDStr, &req)
+	if h.handleServiceError(c, ctx, err, "create meal plan") {
+		return
+	}
+
+	h.logger.Info(ctx, "Meal plan created successfully")
+	h.responseHelper.Created(c, mealPlanToResponse(plan), middleware.MsgMealPlanCreated)
 }
 
 // List handles listing meal plans
 func (h *MealPlanHandler) List(c *gin.Context) {
-	c.JSON(http.StatusNotImplemented, gin.H{"message": "Meal plan listing not implemented yet"})
+	ctx := middleware.GetContext(c)
+
+	userIDStr, ok := h.getUserIDFromContext(c, ctx)
+	if !ok {
+		return
+	}
+
+	// Get and validate query parameters
+	planType := c.Query("planType")
+	status := c.Query("status")
+	limit, offset, err := parsePagination(c, h.pageSize)
+	if err != nil {
+		h.logger.Error(ctx, "Invalid pagination", logger.Error(err))
+		h.responseHelper.BadRequest(c, gin.H{"error": err.Error()}, middleware.MsgInvalidPagination)
+		return
+	}
+
+	plans, err := h.mealPlanService.ListPlans(ctx, userIDStr, planType, status, limit, offset)
+	if h.handleServiceError(c, ctx, err, "list meal plans") {
+		return
+	}
+
+	planResponses := make([]response.MealPlanResponse, len(plans))
+	for i, plan := range plans {
+		planResponses[i] = mealPlanToResponse(plan)
+	}
+
+	h.logger.Info(ctx, "Meal plans listed successfully")
+	h.responseHelper.Success(c, planResponses, middleware.MsgMealPlansListed)
+}
+
+// ForDate handles listing the meal plans covering a day
+// The "date" query param (YYYY-MM-DD) is required
+func (h *MealPlanHandler) ForDate(c *gin.Context) {
+	ctx := middleware.GetContext(c)
+
+	userIDStr, ok := h.getUserIDFromContext(c, ctx)
+	if !ok {
+		return
+	}
+
+	date, err := time.Parse("2006-01-02", c.Query("date"))
+	if err != nil {
+		h.logger.Error(ctx, "Invalid date format", logger.Error(err))
+		h.responseHelper.BadRequest(c, gin.H{"error": "Date must be in YYYY-MM-DD format"}, middleware.MsgInvalidDate)
+		return
+	}
+
+	plans, err := h.mealPlanService.GetPlansForDate(ctx, userIDStr, date)
+	if h.handleServiceError(c, ctx, err, "get meal plans for date") {
+		return
+	}
+
+	planResponses := make([]response.MealPlanResponse, len(plans))
+	for i, plan := range plans {
+		planResponses[i] = mealPlanToResponse(plan)
+	}
+
+	h.logger.Info(ctx, "Meal plans for date listed successfully")
+	h.responseHelper.Success(c, planResponses, middleware.MsgMealPlansListed)
 }
 
 // Get handles getting a meal plan
 func (h *MealPlanHandler) Get(c *gin.Context) {
-	c.JSON(http.StatusNotImplemented, gin.H{"message": "Meal plan get not implemented yet"})
+	ctx := middleware.GetContext(c)
+
+	userIDStr, ok := h.getUserIDFromContext(c, ctx)
+	if !ok {
+		return
+	}
+
+	planID, ok := h.getPlanIDFromParams(c, ctx)
+	if !ok {
+		return
+	}
+
+	plan, err := h.mealPlanService.GetPlan(ctx, userIDStr, planID)
+	if h.handleServiceError(c, ctx, err, "get meal plan") {
+		return
+	}
+
+	h.logger.Info(ctx, "Meal plan retrieved successfully")
+	h.responseHelper.Success(c, mealPlanToResponse(plan), middleware.MsgMealPlanRetrieved)
+}
+
+// Printable handles rendering a meal plan as an HTML page for browser printing
+func (h *MealPlanHandler) Printable(c *gin.Context) {
+	ctx := middleware.GetContext(c)
+
+	userIDStr, ok := h.getUserIDFromContext(c, ctx)
+	if !ok {
+		return
+	}
+
+	planID, ok := h.getPlanIDFromParams(c, ctx)
+	if !ok {
+		return
+	}
+
+	printable, err := h.mealPlanService.RenderPrintable(ctx, userIDStr, planID)
+	if h.handleServiceError(c, ctx, err, "render printable meal plan") {
+		return
+	}
+
+	var page bytes.Buffer
+	if err := renderPrintableMealPlan(&page, printableToResponse(printable)); err != nil {
+		h.logger.Error(ctx, "Failed to render printable meal plan", logger.Error(err))
+		h.responseHelper.InternalError(c, gin.H{"details": err.Error()}, middleware.MsgMealPlanRenderFailed)
+		return
+	}
+
+	h.logger.Info(ctx, "Printable meal plan rendered successfully")
+	c.Data(http.StatusOK, "text/html; charset=utf-8", page.Bytes())
 }
 
 // Update handles meal plan update
 func (h *MealPlanHandler) Update(c *gin.Context) {
-	c.JSON(http.StatusNotImplemented, gin.H{"message": "Meal plan update not implemented yet"})
+	ctx := middleware.GetContext(c)
+
+	userIDStr, ok := h.getUserIDFromContext(c, ctx)
+	if !ok {
+		return
+	}
+
+	planID, ok := h.getPlanIDFromParams(c, ctx)
+	if !ok {
+		return
+	}
+
+	var req request.UpdateMealPlanRequest
+	if !h.bindAndValidate(c, ctx, &req, "UpdateMealPlanRequest") {
+		return
+	}
+
+	plan, err := h.mealPlanService.UpdatePlan(ctx, userIDStr, planID, &req)
+	if h.handleServiceError(c, ctx, err, "update meal plan") {
+		return
+	}
+
+	h.logger.Info(ctx, "Meal plan updated successfully")
+	h.responseHelper.Success(c, mealPlanToResponse(plan), middleware.MsgMealPlanUpdated)
+}
+
+// UpdateTargets handles updating the daily targets of a meal plan
+func (h *MealPlanHandler) UpdateTargets(c *gin.Context) {
+	ctx := middleware.GetContext(c)
+
+	userIDStr, ok := h.getUserIDFromContext(c, ctx)
+	if !ok {
+		return
+	}
+
+	planID, ok := h.getPlanIDFromParams(c, ctx)
+	if !ok {
+		return
+	}
+
+	var req request.UpdateMealPlanTargetsRequest
+	if !h.bindAndValidate(c, ctx, &req, "UpdateMealPlanTargetsRequest") {
+		return
+	}
+
+	var macros *domain.MacroNutrients
+	if req.TargetMacros != nil {
+		macros = &domain.MacroNutrients{
+			Protein:       req.TargetMacros.Protein,
+			Carbohydrates: req.TargetMacros.Carbohydrates,
+			Fat:           req.TargetMacros.Fat,
+			Fiber:         req.TargetMacros.Fiber,
+			Sugar:         req.TargetMacros.Sugar,
+		}
+	}
+
+	plan, err := h.mealPlanService.UpdateTargets(ctx, userIDStr, planID, req.TargetCalories, macros, req.AutoGenerate)
+	if h.handleServiceError(c, ctx, err, "update meal plan targets") {
+		return
+	}
+
+	h.logger.Info(ctx, "Meal plan targets updated successfully")
+	h.responseHelper.Success(c, mealPlanToResponse(plan), middleware.MsgMealPlanTargetsUpdated)
 }
 
 // Delete handles meal plan deletion
 func (h *MealPlanHandler) Delete(c *gin.Context) {
-	c.JSON(http.StatusNotImplemented, gin.H{"message": "Meal plan deletion not implemented yet"})
+	ctx := middleware.GetContext(c)
+
+	userIDStr, ok := h.getUserIDFromContext(c, ctx)
+	if !ok {
+		return
+	}
+
+	planID, ok := h.getPlanIDFromParams(c, ctx)
+	if !ok {
+		return
+	}
+
+	err := h.mealPlanService.DeletePlan(ctx, userIDStr, planID)
+	if h.handleServiceError(c, ctx, err, "delete meal plan") {
+		return
+	}
+
+	h.logger.Info(ctx, "Meal plan deleted successfully")
+	h.responseHelper.Success(c, gin.H{"message": "Meal plan deleted successfully"}, middleware.MsgMealPlanDeleted)
+}
+
+// UpdateMealNotes handles updating the notes of a meal in a plan
+func (h *MealPlanHandler) UpdateMealNotes(c *gin.Context) {
+	ctx := middleware.GetContext(c)
+
+	userIDStr, ok := h.getUserIDFromContext(c, ctx)
+	if !ok {
+		return
+	}
+
+	planID, ok := h.getPlanIDFromParams(c, ctx)
+	if !ok {
+		return
+	}
+
+	var req request.UpdateNotesRequest
+	if !h.bindAndValidate(c, ctx, &req, "UpdateNotesRequest") {
+		return
+	}
+
+	plan, err := h.mealPlanService.UpdateMealNotes(ctx, userIDStr, planID, c.Param("mealId"), req.Notes)
+	if h.handleServiceError(c, ctx, err, "update meal notes") {
+		return
+	}
+
+	h.logger.Info(ctx, "Meal notes updated successfully")
+	h.responseHelper.Success(c, mealPlanToResponse(plan), middleware.MsgMealNotesUpdated)
+}
+
+// UpdateDayNotes handles updating the notes of a day in a plan
+func (h *MealPlanHandler) UpdateDayNotes(c *gin.Context) {
+	ctx := middleware.GetContext(c)
+
+	userIDStr, ok := h.getUserIDFromContext(c, ctx)
+	if !ok {
+		return
+	}
+
+	planID, ok := h.getPlanIDFromParams(c, ctx)
+	if !ok {
+		return
+	}
+
+	date, err := time.Parse("2006-01-02", c.Param("date"))
+	if err != nil {
+		h.logger.Error(ctx, "Invalid date parameter", logger.Error(err))
+		h.responseHelper.BadRequest(c, gin.H{"error": "Date must be in YYYY-MM-DD format"}, middleware.MsgInvalidDate)
+		return
+	}
+
+	var req request.UpdateNotesRequest
+	if !h.bindAndValidate(c, ctx, &req, "UpdateNotesRequest") {
+		return
+	}
+
+	plan, err := h.mealPlanService.UpdateDayNotes(ctx, userIDStr, planID, date, req.Notes)
+	if h.handleServiceError(c, ctx, err, "update day notes") {
+		return
+	}
+
+	h.logger.Info(ctx, "Day notes updated successfully")
+	h.responseHelper.Success(c, mealPlanToResponse(plan), middleware.MsgDayNotesUpdated)
+}
+
+// RegenerateDay handles replacing the open meals of one day in a meal plan
+func (h *MealPlanHandler) RegenerateDay(c *gin.Context) {
+	ctx := middleware.GetContext(c)
+
+	userIDStr, ok := h.getUserIDFromContext(c, ctx)
+	if !ok {
+		return
+	}
+
+	planID, ok := h.getPlanIDFromParams(c, ctx)
+	if !ok {
+		return
+	}
+
+	date, err := time.Parse("2006-01-02", c.Param("date"))
+	if err != nil {
+		h.logger.Error(ctx, "Invalid date parameter", logger.Error(err))
+		h.responseHelper.BadRequest(c, gin.H{"error": "Date must be in YYYY-MM-DD format"}, middleware.MsgInvalidDate)
+		return
+	}
+
+	plan, unfilled, err := h.mealPlanService.RegenerateDay(ctx, userIDStr, planID, date)
+	if h.handleServiceError(c, ctx, err, "regenerate meal plan day") {
+		return
+	}
+
+	resp := response.RegeneratedMealPlanResponse{
+		MealPlanResponse: mealPlanToResponse(plan),
+		UnfilledSlots:    make([]response.UnfilledSlotResponse, len(unfilled)),
+	}
+	for i, slot := range unfilled {
+		resp.UnfilledSlots[i] = response.UnfilledSlotResponse{Date: slot.Date, MealType: slot.MealType, Reason: slot.Reason}
+	}
+
+	h.logger.Info(ctx, "Meal plan day regenerated successfully", logger.Int("unfilled_slots", len(unfilled)))
+	h.responseHelper.Success(c, resp, middleware.MsgMealPlanDayRegenerated)
+}
+
+// SuggestSwaps handles listing templates that could replace a meal in a plan
+func (h *MealPlanHandler) SuggestSwaps(c *gin.Context) {
+	ctx := middleware.GetContext(c)
+
+	userIDStr, ok := h.getUserIDFromContext(c, ctx)
+	if !ok {
+		return
+	}
+
+	planID, ok := h.getPlanIDFromParams(c, ctx)
+	if !ok {
+		return
+	}
+
+	suggestions, err := h.mealPlanService.SuggestSwaps(ctx, userIDStr, planID, c.Param("mealId"))
+	if h.handleServiceError(c, ctx, err, "suggest meal swaps") {
+		return
+	}
+
+	swaps := make([]response.MealSwapResponse, len(suggestions))
+	for i, suggestion := range suggestions {
+		swaps[i] = response.MealSwapResponse{
+			Template:     mealTemplateToResponse(suggestion.Template),
+			CalorieDelta: response.RoundCalories(suggestion.CalorieDelta),
+		}
+	}
+
+	h.logger.Info(ctx, "Meal swaps suggested successfully", logger.Int("count", len(swaps)))
+	h.responseHelper.Success(c, swaps, middleware.MsgMealSwapsSuggested)
+}
+
+// macrosToResponse converts domain MacroNutrients to a response MacroNutrientsResponse
+func macrosToResponse(macros domain.MacroNutrients) response.MacroNutrientsResponse {
+	return response.MacroNutrientsResponse{
+		Protein:       macros.Protein,
+		Carbohydrates: macros.Carbohydrates,
+		Fat:           macros.Fat,
+		Fiber:         macros.Fiber,
+		Sugar:         macros.Sugar,
+	}
+}
+
+// mealPlanToResponse converts a domain MealPlan to a response MealPlanResponse
+func mealPlanToResponse(plan *domain.MealPlan) response.MealPlanResponse {
+	dailyMeals := make([]response.DailyMealResponse, len(plan.DailyMeals))
+	for i, day := range plan.DailyMeals {
+		dailyMeals[i] = dailyMealToResponse(day, plan.TargetCalories)
+	}
+
+	// Build response
+	return response.MealPlanResponse{
+		ID:             plan.ID.Hex(),
+		UserID:         plan.UserID.Hex(),
+		Name:           plan.Name,
+		Description:    plan.Description,
+		StartDate:      plan.StartDate,
+		EndDate:        plan.EndDate,
+		PlanType:       plan.PlanType,
+		Goal:           plan.Goal,
+		TargetCalories: response.RoundCalories(plan.TargetCalories),
+		TargetMacros:   macrosToResponse(plan.TargetMacros),
+		DailyMeals:     dailyMeals,
+		TotalCalories:  response.RoundCalories(plan.TotalCalories),
+		Status:         plan.Status,
+		CreatedAt:      plan.CreatedAt,
+		UpdatedAt:      plan.UpdatedAt,
+	}
+}
+
+// dailyMealToResponse converts a day of a meal plan, comparing its total against the daily target
+func dailyMealToResponse(day domain.DailyMeal, targetCalories float64) response.DailyMealResponse {
+	meals := make([]response.MealResponse, len(day.Meals))
+	for j, meal := range day.Meals {
+		foodItems := make([]response.MealFoodItemResponse, len(meal.FoodItems))
+		for k, item := range meal.FoodItems {
+			foodItems[k] = response.MealFoodItemResponse{
+				FoodItemID:   item.FoodItemID.Hex(),
+				FoodName:     item.FoodName,
+				FoodCategory: item.FoodCategory,
+				ServingUnit:  item.ServingUnit,
+				Amount:       item.Amount,
+				Calories:     response.RoundCalories(item.Calories),
+				Macros:       macrosToResponse(item.Macros),
+			}
+		}
+
+		templateID := ""
+		if meal.TemplateID != nil {
+			templateID = meal.TemplateID.Hex()
+		}
+
+		meals[j] = response.MealResponse{
+			ID:          meal.ID,
+			MealType:    meal.MealType,
+			Time:        meal.Time,
+			TemplateID:  templateID,
+			FoodItems:   foodItems,
+			Calories:    response.RoundCalories(meal.Calories),
+			Macros:      macrosToResponse(meal.Macros),
+			Notes:       meal.Notes,
+			IsCompleted: meal.IsCompleted,
+		}
+	}
+
+	return response.DailyMealResponse{
+		Date:              day.Date,
+		DayOfWeek:         day.DayOfWeek,
+		Meals:             meals,
+		TotalCalories:     response.RoundCalories(day.TotalCalories),
+		TotalMacros:       macrosToResponse(day.TotalMacros),
+		CalorieDifference: response.RoundCalories(day.TotalCalories - targetCalories),
+		Notes:             day.Notes,
+		IsCompleted:       day.IsCompleted,
+	}
 }
@@ -0,0 +1,41 @@
+package rest
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"nutrient_be/internal/dto/response"
+	"nutrient_be/internal/handler/middleware"
+	"nutrient_be/internal/pkg/logger"
+	foodValidator "nutrient_be/internal/pkg/validator"
+)
+
+// MetadataHandler handles endpoints describing the values clients can choose from
+// Values come from the validator registries so they always match what is accepted
+type MetadataHandler struct {
+	logger         logger.Logger
+	responseHelper *middleware.ResponseHelper
+}
+
+// NewMetadataHandler creates a new metadata handler
+func NewMetadataHandler(log logger.Logger) *MetadataHandler {
+	return &MetadataHandler{
+		logger:         log,
+		responseHelper: middleware.NewResponseHelper(),
+	}
+}
+
+// Units handles listing the supported serving units and what they measure
+func (h *MetadataHandler) Units(c *gin.Context) {
+	units := foodValidator.ServingUnits()
+	unitResponses := make([]response.ServingUnitResponse, len(units))
+	for i, unit := range units {
+		unitResponses[i] = response.ServingUnitResponse{Name: unit.Name, Kind: unit.Kind}
+	}
+
+	h.responseHelper.Success(c, unitResponses, middleware.MsgServingUnitsRetrieved)
+}
+
+// Categories handles listing the supported food categories
+func (h *MetadataHandler) Categories(c *gin.Context) {
+	h.responseHelper.Success(c, foodValidator.FoodCategories(), middleware.MsgFoodCategoriesRetrieved)
+}
@@ -0,0 +1,86 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"nutrient_be/internal/dto/response"
+	"nutrient_be/internal/handler/middleware"
+	"nutrient_be/internal/pkg/logger"
+)
+
+func newMetadataRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	log := logger.NewNoopLogger()
+	h := NewMetadataHandler(log)
+
+	router := gin.New()
+	router.Use(middleware.ResponseMiddleware(log))
+	router.GET("/metadata/units", h.Units)
+	router.GET("/metadata/categories", h.Categories)
+	return router
+}
+
+func TestMetadataHandler_Units(t *testing.T) {
+	rec := httptest.NewRecorder()
+	newMetadataRouter().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metadata/units", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got: %d", rec.Code)
+	}
+
+	var body struct {
+		Data []response.ServingUnitResponse `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Expected JSON body, got: %v", err)
+	}
+
+	kinds := make(map[string]string)
+	for _, unit := range body.Data {
+		kinds[unit.Name] = unit.Kind
+	}
+	expected := map[string]string{
+		"gram":  "mass",
+		"kg":    "mass",
+		"ml":    "volume",
+		"cup":   "volume",
+		"piece": "count",
+		"box":   "count",
+	}
+	for name, kind := range expected {
+		if kinds[name] != kind {
+			t.Errorf("Expected unit %s with kind %s, got: %q", name, kind, kinds[name])
+		}
+	}
+}
+
+func TestMetadataHandler_Categories(t *testing.T) {
+	rec := httptest.NewRecorder()
+	newMetadataRouter().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metadata/categories", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got: %d", rec.Code)
+	}
+
+	var body struct {
+		Data []string `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Expected JSON body, got: %v", err)
+	}
+
+	found := make(map[string]bool)
+	for _, category := range body.Data {
+		found[category] = true
+	}
+	for _, category := range []string{"protein", "vegetable", "fruit", "dairy", "grain"} {
+		if !found[category] {
+			t.Errorf("Expected category %s to be listed", category)
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package rest
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"nutrient_be/internal/config"
+)
+
+// parsePagination reads the limit and offset query parameters of a list endpoint
+// A missing or malformed limit falls back to the endpoint default; a limit above the endpoint max is rejected
+func parsePagination(c *gin.Context, sizes config.PageSizeConfig) (limit, offset int, err error) {
+	limit = sizes.Default
+	if parsed, err := strconv.Atoi(c.Query("limit")); err == nil && parsed >= 1 {
+		limit = parsed
+	}
+	if limit > sizes.Max {
+		return 0, 0, fmt.Errorf("invalid limit: %d exceeds the maximum page size of %d", limit, sizes.Max)
+	}
+
+	offset, err = strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	return limit, offset, nil
+}
@@ -0,0 +1,229 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"nutrient_be/internal/config"
+	"nutrient_be/internal/domain"
+	"nutrient_be/internal/pkg/cache"
+	"nutrient_be/internal/pkg/events"
+	"nutrient_be/internal/pkg/logger"
+	"nutrient_be/internal/service"
+)
+
+// The recording repositories embed the service interfaces so only the list methods need implementing
+
+type recordingFoodRepo struct {
+	service.FoodRepository
+	limit int
+}
+
+func (r *recordingFoodRepo) FindFoods(ctx context.Context, filter domain.FoodFilter, page domain.Page, sortBy string) ([]*domain.FoodItem, error) {
+	r.limit = page.Limit
+	return nil, nil
+}
+
+func (r *recordingFoodRepo) GetPublicFoods(ctx context.Context, limit, offset int) ([]*domain.FoodItem, error) {
+	r.limit = limit
+	return nil, nil
+}
+
+type recordingTemplateRepo struct {
+	service.MealTemplateRepository
+	limit int
+}
+
+func (r *recordingTemplateRepo) GetByUser(ctx context.Context, userID primitive.ObjectID, mealType string, limit, offset int) ([]*domain.MealTemplate, error) {
+	r.limit = limit
+	return nil, nil
+}
+
+type recordingPlanRepo struct {
+	service.MealPlanRepository
+	limit int
+}
+
+func (r *recordingPlanRepo) GetByUser(ctx context.Context, userID primitive.ObjectID, planType, status string, limit, offset int) ([]*domain.MealPlan, error) {
+	r.limit = limit
+	return nil, nil
+}
+
+type recordingShoppingRepo struct {
+	service.ShoppingListRepository
+	limit int
+}
+
+func (r *recordingShoppingRepo) GetByUser(ctx context.Context, userID primitive.ObjectID, status string, limit, offset int) ([]*domain.ShoppingList, error) {
+	r.limit = limit
+	return nil, nil
+}
+
+// paginationFixture wires each list endpoint to a recording repository
+type paginationFixture struct {
+	router    *gin.Engine
+	foods     *recordingFoodRepo
+	templates *recordingTemplateRepo
+	plans     *recordingPlanRepo
+	shopping  *recordingShoppingRepo
+}
+
+func newPaginationFixture(pagination config.PaginationConfig) *paginationFixture {
+	gin.SetMode(gin.TestMode)
+	log := logger.NewNoopLogger()
+	f := &paginationFixture{
+		foods:     &recordingFoodRepo{},
+		templates: &recordingTemplateRepo{},
+		plans:     &recordingPlanRepo{},
+		shopping:  &recordingShoppingRepo{},
+	}
+
+	users := service.NewUserService(&profileUserRepo{user: &domain.User{}}, nil, nil, nil, nil, config.UserConfig{}, log)
+	foodHandler := NewFoodHandler(service.NewFoodService(f.foods, nil, nil, service.NewNoopTransactor(), cache.NewNoopCache(), nil, 0, config.FoodConfig{}, log), users, 0, pagination, log)
+	mealHandler := NewMealHandler(service.NewMealService(f.templates, nil, service.NewNoopTransactor(), config.MealConfig{}, log), pagination.MealTemplates, config.MealConfig{}, log)
+	planHandler := NewMealPlanHandler(service.NewMealPlanService(f.plans, nil, nil, nil, config.UserConfig{}, events.NewNoopPublisher(), log), pagination.MealPlans, log)
+	shoppingHandler := NewShoppingHandler(service.NewShoppingService(f.shopping, nil, nil, log), pagination.ShoppingLists, log)
+
+	userID := primitive.NewObjectID().Hex()
+	f.router = gin.New()
+	f.router.Use(func(c *gin.Context) { c.Set("userID", userID) })
+	f.router.GET("/foods/search", foodHandler.Search)
+	f.router.GET("/foods", foodHandler.List)
+	f.router.GET("/meal-templates", mealHandler.ListTemplates)
+	f.router.GET("/meal-plans", planHandler.List)
+	f.router.GET("/shopping-lists", shoppingHandler.List)
+	return f
+}
+
+func testPaginationConfig() config.PaginationConfig {
+	return config.PaginationConfig{
+		FoodSearch:    config.PageSizeConfig{Default: 20, Max: 100},
+		FoodList:      config.PageSizeConfig{Default: 50, Max: 100},
+		MealTemplates: config.PageSizeConfig{Default: 15, Max: 60},
+		MealPlans:     config.PageSizeConfig{Default: 10, Max: 30},
+		ShoppingLists: config.PageSizeConfig{Default: 5, Max: 25},
+	}
+}
+
+func (f *paginationFixture) get(path string) int {
+	rec := httptest.NewRecorder()
+	f.router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	return rec.Code
+}
+
+func TestListEndpoints_UseConfiguredDefaultPageSize(t *testing.T) {
+	f := newPaginationFixture(testPaginationConfig())
+
+	tests := []struct {
+		path     string
+		limit    func() int
+		expected int
+	}{
+		{"/foods/search?query=rice", func() int { return f.foods.limit }, 20},
+		{"/foods", func() int { return f.foods.limit }, 50},
+		{"/meal-templates", func() int { return f.templates.limit }, 15},
+		{"/meal-plans", func() int { return f.plans.limit }, 10},
+		{"/shopping-lists", func() int { return f.shopping.limit }, 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if code := f.get(tt.path); code != http.StatusOK {
+				t.Fatalf("Expected status 200, got: %d", code)
+			}
+			if got := tt.limit(); got != tt.expected {
+				t.Errorf("Expected default limit %d, got: %d", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestListEndpoints_RejectLimitAboveMax(t *testing.T) {
+	f := newPaginationFixture(testPaginationConfig())
+
+	paths := []string{
+		"/foods/search?query=rice&limit=101",
+		"/foods?limit=101",
+		"/meal-templates?limit=61",
+		"/meal-plans?limit=31",
+		"/shopping-lists?limit=26",
+	}
+
+	for _, path := range paths {
+		t.Run(path, func(t *testing.T) {
+			if code := f.get(path); code != http.StatusBadRequest {
+				t.Errorf("Expected status 400, got: %d", code)
+			}
+		})
+	}
+}
+
+func TestListEndpoints_AcceptLimitUpToMax(t *testing.T) {
+	f := newPaginationFixture(testPaginationConfig())
+
+	if code := f.get("/meal-plans?limit=30"); code != http.StatusOK {
+		t.Fatalf("Expected status 200, got: %d", code)
+	}
+	if f.plans.limit != 30 {
+		t.Errorf("Expected limit 30, got: %d", f.plans.limit)
+	}
+}
+
+func TestFoodSearch_RejectsQueryLengthOutOfRange(t *testing.T) {
+	f := newPaginationFixture(testPaginationConfig())
+
+	paths := map[string]string{
+		"too short": "/foods/search?query=a",
+		"too long":  "/foods/search?query=" + strings.Repeat("a", 101),
+	}
+
+	for name, path := range paths {
+		t.Run(name, func(t *testing.T) {
+			if code := f.get(path); code != http.StatusBadRequest {
+				t.Errorf("Expected status 400, got: %d", code)
+			}
+		})
+	}
+
+	if code := f.get("/foods/search?query=" + strings.Repeat("a", 100)); code != http.StatusOK {
+		t.Errorf("Expected status 200 for a 100 character query, got: %d", code)
+	}
+}
+
+func TestFoodSearch_ValidatesSource(t *testing.T) {
+	f := newPaginationFixture(testPaginationConfig())
+
+	if code := f.get("/foods/search?query=rice&source=scraped"); code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for an unknown source, got: %d", code)
+	}
+	if code := f.get("/foods/search?query=rice&source=imported&importedFirst=true"); code != http.StatusOK {
+		t.Errorf("Expected status 200 for the imported source, got: %d", code)
+	}
+}
+
+func TestFoodSearch_ValidatesFilters(t *testing.T) {
+	f := newPaginationFixture(testPaginationConfig())
+
+	paths := map[string]string{
+		"unknown category": "/foods/search?query=rice&category=rock",
+		"negative bound":   "/foods/search?query=rice&minProtein=-1",
+		"inverted range":   "/foods/search?query=rice&minCalories=300&maxCalories=100",
+	}
+	for name, path := range paths {
+		t.Run(name, func(t *testing.T) {
+			if code := f.get(path); code != http.StatusBadRequest {
+				t.Errorf("Expected status 400, got: %d", code)
+			}
+		})
+	}
+
+	if code := f.get("/foods/search?query=rice&category=grain&category=protein&tag=vegan&maxFat=5"); code != http.StatusOK {
+		t.Errorf("Expected status 200 for valid filters, got: %d", code)
+	}
+}
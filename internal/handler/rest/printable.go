@@ -0,0 +1,46 @@
+package rest
+
+import (
+	"embed"
+	"html/template"
+	"io"
+
+	"nutrient_be/internal/dto/response"
+	"nutrient_be/internal/service"
+)
+
+//go:embed templates/mealplan_printable.html
+var printableTemplates embed.FS
+
+// printableMealPlanTemplate renders a meal plan as a print-friendly HTML page, one page per week
+var printableMealPlanTemplate = template.Must(template.ParseFS(printableTemplates, "templates/mealplan_printable.html"))
+
+// printableToResponse converts a printable meal plan into response structs for rendering
+func printableToResponse(printable *service.PrintableMealPlan) response.PrintableMealPlanResponse {
+	planResponse := mealPlanToResponse(printable.Plan)
+	planResponse.DailyMeals = nil // Days are listed per week instead
+
+	weeks := make([]response.PrintableWeekResponse, len(printable.Weeks))
+	for i, week := range printable.Weeks {
+		days := make([]response.DailyMealResponse, len(week.Days))
+		for j, day := range week.Days {
+			days[j] = dailyMealToResponse(day, printable.Plan.TargetCalories)
+		}
+		weeks[i] = response.PrintableWeekResponse{
+			Number:    week.Number,
+			StartDate: week.StartDate,
+			EndDate:   week.EndDate,
+			Days:      days,
+		}
+	}
+
+	return response.PrintableMealPlanResponse{
+		Plan:  planResponse,
+		Weeks: weeks,
+	}
+}
+
+// renderPrintableMealPlan writes the HTML page for a printable meal plan
+func renderPrintableMealPlan(w io.Writer, printable response.PrintableMealPlanResponse) error {
+	return printableMealPlanTemplate.Execute(w, printable)
+}
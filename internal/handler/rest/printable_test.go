@@ -0,0 +1,71 @@
+package rest
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"nutrient_be/internal/domain"
+	"nutrient_be/internal/service"
+)
+
+func TestRenderPrintableMealPlan(t *testing.T) {
+	start := time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)
+	days := []domain.DailyMeal{
+		{
+			Date:      start,
+			DayOfWeek: "Monday",
+			Meals: []domain.Meal{{
+				ID:       "meal_1",
+				MealType: "breakfast",
+				FoodItems: []domain.MealFoodItem{
+					{FoodItemID: primitive.NewObjectID(), FoodName: "Oats", ServingUnit: "gram", Amount: 80, Calories: 311},
+				},
+				Calories: 311,
+			}},
+			TotalCalories: 1850,
+		},
+		{Date: start.AddDate(0, 0, 1), DayOfWeek: "Tuesday", TotalCalories: 2125},
+		{Date: start.AddDate(0, 0, 7), DayOfWeek: "Monday", TotalCalories: 1990},
+	}
+	plan := &domain.MealPlan{
+		ID:             primitive.NewObjectID(),
+		Name:           "Cutting <phase>",
+		StartDate:      start,
+		EndDate:        start.AddDate(0, 0, 7),
+		TargetCalories: 2000,
+		DailyMeals:     days,
+	}
+	printable := &service.PrintableMealPlan{
+		Plan: plan,
+		Weeks: []service.PrintableWeek{
+			{Number: 1, StartDate: start, EndDate: start.AddDate(0, 0, 6), Days: days[:2]},
+			{Number: 2, StartDate: start.AddDate(0, 0, 7), EndDate: start.AddDate(0, 0, 7), Days: days[2:]},
+		},
+	}
+
+	var page bytes.Buffer
+	if err := renderPrintableMealPlan(&page, printableToResponse(printable)); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	html := page.String()
+
+	for _, expected := range []string{
+		"Monday, Jan 6, 2025", "1850",
+		"Tuesday, Jan 7, 2025", "2125",
+		"Monday, Jan 13, 2025", "1990",
+		"Week 1", "Week 2",
+		"Oats", "-150", "&#43;125",
+		"Cutting &lt;phase&gt;",
+	} {
+		if !strings.Contains(html, expected) {
+			t.Errorf("Expected printable page to contain %q", expected)
+		}
+	}
+	if strings.Count(html, `<section class="week">`) != 2 {
+		t.Errorf("Expected one page per week, got %d", strings.Count(html, `<section class="week">`))
+	}
+}
@@ -1,34 +1,272 @@
 package rest
 
 import (
-	"net/http"
+	"context"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 
+	"nutrient_be/internal/handler/middleware"
 	"nutrient_be/internal/pkg/logger"
 	"nutrient_be/internal/service"
 )
 
 // ReportHandler handles report endpoints
 type ReportHandler struct {
-	reportService *service.ReportService
-	logger        logger.Logger
+	reportService  *service.ReportService
+	logger         logger.Logger
+	responseHelper *middleware.ResponseHelper
 }
 
 // NewReportHandler creates a new report handler
 func NewReportHandler(reportService *service.ReportService, log logger.Logger) *ReportHandler {
 	return &ReportHandler{
-		reportService: reportService,
-		logger:        log,
+		reportService:  reportService,
+		logger:         log,
+		responseHelper: middleware.NewResponseHelper(),
 	}
 }
 
+// getUserIDFromContext extracts user ID from context or returns error response
+// Returns userID and true if successful, false if error response was sent
+func (h *ReportHandler) getUserIDFromContext(c *gin.Context, ctx context.Context) (string, bool) {
+	userIDStr, exists := middleware.GetUserIDFromContext(c)
+	if !exists {
+		h.logger.Error(ctx, "User ID not found in context")
+		h.responseHelper.Unauthorized(c, gin.H{"error": "User not authenticated"}, middleware.MsgAuthenticationRequired)
+		return "", false
+	}
+	return userIDStr, true
+}
+
+// handleServiceError handles service errors and sends appropriate response
+// Returns true if error was handled, false if no error
+func (h *ReportHandler) handleServiceError(c *gin.Context, ctx context.Context, err error, operation string) bool {
+	if err == nil {
+		return false
+	}
+
+	h.logger.Error(ctx, "Service operation failed", logger.String("operation", operation), logger.Error(err))
+
+	if handleDatabaseUnavailable(c, h.responseHelper, err) {
+		return true
+	}
+	if err.Error() == "meal plan not found or access denied" {
+		h.responseHelper.ErrorCode(c, middleware.ErrorCodeMealPlanNotFound)
+		h.responseHelper.NotFound(c, gin.H{"error": "Meal plan not found"}, middleware.MsgMealPlanNotFound)
+		return true
+	}
+	if strings.HasPrefix(err.Error(), "invalid ") {
+		h.responseHelper.BadRequest(c, gin.H{"error": err.Error()}, middleware.MsgInvalidRequest)
+		return true
+	}
+	h.responseHelper.InternalError(c, gin.H{"details": err.Error()}, middleware.MsgOperationFailed)
+	return true
+}
+
 // Weekly handles weekly reports
+// The week containing the "date" query param (YYYY-MM-DD, default today) is reported
 func (h *ReportHandler) Weekly(c *gin.Context) {
-	c.JSON(http.StatusNotImplemented, gin.H{"message": "Weekly reports not implemented yet"})
+	ctx := middleware.GetContext(c)
+
+	userIDStr, ok := h.getUserIDFromContext(c, ctx)
+	if !ok {
+		return
+	}
+
+	date := time.Now().UTC()
+	if dateStr := c.Query("date"); dateStr != "" {
+		parsed, err := time.Parse("2006-01-02", dateStr)
+		if err != nil {
+			h.logger.Error(ctx, "Invalid date format", logger.Error(err))
+			h.responseHelper.BadRequest(c, gin.H{"error": "Date must be in YYYY-MM-DD format"}, middleware.MsgInvalidDate)
+			return
+		}
+		date = parsed
+	}
+
+	report, err := h.reportService.GenerateWeekly(ctx, userIDStr, date)
+	if h.handleServiceError(c, ctx, err, "generate weekly report") {
+		return
+	}
+
+	h.logger.Info(ctx, "Weekly report generated successfully")
+	h.responseHelper.Success(c, report, middleware.MsgWeeklyReportGenerated)
 }
 
 // Monthly handles monthly reports
+// The "month" query param (YYYY-MM, default current month) selects the month
 func (h *ReportHandler) Monthly(c *gin.Context) {
-	c.JSON(http.StatusNotImplemented, gin.H{"message": "Monthly reports not implemented yet"})
+	ctx := middleware.GetContext(c)
+
+	userIDStr, ok := h.getUserIDFromContext(c, ctx)
+	if !ok {
+		return
+	}
+
+	month := time.Now().UTC()
+	if monthStr := c.Query("month"); monthStr != "" {
+		parsed, err := time.Parse("2006-01", monthStr)
+		if err != nil {
+			h.logger.Error(ctx, "Invalid month format", logger.Error(err))
+			h.responseHelper.BadRequest(c, gin.H{"error": "Month must be in YYYY-MM format"}, middleware.MsgInvalidMonth)
+			return
+		}
+		month = parsed
+	}
+
+	report, err := h.reportService.GenerateMonthly(ctx, userIDStr, month.Year(), month.Month())
+	if h.handleServiceError(c, ctx, err, "generate monthly report") {
+		return
+	}
+
+	h.logger.Info(ctx, "Monthly report generated successfully")
+	h.responseHelper.Success(c, report, middleware.MsgMonthlyReportGenerated)
+}
+
+// Progress handles goal progress reports
+// The range is given by "startDate" and "endDate" (YYYY-MM-DD) and defaults to the last 30 days
+func (h *ReportHandler) Progress(c *gin.Context) {
+	ctx := middleware.GetContext(c)
+
+	userIDStr, ok := h.getUserIDFromContext(c, ctx)
+	if !ok {
+		return
+	}
+
+	endDate := time.Now().UTC()
+	if endStr := c.Query("endDate"); endStr != "" {
+		parsed, err := time.Parse("2006-01-02", endStr)
+		if err != nil {
+			h.logger.Error(ctx, "Invalid end date format", logger.Error(err))
+			h.responseHelper.BadRequest(c, gin.H{"error": "endDate must be in YYYY-MM-DD format"}, middleware.MsgInvalidDate)
+			return
+		}
+		endDate = parsed
+	}
+
+	startDate := endDate.AddDate(0, 0, -29)
+	if startStr := c.Query("startDate"); startStr != "" {
+		parsed, err := time.Parse("2006-01-02", startStr)
+		if err != nil {
+			h.logger.Error(ctx, "Invalid start date format", logger.Error(err))
+			h.responseHelper.BadRequest(c, gin.H{"error": "startDate must be in YYYY-MM-DD format"}, middleware.MsgInvalidDate)
+			return
+		}
+		startDate = parsed
+	}
+
+	if startDate.After(endDate) {
+		h.responseHelper.BadRequest(c, gin.H{"error": "startDate must not be after endDate"}, middleware.MsgInvalidDateRange)
+		return
+	}
+
+	progress, err := h.reportService.GoalProgress(ctx, userIDStr, startDate, endDate)
+	if h.handleServiceError(c, ctx, err, "generate goal progress report") {
+		return
+	}
+
+	h.logger.Info(ctx, "Goal progress report generated successfully")
+	h.responseHelper.Success(c, progress, middleware.MsgProgressReportGenerated)
+}
+
+// Range handles aggregate nutrition over a custom date range
+// The "start" and "end" query params (YYYY-MM-DD) are both required and inclusive
+func (h *ReportHandler) Range(c *gin.Context) {
+	ctx := middleware.GetContext(c)
+
+	userIDStr, ok := h.getUserIDFromContext(c, ctx)
+	if !ok {
+		return
+	}
+
+	startDate, err := time.Parse("2006-01-02", c.Query("start"))
+	if err != nil {
+		h.logger.Error(ctx, "Invalid start date format", logger.Error(err))
+		h.responseHelper.BadRequest(c, gin.H{"error": "start is required in YYYY-MM-DD format"}, middleware.MsgInvalidDate)
+		return
+	}
+	endDate, err := time.Parse("2006-01-02", c.Query("end"))
+	if err != nil {
+		h.logger.Error(ctx, "Invalid end date format", logger.Error(err))
+		h.responseHelper.BadRequest(c, gin.H{"error": "end is required in YYYY-MM-DD format"}, middleware.MsgInvalidDate)
+		return
+	}
+
+	summary, err := h.reportService.RangeSummary(ctx, userIDStr, startDate, endDate)
+	if h.handleServiceError(c, ctx, err, "generate range summary") {
+		return
+	}
+
+	h.logger.Info(ctx, "Range summary generated successfully")
+	h.responseHelper.Success(c, summary, middleware.MsgRangeSummaryGenerated)
+}
+
+// CalorieTrend handles the consumed calories over a date range as a daily or weekly series
+// The "start" and "end" query params (YYYY-MM-DD) are both required and inclusive; "granularity" is day (default) or week
+func (h *ReportHandler) CalorieTrend(c *gin.Context) {
+	ctx := middleware.GetContext(c)
+
+	userIDStr, ok := h.getUserIDFromContext(c, ctx)
+	if !ok {
+		return
+	}
+
+	startDate, err := time.Parse("2006-01-02", c.Query("start"))
+	if err != nil {
+		h.logger.Error(ctx, "Invalid start date format", logger.Error(err))
+		h.responseHelper.BadRequest(c, gin.H{"error": "start is required in YYYY-MM-DD format"}, middleware.MsgInvalidDate)
+		return
+	}
+	endDate, err := time.Parse("2006-01-02", c.Query("end"))
+	if err != nil {
+		h.logger.Error(ctx, "Invalid end date format", logger.Error(err))
+		h.responseHelper.BadRequest(c, gin.H{"error": "end is required in YYYY-MM-DD format"}, middleware.MsgInvalidDate)
+		return
+	}
+
+	trend, err := h.reportService.CalorieTrend(ctx, userIDStr, startDate, endDate, c.DefaultQuery("granularity", service.TrendGranularityDay))
+	if h.handleServiceError(c, ctx, err, "generate calorie trend") {
+		return
+	}
+
+	h.logger.Info(ctx, "Calorie trend generated successfully")
+	h.responseHelper.Success(c, trend, middleware.MsgCalorieTrendGenerated)
+}
+
+// Streak handles reporting the user's current and longest runs of on-target days
+func (h *ReportHandler) Streak(c *gin.Context) {
+	ctx := middleware.GetContext(c)
+
+	userIDStr, ok := h.getUserIDFromContext(c, ctx)
+	if !ok {
+		return
+	}
+
+	streak, err := h.reportService.AdherenceStreak(ctx, userIDStr)
+	if h.handleServiceError(c, ctx, err, "generate adherence streak") {
+		return
+	}
+
+	h.logger.Info(ctx, "Adherence streak generated successfully")
+	h.responseHelper.Success(c, streak, middleware.MsgAdherenceStreakGenerated)
+}
+
+// PlanAdherence handles comparing a meal plan's planned nutrition with its targets
+func (h *ReportHandler) PlanAdherence(c *gin.Context) {
+	ctx := middleware.GetContext(c)
+
+	userIDStr, ok := h.getUserIDFromContext(c, ctx)
+	if !ok {
+		return
+	}
+
+	adherence, err := h.reportService.PlanAdherence(ctx, userIDStr, c.Param("id"))
+	if h.handleServiceError(c, ctx, err, "generate plan adherence report") {
+		return
+	}
+
+	h.logger.Info(ctx, "Plan adherence report generated successfully")
+	h.responseHelper.Success(c, adherence, middleware.MsgAdherenceReportGenerated)
 }
@@ -1,27 +1,77 @@
 package rest
 
 import (
-	"net/http"
+	"context"
 
 	"github.com/gin-gonic/gin"
 
+	"nutrient_be/internal/config"
 	"nutrient_be/internal/handler/middleware"
+	"nutrient_be/internal/pkg/circuitbreaker"
+	"nutrient_be/internal/pkg/logger"
 )
 
+// RouteOptions contains the settings used when registering global middleware
+type RouteOptions struct {
+	CORSOrigins   *middleware.CORSOrigins // Replaced when the config is reloaded
+	LogBodies     bool                    // Log redacted request/response bodies of non-GET requests
+	BodyMaxLength int
+	// Header the request ID is read from and echoed in; empty uses middleware.DefaultRequestIDHeader
+	RequestIDHeader string
+	Routing         config.RoutingConfig
+	DBBreaker       *circuitbreaker.Breaker // Fails requests fast while the database is unreachable; nil disables it
+	// Treats every protected request as middleware.DefaultUserID without a token; for local development only
+	AllowDefaultUser bool
+}
+
+// readOnlyTogglePath is the admin endpoint that turns read-only mode on and off
+const readOnlyTogglePath = "/api/v1/admin/read-only"
+
+// readOnlyExemptPaths are the POST endpoints that store nothing, so they keep working in read-only mode
+// Signing in and computing nutrition or calorie targets only read data; the toggle must stay reachable to turn the mode off
+var readOnlyExemptPaths = []string{
+	readOnlyTogglePath,
+	"/api/v1/auth/login",
+	"/api/v1/auth/refresh",
+	"/api/v1/auth/validate",
+	"/api/v1/auth/logout",
+	"/api/v1/foods/validate",
+	"/api/v1/nutrition/calculate",
+	"/api/v1/nutrition/estimate-calories",
+	"/api/v1/users/calculate-targets",
+}
+
 // SetupRoutes configures all API routes
-func SetupRoutes(r *gin.Engine, handlers *Handlers) {
+func SetupRoutes(r *gin.Engine, handlers *Handlers, options RouteOptions) {
+	applyRoutingPolicy(r, options.Routing)
+
 	// Add global middleware first
 	// Order matters: ContextMiddleware must come before LoggingMiddleware
 	// so that LoggingMiddleware can use the enriched context
-	r.Use(middleware.ContextMiddleware(handlers.Auth.logger)) // Add context middleware first
-	r.Use(middleware.LoggingMiddleware(handlers.Auth.logger)) // Uses enriched context from ContextMiddleware
+	r.Use(middleware.ContextMiddleware(handlers.Auth.logger, options.RequestIDHeader)) // Add context middleware first
+	r.Use(middleware.LoggingMiddleware(handlers.Auth.logger, options.RequestIDHeader)) // Uses enriched context from ContextMiddleware
 	r.Use(middleware.RecoveryMiddleware(handlers.Auth.logger))
-	r.Use(middleware.CORSMiddleware())
+	r.Use(middleware.CORSMiddleware(options.CORSOrigins))
+	if options.LogBodies {
+		r.Use(middleware.BodyLoggingMiddleware(handlers.Auth.logger, options.BodyMaxLength))
+	}
 	r.Use(middleware.ResponseMiddleware(handlers.Auth.logger)) // Add response middleware
+	// Blocks writes during maintenance; endpoints that store nothing stay reachable
+	r.Use(middleware.ReadOnlyMiddleware(handlers.Admin.readOnly, readOnlyExemptPaths...))
+	// Probes stay reachable so readiness can report the open breaker
+	r.Use(middleware.CircuitBreakerMiddleware(options.DBBreaker, "/health/liveness", "/health/readiness", "/ping", readOnlyTogglePath))
+
+	authMiddleware := middleware.AuthMiddleware(handlers.Auth.logger, handlers.Auth.tokenKeys)
+	if options.AllowDefaultUser {
+		handlers.Auth.logger.Warn(context.Background(), "DEFAULT USER AUTH IS ENABLED: every protected request runs as the default user without a token; never use this outside local development",
+			logger.String("user_id", middleware.DefaultUserID))
+		authMiddleware = middleware.DefaultUserAuthMiddleware(handlers.Auth.logger)
+	}
 
 	// Health checks (no auth required)
 	r.HEAD("/health/liveness", handlers.Health.Liveness)
 	r.GET("/health/readiness", handlers.Health.Readiness)
+	r.GET("/ping", handlers.Health.Ping)
 
 	// API v1 routes
 	v1 := r.Group("/api/v1")
@@ -35,17 +85,38 @@ func SetupRoutes(r *gin.Engine, handlers *Handlers) {
 			auth.POST("/validate", handlers.Auth.Validate)
 		}
 
+		// Metadata routes (no auth required)
+		metadata := v1.Group("/metadata")
+		{
+			metadata.GET("/units", handlers.Metadata.Units)
+			metadata.GET("/categories", handlers.Metadata.Categories)
+		}
+
+		// Admin routes (admin token required)
+		admin := v1.Group("/admin")
+		admin.Use(middleware.AdminTokenMiddleware(handlers.Admin.adminToken))
+		{
+			admin.GET("/read-only", handlers.Admin.GetReadOnly)
+			admin.PUT("/read-only", handlers.Admin.SetReadOnly)
+			// Data changes also need a user token so the admin making them is known
+			admin.POST("/foods/merge", authMiddleware, handlers.Food.Merge)
+			admin.POST("/foods/recalculate-calories", authMiddleware, handlers.Food.RecalculateCalories)
+		}
+
 		// Protected routes (auth required)
 		protected := v1.Group("")
-		protected.Use(middleware.AuthMiddleware(handlers.Auth.logger, handlers.Auth.config))
+		protected.Use(authMiddleware)
 		{
 			// User management
 			users := protected.Group("/users")
 			{
 				users.GET("/profile", handlers.User.GetProfile)
 				users.PUT("/profile", handlers.User.UpdateProfile)
+				users.POST("/calculate-targets", handlers.User.CalculateTargets)
 				users.PUT("/preferences", handlers.User.UpdatePreferences)
 				users.PUT("/password", handlers.User.ChangePassword)
+				users.DELETE("/me", handlers.User.DeleteAccount)
+				users.GET("/me/export", handlers.User.ExportData)
 			}
 
 			// Auth (protected)
@@ -58,11 +129,18 @@ func SetupRoutes(r *gin.Engine, handlers *Handlers) {
 			foods := protected.Group("/foods")
 			{
 				foods.POST("", handlers.Food.Create)
+				foods.GET("", handlers.Food.List)
 				foods.GET("/search", handlers.Food.Search)
+				foods.POST("/validate", handlers.Food.Validate)
 				foods.GET("/:id", handlers.Food.Get)
+				foods.GET("/:id/breakdown", handlers.Food.Breakdown)
 				foods.PUT("/:id", handlers.Food.Update)
+				foods.POST("/:id/clone", handlers.Food.Clone)
 				foods.DELETE("/:id", handlers.Food.Delete)
+				foods.GET("/:id/image", handlers.Food.GetImage)
+				foods.POST("/:id/image", handlers.Food.UploadImage)
 				foods.POST("/import", handlers.Food.ImportExcel)
+				foods.PATCH("/visibility", handlers.Food.BulkSetVisibility)
 			}
 
 			// Meal templates
@@ -70,10 +148,13 @@ func SetupRoutes(r *gin.Engine, handlers *Handlers) {
 			{
 				templates.POST("", handlers.Meal.CreateTemplate)
 				templates.GET("", handlers.Meal.ListTemplates)
+				templates.GET("/fitting", handlers.Meal.ListFittingTemplates)
+				templates.GET("/tags", handlers.Meal.ListTags)
 				templates.GET("/:id", handlers.Meal.GetTemplate)
 				templates.POST("/:id/foods", handlers.Meal.AddFoodToTemplate)
 				templates.PUT("/:id", handlers.Meal.UpdateTemplate)
 				templates.DELETE("/:id", handlers.Meal.DeleteTemplate)
+				templates.DELETE("", handlers.Meal.BulkDeleteTemplates)
 			}
 
 			// Meal plans
@@ -81,17 +162,29 @@ func SetupRoutes(r *gin.Engine, handlers *Handlers) {
 			{
 				plans.POST("", handlers.MealPlan.Create)
 				plans.GET("", handlers.MealPlan.List)
+				plans.GET("/for-date", handlers.MealPlan.ForDate)
 				plans.GET("/:id", handlers.MealPlan.Get)
+				plans.GET("/:id/printable", handlers.MealPlan.Printable)
+				plans.GET("/:id/adherence", handlers.Report.PlanAdherence)
 				plans.PUT("/:id", handlers.MealPlan.Update)
+				plans.PUT("/:id/targets", handlers.MealPlan.UpdateTargets)
 				plans.DELETE("/:id", handlers.MealPlan.Delete)
+				plans.PUT("/:id/meals/:mealId/notes", handlers.MealPlan.UpdateMealNotes)
+				plans.GET("/:id/meals/:mealId/swaps", handlers.MealPlan.SuggestSwaps)
+				plans.PUT("/:id/days/:date/notes", handlers.MealPlan.UpdateDayNotes)
+				plans.POST("/:id/days/:date/regenerate", handlers.MealPlan.RegenerateDay)
 			}
 
 			// Shopping lists
 			shopping := protected.Group("/shopping-lists")
 			{
 				shopping.POST("/generate/:mealPlanId", handlers.Shopping.Generate)
+				shopping.POST("/merge", handlers.Shopping.Merge)
 				shopping.GET("", handlers.Shopping.List)
 				shopping.PUT("/:id/items/:itemId/check", handlers.Shopping.ToggleItem)
+				shopping.PUT("/:id/items/check-batch", handlers.Shopping.ToggleItems)
+				shopping.POST("/:id/items", handlers.Shopping.AddItem)
+				shopping.DELETE("/:id/items/:itemId", handlers.Shopping.RemoveItem)
 			}
 
 			// Reports
@@ -99,15 +192,38 @@ func SetupRoutes(r *gin.Engine, handlers *Handlers) {
 			{
 				reports.GET("/weekly", handlers.Report.Weekly)
 				reports.GET("/monthly", handlers.Report.Monthly)
+				reports.GET("/progress", handlers.Report.Progress)
+				reports.GET("/range", handlers.Report.Range)
+				reports.GET("/calorie-trend", handlers.Report.CalorieTrend)
+				reports.GET("/streak", handlers.Report.Streak)
 			}
+
+			// Nutrition
+			protected.POST("/nutrition/calculate", handlers.Meal.CalculateNutrition)
+			protected.POST("/nutrition/estimate-calories", handlers.Food.EstimateCalories)
+
+			// Suggestions
+			protected.GET("/suggestions", handlers.Suggestion.Suggest)
+
+			// Dashboard
+			protected.GET("/dashboard", handlers.Dashboard.Get)
 		}
 	}
 
 	// 404 handler
-	r.NoRoute(func(c *gin.Context) {
-		c.JSON(http.StatusNotFound, gin.H{
-			"error": "Route not found",
-			"path":  c.Request.URL.Path,
-		})
-	})
+	r.NoRoute(notFound)
+}
+
+// applyRoutingPolicy sets whether paths differing from a route by a trailing slash or letter case are redirected to it
+func applyRoutingPolicy(r *gin.Engine, routing config.RoutingConfig) {
+	r.RedirectTrailingSlash = routing.RedirectTrailingSlash
+	r.RedirectFixedPath = routing.CaseInsensitive
+}
+
+// notFound answers requests matching no route in the standard response format
+func notFound(c *gin.Context) {
+	middleware.NewResponseHelper().NotFound(c, gin.H{
+		"error": "Route not found",
+		"path":  c.Request.URL.Path,
+	}, middleware.MsgRouteNotFound)
 }
@@ -0,0 +1,125 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"nutrient_be/internal/config"
+	"nutrient_be/internal/handler/middleware"
+	"nutrient_be/internal/pkg/logger"
+)
+
+func newRoutingRouter(routing config.RoutingConfig) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	applyRoutingPolicy(router, routing)
+	router.Use(middleware.ResponseMiddleware(logger.NewNoopLogger()))
+	router.GET("/api/v1/foods", func(c *gin.Context) {
+		middleware.NewResponseHelper().Success(c, "foods")
+	})
+	router.NoRoute(notFound)
+	return router
+}
+
+// resolve requests path, following redirects the way a client would, and returns the final response
+func resolve(router *gin.Engine, path string) *httptest.ResponseRecorder {
+	for i := 0; i < 3; i++ {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		location := rec.Header().Get("Location")
+		if rec.Code != http.StatusMovedPermanently || location == "" {
+			return rec
+		}
+		path = location
+	}
+	return nil
+}
+
+func TestRoutingPolicy_Enabled(t *testing.T) {
+	router := newRoutingRouter(config.RoutingConfig{RedirectTrailingSlash: true, CaseInsensitive: true})
+
+	for _, path := range []string{"/api/v1/foods", "/api/v1/foods/", "/api/v1/Foods", "/API/v1/Foods/"} {
+		t.Run(path, func(t *testing.T) {
+			rec := resolve(router, path)
+			if rec == nil || rec.Code != http.StatusOK {
+				t.Fatalf("Expected %s to resolve to the foods handler, got: %v", path, rec)
+			}
+			var body struct {
+				Data string `json:"data"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil || body.Data != "foods" {
+				t.Errorf("Expected the foods handler's response, got: %s", rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestRoutingPolicy_Disabled(t *testing.T) {
+	router := newRoutingRouter(config.RoutingConfig{})
+
+	for _, path := range []string{"/api/v1/foods/", "/api/v1/Foods"} {
+		t.Run(path, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+			if rec.Code != http.StatusNotFound {
+				t.Fatalf("Expected status 404, got: %d", rec.Code)
+			}
+
+			var body middleware.ResponseFormat
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("Expected JSON body, got: %v", err)
+			}
+			if body.Code != http.StatusNotFound || body.Message != "Route not found" || body.Error == nil {
+				t.Errorf("Expected the standard 404 response, got: %s", rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestReadOnlyExemptPaths(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.ReadOnlyMiddleware(middleware.NewReadOnlyMode(true), readOnlyExemptPaths...))
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	router.PUT(readOnlyTogglePath, ok)
+	router.POST("/api/v1/foods", ok)
+	router.POST("/api/v1/auth/register", ok)
+
+	compute := []string{
+		"/api/v1/auth/login",
+		"/api/v1/auth/refresh",
+		"/api/v1/auth/validate",
+		"/api/v1/auth/logout",
+		"/api/v1/foods/validate",
+		"/api/v1/nutrition/calculate",
+		"/api/v1/nutrition/estimate-calories",
+		"/api/v1/users/calculate-targets",
+	}
+	for _, path := range compute {
+		router.POST(path, ok)
+		t.Run(path, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, path, nil))
+			if rec.Code != http.StatusOK {
+				t.Errorf("Expected POST %s to pass in read-only mode, got: %d", path, rec.Code)
+			}
+		})
+	}
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, readOnlyTogglePath, nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("Expected the read-only toggle to stay reachable, got: %d", rec.Code)
+	}
+	for _, path := range []string{"/api/v1/foods", "/api/v1/auth/register"} {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, path, nil))
+		if rec.Code != http.StatusServiceUnavailable {
+			t.Errorf("Expected POST %s to be blocked in read-only mode, got: %d", path, rec.Code)
+		}
+	}
+}
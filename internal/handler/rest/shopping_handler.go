@@ -1,10 +1,18 @@
 package rest
 
 import (
-	"net/http"
+	"context"
+	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
 
+	"nutrient_be/internal/config"
+	"nutrient_be/internal/domain"
+	"nutrient_be/internal/dto/request"
+	"nutrient_be/internal/dto/response"
+	"nutrient_be/internal/handler/middleware"
 	"nutrient_be/internal/pkg/logger"
 	"nutrient_be/internal/service"
 )
@@ -12,28 +20,349 @@ import (
 // ShoppingHandler handles shopping list endpoints
 type ShoppingHandler struct {
 	shoppingService *service.ShoppingService
+	structValidator *validator.Validate
+	pageSize        config.PageSizeConfig
 	logger          logger.Logger
+	responseHelper  *middleware.ResponseHelper
 }
 
 // NewShoppingHandler creates a new shopping handler
-func NewShoppingHandler(shoppingService *service.ShoppingService, log logger.Logger) *ShoppingHandler {
+func NewShoppingHandler(shoppingService *service.ShoppingService, pageSize config.PageSizeConfig, log logger.Logger) *ShoppingHandler {
 	return &ShoppingHandler{
 		shoppingService: shoppingService,
+		structValidator: validator.New(),
+		pageSize:        pageSize,
 		logger:          log,
+		responseHelper:  middleware.NewResponseHelper(),
 	}
 }
 
+// getUserIDFromContext extracts user ID from context or returns error response
+// Returns userID and true if successful, false if error response was sent
+func (h *ShoppingHandler) getUserIDFromContext(c *gin.Context, ctx context.Context) (string, bool) {
+	userIDStr, exists := middleware.GetUserIDFromContext(c)
+	if !exists {
+		h.logger.Error(ctx, "User ID not found in context")
+		h.responseHelper.Unauthorized(c, gin.H{"error": "User not authenticated"}, middleware.MsgAuthenticationRequired)
+		return "", false
+	}
+	return userIDStr, true
+}
+
+// getListIDFromParams extracts shopping list ID from URL params or returns error response
+// Returns listID and true if successful, false if error response was sent
+func (h *ShoppingHandler) getListIDFromParams(c *gin.Context, ctx context.Context) (string, bool) {
+	listID := c.Param("id")
+	if listID == "" {
+		h.logger.Error(ctx, "Shopping list ID is required")
+		h.responseHelper.BadRequest(c, gin.H{"error": "Shopping list ID is required"}, middleware.MsgShoppingListIDRequired)
+		return "", false
+	}
+	return listID, true
+}
+
+// bindAndValidate binds a JSON request and validates it using struct tags
+// Returns true if successful, false if error response was sent
+func (h *ShoppingHandler) bindAndValidate(c *gin.Context, ctx context.Context, req interface{}, requestType string) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		h.logger.Error(ctx, "Failed to bind request", logger.String("type", requestType), logger.Error(err))
+		h.responseHelper.BadRequest(c, gin.H{"details": err.Error()}, middleware.MsgInvalidRequestBody)
+		return false
+	}
+	if err := h.structValidator.Struct(req); err != nil {
+		h.logger.Error(ctx, "Request validation failed", logger.String("type", requestType), logger.Error(err))
+		h.responseHelper.ValidationError(c, gin.H{"validation_errors": err.Error()}, middleware.MsgValidationFailed)
+		return false
+	}
+	return true
+}
+
+// handleServiceError handles service errors and sends appropriate response
+// Returns true if error was handled, false if no error
+func (h *ShoppingHandler) handleServiceError(c *gin.Context, ctx context.Context, err error, operation string) bool {
+	if err == nil {
+		return false
+	}
+
+	h.logger.Error(ctx, "Service operation failed", logger.String("operation", operation), logger.Error(err))
+
+	if handleDatabaseUnavailable(c, h.responseHelper, err) {
+		return true
+	}
+
+	// Check for specific error types
+	errMsg := err.Error()
+	switch errMsg {
+	case "shopping list not found or access denied":
+		h.responseHelper.ErrorCode(c, middleware.ErrorCodeShoppingListNotFound)
+		h.responseHelper.NotFound(c, gin.H{"error": "Shopping list not found"}, middleware.MsgShoppingListNotFound)
+		return true
+	case "item not found in shopping list":
+		h.responseHelper.ErrorCode(c, middleware.ErrorCodeShoppingItemNotFound)
+		h.responseHelper.NotFound(c, gin.H{"error": "Item not found"}, middleware.MsgShoppingItemNotFound)
+		return true
+	case "meal plan not found or access denied":
+		h.responseHelper.ErrorCode(c, middleware.ErrorCodeMealPlanNotFound)
+		h.responseHelper.NotFound(c, gin.H{"error": "Meal plan not found"}, middleware.MsgMealPlanNotFound)
+		return true
+	}
+	if strings.HasPrefix(errMsg, "validation failed:") || strings.HasPrefix(errMsg, "invalid ") {
+		if strings.HasPrefix(errMsg, "validation failed:") {
+			h.responseHelper.ErrorCode(c, middleware.ErrorCodeValidationFailed)
+		}
+		h.responseHelper.BadRequest(c, gin.H{"details": errMsg}, middleware.MsgInvalidRequest)
+		return true
+	}
+
+	// Default to internal error
+	h.responseHelper.InternalError(c, gin.H{"details": errMsg}, middleware.MsgOperationFailed)
+	return true
+}
+
 // Generate handles shopping list generation
 func (h *ShoppingHandler) Generate(c *gin.Context) {
-	c.JSON(http.StatusNotImplemented, gin.H{"message": "Shopping list generation not implemented yet"})
+	ctx := middleware.GetContext(c)
+
+	userIDStr, ok := h.getUserIDFromContext(c, ctx)
+	if !ok {
+		return
+	}
+
+	mealPlanID := c.Param("mealPlanId")
+	if mealPlanID == "" {
+		h.logger.Error(ctx, "Meal plan ID is required")
+		h.responseHelper.BadRequest(c, gin.H{"error": "Meal plan ID is required"}, middleware.MsgMealPlanIDRequired)
+		return
+	}
+
+	list, err := h.shoppingService.GenerateFromMealPlan(ctx, userIDStr, mealPlanID)
+	if h.handleServiceError(c, ctx, err, "generate shopping list") {
+		return
+	}
+
+	h.logger.Info(ctx, "Shopping list generated successfully")
+	h.responseHelper.Created(c, shoppingListToResponse(list), middleware.MsgShoppingListGenerated)
+}
+
+// Merge handles combining several shopping lists into a new one
+func (h *ShoppingHandler) Merge(c *gin.Context) {
+	ctx := middleware.GetContext(c)
+
+	userIDStr, ok := h.getUserIDFromContext(c, ctx)
+	if !ok {
+		return
+	}
+
+	var req request.MergeShoppingListsRequest
+	if !h.bindAndValidate(c, ctx, &req, "MergeShoppingListsRequest") {
+		return
+	}
+
+	list, err := h.shoppingService.MergeLists(ctx, userIDStr, req.ListIDs, req.Name)
+	if h.handleServiceError(c, ctx, err, "merge shopping lists") {
+		return
+	}
+
+	h.logger.Info(ctx, "Shopping lists merged successfully")
+	h.responseHelper.Created(c, shoppingListToResponse(list), middleware.MsgShoppingListsMerged)
 }
 
 // List handles listing shopping lists
 func (h *ShoppingHandler) List(c *gin.Context) {
-	c.JSON(http.StatusNotImplemented, gin.H{"message": "Shopping list listing not implemented yet"})
+	ctx := middleware.GetContext(c)
+
+	userIDStr, ok := h.getUserIDFromContext(c, ctx)
+	if !ok {
+		return
+	}
+
+	// Get and validate query parameters
+	status := c.Query("status")
+	limit, offset, err := parsePagination(c, h.pageSize)
+	if err != nil {
+		h.logger.Error(ctx, "Invalid pagination", logger.Error(err))
+		h.responseHelper.BadRequest(c, gin.H{"error": err.Error()}, middleware.MsgInvalidPagination)
+		return
+	}
+
+	lists, err := h.shoppingService.ListLists(ctx, userIDStr, status, limit, offset)
+	if h.handleServiceError(c, ctx, err, "list shopping lists") {
+		return
+	}
+
+	listResponses := make([]response.ShoppingListResponse, len(lists))
+	for i, list := range lists {
+		listResponses[i] = shoppingListToResponse(list)
+	}
+
+	h.logger.Info(ctx, "Shopping lists listed successfully")
+	h.responseHelper.Success(c, listResponses, middleware.MsgShoppingListsListed)
 }
 
 // ToggleItem handles toggling shopping list item
+// The desired state is read from the "checked" query param, or from the JSON body when absent
 func (h *ShoppingHandler) ToggleItem(c *gin.Context) {
-	c.JSON(http.StatusNotImplemented, gin.H{"message": "Shopping list item toggle not implemented yet"})
+	ctx := middleware.GetContext(c)
+
+	userIDStr, ok := h.getUserIDFromContext(c, ctx)
+	if !ok {
+		return
+	}
+
+	listID, ok := h.getListIDFromParams(c, ctx)
+	if !ok {
+		return
+	}
+
+	var checked bool
+	if checkedStr, exists := c.GetQuery("checked"); exists {
+		parsed, err := strconv.ParseBool(checkedStr)
+		if err != nil {
+			h.logger.Error(ctx, "Invalid checked query parameter", logger.Error(err))
+			h.responseHelper.BadRequest(c, gin.H{"error": "checked must be true or false"}, middleware.MsgInvalidCheckedValue)
+			return
+		}
+		checked = parsed
+	} else {
+		var req request.ToggleShoppingItemRequest
+		if !h.bindAndValidate(c, ctx, &req, "ToggleShoppingItemRequest") {
+			return
+		}
+		checked = *req.Checked
+	}
+
+	list, err := h.shoppingService.ToggleItem(ctx, userIDStr, listID, c.Param("itemId"), checked)
+	if h.handleServiceError(c, ctx, err, "toggle shopping item") {
+		return
+	}
+
+	h.logger.Info(ctx, "Shopping item toggled successfully")
+	h.responseHelper.Success(c, shoppingListToResponse(list), middleware.MsgShoppingItemToggled)
+}
+
+// ToggleItems handles setting the checked state of several shopping list items at once
+func (h *ShoppingHandler) ToggleItems(c *gin.Context) {
+	ctx := middleware.GetContext(c)
+
+	userIDStr, ok := h.getUserIDFromContext(c, ctx)
+	if !ok {
+		return
+	}
+
+	listID, ok := h.getListIDFromParams(c, ctx)
+	if !ok {
+		return
+	}
+
+	var req request.ToggleShoppingItemsRequest
+	if !h.bindAndValidate(c, ctx, &req, "ToggleShoppingItemsRequest") {
+		return
+	}
+
+	list, err := h.shoppingService.ToggleItems(ctx, userIDStr, listID, req.Items)
+	if h.handleServiceError(c, ctx, err, "toggle shopping items") {
+		return
+	}
+
+	h.logger.Info(ctx, "Shopping items toggled successfully")
+	h.responseHelper.Success(c, shoppingListToResponse(list), middleware.MsgShoppingItemsToggled)
+}
+
+// AddItem handles adding a manual item to a shopping list
+func (h *ShoppingHandler) AddItem(c *gin.Context) {
+	ctx := middleware.GetContext(c)
+
+	userIDStr, ok := h.getUserIDFromContext(c, ctx)
+	if !ok {
+		return
+	}
+
+	listID, ok := h.getListIDFromParams(c, ctx)
+	if !ok {
+		return
+	}
+
+	var req request.AddShoppingItemRequest
+	if !h.bindAndValidate(c, ctx, &req, "AddShoppingItemRequest") {
+		return
+	}
+
+	list, err := h.shoppingService.AddItem(ctx, userIDStr, listID, &req)
+	if h.handleServiceError(c, ctx, err, "add shopping item") {
+		return
+	}
+
+	h.logger.Info(ctx, "Shopping item added successfully")
+	h.responseHelper.Created(c, shoppingListToResponse(list), middleware.MsgShoppingItemAdded)
+}
+
+// RemoveItem handles removing an item from a shopping list
+func (h *ShoppingHandler) RemoveItem(c *gin.Context) {
+	ctx := middleware.GetContext(c)
+
+	userIDStr, ok := h.getUserIDFromContext(c, ctx)
+	if !ok {
+		return
+	}
+
+	listID, ok := h.getListIDFromParams(c, ctx)
+	if !ok {
+		return
+	}
+
+	list, err := h.shoppingService.RemoveItem(ctx, userIDStr, listID, c.Param("itemId"))
+	if h.handleServiceError(c, ctx, err, "remove shopping item") {
+		return
+	}
+
+	h.logger.Info(ctx, "Shopping item removed successfully")
+	h.responseHelper.Success(c, shoppingListToResponse(list), middleware.MsgShoppingItemRemoved)
+}
+
+// shoppingListToResponse converts a domain ShoppingList to a response ShoppingListResponse
+func shoppingListToResponse(list *domain.ShoppingList) response.ShoppingListResponse {
+	items := make([]response.ShoppingItemResponse, len(list.Items))
+	checkedCount := 0
+	for i, item := range list.Items {
+		if item.Checked {
+			checkedCount++
+		}
+		foodItemID := ""
+		if item.FoodItemID != nil {
+			foodItemID = item.FoodItemID.Hex()
+		}
+
+		items[i] = response.ShoppingItemResponse{
+			ID:          item.ID.Hex(),
+			FoodItemID:  foodItemID,
+			FoodName:    item.FoodName,
+			TotalAmount: item.TotalAmount,
+			Unit:        item.Unit,
+			TotalGrams:  item.TotalGrams,
+			Display:     item.Display,
+			Cost:        item.Cost,
+			Checked:     item.Checked,
+			IsManual:    item.IsManual,
+		}
+	}
+
+	resp := response.ShoppingListResponse{
+		ID:           list.ID.Hex(),
+		UserID:       list.UserID.Hex(),
+		Name:         list.Name,
+		Items:        items,
+		ItemCount:    len(items),
+		CheckedCount: checkedCount,
+		TotalCost:    list.TotalCost,
+		Status:       list.Status,
+		CreatedAt:    list.CreatedAt,
+		UpdatedAt:    list.UpdatedAt,
+	}
+	if !list.MealPlanID.IsZero() {
+		resp.MealPlanID = list.MealPlanID.Hex()
+	}
+	for _, id := range list.SourceListIDs {
+		resp.SourceListIDs = append(resp.SourceListIDs, id.Hex())
+	}
+	return resp
 }
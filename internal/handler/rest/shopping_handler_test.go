@@ -0,0 +1,84 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"nutrient_be/internal/config"
+	"nutrient_be/internal/domain"
+	"nutrient_be/internal/dto/response"
+	"nutrient_be/internal/handler/middleware"
+	"nutrient_be/internal/pkg/logger"
+	"nutrient_be/internal/service"
+)
+
+// statusShoppingRepo serves its lists filtered by status
+type statusShoppingRepo struct {
+	service.ShoppingListRepository
+	lists []*domain.ShoppingList
+}
+
+func (r *statusShoppingRepo) GetByUser(ctx context.Context, userID primitive.ObjectID, status string, limit, offset int) ([]*domain.ShoppingList, error) {
+	var lists []*domain.ShoppingList
+	for _, list := range r.lists {
+		if list.UserID == userID && (status == "" || list.Status == status) {
+			lists = append(lists, list)
+		}
+	}
+	return lists, nil
+}
+
+func TestShoppingList_FiltersByStatusAndCountsItems(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	log := logger.NewNoopLogger()
+	userID := primitive.NewObjectID()
+	repo := &statusShoppingRepo{lists: []*domain.ShoppingList{
+		{
+			ID:     primitive.NewObjectID(),
+			UserID: userID,
+			Status: domain.ShoppingListStatusActive,
+			Items: []domain.ShoppingItem{
+				{ID: primitive.NewObjectID(), FoodName: "Oats", Checked: true},
+				{ID: primitive.NewObjectID(), FoodName: "Milk"},
+			},
+		},
+		{ID: primitive.NewObjectID(), UserID: userID, Status: domain.ShoppingListStatusCompleted},
+	}}
+	handler := NewShoppingHandler(service.NewShoppingService(repo, nil, nil, log), config.PageSizeConfig{Default: 20, Max: 100}, log)
+
+	router := gin.New()
+	router.Use(middleware.ResponseMiddleware(log))
+	router.Use(func(c *gin.Context) { c.Set("userID", userID.Hex()) })
+	router.GET("/shopping-lists", handler.List)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/shopping-lists?status=active", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got: %d", rec.Code)
+	}
+
+	var body struct {
+		Data []response.ShoppingListResponse `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Expected a JSON body, got: %v", err)
+	}
+	if len(body.Data) != 1 || body.Data[0].Status != domain.ShoppingListStatusActive {
+		t.Fatalf("Expected only the active list, got: %+v", body.Data)
+	}
+	if body.Data[0].ItemCount != 2 || body.Data[0].CheckedCount != 1 {
+		t.Errorf("Expected 2 items with 1 checked, got: %d items with %d checked", body.Data[0].ItemCount, body.Data[0].CheckedCount)
+	}
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/shopping-lists?status=pending", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for an unknown status, got: %d", rec.Code)
+	}
+}
@@ -0,0 +1,94 @@
+package rest
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"nutrient_be/internal/handler/middleware"
+	"nutrient_be/internal/pkg/logger"
+	"nutrient_be/internal/service"
+)
+
+// SuggestionHandler handles meal suggestion endpoints
+type SuggestionHandler struct {
+	suggestionService *service.MealSuggestionService
+	logger            logger.Logger
+	responseHelper    *middleware.ResponseHelper
+}
+
+// NewSuggestionHandler creates a new suggestion handler
+func NewSuggestionHandler(suggestionService *service.MealSuggestionService, log logger.Logger) *SuggestionHandler {
+	return &SuggestionHandler{
+		suggestionService: suggestionService,
+		logger:            log,
+		responseHelper:    middleware.NewResponseHelper(),
+	}
+}
+
+// getUserIDFromContext extracts user ID from context or returns error response
+// Returns userID and true if successful, false if error response was sent
+func (h *SuggestionHandler) getUserIDFromContext(c *gin.Context, ctx context.Context) (string, bool) {
+	userIDStr, exists := middleware.GetUserIDFromContext(c)
+	if !exists {
+		h.logger.Error(ctx, "User ID not found in context")
+		h.responseHelper.Unauthorized(c, gin.H{"error": "User not authenticated"}, middleware.MsgAuthenticationRequired)
+		return "", false
+	}
+	return userIDStr, true
+}
+
+// handleServiceError handles service errors and sends appropriate response
+// Returns true if error was handled, false if no error
+func (h *SuggestionHandler) handleServiceError(c *gin.Context, ctx context.Context, err error, operation string) bool {
+	if err == nil {
+		return false
+	}
+
+	h.logger.Error(ctx, "Service operation failed", logger.String("operation", operation), logger.Error(err))
+
+	if handleDatabaseUnavailable(c, h.responseHelper, err) {
+		return true
+	}
+
+	if strings.HasPrefix(err.Error(), "user not found") {
+		h.responseHelper.ErrorCode(c, middleware.ErrorCodeUserNotFound)
+		h.responseHelper.NotFound(c, gin.H{"error": err.Error()}, middleware.MsgUserNotFound)
+		return true
+	}
+
+	h.responseHelper.InternalError(c, gin.H{"details": err.Error()}, middleware.MsgOperationFailed)
+	return true
+}
+
+// Suggest handles meal suggestions for the budget left on a day
+// The "date" query param (YYYY-MM-DD, default today) selects the day
+func (h *SuggestionHandler) Suggest(c *gin.Context) {
+	ctx := middleware.GetContext(c)
+
+	userIDStr, ok := h.getUserIDFromContext(c, ctx)
+	if !ok {
+		return
+	}
+
+	date := time.Now().UTC()
+	if dateStr := c.Query("date"); dateStr != "" {
+		parsed, err := time.Parse("2006-01-02", dateStr)
+		if err != nil {
+			h.logger.Error(ctx, "Invalid date format", logger.Error(err))
+			h.responseHelper.BadRequest(c, gin.H{"error": "Date must be in YYYY-MM-DD format"}, middleware.MsgInvalidDate)
+			return
+		}
+		date = parsed
+	}
+
+	suggestions, err := h.suggestionService.SuggestForDate(ctx, userIDStr, date)
+	if h.handleServiceError(c, ctx, err, "suggest meals") {
+		return
+	}
+
+	h.logger.Info(ctx, "Meal suggestions generated successfully")
+	h.responseHelper.Success(c, suggestions, middleware.MsgMealSuggestionsGenerated)
+}
@@ -1,28 +1,44 @@
 package rest
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
 
 	"nutrient_be/internal/dto/request"
 	"nutrient_be/internal/handler/middleware"
 	"nutrient_be/internal/pkg/logger"
+	userValidator "nutrient_be/internal/pkg/validator"
 	"nutrient_be/internal/service"
 )
 
 // UserHandler handles user profile and preferences endpoints
 type UserHandler struct {
-	userService    *service.UserService
+	userService     *service.UserService
 	structValidator *validator.Validate
 	logger          logger.Logger
 	responseHelper  *middleware.ResponseHelper
 }
 
 // NewUserHandler creates a new user handler
-func NewUserHandler(userService *service.UserService, log logger.Logger) *UserHandler {
+// New passwords shorter than minPasswordLength are rejected, values below 1 use the validator default
+func NewUserHandler(userService *service.UserService, minPasswordLength int, log logger.Logger) *UserHandler {
+	structValidator := validator.New()
+	if err := userValidator.RegisterFiniteTag(structValidator); err != nil {
+		panic(fmt.Sprintf("failed to register finite validation: %v", err))
+	}
+	if err := userValidator.RegisterPasswordTag(structValidator, minPasswordLength); err != nil {
+		panic(fmt.Sprintf("failed to register password validation: %v", err))
+	}
+
 	return &UserHandler{
 		userService:     userService,
-		structValidator: validator.New(),
+		structValidator: structValidator,
 		logger:          log,
 		responseHelper:  middleware.NewResponseHelper(),
 	}
@@ -36,7 +52,7 @@ func (h *UserHandler) GetProfile(c *gin.Context) {
 	userIDStr, exists := middleware.GetUserIDFromContext(c)
 	if !exists {
 		h.logger.Error(ctx, "User ID not found in context")
-		h.responseHelper.Unauthorized(c, gin.H{"error": "User not authenticated"}, "Authentication required")
+		h.responseHelper.Unauthorized(c, gin.H{"error": "User not authenticated"}, middleware.MsgAuthenticationRequired)
 		return
 	}
 
@@ -44,12 +60,12 @@ func (h *UserHandler) GetProfile(c *gin.Context) {
 	profile, err := h.userService.GetProfile(c.Request.Context(), userIDStr)
 	if err != nil {
 		h.logger.Error(ctx, "Failed to get user profile", logger.Error(err))
-		h.responseHelper.InternalError(c, gin.H{"details": err.Error()}, "Failed to get user profile")
+		h.responseHelper.InternalError(c, gin.H{"details": err.Error()}, middleware.MsgProfileGetFailed)
 		return
 	}
 
 	h.logger.Info(ctx, "User profile retrieved successfully")
-	h.responseHelper.Success(c, profile, "User profile retrieved successfully")
+	h.responseHelper.Success(c, profile, middleware.MsgProfileRetrieved)
 }
 
 // UpdateProfile handles updating user profile
@@ -60,7 +76,7 @@ func (h *UserHandler) UpdateProfile(c *gin.Context) {
 	userIDStr, exists := middleware.GetUserIDFromContext(c)
 	if !exists {
 		h.logger.Error(ctx, "User ID not found in context")
-		h.responseHelper.Unauthorized(c, gin.H{"error": "User not authenticated"}, "Authentication required")
+		h.responseHelper.Unauthorized(c, gin.H{"error": "User not authenticated"}, middleware.MsgAuthenticationRequired)
 		return
 	}
 
@@ -68,27 +84,71 @@ func (h *UserHandler) UpdateProfile(c *gin.Context) {
 	var req request.UpdateProfileRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		h.logger.Error(ctx, "Failed to bind update profile request", logger.Error(err))
-		h.responseHelper.BadRequest(c, gin.H{"details": err.Error()}, "Invalid request body")
+		h.responseHelper.BadRequest(c, gin.H{"details": err.Error()}, middleware.MsgInvalidRequestBody)
 		return
 	}
 
 	// Validate request
+	req.Normalize()
 	if err := h.structValidator.Struct(&req); err != nil {
 		h.logger.Error(ctx, "Update profile validation failed", logger.Error(err))
-		h.responseHelper.ValidationError(c, gin.H{"validation_errors": err.Error()}, "Validation failed")
+		h.responseHelper.ValidationError(c, gin.H{"validation_errors": err.Error()}, middleware.MsgValidationFailed)
 		return
 	}
 
 	// Update profile
 	updatedProfile, err := h.userService.UpdateProfile(c.Request.Context(), userIDStr, &req)
+	if h.handleProfileValidationError(c, ctx, err) {
+		return
+	}
 	if err != nil {
 		h.logger.Error(ctx, "Failed to update user profile", logger.Error(err))
-		h.responseHelper.InternalError(c, gin.H{"details": err.Error()}, "Failed to update user profile")
+		h.responseHelper.InternalError(c, gin.H{"details": err.Error()}, middleware.MsgProfileUpdateFailed)
 		return
 	}
 
 	h.logger.Info(ctx, "User profile updated successfully")
-	h.responseHelper.Success(c, updatedProfile, "User profile updated successfully")
+	h.responseHelper.Success(c, updatedProfile, middleware.MsgProfileUpdated)
+}
+
+// CalculateTargets handles previewing calorie and macro targets for a profile without saving it
+func (h *UserHandler) CalculateTargets(c *gin.Context) {
+	ctx := middleware.GetContext(c)
+
+	// Bind request
+	var req request.CalculateTargetsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error(ctx, "Failed to bind calculate targets request", logger.Error(err))
+		h.responseHelper.BadRequest(c, gin.H{"details": err.Error()}, middleware.MsgInvalidRequestBody)
+		return
+	}
+
+	// Validate request
+	req.Normalize()
+	if err := h.structValidator.Struct(&req); err != nil {
+		h.logger.Error(ctx, "Calculate targets validation failed", logger.Error(err))
+		h.responseHelper.ValidationError(c, gin.H{"validation_errors": err.Error()}, middleware.MsgValidationFailed)
+		return
+	}
+
+	targets, err := h.userService.CalculateTargets(c.Request.Context(), &req)
+	if h.handleProfileValidationError(c, ctx, err) {
+		return
+	}
+	h.responseHelper.Success(c, targets, middleware.MsgTargetsCalculated)
+}
+
+// handleProfileValidationError sends a 422 listing the failed fields when err is a *service.ProfileValidationError
+// Returns true if a response was sent
+func (h *UserHandler) handleProfileValidationError(c *gin.Context, ctx context.Context, err error) bool {
+	var profileErr *service.ProfileValidationError
+	if !errors.As(err, &profileErr) {
+		return false
+	}
+
+	h.logger.Warn(ctx, "Profile measurements out of range", logger.Error(err))
+	h.responseHelper.ValidationError(c, gin.H{"validation_errors": err.Error(), "fields": profileErr.Fields}, middleware.MsgValidationFailed)
+	return true
 }
 
 // UpdatePreferences handles updating user preferences
@@ -99,7 +159,7 @@ func (h *UserHandler) UpdatePreferences(c *gin.Context) {
 	userIDStr, exists := middleware.GetUserIDFromContext(c)
 	if !exists {
 		h.logger.Error(ctx, "User ID not found in context")
-		h.responseHelper.Unauthorized(c, gin.H{"error": "User not authenticated"}, "Authentication required")
+		h.responseHelper.Unauthorized(c, gin.H{"error": "User not authenticated"}, middleware.MsgAuthenticationRequired)
 		return
 	}
 
@@ -107,14 +167,14 @@ func (h *UserHandler) UpdatePreferences(c *gin.Context) {
 	var req request.UpdatePreferencesRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		h.logger.Error(ctx, "Failed to bind update preferences request", logger.Error(err))
-		h.responseHelper.BadRequest(c, gin.H{"details": err.Error()}, "Invalid request body")
+		h.responseHelper.BadRequest(c, gin.H{"details": err.Error()}, middleware.MsgInvalidRequestBody)
 		return
 	}
 
 	// Validate request
 	if err := h.structValidator.Struct(&req); err != nil {
 		h.logger.Error(ctx, "Update preferences validation failed", logger.Error(err))
-		h.responseHelper.ValidationError(c, gin.H{"validation_errors": err.Error()}, "Validation failed")
+		h.responseHelper.ValidationError(c, gin.H{"validation_errors": err.Error()}, middleware.MsgValidationFailed)
 		return
 	}
 
@@ -122,12 +182,12 @@ func (h *UserHandler) UpdatePreferences(c *gin.Context) {
 	updatedUser, err := h.userService.UpdatePreferences(c.Request.Context(), userIDStr, &req)
 	if err != nil {
 		h.logger.Error(ctx, "Failed to update user preferences", logger.Error(err))
-		h.responseHelper.InternalError(c, gin.H{"details": err.Error()}, "Failed to update user preferences")
+		h.responseHelper.InternalError(c, gin.H{"details": err.Error()}, middleware.MsgPreferencesUpdateFailed)
 		return
 	}
 
 	h.logger.Info(ctx, "User preferences updated successfully")
-	h.responseHelper.Success(c, updatedUser, "User preferences updated successfully")
+	h.responseHelper.Success(c, updatedUser, middleware.MsgPreferencesUpdated)
 }
 
 // ChangePassword handles changing user password
@@ -138,7 +198,7 @@ func (h *UserHandler) ChangePassword(c *gin.Context) {
 	userIDStr, exists := middleware.GetUserIDFromContext(c)
 	if !exists {
 		h.logger.Error(ctx, "User ID not found in context")
-		h.responseHelper.Unauthorized(c, gin.H{"error": "User not authenticated"}, "Authentication required")
+		h.responseHelper.Unauthorized(c, gin.H{"error": "User not authenticated"}, middleware.MsgAuthenticationRequired)
 		return
 	}
 
@@ -146,25 +206,94 @@ func (h *UserHandler) ChangePassword(c *gin.Context) {
 	var req request.ChangePasswordRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		h.logger.Error(ctx, "Failed to bind change password request", logger.Error(err))
-		h.responseHelper.BadRequest(c, gin.H{"details": err.Error()}, "Invalid request body")
+		h.responseHelper.BadRequest(c, gin.H{"details": err.Error()}, middleware.MsgInvalidRequestBody)
 		return
 	}
 
 	// Validate request
 	if err := h.structValidator.Struct(&req); err != nil {
 		h.logger.Error(ctx, "Change password validation failed", logger.Error(err))
-		h.responseHelper.ValidationError(c, gin.H{"validation_errors": err.Error()}, "Validation failed")
+		h.responseHelper.ValidationError(c, gin.H{"validation_errors": err.Error()}, middleware.MsgValidationFailed)
 		return
 	}
 
 	// Change password
 	if err := h.userService.ChangePassword(c.Request.Context(), userIDStr, &req); err != nil {
 		h.logger.Error(ctx, "Failed to change password", logger.Error(err))
-		h.responseHelper.InternalError(c, gin.H{"details": err.Error()}, "Failed to change password")
+		h.responseHelper.InternalError(c, gin.H{"details": err.Error()}, middleware.MsgPasswordChangeFailed)
 		return
 	}
 
 	h.logger.Info(ctx, "Password changed successfully")
-	h.responseHelper.Success(c, gin.H{"message": "Password changed successfully"}, "Password changed successfully")
+	h.responseHelper.Success(c, gin.H{"message": "Password changed successfully"}, middleware.MsgPasswordChanged)
 }
 
+// DeleteAccount handles deleting the current user's account
+func (h *UserHandler) DeleteAccount(c *gin.Context) {
+	ctx := middleware.GetContext(c)
+
+	// Get user ID from context
+	userIDStr, exists := middleware.GetUserIDFromContext(c)
+	if !exists {
+		h.logger.Error(ctx, "User ID not found in context")
+		h.responseHelper.Unauthorized(c, gin.H{"error": "User not authenticated"}, middleware.MsgAuthenticationRequired)
+		return
+	}
+
+	// Bind request
+	var req request.DeleteAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error(ctx, "Failed to bind delete account request", logger.Error(err))
+		h.responseHelper.BadRequest(c, gin.H{"details": err.Error()}, middleware.MsgInvalidRequestBody)
+		return
+	}
+
+	// Validate request
+	if err := h.structValidator.Struct(&req); err != nil {
+		h.logger.Error(ctx, "Delete account validation failed", logger.Error(err))
+		h.responseHelper.ValidationError(c, gin.H{"validation_errors": err.Error()}, middleware.MsgValidationFailed)
+		return
+	}
+
+	// Delete account
+	if err := h.userService.DeleteAccount(c.Request.Context(), userIDStr, req.Password); err != nil {
+		h.logger.Error(ctx, "Failed to delete account", logger.Error(err))
+		if err.Error() == "invalid password" {
+			h.responseHelper.ErrorCode(c, middleware.ErrorCodeInvalidCredentials)
+			h.responseHelper.Unauthorized(c, gin.H{"error": "Invalid password"}, middleware.MsgPasswordConfirmationFailed)
+			return
+		}
+		h.responseHelper.InternalError(c, gin.H{"details": err.Error()}, middleware.MsgAccountDeleteFailed)
+		return
+	}
+
+	h.logger.Info(ctx, "Account deleted successfully")
+	h.responseHelper.Success(c, gin.H{"message": "Account deleted successfully"}, middleware.MsgAccountDeleted)
+}
+
+// ExportData handles exporting all of the current user's data as a downloadable JSON file
+func (h *UserHandler) ExportData(c *gin.Context) {
+	ctx := middleware.GetContext(c)
+
+	// Get user ID from context
+	userIDStr, exists := middleware.GetUserIDFromContext(c)
+	if !exists {
+		h.logger.Error(ctx, "User ID not found in context")
+		h.responseHelper.Unauthorized(c, gin.H{"error": "User not authenticated"}, middleware.MsgAuthenticationRequired)
+		return
+	}
+
+	// Export data
+	export, err := h.userService.ExportUserData(c.Request.Context(), userIDStr)
+	if err != nil {
+		h.logger.Error(ctx, "Failed to export user data", logger.Error(err))
+		h.responseHelper.InternalError(c, gin.H{"details": err.Error()}, middleware.MsgUserDataExportFailed)
+		return
+	}
+
+	filename := fmt.Sprintf("nutrient-export-%s.json", time.Now().UTC().Format("20060102"))
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+
+	h.logger.Info(ctx, "User data exported successfully")
+	c.IndentedJSON(http.StatusOK, export)
+}
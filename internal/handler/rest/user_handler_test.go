@@ -0,0 +1,256 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"nutrient_be/internal/config"
+	"nutrient_be/internal/domain"
+	"nutrient_be/internal/dto/response"
+	"nutrient_be/internal/handler/middleware"
+	"nutrient_be/internal/pkg/logger"
+	"nutrient_be/internal/service"
+)
+
+// profileUserRepo serves a single user and embeds the interface so only the profile methods need implementing
+type profileUserRepo struct {
+	service.UserRepository
+	user *domain.User
+}
+
+func (r *profileUserRepo) GetByID(ctx context.Context, id primitive.ObjectID) (*domain.User, error) {
+	copied := *r.user
+	return &copied, nil
+}
+
+func (r *profileUserRepo) Update(ctx context.Context, user *domain.User) error {
+	r.user = user
+	return nil
+}
+
+func newProfileRouter(repo *profileUserRepo) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	log := logger.NewNoopLogger()
+	handler := NewUserHandler(service.NewUserService(repo, nil, nil, nil, nil, config.UserConfig{}, log), 0, log)
+
+	router := gin.New()
+	router.Use(middleware.ResponseMiddleware(log))
+	router.Use(func(c *gin.Context) { c.Set("userID", repo.user.ID.Hex()) })
+	router.PUT("/users/profile", handler.UpdateProfile)
+	router.POST("/users/calculate-targets", handler.CalculateTargets)
+	return router
+}
+
+func TestUserHandler_UpdateProfile_GenderAndGoal(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+		wantGender string
+		wantGoal   string
+	}{
+		{name: "accepted", body: `{"gender":"female","goal":"maintenance"}`, wantStatus: http.StatusOK, wantGender: "female", wantGoal: "maintenance"},
+		{name: "normalized", body: `{"gender":"  Male ","goal":"Weight_Loss"}`, wantStatus: http.StatusOK, wantGender: "male", wantGoal: "weight_loss"},
+		{name: "other", body: `{"gender":"OTHER"}`, wantStatus: http.StatusOK, wantGender: "other"},
+		{name: "abbreviated gender", body: `{"gender":"M"}`, wantStatus: http.StatusUnprocessableEntity},
+		{name: "unknown gender", body: `{"gender":"man"}`, wantStatus: http.StatusUnprocessableEntity},
+		{name: "unknown goal", body: `{"goal":"bulk"}`, wantStatus: http.StatusUnprocessableEntity},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &profileUserRepo{user: &domain.User{ID: primitive.NewObjectID()}}
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPut, "/users/profile", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			newProfileRouter(repo).ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("Expected status %d, got: %d", tt.wantStatus, rec.Code)
+			}
+			if repo.user.Profile.Gender != tt.wantGender {
+				t.Errorf("Expected stored gender %q, got: %q", tt.wantGender, repo.user.Profile.Gender)
+			}
+			if repo.user.Profile.Goal != tt.wantGoal {
+				t.Errorf("Expected stored goal %q, got: %q", tt.wantGoal, repo.user.Profile.Goal)
+			}
+		})
+	}
+}
+
+func TestUserHandler_UpdateProfile_IgnoresUnexpectedFields(t *testing.T) {
+	original := domain.User{
+		ID:           primitive.NewObjectID(),
+		Email:        "user@example.com",
+		PasswordHash: "hash",
+		Profile:      domain.UserProfile{Name: "Old name", Age: 30},
+		Preferences:  domain.UserPreferences{Language: "en", CalorieTarget: 2000},
+		CreatedAt:    time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	stored := original
+	repo := &profileUserRepo{user: &stored}
+
+	body := `{
+		"name": "New name",
+		"role": "admin",
+		"verified": true,
+		"email": "attacker@example.com",
+		"passwordHash": "injected",
+		"preferences": {"calorieTarget": 100},
+		"profile": {"age": 99},
+		"createdAt": "2000-01-01T00:00:00Z"
+	}`
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPut, "/users/profile", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	newProfileRouter(repo).ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got: %d", rec.Code)
+	}
+
+	expected := original
+	expected.Profile.Name = "New name"
+	if !reflect.DeepEqual(*repo.user, expected) {
+		t.Errorf("Expected only the name to change, got: %+v", *repo.user)
+	}
+}
+
+func TestUserHandler_CalculateTargets_MatchesUpdateProfile(t *testing.T) {
+	profiles := []string{
+		`{"weight":80,"height":180,"age":30,"gender":"male","goal":"weight_loss","activityLevel":"moderate"}`,
+		`{"weight":62.5,"height":165,"age":45,"gender":"female","goal":"muscle_gain","activityLevel":"very_active"}`,
+		`{"weight":70,"height":172,"age":25,"gender":"Other","goal":"maintenance"}`,
+	}
+
+	for _, body := range profiles {
+		t.Run(body, func(t *testing.T) {
+			user := &domain.User{ID: primitive.NewObjectID(), Preferences: domain.UserPreferences{CalorieTarget: 2000}}
+			repo := &profileUserRepo{user: user}
+			router := newProfileRouter(repo)
+
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/users/calculate-targets", strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			router.ServeHTTP(rec, req)
+			if rec.Code != http.StatusOK {
+				t.Fatalf("Expected status 200, got: %d", rec.Code)
+			}
+			var preview struct {
+				Data response.CalorieTargetsResponse `json:"data"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &preview); err != nil {
+				t.Fatalf("Expected a JSON body, got: %v", err)
+			}
+			if repo.user != user || user.Preferences.CalorieTarget != 2000 {
+				t.Fatal("Expected the preview not to touch the stored user")
+			}
+			if preview.Data.BMR <= 0 || preview.Data.MaintenanceCalories < preview.Data.BMR {
+				t.Errorf("Expected BMR below maintenance calories, got: %+v", preview.Data)
+			}
+
+			rec = httptest.NewRecorder()
+			req = httptest.NewRequest(http.MethodPut, "/users/profile", strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			router.ServeHTTP(rec, req)
+			if rec.Code != http.StatusOK {
+				t.Fatalf("Expected status 200, got: %d", rec.Code)
+			}
+
+			stored := repo.user.Preferences
+			if math.Abs(stored.CalorieTarget-preview.Data.CalorieTarget) > 1e-9 {
+				t.Errorf("Expected calorie target %v, got: %v", stored.CalorieTarget, preview.Data.CalorieTarget)
+			}
+			if stored.MacroTargets.Protein != preview.Data.MacroTargets.Protein ||
+				stored.MacroTargets.Carbohydrates != preview.Data.MacroTargets.Carbohydrates ||
+				stored.MacroTargets.Fat != preview.Data.MacroTargets.Fat {
+				t.Errorf("Expected macro targets %+v, got: %+v", stored.MacroTargets, preview.Data.MacroTargets)
+			}
+		})
+	}
+}
+
+func TestUserHandler_CalculateTargets_RejectsOutOfRange(t *testing.T) {
+	bodies := map[string]string{
+		"missing goal":     `{"weight":80,"height":180,"age":30,"gender":"male"}`,
+		"weight too low":   `{"weight":5,"height":180,"age":30,"gender":"male","goal":"maintenance"}`,
+		"height too high":  `{"weight":80,"height":400,"age":30,"gender":"male","goal":"maintenance"}`,
+		"age too high":     `{"weight":80,"height":180,"age":150,"gender":"male","goal":"maintenance"}`,
+		"unknown activity": `{"weight":80,"height":180,"age":30,"gender":"male","goal":"maintenance","activityLevel":"extreme"}`,
+	}
+
+	for name, body := range bodies {
+		t.Run(name, func(t *testing.T) {
+			repo := &profileUserRepo{user: &domain.User{ID: primitive.NewObjectID()}}
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/users/calculate-targets", strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			newProfileRouter(repo).ServeHTTP(rec, req)
+
+			if rec.Code != http.StatusUnprocessableEntity {
+				t.Errorf("Expected status 422, got: %d", rec.Code)
+			}
+		})
+	}
+}
+
+func TestUserHandler_CalculateTargets_ReportsMissingMeasurements(t *testing.T) {
+	repo := &profileUserRepo{user: &domain.User{ID: primitive.NewObjectID()}}
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/users/calculate-targets", strings.NewReader(`{"weight":80,"gender":"male","goal":"maintenance"}`))
+	req.Header.Set("Content-Type", "application/json")
+	newProfileRouter(repo).ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("Expected status 422, got: %d", rec.Code)
+	}
+	var body struct {
+		Error struct {
+			Fields map[string]string `json:"fields"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Expected a JSON body, got: %v", err)
+	}
+	expected := map[string]string{"age": "must be between 1 and 120", "height": "must be between 50 and 300 cm"}
+	if !reflect.DeepEqual(body.Error.Fields, expected) {
+		t.Errorf("Expected fields %v, got: %v", expected, body.Error.Fields)
+	}
+}
+
+func TestUserHandler_UpdateProfile_ReportsOutOfRangeFields(t *testing.T) {
+	repo := &profileUserRepo{user: &domain.User{ID: primitive.NewObjectID(), Profile: domain.UserProfile{Age: 30}}}
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPut, "/users/profile", strings.NewReader(`{"age":0,"weight":-5,"height":175}`))
+	req.Header.Set("Content-Type", "application/json")
+	newProfileRouter(repo).ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("Expected status 422, got: %d", rec.Code)
+	}
+	var body struct {
+		Error struct {
+			Fields map[string]string `json:"fields"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Expected a JSON body, got: %v", err)
+	}
+	expected := map[string]string{"age": "must be between 1 and 120", "weight": "must be between 20 and 500 kg"}
+	if !reflect.DeepEqual(body.Error.Fields, expected) {
+		t.Errorf("Expected fields %v, got: %v", expected, body.Error.Fields)
+	}
+	if repo.user.Profile.Age != 30 {
+		t.Errorf("Expected the stored age to stay 30, got: %d", repo.user.Profile.Age)
+	}
+}
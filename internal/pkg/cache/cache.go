@@ -0,0 +1,32 @@
+package cache
+
+import (
+	"context"
+)
+
+// Cache interface defines the contract for caching serialized values
+// Values are stored as bytes so that an in-memory store can be swapped for Redis
+// without changing callers
+type Cache interface {
+	// Get returns the value stored under key and whether it was found
+	Get(ctx context.Context, key string) ([]byte, bool)
+	// Set stores value under key, replacing any previous value
+	Set(ctx context.Context, key string, value []byte)
+	// Delete removes the given keys
+	Delete(ctx context.Context, keys ...string)
+	// DeletePrefix removes every key starting with prefix
+	DeletePrefix(ctx context.Context, prefix string)
+}
+
+// noopCache never stores anything
+type noopCache struct{}
+
+// NewNoopCache creates a cache that always misses, used when caching is disabled
+func NewNoopCache() Cache {
+	return noopCache{}
+}
+
+func (noopCache) Get(ctx context.Context, key string) ([]byte, bool) { return nil, false }
+func (noopCache) Set(ctx context.Context, key string, value []byte)  {}
+func (noopCache) Delete(ctx context.Context, keys ...string)         {}
+func (noopCache) DeletePrefix(ctx context.Context, prefix string)    {}
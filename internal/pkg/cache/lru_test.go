@@ -0,0 +1,80 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestLRUCache_GetSet(t *testing.T) {
+	ctx := context.Background()
+	c := NewLRUCache(2, time.Minute)
+
+	if _, ok := c.Get(ctx, "a"); ok {
+		t.Error("Expected miss on empty cache")
+	}
+
+	c.Set(ctx, "a", []byte("1"))
+	value, ok := c.Get(ctx, "a")
+	if !ok || string(value) != "1" {
+		t.Errorf("Expected hit with value 1, got: %q (found=%v)", value, ok)
+	}
+}
+
+func TestLRUCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	ctx := context.Background()
+	c := NewLRUCache(2, time.Minute)
+
+	c.Set(ctx, "a", []byte("1"))
+	c.Set(ctx, "b", []byte("2"))
+	c.Get(ctx, "a") // a is now more recently used than b
+	c.Set(ctx, "c", []byte("3"))
+
+	if _, ok := c.Get(ctx, "b"); ok {
+		t.Error("Expected b to be evicted")
+	}
+	if _, ok := c.Get(ctx, "a"); !ok {
+		t.Error("Expected a to be kept")
+	}
+	if c.Len() != 2 {
+		t.Errorf("Expected 2 entries, got: %d", c.Len())
+	}
+}
+
+func TestLRUCache_Expiry(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := NewLRUCache(10, time.Minute)
+	c.now = func() time.Time { return now }
+
+	c.Set(ctx, "a", []byte("1"))
+	now = now.Add(59 * time.Second)
+	if _, ok := c.Get(ctx, "a"); !ok {
+		t.Error("Expected hit before ttl")
+	}
+
+	now = now.Add(time.Second)
+	if _, ok := c.Get(ctx, "a"); ok {
+		t.Error("Expected miss after ttl")
+	}
+	if c.Len() != 0 {
+		t.Errorf("Expected expired entry to be dropped, got: %d entries", c.Len())
+	}
+}
+
+func TestLRUCache_DeletePrefix(t *testing.T) {
+	ctx := context.Background()
+	c := NewLRUCache(10, time.Minute)
+
+	c.Set(ctx, "foods:public:20:0", []byte("1"))
+	c.Set(ctx, "foods:public:20:20", []byte("2"))
+	c.Set(ctx, "food:abc", []byte("3"))
+	c.DeletePrefix(ctx, "foods:public:")
+
+	if c.Len() != 1 {
+		t.Errorf("Expected 1 entry left, got: %d", c.Len())
+	}
+	if _, ok := c.Get(ctx, "food:abc"); !ok {
+		t.Error("Expected unrelated key to be kept")
+	}
+}
@@ -0,0 +1,108 @@
+package calculator
+
+import (
+	"nutrient_be/internal/domain"
+)
+
+// Nutrients PercentDailyValue can compute %DV for
+const (
+	NutrientCalories      = "calories"
+	NutrientProtein       = "protein"
+	NutrientCarbohydrates = "carbohydrates"
+	NutrientFat           = "fat"
+	NutrientFiber         = "fiber"
+	NutrientSugar         = "sugar"
+	NutrientVitaminA      = "vitaminA"
+	NutrientVitaminC      = "vitaminC"
+	NutrientCalcium       = "calcium"
+	NutrientIron          = "iron"
+	NutrientSodium        = "sodium"
+	NutrientPotassium     = "potassium"
+)
+
+// DefaultDailyReferenceValues returns the FDA reference daily values for adults
+func DefaultDailyReferenceValues() domain.DailyReferenceValues {
+	return domain.DailyReferenceValues{
+		Calories:      2000,
+		Protein:       50,
+		Carbohydrates: 275,
+		Fat:           78,
+		Fiber:         28,
+		Sugar:         50,
+		VitaminA:      900,
+		VitaminC:      90,
+		Calcium:       1300,
+		Iron:          18,
+		Sodium:        2300,
+		Potassium:     4700,
+	}
+}
+
+// DailyValues computes %DV against a reference daily values table
+type DailyValues struct {
+	refs domain.DailyReferenceValues
+}
+
+// NewDailyValues creates a %DV calculator from refs, with each non-zero field of override replacing its reference
+// override is typically a user's preferences, e.g. a 2500 kcal reference
+func NewDailyValues(refs, override domain.DailyReferenceValues) DailyValues {
+	merge := func(ref *float64, value float64) {
+		if value > 0 {
+			*ref = value
+		}
+	}
+	merge(&refs.Calories, override.Calories)
+	merge(&refs.Protein, override.Protein)
+	merge(&refs.Carbohydrates, override.Carbohydrates)
+	merge(&refs.Fat, override.Fat)
+	merge(&refs.Fiber, override.Fiber)
+	merge(&refs.Sugar, override.Sugar)
+	merge(&refs.VitaminA, override.VitaminA)
+	merge(&refs.VitaminC, override.VitaminC)
+	merge(&refs.Calcium, override.Calcium)
+	merge(&refs.Iron, override.Iron)
+	merge(&refs.Sodium, override.Sodium)
+	merge(&refs.Potassium, override.Potassium)
+	return DailyValues{refs: refs}
+}
+
+// References returns the reference values in use
+func (d DailyValues) References() domain.DailyReferenceValues {
+	return d.refs
+}
+
+// PercentDailyValue returns amount as a percentage of the reference daily value for nutrient
+// Unknown nutrients and nutrients without a reference value return 0
+func (d DailyValues) PercentDailyValue(nutrient string, amount float64) float64 {
+	var ref float64
+	switch nutrient {
+	case NutrientCalories:
+		ref = d.refs.Calories
+	case NutrientProtein:
+		ref = d.refs.Protein
+	case NutrientCarbohydrates:
+		ref = d.refs.Carbohydrates
+	case NutrientFat:
+		ref = d.refs.Fat
+	case NutrientFiber:
+		ref = d.refs.Fiber
+	case NutrientSugar:
+		ref = d.refs.Sugar
+	case NutrientVitaminA:
+		ref = d.refs.VitaminA
+	case NutrientVitaminC:
+		ref = d.refs.VitaminC
+	case NutrientCalcium:
+		ref = d.refs.Calcium
+	case NutrientIron:
+		ref = d.refs.Iron
+	case NutrientSodium:
+		ref = d.refs.Sodium
+	case NutrientPotassium:
+		ref = d.refs.Potassium
+	}
+	if ref <= 0 {
+		return 0
+	}
+	return amount / ref * 100
+}
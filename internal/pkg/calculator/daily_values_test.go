@@ -0,0 +1,44 @@
+package calculator
+
+import (
+	"math"
+	"testing"
+
+	"nutrient_be/internal/domain"
+)
+
+func TestPercentDailyValue_Defaults(t *testing.T) {
+	dv := NewDailyValues(DefaultDailyReferenceValues(), domain.DailyReferenceValues{})
+
+	tests := []struct {
+		nutrient string
+		amount   float64
+		expected float64
+	}{
+		{NutrientProtein, 25, 50},
+		{NutrientProtein, 50, 100},
+		{NutrientFiber, 7, 25},
+		{NutrientFiber, 14, 50},
+		{NutrientCalories, 500, 25},
+		{"unknown", 10, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.nutrient, func(t *testing.T) {
+			if got := dv.PercentDailyValue(tt.nutrient, tt.amount); math.Abs(got-tt.expected) > 1e-9 {
+				t.Errorf("Expected %.1f%% DV for %v %s, got: %.1f", tt.expected, tt.amount, tt.nutrient, got)
+			}
+		})
+	}
+}
+
+func TestPercentDailyValue_Override(t *testing.T) {
+	dv := NewDailyValues(DefaultDailyReferenceValues(), domain.DailyReferenceValues{Calories: 2500})
+
+	if got := dv.PercentDailyValue(NutrientCalories, 500); math.Abs(got-20) > 1e-9 {
+		t.Errorf("Expected 20%% DV against a 2500 kcal reference, got: %.1f", got)
+	}
+	if got := dv.PercentDailyValue(NutrientProtein, 25); math.Abs(got-50) > 1e-9 {
+		t.Errorf("Expected protein to keep the default reference, got: %.1f", got)
+	}
+}
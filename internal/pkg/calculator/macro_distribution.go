@@ -0,0 +1,57 @@
+package calculator
+
+import (
+	"nutrient_be/internal/domain"
+)
+
+// Energy per gram of each calorie-providing macro (Atwater factors)
+const (
+	ProteinCaloriesPerGram      = 4.0
+	CarbohydrateCaloriesPerGram = 4.0
+	FatCaloriesPerGram          = 9.0
+	FiberCaloriesPerGram        = 2.0
+)
+
+// CaloriesFromMacros estimates the energy of a food from its macros using the Atwater factors
+// Fiber is counted at roughly 2 cal/g and sugar is already part of the carbohydrates
+func CaloriesFromMacros(macros domain.MacroNutrients) float64 {
+	return macros.Protein*ProteinCaloriesPerGram +
+		macros.Carbohydrates*CarbohydrateCaloriesPerGram +
+		macros.Fat*FatCaloriesPerGram +
+		macros.Fiber*FiberCaloriesPerGram
+}
+
+// MacroDistribution is the share of macro calories coming from protein, carbohydrates and fat, in percent
+type MacroDistribution struct {
+	Protein       float64
+	Carbohydrates float64
+	Fat           float64
+}
+
+// CalculateMacroDistribution splits the calories provided by macros into percentages
+// The shares are relative to the macro calories, so they sum to 100 unless there are none, in which case all are 0
+func CalculateMacroDistribution(macros domain.MacroNutrients) MacroDistribution {
+	protein := macros.Protein * ProteinCaloriesPerGram
+	carbohydrates := macros.Carbohydrates * CarbohydrateCaloriesPerGram
+	fat := macros.Fat * FatCaloriesPerGram
+
+	total := protein + carbohydrates + fat
+	if total <= 0 {
+		return MacroDistribution{}
+	}
+	return MacroDistribution{
+		Protein:       protein / total * 100,
+		Carbohydrates: carbohydrates / total * 100,
+		Fat:           fat / total * 100,
+	}
+}
+
+// MacroGramsFromDistribution turns a calorie target and its split between macros into grams of each macro
+// It is the inverse of CalculateMacroDistribution for a split that sums to 100
+func MacroGramsFromDistribution(calories float64, dist MacroDistribution) domain.MacroNutrients {
+	return domain.MacroNutrients{
+		Protein:       calories * dist.Protein / 100 / ProteinCaloriesPerGram,
+		Carbohydrates: calories * dist.Carbohydrates / 100 / CarbohydrateCaloriesPerGram,
+		Fat:           calories * dist.Fat / 100 / FatCaloriesPerGram,
+	}
+}
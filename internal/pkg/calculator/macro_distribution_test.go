@@ -0,0 +1,40 @@
+package calculator
+
+import (
+	"math"
+	"testing"
+
+	"nutrient_be/internal/domain"
+)
+
+func TestCalculateMacroDistribution(t *testing.T) {
+	// 75g protein (300 kcal), 100g carbs (400 kcal) and 33.3g fat (300 kcal)
+	dist := CalculateMacroDistribution(domain.MacroNutrients{Protein: 75, Carbohydrates: 100, Fat: 300.0 / 9})
+
+	if math.Abs(dist.Protein-30) > 1e-9 || math.Abs(dist.Carbohydrates-40) > 1e-9 || math.Abs(dist.Fat-30) > 1e-9 {
+		t.Errorf("Expected a 30/40/30 split, got: %.1f/%.1f/%.1f", dist.Protein, dist.Carbohydrates, dist.Fat)
+	}
+	if sum := dist.Protein + dist.Carbohydrates + dist.Fat; math.Abs(sum-100) > 1e-9 {
+		t.Errorf("Expected percentages to sum to 100, got: %v", sum)
+	}
+}
+
+func TestCalculateMacroDistribution_NoCalories(t *testing.T) {
+	if dist := CalculateMacroDistribution(domain.MacroNutrients{Fiber: 10}); dist != (MacroDistribution{}) {
+		t.Errorf("Expected a 0/0/0 split without macro calories, got: %+v", dist)
+	}
+}
+
+func TestMacroGramsFromDistribution(t *testing.T) {
+	// 40% of 2000 kcal is 800 kcal of protein (200g), 30% is 600 kcal of carbs (150g) and of fat (66.7g)
+	macros := MacroGramsFromDistribution(2000, MacroDistribution{Protein: 40, Carbohydrates: 30, Fat: 30})
+
+	if math.Abs(macros.Protein-200) > 1e-9 || math.Abs(macros.Carbohydrates-150) > 1e-9 || math.Abs(macros.Fat-600.0/9) > 1e-9 {
+		t.Errorf("Expected 200g/150g/66.7g, got: %.1f/%.1f/%.1f", macros.Protein, macros.Carbohydrates, macros.Fat)
+	}
+
+	back := CalculateMacroDistribution(macros)
+	if math.Abs(back.Protein-40) > 1e-9 || math.Abs(back.Carbohydrates-30) > 1e-9 || math.Abs(back.Fat-30) > 1e-9 {
+		t.Errorf("Expected the grams to split back to 40/30/30, got: %+v", back)
+	}
+}
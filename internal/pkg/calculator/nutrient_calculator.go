@@ -2,6 +2,7 @@ package calculator
 
 import (
 	"fmt"
+	"math"
 
 	"nutrient_be/internal/domain"
 )
@@ -24,24 +25,11 @@ func CalculateNutrientsForServing(
 	servingUnit string,
 	amount float64,
 ) (float64, domain.MacroNutrients, domain.MicroNutrients, error) {
-	// Find the matching serving size
-	var servingSize *domain.ServingSize
-	for i := range food.ServingSizes {
-		if food.ServingSizes[i].Unit == servingUnit {
-			servingSize = &food.ServingSizes[i]
-			break
-		}
-	}
-
-	if servingSize == nil {
-		return 0, domain.MacroNutrients{}, domain.MicroNutrients{},
-			fmt.Errorf("serving unit '%s' not found for food '%s'", servingUnit, food.Name)
+	totalGrams, err := ConvertToGrams(food, servingUnit, amount)
+	if err != nil {
+		return 0, domain.MacroNutrients{}, domain.MicroNutrients{}, err
 	}
 
-	// Calculate total grams: (amount / servingSize.amount) * servingSize.gramEquivalent
-	// Example: 2 cups where 1 cup = 250g -> (2 / 1) * 250 = 500g
-	totalGrams := (amount / servingSize.Amount) * servingSize.GramEquivalent
-
 	// Calculate multiplier: totalGrams / 100 (since food nutrients are per 100g)
 	multiplier := totalGrams / 100.0
 
@@ -63,9 +51,26 @@ func CalculateNutrientsForServing(
 		Potassium: food.Micros.Potassium * multiplier,
 	}
 
+	// A corrupt stored value or a zero-sized serving must not leak NaN or Inf into saved totals
+	if !IsFinite(calories,
+		macros.Protein, macros.Carbohydrates, macros.Fat, macros.Fiber, macros.Sugar,
+		micros.VitaminA, micros.VitaminC, micros.Calcium, micros.Iron, micros.Sodium, micros.Potassium) {
+		return 0, domain.MacroNutrients{}, domain.MicroNutrients{}, fmt.Errorf("nutrients for %g %s of food '%s' are not finite numbers", amount, servingUnit, food.Name)
+	}
+
 	return calories, macros, micros, nil
 }
 
+// IsFinite reports whether none of the values is NaN or ±Inf
+func IsFinite(values ...float64) bool {
+	for _, v := range values {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return false
+		}
+	}
+	return true
+}
+
 // SumMacros sums multiple macro nutrient values
 func SumMacros(macrosList ...domain.MacroNutrients) domain.MacroNutrients {
 	result := domain.MacroNutrients{}
@@ -93,3 +98,14 @@ func SumMicros(microsList ...domain.MicroNutrients) domain.MicroNutrients {
 	return result
 }
 
+
+// ScaleMacros multiplies every macro nutrient value by factor
+func ScaleMacros(macros domain.MacroNutrients, factor float64) domain.MacroNutrients {
+	return domain.MacroNutrients{
+		Protein:       macros.Protein * factor,
+		Carbohydrates: macros.Carbohydrates * factor,
+		Fat:           macros.Fat * factor,
+		Fiber:         macros.Fiber * factor,
+		Sugar:         macros.Sugar * factor,
+	}
+}
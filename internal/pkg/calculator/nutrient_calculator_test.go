@@ -0,0 +1,34 @@
+package calculator
+
+import (
+	"math"
+	"testing"
+
+	"nutrient_be/internal/domain"
+)
+
+func TestCalculateNutrientsForServing_RejectsNonFiniteResults(t *testing.T) {
+	food := &domain.FoodItem{
+		Name:         map[string]string{"en": "Rice"},
+		Calories:     130,
+		Macros:       domain.MacroNutrients{Carbohydrates: 28},
+		ServingSizes: []domain.ServingSize{{Unit: "gram", Amount: 100, GramEquivalent: 100}},
+	}
+
+	if _, _, _, err := CalculateNutrientsForServing(food, "gram", 150); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if _, _, _, err := CalculateNutrientsForServing(food, "gram", math.NaN()); err == nil {
+		t.Error("Expected an error for a NaN amount, got: nil")
+	}
+
+	food.Macros.Fat = math.Inf(1)
+	calories, _, _, err := CalculateNutrientsForServing(food, "gram", 100)
+	if err == nil {
+		t.Error("Expected an error for an infinite stored macro, got: nil")
+	}
+	if calories != 0 {
+		t.Errorf("Expected no calories on error, got: %v", calories)
+	}
+}
@@ -0,0 +1,218 @@
+package calculator
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+
+	"nutrient_be/internal/domain"
+)
+
+// gramUnits are serving units that already express a weight in grams
+var gramUnits = map[string]bool{
+	"g":     true,
+	"gram":  true,
+	"grams": true,
+}
+
+// uncountableUnits are units that are not pluralized when displayed
+var uncountableUnits = map[string]bool{
+	"g":    true,
+	"gram": true,
+	"kg":   true,
+	"ml":   true,
+	"l":    true,
+}
+
+// Unit systems amounts can be presented in; amounts are always stored in metric
+const (
+	UnitSystemMetric   = "metric"
+	UnitSystemImperial = "imperial"
+)
+
+// GramsPerOunce is the weight of one avoirdupois ounce in grams
+const GramsPerOunce = 28.349523125
+
+// UnitConversions gives the size of convertible units in a common base unit of their kind,
+// e.g. Mass {"gram": 1, "kg": 1000} or Volume {"ml": 1, "cup": 240}
+// Only units of the same kind convert into each other; count units such as "piece" or "box" are
+// food-specific and have no table, so they are never converted
+type UnitConversions struct {
+	Mass   map[string]float64
+	Volume map[string]float64
+}
+
+// DefaultUnitConversions returns the conversions used until SetUnitConversions is called
+func DefaultUnitConversions() UnitConversions {
+	return UnitConversions{
+		Mass:   map[string]float64{"g": 1, "gram": 1, "grams": 1, "kg": 1000, "oz": 28.35, "lb": 453.59},
+		Volume: map[string]float64{"ml": 1, "l": 1000, "cup": 240, "tbsp": 15, "tsp": 5},
+	}
+}
+
+// unitFactor is the kind of a convertible unit and its size in the kind's base unit
+type unitFactor struct {
+	kind   string
+	factor float64
+}
+
+// unitConversions is the conversion table used by ConvertToGrams, keyed by unit
+var unitConversions = struct {
+	mu      sync.RWMutex
+	factors map[string]unitFactor
+}{factors: unitFactors(DefaultUnitConversions())}
+
+// SetUnitConversions replaces the conversions used for units a food does not define
+// Factors must be positive and finite, and a unit may only belong to one kind
+func SetUnitConversions(conversions UnitConversions) error {
+	for _, table := range []map[string]float64{conversions.Mass, conversions.Volume} {
+		for unit, factor := range table {
+			if !(factor > 0) || math.IsInf(factor, 0) {
+				return fmt.Errorf("invalid conversion factor for unit '%s': %v (must be positive)", unit, factor)
+			}
+		}
+	}
+	for unit := range conversions.Mass {
+		if _, ok := conversions.Volume[unit]; ok {
+			return fmt.Errorf("invalid unit conversions: unit '%s' is both a mass and a volume", unit)
+		}
+	}
+
+	factors := unitFactors(conversions)
+	unitConversions.mu.Lock()
+	defer unitConversions.mu.Unlock()
+	unitConversions.factors = factors
+	return nil
+}
+
+// unitFactors indexes the conversion tables by unit
+func unitFactors(conversions UnitConversions) map[string]unitFactor {
+	factors := make(map[string]unitFactor, len(conversions.Mass)+len(conversions.Volume))
+	for unit, factor := range conversions.Mass {
+		factors[unit] = unitFactor{kind: "mass", factor: factor}
+	}
+	for unit, factor := range conversions.Volume {
+		factors[unit] = unitFactor{kind: "volume", factor: factor}
+	}
+	return factors
+}
+
+// IsConvertibleUnit reports whether unit is in the conversion table, so it can stand in for a food's own unit of its kind
+func IsConvertibleUnit(unit string) bool {
+	unitConversions.mu.RLock()
+	defer unitConversions.mu.RUnlock()
+	_, ok := unitConversions.factors[unit]
+	return ok
+}
+
+// IsGramUnit reports whether unit expresses a weight in grams, e.g. "g" or "gram"
+func IsGramUnit(unit string) bool {
+	return gramUnits[unit]
+}
+
+// GramsToOunces converts grams to ounces for display, rounded to two decimals, e.g. 100g -> 3.53 oz
+func GramsToOunces(grams float64) float64 {
+	return math.Round(grams/GramsPerOunce*100) / 100
+}
+
+// FindServingSize returns the serving size of a food matching the given unit, or nil
+func FindServingSize(food *domain.FoodItem, unit string) *domain.ServingSize {
+	for i := range food.ServingSizes {
+		if food.ServingSizes[i].Unit == unit {
+			return &food.ServingSizes[i]
+		}
+	}
+	return nil
+}
+
+// ConvertToGrams converts an amount in the given serving unit of a food to grams
+// Example: 2 cups where 1 cup = 250g -> (2 / 1) * 250 = 500g
+// A unit the food does not define is first converted into one it does of the same kind, e.g. kg into gram
+func ConvertToGrams(food *domain.FoodItem, servingUnit string, amount float64) (float64, error) {
+	servingSize := FindServingSize(food, servingUnit)
+	if servingSize == nil {
+		servingSize, amount = findConvertibleServing(food, servingUnit, amount)
+	}
+	if servingSize == nil {
+		return 0, fmt.Errorf("serving unit '%s' not found for food '%s'", servingUnit, food.Name)
+	}
+	if servingSize.Amount <= 0 {
+		return 0, fmt.Errorf("serving unit '%s' has invalid amount for food '%s'", servingUnit, food.Name)
+	}
+
+	return (amount / servingSize.Amount) * servingSize.GramEquivalent, nil
+}
+
+// findConvertibleServing returns the first serving size of a food in a unit of the same kind as unit,
+// and amount converted into that serving's unit, or nil when unit cannot be converted
+func findConvertibleServing(food *domain.FoodItem, unit string, amount float64) (*domain.ServingSize, float64) {
+	unitConversions.mu.RLock()
+	defer unitConversions.mu.RUnlock()
+
+	requested, ok := unitConversions.factors[unit]
+	if !ok {
+		return nil, 0
+	}
+	for i := range food.ServingSizes {
+		serving := &food.ServingSizes[i]
+		if defined, ok := unitConversions.factors[serving.Unit]; ok && defined.kind == requested.kind {
+			return serving, amount * requested.factor / defined.factor
+		}
+	}
+	return nil, 0
+}
+
+// PreferredDisplayServing returns the serving size used to present amounts of a food to people
+// The first non-gram serving (e.g. "piece", "ml") is preferred; nil means grams should be shown
+func PreferredDisplayServing(food *domain.FoodItem) *domain.ServingSize {
+	for i := range food.ServingSizes {
+		serving := &food.ServingSizes[i]
+		if !gramUnits[serving.Unit] && serving.GramEquivalent > 0 && serving.Amount > 0 {
+			return serving
+		}
+	}
+	return nil
+}
+
+// ConvertFromGrams converts grams into the given serving size, rounded to two decimals
+func ConvertFromGrams(servingSize *domain.ServingSize, grams float64) float64 {
+	amount := grams / servingSize.GramEquivalent * servingSize.Amount
+	return math.Round(amount*100) / 100
+}
+
+// FormatQuantity renders an amount and unit for display, e.g. "3 pieces" or "250 ml"
+func FormatQuantity(amount float64, unit string) string {
+	formatted := strconv.FormatFloat(amount, 'f', -1, 64)
+	if amount != 1 && !uncountableUnits[unit] {
+		unit = pluralize(unit)
+	}
+	return formatted + " " + unit
+}
+
+// FormatDisplayAmount renders grams of a food in its preferred display unit, e.g. "2 pieces" for 236g of a 118g piece
+// Amounts are rounded to one decimal and prefixed with "≈" when rounding changed them; foods without a display serving are shown in grams
+func FormatDisplayAmount(food *domain.FoodItem, grams float64) string {
+	amount, unit := grams, "g"
+	if serving := PreferredDisplayServing(food); serving != nil {
+		amount, unit = grams/serving.GramEquivalent*serving.Amount, serving.Unit
+	}
+
+	rounded := math.Round(amount*10) / 10
+	display := FormatQuantity(rounded, unit)
+	if math.Abs(rounded-amount) > 1e-9 {
+		display = "≈" + display
+	}
+	return display
+}
+
+// pluralize returns the plural of a simple English unit name
+func pluralize(unit string) string {
+	for _, suffix := range []string{"s", "x", "ch", "sh"} {
+		if strings.HasSuffix(unit, suffix) {
+			return unit + "es"
+		}
+	}
+	return unit + "s"
+}
@@ -0,0 +1,151 @@
+package calculator
+
+import (
+	"math"
+	"testing"
+
+	"nutrient_be/internal/domain"
+)
+
+func TestFormatDisplayAmount(t *testing.T) {
+	apple := &domain.FoodItem{
+		Name: map[string]string{"en": "Apple"},
+		ServingSizes: []domain.ServingSize{
+			{Unit: "gram", Amount: 100, GramEquivalent: 100},
+			{Unit: "piece", Amount: 1, GramEquivalent: 118},
+		},
+	}
+	rice := &domain.FoodItem{
+		Name:         map[string]string{"en": "Rice"},
+		ServingSizes: []domain.ServingSize{{Unit: "gram", Amount: 100, GramEquivalent: 100}},
+	}
+
+	tests := []struct {
+		name     string
+		food     *domain.FoodItem
+		grams    float64
+		expected string
+	}{
+		{"whole pieces", apple, 236, "2 pieces"},
+		{"single piece", apple, 118, "1 piece"},
+		{"approximate pieces", apple, 150, "≈1.3 pieces"},
+		{"grams only", rice, 150, "150 g"},
+		{"approximate grams", rice, 80.04, "≈80 g"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatDisplayAmount(tt.food, tt.grams); got != tt.expected {
+				t.Errorf("Expected %q, got: %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestCalculateNutrientsForServing_ConvertsKgForGramOnlyFood(t *testing.T) {
+	rice := &domain.FoodItem{
+		Name:         map[string]string{"en": "Rice"},
+		Calories:     130,
+		Macros:       domain.MacroNutrients{Carbohydrates: 28},
+		ServingSizes: []domain.ServingSize{{Unit: "gram", Amount: 100, GramEquivalent: 100}},
+	}
+
+	calories, macros, _, err := CalculateNutrientsForServing(rice, "kg", 0.25)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if math.Abs(calories-325) > 1e-9 || math.Abs(macros.Carbohydrates-70) > 1e-9 {
+		t.Errorf("Expected 325 kcal and 70 g carbohydrates for 0.25 kg, got: %v kcal, %v g", calories, macros.Carbohydrates)
+	}
+}
+
+func TestConvertToGrams_UnitConversions(t *testing.T) {
+	milk := &domain.FoodItem{
+		Name:         map[string]string{"en": "Milk"},
+		ServingSizes: []domain.ServingSize{{Unit: "ml", Amount: 100, GramEquivalent: 103}},
+	}
+	egg := &domain.FoodItem{
+		Name:         map[string]string{"en": "Egg"},
+		ServingSizes: []domain.ServingSize{{Unit: "piece", Amount: 1, GramEquivalent: 50}},
+	}
+
+	tests := []struct {
+		name    string
+		food    *domain.FoodItem
+		unit    string
+		amount  float64
+		grams   float64
+		wantErr bool
+	}{
+		{name: "defined unit", food: milk, unit: "ml", amount: 200, grams: 206},
+		{name: "volume into ml", food: milk, unit: "cup", amount: 1, grams: 247.2},
+		{name: "mass into volume", food: milk, unit: "kg", amount: 1, wantErr: true},
+		{name: "count unit", food: egg, unit: "box", amount: 1, wantErr: true},
+		{name: "mass into count", food: egg, unit: "gram", amount: 100, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			grams, err := ConvertToGrams(tt.food, tt.unit, tt.amount)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Expected an error, got: %v g", grams)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			if math.Abs(grams-tt.grams) > 1e-9 {
+				t.Errorf("Expected %v g, got: %v", tt.grams, grams)
+			}
+		})
+	}
+}
+
+func TestSetUnitConversions(t *testing.T) {
+	defer func() {
+		if err := SetUnitConversions(DefaultUnitConversions()); err != nil {
+			t.Fatalf("Expected the defaults to be accepted, got: %v", err)
+		}
+	}()
+
+	invalid := []UnitConversions{
+		{Mass: map[string]float64{"gram": 1, "kg": 0}},
+		{Mass: map[string]float64{"gram": 1, "kg": math.Inf(1)}},
+		{Mass: map[string]float64{"gram": 1}, Volume: map[string]float64{"gram": 1}},
+	}
+	for _, conversions := range invalid {
+		if err := SetUnitConversions(conversions); err == nil {
+			t.Errorf("Expected %+v to be rejected, got: nil", conversions)
+		}
+	}
+
+	// A custom table replaces the defaults
+	if err := SetUnitConversions(UnitConversions{Mass: map[string]float64{"gram": 1, "jin": 500}}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	rice := &domain.FoodItem{ServingSizes: []domain.ServingSize{{Unit: "gram", Amount: 100, GramEquivalent: 100}}}
+	if grams, err := ConvertToGrams(rice, "jin", 2); err != nil || grams != 1000 {
+		t.Errorf("Expected 1000 g for 2 jin, got: %v, %v", grams, err)
+	}
+	if _, err := ConvertToGrams(rice, "kg", 1); err == nil {
+		t.Error("Expected kg to be unknown after replacing the table, got: nil")
+	}
+}
+
+func TestGramsToOunces(t *testing.T) {
+	tests := []struct {
+		grams    float64
+		expected float64
+	}{
+		{grams: 100, expected: 3.53},
+		{grams: 28.349523125, expected: 1},
+		{grams: 453.59, expected: 16},
+		{grams: 0, expected: 0},
+	}
+	for _, tt := range tests {
+		if got := GramsToOunces(tt.grams); got != tt.expected {
+			t.Errorf("Expected %v g to be %v oz, got: %v", tt.grams, tt.expected, got)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package circuitbreaker
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// State is the position of a circuit breaker
+type State string
+
+// Circuit breaker states
+const (
+	StateClosed   State = "closed"    // Calls pass and consecutive failures are counted
+	StateOpen     State = "open"      // Calls are rejected until the open duration has passed
+	StateHalfOpen State = "half-open" // One call passes as a probe; its result closes or reopens the breaker
+)
+
+// ErrOpen is returned instead of running a call while the breaker is open
+var ErrOpen = errors.New("circuit breaker is open")
+
+// Breaker stops calling a failing dependency after a run of consecutive failures
+// Once the open duration has passed it lets a single call through as a probe, rejecting the others
+// until it is recorded: a success closes the breaker and a failure opens it for another open duration.
+// A threshold below 1 disables the breaker, so it never opens.
+type Breaker struct {
+	threshold    int
+	openDuration time.Duration
+	now          func() time.Time
+
+	mu       sync.Mutex
+	state    State
+	failures int
+	openedAt time.Time
+	probing  bool // A half-open probe has been let through and not recorded yet
+}
+
+// New creates a closed breaker that opens after threshold consecutive failures
+func New(threshold int, openDuration time.Duration) *Breaker {
+	return &Breaker{
+		threshold:    threshold,
+		openDuration: openDuration,
+		now:          time.Now,
+		state:        StateClosed,
+	}
+}
+
+// State returns the current state; an open breaker whose open duration has passed reports half-open
+func (b *Breaker) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.currentState()
+}
+
+// Allow returns ErrOpen while the breaker is open and nil when a call may run
+// In half-open only the first caller is let through; the others get ErrOpen until its outcome is recorded
+func (b *Breaker) Allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.currentState() {
+	case StateOpen:
+		return ErrOpen
+	case StateHalfOpen:
+		if b.probing {
+			return ErrOpen
+		}
+		b.probing = true
+	}
+	return nil
+}
+
+// Record reports the outcome of a call that Allow let through
+func (b *Breaker) Record(failed bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.currentState() {
+	case StateHalfOpen:
+		if failed {
+			b.open()
+		} else {
+			b.close()
+		}
+	case StateClosed:
+		if !failed {
+			b.failures = 0
+			return
+		}
+		b.failures++
+		if b.threshold > 0 && b.failures >= b.threshold {
+			b.open()
+		}
+	}
+	// Calls that were let through before the breaker opened do not change an open breaker
+}
+
+// currentState resolves an expired open state to half-open; callers hold mu
+func (b *Breaker) currentState() State {
+	if b.state == StateOpen && b.now().Sub(b.openedAt) >= b.openDuration {
+		b.state = StateHalfOpen
+	}
+	return b.state
+}
+
+// open starts a new open period; callers hold mu
+func (b *Breaker) open() {
+	b.state = StateOpen
+	b.openedAt = b.now()
+	b.failures = 0
+	b.probing = false
+}
+
+// close resets the breaker; callers hold mu
+func (b *Breaker) close() {
+	b.state = StateClosed
+	b.failures = 0
+	b.probing = false
+}
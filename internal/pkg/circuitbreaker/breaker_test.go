@@ -0,0 +1,120 @@
+package circuitbreaker
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newTestBreaker returns a breaker whose clock is advanced by the returned function
+func newTestBreaker(threshold int, openDuration time.Duration) (*Breaker, func(time.Duration)) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	b := New(threshold, openDuration)
+	b.now = func() time.Time { return now }
+	return b, func(d time.Duration) { now = now.Add(d) }
+}
+
+func TestBreaker_OpensAfterConsecutiveFailures(t *testing.T) {
+	b, _ := newTestBreaker(3, time.Minute)
+
+	b.Record(true)
+	b.Record(true)
+	if state := b.State(); state != StateClosed {
+		t.Fatalf("Expected closed below the threshold, got: %s", state)
+	}
+
+	b.Record(true)
+	if state := b.State(); state != StateOpen {
+		t.Fatalf("Expected open at the threshold, got: %s", state)
+	}
+	if err := b.Allow(); !errors.Is(err, ErrOpen) {
+		t.Errorf("Expected ErrOpen while open, got: %v", err)
+	}
+}
+
+func TestBreaker_SuccessResetsFailureCount(t *testing.T) {
+	b, _ := newTestBreaker(2, time.Minute)
+
+	b.Record(true)
+	b.Record(false)
+	b.Record(true)
+	if state := b.State(); state != StateClosed {
+		t.Errorf("Expected failures separated by a success to keep the breaker closed, got: %s", state)
+	}
+}
+
+func TestBreaker_HalfOpenSuccessCloses(t *testing.T) {
+	b, advance := newTestBreaker(1, time.Minute)
+	b.Record(true)
+
+	advance(time.Minute)
+	if state := b.State(); state != StateHalfOpen {
+		t.Fatalf("Expected half-open after the open duration, got: %s", state)
+	}
+	if err := b.Allow(); err != nil {
+		t.Fatalf("Expected a probe to be allowed, got: %v", err)
+	}
+
+	b.Record(false)
+	if state := b.State(); state != StateClosed {
+		t.Errorf("Expected a successful probe to close the breaker, got: %s", state)
+	}
+}
+
+func TestBreaker_HalfOpenLetsOneProbeThrough(t *testing.T) {
+	b, advance := newTestBreaker(1, time.Minute)
+	b.Record(true)
+	advance(time.Minute)
+
+	var allowed int32
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if b.Allow() == nil {
+				atomic.AddInt32(&allowed, 1)
+			}
+		}()
+	}
+	wg.Wait()
+	if allowed != 1 {
+		t.Fatalf("Expected a single probe to be let through, got: %d", allowed)
+	}
+	if err := b.Allow(); !errors.Is(err, ErrOpen) {
+		t.Errorf("Expected ErrOpen while the probe is in flight, got: %v", err)
+	}
+
+	b.Record(false)
+	if err := b.Allow(); err != nil {
+		t.Errorf("Expected calls to pass once the probe closed the breaker, got: %v", err)
+	}
+}
+
+func TestBreaker_HalfOpenFailureReopens(t *testing.T) {
+	b, advance := newTestBreaker(1, time.Minute)
+	b.Record(true)
+
+	advance(time.Minute)
+	b.Record(true)
+	if state := b.State(); state != StateOpen {
+		t.Fatalf("Expected a failed probe to reopen the breaker, got: %s", state)
+	}
+
+	advance(30 * time.Second)
+	if err := b.Allow(); !errors.Is(err, ErrOpen) {
+		t.Errorf("Expected a new open period after the failed probe, got: %v", err)
+	}
+}
+
+func TestBreaker_DisabledNeverOpens(t *testing.T) {
+	b, _ := newTestBreaker(0, time.Minute)
+	for i := 0; i < 10; i++ {
+		b.Record(true)
+	}
+	if err := b.Allow(); err != nil {
+		t.Errorf("Expected a disabled breaker to allow calls, got: %v", err)
+	}
+}
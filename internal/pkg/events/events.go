@@ -0,0 +1,45 @@
+package events
+
+import (
+	"context"
+)
+
+// Subjects of the events published by the application
+const (
+	SubjectMealPlanUpdated = "mealplan.updated"
+)
+
+// MealPlanUpdated is the payload of a mealplan.updated event
+type MealPlanUpdated struct {
+	MealPlanID string `json:"mealPlanId"`
+	UserID     string `json:"userId"`
+}
+
+// Handler processes a single event payload
+type Handler func(ctx context.Context, data []byte) error
+
+// Publisher interface defines the contract for publishing events
+type Publisher interface {
+	// Publish sends data to every subscriber of subject
+	Publish(ctx context.Context, subject string, data []byte) error
+}
+
+// Bus interface defines the contract for publishing and subscribing to events
+// The in-memory implementation can be swapped for NATS without changing callers
+type Bus interface {
+	Publisher
+	// Subscribe registers handler for subject and returns a function that removes it
+	Subscribe(subject string, handler Handler) (func(), error)
+	// Close stops accepting events and waits for in-flight handlers until ctx is done
+	Close(ctx context.Context) error
+}
+
+// noopPublisher drops every event
+type noopPublisher struct{}
+
+// NewNoopPublisher creates a publisher that discards events, used when nothing consumes them
+func NewNoopPublisher() Publisher {
+	return noopPublisher{}
+}
+
+func (noopPublisher) Publish(ctx context.Context, subject string, data []byte) error { return nil }
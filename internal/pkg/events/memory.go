@@ -0,0 +1,92 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// memoryBus delivers events to subscribers in the same process
+// Each handler runs in its own goroutine so publishers are never blocked by slow consumers
+type memoryBus struct {
+	mu       sync.RWMutex
+	handlers map[string]map[int]Handler
+	nextID   int
+	closed   bool
+	inflight sync.WaitGroup
+	ctx      context.Context
+	cancel   context.CancelFunc
+}
+
+// NewMemoryBus creates an in-process event bus
+func NewMemoryBus() Bus {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &memoryBus{
+		handlers: make(map[string]map[int]Handler),
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+}
+
+func (b *memoryBus) Publish(ctx context.Context, subject string, data []byte) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if b.closed {
+		return fmt.Errorf("event bus is closed")
+	}
+
+	for _, handler := range b.handlers[subject] {
+		b.inflight.Add(1)
+		go func(handler Handler) {
+			defer b.inflight.Done()
+			// Handlers outlive the publishing request, so they get the bus context instead
+			_ = handler(b.ctx, data)
+		}(handler)
+	}
+	return nil
+}
+
+func (b *memoryBus) Subscribe(subject string, handler Handler) (func(), error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return nil, fmt.Errorf("event bus is closed")
+	}
+
+	if b.handlers[subject] == nil {
+		b.handlers[subject] = make(map[int]Handler)
+	}
+	id := b.nextID
+	b.nextID++
+	b.handlers[subject][id] = handler
+
+	return func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		delete(b.handlers[subject], id)
+	}, nil
+}
+
+func (b *memoryBus) Close(ctx context.Context) error {
+	b.mu.Lock()
+	b.closed = true
+	b.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		b.inflight.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		b.cancel()
+		return nil
+	case <-ctx.Done():
+		// Tell handlers still running to give up
+		b.cancel()
+		return fmt.Errorf("timed out waiting for event handlers: %w", ctx.Err())
+	}
+}
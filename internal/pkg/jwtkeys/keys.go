@@ -0,0 +1,110 @@
+package jwtkeys
+
+import (
+	"crypto/rsa"
+	"fmt"
+	"os"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Supported signing algorithms
+const (
+	AlgorithmHS256 = "HS256"
+	AlgorithmRS256 = "RS256"
+)
+
+// Keys signs and verifies JWTs with a single configured algorithm
+// Tokens using any other algorithm are rejected, so an HS256 token signed with
+// the distributed RS256 public key as its secret is never accepted
+type Keys struct {
+	method    jwt.SigningMethod
+	signKey   interface{}
+	verifyKey interface{}
+}
+
+// NewHS256Keys creates keys that sign and verify with a shared secret
+func NewHS256Keys(secret string) *Keys {
+	return &Keys{
+		method:    jwt.SigningMethodHS256,
+		signKey:   []byte(secret),
+		verifyKey: []byte(secret),
+	}
+}
+
+// NewRS256Keys creates keys that sign with an RSA private key and verify with the matching public key
+func NewRS256Keys(privateKey *rsa.PrivateKey, publicKey *rsa.PublicKey) *Keys {
+	return &Keys{
+		method:    jwt.SigningMethodRS256,
+		signKey:   privateKey,
+		verifyKey: publicKey,
+	}
+}
+
+// Load creates keys for the configured algorithm
+// HS256 uses the secret; RS256 reads PEM encoded RSA keys from the given paths
+func Load(algorithm, secret, privateKeyPath, publicKeyPath string) (*Keys, error) {
+	switch algorithm {
+	case AlgorithmHS256:
+		if secret == "" {
+			return nil, fmt.Errorf("JWT secret is required for %s", AlgorithmHS256)
+		}
+		return NewHS256Keys(secret), nil
+	case AlgorithmRS256:
+		privatePEM, err := os.ReadFile(privateKeyPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read JWT private key: %w", err)
+		}
+		privateKey, err := jwt.ParseRSAPrivateKeyFromPEM(privatePEM)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse JWT private key: %w", err)
+		}
+
+		publicPEM, err := os.ReadFile(publicKeyPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read JWT public key: %w", err)
+		}
+		publicKey, err := jwt.ParseRSAPublicKeyFromPEM(publicPEM)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse JWT public key: %w", err)
+		}
+
+		if !privateKey.PublicKey.Equal(publicKey) {
+			return nil, fmt.Errorf("JWT public key does not match the private key")
+		}
+		return NewRS256Keys(privateKey, publicKey), nil
+	default:
+		return nil, fmt.Errorf("unsupported JWT algorithm: %s", algorithm)
+	}
+}
+
+// Algorithm returns the name of the signing algorithm
+func (k *Keys) Algorithm() string {
+	return k.method.Alg()
+}
+
+// Sign creates a signed token for the claims
+func (k *Keys) Sign(claims jwt.Claims) (string, error) {
+	return jwt.NewWithClaims(k.method, claims).SignedString(k.signKey)
+}
+
+// Parse parses a token and verifies its signature and expiry
+// The token's alg header selects the verification key and must match the configured algorithm
+func (k *Keys) Parse(tokenString string) (*jwt.Token, error) {
+	return jwt.Parse(tokenString, k.verificationKey, jwt.WithValidMethods([]string{k.method.Alg()}))
+}
+
+// verificationKey returns the key for the token's signing method, rejecting methods of another family
+func (k *Keys) verificationKey(token *jwt.Token) (interface{}, error) {
+	switch token.Method.(type) {
+	case *jwt.SigningMethodHMAC:
+		if _, ok := k.method.(*jwt.SigningMethodHMAC); ok {
+			return k.verifyKey, nil
+		}
+	case *jwt.SigningMethodRSA:
+		if _, ok := k.method.(*jwt.SigningMethodRSA); ok {
+			return k.verifyKey, nil
+		}
+	}
+	return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+}
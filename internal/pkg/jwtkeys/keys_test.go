@@ -0,0 +1,162 @@
+package jwtkeys
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// writeRSAKeyPair generates an RSA key pair and writes it as PEM files, returning their paths
+func writeRSAKeyPair(t *testing.T, dir, name string) (string, string, []byte) {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Failed to generate RSA key: %v", err)
+	}
+	publicDER, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatalf("Failed to marshal public key: %v", err)
+	}
+
+	privatePEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	publicPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER})
+
+	privatePath := filepath.Join(dir, name+".pem")
+	publicPath := filepath.Join(dir, name+".pub.pem")
+	if err := os.WriteFile(privatePath, privatePEM, 0o600); err != nil {
+		t.Fatalf("Failed to write private key: %v", err)
+	}
+	if err := os.WriteFile(publicPath, publicPEM, 0o644); err != nil {
+		t.Fatalf("Failed to write public key: %v", err)
+	}
+	return privatePath, publicPath, publicPEM
+}
+
+func testClaims() jwt.MapClaims {
+	return jwt.MapClaims{
+		"user_id": "user-1",
+		"type":    "access",
+		"exp":     time.Now().Add(time.Hour).Unix(),
+	}
+}
+
+func TestKeys_RS256SignAndVerify(t *testing.T) {
+	privatePath, publicPath, _ := writeRSAKeyPair(t, t.TempDir(), "jwt")
+	keys, err := Load(AlgorithmRS256, "", privatePath, publicPath)
+	if err != nil {
+		t.Fatalf("Expected keys to load, got: %v", err)
+	}
+
+	signed, err := keys.Sign(testClaims())
+	if err != nil {
+		t.Fatalf("Expected token to be signed, got: %v", err)
+	}
+
+	token, err := keys.Parse(signed)
+	if err != nil || !token.Valid {
+		t.Fatalf("Expected token to verify, got: %v", err)
+	}
+	if token.Method.Alg() != AlgorithmRS256 {
+		t.Errorf("Expected RS256 token, got: %s", token.Method.Alg())
+	}
+	if claims := token.Claims.(jwt.MapClaims); claims["user_id"] != "user-1" {
+		t.Errorf("Expected user_id user-1, got: %v", claims["user_id"])
+	}
+}
+
+func TestKeys_RS256RejectsTokenFromOtherKey(t *testing.T) {
+	dir := t.TempDir()
+	privatePath, publicPath, _ := writeRSAKeyPair(t, dir, "jwt")
+	otherPrivatePath, otherPublicPath, _ := writeRSAKeyPair(t, dir, "other")
+
+	keys, err := Load(AlgorithmRS256, "", privatePath, publicPath)
+	if err != nil {
+		t.Fatalf("Expected keys to load, got: %v", err)
+	}
+	otherKeys, err := Load(AlgorithmRS256, "", otherPrivatePath, otherPublicPath)
+	if err != nil {
+		t.Fatalf("Expected keys to load, got: %v", err)
+	}
+
+	signed, err := otherKeys.Sign(testClaims())
+	if err != nil {
+		t.Fatalf("Expected token to be signed, got: %v", err)
+	}
+	if _, err := keys.Parse(signed); err == nil {
+		t.Error("Expected token signed by another key to be rejected")
+	}
+}
+
+func TestKeys_RejectsAlgorithmConfusion(t *testing.T) {
+	privatePath, publicPath, publicPEM := writeRSAKeyPair(t, t.TempDir(), "jwt")
+	rsKeys, err := Load(AlgorithmRS256, "", privatePath, publicPath)
+	if err != nil {
+		t.Fatalf("Expected keys to load, got: %v", err)
+	}
+
+	// An attacker who has the distributed public key signs an HS256 token with it as the secret
+	forged, err := NewHS256Keys(string(publicPEM)).Sign(testClaims())
+	if err != nil {
+		t.Fatalf("Expected forged token to be signed, got: %v", err)
+	}
+	if _, err := rsKeys.Parse(forged); err == nil {
+		t.Error("Expected HS256 token to be rejected by RS256 keys")
+	}
+
+	// RS256 tokens are not accepted when HS256 is configured either
+	rsSigned, err := rsKeys.Sign(testClaims())
+	if err != nil {
+		t.Fatalf("Expected token to be signed, got: %v", err)
+	}
+	if _, err := NewHS256Keys("secret").Parse(rsSigned); err == nil {
+		t.Error("Expected RS256 token to be rejected by HS256 keys")
+	}
+
+	// Unsigned tokens are always rejected
+	unsigned, err := jwt.NewWithClaims(jwt.SigningMethodNone, testClaims()).SignedString(jwt.UnsafeAllowNoneSignatureType)
+	if err != nil {
+		t.Fatalf("Expected unsigned token to be built, got: %v", err)
+	}
+	if _, err := rsKeys.Parse(unsigned); err == nil {
+		t.Error("Expected alg none token to be rejected")
+	}
+}
+
+func TestKeys_HS256SignAndVerify(t *testing.T) {
+	keys, err := Load(AlgorithmHS256, "secret", "", "")
+	if err != nil {
+		t.Fatalf("Expected keys to load, got: %v", err)
+	}
+
+	signed, err := keys.Sign(testClaims())
+	if err != nil {
+		t.Fatalf("Expected token to be signed, got: %v", err)
+	}
+	if _, err := keys.Parse(signed); err != nil {
+		t.Errorf("Expected token to verify, got: %v", err)
+	}
+	if _, err := NewHS256Keys("other-secret").Parse(signed); err == nil {
+		t.Error("Expected token to be rejected with a different secret")
+	}
+}
+
+func TestLoad_RejectsMismatchedKeyPair(t *testing.T) {
+	dir := t.TempDir()
+	privatePath, _, _ := writeRSAKeyPair(t, dir, "jwt")
+	_, otherPublicPath, _ := writeRSAKeyPair(t, dir, "other")
+
+	if _, err := Load(AlgorithmRS256, "", privatePath, otherPublicPath); err == nil {
+		t.Error("Expected mismatched key pair to be rejected")
+	}
+	if _, err := Load("HS512", "secret", "", ""); err == nil {
+		t.Error("Expected unsupported algorithm to be rejected")
+	}
+}
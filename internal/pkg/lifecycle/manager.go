@@ -0,0 +1,109 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"nutrient_be/internal/pkg/logger"
+)
+
+// Closer releases a resource during shutdown, giving up when ctx is done
+type Closer func(ctx context.Context) error
+
+// namedCloser is a registered closer and the name it is logged under
+type namedCloser struct {
+	name  string
+	close Closer
+}
+
+// Manager shuts down the application's resources in a fixed order
+// Closers run in reverse order of registration, so resources a later component depends on
+// (such as the database behind the HTTP server) should be registered first
+type Manager struct {
+	mu      sync.Mutex
+	closers []namedCloser
+	logger  logger.Logger
+}
+
+// NewManager creates a lifecycle manager with no registered closers
+func NewManager(log logger.Logger) *Manager {
+	return &Manager{logger: log}
+}
+
+// Register adds a closer that runs before every closer registered earlier
+func (m *Manager) Register(name string, closer Closer) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.closers = append(m.closers, namedCloser{name: name, close: closer})
+}
+
+// WaitForSignal blocks until SIGINT or SIGTERM and then shuts down within timeout
+func (m *Manager) WaitForSignal(timeout time.Duration) error {
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(quit)
+
+	sig := <-quit
+	m.logger.Info(context.Background(), "Shutdown signal received", logger.String("signal", sig.String()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return m.Shutdown(ctx)
+}
+
+// Shutdown runs every closer in reverse order of registration, sharing the deadline of ctx
+// A closer still running when ctx is done is logged and abandoned; the remaining closers are
+// still started so they can release what they can. The errors of all closers are joined.
+func (m *Manager) Shutdown(ctx context.Context) error {
+	m.mu.Lock()
+	closers := m.closers
+	m.closers = nil
+	m.mu.Unlock()
+
+	var errs []error
+	for i := len(closers) - 1; i >= 0; i-- {
+		closer := closers[i]
+		started := time.Now()
+
+		finished, err := m.run(ctx, closer)
+		switch {
+		case !finished:
+			m.logger.Error(ctx, "Resource exceeded the shutdown timeout", logger.String("name", closer.name))
+			errs = append(errs, fmt.Errorf("%s: %w", closer.name, ctx.Err()))
+		case err != nil:
+			m.logger.Error(ctx, "Failed to close resource", logger.String("name", closer.name), logger.Error(err))
+			errs = append(errs, fmt.Errorf("%s: %w", closer.name, err))
+		default:
+			m.logger.Info(ctx, "Resource closed",
+				logger.String("name", closer.name),
+				logger.Int64("duration_ms", time.Since(started).Milliseconds()))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// run invokes closer and waits for it until ctx is done
+// finished is false when the closer was still running at the deadline
+func (m *Manager) run(ctx context.Context, closer namedCloser) (finished bool, err error) {
+	done := make(chan error, 1)
+	go func() { done <- closer.close(ctx) }()
+
+	select {
+	case err := <-done:
+		return true, err
+	case <-ctx.Done():
+		// A closer that returned right as the deadline passed still counts as finished
+		select {
+		case err := <-done:
+			return true, err
+		default:
+			return false, nil
+		}
+	}
+}
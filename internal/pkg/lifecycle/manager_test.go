@@ -0,0 +1,63 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"nutrient_be/internal/pkg/logger"
+)
+
+func TestManager_ShutdownClosesInReverseOrder(t *testing.T) {
+	m := NewManager(logger.NewNoopLogger())
+
+	var closed []string
+	for _, name := range []string{"mongodb", "event bus", "http server"} {
+		name := name
+		m.Register(name, func(ctx context.Context) error {
+			closed = append(closed, name)
+			return nil
+		})
+	}
+
+	if err := m.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	expected := []string{"http server", "event bus", "mongodb"}
+	if !reflect.DeepEqual(closed, expected) {
+		t.Errorf("Expected closers to run in order %v, got: %v", expected, closed)
+	}
+}
+
+func TestManager_ShutdownContinuesAfterFailureAndTimeout(t *testing.T) {
+	m := NewManager(logger.NewNoopLogger())
+
+	lastStarted := make(chan struct{})
+	m.Register("mongodb", func(ctx context.Context) error {
+		close(lastStarted)
+		return nil
+	})
+	m.Register("consumer", func(ctx context.Context) error {
+		<-ctx.Done()
+		time.Sleep(10 * time.Millisecond)
+		return nil
+	})
+	m.Register("http server", func(ctx context.Context) error {
+		return errors.New("listener already closed")
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err := m.Shutdown(ctx)
+
+	if err == nil || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the slow closer to exceed the deadline, got: %v", err)
+	}
+	select {
+	case <-lastStarted:
+	case <-time.After(time.Second):
+		t.Error("Expected closers after a failed and a timed out one to still run")
+	}
+}
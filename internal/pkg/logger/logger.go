@@ -25,6 +25,12 @@ type Logger interface {
 	With(fields ...Field) Logger
 }
 
+// LevelController is implemented by loggers whose level can be changed at runtime
+type LevelController interface {
+	Level() string
+	SetLevel(level string) error
+}
+
 // Field represents a key-value pair for structured logging
 type Field struct {
 	Key   string
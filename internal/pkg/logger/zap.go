@@ -23,11 +23,13 @@ const (
 // zapLogger implements the Logger interface using Zap
 type zapLogger struct {
 	logger *zap.Logger
+	level  zap.AtomicLevel // Shared with loggers derived through With
 }
 
 // NewZapLogger creates a new Zap logger instance
 func NewZapLogger(isDevelopment bool) (Logger, error) {
 	var zapLog *zap.Logger
+	var level zap.AtomicLevel
 	var err error
 
 	if isDevelopment {
@@ -39,6 +41,7 @@ func NewZapLogger(isDevelopment bool) (Logger, error) {
 		// Only show stack trace for Panic and Fatal levels, not for Error
 		// This prevents verbose stack traces in normal error logging
 		// Note: AddStacktrace with PanicLevel means stack trace only appears for Panic/Fatal
+		level = config.Level
 		zapLog, err = config.Build(
 			zap.AddStacktrace(zapcore.PanicLevel), // Only add stack trace for Panic and above
 			zap.AddCallerSkip(1),                  // Include caller information (file:line)
@@ -47,6 +50,7 @@ func NewZapLogger(isDevelopment bool) (Logger, error) {
 		config := zap.NewProductionConfig()
 		config.EncoderConfig.TimeKey = "timestamp"
 		config.EncoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+		level = config.Level
 		// Only show stack trace for Panic and Fatal in production
 		zapLog, err = config.Build(zap.AddStacktrace(zapcore.PanicLevel))
 	}
@@ -55,7 +59,7 @@ func NewZapLogger(isDevelopment bool) (Logger, error) {
 		return nil, err
 	}
 
-	return &zapLogger{logger: zapLog}, nil
+	return &zapLogger{logger: zapLog, level: level}, nil
 }
 
 // Context-aware logging methods
@@ -204,9 +208,25 @@ func (l *zapLogger) PanicfLegacy(format string, args ...interface{}) {
 func (l *zapLogger) With(fields ...Field) Logger {
 	return &zapLogger{
 		logger: l.logger.With(l.toZapFields(fields)...),
+		level:  l.level,
 	}
 }
 
+// Level returns the current minimum enabled level
+func (l *zapLogger) Level() string {
+	return l.level.Level().String()
+}
+
+// SetLevel changes the minimum enabled level at runtime, including for loggers derived through With
+func (l *zapLogger) SetLevel(level string) error {
+	parsed, err := zapcore.ParseLevel(level)
+	if err != nil {
+		return fmt.Errorf("invalid log level %q: %w", level, err)
+	}
+	l.level.SetLevel(parsed)
+	return nil
+}
+
 // buildContextFields builds fields from context and additional fields
 func (l *zapLogger) buildContextFields(ctx context.Context, additionalFields []Field) []zap.Field {
 	fields := make([]zap.Field, 0, len(additionalFields)+10) // Pre-allocate with extra capacity
@@ -0,0 +1,50 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLocalStorage_SaveAndDelete(t *testing.T) {
+	dir := t.TempDir()
+	s := NewLocalStorage(dir)
+	ctx := context.Background()
+
+	if err := s.Save(ctx, "foods/abc.png", []byte("data"), "image/png"); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, "foods", "abc.png"))
+	if err != nil || string(content) != "data" {
+		t.Fatalf("Expected stored file with content 'data', got: %q, %v", content, err)
+	}
+	if loaded, err := s.Load(ctx, "foods/abc.png"); err != nil || string(loaded) != "data" {
+		t.Fatalf("Expected to load content 'data', got: %q, %v", loaded, err)
+	}
+
+	if err := s.Delete(ctx, "foods/abc.png"); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "foods", "abc.png")); !os.IsNotExist(err) {
+		t.Errorf("Expected file to be deleted, got: %v", err)
+	}
+	if err := s.Delete(ctx, "foods/abc.png"); err != nil {
+		t.Errorf("Expected deleting a missing file to succeed, got: %v", err)
+	}
+	if _, err := s.Load(ctx, "foods/abc.png"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for a deleted file, got: %v", err)
+	}
+}
+
+func TestLocalStorage_RejectsKeysOutsideDir(t *testing.T) {
+	s := NewLocalStorage(t.TempDir())
+
+	for _, key := range []string{"", "../escape.png", "foods/../../escape.png"} {
+		if err := s.Save(context.Background(), key, []byte("data"), "image/png"); err == nil {
+			t.Errorf("Expected error for key %q", key)
+		}
+	}
+}
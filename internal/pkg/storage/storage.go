@@ -0,0 +1,21 @@
+package storage
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrNotFound is returned when no file is stored under a key
+var ErrNotFound = errors.New("file not found")
+
+// Storage interface defines the contract for storing uploaded files
+// The local filesystem implementation can be swapped for an S3-compatible store
+// without changing callers. Files are not served directly; handlers load them after checking access
+type Storage interface {
+	// Save stores data under key, replacing any previous file
+	Save(ctx context.Context, key string, data []byte, contentType string) error
+	// Load returns the file stored under key, or ErrNotFound
+	Load(ctx context.Context, key string) ([]byte, error)
+	// Delete removes the file stored under key; deleting a missing file is not an error
+	Delete(ctx context.Context, key string) error
+}
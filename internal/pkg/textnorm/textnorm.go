@@ -0,0 +1,55 @@
+// Package textnorm normalizes text for accent-insensitive search
+package textnorm
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// RemoveDiacritics strips accents and tone marks, e.g. "Chả" becomes "Cha"
+// Text is decomposed (NFD) and the combining marks are dropped. "đ" has no
+// decomposition so it is mapped to "d" explicitly.
+func RemoveDiacritics(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+	for _, r := range norm.NFD.String(s) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+			continue
+		case r == 'đ':
+			b.WriteRune('d')
+		case r == 'Đ':
+			b.WriteRune('D')
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return norm.NFC.String(b.String())
+}
+
+// Normalize prepares text for search matching: trimmed, lower case and without diacritics
+func Normalize(s string) string {
+	return RemoveDiacritics(strings.ToLower(strings.TrimSpace(s)))
+}
+
+// Matches reports whether query occurs in text, ignoring case and diacritics
+func Matches(text, query string) bool {
+	return strings.Contains(Normalize(text), Normalize(query))
+}
+
+// SearchTerms normalizes terms and removes empty and duplicate entries, keeping the first occurrence
+func SearchTerms(terms ...string) []string {
+	seen := make(map[string]bool, len(terms))
+	normalized := make([]string, 0, len(terms))
+	for _, term := range terms {
+		term = Normalize(term)
+		if term == "" || seen[term] {
+			continue
+		}
+		seen[term] = true
+		normalized = append(normalized, term)
+	}
+	return normalized
+}
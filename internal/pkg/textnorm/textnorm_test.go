@@ -0,0 +1,57 @@
+package textnorm
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"Táo", "tao"},
+		{"Chả", "cha"},
+		{"  Phở Bò  ", "pho bo"},
+		{"Đậu phụ", "dau phu"},
+		{"Chicken breast", "chicken breast"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := Normalize(tt.input); got != tt.expected {
+				t.Errorf("Expected %q, got: %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestMatches(t *testing.T) {
+	tests := []struct {
+		text     string
+		query    string
+		expected bool
+	}{
+		{"Táo", "tao", true},
+		{"Chả giò", "cha", true},
+		{"Táo", "táo", true},
+		{"tao", "Táo", true},
+		{"Cà chua", "tao", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text+"/"+tt.query, func(t *testing.T) {
+			if got := Matches(tt.text, tt.query); got != tt.expected {
+				t.Errorf("Expected Matches(%q, %q) to be %v, got: %v", tt.text, tt.query, tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestSearchTerms(t *testing.T) {
+	got := SearchTerms("Táo", "apple", "tao", "", "Apple")
+	expected := []string{"tao", "apple"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %v, got: %v", expected, got)
+	}
+}
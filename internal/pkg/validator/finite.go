@@ -0,0 +1,23 @@
+package validator
+
+import (
+	"reflect"
+
+	playground "github.com/go-playground/validator/v10"
+
+	"nutrient_be/internal/pkg/calculator"
+)
+
+// RegisterFiniteTag adds the finite struct tag, which rejects NaN and ±Inf in float fields
+// NaN compares false against every bound, so min and max alone let it through
+func RegisterFiniteTag(v *playground.Validate) error {
+	return v.RegisterValidation("finite", func(fl playground.FieldLevel) bool {
+		field := fl.Field()
+		switch field.Kind() {
+		case reflect.Float32, reflect.Float64:
+			return calculator.IsFinite(field.Float())
+		default:
+			return true
+		}
+	})
+}
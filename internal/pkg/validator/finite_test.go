@@ -0,0 +1,40 @@
+package validator
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	playground "github.com/go-playground/validator/v10"
+
+	"nutrient_be/internal/dto/request"
+)
+
+func TestFiniteTag_RejectsNonFiniteNutrients(t *testing.T) {
+	v := playground.New()
+	if err := RegisterFiniteTag(v); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		macros request.MacroNutrientsRequest
+		valid  bool
+	}{
+		{"finite", request.MacroNutrientsRequest{Protein: 31, Fat: 3.6}, true},
+		{"NaN protein", request.MacroNutrientsRequest{Protein: math.NaN()}, false},
+		{"infinite fat", request.MacroNutrientsRequest{Fat: math.Inf(1)}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := v.Struct(&request.EstimateCaloriesRequest{Macros: tt.macros})
+			if tt.valid && err != nil {
+				t.Errorf("Expected no error, got: %v", err)
+			}
+			if !tt.valid && (err == nil || !strings.Contains(err.Error(), "finite")) {
+				t.Errorf("Expected a finite validation error, got: %v", err)
+			}
+		})
+	}
+}
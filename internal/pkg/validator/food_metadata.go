@@ -0,0 +1,144 @@
+package validator
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+
+	playground "github.com/go-playground/validator/v10"
+
+	"nutrient_be/internal/pkg/calculator"
+)
+
+// Serving unit kinds describe what a unit measures
+const (
+	UnitKindMass   = "mass"
+	UnitKindVolume = "volume"
+	UnitKindCount  = "count"
+)
+
+// ServingUnit is a unit food servings can be expressed in
+type ServingUnit struct {
+	Name string
+	Kind string // mass, volume or count
+}
+
+// servingUnits is the registry of supported serving units, in display order
+var servingUnits = []ServingUnit{
+	{Name: "gram", Kind: UnitKindMass},
+	{Name: "kg", Kind: UnitKindMass},
+	{Name: "ml", Kind: UnitKindVolume},
+	{Name: "cup", Kind: UnitKindVolume},
+	{Name: "piece", Kind: UnitKindCount},
+	{Name: "box", Kind: UnitKindCount},
+}
+
+// defaultFoodCategories are the food categories supported without configuration
+var defaultFoodCategories = []string{"protein", "vegetable", "fruit", "dairy", "grain"}
+
+// foodCategoryNamePattern restricts registered food categories to lowercase identifiers such as "beverage"
+var foodCategoryNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// foodCategories is the registry of supported food categories shared by the food_category tag and the metadata endpoint
+var foodCategories = newFoodCategoryRegistry(defaultFoodCategories)
+
+// foodCategoryRegistry is an ordered, concurrency-safe set of food categories
+type foodCategoryRegistry struct {
+	mu         sync.RWMutex
+	categories []string
+	index      map[string]bool
+}
+
+func newFoodCategoryRegistry(categories []string) *foodCategoryRegistry {
+	r := &foodCategoryRegistry{index: make(map[string]bool)}
+	for _, category := range categories {
+		r.categories = append(r.categories, category)
+		r.index[category] = true
+	}
+	return r
+}
+
+// ServingUnits returns the supported serving units
+func ServingUnits() []ServingUnit {
+	return append([]ServingUnit(nil), servingUnits...)
+}
+
+// IsValidServingUnit reports whether a serving unit is supported
+func IsValidServingUnit(unit string) bool {
+	for _, servingUnit := range servingUnits {
+		if servingUnit.Name == unit {
+			return true
+		}
+	}
+	return false
+}
+
+// SetUnitConversions installs the conversions used for units a food does not define
+// A registered serving unit may only be listed under its own kind, so count units such as "piece" are never converted
+func SetUnitConversions(conversions calculator.UnitConversions) error {
+	tables := []struct {
+		kind  string
+		units map[string]float64
+	}{
+		{UnitKindMass, conversions.Mass},
+		{UnitKindVolume, conversions.Volume},
+	}
+	for _, table := range tables {
+		for unit := range table.units {
+			for _, servingUnit := range servingUnits {
+				if servingUnit.Name == unit && servingUnit.Kind != table.kind {
+					return fmt.Errorf("invalid unit conversion for '%s': a %s unit cannot be converted as %s", unit, servingUnit.Kind, table.kind)
+				}
+			}
+		}
+	}
+	return calculator.SetUnitConversions(conversions)
+}
+
+// servingUnitList formats the supported serving units for error messages
+func servingUnitList() string {
+	names := make([]string, len(servingUnits))
+	for i, servingUnit := range servingUnits {
+		names[i] = servingUnit.Name
+	}
+	return strings.Join(names, ", ")
+}
+
+// RegisterFoodCategory adds a supported food category, e.g. "legume" or "beverage"
+// Registering an existing category is a no-op
+func RegisterFoodCategory(category string) error {
+	if !foodCategoryNamePattern.MatchString(category) {
+		return fmt.Errorf("invalid food category name '%s': must be lowercase letters, digits and underscores", category)
+	}
+
+	foodCategories.mu.Lock()
+	defer foodCategories.mu.Unlock()
+
+	if !foodCategories.index[category] {
+		foodCategories.categories = append(foodCategories.categories, category)
+		foodCategories.index[category] = true
+	}
+	return nil
+}
+
+// FoodCategories returns the supported food categories, defaults first, then in registration order
+func FoodCategories() []string {
+	foodCategories.mu.RLock()
+	defer foodCategories.mu.RUnlock()
+	return append([]string(nil), foodCategories.categories...)
+}
+
+// RegisterFoodCategoryTag adds the food_category struct tag, which checks a value against the registered food categories
+func RegisterFoodCategoryTag(v *playground.Validate) error {
+	return v.RegisterValidation("food_category", func(fl playground.FieldLevel) bool {
+		return IsValidFoodCategory(fl.Field().String())
+	})
+}
+
+// IsValidFoodCategory reports whether a food category is registered
+func IsValidFoodCategory(category string) bool {
+	foodCategories.mu.RLock()
+	defer foodCategories.mu.RUnlock()
+	return foodCategories.index[category]
+}
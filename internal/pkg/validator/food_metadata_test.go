@@ -0,0 +1,97 @@
+package validator
+
+import (
+	"testing"
+
+	playground "github.com/go-playground/validator/v10"
+
+	"nutrient_be/internal/dto/request"
+	"nutrient_be/internal/pkg/calculator"
+)
+
+// resetFoodCategories restores the default food categories after a test registers its own
+func resetFoodCategories() {
+	foodCategories.mu.Lock()
+	defer foodCategories.mu.Unlock()
+	fresh := newFoodCategoryRegistry(defaultFoodCategories)
+	foodCategories.categories, foodCategories.index = fresh.categories, fresh.index
+}
+
+func newCategoryTestRequest(category string) *request.CreateFoodRequest {
+	return &request.CreateFoodRequest{
+		Name:         request.MultiLanguage{"en": "Orange juice"},
+		Category:     category,
+		ServingSizes: []request.ServingSizeRequest{{Unit: "ml", Amount: 100, GramEquivalent: 104}},
+		Calories:     float64Ptr(45),
+		Visibility:   "public",
+	}
+}
+
+func TestFoodCategoryTag_RegisteredCategory(t *testing.T) {
+	defer resetFoodCategories()
+	v := playground.New()
+	if err := RegisterFoodCategoryTag(v); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if err := RegisterFiniteTag(v); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if err := v.Struct(newCategoryTestRequest("beverage")); err == nil {
+		t.Fatal("Expected beverage to be rejected before registration")
+	}
+
+	if err := RegisterFoodCategory("beverage"); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if err := v.Struct(newCategoryTestRequest("beverage")); err != nil {
+		t.Errorf("Expected beverage food to be valid, got: %v", err)
+	}
+
+	// Defaults stay supported and keep their order
+	categories := FoodCategories()
+	expected := []string{"protein", "vegetable", "fruit", "dairy", "grain", "beverage"}
+	if len(categories) != len(expected) {
+		t.Fatalf("Expected %v, got: %v", expected, categories)
+	}
+	for i := range expected {
+		if categories[i] != expected[i] {
+			t.Errorf("Expected %v, got: %v", expected, categories)
+			break
+		}
+	}
+}
+
+func TestRegisterFoodCategory_InvalidName(t *testing.T) {
+	defer resetFoodCategories()
+
+	for _, name := range []string{"", "Beverage", "soft-drink", "hot drink"} {
+		if err := RegisterFoodCategory(name); err == nil {
+			t.Errorf("Expected error for food category %q", name)
+		}
+	}
+}
+
+func TestSetUnitConversions_KeepsUnitsInTheirKind(t *testing.T) {
+	defer func() {
+		if err := SetUnitConversions(calculator.DefaultUnitConversions()); err != nil {
+			t.Fatalf("Expected the defaults to be accepted, got: %v", err)
+		}
+	}()
+
+	invalid := map[string]calculator.UnitConversions{
+		"count unit as mass":   {Mass: map[string]float64{"gram": 1, "piece": 50}},
+		"count unit as volume": {Volume: map[string]float64{"ml": 1, "box": 500}},
+		"volume unit as mass":  {Mass: map[string]float64{"gram": 1, "ml": 1}},
+	}
+	for name, conversions := range invalid {
+		if err := SetUnitConversions(conversions); err == nil {
+			t.Errorf("%s: expected an error, got: nil", name)
+		}
+	}
+
+	// Units outside the registry, such as oz, may be listed under either kind
+	if err := SetUnitConversions(calculator.UnitConversions{Mass: map[string]float64{"gram": 1, "oz": 28.35}}); err != nil {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+}
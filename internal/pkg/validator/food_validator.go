@@ -2,11 +2,15 @@ package validator
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math"
 	"net/url"
 	"strings"
 
+	"nutrient_be/internal/domain"
 	"nutrient_be/internal/dto/request"
+	"nutrient_be/internal/pkg/calculator"
 	"nutrient_be/internal/pkg/logger"
 )
 
@@ -19,6 +23,10 @@ type FoodValidator struct {
 	maxCalories          float64
 	maxMacroValue        float64
 	caloriesTolerance    float64
+	servingTolerance     float64  // Grams two serving amounts may differ by and still be considered equal
+	requireGramBase      bool     // Reject foods without a 100g serving instead of only warning
+	maxServingCalories   float64  // Calories above which a non-gram serving is logged as suspicious; 0 disables the check
+	allowedImageHosts    []string // Hosts image URLs may point at, subdomains included; empty allows any host
 }
 
 // NewFoodValidator creates a new food validator with default rules
@@ -29,15 +37,99 @@ func NewFoodValidator(logger logger.Logger) *FoodValidator {
 		maxDescriptionLength: 1000,
 		maxCalories:          1000,
 		maxMacroValue:        100,
-		caloriesTolerance:    10, // Allow ±10 calories difference
+		caloriesTolerance:    10,    // Allow ±10 calories difference
+		servingTolerance:     0.001, // Absorbs floating-point noise such as 100 vs 100.0000001
 	}
 }
 
+// SetServingTolerance sets how far serving amounts and gram equivalents may differ and still be considered equal
+// Negative values are ignored
+func (v *FoodValidator) SetServingTolerance(epsilon float64) {
+	if epsilon >= 0 {
+		v.servingTolerance = epsilon
+	}
+}
+
+// CaloriesTolerance returns how far stored calories may differ from the macro-derived estimate
+func (v *FoodValidator) CaloriesTolerance() float64 {
+	return v.caloriesTolerance
+}
+
+// SetMaxServingCalories sets the calories above which a single non-gram serving is logged as suspicious
+// 0 disables the check and negative values are ignored
+func (v *FoodValidator) SetMaxServingCalories(calories float64) {
+	if calories >= 0 {
+		v.maxServingCalories = calories
+	}
+}
+
+// SetRequireGramBase sets whether a 100g gram serving is mandatory
+// Nutrients are stored per 100g, so without it calculations rely on the other servings' gram equivalents
+func (v *FoodValidator) SetRequireGramBase(required bool) {
+	v.requireGramBase = required
+}
+
+// SetAllowedImageHosts restricts image URLs to the given hosts and their subdomains
+// An empty list allows any host
+func (v *FoodValidator) SetAllowedImageHosts(hosts []string) {
+	v.allowedImageHosts = make([]string, 0, len(hosts))
+	for _, host := range hosts {
+		if host = strings.ToLower(strings.TrimSpace(host)); host != "" {
+			v.allowedImageHosts = append(v.allowedImageHosts, host)
+		}
+	}
+}
+
+// imageHostAllowed reports whether an image URL host is on the allowlist
+func (v *FoodValidator) imageHostAllowed(host string) bool {
+	if len(v.allowedImageHosts) == 0 {
+		return true
+	}
+	host = strings.ToLower(host)
+	for _, allowed := range v.allowedImageHosts {
+		if host == allowed || strings.HasSuffix(host, "."+allowed) {
+			return true
+		}
+	}
+	return false
+}
+
+// servingAmountsEqual reports whether two gram amounts are equal within the serving tolerance
+func (v *FoodValidator) servingAmountsEqual(a, b float64) bool {
+	return math.Abs(a-b) <= v.servingTolerance
+}
+
+// Rules a food request can fail, reported as FoodValidationError.Rule
+const (
+	FoodRuleName                = "name"
+	FoodRuleDescription         = "description"
+	FoodRuleNutrition           = "nutrition"
+	FoodRuleServingSizes        = "serving_sizes"
+	FoodRuleCaloriesConsistency = "calories_consistency"
+	FoodRuleImageURL            = "image_url"
+)
+
+// Warnings a valid food request can get; they are returned alongside the saved food
+const (
+	FoodWarningNoGramBase      = "no_gram_base"
+	FoodWarningServingCalories = "implausible_serving_calories"
+)
+
+// FoodValidationError is a food request failing one validation rule
+type FoodValidationError struct {
+	Rule string
+	Err  error
+}
+
+func (e *FoodValidationError) Error() string { return e.Err.Error() }
+func (e *FoodValidationError) Unwrap() error { return e.Err }
+
 // ValidateCreateRequest validates a CreateFoodRequest
+// The error for a failed rule is a *FoodValidationError
 func (v *FoodValidator) ValidateCreateRequest(ctx context.Context, req *request.CreateFoodRequest) error {
 	// 1. Validate Name
 	if err := v.validateName(req.Name); err != nil {
-		return fmt.Errorf("name validation failed: %w", err)
+		return &FoodValidationError{Rule: FoodRuleName, Err: fmt.Errorf("name validation failed: %w", err)}
 	}
 
 	// 2. Validate Description (optional field)
@@ -45,36 +137,105 @@ func (v *FoodValidator) ValidateCreateRequest(ctx context.Context, req *request.
 		descRaw := req.Description.GetRaw()
 		if len(descRaw) > 0 {
 			if err := v.validateDescription(req.Description); err != nil {
-				return fmt.Errorf("description validation failed: %w", err)
+				return &FoodValidationError{Rule: FoodRuleDescription, Err: fmt.Errorf("description validation failed: %w", err)}
 			}
 		}
 	}
 
 	// 3. Validate Nutrition Values
 	if err := v.validateNutrition(req); err != nil {
-		return fmt.Errorf("nutrition validation failed: %w", err)
+		return &FoodValidationError{Rule: FoodRuleNutrition, Err: fmt.Errorf("nutrition validation failed: %w", err)}
 	}
 
 	// 4. Validate Serving Sizes
 	if err := v.validateServingSizes(ctx, req.ServingSizes); err != nil {
-		return fmt.Errorf("serving sizes validation failed: %w", err)
+		return &FoodValidationError{Rule: FoodRuleServingSizes, Err: fmt.Errorf("serving sizes validation failed: %w", err)}
 	}
 
 	// 5. Validate Calories Consistency
 	if err := v.validateCaloriesConsistency(req); err != nil {
-		return fmt.Errorf("calories consistency validation failed: %w", err)
+		return &FoodValidationError{Rule: FoodRuleCaloriesConsistency, Err: fmt.Errorf("calories consistency validation failed: %w", err)}
 	}
 
-	// 6. Validate Image URL (optional)
+	// 6. Sanity-check the portion each serving implies (warning only)
+	v.checkServingCalories(ctx, req)
+
+	// 7. Validate Image URL (optional)
 	if req.ImageURL != "" {
 		if err := v.validateImageURL(req.ImageURL); err != nil {
-			return fmt.Errorf("image URL validation failed: %w", err)
+			return &FoodValidationError{Rule: FoodRuleImageURL, Err: fmt.Errorf("image URL validation failed: %w", err)}
 		}
 	}
 
 	return nil
 }
 
+// FoodValidationIssue is a failed rule or a warning in a food validation report
+type FoodValidationIssue struct {
+	Code    string
+	Message string
+}
+
+// FoodValidationReport is the outcome of validating a food request without saving it
+type FoodValidationReport struct {
+	Valid             bool
+	Errors            []FoodValidationIssue // The first failed rule; validation stops there like on creation
+	Warnings          []FoodValidationIssue
+	Calories          float64 // Calories stated in the request
+	ExpectedCalories  float64 // Calories implied by the macros
+	CaloriesDelta     float64 // Stated minus expected calories
+	CaloriesTolerance float64 // Largest delta accepted either way
+}
+
+// Report validates req with the same rules as ValidateCreateRequest and also returns its warnings,
+// along with how far the stated calories are from the macros
+func (v *FoodValidator) Report(ctx context.Context, req *request.CreateFoodRequest) *FoodValidationReport {
+	expected := EstimateCalories(req.Macros)
+	report := &FoodValidationReport{
+		Valid:             true,
+		Errors:            []FoodValidationIssue{},
+		Warnings:          []FoodValidationIssue{},
+		Calories:          req.CaloriesValue(),
+		ExpectedCalories:  expected,
+		CaloriesDelta:     req.CaloriesValue() - expected,
+		CaloriesTolerance: v.caloriesTolerance,
+	}
+
+	if err := v.ValidateCreateRequest(ctx, req); err != nil {
+		report.Valid = false
+		code := "invalid"
+		var ruleErr *FoodValidationError
+		if errors.As(err, &ruleErr) {
+			code = ruleErr.Rule
+		}
+		report.Errors = append(report.Errors, FoodValidationIssue{Code: code, Message: err.Error()})
+	}
+	report.Warnings = append(report.Warnings, v.Warnings(req)...)
+
+	return report
+}
+
+// Warnings returns what is suspicious about a food request without making it invalid:
+// a missing 100 gram base serving and servings implying implausible calories for one portion
+func (v *FoodValidator) Warnings(req *request.CreateFoodRequest) []FoodValidationIssue {
+	var warnings []FoodValidationIssue
+	if !v.requireGramBase && len(req.ServingSizes) > 0 && !v.hasGramBase(req.ServingSizes) {
+		warnings = append(warnings, FoodValidationIssue{
+			Code:    FoodWarningNoGramBase,
+			Message: "no 100 gram base serving size; nutrients per serving rely on the gram equivalents",
+		})
+	}
+	for _, i := range v.implausibleServings(req) {
+		size := req.ServingSizes[i]
+		warnings = append(warnings, FoodValidationIssue{
+			Code: FoodWarningServingCalories,
+			Message: fmt.Sprintf("serving size %d (%g %s) implies %.0f calories, more than %.0f for one portion",
+				i+1, size.Amount, size.Unit, req.CaloriesValue()*size.GramEquivalent/100, v.maxServingCalories),
+		})
+	}
+	return warnings
+}
+
 // validateName validates multi-language name
 func (v *FoodValidator) validateName(name request.MultiLanguage) error {
 	raw := name.GetRaw()
@@ -123,6 +284,11 @@ func (v *FoodValidator) validateDescription(desc request.MultiLanguage) error {
 func (v *FoodValidator) validateNutrition(req *request.CreateFoodRequest) error {
 	macros := req.Macros
 
+	// NaN passes every range check below, so non-finite values are rejected first
+	if err := validateFiniteNutrients(req); err != nil {
+		return err
+	}
+
 	// At least one macro must be > 0
 	if macros.Protein == 0 && macros.Carbohydrates == 0 && macros.Fat == 0 {
 		return fmt.Errorf("at least one macro nutrient (protein, carbs, or fat) must be greater than 0")
@@ -152,10 +318,10 @@ func (v *FoodValidator) validateNutrition(req *request.CreateFoodRequest) error
 	}
 
 	// Validate calories
-	if req.Calories < 0 {
+	if req.CaloriesValue() < 0 {
 		return fmt.Errorf("calories cannot be negative")
 	}
-	if req.Calories > v.maxCalories {
+	if req.CaloriesValue() > v.maxCalories {
 		return fmt.Errorf("calories exceed maximum (%.2f per 100g)", v.maxCalories)
 	}
 
@@ -183,27 +349,55 @@ func (v *FoodValidator) validateNutrition(req *request.CreateFoodRequest) error
 	return nil
 }
 
+// validateFiniteNutrients rejects NaN and ±Inf in calories, macros and micros
+func validateFiniteNutrients(req *request.CreateFoodRequest) error {
+	values := []struct {
+		name  string
+		value float64
+	}{
+		{"protein", req.Macros.Protein},
+		{"carbohydrates", req.Macros.Carbohydrates},
+		{"fat", req.Macros.Fat},
+		{"fiber", req.Macros.Fiber},
+		{"sugar", req.Macros.Sugar},
+		{"vitaminA", req.Micros.VitaminA},
+		{"vitaminC", req.Micros.VitaminC},
+		{"calcium", req.Micros.Calcium},
+		{"iron", req.Micros.Iron},
+		{"sodium", req.Micros.Sodium},
+		{"potassium", req.Micros.Potassium},
+		{"calories", req.CaloriesValue()},
+	}
+	for _, v := range values {
+		if !calculator.IsFinite(v.value) {
+			return fmt.Errorf("%s must be a finite number", v.name)
+		}
+	}
+	return nil
+}
+
 // validateServingSizes validates serving sizes
 func (v *FoodValidator) validateServingSizes(ctx context.Context, sizes []request.ServingSizeRequest) error {
 	if len(sizes) == 0 {
 		return fmt.Errorf("at least one serving size is required")
 	}
 
-	validUnits := map[string]bool{
-		"gram":  true,
-		"kg":    true,
-		"piece": true,
-		"cup":   true,
-		"ml":    true,
-		"box":   true,
-	}
-
-	hasGramBase := false
+	seenUnits := make(map[string]int, len(sizes))
 
 	for i, size := range sizes {
 		// Validate unit
-		if !validUnits[size.Unit] {
-			return fmt.Errorf("serving size %d: invalid unit '%s'. Valid units: gram, kg, piece, cup, ml, box", i+1, size.Unit)
+		if !IsValidServingUnit(size.Unit) {
+			return fmt.Errorf("serving size %d: invalid unit '%s'. Valid units: %s", i+1, size.Unit, servingUnitList())
+		}
+
+		// Nutrients are looked up by unit, so a second serving with the same unit would be ambiguous
+		if first, ok := seenUnits[size.Unit]; ok {
+			return fmt.Errorf("serving size %d: duplicate unit '%s' (already used by serving size %d)", i+1, size.Unit, first)
+		}
+		seenUnits[size.Unit] = i + 1
+
+		if !calculator.IsFinite(size.Amount, size.GramEquivalent) {
+			return fmt.Errorf("serving size %d: amount and gramEquivalent must be finite numbers", i+1)
 		}
 
 		// Validate amount
@@ -216,13 +410,8 @@ func (v *FoodValidator) validateServingSizes(ctx context.Context, sizes []reques
 			return fmt.Errorf("serving size %d: gramEquivalent must be greater than 0", i+1)
 		}
 
-		// Check for base gram serving (100g recommended)
-		if size.Unit == "gram" && size.Amount == 100 && size.GramEquivalent == 100 {
-			hasGramBase = true
-		}
-
 		// Validate consistency: for gram unit, amount should equal gramEquivalent
-		if size.Unit == "gram" && size.Amount != size.GramEquivalent {
+		if size.Unit == "gram" && !v.servingAmountsEqual(size.Amount, size.GramEquivalent) {
 			return fmt.Errorf("serving size %d: for gram unit, amount (%.2f) should equal gramEquivalent (%.2f)", i+1, size.Amount, size.GramEquivalent)
 		}
 
@@ -232,35 +421,87 @@ func (v *FoodValidator) validateServingSizes(ctx context.Context, sizes []reques
 		}
 	}
 
-	// Recommend having 100g base serving (warn but don't fail)
-	if !hasGramBase {
+	// A 100g base serving is recommended, or required when the validator is strict
+	if !v.hasGramBase(sizes) {
+		if v.requireGramBase {
+			return fmt.Errorf("a 100 gram base serving size is required (unit gram, amount 100, gramEquivalent 100)")
+		}
 		v.logger.Warn(ctx, "No gram base serving size found")
 	}
 
 	return nil
 }
 
+// hasGramBase reports whether the servings include the 100g base serving nutrients are stored for
+func (v *FoodValidator) hasGramBase(sizes []request.ServingSizeRequest) bool {
+	for _, size := range sizes {
+		if size.Unit == "gram" && v.servingAmountsEqual(size.Amount, 100) && v.servingAmountsEqual(size.GramEquivalent, 100) {
+			return true
+		}
+	}
+	return false
+}
+
+// implausibleServings returns the indexes of non-gram servings whose implied calories are implausible for one portion
+// A serving's calories are the per-100g calories scaled by its gram equivalent, so a data-entry error
+// such as a 1 piece serving of 5000g shows up as an absurd calorie count
+func (v *FoodValidator) implausibleServings(req *request.CreateFoodRequest) []int {
+	if v.maxServingCalories <= 0 {
+		return nil
+	}
+
+	var indexes []int
+	for i, size := range req.ServingSizes {
+		if size.Unit != "gram" && req.CaloriesValue()*size.GramEquivalent/100 > v.maxServingCalories {
+			indexes = append(indexes, i)
+		}
+	}
+	return indexes
+}
+
+// checkServingCalories warns about non-gram servings whose implied calories are implausible for one portion
+func (v *FoodValidator) checkServingCalories(ctx context.Context, req *request.CreateFoodRequest) {
+	for _, i := range v.implausibleServings(req) {
+		size := req.ServingSizes[i]
+		v.logger.Warn(ctx, "Serving size implies an implausible portion",
+			logger.Int("serving_size", i+1),
+			logger.String("unit", size.Unit),
+			logger.Float64("amount", size.Amount),
+			logger.Float64("gram_equivalent", size.GramEquivalent),
+			logger.Float64("serving_calories", req.CaloriesValue()*size.GramEquivalent/100),
+			logger.Float64("max_serving_calories", v.maxServingCalories))
+	}
+}
+
 // validateCaloriesConsistency validates that calories match calculated value from macros
 func (v *FoodValidator) validateCaloriesConsistency(req *request.CreateFoodRequest) error {
 	// Calculate expected calories from macros
 	// Formula: Protein (4 cal/g) + Carbs (4 cal/g) + Fat (9 cal/g) + Fiber (~2 cal/g)
-	expectedCalories := (req.Macros.Protein * 4) +
-		(req.Macros.Carbohydrates * 4) +
-		(req.Macros.Fat * 9) +
-		(req.Macros.Fiber * 2) // Fiber has approximately 2 calories per gram
+	expectedCalories := EstimateCalories(req.Macros)
 
 	// Allow tolerance
-	diff := req.Calories - expectedCalories
+	diff := req.CaloriesValue() - expectedCalories
 	if diff < -v.caloriesTolerance || diff > v.caloriesTolerance {
 		return fmt.Errorf(
 			"calories (%.2f) don't match calculated calories from macros (%.2f). Difference: %.2f. Allowed tolerance: ±%.2f",
-			req.Calories, expectedCalories, diff, v.caloriesTolerance,
+			req.CaloriesValue(), expectedCalories, diff, v.caloriesTolerance,
 		)
 	}
 
 	return nil
 }
 
+// EstimateCalories computes the calories implied by the macros of a request
+func EstimateCalories(macros request.MacroNutrientsRequest) float64 {
+	return calculator.CaloriesFromMacros(domain.MacroNutrients{
+		Protein:       macros.Protein,
+		Carbohydrates: macros.Carbohydrates,
+		Fat:           macros.Fat,
+		Fiber:         macros.Fiber,
+		Sugar:         macros.Sugar,
+	})
+}
+
 // validateImageURL validates image URL format
 func (v *FoodValidator) validateImageURL(urlStr string) error {
 	if strings.TrimSpace(urlStr) == "" {
@@ -283,6 +524,11 @@ func (v *FoodValidator) validateImageURL(urlStr string) error {
 		return fmt.Errorf("URL must have a valid host")
 	}
 
+	// Validate host against the allowlist
+	if !v.imageHostAllowed(parsedURL.Hostname()) {
+		return fmt.Errorf("image host %q is not allowed", parsedURL.Hostname())
+	}
+
 	// Validate URL length
 	if len(urlStr) > 2048 {
 		return fmt.Errorf("image URL exceeds maximum length (2048 chars)")
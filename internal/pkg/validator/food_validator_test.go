@@ -2,6 +2,9 @@ package validator
 
 import (
 	"context"
+	"errors"
+	"math"
+	"strings"
 	"testing"
 
 	"nutrient_be/internal/dto/request"
@@ -41,6 +44,11 @@ func (m *mockLogger) With(fields ...logger.Field) logger.Logger {
 }
 
 // Helper function to create a valid base request
+// float64Ptr returns a pointer to v for optional request fields
+func float64Ptr(v float64) *float64 {
+	return &v
+}
+
 func createValidFoodRequest() *request.CreateFoodRequest {
 	return &request.CreateFoodRequest{
 		Name: request.MultiLanguage{
@@ -76,7 +84,7 @@ func createValidFoodRequest() *request.CreateFoodRequest {
 				GramEquivalent: 182,
 			},
 		},
-		Calories:   63.8, // Matches calculated: 0.3*4 + 14*4 + 0.2*9 + 2.4*2 = 1.2 + 56 + 1.8 + 4.8 = 63.8
+		Calories:   float64Ptr(63.8), // Matches calculated: 0.3*4 + 14*4 + 0.2*9 + 2.4*2 = 1.2 + 56 + 1.8 + 4.8 = 63.8
 		Visibility: "public",
 	}
 }
@@ -394,7 +402,7 @@ func TestValidateCreateRequest_NutritionValidation(t *testing.T) {
 			name: "negative calories",
 			request: func() *request.CreateFoodRequest {
 				req := createValidFoodRequest()
-				req.Calories = -1
+				req.Calories = float64Ptr(-1)
 				return req
 			}(),
 			expectedErr: "nutrition validation failed",
@@ -403,7 +411,7 @@ func TestValidateCreateRequest_NutritionValidation(t *testing.T) {
 			name: "calories exceed max",
 			request: func() *request.CreateFoodRequest {
 				req := createValidFoodRequest()
-				req.Calories = 1001
+				req.Calories = float64Ptr(1001)
 				return req
 			}(),
 			expectedErr: "nutrition validation failed",
@@ -661,6 +669,32 @@ func TestValidateCreateRequest_ServingSizesValidation(t *testing.T) {
 			}(),
 			expectedErr: "", // Should pass
 		},
+		{
+			name: "duplicate cup servings",
+			request: func() *request.CreateFoodRequest {
+				req := createValidFoodRequest()
+				req.ServingSizes = []request.ServingSizeRequest{
+					{Unit: "gram", Amount: 100, GramEquivalent: 100},
+					{Unit: "cup", Amount: 1, GramEquivalent: 240},
+					{Unit: "cup", Amount: 1, GramEquivalent: 200},
+				}
+				return req
+			}(),
+			expectedErr: "duplicate unit 'cup'",
+		},
+		{
+			name: "distinct units",
+			request: func() *request.CreateFoodRequest {
+				req := createValidFoodRequest()
+				req.ServingSizes = []request.ServingSizeRequest{
+					{Unit: "gram", Amount: 100, GramEquivalent: 100},
+					{Unit: "cup", Amount: 1, GramEquivalent: 240},
+					{Unit: "piece", Amount: 1, GramEquivalent: 50},
+				}
+				return req
+			}(),
+			expectedErr: "", // Should pass
+		},
 		{
 			name: "valid box serving",
 			request: func() *request.CreateFoodRequest {
@@ -767,7 +801,7 @@ func TestValidateCreateRequest_CaloriesConsistency(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			req := createValidFoodRequest()
-			req.Calories = tt.calories
+			req.Calories = float64Ptr(tt.calories)
 			req.Macros = tt.macros
 
 			err := validator.ValidateCreateRequest(ctx, req)
@@ -906,6 +940,46 @@ func TestValidateCreateRequest_NoGramBaseWarning(t *testing.T) {
 	}
 }
 
+func TestValidateCreateRequest_ServingCaloriesWarning(t *testing.T) {
+	tests := []struct {
+		name           string
+		gramEquivalent float64
+		expectWarning  bool
+	}{
+		{"reasonable piece", 182, false},
+		{"absurd piece", 5000, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockLog := &mockLogger{}
+			validator := NewFoodValidator(mockLog)
+			validator.SetMaxServingCalories(2000)
+
+			// The apple has 63.8 kcal per 100g, so a 5000g piece would be 3190 kcal
+			req := createValidFoodRequest()
+			req.ServingSizes = []request.ServingSizeRequest{
+				{Unit: "gram", Amount: 100, GramEquivalent: 100},
+				{Unit: "piece", Amount: 1, GramEquivalent: tt.gramEquivalent},
+			}
+
+			if err := validator.ValidateCreateRequest(context.Background(), req); err != nil {
+				t.Fatalf("Expected request to pass (warning only), got error: %v", err)
+			}
+
+			warned := false
+			for _, warning := range mockLog.warnings {
+				if contains(warning, "implausible portion") {
+					warned = true
+				}
+			}
+			if warned != tt.expectWarning {
+				t.Errorf("Expected warning %v, got warnings: %v", tt.expectWarning, mockLog.warnings)
+			}
+		})
+	}
+}
+
 func TestValidateCreateRequest_BoundaryValues(t *testing.T) {
 	mockLog := &mockLogger{}
 	validator := NewFoodValidator(mockLog)
@@ -952,7 +1026,7 @@ func TestValidateCreateRequest_BoundaryValues(t *testing.T) {
 					Fat:           0,
 					Fiber:         0,
 				}
-				req.Calories = 400.0 // 100*4 = 400
+				req.Calories = float64Ptr(400.0) // 100*4 = 400
 				return req
 			}(),
 			shouldError: false,
@@ -967,7 +1041,7 @@ func TestValidateCreateRequest_BoundaryValues(t *testing.T) {
 					Fat:           0,
 					Fiber:         0,
 				}
-				req.Calories = 400.0 // 100*4
+				req.Calories = float64Ptr(400.0) // 100*4
 				return req
 			}(),
 			shouldError: false,
@@ -983,7 +1057,7 @@ func TestValidateCreateRequest_BoundaryValues(t *testing.T) {
 					Fiber:         49.0,
 				}
 				// Adjust calories to match: 50*4 + 50*4 + 50*9 + 49*2 = 200 + 200 + 450 + 98 = 948
-				req.Calories = 948.0
+				req.Calories = float64Ptr(948.0)
 				return req
 			}(),
 			shouldError: false,
@@ -1044,7 +1118,7 @@ func TestValidateCreateRequest_RealWorldScenarios(t *testing.T) {
 				ServingSizes: []request.ServingSizeRequest{
 					{Unit: "gram", Amount: 100, GramEquivalent: 100},
 				},
-				Calories:   156.4, // 31*4 + 0*4 + 3.6*9 = 124 + 32.4 = 156.4
+				Calories:   float64Ptr(156.4), // 31*4 + 0*4 + 3.6*9 = 124 + 32.4 = 156.4
 				Visibility: "public",
 			},
 			description: "Real chicken breast nutrition",
@@ -1070,7 +1144,7 @@ func TestValidateCreateRequest_RealWorldScenarios(t *testing.T) {
 					{Unit: "gram", Amount: 100, GramEquivalent: 100},
 					{Unit: "piece", Amount: 1, Description: "1 medium banana", GramEquivalent: 118},
 				},
-				Calories:   104.3, // 1.1*4 + 23*4 + 0.3*9 + 2.6*2 = 4.4 + 92 + 2.7 + 5.2 = 104.3
+				Calories:   float64Ptr(104.3), // 1.1*4 + 23*4 + 0.3*9 + 2.6*2 = 4.4 + 92 + 2.7 + 5.2 = 104.3
 				Visibility: "public",
 			},
 			description: "Real banana nutrition",
@@ -1092,7 +1166,7 @@ func TestValidateCreateRequest_RealWorldScenarios(t *testing.T) {
 					{Unit: "gram", Amount: 100, GramEquivalent: 100},
 					{Unit: "ml", Amount: 15, Description: "1 tablespoon", GramEquivalent: 13.5},
 				},
-				Calories:   900.0, // 100*9 = 900
+				Calories:   float64Ptr(900.0), // 100*9 = 900
 				Visibility: "public",
 			},
 			description: "Real olive oil nutrition (fat category)",
@@ -1126,3 +1200,191 @@ func containsInner(s, substr string) bool {
 	}
 	return false
 }
+
+func TestValidateServingSizes_GramTolerance(t *testing.T) {
+	tests := []struct {
+		name           string
+		amount         float64
+		gramEquivalent float64
+		expectErr      bool
+	}{
+		{name: "floating-point noise passes", amount: 100, gramEquivalent: 100.0000001, expectErr: false},
+		{name: "real mismatch fails", amount: 100, gramEquivalent: 105, expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockLog := &mockLogger{}
+			validator := NewFoodValidator(mockLog)
+			req := createValidFoodRequest()
+			req.ServingSizes[0].Amount = tt.amount
+			req.ServingSizes[0].GramEquivalent = tt.gramEquivalent
+
+			err := validator.ValidateCreateRequest(context.Background(), req)
+			if tt.expectErr && err == nil {
+				t.Error("Expected error but got nil")
+			}
+			if !tt.expectErr && err != nil {
+				t.Errorf("Expected no error, got: %v", err)
+			}
+			if !tt.expectErr && len(mockLog.warnings) != 0 {
+				t.Errorf("Expected the noisy 100g serving to count as the gram base, got warnings: %v", mockLog.warnings)
+			}
+		})
+	}
+}
+
+func TestSetServingTolerance(t *testing.T) {
+	validator := NewFoodValidator(&mockLogger{})
+	req := createValidFoodRequest()
+	req.ServingSizes[0].GramEquivalent = 100.5
+
+	if err := validator.ValidateCreateRequest(context.Background(), req); err == nil {
+		t.Error("Expected 0.5g difference to fail with the default tolerance")
+	}
+
+	validator.SetServingTolerance(1)
+	if err := validator.ValidateCreateRequest(context.Background(), req); err != nil {
+		t.Errorf("Expected 0.5g difference to pass with a 1g tolerance, got: %v", err)
+	}
+}
+
+func TestValidateServingSizes_RequireGramBase(t *testing.T) {
+	pieceOnly := func() *request.CreateFoodRequest {
+		req := createValidFoodRequest()
+		req.ServingSizes = []request.ServingSizeRequest{{Unit: "piece", Amount: 1, GramEquivalent: 182}}
+		return req
+	}
+	withBase := func() *request.CreateFoodRequest {
+		req := pieceOnly()
+		req.ServingSizes = append(req.ServingSizes, request.ServingSizeRequest{Unit: "gram", Amount: 100, GramEquivalent: 100})
+		return req
+	}
+
+	t.Run("lenient mode warns", func(t *testing.T) {
+		mockLog := &mockLogger{}
+		validator := NewFoodValidator(mockLog)
+
+		if err := validator.ValidateCreateRequest(context.Background(), pieceOnly()); err != nil {
+			t.Errorf("Expected no error, got: %v", err)
+		}
+		if len(mockLog.warnings) == 0 {
+			t.Error("Expected a missing gram base warning")
+		}
+	})
+
+	t.Run("strict mode rejects missing base", func(t *testing.T) {
+		validator := NewFoodValidator(&mockLogger{})
+		validator.SetRequireGramBase(true)
+
+		err := validator.ValidateCreateRequest(context.Background(), pieceOnly())
+		if err == nil || !contains(err.Error(), "100 gram base serving size is required") {
+			t.Errorf("Expected missing gram base error, got: %v", err)
+		}
+	})
+
+	t.Run("strict mode accepts base", func(t *testing.T) {
+		validator := NewFoodValidator(&mockLogger{})
+		validator.SetRequireGramBase(true)
+
+		if err := validator.ValidateCreateRequest(context.Background(), withBase()); err != nil {
+			t.Errorf("Expected no error, got: %v", err)
+		}
+	})
+}
+
+func TestValidateImageURL_AllowedHosts(t *testing.T) {
+	validator := NewFoodValidator(&mockLogger{})
+	req := createValidFoodRequest()
+	req.ImageURL = "https://images.example.org/apple.jpg"
+
+	if err := validator.ValidateCreateRequest(context.Background(), req); err != nil {
+		t.Fatalf("Expected any host to pass without an allowlist, got: %v", err)
+	}
+
+	validator.SetAllowedImageHosts([]string{"cdn.nutrient.app", " Example.org "})
+
+	allowed := []string{
+		"https://cdn.nutrient.app/foods/apple.jpg",
+		"https://images.example.org/apple.jpg",
+		"https://example.org:8443/apple.jpg",
+	}
+	for _, imageURL := range allowed {
+		req.ImageURL = imageURL
+		if err := validator.ValidateCreateRequest(context.Background(), req); err != nil {
+			t.Errorf("Expected %s to be allowed, got: %v", imageURL, err)
+		}
+	}
+
+	rejected := []string{
+		"https://evil.com/apple.jpg",
+		"https://notexample.org/apple.jpg",
+		"https://cdn.nutrient.app.evil.com/apple.jpg",
+		"http://169.254.169.254/latest/meta-data",
+	}
+	for _, imageURL := range rejected {
+		req.ImageURL = imageURL
+		err := validator.ValidateCreateRequest(context.Background(), req)
+		if err == nil || !strings.Contains(err.Error(), "is not allowed") {
+			t.Errorf("Expected %s to be rejected, got: %v", imageURL, err)
+		}
+	}
+}
+
+func TestReport_InconsistentCalories(t *testing.T) {
+	validator := NewFoodValidator(&mockLogger{})
+
+	// The macros imply 63.8 kcal
+	req := createValidFoodRequest()
+	req.Calories = float64Ptr(90)
+
+	report := validator.Report(context.Background(), req)
+	if report.Valid {
+		t.Fatal("Expected the report to be invalid")
+	}
+	if len(report.Errors) != 1 || report.Errors[0].Code != FoodRuleCaloriesConsistency {
+		t.Fatalf("Expected a calories consistency error, got: %+v", report.Errors)
+	}
+	if !contains(report.Errors[0].Message, "don't match calculated calories") {
+		t.Errorf("Expected the consistency message, got: %s", report.Errors[0].Message)
+	}
+	if math.Abs(report.ExpectedCalories-63.8) > 1e-9 || math.Abs(report.CaloriesDelta-26.2) > 1e-9 || report.CaloriesTolerance != 10 {
+		t.Errorf("Expected expected 63.8, delta 26.2 and tolerance 10, got: %+v", report)
+	}
+}
+
+func TestReport_ValidWithWarnings(t *testing.T) {
+	validator := NewFoodValidator(&mockLogger{})
+	validator.SetMaxServingCalories(2000)
+
+	// No 100g serving, and a 5000g piece would be 3190 kcal
+	req := createValidFoodRequest()
+	req.ServingSizes = []request.ServingSizeRequest{{Unit: "piece", Amount: 1, GramEquivalent: 5000}}
+
+	report := validator.Report(context.Background(), req)
+	if !report.Valid || len(report.Errors) != 0 {
+		t.Fatalf("Expected a valid report, got errors: %+v", report.Errors)
+	}
+	codes := make([]string, len(report.Warnings))
+	for i, warning := range report.Warnings {
+		codes[i] = warning.Code
+	}
+	if len(codes) != 2 || codes[0] != FoodWarningNoGramBase || codes[1] != FoodWarningServingCalories {
+		t.Errorf("Expected gram base and serving calories warnings, got: %v", codes)
+	}
+
+	if clean := validator.Report(context.Background(), createValidFoodRequest()); !clean.Valid || len(clean.Warnings) != 0 {
+		t.Errorf("Expected a clean request to be valid without warnings, got: %+v", clean)
+	}
+}
+
+func TestValidateCreateRequest_ReportsRule(t *testing.T) {
+	validator := NewFoodValidator(&mockLogger{})
+	req := createValidFoodRequest()
+	req.Name = request.MultiLanguage{"vi": "Táo"}
+
+	var ruleErr *FoodValidationError
+	if err := validator.ValidateCreateRequest(context.Background(), req); !errors.As(err, &ruleErr) || ruleErr.Rule != FoodRuleName {
+		t.Errorf("Expected a name rule error, got: %v", err)
+	}
+}
@@ -0,0 +1,78 @@
+package validator
+
+import (
+	"fmt"
+	"regexp"
+	"sync"
+)
+
+// builtinMealTimes are the times given to meals created without one
+var builtinMealTimes = map[string]string{
+	"breakfast": "07:00",
+	"lunch":     "12:00",
+	"dinner":    "19:00",
+	"snack":     "15:00",
+}
+
+// mealTimePattern matches a 24-hour HH:MM time
+var mealTimePattern = regexp.MustCompile(`^([01][0-9]|2[0-3]):[0-5][0-9]$`)
+
+// mealTimes is the registry of default times by meal type shared by all services
+var mealTimes = newMealTimeRegistry(builtinMealTimes)
+
+// mealTimeRegistry is a concurrency-safe map of meal type to default time
+type mealTimeRegistry struct {
+	mu    sync.RWMutex
+	times map[string]string
+}
+
+func newMealTimeRegistry(times map[string]string) *mealTimeRegistry {
+	r := &mealTimeRegistry{times: make(map[string]string, len(times))}
+	for mealType, t := range times {
+		r.times[mealType] = t
+	}
+	return r
+}
+
+// ValidateMealTime checks that t is a well-formed 24-hour HH:MM time such as "07:30"
+func ValidateMealTime(t string) error {
+	if !mealTimePattern.MatchString(t) {
+		return fmt.Errorf("invalid meal time '%s': must be HH:MM between 00:00 and 23:59", t)
+	}
+	return nil
+}
+
+// SetDefaultMealTime sets the time assigned to meals of mealType created without one
+// The meal type must be registered first
+func SetDefaultMealTime(mealType, t string) error {
+	if !IsValidMealType(mealType) {
+		return fmt.Errorf("invalid meal type '%s' for default time: must be one of: %s", mealType, mealTypeList())
+	}
+	if err := ValidateMealTime(t); err != nil {
+		return err
+	}
+
+	mealTimes.mu.Lock()
+	defer mealTimes.mu.Unlock()
+	mealTimes.times[mealType] = t
+	return nil
+}
+
+// DefaultMealTime returns the default time for a meal type, or "" when it has none
+func DefaultMealTime(mealType string) string {
+	mealTimes.mu.RLock()
+	defer mealTimes.mu.RUnlock()
+	return mealTimes.times[mealType]
+}
+
+// ResolveMealTime returns the time a new meal should get
+// A provided time must be well-formed; an empty one falls back to the meal type's default
+func ResolveMealTime(mealType, t string) (string, error) {
+	if t == "" {
+		return DefaultMealTime(mealType), nil
+	}
+	if err := ValidateMealTime(t); err != nil {
+		return "", err
+	}
+	return t, nil
+}
@@ -0,0 +1,64 @@
+package validator
+
+import "testing"
+
+// resetMealTimes restores the built-in default meal times after a test configures its own
+func resetMealTimes() {
+	mealTimes.mu.Lock()
+	defer mealTimes.mu.Unlock()
+	mealTimes.times = newMealTimeRegistry(builtinMealTimes).times
+}
+
+func TestResolveMealTime_UsesConfiguredDefault(t *testing.T) {
+	defer resetMealTimes()
+
+	got, err := ResolveMealTime("breakfast", "")
+	if err != nil || got != "07:00" {
+		t.Fatalf("Expected built-in breakfast time 07:00, got: %q (%v)", got, err)
+	}
+
+	if err := SetDefaultMealTime("breakfast", "06:30"); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	got, err = ResolveMealTime("breakfast", "")
+	if err != nil || got != "06:30" {
+		t.Errorf("Expected configured breakfast time 06:30, got: %q (%v)", got, err)
+	}
+
+	// An explicit time is kept
+	got, err = ResolveMealTime("breakfast", "08:15")
+	if err != nil || got != "08:15" {
+		t.Errorf("Expected explicit time 08:15, got: %q (%v)", got, err)
+	}
+}
+
+func TestValidateMealTime(t *testing.T) {
+	for _, valid := range []string{"00:00", "07:00", "19:45", "23:59"} {
+		if err := ValidateMealTime(valid); err != nil {
+			t.Errorf("Expected %q to be valid, got: %v", valid, err)
+		}
+	}
+	for _, invalid := range []string{"25:00", "24:00", "7:00", "07:60", "07-00", "noon", ""} {
+		if err := ValidateMealTime(invalid); err == nil {
+			t.Errorf("Expected %q to be rejected", invalid)
+		}
+	}
+
+	if _, err := ResolveMealTime("dinner", "25:00"); err == nil {
+		t.Error("Expected 25:00 to be rejected")
+	}
+}
+
+func TestSetDefaultMealTime_Rejects(t *testing.T) {
+	defer resetMealTimes()
+
+	if err := SetDefaultMealTime("brunch", "10:00"); err == nil {
+		t.Error("Expected an unregistered meal type to be rejected")
+	}
+	if err := SetDefaultMealTime("lunch", "12:5"); err == nil {
+		t.Error("Expected a malformed time to be rejected")
+	}
+	if got := DefaultMealTime("lunch"); got != "12:00" {
+		t.Errorf("Expected lunch time to stay 12:00, got: %q", got)
+	}
+}
@@ -0,0 +1,69 @@
+package validator
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// defaultMealTypes are the meal types that are always supported, in the order meals occur in a day
+var defaultMealTypes = []string{"breakfast", "lunch", "dinner", "snack"}
+
+// mealTypeNamePattern restricts registered meal types to lowercase identifiers such as "pre_workout"
+var mealTypeNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// mealTypes is the registry of supported meal types shared by all validators
+var mealTypes = newMealTypeRegistry(defaultMealTypes)
+
+// mealTypeRegistry is an ordered, concurrency-safe set of meal types
+type mealTypeRegistry struct {
+	mu    sync.RWMutex
+	types []string
+	index map[string]bool
+}
+
+func newMealTypeRegistry(types []string) *mealTypeRegistry {
+	r := &mealTypeRegistry{index: make(map[string]bool)}
+	for _, mealType := range types {
+		r.types = append(r.types, mealType)
+		r.index[mealType] = true
+	}
+	return r
+}
+
+// RegisterMealType adds a supported meal type, e.g. "brunch" or "post_workout"
+// Registering an existing type is a no-op
+func RegisterMealType(mealType string) error {
+	if !mealTypeNamePattern.MatchString(mealType) {
+		return fmt.Errorf("invalid meal type name '%s': must be lowercase letters, digits and underscores", mealType)
+	}
+
+	mealTypes.mu.Lock()
+	defer mealTypes.mu.Unlock()
+
+	if !mealTypes.index[mealType] {
+		mealTypes.types = append(mealTypes.types, mealType)
+		mealTypes.index[mealType] = true
+	}
+	return nil
+}
+
+// IsValidMealType reports whether a meal type is registered
+func IsValidMealType(mealType string) bool {
+	mealTypes.mu.RLock()
+	defer mealTypes.mu.RUnlock()
+	return mealTypes.index[mealType]
+}
+
+// MealTypes returns the supported meal types, defaults first, then in registration order
+func MealTypes() []string {
+	mealTypes.mu.RLock()
+	defer mealTypes.mu.RUnlock()
+	return append([]string(nil), mealTypes.types...)
+}
+
+// mealTypeList formats the supported meal types for error messages
+func mealTypeList() string {
+	return strings.Join(MealTypes(), ", ")
+}
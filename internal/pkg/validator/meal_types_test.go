@@ -0,0 +1,65 @@
+package validator
+
+import (
+	"context"
+	"testing"
+
+	"nutrient_be/internal/dto/request"
+)
+
+// resetMealTypes restores the default meal types after a test registers its own
+func resetMealTypes() {
+	mealTypes.mu.Lock()
+	defer mealTypes.mu.Unlock()
+	fresh := newMealTypeRegistry(defaultMealTypes)
+	mealTypes.types, mealTypes.index = fresh.types, fresh.index
+}
+
+func newMealTypeTestRequest(mealType string) *request.CreateMealTemplateRequest {
+	return &request.CreateMealTemplateRequest{
+		Name:     "Weekend brunch",
+		MealType: mealType,
+		FoodItems: []request.MealTemplateFoodItemRequest{
+			{FoodItemID: "507f1f77bcf86cd799439011", ServingUnit: "gram", Amount: 100},
+		},
+	}
+}
+
+func TestMealValidator_RegisteredMealType(t *testing.T) {
+	defer resetMealTypes()
+	v := NewMealValidator(&mockLogger{})
+
+	if err := v.ValidateCreateRequest(context.Background(), newMealTypeTestRequest("brunch")); err == nil {
+		t.Fatal("Expected brunch to be rejected before registration")
+	}
+
+	if err := RegisterMealType("brunch"); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if err := v.ValidateCreateRequest(context.Background(), newMealTypeTestRequest("brunch")); err != nil {
+		t.Errorf("Expected brunch template to be valid, got: %v", err)
+	}
+
+	// Defaults stay supported and keep their order
+	types := MealTypes()
+	expected := []string{"breakfast", "lunch", "dinner", "snack", "brunch"}
+	if len(types) != len(expected) {
+		t.Fatalf("Expected %v, got: %v", expected, types)
+	}
+	for i := range expected {
+		if types[i] != expected[i] {
+			t.Errorf("Expected %v, got: %v", expected, types)
+			break
+		}
+	}
+}
+
+func TestRegisterMealType_InvalidName(t *testing.T) {
+	defer resetMealTypes()
+
+	for _, name := range []string{"", "Brunch", "pre-workout", "late snack"} {
+		if err := RegisterMealType(name); err == nil {
+			t.Errorf("Expected error for meal type %q", name)
+		}
+	}
+}
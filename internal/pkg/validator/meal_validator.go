@@ -6,29 +6,74 @@ import (
 	"strings"
 
 	"nutrient_be/internal/dto/request"
+	"nutrient_be/internal/pkg/calculator"
 	"nutrient_be/internal/pkg/logger"
 )
 
 // MealValidator handles meal template data validation
 type MealValidator struct {
-	logger          logger.Logger
-	maxNameLength   int
+	logger               logger.Logger
+	maxNameLength        int
 	maxDescriptionLength int
-	maxTags         int
-	maxTagLength    int
+	maxTags              int
+	maxTagLength         int
+	maxFoodItems         int
+	maxItemAmount        float64
+	checkServingUnits    bool
 }
 
 // NewMealValidator creates a new meal validator with default rules
 func NewMealValidator(logger logger.Logger) *MealValidator {
 	return &MealValidator{
 		logger:               logger,
-		maxNameLength:         200,
+		maxNameLength:        200,
 		maxDescriptionLength: 1000,
-		maxTags:               20,
-		maxTagLength:          50,
+		maxTags:              20,
+		maxTagLength:         50,
+		maxFoodItems:         50,
+		maxItemAmount:        10000,
+		checkServingUnits:    true,
 	}
 }
 
+// SetMaxFoodItems sets how many food items a single request may contain
+// Values below 1 are ignored
+func (v *MealValidator) SetMaxFoodItems(n int) {
+	if n > 0 {
+		v.maxFoodItems = n
+	}
+}
+
+// SetMaxTags sets how many distinct tags a template may carry
+// Values below 1 are ignored
+func (v *MealValidator) SetMaxTags(n int) {
+	if n > 0 {
+		v.maxTags = n
+	}
+}
+
+// SetMaxTagLength sets the longest tag accepted, in bytes
+// Values below 1 are ignored
+func (v *MealValidator) SetMaxTagLength(n int) {
+	if n > 0 {
+		v.maxTagLength = n
+	}
+}
+
+// SetMaxItemAmount sets the largest amount a single food item may use, in its own serving unit
+// Values not above 0 are ignored
+func (v *MealValidator) SetMaxItemAmount(amount float64) {
+	if amount > 0 {
+		v.maxItemAmount = amount
+	}
+}
+
+// SetServingUnitCheck turns the serving unit check on or off
+// The check only accepts known units; whether the food itself has the unit is checked once the food is loaded
+func (v *MealValidator) SetServingUnitCheck(enabled bool) {
+	v.checkServingUnits = enabled
+}
+
 // ValidateCreateRequest validates a CreateMealTemplateRequest
 func (v *MealValidator) ValidateCreateRequest(ctx context.Context, req *request.CreateMealTemplateRequest) error {
 	// Validate name
@@ -133,17 +178,10 @@ func (v *MealValidator) validateDescription(description string) error {
 	return nil
 }
 
-// validateMealType validates meal type
+// validateMealType validates meal type against the registered meal types
 func (v *MealValidator) validateMealType(mealType string) error {
-	validTypes := map[string]bool{
-		"breakfast": true,
-		"lunch":     true,
-		"dinner":    true,
-		"snack":     true,
-	}
-
-	if !validTypes[mealType] {
-		return fmt.Errorf("invalid meal type '%s', must be one of: breakfast, lunch, dinner, snack", mealType)
+	if !IsValidMealType(mealType) {
+		return fmt.Errorf("invalid meal type '%s', must be one of: %s", mealType, mealTypeList())
 	}
 
 	return nil
@@ -154,6 +192,9 @@ func (v *MealValidator) validateFoodItems(foodItems []request.MealTemplateFoodIt
 	if len(foodItems) == 0 {
 		return fmt.Errorf("at least one food item is required")
 	}
+	if len(foodItems) > v.maxFoodItems {
+		return fmt.Errorf("too many food items: %d (maximum %d)", len(foodItems), v.maxFoodItems)
+	}
 
 	// Check for duplicate food items (same food ID and serving unit)
 	foodItemMap := make(map[string]bool)
@@ -167,11 +208,20 @@ func (v *MealValidator) validateFoodItems(foodItems []request.MealTemplateFoodIt
 		if strings.TrimSpace(item.ServingUnit) == "" {
 			return fmt.Errorf("food item %d: servingUnit is required", i+1)
 		}
+		if v.checkServingUnits && !IsValidServingUnit(item.ServingUnit) && !calculator.IsConvertibleUnit(item.ServingUnit) {
+			return fmt.Errorf("food item %d: unknown servingUnit '%s'. Valid units: %s", i+1, item.ServingUnit, servingUnitList())
+		}
 
-		// Validate amount
+		// Validate amount, NaN would pass the range check
+		if !calculator.IsFinite(item.Amount) {
+			return fmt.Errorf("food item %d: amount must be a finite number", i+1)
+		}
 		if item.Amount <= 0 {
 			return fmt.Errorf("food item %d: amount must be greater than 0", i+1)
 		}
+		if item.Amount > v.maxItemAmount {
+			return fmt.Errorf("food item %d: amount %g exceeds the maximum of %g per item", i+1, item.Amount, v.maxItemAmount)
+		}
 
 		// Check for duplicates
 		key := fmt.Sprintf("%s:%s", item.FoodItemID, item.ServingUnit)
@@ -185,8 +235,9 @@ func (v *MealValidator) validateFoodItems(foodItems []request.MealTemplateFoodIt
 }
 
 // validateTags validates tags array
+// Tags differing only in case or surrounding spaces count once, as they are stored normalized
 func (v *MealValidator) validateTags(tags []string) error {
-	if len(tags) > v.maxTags {
+	if len(NormalizeTags(tags)) > v.maxTags {
 		return fmt.Errorf("maximum number of tags is %d", v.maxTags)
 	}
 
@@ -202,3 +253,22 @@ func (v *MealValidator) validateTags(tags []string) error {
 
 	return nil
 }
+
+// NormalizeTags trims and lowercases tags, dropping empty ones and repeats while keeping the first occurrence order
+func NormalizeTags(tags []string) []string {
+	if tags == nil {
+		return nil
+	}
+
+	normalized := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	return normalized
+}
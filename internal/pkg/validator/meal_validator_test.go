@@ -0,0 +1,137 @@
+package validator
+
+import (
+	"context"
+	"math"
+	"strings"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"nutrient_be/internal/dto/request"
+)
+
+func newFoodItemRequests(n int) []request.MealTemplateFoodItemRequest {
+	items := make([]request.MealTemplateFoodItemRequest, n)
+	for i := range items {
+		items[i] = request.MealTemplateFoodItemRequest{FoodItemID: primitive.NewObjectID().Hex(), ServingUnit: "gram", Amount: 100}
+	}
+	return items
+}
+
+func TestMealValidator_FoodItemCap(t *testing.T) {
+	v := NewMealValidator(&mockLogger{})
+	v.SetMaxFoodItems(3)
+
+	req := newMealTypeTestRequest("lunch")
+	req.FoodItems = newFoodItemRequests(3)
+	if err := v.ValidateCreateRequest(context.Background(), req); err != nil {
+		t.Errorf("Expected 3 food items to be valid, got: %v", err)
+	}
+
+	req.FoodItems = newFoodItemRequests(4)
+	err := v.ValidateCreateRequest(context.Background(), req)
+	if err == nil || !strings.Contains(err.Error(), "too many food items: 4 (maximum 3)") {
+		t.Errorf("Expected too many food items error, got: %v", err)
+	}
+
+	if err := v.ValidateAddFoodRequest(context.Background(), &request.AddFoodToTemplateRequest{FoodItems: newFoodItemRequests(4)}); err == nil {
+		t.Error("Expected add food request above the cap to be rejected")
+	}
+}
+
+func TestMealValidator_SetMaxFoodItemsIgnoresNonPositive(t *testing.T) {
+	v := NewMealValidator(&mockLogger{})
+	v.SetMaxFoodItems(0)
+
+	if err := v.ValidateAddFoodRequest(context.Background(), &request.AddFoodToTemplateRequest{FoodItems: newFoodItemRequests(50)}); err != nil {
+		t.Errorf("Expected the default cap of 50 to apply, got: %v", err)
+	}
+	if err := v.ValidateAddFoodRequest(context.Background(), &request.AddFoodToTemplateRequest{FoodItems: newFoodItemRequests(51)}); err == nil {
+		t.Error("Expected 51 food items to be rejected")
+	}
+}
+
+func TestNormalizeTags(t *testing.T) {
+	got := NormalizeTags([]string{"Keto", " keto ", "KETO", "", "Quick"})
+	if strings.Join(got, ",") != "keto,quick" {
+		t.Errorf("Expected [keto quick], got: %v", got)
+	}
+	if NormalizeTags(nil) != nil {
+		t.Error("Expected nil tags to stay nil")
+	}
+}
+
+func TestMealValidator_TagCap(t *testing.T) {
+	v := NewMealValidator(&mockLogger{})
+	v.SetMaxTags(2)
+	v.SetMaxTagLength(5)
+
+	req := newMealTypeTestRequest("lunch")
+	req.Tags = []string{"Keto", "keto", "KETO", "quick"}
+	if err := v.ValidateCreateRequest(context.Background(), req); err != nil {
+		t.Errorf("Expected case duplicates to count once, got: %v", err)
+	}
+
+	req.Tags = []string{"keto", "quick", "vegan"}
+	err := v.ValidateCreateRequest(context.Background(), req)
+	if err == nil || !strings.Contains(err.Error(), "maximum number of tags is 2") {
+		t.Errorf("Expected the configured tag cap error, got: %v", err)
+	}
+
+	req.Tags = []string{"healthy"}
+	if err := v.ValidateCreateRequest(context.Background(), req); err == nil || !strings.Contains(err.Error(), "exceeds maximum length (5 chars)") {
+		t.Errorf("Expected the configured tag length error, got: %v", err)
+	}
+}
+
+func TestMealValidator_RejectsNaNAmount(t *testing.T) {
+	v := NewMealValidator(&mockLogger{})
+
+	err := v.validateFoodItems([]request.MealTemplateFoodItemRequest{
+		{FoodItemID: "507f1f77bcf86cd799439011", ServingUnit: "gram", Amount: math.NaN()},
+	})
+	if err == nil || !strings.Contains(err.Error(), "finite") {
+		t.Errorf("Expected a finite number error, got: %v", err)
+	}
+}
+
+func TestMealValidator_RejectsAbsurdAmount(t *testing.T) {
+	v := NewMealValidator(&mockLogger{})
+	v.SetMaxItemAmount(1000)
+
+	err := v.validateFoodItems([]request.MealTemplateFoodItemRequest{
+		{FoodItemID: "507f1f77bcf86cd799439011", ServingUnit: "cup", Amount: 100000},
+	})
+	if err == nil || !strings.Contains(err.Error(), "amount 100000 exceeds the maximum of 1000 per item") {
+		t.Errorf("Expected a maximum amount error, got: %v", err)
+	}
+
+	if err := v.validateFoodItems([]request.MealTemplateFoodItemRequest{
+		{FoodItemID: "507f1f77bcf86cd799439011", ServingUnit: "cup", Amount: 1000},
+	}); err != nil {
+		t.Errorf("Expected the maximum amount itself to be valid, got: %v", err)
+	}
+}
+
+func TestMealValidator_ServingUnitCheck(t *testing.T) {
+	v := NewMealValidator(&mockLogger{})
+	items := func(unit string) []request.MealTemplateFoodItemRequest {
+		return []request.MealTemplateFoodItemRequest{{FoodItemID: "507f1f77bcf86cd799439011", ServingUnit: unit, Amount: 1}}
+	}
+
+	// Registered units and units that only convert are both known
+	for _, unit := range []string{"piece", "tbsp"} {
+		if err := v.validateFoodItems(items(unit)); err != nil {
+			t.Errorf("Expected %q to be accepted, got: %v", unit, err)
+		}
+	}
+	if err := v.validateFoodItems(items("bucket")); err == nil || !strings.Contains(err.Error(), "unknown servingUnit 'bucket'") {
+		t.Errorf("Expected an unknown serving unit error, got: %v", err)
+	}
+
+	v.SetServingUnitCheck(false)
+	if err := v.validateFoodItems(items("bucket")); err != nil {
+		t.Errorf("Expected the check to be skipped when disabled, got: %v", err)
+	}
+}
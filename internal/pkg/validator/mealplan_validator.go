@@ -4,32 +4,38 @@ import (
 	"fmt"
 	"strings"
 	"time"
+	"unicode/utf8"
 
+	"nutrient_be/internal/domain"
 	"nutrient_be/internal/dto/request"
 	"nutrient_be/internal/pkg/logger"
 )
 
 // MealPlanValidator handles meal plan validation
 type MealPlanValidator struct {
-	minCalories          float64
-	maxCalories          float64
-	minDateRangeDays     int
-	maxDateRangeDays     int
-	maxNameLength        int
-	maxDescriptionLength int
-	logger               logger.Logger
+	minCalories           float64
+	maxCalories           float64
+	minDateRangeDays      int
+	maxDateRangeDays      int
+	maxNameLength         int
+	maxDescriptionLength  int
+	maxNotesLength        int
+	macroCalorieTolerance float64
+	logger                logger.Logger
 }
 
 // NewMealPlanValidator creates a new meal plan validator
 func NewMealPlanValidator(logger logger.Logger) *MealPlanValidator {
 	return &MealPlanValidator{
-		minCalories:          500,
-		maxCalories:          5000,
-		minDateRangeDays:     1,
-		maxDateRangeDays:     90, // Max 3 months
-		maxNameLength:        100,
-		maxDescriptionLength: 500,
-		logger:               logger,
+		minCalories:           500,
+		maxCalories:           5000,
+		minDateRangeDays:      1,
+		maxDateRangeDays:      90, // Max 3 months
+		maxNameLength:         100,
+		maxDescriptionLength:  500,
+		maxNotesLength:        500,
+		macroCalorieTolerance: 1.1, // Allow 10% for rounding in user-entered macros
+		logger:                logger,
 	}
 }
 
@@ -61,10 +67,59 @@ func (v *MealPlanValidator) ValidateCreateRequest(req *request.CreateMealPlanReq
 	}
 
 	// 6. Validate Target Calories
-	if err := v.validateTargetCalories(req.TargetCalories); err != nil {
+	// Targets left to the user's preferences are checked with ValidateTargets once they are known
+	if req.TargetCalories > 0 || !req.UseProfileTargets {
+		if err := v.validateTargetCalories(req.TargetCalories); err != nil {
+			return fmt.Errorf("target calories validation failed: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// ValidateUpdateRequest validates the provided fields of an UpdateMealPlanRequest
+func (v *MealPlanValidator) ValidateUpdateRequest(req *request.UpdateMealPlanRequest) error {
+	if req.Name != nil {
+		if err := v.validateName(*req.Name); err != nil {
+			return fmt.Errorf("name validation failed: %w", err)
+		}
+	}
+
+	if req.Description != nil && len(*req.Description) > v.maxDescriptionLength {
+		return fmt.Errorf("description exceeds maximum length (%d chars)", v.maxDescriptionLength)
+	}
+
+	if req.Goal != nil {
+		if err := v.validateGoal(*req.Goal); err != nil {
+			return fmt.Errorf("goal validation failed: %w", err)
+		}
+	}
+
+	if req.TargetCalories != nil {
+		if err := v.validateTargetCalories(*req.TargetCalories); err != nil {
+			return fmt.Errorf("target calories validation failed: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// ValidateTargets validates daily calorie and macro targets
+// The energy of the macro targets (4 kcal/g protein and carbohydrates, 9 kcal/g fat) must fit within the calorie target
+func (v *MealPlanValidator) ValidateTargets(calories float64, macros domain.MacroNutrients) error {
+	if err := v.validateTargetCalories(calories); err != nil {
 		return fmt.Errorf("target calories validation failed: %w", err)
 	}
 
+	if macros.Protein < 0 || macros.Carbohydrates < 0 || macros.Fat < 0 || macros.Fiber < 0 || macros.Sugar < 0 {
+		return fmt.Errorf("target macros must not be negative")
+	}
+
+	macroCalories := macros.Protein*4 + macros.Carbohydrates*4 + macros.Fat*9
+	if macroCalories > calories*v.macroCalorieTolerance {
+		return fmt.Errorf("target macros (%.2f kcal) exceed target calories (%.2f)", macroCalories, calories)
+	}
+
 	return nil
 }
 
@@ -124,6 +179,36 @@ func (v *MealPlanValidator) validatePlanType(planType string) error {
 	return nil
 }
 
+// validateStatus validates plan status
+func (v *MealPlanValidator) validateStatus(status string) error {
+	validStatuses := map[string]bool{
+		"draft":     true,
+		"active":    true,
+		"completed": true,
+	}
+
+	if !validStatuses[status] {
+		return fmt.Errorf("invalid status '%s'. Valid statuses: draft, active, completed", status)
+	}
+
+	return nil
+}
+
+// ValidateListFilters validates the optional plan type and status filters used when listing plans
+func (v *MealPlanValidator) ValidateListFilters(planType, status string) error {
+	if planType != "" {
+		if err := v.validatePlanType(planType); err != nil {
+			return err
+		}
+	}
+	if status != "" {
+		if err := v.validateStatus(status); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // validateGoal validates goal value
 func (v *MealPlanValidator) validateGoal(goal string) error {
 	validGoals := map[string]bool{
@@ -149,3 +234,12 @@ func (v *MealPlanValidator) validateTargetCalories(calories float64) error {
 	}
 	return nil
 }
+
+// ValidateNotes validates a day or meal note
+// The limit counts characters, so notes in Vietnamese are not cut short by their multi-byte letters
+func (v *MealPlanValidator) ValidateNotes(notes string) error {
+	if utf8.RuneCountInString(notes) > v.maxNotesLength {
+		return fmt.Errorf("notes exceed maximum length (%d chars)", v.maxNotesLength)
+	}
+	return nil
+}
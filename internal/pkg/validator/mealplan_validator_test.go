@@ -0,0 +1,18 @@
+package validator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMealPlanValidator_ValidateNotes_CountsCharacters(t *testing.T) {
+	v := NewMealPlanValidator(&mockLogger{})
+
+	// "ă" is 2 bytes, so 500 of them are 1000 bytes but 500 characters
+	if err := v.ValidateNotes(strings.Repeat("ă", 500)); err != nil {
+		t.Errorf("Expected 500 multi-byte characters to be valid, got: %v", err)
+	}
+	if err := v.ValidateNotes(strings.Repeat("ă", 501)); err == nil {
+		t.Error("Expected 501 characters to exceed the limit")
+	}
+}
@@ -0,0 +1,27 @@
+package validator
+
+import (
+	"unicode/utf8"
+
+	playground "github.com/go-playground/validator/v10"
+)
+
+// DefaultMinPasswordLength is the shortest password accepted when no minimum is configured
+const DefaultMinPasswordLength = 6
+
+// RegisterPasswordTag adds the password struct tag, which requires at least minLength characters
+// Values below 1 use DefaultMinPasswordLength
+func RegisterPasswordTag(v *playground.Validate, minLength int) error {
+	minLength = MinPasswordLength(minLength)
+	return v.RegisterValidation("password", func(fl playground.FieldLevel) bool {
+		return utf8.RuneCountInString(fl.Field().String()) >= minLength
+	})
+}
+
+// MinPasswordLength returns the effective minimum password length for a configured value
+func MinPasswordLength(configured int) int {
+	if configured < 1 {
+		return DefaultMinPasswordLength
+	}
+	return configured
+}
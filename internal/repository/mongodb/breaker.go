@@ -0,0 +1,140 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"nutrient_be/internal/pkg/circuitbreaker"
+)
+
+// guardedCollection runs the collection operations used by the repositories through a circuit breaker
+// While the breaker is open they fail with circuitbreaker.ErrOpen without reaching the server.
+// A nil breaker lets every operation through.
+type guardedCollection struct {
+	*mongo.Collection
+	breaker *circuitbreaker.Breaker
+}
+
+// newGuardedCollection wraps a collection of db with breaker
+func newGuardedCollection(db *mongo.Database, name string, breaker *circuitbreaker.Breaker) *guardedCollection {
+	return &guardedCollection{Collection: db.Collection(name), breaker: breaker}
+}
+
+// allow returns circuitbreaker.ErrOpen when the breaker rejects the operation
+func (c *guardedCollection) allow() error {
+	if c.breaker == nil {
+		return nil
+	}
+	return c.breaker.Allow()
+}
+
+// record reports the outcome of an operation to the breaker
+func (c *guardedCollection) record(err error) {
+	if c.breaker != nil {
+		c.breaker.Record(isBreakerFailure(err))
+	}
+}
+
+// InsertOne inserts a document through the breaker
+func (c *guardedCollection) InsertOne(ctx context.Context, document interface{}, opts ...*options.InsertOneOptions) (*mongo.InsertOneResult, error) {
+	if err := c.allow(); err != nil {
+		return nil, err
+	}
+	result, err := c.Collection.InsertOne(ctx, document, opts...)
+	c.record(err)
+	return result, err
+}
+
+// UpdateOne updates a document through the breaker
+func (c *guardedCollection) UpdateOne(ctx context.Context, filter interface{}, update interface{}, opts ...*options.UpdateOptions) (*mongo.UpdateResult, error) {
+	if err := c.allow(); err != nil {
+		return nil, err
+	}
+	result, err := c.Collection.UpdateOne(ctx, filter, update, opts...)
+	c.record(err)
+	return result, err
+}
+
+// UpdateMany updates documents through the breaker
+func (c *guardedCollection) UpdateMany(ctx context.Context, filter interface{}, update interface{}, opts ...*options.UpdateOptions) (*mongo.UpdateResult, error) {
+	if err := c.allow(); err != nil {
+		return nil, err
+	}
+	result, err := c.Collection.UpdateMany(ctx, filter, update, opts...)
+	c.record(err)
+	return result, err
+}
+
+// DeleteOne deletes a document through the breaker
+func (c *guardedCollection) DeleteOne(ctx context.Context, filter interface{}, opts ...*options.DeleteOptions) (*mongo.DeleteResult, error) {
+	if err := c.allow(); err != nil {
+		return nil, err
+	}
+	result, err := c.Collection.DeleteOne(ctx, filter, opts...)
+	c.record(err)
+	return result, err
+}
+
+// DeleteMany deletes documents through the breaker
+func (c *guardedCollection) DeleteMany(ctx context.Context, filter interface{}, opts ...*options.DeleteOptions) (*mongo.DeleteResult, error) {
+	if err := c.allow(); err != nil {
+		return nil, err
+	}
+	result, err := c.Collection.DeleteMany(ctx, filter, opts...)
+	c.record(err)
+	return result, err
+}
+
+// Find queries documents through the breaker
+func (c *guardedCollection) Find(ctx context.Context, filter interface{}, opts ...*options.FindOptions) (*mongo.Cursor, error) {
+	if err := c.allow(); err != nil {
+		return nil, err
+	}
+	cursor, err := c.Collection.Find(ctx, filter, opts...)
+	c.record(err)
+	return cursor, err
+}
+
+// CountDocuments counts documents through the breaker
+func (c *guardedCollection) CountDocuments(ctx context.Context, filter interface{}, opts ...*options.CountOptions) (int64, error) {
+	if err := c.allow(); err != nil {
+		return 0, err
+	}
+	count, err := c.Collection.CountDocuments(ctx, filter, opts...)
+	c.record(err)
+	return count, err
+}
+
+// Aggregate runs an aggregation pipeline through the breaker
+func (c *guardedCollection) Aggregate(ctx context.Context, pipeline interface{}, opts ...*options.AggregateOptions) (*mongo.Cursor, error) {
+	if err := c.allow(); err != nil {
+		return nil, err
+	}
+	cursor, err := c.Collection.Aggregate(ctx, pipeline, opts...)
+	c.record(err)
+	return cursor, err
+}
+
+// FindOne queries a document through the breaker
+// A missing document is a normal result and does not count as a failure
+func (c *guardedCollection) FindOne(ctx context.Context, filter interface{}, opts ...*options.FindOneOptions) *mongo.SingleResult {
+	if err := c.allow(); err != nil {
+		return mongo.NewSingleResultFromDocument(bson.D{}, err, nil)
+	}
+	result := c.Collection.FindOne(ctx, filter, opts...)
+	c.record(result.Err())
+	return result
+}
+
+// isBreakerFailure reports whether err means the database itself is unreachable or unhealthy
+// Errors caused by the request, such as missing documents, duplicate keys or a cancelled context, do not count
+func isBreakerFailure(err error) bool {
+	if err == nil || errors.Is(err, mongo.ErrNoDocuments) || errors.Is(err, context.Canceled) {
+		return false
+	}
+	return isRetryableError(err) || mongo.IsTimeout(err)
+}
@@ -0,0 +1,51 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"nutrient_be/internal/pkg/circuitbreaker"
+)
+
+func TestIsBreakerFailure(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"success", nil, false},
+		{"no documents", mongo.ErrNoDocuments, false},
+		{"cancelled", context.Canceled, false},
+		{"duplicate key", mongo.WriteException{WriteErrors: []mongo.WriteError{{Code: 11000}}}, false},
+		{"network error", errNetwork, true},
+		{"timeout", context.DeadlineExceeded, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isBreakerFailure(tt.err); got != tt.want {
+				t.Errorf("Expected %v, got: %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestGuardedCollection_FailsFastWhileOpen(t *testing.T) {
+	breaker := circuitbreaker.New(1, time.Hour)
+	breaker.Record(true)
+	// No collection is set, so reaching the driver would panic
+	collection := &guardedCollection{breaker: breaker}
+
+	var doc bson.M
+	if err := collection.FindOne(context.Background(), bson.M{}).Decode(&doc); !errors.Is(err, circuitbreaker.ErrOpen) {
+		t.Errorf("Expected FindOne to fail with ErrOpen, got: %v", err)
+	}
+	if _, err := collection.InsertOne(context.Background(), bson.M{}); !errors.Is(err, circuitbreaker.ErrOpen) {
+		t.Errorf("Expected InsertOne to fail with ErrOpen, got: %v", err)
+	}
+}
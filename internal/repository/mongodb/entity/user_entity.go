@@ -10,30 +10,56 @@ import (
 
 // UserEntity represents a user in MongoDB
 type UserEntity struct {
-	ID           primitive.ObjectID `bson:"_id,omitempty"`
-	Email        string             `bson:"email"`
-	PasswordHash string             `bson:"passwordHash"`
-	Profile      UserProfileEntity  `bson:"profile"`
-	Preferences  UserPreferencesEntity `bson:"preferences"`
-	CreatedAt    time.Time          `bson:"createdAt"`
-	UpdatedAt    time.Time          `bson:"updatedAt"`
+	ID            primitive.ObjectID    `bson:"_id,omitempty"`
+	Email         string                `bson:"email"`
+	PasswordHash  string                `bson:"passwordHash"`
+	Profile       UserProfileEntity     `bson:"profile"`
+	Preferences   UserPreferencesEntity `bson:"preferences"`
+	WeightHistory []WeightEntryEntity   `bson:"weightHistory,omitempty"`
+	CreatedAt     time.Time             `bson:"createdAt"`
+	UpdatedAt     time.Time             `bson:"updatedAt"`
 }
 
 // UserProfileEntity represents user profile in MongoDB
 type UserProfileEntity struct {
-	Name   string  `bson:"name"`
-	Age    int     `bson:"age"`
-	Weight float64 `bson:"weight"`
-	Height float64 `bson:"height"`
-	Gender string  `bson:"gender"`
-	Goal   string  `bson:"goal"`
+	Name          string  `bson:"name"`
+	Age           int     `bson:"age"`
+	Weight        float64 `bson:"weight"`
+	Height        float64 `bson:"height"`
+	Gender        string  `bson:"gender"`
+	Goal          string  `bson:"goal"`
+	ActivityLevel string  `bson:"activityLevel,omitempty"`
+}
+
+// WeightEntryEntity represents a weight measurement in MongoDB
+type WeightEntryEntity struct {
+	Weight float64   `bson:"weight"`
+	Date   time.Time `bson:"date"`
 }
 
 // UserPreferencesEntity represents user preferences in MongoDB
 type UserPreferencesEntity struct {
-	Language      string              `bson:"language"`
-	CalorieTarget float64             `bson:"calorieTarget"`
+	Language      string               `bson:"language"`
+	CalorieTarget float64              `bson:"calorieTarget"`
 	MacroTargets  MacroNutrientsEntity `bson:"macroTargets"`
+	WeekStart     string               `bson:"weekStart,omitempty"`
+	DailyValues   DailyValuesEntity    `bson:"dailyValues,omitempty"`
+}
+
+// DailyValuesEntity represents a user's reference daily values in MongoDB
+type DailyValuesEntity struct {
+	Calories      float64 `bson:"calories,omitempty"`
+	Protein       float64 `bson:"protein,omitempty"`
+	Carbohydrates float64 `bson:"carbohydrates,omitempty"`
+	Fat           float64 `bson:"fat,omitempty"`
+	Fiber         float64 `bson:"fiber,omitempty"`
+	Sugar         float64 `bson:"sugar,omitempty"`
+	VitaminA      float64 `bson:"vitaminA,omitempty"`
+	VitaminC      float64 `bson:"vitaminC,omitempty"`
+	Calcium       float64 `bson:"calcium,omitempty"`
+	Iron          float64 `bson:"iron,omitempty"`
+	Sodium        float64 `bson:"sodium,omitempty"`
+	Potassium     float64 `bson:"potassium,omitempty"`
 }
 
 // MacroNutrientsEntity represents macronutrient values in MongoDB
@@ -56,12 +82,13 @@ func (e *UserEntity) ToDomain() *domain.User {
 		Email:        e.Email,
 		PasswordHash: e.PasswordHash,
 		Profile: domain.UserProfile{
-			Name:   e.Profile.Name,
-			Age:    e.Profile.Age,
-			Weight: e.Profile.Weight,
-			Height: e.Profile.Height,
-			Gender: e.Profile.Gender,
-			Goal:   e.Profile.Goal,
+			Name:          e.Profile.Name,
+			Age:           e.Profile.Age,
+			Weight:        e.Profile.Weight,
+			Height:        e.Profile.Height,
+			Gender:        e.Profile.Gender,
+			Goal:          e.Profile.Goal,
+			ActivityLevel: e.Profile.ActivityLevel,
 		},
 		Preferences: domain.UserPreferences{
 			Language:      e.Preferences.Language,
@@ -73,9 +100,25 @@ func (e *UserEntity) ToDomain() *domain.User {
 				Fiber:         e.Preferences.MacroTargets.Fiber,
 				Sugar:         e.Preferences.MacroTargets.Sugar,
 			},
+			WeekStart: e.Preferences.WeekStart,
+			DailyValues: domain.DailyReferenceValues{
+				Calories:      e.Preferences.DailyValues.Calories,
+				Protein:       e.Preferences.DailyValues.Protein,
+				Carbohydrates: e.Preferences.DailyValues.Carbohydrates,
+				Fat:           e.Preferences.DailyValues.Fat,
+				Fiber:         e.Preferences.DailyValues.Fiber,
+				Sugar:         e.Preferences.DailyValues.Sugar,
+				VitaminA:      e.Preferences.DailyValues.VitaminA,
+				VitaminC:      e.Preferences.DailyValues.VitaminC,
+				Calcium:       e.Preferences.DailyValues.Calcium,
+				Iron:          e.Preferences.DailyValues.Iron,
+				Sodium:        e.Preferences.DailyValues.Sodium,
+				Potassium:     e.Preferences.DailyValues.Potassium,
+			},
 		},
-		CreatedAt: e.CreatedAt,
-		UpdatedAt: e.UpdatedAt,
+		WeightHistory: weightHistoryToDomain(e.WeightHistory),
+		CreatedAt:     e.CreatedAt,
+		UpdatedAt:     e.UpdatedAt,
 	}
 }
 
@@ -95,12 +138,13 @@ func (e *UserEntity) FromDomain(u *domain.User) error {
 	e.Email = u.Email
 	e.PasswordHash = u.PasswordHash
 	e.Profile = UserProfileEntity{
-		Name:   u.Profile.Name,
-		Age:    u.Profile.Age,
-		Weight: u.Profile.Weight,
-		Height: u.Profile.Height,
-		Gender: u.Profile.Gender,
-		Goal:   u.Profile.Goal,
+		Name:          u.Profile.Name,
+		Age:           u.Profile.Age,
+		Weight:        u.Profile.Weight,
+		Height:        u.Profile.Height,
+		Gender:        u.Profile.Gender,
+		Goal:          u.Profile.Goal,
+		ActivityLevel: u.Profile.ActivityLevel,
 	}
 	e.Preferences = UserPreferencesEntity{
 		Language:      u.Preferences.Language,
@@ -112,6 +156,25 @@ func (e *UserEntity) FromDomain(u *domain.User) error {
 			Fiber:         u.Preferences.MacroTargets.Fiber,
 			Sugar:         u.Preferences.MacroTargets.Sugar,
 		},
+		WeekStart: u.Preferences.WeekStart,
+		DailyValues: DailyValuesEntity{
+			Calories:      u.Preferences.DailyValues.Calories,
+			Protein:       u.Preferences.DailyValues.Protein,
+			Carbohydrates: u.Preferences.DailyValues.Carbohydrates,
+			Fat:           u.Preferences.DailyValues.Fat,
+			Fiber:         u.Preferences.DailyValues.Fiber,
+			Sugar:         u.Preferences.DailyValues.Sugar,
+			VitaminA:      u.Preferences.DailyValues.VitaminA,
+			VitaminC:      u.Preferences.DailyValues.VitaminC,
+			Calcium:       u.Preferences.DailyValues.Calcium,
+			Iron:          u.Preferences.DailyValues.Iron,
+			Sodium:        u.Preferences.DailyValues.Sodium,
+			Potassium:     u.Preferences.DailyValues.Potassium,
+		},
+	}
+	e.WeightHistory = nil
+	for _, entry := range u.WeightHistory {
+		e.WeightHistory = append(e.WeightHistory, WeightEntryEntity{Weight: entry.Weight, Date: entry.Date})
 	}
 	e.CreatedAt = u.CreatedAt
 	e.UpdatedAt = u.UpdatedAt
@@ -119,3 +182,11 @@ func (e *UserEntity) FromDomain(u *domain.User) error {
 	return nil
 }
 
+// weightHistoryToDomain converts stored weight entries to domain entries
+func weightHistoryToDomain(entries []WeightEntryEntity) []domain.WeightEntry {
+	var history []domain.WeightEntry
+	for _, entry := range entries {
+		history = append(history, domain.WeightEntry{Weight: entry.Weight, Date: entry.Date})
+	}
+	return history
+}
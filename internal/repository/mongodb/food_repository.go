@@ -12,6 +12,8 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/options"
 
 	"nutrient_be/internal/domain"
+	"nutrient_be/internal/pkg/circuitbreaker"
+	"nutrient_be/internal/pkg/textnorm"
 )
 
 const (
@@ -20,13 +22,15 @@ const (
 
 // foodRepository handles food data operations
 type foodRepository struct {
-	collection *mongo.Collection
+	collection *guardedCollection
+	retry      RetryPolicy
 }
 
 // NewFoodRepository creates a new food repository
-func NewFoodRepository(db *mongo.Database) *foodRepository {
+func NewFoodRepository(db *mongo.Database, retry RetryPolicy, breaker *circuitbreaker.Breaker) *foodRepository {
 	return &foodRepository{
-		collection: db.Collection(foodCollection),
+		collection: newGuardedCollection(db, foodCollection, breaker),
+		retry:      retry,
 	}
 }
 
@@ -45,7 +49,9 @@ func (r *foodRepository) Create(ctx context.Context, food *domain.FoodItem) erro
 // GetByID retrieves a food item by ID
 func (r *foodRepository) GetByID(ctx context.Context, id primitive.ObjectID) (*domain.FoodItem, error) {
 	var food domain.FoodItem
-	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&food)
+	err := r.retry.Do(ctx, func(ctx context.Context) error {
+		return r.collection.FindOne(ctx, excludeDeleted(bson.M{"_id": id})).Decode(&food)
+	})
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
 			return nil, fmt.Errorf("food item not found")
@@ -55,99 +61,220 @@ func (r *foodRepository) GetByID(ctx context.Context, id primitive.ObjectID) (*d
 	return &food, nil
 }
 
-// Search searches for food items using text search
-func (r *foodRepository) Search(ctx context.Context, query string, userID primitive.ObjectID, limit, offset int) ([]*domain.FoodItem, error) {
-	// Normalize search query
-	normalizedQuery := strings.ToLower(strings.TrimSpace(query))
-
-	// Build search filter
-	filter := bson.M{
-		"$and": []bson.M{
-			{
-				"$or": []bson.M{
-					{"searchTerms": bson.M{"$regex": normalizedQuery, "$options": "i"}},
-					{"name.en": bson.M{"$regex": normalizedQuery, "$options": "i"}},
-					{"name.vi": bson.M{"$regex": normalizedQuery, "$options": "i"}},
-				},
-			},
-			{
-				"$or": []bson.M{
-					{"visibility": "public"},
-					{"createdBy": userID},
-				},
-			},
-		},
+// GetByIDs retrieves the food items with the given IDs in one query
+// IDs without a food item are left out of the result
+func (r *foodRepository) GetByIDs(ctx context.Context, ids []primitive.ObjectID) ([]*domain.FoodItem, error) {
+	if len(ids) == 0 {
+		return nil, nil
 	}
 
-	opts := options.Find().
-		SetLimit(int64(limit)).
-		SetSkip(int64(offset)).
-		SetSort(bson.M{"createdAt": -1})
+	var foods []*domain.FoodItem
+	err := r.retry.Do(ctx, func(ctx context.Context) error {
+		cursor, err := r.collection.Find(ctx, excludeDeleted(bson.M{"_id": bson.M{"$in": ids}}))
+		if err != nil {
+			return fmt.Errorf("failed to get food items: %w", err)
+		}
+		defer cursor.Close(ctx)
 
-	cursor, err := r.collection.Find(ctx, filter, opts)
+		foods = nil
+		if err := cursor.All(ctx, &foods); err != nil {
+			return fmt.Errorf("failed to decode food items: %w", err)
+		}
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to search food items: %w", err)
-	}
-	defer cursor.Close(ctx)
-
-	var foods []*domain.FoodItem
-	if err := cursor.All(ctx, &foods); err != nil {
-		return nil, fmt.Errorf("failed to decode food items: %w", err)
+		return nil, err
 	}
 
 	return foods, nil
 }
 
-// GetByCategory retrieves food items by category
-func (r *foodRepository) GetByCategory(ctx context.Context, category string, userID primitive.ObjectID, limit, offset int) ([]*domain.FoodItem, error) {
-	filter := bson.M{
-		"category": category,
-		"$or": []bson.M{
-			{"visibility": "public"},
-			{"createdBy": userID},
-		},
+// FindFoods retrieves the food items matching filter, one page at a time
+// Every narrower food query delegates to it, so new criteria only need adding to foodFilterQuery
+func (r *foodRepository) FindFoods(ctx context.Context, filter domain.FoodFilter, page domain.Page, sortBy string) ([]*domain.FoodItem, error) {
+	// "imported" sorts before "user", so an ascending source sort puts curated foods first
+	// The ID breaks ties between foods created at the same time so pages never overlap
+	sortOrder := bson.D{{Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}}
+	if sortBy == domain.FoodSortImportedFirst {
+		if page.After != nil {
+			return nil, fmt.Errorf("cursor paging only supports the newest-first order")
+		}
+		sortOrder = bson.D{{Key: "source", Value: 1}, {Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}}
 	}
 
 	opts := options.Find().
-		SetLimit(int64(limit)).
-		SetSkip(int64(offset)).
-		SetSort(bson.M{"createdAt": -1})
+		SetLimit(int64(page.Limit)).
+		SetSkip(int64(page.Offset)).
+		SetSort(sortOrder)
 
-	cursor, err := r.collection.Find(ctx, filter, opts)
+	query := excludeDeleted(afterCursor(foodFilterQuery(filter), page.After))
+	var foods []*domain.FoodItem
+	err := r.retry.Do(ctx, func(ctx context.Context) error {
+		cursor, err := r.collection.Find(ctx, query, opts)
+		if err != nil {
+			return fmt.Errorf("failed to find food items: %w", err)
+		}
+		defer cursor.Close(ctx)
+
+		foods = nil
+		if err := cursor.All(ctx, &foods); err != nil {
+			return fmt.Errorf("failed to decode food items: %w", err)
+		}
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to get food items by category: %w", err)
+		return nil, err
 	}
-	defer cursor.Close(ctx)
 
-	var foods []*domain.FoodItem
-	if err := cursor.All(ctx, &foods); err != nil {
-		return nil, fmt.Errorf("failed to decode food items: %w", err)
+	return foods, nil
+}
+
+// CountFoods counts the food items matching filter
+func (r *foodRepository) CountFoods(ctx context.Context, filter domain.FoodFilter) (int64, error) {
+	query := excludeDeleted(foodFilterQuery(filter))
+	var count int64
+	err := r.retry.Do(ctx, func(ctx context.Context) error {
+		var err error
+		count, err = r.collection.CountDocuments(ctx, query)
+		if err != nil {
+			return fmt.Errorf("failed to count food items: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
 	}
 
-	return foods, nil
+	return count, nil
 }
 
-// GetByUser retrieves food items created by a specific user
-func (r *foodRepository) GetByUser(ctx context.Context, userID primitive.ObjectID, limit, offset int) ([]*domain.FoodItem, error) {
-	filter := bson.M{"createdBy": userID}
+// afterCursor narrows query to the foods following cursor in the newest-first order of (createdAt, _id)
+// A nil cursor leaves the query unchanged
+func afterCursor(query bson.M, cursor *domain.PageCursor) bson.M {
+	if cursor == nil {
+		return query
+	}
+	after := bson.M{"$or": []bson.M{
+		{"createdAt": bson.M{"$lt": cursor.CreatedAt}},
+		{"createdAt": cursor.CreatedAt, "_id": bson.M{"$lt": cursor.ID}},
+	}}
+	if len(query) == 0 {
+		return after
+	}
+	return bson.M{"$and": []bson.M{query, after}}
+}
 
-	opts := options.Find().
-		SetLimit(int64(limit)).
-		SetSkip(int64(offset)).
-		SetSort(bson.M{"createdAt": -1})
+// excludeDeleted narrows query to foods that have not been soft-deleted
+func excludeDeleted(query bson.M) bson.M {
+	query["deletedAt"] = bson.M{"$exists": false}
+	return query
+}
 
-	cursor, err := r.collection.Find(ctx, filter, opts)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get user food items: %w", err)
+// foodFilterQuery composes the MongoDB query for the fields of filter that are set
+func foodFilterQuery(filter domain.FoodFilter) bson.M {
+	var conditions []bson.M
+
+	if filter.Query != "" {
+		// Search terms are stored without diacritics, so they are matched against the query stripped the same way
+		normalizedQuery := strings.ToLower(strings.TrimSpace(filter.Query))
+		searchTermQuery := textnorm.Normalize(filter.Query)
+		conditions = append(conditions, bson.M{
+			"$or": []bson.M{
+				{"searchTerms": bson.M{"$regex": searchTermQuery, "$options": "i"}},
+				{"name.en": bson.M{"$regex": normalizedQuery, "$options": "i"}},
+				{"name.vi": bson.M{"$regex": normalizedQuery, "$options": "i"}},
+			},
+		})
+	}
+	if filter.AccessibleTo != nil {
+		// Anonymous callers only see public foods; matching createdBy on the nil ID would expose ownerless foods
+		if filter.AccessibleTo.IsZero() {
+			conditions = append(conditions, bson.M{"visibility": "public"})
+		} else {
+			conditions = append(conditions, bson.M{
+				"$or": []bson.M{
+					{"visibility": "public"},
+					{"createdBy": *filter.AccessibleTo},
+				},
+			})
+		}
+	}
+	if filter.CreatedBy != nil {
+		conditions = append(conditions, bson.M{"createdBy": *filter.CreatedBy})
+	}
+	if len(filter.Categories) > 0 {
+		conditions = append(conditions, bson.M{"category": bson.M{"$in": filter.Categories}})
+	}
+	if len(filter.Tags) > 0 {
+		conditions = append(conditions, bson.M{"searchTerms": bson.M{"$in": filter.Tags}})
+	}
+	if len(filter.ExcludeAllergens) > 0 {
+		conditions = append(conditions, bson.M{"allergens": bson.M{"$nin": filter.ExcludeAllergens}})
+	}
+	if len(filter.DietTags) > 0 {
+		conditions = append(conditions, bson.M{"dietTags": bson.M{"$all": filter.DietTags}})
+	}
+	if filter.Source != "" {
+		conditions = append(conditions, bson.M{"source": filter.Source})
+	}
+	if filter.Visibility != "" {
+		conditions = append(conditions, bson.M{"visibility": filter.Visibility})
 	}
-	defer cursor.Close(ctx)
 
-	var foods []*domain.FoodItem
-	if err := cursor.All(ctx, &foods); err != nil {
-		return nil, fmt.Errorf("failed to decode food items: %w", err)
+	ranges := []struct {
+		field string
+		r     domain.NutrientRange
+	}{
+		{"calories", filter.Calories},
+		{"macros.protein", filter.Protein},
+		{"macros.carbohydrates", filter.Carbohydrates},
+		{"macros.fat", filter.Fat},
+		{"macros.fiber", filter.Fiber},
+	}
+	for _, nutrient := range ranges {
+		if !nutrient.r.IsSet() {
+			continue
+		}
+		bounds := bson.M{}
+		if nutrient.r.Min != nil {
+			bounds["$gte"] = *nutrient.r.Min
+		}
+		if nutrient.r.Max != nil {
+			bounds["$lte"] = *nutrient.r.Max
+		}
+		conditions = append(conditions, bson.M{nutrient.field: bounds})
 	}
 
-	return foods, nil
+	switch len(conditions) {
+	case 0:
+		return bson.M{}
+	case 1:
+		return conditions[0]
+	default:
+		return bson.M{"$and": conditions}
+	}
+}
+
+// Search searches for food items the user can see by name or search term
+func (r *foodRepository) Search(ctx context.Context, query string, userID primitive.ObjectID, source string, importedFirst bool, limit, offset int) ([]*domain.FoodItem, error) {
+	sortBy := domain.FoodSortNewest
+	if importedFirst {
+		sortBy = domain.FoodSortImportedFirst
+	}
+	filter := domain.FoodFilter{Query: query, AccessibleTo: &userID, Source: source}
+	return r.FindFoods(ctx, filter, domain.Page{Limit: limit, Offset: offset}, sortBy)
+}
+
+// GetByCategory retrieves food items by category
+func (r *foodRepository) GetByCategory(ctx context.Context, category string, userID primitive.ObjectID, limit, offset int) ([]*domain.FoodItem, error) {
+	filter := domain.FoodFilter{Categories: []string{category}, AccessibleTo: &userID}
+	return r.FindFoods(ctx, filter, domain.Page{Limit: limit, Offset: offset}, domain.FoodSortNewest)
+}
+
+// GetByUser retrieves food items created by a specific user
+func (r *foodRepository) GetByUser(ctx context.Context, userID primitive.ObjectID, limit, offset int) ([]*domain.FoodItem, error) {
+	filter := domain.FoodFilter{CreatedBy: &userID}
+	return r.FindFoods(ctx, filter, domain.Page{Limit: limit, Offset: offset}, domain.FoodSortNewest)
 }
 
 // Update updates a food item
@@ -173,25 +300,65 @@ func (r *foodRepository) Delete(ctx context.Context, id primitive.ObjectID) erro
 	return nil
 }
 
-// GetPublicFoods retrieves public food items
-func (r *foodRepository) GetPublicFoods(ctx context.Context, limit, offset int) ([]*domain.FoodItem, error) {
-	filter := bson.M{"visibility": "public"}
+// MarkMerged soft-deletes a food merged into keepID, keeping the record for auditing
+func (r *foodRepository) MarkMerged(ctx context.Context, id, keepID primitive.ObjectID) error {
+	now := time.Now()
+	update := bson.M{"$set": bson.M{"mergedInto": keepID, "deletedAt": now, "updatedAt": now}}
+	result, err := r.collection.UpdateOne(ctx, excludeDeleted(bson.M{"_id": id}), update)
+	if err != nil {
+		return fmt.Errorf("failed to mark food item as merged: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("food item not found")
+	}
+	return nil
+}
 
-	opts := options.Find().
-		SetLimit(int64(limit)).
-		SetSkip(int64(offset)).
-		SetSort(bson.M{"createdAt": -1})
+// SetVisibilityMatching sets the visibility of the user's food items matching the category and any of the search terms
+// Empty criteria are ignored; foods that already have the visibility are not counted
+func (r *foodRepository) SetVisibilityMatching(ctx context.Context, userID primitive.ObjectID, category string, searchTerms []string, visibility string) (int64, error) {
+	filter := excludeDeleted(bson.M{"createdBy": userID, "visibility": bson.M{"$ne": visibility}})
+	if category != "" {
+		filter["category"] = category
+	}
+	if len(searchTerms) > 0 {
+		filter["searchTerms"] = bson.M{"$in": searchTerms}
+	}
 
-	cursor, err := r.collection.Find(ctx, filter, opts)
+	update := bson.M{"$set": bson.M{"visibility": visibility, "updatedAt": time.Now()}}
+	result, err := r.collection.UpdateMany(ctx, filter, update)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get public food items: %w", err)
+		return 0, fmt.Errorf("failed to update food visibility: %w", err)
 	}
-	defer cursor.Close(ctx)
+	return result.ModifiedCount, nil
+}
 
-	var foods []*domain.FoodItem
-	if err := cursor.All(ctx, &foods); err != nil {
-		return nil, fmt.Errorf("failed to decode food items: %w", err)
+// DeleteByUser deletes all food items created by a user
+func (r *foodRepository) DeleteByUser(ctx context.Context, userID primitive.ObjectID) error {
+	_, err := r.collection.DeleteMany(ctx, bson.M{"createdBy": userID})
+	if err != nil {
+		return fmt.Errorf("failed to delete user food items: %w", err)
 	}
+	return nil
+}
 
-	return foods, nil
+// AnonymizeByUser deletes a user's private food items and detaches their public ones from the account
+func (r *foodRepository) AnonymizeByUser(ctx context.Context, userID primitive.ObjectID) error {
+	_, err := r.collection.DeleteMany(ctx, bson.M{"createdBy": userID, "visibility": bson.M{"$ne": "public"}})
+	if err != nil {
+		return fmt.Errorf("failed to delete private user food items: %w", err)
+	}
+
+	update := bson.M{"$set": bson.M{"createdBy": primitive.NilObjectID, "updatedAt": time.Now()}}
+	_, err = r.collection.UpdateMany(ctx, bson.M{"createdBy": userID}, update)
+	if err != nil {
+		return fmt.Errorf("failed to anonymize user food items: %w", err)
+	}
+	return nil
+}
+
+// GetPublicFoods retrieves public food items
+func (r *foodRepository) GetPublicFoods(ctx context.Context, limit, offset int) ([]*domain.FoodItem, error) {
+	filter := domain.FoodFilter{Visibility: "public"}
+	return r.FindFoods(ctx, filter, domain.Page{Limit: limit, Offset: offset}, domain.FoodSortNewest)
 }
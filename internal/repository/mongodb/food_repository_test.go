@@ -0,0 +1,106 @@
+package mongodb
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"nutrient_be/internal/domain"
+)
+
+func TestFoodFilterQuery_CombinesQueryCategoryAndMacroRange(t *testing.T) {
+	userID := primitive.NewObjectID()
+	minProtein, maxFat := 20.0, 5.0
+	filter := domain.FoodFilter{
+		Query:        "Phở",
+		Categories:   []string{"protein", "grain"},
+		AccessibleTo: &userID,
+		Protein:      domain.NutrientRange{Min: &minProtein},
+		Fat:          domain.NutrientRange{Max: &maxFat},
+	}
+
+	query := foodFilterQuery(filter)
+	conditions, ok := query["$and"].([]bson.M)
+	if !ok {
+		t.Fatalf("Expected the conditions to be combined with $and, got: %v", query)
+	}
+
+	expected := []bson.M{
+		{"$or": []bson.M{
+			{"searchTerms": bson.M{"$regex": "pho", "$options": "i"}},
+			{"name.en": bson.M{"$regex": "phở", "$options": "i"}},
+			{"name.vi": bson.M{"$regex": "phở", "$options": "i"}},
+		}},
+		{"$or": []bson.M{
+			{"visibility": "public"},
+			{"createdBy": userID},
+		}},
+		{"category": bson.M{"$in": []string{"protein", "grain"}}},
+		{"macros.protein": bson.M{"$gte": 20.0}},
+		{"macros.fat": bson.M{"$lte": 5.0}},
+	}
+	if !reflect.DeepEqual(conditions, expected) {
+		t.Errorf("Expected conditions %v, got: %v", expected, conditions)
+	}
+}
+
+func TestFoodFilterQuery_AllergensAndDietTags(t *testing.T) {
+	query := foodFilterQuery(domain.FoodFilter{ExcludeAllergens: []string{"peanut"}, DietTags: []string{"vegan", "gluten_free"}})
+	expected := bson.M{"$and": []bson.M{
+		{"allergens": bson.M{"$nin": []string{"peanut"}}},
+		{"dietTags": bson.M{"$all": []string{"vegan", "gluten_free"}}},
+	}}
+	if !reflect.DeepEqual(query, expected) {
+		t.Errorf("Expected %v, got: %v", expected, query)
+	}
+}
+
+func TestFoodFilterQuery_SingleAndEmptyFilters(t *testing.T) {
+	if query := foodFilterQuery(domain.FoodFilter{}); len(query) != 0 {
+		t.Errorf("Expected an empty filter to match every food, got: %v", query)
+	}
+
+	query := foodFilterQuery(domain.FoodFilter{Visibility: "public"})
+	if !reflect.DeepEqual(query, bson.M{"visibility": "public"}) {
+		t.Errorf("Expected a single condition without $and, got: %v", query)
+	}
+}
+
+func TestFoodFilterQuery_AnonymousOnlyMatchesPublic(t *testing.T) {
+	anonymous := primitive.NilObjectID
+	query := foodFilterQuery(domain.FoodFilter{AccessibleTo: &anonymous})
+	if !reflect.DeepEqual(query, bson.M{"visibility": "public"}) {
+		t.Errorf("Expected the nil ID to only match public foods, got: %v", query)
+	}
+}
+
+func TestExcludeDeleted_HidesSoftDeletedFoods(t *testing.T) {
+	query := excludeDeleted(foodFilterQuery(domain.FoodFilter{Visibility: "public"}))
+	expected := bson.M{"visibility": "public", "deletedAt": bson.M{"$exists": false}}
+	if !reflect.DeepEqual(query, expected) {
+		t.Errorf("Expected %v, got: %v", expected, query)
+	}
+}
+
+func TestAfterCursor(t *testing.T) {
+	cursor := &domain.PageCursor{CreatedAt: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC), ID: primitive.NewObjectID()}
+	after := bson.M{"$or": []bson.M{
+		{"createdAt": bson.M{"$lt": cursor.CreatedAt}},
+		{"createdAt": cursor.CreatedAt, "_id": bson.M{"$lt": cursor.ID}},
+	}}
+
+	if query := afterCursor(bson.M{}, nil); len(query) != 0 {
+		t.Errorf("Expected no cursor to leave the query unchanged, got: %v", query)
+	}
+	if query := afterCursor(bson.M{}, cursor); !reflect.DeepEqual(query, after) {
+		t.Errorf("Expected %v, got: %v", after, query)
+	}
+	filter := bson.M{"category": bson.M{"$in": []string{"protein"}}}
+	expected := bson.M{"$and": []bson.M{filter, after}}
+	if query := afterCursor(filter, cursor); !reflect.DeepEqual(query, expected) {
+		t.Errorf("Expected %v, got: %v", expected, query)
+	}
+}
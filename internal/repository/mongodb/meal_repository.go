@@ -11,6 +11,7 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/options"
 
 	"nutrient_be/internal/domain"
+	"nutrient_be/internal/pkg/circuitbreaker"
 )
 
 const (
@@ -19,13 +20,15 @@ const (
 
 // mealTemplateRepository handles meal template data operations
 type mealTemplateRepository struct {
-	collection *mongo.Collection
+	collection *guardedCollection
+	retry      RetryPolicy
 }
 
 // NewMealTemplateRepository creates a new meal template repository
-func NewMealTemplateRepository(db *mongo.Database) *mealTemplateRepository {
+func NewMealTemplateRepository(db *mongo.Database, retry RetryPolicy, breaker *circuitbreaker.Breaker) *mealTemplateRepository {
 	return &mealTemplateRepository{
-		collection: db.Collection(mealTemplateCollection),
+		collection: newGuardedCollection(db, mealTemplateCollection, breaker),
+		retry:      retry,
 	}
 }
 
@@ -44,7 +47,9 @@ func (r *mealTemplateRepository) Create(ctx context.Context, template *domain.Me
 // GetByID retrieves a meal template by ID
 func (r *mealTemplateRepository) GetByID(ctx context.Context, id primitive.ObjectID) (*domain.MealTemplate, error) {
 	var template domain.MealTemplate
-	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&template)
+	err := r.retry.Do(ctx, func(ctx context.Context) error {
+		return r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&template)
+	})
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
 			return nil, fmt.Errorf("meal template not found")
@@ -66,18 +71,85 @@ func (r *mealTemplateRepository) GetByUser(ctx context.Context, userID primitive
 		SetSkip(int64(offset)).
 		SetSort(bson.M{"createdAt": -1})
 
-	cursor, err := r.collection.Find(ctx, filter, opts)
+	var templates []*domain.MealTemplate
+	err := r.retry.Do(ctx, func(ctx context.Context) error {
+		cursor, err := r.collection.Find(ctx, filter, opts)
+		if err != nil {
+			return fmt.Errorf("failed to get meal templates: %w", err)
+		}
+		defer cursor.Close(ctx)
+
+		templates = nil
+		if err := cursor.All(ctx, &templates); err != nil {
+			return fmt.Errorf("failed to decode meal templates: %w", err)
+		}
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to get meal templates: %w", err)
+		return nil, err
 	}
-	defer cursor.Close(ctx)
 
-	var templates []*domain.MealTemplate
-	if err := cursor.All(ctx, &templates); err != nil {
-		return nil, fmt.Errorf("failed to decode meal templates: %w", err)
+	return templates, nil
+}
+
+// CountByUser counts the meal templates of a user, optionally filtered by meal type
+func (r *mealTemplateRepository) CountByUser(ctx context.Context, userID primitive.ObjectID, mealType string) (int64, error) {
+	filter := bson.M{"userId": userID}
+	if mealType != "" {
+		filter["mealType"] = mealType
 	}
 
-	return templates, nil
+	var count int64
+	err := r.retry.Do(ctx, func(ctx context.Context) error {
+		var err error
+		count, err = r.collection.CountDocuments(ctx, filter)
+		if err != nil {
+			return fmt.Errorf("failed to count meal templates: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// TagCounts returns every tag on the user's templates with the number of templates carrying it
+// Tags are sorted by count, most used first, then alphabetically
+func (r *mealTemplateRepository) TagCounts(ctx context.Context, userID primitive.ObjectID) ([]domain.TagCount, error) {
+	pipeline := templateTagCountsPipeline(userID)
+
+	var tags []domain.TagCount
+	err := r.retry.Do(ctx, func(ctx context.Context) error {
+		cursor, err := r.collection.Aggregate(ctx, pipeline)
+		if err != nil {
+			return fmt.Errorf("failed to aggregate meal template tags: %w", err)
+		}
+		defer cursor.Close(ctx)
+
+		tags = nil
+		if err := cursor.All(ctx, &tags); err != nil {
+			return fmt.Errorf("failed to decode meal template tags: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return tags, nil
+}
+
+// templateTagCountsPipeline groups the tags of a user's templates, counting each template once per tag
+func templateTagCountsPipeline(userID primitive.ObjectID) mongo.Pipeline {
+	return mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"userId": userID}}},
+		// Stored tags are normalized and deduplicated, so unwinding counts templates, not occurrences
+		{{Key: "$unwind", Value: "$tags"}},
+		{{Key: "$group", Value: bson.M{"_id": "$tags", "count": bson.M{"$sum": 1}}}},
+		{{Key: "$sort", Value: bson.D{{Key: "count", Value: -1}, {Key: "_id", Value: 1}}}},
+	}
 }
 
 // GetPublicTemplates retrieves public meal templates
@@ -92,15 +164,59 @@ func (r *mealTemplateRepository) GetPublicTemplates(ctx context.Context, mealTyp
 		SetSkip(int64(offset)).
 		SetSort(bson.M{"createdAt": -1})
 
-	cursor, err := r.collection.Find(ctx, filter, opts)
+	var templates []*domain.MealTemplate
+	err := r.retry.Do(ctx, func(ctx context.Context) error {
+		cursor, err := r.collection.Find(ctx, filter, opts)
+		if err != nil {
+			return fmt.Errorf("failed to get public meal templates: %w", err)
+		}
+		defer cursor.Close(ctx)
+
+		templates = nil
+		if err := cursor.All(ctx, &templates); err != nil {
+			return fmt.Errorf("failed to decode meal templates: %w", err)
+		}
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to get public meal templates: %w", err)
+		return nil, err
 	}
-	defer cursor.Close(ctx)
+
+	return templates, nil
+}
+
+// GetWithinCalories retrieves the user's and public meal templates of a meal type whose total calories do not exceed maxCalories
+// Results are sorted by total calories, highest first, so the templates filling most of the budget come first
+func (r *mealTemplateRepository) GetWithinCalories(ctx context.Context, userID primitive.ObjectID, mealType string, maxCalories float64, limit int) ([]*domain.MealTemplate, error) {
+	filter := bson.M{
+		"$or": []bson.M{
+			{"userId": userID},
+			{"isPublic": true},
+		},
+		"mealType":      mealType,
+		"totalCalories": bson.M{"$gt": 0, "$lte": maxCalories},
+	}
+
+	opts := options.Find().
+		SetLimit(int64(limit)).
+		SetSort(bson.D{{Key: "totalCalories", Value: -1}, {Key: "name", Value: 1}})
 
 	var templates []*domain.MealTemplate
-	if err := cursor.All(ctx, &templates); err != nil {
-		return nil, fmt.Errorf("failed to decode meal templates: %w", err)
+	err := r.retry.Do(ctx, func(ctx context.Context) error {
+		cursor, err := r.collection.Find(ctx, filter, opts)
+		if err != nil {
+			return fmt.Errorf("failed to get meal templates within calories: %w", err)
+		}
+		defer cursor.Close(ctx)
+
+		templates = nil
+		if err := cursor.All(ctx, &templates); err != nil {
+			return fmt.Errorf("failed to decode meal templates: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return templates, nil
@@ -128,3 +244,69 @@ func (r *mealTemplateRepository) Delete(ctx context.Context, id primitive.Object
 	}
 	return nil
 }
+
+// DeleteMatching deletes the user's meal templates of mealType carrying any of tags
+// Empty criteria are ignored, so callers must pass at least one; returns the number deleted
+func (r *mealTemplateRepository) DeleteMatching(ctx context.Context, userID primitive.ObjectID, mealType string, tags []string) (int64, error) {
+	filter := bson.M{"userId": userID}
+	if mealType != "" {
+		filter["mealType"] = mealType
+	}
+	if len(tags) > 0 {
+		filter["tags"] = bson.M{"$in": tags}
+	}
+
+	result, err := r.collection.DeleteMany(ctx, filter)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete meal templates: %w", err)
+	}
+	return result.DeletedCount, nil
+}
+
+// DeleteByUser deletes all meal templates owned by a user
+func (r *mealTemplateRepository) DeleteByUser(ctx context.Context, userID primitive.ObjectID) error {
+	_, err := r.collection.DeleteMany(ctx, bson.M{"userId": userID})
+	if err != nil {
+		return fmt.Errorf("failed to delete user meal templates: %w", err)
+	}
+	return nil
+}
+
+// AnonymizeByUser deletes a user's private meal templates and detaches their public ones from the account
+func (r *mealTemplateRepository) AnonymizeByUser(ctx context.Context, userID primitive.ObjectID) error {
+	_, err := r.collection.DeleteMany(ctx, bson.M{"userId": userID, "isPublic": bson.M{"$ne": true}})
+	if err != nil {
+		return fmt.Errorf("failed to delete private user meal templates: %w", err)
+	}
+
+	update := bson.M{"$set": bson.M{"userId": primitive.NilObjectID, "updatedAt": time.Now()}}
+	_, err = r.collection.UpdateMany(ctx, bson.M{"userId": userID}, update)
+	if err != nil {
+		return fmt.Errorf("failed to anonymize user meal templates: %w", err)
+	}
+	return nil
+}
+
+// ReplaceFoodReference points every template food item referencing fromID at toID
+// Returns the number of templates updated
+func (r *mealTemplateRepository) ReplaceFoodReference(ctx context.Context, fromID, toID primitive.ObjectID) (int64, error) {
+	filter := bson.M{"foodItems.foodItemId": fromID}
+	update := bson.M{
+		"$set": bson.M{
+			"foodItems.$[item].foodItemId": toID,
+			"updatedAt":                    time.Now(),
+		},
+	}
+
+	arrayFilters := options.ArrayFilters{
+		Filters: []interface{}{
+			bson.M{"item.foodItemId": fromID},
+		},
+	}
+
+	result, err := r.collection.UpdateMany(ctx, filter, update, options.Update().SetArrayFilters(arrayFilters))
+	if err != nil {
+		return 0, fmt.Errorf("failed to replace food reference in meal templates: %w", err)
+	}
+	return result.ModifiedCount, nil
+}
@@ -0,0 +1,30 @@
+package mongodb
+
+import (
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestTemplateTagCountsPipeline_GroupsTheUsersTags(t *testing.T) {
+	userID := primitive.NewObjectID()
+	pipeline := templateTagCountsPipeline(userID)
+
+	if len(pipeline) != 4 {
+		t.Fatalf("Expected 4 stages, got: %d", len(pipeline))
+	}
+	stages := make([]string, len(pipeline))
+	for i, stage := range pipeline {
+		stages[i] = stage[0].Key
+	}
+	if stages[0] != "$match" || stages[1] != "$unwind" || stages[2] != "$group" || stages[3] != "$sort" {
+		t.Errorf("Expected match, unwind, group and sort stages, got: %v", stages)
+	}
+	if match := pipeline[0][0].Value.(bson.M); match["userId"] != userID {
+		t.Errorf("Expected the match stage to select the user's templates, got: %v", match)
+	}
+	if group := pipeline[2][0].Value.(bson.M); group["_id"] != "$tags" {
+		t.Errorf("Expected tags to be grouped by value, got: %v", group["_id"])
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/options"
 
 	"nutrient_be/internal/domain"
+	"nutrient_be/internal/pkg/circuitbreaker"
 )
 
 const (
@@ -19,13 +20,15 @@ const (
 
 // mealPlanRepository handles meal plan data operations
 type mealPlanRepository struct {
-	collection *mongo.Collection
+	collection *guardedCollection
+	retry      RetryPolicy
 }
 
 // NewMealPlanRepository creates a new meal plan repository
-func NewMealPlanRepository(db *mongo.Database) *mealPlanRepository {
+func NewMealPlanRepository(db *mongo.Database, retry RetryPolicy, breaker *circuitbreaker.Breaker) *mealPlanRepository {
 	return &mealPlanRepository{
-		collection: db.Collection(mealPlanCollection),
+		collection: newGuardedCollection(db, mealPlanCollection, breaker),
+		retry:      retry,
 	}
 }
 
@@ -44,7 +47,9 @@ func (r *mealPlanRepository) Create(ctx context.Context, plan *domain.MealPlan)
 // GetByID retrieves a meal plan by ID
 func (r *mealPlanRepository) GetByID(ctx context.Context, id primitive.ObjectID) (*domain.MealPlan, error) {
 	var plan domain.MealPlan
-	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&plan)
+	err := r.retry.Do(ctx, func(ctx context.Context) error {
+		return r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&plan)
+	})
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
 			return nil, fmt.Errorf("meal plan not found")
@@ -54,60 +59,109 @@ func (r *mealPlanRepository) GetByID(ctx context.Context, id primitive.ObjectID)
 	return &plan, nil
 }
 
-// GetByUser retrieves meal plans by user
-func (r *mealPlanRepository) GetByUser(ctx context.Context, userID primitive.ObjectID, planType string, limit, offset int) ([]*domain.MealPlan, error) {
+// GetByUser retrieves meal plans by user, optionally filtered by plan type and status
+func (r *mealPlanRepository) GetByUser(ctx context.Context, userID primitive.ObjectID, planType, status string, limit, offset int) ([]*domain.MealPlan, error) {
 	filter := bson.M{"userId": userID}
 	if planType != "" {
 		filter["planType"] = planType
 	}
+	if status != "" {
+		filter["status"] = status
+	}
 
 	opts := options.Find().
 		SetLimit(int64(limit)).
 		SetSkip(int64(offset)).
 		SetSort(bson.M{"startDate": -1})
 
-	cursor, err := r.collection.Find(ctx, filter, opts)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get meal plans: %w", err)
-	}
-	defer cursor.Close(ctx)
-
 	var plans []*domain.MealPlan
-	if err := cursor.All(ctx, &plans); err != nil {
-		return nil, fmt.Errorf("failed to decode meal plans: %w", err)
+	err := r.retry.Do(ctx, func(ctx context.Context) error {
+		cursor, err := r.collection.Find(ctx, filter, opts)
+		if err != nil {
+			return fmt.Errorf("failed to get meal plans: %w", err)
+		}
+		defer cursor.Close(ctx)
+
+		plans = nil
+		if err := cursor.All(ctx, &plans); err != nil {
+			return fmt.Errorf("failed to decode meal plans: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return plans, nil
 }
 
-// GetByUserAndDateRange retrieves meal plans by user and date range
-func (r *mealPlanRepository) GetByUserAndDateRange(ctx context.Context, userID primitive.ObjectID, startDate, endDate string) ([]*domain.MealPlan, error) {
-	filter := bson.M{
-		"userId": userID,
-		"$or": []bson.M{
-			{
-				"startDate": bson.M{"$lte": endDate},
-				"endDate":   bson.M{"$gte": startDate},
-			},
-		},
+// CountByUser counts the meal plans of a user, optionally filtered by plan type and status
+func (r *mealPlanRepository) CountByUser(ctx context.Context, userID primitive.ObjectID, planType, status string) (int64, error) {
+	filter := bson.M{"userId": userID}
+	if planType != "" {
+		filter["planType"] = planType
+	}
+	if status != "" {
+		filter["status"] = status
 	}
 
-	opts := options.Find().SetSort(bson.M{"startDate": 1})
-
-	cursor, err := r.collection.Find(ctx, filter, opts)
+	var count int64
+	err := r.retry.Do(ctx, func(ctx context.Context) error {
+		var err error
+		count, err = r.collection.CountDocuments(ctx, filter)
+		if err != nil {
+			return fmt.Errorf("failed to count meal plans: %w", err)
+		}
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to get meal plans by date range: %w", err)
+		return 0, err
 	}
-	defer cursor.Close(ctx)
+
+	return count, nil
+}
+
+// GetByUserAndDateRange retrieves meal plans by user overlapping the days from startDate to endDate, both inclusive
+func (r *mealPlanRepository) GetByUserAndDateRange(ctx context.Context, userID primitive.ObjectID, startDate, endDate time.Time) ([]*domain.MealPlan, error) {
+	filter := mealPlanDateRangeFilter(userID, startDate, endDate)
+
+	opts := options.Find().SetSort(bson.M{"startDate": 1})
 
 	var plans []*domain.MealPlan
-	if err := cursor.All(ctx, &plans); err != nil {
-		return nil, fmt.Errorf("failed to decode meal plans: %w", err)
+	err := r.retry.Do(ctx, func(ctx context.Context) error {
+		cursor, err := r.collection.Find(ctx, filter, opts)
+		if err != nil {
+			return fmt.Errorf("failed to get meal plans by date range: %w", err)
+		}
+		defer cursor.Close(ctx)
+
+		plans = nil
+		if err := cursor.All(ctx, &plans); err != nil {
+			return fmt.Errorf("failed to decode meal plans: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return plans, nil
 }
 
+// mealPlanDateRangeFilter matches the user's plans overlapping the days from start to end, both inclusive
+// Plan dates are stored as BSON dates, so a plan overlaps when it starts before the day after end and ends on or after the day of start
+func mealPlanDateRangeFilter(userID primitive.ObjectID, start, end time.Time) bson.M {
+	start, end = start.UTC(), end.UTC()
+	from := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC)
+	until := time.Date(end.Year(), end.Month(), end.Day()+1, 0, 0, 0, 0, time.UTC)
+
+	return bson.M{
+		"userId":    userID,
+		"startDate": bson.M{"$lt": until},
+		"endDate":   bson.M{"$gte": from},
+	}
+}
+
 // Update updates a meal plan
 func (r *mealPlanRepository) Update(ctx context.Context, plan *domain.MealPlan) error {
 	plan.UpdatedAt = time.Now()
@@ -131,6 +185,15 @@ func (r *mealPlanRepository) Delete(ctx context.Context, id primitive.ObjectID)
 	return nil
 }
 
+// DeleteByUser deletes all meal plans owned by a user
+func (r *mealPlanRepository) DeleteByUser(ctx context.Context, userID primitive.ObjectID) error {
+	_, err := r.collection.DeleteMany(ctx, bson.M{"userId": userID})
+	if err != nil {
+		return fmt.Errorf("failed to delete user meal plans: %w", err)
+	}
+	return nil
+}
+
 // UpdateMealCompletion updates meal completion status
 func (r *mealPlanRepository) UpdateMealCompletion(ctx context.Context, planID primitive.ObjectID, mealID string, isCompleted bool) error {
 	filter := bson.M{
@@ -158,3 +221,27 @@ func (r *mealPlanRepository) UpdateMealCompletion(ctx context.Context, planID pr
 	}
 	return nil
 }
+
+// ReplaceFoodReference points every planned meal food item referencing fromID at toID
+// Returns the number of meal plans updated
+func (r *mealPlanRepository) ReplaceFoodReference(ctx context.Context, fromID, toID primitive.ObjectID) (int64, error) {
+	filter := bson.M{"dailyMeals.meals.foodItems.foodItemId": fromID}
+	update := bson.M{
+		"$set": bson.M{
+			"dailyMeals.$[].meals.$[].foodItems.$[item].foodItemId": toID,
+			"updatedAt": time.Now(),
+		},
+	}
+
+	arrayFilters := options.ArrayFilters{
+		Filters: []interface{}{
+			bson.M{"item.foodItemId": fromID},
+		},
+	}
+
+	result, err := r.collection.UpdateMany(ctx, filter, update, options.Update().SetArrayFilters(arrayFilters))
+	if err != nil {
+		return 0, fmt.Errorf("failed to replace food reference in meal plans: %w", err)
+	}
+	return result.ModifiedCount, nil
+}
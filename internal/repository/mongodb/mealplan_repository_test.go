@@ -0,0 +1,58 @@
+package mongodb
+
+import (
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"nutrient_be/internal/domain"
+)
+
+// matchesDateRangeFilter applies the date bounds of a filter to a plan the way MongoDB compares BSON dates
+func matchesDateRangeFilter(t *testing.T, filter bson.M, plan *domain.MealPlan) bool {
+	t.Helper()
+	startBound, ok := filter["startDate"].(bson.M)["$lt"].(time.Time)
+	if !ok {
+		t.Fatalf("Expected startDate to be compared with a time, got: %T", filter["startDate"].(bson.M)["$lt"])
+	}
+	endBound, ok := filter["endDate"].(bson.M)["$gte"].(time.Time)
+	if !ok {
+		t.Fatalf("Expected endDate to be compared with a time, got: %T", filter["endDate"].(bson.M)["$gte"])
+	}
+	return filter["userId"] == plan.UserID && plan.StartDate.Before(startBound) && !plan.EndDate.Before(endBound)
+}
+
+func TestMealPlanDateRangeFilter_MatchesOnlyOverlappingPlans(t *testing.T) {
+	userID := primitive.NewObjectID()
+	day := func(d, hour int) time.Time { return time.Date(2024, 6, d, hour, 0, 0, 0, time.UTC) }
+	plan := func(start, end time.Time) *domain.MealPlan {
+		return &domain.MealPlan{UserID: userID, StartDate: start, EndDate: end}
+	}
+
+	tests := []struct {
+		name     string
+		plan     *domain.MealPlan
+		expected bool
+	}{
+		{"covers the whole range", plan(day(1, 0), day(30, 0)), true},
+		{"starts inside the range", plan(day(12, 0), day(20, 0)), true},
+		{"ends inside the range", plan(day(5, 0), day(10, 0)), true},
+		{"starts later on the last day", plan(day(14, 18), day(21, 0)), true},
+		{"ends on the first day", plan(day(1, 0), day(10, 0)), true},
+		{"ends the day before", plan(day(1, 0), day(9, 23)), false},
+		{"starts the day after", plan(day(15, 0), day(21, 0)), false},
+		{"other user", &domain.MealPlan{UserID: primitive.NewObjectID(), StartDate: day(1, 0), EndDate: day(30, 0)}, false},
+	}
+
+	// The bounds are whole days even when the range is given mid-day
+	filter := mealPlanDateRangeFilter(userID, day(10, 15), day(14, 8))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := matchesDateRangeFilter(t, filter, tt.plan); got != tt.expected {
+				t.Errorf("Expected match %v, got: %v", tt.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// notPrimaryErrorCodes are server error codes returned while a replica set is electing or stepping down
+var notPrimaryErrorCodes = []int{
+	91,    // ShutdownInProgress
+	189,   // PrimarySteppedDown
+	10107, // NotWritablePrimary
+	11600, // InterruptedAtShutdown
+	11602, // InterruptedDueToReplStateChange
+	13435, // NotPrimaryNoSecondaryOk
+	13436, // NotPrimaryOrSecondary
+}
+
+// RetryPolicy controls how read operations are retried on transient errors
+// Writes are never retried by the repositories since they are not all idempotent
+type RetryPolicy struct {
+	MaxAttempts    int           // Total attempts including the first; values below 1 mean a single attempt
+	InitialBackoff time.Duration // Wait before the first retry, doubled on each further retry
+	MaxBackoff     time.Duration // Upper bound for a single wait
+}
+
+// Do runs op, retrying retryable errors with exponential backoff
+// It stops as soon as ctx is done and returns the last error from op
+func (p RetryPolicy) Do(ctx context.Context, op func(ctx context.Context) error) error {
+	backoff := p.InitialBackoff
+	for attempt := 1; ; attempt++ {
+		err := op(ctx)
+		if err == nil || attempt >= p.MaxAttempts || !isRetryableError(err) {
+			return err
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+
+		backoff *= 2
+		if p.MaxBackoff > 0 && backoff > p.MaxBackoff {
+			backoff = p.MaxBackoff
+		}
+	}
+}
+
+// isRetryableError reports whether err is a transient network or primary election failure
+func isRetryableError(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if mongo.IsNetworkError(err) {
+		return true
+	}
+
+	var serverErr mongo.ServerError
+	if errors.As(err, &serverErr) {
+		if serverErr.HasErrorLabel("RetryableReadError") {
+			return true
+		}
+		for _, code := range notPrimaryErrorCodes {
+			if serverErr.HasErrorCode(code) {
+				return true
+			}
+		}
+	}
+	return false
+}
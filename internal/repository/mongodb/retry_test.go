@@ -0,0 +1,105 @@
+package mongodb
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var errNetwork = mongo.CommandError{Code: 6, Message: "connection reset", Labels: []string{"NetworkError"}}
+
+func TestRetryPolicy_Do_SucceedsOnSecondAttempt(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: 5 * time.Millisecond}
+
+	attempts := 0
+	err := policy.Do(context.Background(), func(ctx context.Context) error {
+		attempts++
+		if attempts == 1 {
+			return errNetwork
+		}
+		return nil
+	})
+
+	if err != nil {
+		t.Fatalf("Expected success, got: %v", err)
+	}
+	if attempts != 2 {
+		t.Errorf("Expected 2 attempts, got: %d", attempts)
+	}
+}
+
+func TestRetryPolicy_Do_StopsWhenContextCancelled(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 10, InitialBackoff: time.Hour, MaxBackoff: time.Hour}
+	ctx, cancel := context.WithCancel(context.Background())
+
+	attempts := 0
+	done := make(chan error, 1)
+	go func() {
+		done <- policy.Do(ctx, func(ctx context.Context) error {
+			attempts++
+			return errNetwork
+		})
+	}()
+	cancel()
+
+	select {
+	case err := <-done:
+		if err == nil || err.Error() != errNetwork.Error() {
+			t.Errorf("Expected last operation error, got: %v", err)
+		}
+		if attempts != 1 {
+			t.Errorf("Expected 1 attempt, got: %d", attempts)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected retry loop to stop after cancellation")
+	}
+}
+
+func TestRetryPolicy_Do_DoesNotRetryPermanentErrors(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond}
+
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{name: "no documents", err: mongo.ErrNoDocuments},
+		{name: "duplicate key", err: mongo.CommandError{Code: 11000, Message: "duplicate key"}},
+		{name: "deadline exceeded", err: context.DeadlineExceeded},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attempts := 0
+			err := policy.Do(context.Background(), func(ctx context.Context) error {
+				attempts++
+				return tt.err
+			})
+			if err == nil || err.Error() != tt.err.Error() {
+				t.Errorf("Expected %v, got: %v", tt.err, err)
+			}
+			if attempts != 1 {
+				t.Errorf("Expected 1 attempt, got: %d", attempts)
+			}
+		})
+	}
+}
+
+func TestRetryPolicy_Do_RetriesNotPrimaryUntilMaxAttempts(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond}
+	notPrimary := mongo.CommandError{Code: 10107, Message: "not primary"}
+
+	attempts := 0
+	err := policy.Do(context.Background(), func(ctx context.Context) error {
+		attempts++
+		return notPrimary
+	})
+
+	if err == nil {
+		t.Fatal("Expected error after exhausting attempts")
+	}
+	if attempts != 3 {
+		t.Errorf("Expected 3 attempts, got: %d", attempts)
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/options"
 
 	"nutrient_be/internal/domain"
+	"nutrient_be/internal/pkg/circuitbreaker"
 )
 
 const (
@@ -19,13 +20,15 @@ const (
 
 // shoppingListRepository handles shopping list data operations
 type shoppingListRepository struct {
-	collection *mongo.Collection
+	collection *guardedCollection
+	retry      RetryPolicy
 }
 
 // NewShoppingListRepository creates a new shopping list repository
-func NewShoppingListRepository(db *mongo.Database) *shoppingListRepository {
+func NewShoppingListRepository(db *mongo.Database, retry RetryPolicy, breaker *circuitbreaker.Breaker) *shoppingListRepository {
 	return &shoppingListRepository{
-		collection: db.Collection(shoppingListCollection),
+		collection: newGuardedCollection(db, shoppingListCollection, breaker),
+		retry:      retry,
 	}
 }
 
@@ -44,7 +47,9 @@ func (r *shoppingListRepository) Create(ctx context.Context, list *domain.Shoppi
 // GetByID retrieves a shopping list by ID
 func (r *shoppingListRepository) GetByID(ctx context.Context, id primitive.ObjectID) (*domain.ShoppingList, error) {
 	var list domain.ShoppingList
-	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&list)
+	err := r.retry.Do(ctx, func(ctx context.Context) error {
+		return r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&list)
+	})
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
 			return nil, fmt.Errorf("shopping list not found")
@@ -54,24 +59,34 @@ func (r *shoppingListRepository) GetByID(ctx context.Context, id primitive.Objec
 	return &list, nil
 }
 
-// GetByUser retrieves shopping lists by user
-func (r *shoppingListRepository) GetByUser(ctx context.Context, userID primitive.ObjectID, limit, offset int) ([]*domain.ShoppingList, error) {
+// GetByUser retrieves shopping lists by user, optionally filtered by status
+func (r *shoppingListRepository) GetByUser(ctx context.Context, userID primitive.ObjectID, status string, limit, offset int) ([]*domain.ShoppingList, error) {
 	filter := bson.M{"userId": userID}
+	if status != "" {
+		filter["status"] = status
+	}
 
 	opts := options.Find().
 		SetLimit(int64(limit)).
 		SetSkip(int64(offset)).
 		SetSort(bson.M{"createdAt": -1})
 
-	cursor, err := r.collection.Find(ctx, filter, opts)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get shopping lists: %w", err)
-	}
-	defer cursor.Close(ctx)
-
 	var lists []*domain.ShoppingList
-	if err := cursor.All(ctx, &lists); err != nil {
-		return nil, fmt.Errorf("failed to decode shopping lists: %w", err)
+	err := r.retry.Do(ctx, func(ctx context.Context) error {
+		cursor, err := r.collection.Find(ctx, filter, opts)
+		if err != nil {
+			return fmt.Errorf("failed to get shopping lists: %w", err)
+		}
+		defer cursor.Close(ctx)
+
+		lists = nil
+		if err := cursor.All(ctx, &lists); err != nil {
+			return fmt.Errorf("failed to decode shopping lists: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return lists, nil
@@ -80,7 +95,9 @@ func (r *shoppingListRepository) GetByUser(ctx context.Context, userID primitive
 // GetByMealPlan retrieves a shopping list by meal plan ID
 func (r *shoppingListRepository) GetByMealPlan(ctx context.Context, mealPlanID primitive.ObjectID) (*domain.ShoppingList, error) {
 	var list domain.ShoppingList
-	err := r.collection.FindOne(ctx, bson.M{"mealPlanId": mealPlanID}).Decode(&list)
+	err := r.retry.Do(ctx, func(ctx context.Context) error {
+		return r.collection.FindOne(ctx, bson.M{"mealPlanId": mealPlanID}).Decode(&list)
+	})
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
 			return nil, fmt.Errorf("shopping list not found")
@@ -113,11 +130,29 @@ func (r *shoppingListRepository) Delete(ctx context.Context, id primitive.Object
 	return nil
 }
 
+// DeleteByUser deletes all shopping lists owned by a user
+func (r *shoppingListRepository) DeleteByUser(ctx context.Context, userID primitive.ObjectID) error {
+	_, err := r.collection.DeleteMany(ctx, bson.M{"userId": userID})
+	if err != nil {
+		return fmt.Errorf("failed to delete user shopping lists: %w", err)
+	}
+	return nil
+}
+
+// DeleteByMealPlan deletes all shopping lists generated from a meal plan
+func (r *shoppingListRepository) DeleteByMealPlan(ctx context.Context, mealPlanID primitive.ObjectID) error {
+	_, err := r.collection.DeleteMany(ctx, bson.M{"mealPlanId": mealPlanID})
+	if err != nil {
+		return fmt.Errorf("failed to delete meal plan shopping lists: %w", err)
+	}
+	return nil
+}
+
 // ToggleItemChecked toggles the checked status of a shopping list item
 func (r *shoppingListRepository) ToggleItemChecked(ctx context.Context, listID primitive.ObjectID, itemID primitive.ObjectID, checked bool) error {
 	filter := bson.M{
-		"_id":              listID,
-		"items.foodItemId": itemID,
+		"_id":      listID,
+		"items.id": itemID,
 	}
 
 	update := bson.M{
@@ -133,3 +168,37 @@ func (r *shoppingListRepository) ToggleItemChecked(ctx context.Context, listID p
 	}
 	return nil
 }
+
+// ToggleItemsChecked sets the checked status of several shopping list items in a single update
+// Items to check and to uncheck are matched through separate array filters
+func (r *shoppingListRepository) ToggleItemsChecked(ctx context.Context, listID primitive.ObjectID, checked map[primitive.ObjectID]bool) error {
+	var checkIDs, uncheckIDs []primitive.ObjectID
+	for itemID, isChecked := range checked {
+		if isChecked {
+			checkIDs = append(checkIDs, itemID)
+		} else {
+			uncheckIDs = append(uncheckIDs, itemID)
+		}
+	}
+
+	set := bson.M{"updatedAt": time.Now()}
+	var filters []interface{}
+	if len(checkIDs) > 0 {
+		set["items.$[check].checked"] = true
+		filters = append(filters, bson.M{"check.id": bson.M{"$in": checkIDs}})
+	}
+	if len(uncheckIDs) > 0 {
+		set["items.$[uncheck].checked"] = false
+		filters = append(filters, bson.M{"uncheck.id": bson.M{"$in": uncheckIDs}})
+	}
+	if len(filters) == 0 {
+		return nil
+	}
+
+	opts := options.Update().SetArrayFilters(options.ArrayFilters{Filters: filters})
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": listID}, bson.M{"$set": set}, opts)
+	if err != nil {
+		return fmt.Errorf("failed to toggle items checked status: %w", err)
+	}
+	return nil
+}
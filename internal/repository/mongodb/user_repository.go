@@ -10,17 +10,21 @@ import (
 	"go.mongodb.org/mongo-driver/mongo"
 
 	"nutrient_be/internal/domain"
+	"nutrient_be/internal/dto/request"
+	"nutrient_be/internal/pkg/circuitbreaker"
 )
 
 // userRepository handles user data operations
 type userRepository struct {
-	collection *mongo.Collection
+	collection *guardedCollection
+	retry      RetryPolicy
 }
 
 // NewUserRepository creates a new user repository
-func NewUserRepository(db *mongo.Database) *userRepository {
+func NewUserRepository(db *mongo.Database, retry RetryPolicy, breaker *circuitbreaker.Breaker) *userRepository {
 	return &userRepository{
-		collection: db.Collection("users"),
+		collection: newGuardedCollection(db, "users", breaker),
+		retry:      retry,
 	}
 }
 
@@ -39,7 +43,9 @@ func (r *userRepository) Create(ctx context.Context, user *domain.User) error {
 // GetByID retrieves a user by ID
 func (r *userRepository) GetByID(ctx context.Context, id primitive.ObjectID) (*domain.User, error) {
 	var user domain.User
-	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&user)
+	err := r.retry.Do(ctx, func(ctx context.Context) error {
+		return r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&user)
+	})
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
 			return nil, fmt.Errorf("user not found")
@@ -50,9 +56,13 @@ func (r *userRepository) GetByID(ctx context.Context, id primitive.ObjectID) (*d
 }
 
 // GetByEmail retrieves a user by email
+// Emails are stored normalized, so email is normalized the same way whatever form the caller passes
 func (r *userRepository) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
+	email = request.NormalizeEmail(email)
 	var user domain.User
-	err := r.collection.FindOne(ctx, bson.M{"email": email}).Decode(&user)
+	err := r.retry.Do(ctx, func(ctx context.Context) error {
+		return r.collection.FindOne(ctx, bson.M{"email": email}).Decode(&user)
+	})
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
 			return nil, fmt.Errorf("user not found")
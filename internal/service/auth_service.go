@@ -2,7 +2,9 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -13,6 +15,7 @@ import (
 	"nutrient_be/internal/domain"
 	"nutrient_be/internal/dto/request"
 	"nutrient_be/internal/dto/response"
+	"nutrient_be/internal/pkg/jwtkeys"
 	"nutrient_be/internal/pkg/logger"
 )
 
@@ -27,20 +30,45 @@ type UserRepository interface {
 
 // AuthService handles authentication operations
 type AuthService struct {
-	userRepo UserRepository
-	config   config.AuthConfig
-	logger   logger.Logger
+	userRepo        UserRepository
+	tokenKeys       *jwtkeys.Keys
+	config          config.AuthConfig
+	userConfig      config.UserConfig
+	logger          logger.Logger
+	comparePassword func(hash, password []byte) error
 }
 
 // NewAuthService creates a new auth service
-func NewAuthService(userRepo UserRepository, cfg config.AuthConfig, log logger.Logger) *AuthService {
+// Tokens are signed and verified with tokenKeys
+func NewAuthService(userRepo UserRepository, tokenKeys *jwtkeys.Keys, cfg config.AuthConfig, userCfg config.UserConfig, log logger.Logger) *AuthService {
 	return &AuthService{
-		userRepo: userRepo,
-		config:   cfg,
-		logger:   log,
+		userRepo:        userRepo,
+		tokenKeys:       tokenKeys,
+		config:          cfg,
+		userConfig:      userCfg,
+		logger:          log,
+		comparePassword: bcrypt.CompareHashAndPassword,
 	}
 }
 
+var (
+	dummyPasswordHashOnce sync.Once
+	dummyPasswordHash     []byte
+)
+
+// getDummyPasswordHash returns a bcrypt hash with the same cost as real password hashes
+// Login compares against it when the email is unknown, so both paths take as long
+func getDummyPasswordHash() []byte {
+	dummyPasswordHashOnce.Do(func() {
+		hash, err := bcrypt.GenerateFromPassword([]byte("dummy-password-for-timing"), bcrypt.DefaultCost)
+		if err != nil {
+			panic(fmt.Sprintf("failed to generate dummy password hash: %v", err))
+		}
+		dummyPasswordHash = hash
+	})
+	return dummyPasswordHash
+}
+
 // LoginRequest is now in internal/dto/request/auth.go
 
 // AuthResponse represents an authentication response
@@ -51,13 +79,19 @@ type AuthResponse struct {
 	ExpiresAt    time.Time              `json:"expiresAt"`
 }
 
-// Register registers a new user (email and password only)
-// Profile should be set separately via user service
+// ErrDuplicateEmail is returned by Register when the email is already registered
+var ErrDuplicateEmail = errors.New("email already registered")
+
+// Register registers a new user
+// Profile fields are optional; missing goal and activity level fall back to the configured defaults
 func (s *AuthService) Register(ctx context.Context, req *request.RegisterRequest) (*AuthResponse, error) {
+	// Emails are stored normalized so differently cased addresses are one account
+	email := request.NormalizeEmail(req.Email)
+
 	// Check if user already exists
-	existingUser, err := s.userRepo.GetByEmail(ctx, req.Email)
+	existingUser, err := s.userRepo.GetByEmail(ctx, email)
 	if err == nil && existingUser != nil {
-		return nil, fmt.Errorf("user with email %s already exists", req.Email)
+		return nil, fmt.Errorf("user with email %s already exists: %w", email, ErrDuplicateEmail)
 	}
 
 	// Hash password
@@ -66,20 +100,38 @@ func (s *AuthService) Register(ctx context.Context, req *request.RegisterRequest
 		return nil, fmt.Errorf("failed to hash password: %w", err)
 	}
 
-	// Create user with default profile
+	// Create user with the provided profile and configured defaults
 	user := &domain.User{
 		ID:           primitive.NewObjectID(),
-		Email:        req.Email,
+		Email:        email,
 		PasswordHash: string(hashedPassword),
-		Profile:      domain.UserProfile{
-			// Default empty profile - user should set it via user service
-		},
+		Profile:      s.buildInitialProfile(req),
 		Preferences: domain.UserPreferences{
 			Language:      "en",
-			CalorieTarget: 0, // Will be calculated when profile is set
+			CalorieTarget: 0, // Will be calculated when profile is complete
 			MacroTargets:  domain.MacroNutrients{},
+			WeekStart:     "monday",
 		},
 	}
+	if user.Profile.Weight > 0 {
+		user.WeightHistory = []domain.WeightEntry{{Weight: user.Profile.Weight, Date: time.Now().UTC()}}
+	}
+
+	// Calculate initial targets when enough profile data was provided
+	profile := user.Profile
+	if profile.Weight > 0 && profile.Height > 0 && profile.Age > 0 {
+		calorieTarget := calculateCalorieTarget(
+			profile.Weight,
+			profile.Height,
+			profile.Age,
+			profile.Gender,
+			profile.Goal,
+			profile.ActivityLevel,
+		)
+		user.Preferences.CalorieTarget = clampCalorieTarget(ctx, s.logger, s.userConfig, calorieTarget, profile.Gender)
+		user.Preferences.MacroTargets = calculateMacroTargets(s.userConfig, profile.Goal, user.Preferences.CalorieTarget, profile.Weight)
+		user.Preferences.MacroTargetsUnit = macroTargetsUnit(s.userConfig)
+	}
 
 	// Save user
 	if err := s.userRepo.Create(ctx, user); err != nil {
@@ -93,7 +145,7 @@ func (s *AuthService) Register(ctx context.Context, req *request.RegisterRequest
 	}
 
 	s.logger.Info(ctx, "User registered successfully",
-		logger.String("email", req.Email),
+		logger.String("email", email),
 		logger.String("userID", user.ID.Hex()))
 
 	// Convert to response
@@ -107,16 +159,45 @@ func (s *AuthService) Register(ctx context.Context, req *request.RegisterRequest
 	}, nil
 }
 
+// buildInitialProfile builds the profile of a new user from the registration request
+// Goal and activity level default to the values configured under user.*
+func (s *AuthService) buildInitialProfile(req *request.RegisterRequest) domain.UserProfile {
+	profile := domain.UserProfile{
+		Name:          req.Name,
+		Gender:        req.Gender,
+		Goal:          req.Goal,
+		ActivityLevel: req.ActivityLevel,
+	}
+	if req.Age != nil {
+		profile.Age = *req.Age
+	}
+	if req.Weight != nil {
+		profile.Weight = *req.Weight
+	}
+	if req.Height != nil {
+		profile.Height = *req.Height
+	}
+	if profile.Goal == "" {
+		profile.Goal = s.userConfig.DefaultGoal
+	}
+	if profile.ActivityLevel == "" {
+		profile.ActivityLevel = s.userConfig.DefaultActivityLevel
+	}
+	return profile
+}
+
 // Login authenticates a user
 func (s *AuthService) Login(ctx context.Context, req *request.LoginRequest) (*AuthResponse, error) {
-	// Get user by email
-	user, err := s.userRepo.GetByEmail(ctx, req.Email)
+	// Get user by email, in the normalized form it was stored in
+	user, err := s.userRepo.GetByEmail(ctx, request.NormalizeEmail(req.Email))
 	if err != nil {
+		// Still run a bcrypt comparison so response timing does not reveal whether the account exists
+		_ = s.comparePassword(getDummyPasswordHash(), []byte(req.Password))
 		return nil, fmt.Errorf("invalid credentials")
 	}
 
 	// Check password
-	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.Password)); err != nil {
+	if err := s.comparePassword([]byte(user.PasswordHash), []byte(req.Password)); err != nil {
 		return nil, fmt.Errorf("invalid credentials")
 	}
 
@@ -127,7 +208,7 @@ func (s *AuthService) Login(ctx context.Context, req *request.LoginRequest) (*Au
 	}
 
 	s.logger.Info(ctx, "User logged in successfully",
-		logger.String("email", req.Email),
+		logger.String("email", user.Email),
 		logger.String("userID", user.ID.Hex()))
 
 	// Convert to response
@@ -144,13 +225,7 @@ func (s *AuthService) Login(ctx context.Context, req *request.LoginRequest) (*Au
 // RefreshToken refreshes an access token
 func (s *AuthService) RefreshToken(ctx context.Context, refreshToken string) (*AuthResponse, error) {
 	// Parse and validate refresh token
-	token, err := jwt.Parse(refreshToken, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-		return []byte(s.config.JWTSecret), nil
-	})
-
+	token, err := s.tokenKeys.Parse(refreshToken)
 	if err != nil || !token.Valid {
 		return nil, fmt.Errorf("invalid refresh token")
 	}
@@ -206,8 +281,7 @@ func (s *AuthService) generateTokens(userID string) (string, string, time.Time,
 		"type":    "access",
 	}
 
-	accessToken := jwt.NewWithClaims(jwt.SigningMethodHS256, accessClaims)
-	accessTokenString, err := accessToken.SignedString([]byte(s.config.JWTSecret))
+	accessTokenString, err := s.tokenKeys.Sign(accessClaims)
 	if err != nil {
 		return "", "", time.Time{}, err
 	}
@@ -221,8 +295,7 @@ func (s *AuthService) generateTokens(userID string) (string, string, time.Time,
 		"type":    "refresh",
 	}
 
-	refreshToken := jwt.NewWithClaims(jwt.SigningMethodHS256, refreshClaims)
-	refreshTokenString, err := refreshToken.SignedString([]byte(s.config.JWTSecret))
+	refreshTokenString, err := s.tokenKeys.Sign(refreshClaims)
 	if err != nil {
 		return "", "", time.Time{}, err
 	}
@@ -232,13 +305,7 @@ func (s *AuthService) generateTokens(userID string) (string, string, time.Time,
 
 // ValidateToken validates a JWT token and returns user ID
 func (s *AuthService) ValidateToken(ctx context.Context, tokenString string) (string, error) {
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-		return []byte(s.config.JWTSecret), nil
-	})
-
+	token, err := s.tokenKeys.Parse(tokenString)
 	if err != nil || !token.Valid {
 		return "", fmt.Errorf("invalid token")
 	}
@@ -268,12 +335,13 @@ func domainUserToResponse(user *domain.User) *response.UserResponse {
 		ID:    user.ID.Hex(),
 		Email: user.Email,
 		Profile: response.UserProfileResponse{
-			Name:   user.Profile.Name,
-			Age:    user.Profile.Age,
-			Weight: user.Profile.Weight,
-			Height: user.Profile.Height,
-			Gender: user.Profile.Gender,
-			Goal:   user.Profile.Goal,
+			Name:          user.Profile.Name,
+			Age:           user.Profile.Age,
+			Weight:        user.Profile.Weight,
+			Height:        user.Profile.Height,
+			Gender:        user.Profile.Gender,
+			Goal:          user.Profile.Goal,
+			ActivityLevel: user.Profile.ActivityLevel,
 		},
 		Preferences: response.UserPreferencesResponse{
 			Language:      user.Preferences.Language,
@@ -285,6 +353,22 @@ func domainUserToResponse(user *domain.User) *response.UserResponse {
 				Fiber:         user.Preferences.MacroTargets.Fiber,
 				Sugar:         user.Preferences.MacroTargets.Sugar,
 			},
+			MacroTargetsUnit: user.Preferences.MacroTargetsUnit,
+			WeekStart:        weekStartOrDefault(user.Preferences.WeekStart),
+			DailyValues: response.DailyValuesResponse{
+				Calories:      user.Preferences.DailyValues.Calories,
+				Protein:       user.Preferences.DailyValues.Protein,
+				Carbohydrates: user.Preferences.DailyValues.Carbohydrates,
+				Fat:           user.Preferences.DailyValues.Fat,
+				Fiber:         user.Preferences.DailyValues.Fiber,
+				Sugar:         user.Preferences.DailyValues.Sugar,
+				VitaminA:      user.Preferences.DailyValues.VitaminA,
+				VitaminC:      user.Preferences.DailyValues.VitaminC,
+				Calcium:       user.Preferences.DailyValues.Calcium,
+				Iron:          user.Preferences.DailyValues.Iron,
+				Sodium:        user.Preferences.DailyValues.Sodium,
+				Potassium:     user.Preferences.DailyValues.Potassium,
+			},
 		},
 		CreatedAt: user.CreatedAt,
 		UpdatedAt: user.UpdatedAt,
@@ -0,0 +1,146 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"nutrient_be/internal/config"
+	"nutrient_be/internal/dto/request"
+	"nutrient_be/internal/pkg/jwtkeys"
+	"nutrient_be/internal/pkg/logger"
+)
+
+func newTestAuthService(repo UserRepository) *AuthService {
+	authCfg := config.AuthConfig{
+		JWTSecret:         "test-secret",
+		JWTExpiration:     time.Hour,
+		RefreshExpiration: 24 * time.Hour,
+	}
+	userCfg := config.UserConfig{
+		DefaultGoal:          "maintenance",
+		DefaultActivityLevel: "sedentary",
+	}
+	return NewAuthService(repo, jwtkeys.NewHS256Keys(authCfg.JWTSecret), authCfg, userCfg, logger.NewNoopLogger())
+}
+
+func TestAuthService_Register_WithProfile(t *testing.T) {
+	svc := newTestAuthService(newFakeUserRepo())
+
+	age, weight, height := 30, 80.0, 180.0
+	resp, err := svc.Register(context.Background(), &request.RegisterRequest{
+		Email:         "full@example.com",
+		Password:      "secret123",
+		Age:           &age,
+		Weight:        &weight,
+		Height:        &height,
+		Gender:        "male",
+		Goal:          "weight_loss",
+		ActivityLevel: "moderate",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// BMR = 10*80 + 6.25*180 - 5*30 + 5 = 1780; TDEE = 1780*1.55 = 2759; -500 deficit
+	want := 1780*1.55 - 500
+	if got := resp.User.Preferences.CalorieTarget; got != want {
+		t.Errorf("calorie target = %v, want %v", got, want)
+	}
+	if resp.User.Preferences.MacroTargets.Protein == 0 {
+		t.Error("expected macro targets to be set")
+	}
+}
+
+func TestAuthService_Register_EmailPasswordOnly(t *testing.T) {
+	svc := newTestAuthService(newFakeUserRepo())
+
+	resp, err := svc.Register(context.Background(), &request.RegisterRequest{
+		Email:    "minimal@example.com",
+		Password: "secret123",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if resp.User.Preferences.CalorieTarget != 0 {
+		t.Errorf("calorie target = %v, want 0", resp.User.Preferences.CalorieTarget)
+	}
+	if resp.User.Profile.Goal != "maintenance" {
+		t.Errorf("goal = %q, want configured default", resp.User.Profile.Goal)
+	}
+	if resp.User.Profile.ActivityLevel != "sedentary" {
+		t.Errorf("activity level = %q, want configured default", resp.User.Profile.ActivityLevel)
+	}
+}
+
+func TestAuthService_Login_UnknownEmailComparesPassword(t *testing.T) {
+	svc := newTestAuthService(newFakeUserRepo())
+	compareCalls := 0
+	svc.comparePassword = func(hash, password []byte) error {
+		compareCalls++
+		return bcrypt.CompareHashAndPassword(hash, password)
+	}
+
+	_, err := svc.Login(context.Background(), &request.LoginRequest{Email: "missing@example.com", Password: "secret123"})
+	if err == nil || err.Error() != "invalid credentials" {
+		t.Errorf("Expected invalid credentials error, got: %v", err)
+	}
+	if compareCalls != 1 {
+		t.Errorf("Expected 1 password comparison for an unknown email, got: %d", compareCalls)
+	}
+}
+
+func TestAuthService_Login_WrongPassword(t *testing.T) {
+	repo := newFakeUserRepo()
+	svc := newTestAuthService(repo)
+	if _, err := svc.Register(context.Background(), &request.RegisterRequest{Email: "user@example.com", Password: "secret123"}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	compareCalls := 0
+	svc.comparePassword = func(hash, password []byte) error {
+		compareCalls++
+		return bcrypt.CompareHashAndPassword(hash, password)
+	}
+
+	_, err := svc.Login(context.Background(), &request.LoginRequest{Email: "user@example.com", Password: "wrong-password"})
+	if err == nil || err.Error() != "invalid credentials" {
+		t.Errorf("Expected invalid credentials error, got: %v", err)
+	}
+	if compareCalls != 1 {
+		t.Errorf("Expected 1 password comparison, got: %d", compareCalls)
+	}
+
+	if _, err := svc.Login(context.Background(), &request.LoginRequest{Email: "user@example.com", Password: "secret123"}); err != nil {
+		t.Errorf("Expected login to succeed, got: %v", err)
+	}
+}
+
+func TestAuthService_MixedCaseEmailsAreOneAccount(t *testing.T) {
+	repo := newFakeUserRepo()
+	svc := newTestAuthService(repo)
+
+	resp, err := svc.Register(context.Background(), &request.RegisterRequest{Email: " User@Example.com", Password: "secret123"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if resp.User.Email != "user@example.com" {
+		t.Errorf("Expected the email to be stored as user@example.com, got: %q", resp.User.Email)
+	}
+
+	_, err = svc.Register(context.Background(), &request.RegisterRequest{Email: "user@EXAMPLE.com", Password: "secret123"})
+	if !errors.Is(err, ErrDuplicateEmail) {
+		t.Errorf("Expected a duplicate email error, got: %v", err)
+	}
+
+	login, err := svc.Login(context.Background(), &request.LoginRequest{Email: "USER@example.COM", Password: "secret123"})
+	if err != nil {
+		t.Fatalf("Expected login with a differently cased email to succeed, got: %v", err)
+	}
+	if login.User.ID != resp.User.ID {
+		t.Errorf("Expected the same account, got: %s and %s", resp.User.ID, login.User.ID)
+	}
+}
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"nutrient_be/internal/domain"
+	"nutrient_be/internal/dto/response"
+	"nutrient_be/internal/pkg/logger"
+)
+
+// DashboardFoodRepository defines the food data operations used by DashboardService
+type DashboardFoodRepository interface {
+	CountFoods(ctx context.Context, filter domain.FoodFilter) (int64, error)
+}
+
+// DashboardMealTemplateRepository defines the meal template data operations used by DashboardService
+type DashboardMealTemplateRepository interface {
+	CountByUser(ctx context.Context, userID primitive.ObjectID, mealType string) (int64, error)
+}
+
+// DashboardMealPlanRepository defines the meal plan data operations used by DashboardService
+type DashboardMealPlanRepository interface {
+	CountByUser(ctx context.Context, userID primitive.ObjectID, planType, status string) (int64, error)
+	GetByUserAndDateRange(ctx context.Context, userID primitive.ObjectID, startDate, endDate time.Time) ([]*domain.MealPlan, error)
+}
+
+// DashboardUserRepository defines the user data operations used by DashboardService
+type DashboardUserRepository interface {
+	GetByID(ctx context.Context, id primitive.ObjectID) (*domain.User, error)
+}
+
+// DashboardService assembles the dashboard summary
+type DashboardService struct {
+	foodRepo         DashboardFoodRepository
+	mealTemplateRepo DashboardMealTemplateRepository
+	mealPlanRepo     DashboardMealPlanRepository
+	userRepo         DashboardUserRepository
+	logger           logger.Logger
+}
+
+// NewDashboardService creates a new dashboard service
+func NewDashboardService(
+	foodRepo DashboardFoodRepository,
+	mealTemplateRepo DashboardMealTemplateRepository,
+	mealPlanRepo DashboardMealPlanRepository,
+	userRepo DashboardUserRepository,
+	log logger.Logger,
+) *DashboardService {
+	return &DashboardService{
+		foodRepo:         foodRepo,
+		mealTemplateRepo: mealTemplateRepo,
+		mealPlanRepo:     mealPlanRepo,
+		userRepo:         userRepo,
+		logger:           log,
+	}
+}
+
+// Summary returns the user's food, template and active plan counts and the calories left on date
+// Counts are read with count queries rather than by loading the documents
+func (s *DashboardService) Summary(ctx context.Context, userID string, date time.Time) (*response.DashboardResponse, error) {
+	s.logger.Info(ctx, "Building dashboard", logger.String("date", date.Format(dateLayout)))
+
+	userIDObj, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		s.logger.Error(ctx, "Invalid user ID", logger.Error(err))
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userIDObj)
+	if err != nil {
+		s.logger.Error(ctx, "Failed to get user", logger.Error(err))
+		return nil, fmt.Errorf("user not found: %w", err)
+	}
+
+	foodCount, err := s.foodRepo.CountFoods(ctx, domain.FoodFilter{CreatedBy: &userIDObj})
+	if err != nil {
+		s.logger.Error(ctx, "Failed to count foods", logger.Error(err))
+		return nil, fmt.Errorf("failed to count foods: %w", err)
+	}
+
+	templateCount, err := s.mealTemplateRepo.CountByUser(ctx, userIDObj, "")
+	if err != nil {
+		s.logger.Error(ctx, "Failed to count meal templates", logger.Error(err))
+		return nil, fmt.Errorf("failed to count meal templates: %w", err)
+	}
+
+	activePlanCount, err := s.mealPlanRepo.CountByUser(ctx, userIDObj, "", domain.MealPlanStatusActive)
+	if err != nil {
+		s.logger.Error(ctx, "Failed to count meal plans", logger.Error(err))
+		return nil, fmt.Errorf("failed to count meal plans: %w", err)
+	}
+
+	day := startOfDay(date)
+	plans, err := s.mealPlanRepo.GetByUserAndDateRange(ctx, userIDObj, day, day)
+	if err != nil {
+		s.logger.Error(ctx, "Failed to get meal plans", logger.Error(err))
+		return nil, fmt.Errorf("failed to get meal plans: %w", err)
+	}
+	days, consumedMacros := buildDailyReports(plans, day, day.AddDate(0, 0, 1))
+	remainingCalories, _ := remainingBudget(user, days[0].ConsumedCalories, consumedMacros)
+
+	return &response.DashboardResponse{
+		FoodCount:       foodCount,
+		TemplateCount:   templateCount,
+		ActivePlanCount: activePlanCount,
+		Today: response.DashboardTodayResponse{
+			Date:              day,
+			TargetCalories:    user.Preferences.CalorieTarget,
+			ConsumedCalories:  days[0].ConsumedCalories,
+			RemainingCalories: remainingCalories,
+		},
+	}, nil
+}
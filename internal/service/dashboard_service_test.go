@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"nutrient_be/internal/domain"
+	"nutrient_be/internal/pkg/logger"
+)
+
+func TestDashboardService_Summary_CountsSeededData(t *testing.T) {
+	ctx := context.Background()
+	day := time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)
+	user := &domain.User{ID: primitive.NewObjectID(), Preferences: domain.UserPreferences{CalorieTarget: 2000}}
+	otherUserID := primitive.NewObjectID()
+
+	activePlan := newReportTestPlan(user.ID, day, 1)
+	activePlan.Status = domain.MealPlanStatusActive
+	draftPlan := newReportTestPlan(user.ID, day.AddDate(0, 0, 7), 1)
+	draftPlan.Status = domain.MealPlanStatusDraft
+	otherPlan := newReportTestPlan(otherUserID, day, 1)
+	otherPlan.Status = domain.MealPlanStatusActive
+
+	svc := NewDashboardService(
+		newFakeFoodRepo(
+			&domain.FoodItem{ID: primitive.NewObjectID(), CreatedBy: user.ID},
+			&domain.FoodItem{ID: primitive.NewObjectID(), CreatedBy: user.ID},
+			&domain.FoodItem{ID: primitive.NewObjectID(), CreatedBy: otherUserID, Visibility: "public"},
+		),
+		newFakeMealTemplateRepo(
+			&domain.MealTemplate{ID: primitive.NewObjectID(), UserID: user.ID, MealType: "breakfast"},
+			&domain.MealTemplate{ID: primitive.NewObjectID(), UserID: user.ID, MealType: "dinner"},
+			&domain.MealTemplate{ID: primitive.NewObjectID(), UserID: user.ID, MealType: "dinner"},
+			&domain.MealTemplate{ID: primitive.NewObjectID(), UserID: otherUserID, MealType: "dinner"},
+		),
+		newFakeMealPlanRepo(activePlan, draftPlan, otherPlan),
+		newFakeUserRepo(user),
+		logger.NewNoopLogger(),
+	)
+
+	dashboard, err := svc.Summary(ctx, user.ID.Hex(), day.Add(15*time.Hour))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if dashboard.FoodCount != 2 {
+		t.Errorf("Expected 2 foods created by the user, got: %d", dashboard.FoodCount)
+	}
+	if dashboard.TemplateCount != 3 {
+		t.Errorf("Expected 3 templates, got: %d", dashboard.TemplateCount)
+	}
+	if dashboard.ActivePlanCount != 1 {
+		t.Errorf("Expected 1 active plan, got: %d", dashboard.ActivePlanCount)
+	}
+	if !dashboard.Today.Date.Equal(day) {
+		t.Errorf("Expected today to be %v, got: %v", day, dashboard.Today.Date)
+	}
+	// One completed meal of 500 kcal against a 2000 kcal target
+	if dashboard.Today.ConsumedCalories != 500 || dashboard.Today.RemainingCalories != 1500 {
+		t.Errorf("Expected 500 consumed and 1500 remaining, got: %v and %v", dashboard.Today.ConsumedCalories, dashboard.Today.RemainingCalories)
+	}
+}
+
+func TestDashboardService_Summary_ZerosWithoutData(t *testing.T) {
+	user := &domain.User{ID: primitive.NewObjectID()}
+	svc := NewDashboardService(newFakeFoodRepo(), newFakeMealTemplateRepo(), newFakeMealPlanRepo(), newFakeUserRepo(user), logger.NewNoopLogger())
+
+	dashboard, err := svc.Summary(context.Background(), user.ID.Hex(), time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if dashboard.FoodCount != 0 || dashboard.TemplateCount != 0 || dashboard.ActivePlanCount != 0 {
+		t.Errorf("Expected zero counts, got: %+v", dashboard)
+	}
+	if dashboard.Today.TargetCalories != 0 || dashboard.Today.ConsumedCalories != 0 || dashboard.Today.RemainingCalories != 0 {
+		t.Errorf("Expected zero calories, got: %+v", dashboard.Today)
+	}
+}
@@ -0,0 +1,818 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"nutrient_be/internal/domain"
+	"nutrient_be/internal/pkg/storage"
+	"nutrient_be/internal/pkg/textnorm"
+)
+
+// paginate applies limit/offset to items the way the Mongo repositories do
+func paginate[T any](items []T, limit, offset int) []T {
+	if offset >= len(items) {
+		return nil
+	}
+	items = items[offset:]
+	if limit > 0 && limit < len(items) {
+		items = items[:limit]
+	}
+	return items
+}
+
+// fakeMealPlanRepo is an in-memory MealPlanRepository for service tests
+type fakeMealPlanRepo struct {
+	plans map[primitive.ObjectID]*domain.MealPlan
+}
+
+func newFakeMealPlanRepo(plans ...*domain.MealPlan) *fakeMealPlanRepo {
+	r := &fakeMealPlanRepo{plans: make(map[primitive.ObjectID]*domain.MealPlan)}
+	for _, p := range plans {
+		r.plans[p.ID] = p
+	}
+	return r
+}
+
+func (r *fakeMealPlanRepo) Create(ctx context.Context, plan *domain.MealPlan) error {
+	if plan.ID.IsZero() {
+		plan.ID = primitive.NewObjectID()
+	}
+	r.plans[plan.ID] = plan
+	return nil
+}
+
+func (r *fakeMealPlanRepo) GetByID(ctx context.Context, id primitive.ObjectID) (*domain.MealPlan, error) {
+	plan, ok := r.plans[id]
+	if !ok {
+		return nil, fmt.Errorf("meal plan not found")
+	}
+	copied := *plan
+	return &copied, nil
+}
+
+func (r *fakeMealPlanRepo) GetByUser(ctx context.Context, userID primitive.ObjectID, planType, status string, limit, offset int) ([]*domain.MealPlan, error) {
+	var plans []*domain.MealPlan
+	for _, p := range r.plans {
+		if p.UserID == userID && (planType == "" || p.PlanType == planType) && (status == "" || p.Status == status) {
+			plans = append(plans, p)
+		}
+	}
+	// Newest first like the repository, so paging is stable
+	sort.Slice(plans, func(i, j int) bool { return plans[i].ID.Hex() > plans[j].ID.Hex() })
+	return paginate(plans, limit, offset), nil
+}
+
+func (r *fakeMealPlanRepo) CountByUser(ctx context.Context, userID primitive.ObjectID, planType, status string) (int64, error) {
+	plans, _ := r.GetByUser(ctx, userID, planType, status, 0, 0)
+	return int64(len(plans)), nil
+}
+
+func (r *fakeMealPlanRepo) GetByUserAndDateRange(ctx context.Context, userID primitive.ObjectID, startDate, endDate time.Time) ([]*domain.MealPlan, error) {
+	start, end := startOfDay(startDate), startOfDay(endDate).AddDate(0, 0, 1)
+	var result []*domain.MealPlan
+	for _, p := range r.plans {
+		if p.UserID == userID && p.StartDate.Before(end) && !p.EndDate.Before(start) {
+			result = append(result, p)
+		}
+	}
+	return result, nil
+}
+
+func (r *fakeMealPlanRepo) Update(ctx context.Context, plan *domain.MealPlan) error {
+	r.plans[plan.ID] = plan
+	return nil
+}
+
+func (r *fakeMealPlanRepo) Delete(ctx context.Context, id primitive.ObjectID) error {
+	delete(r.plans, id)
+	return nil
+}
+
+func (r *fakeMealPlanRepo) UpdateMealCompletion(ctx context.Context, planID primitive.ObjectID, mealID string, isCompleted bool) error {
+	return nil
+}
+
+func (r *fakeMealPlanRepo) ReplaceFoodReference(ctx context.Context, fromID, toID primitive.ObjectID) (int64, error) {
+	var modified int64
+	for _, p := range r.plans {
+		replaced := false
+		for d := range p.DailyMeals {
+			for m := range p.DailyMeals[d].Meals {
+				items := p.DailyMeals[d].Meals[m].FoodItems
+				for i := range items {
+					if items[i].FoodItemID == fromID {
+						items[i].FoodItemID = toID
+						replaced = true
+					}
+				}
+			}
+		}
+		if replaced {
+			modified++
+		}
+	}
+	return modified, nil
+}
+
+// fakeUserRepo is an in-memory UserRepository for service tests
+type fakeUserRepo struct {
+	users map[primitive.ObjectID]*domain.User
+}
+
+func newFakeUserRepo(users ...*domain.User) *fakeUserRepo {
+	r := &fakeUserRepo{users: make(map[primitive.ObjectID]*domain.User)}
+	for _, u := range users {
+		r.users[u.ID] = u
+	}
+	return r
+}
+
+func (r *fakeUserRepo) Create(ctx context.Context, user *domain.User) error {
+	if user.ID.IsZero() {
+		user.ID = primitive.NewObjectID()
+	}
+	r.users[user.ID] = user
+	return nil
+}
+
+func (r *fakeUserRepo) GetByID(ctx context.Context, id primitive.ObjectID) (*domain.User, error) {
+	u, ok := r.users[id]
+	if !ok {
+		return nil, fmt.Errorf("user not found")
+	}
+	cp := *u
+	return &cp, nil
+}
+
+func (r *fakeUserRepo) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
+	for _, u := range r.users {
+		if u.Email == email {
+			cp := *u
+			return &cp, nil
+		}
+	}
+	return nil, fmt.Errorf("user not found")
+}
+
+func (r *fakeUserRepo) Update(ctx context.Context, user *domain.User) error {
+	if _, ok := r.users[user.ID]; !ok {
+		return fmt.Errorf("user not found")
+	}
+	r.users[user.ID] = user
+	return nil
+}
+
+func (r *fakeUserRepo) Delete(ctx context.Context, id primitive.ObjectID) error {
+	if _, ok := r.users[id]; !ok {
+		return fmt.Errorf("user not found")
+	}
+	delete(r.users, id)
+	return nil
+}
+
+func (r *fakeMealPlanRepo) DeleteByUser(ctx context.Context, userID primitive.ObjectID) error {
+	for id, p := range r.plans {
+		if p.UserID == userID {
+			delete(r.plans, id)
+		}
+	}
+	return nil
+}
+
+// fakeFoodRepo is an in-memory store of food items for service tests
+type fakeFoodRepo struct {
+	foods        map[primitive.ObjectID]*domain.FoodItem
+	getByIDCalls int
+	publicCalls  int
+	lastFilter   domain.FoodFilter
+}
+
+func newFakeFoodRepo(foods ...*domain.FoodItem) *fakeFoodRepo {
+	r := &fakeFoodRepo{foods: make(map[primitive.ObjectID]*domain.FoodItem)}
+	for _, f := range foods {
+		r.foods[f.ID] = f
+	}
+	return r
+}
+
+func (r *fakeFoodRepo) Create(ctx context.Context, food *domain.FoodItem) error {
+	if food.ID.IsZero() {
+		food.ID = primitive.NewObjectID()
+	}
+	r.foods[food.ID] = food
+	return nil
+}
+
+func (r *fakeFoodRepo) GetByID(ctx context.Context, id primitive.ObjectID) (*domain.FoodItem, error) {
+	r.getByIDCalls++
+	f, ok := r.foods[id]
+	if !ok || f.DeletedAt != nil {
+		return nil, fmt.Errorf("food item not found")
+	}
+	return f, nil
+}
+
+func (r *fakeFoodRepo) GetByIDs(ctx context.Context, ids []primitive.ObjectID) ([]*domain.FoodItem, error) {
+	var foods []*domain.FoodItem
+	for _, id := range ids {
+		if f, ok := r.foods[id]; ok && f.DeletedAt == nil {
+			foods = append(foods, f)
+		}
+	}
+	return foods, nil
+}
+
+func (r *fakeFoodRepo) GetByUser(ctx context.Context, userID primitive.ObjectID, limit, offset int) ([]*domain.FoodItem, error) {
+	var foods []*domain.FoodItem
+	for _, f := range r.foods {
+		if f.CreatedBy == userID && f.DeletedAt == nil {
+			foods = append(foods, f)
+		}
+	}
+	// Newest first like the repository, so paging is stable
+	sort.Slice(foods, func(i, j int) bool { return foods[i].ID.Hex() > foods[j].ID.Hex() })
+	return paginate(foods, limit, offset), nil
+}
+
+func (r *fakeFoodRepo) FindFoods(ctx context.Context, filter domain.FoodFilter, page domain.Page, sortBy string) ([]*domain.FoodItem, error) {
+	r.lastFilter = filter
+	var foods []*domain.FoodItem
+	for _, f := range r.foods {
+		if matchesFoodFilter(f, filter) {
+			foods = append(foods, f)
+		}
+	}
+	// Newest first with the ID breaking ties, like the repository
+	sort.Slice(foods, func(i, j int) bool { return foodNewerThan(foods[i], domain.FoodPageCursor(foods[j])) })
+	if page.After != nil {
+		var following []*domain.FoodItem
+		for _, f := range foods {
+			if !foodNewerThan(f, page.After) && !(f.CreatedAt.Equal(page.After.CreatedAt) && f.ID == page.After.ID) {
+				following = append(following, f)
+			}
+		}
+		foods = following
+	}
+	if sortBy == domain.FoodSortImportedFirst {
+		sort.SliceStable(foods, func(i, j int) bool {
+			return foods[i].Source == domain.FoodSourceImported && foods[j].Source != domain.FoodSourceImported
+		})
+	}
+	return paginate(foods, page.Limit, page.Offset), nil
+}
+
+func (r *fakeFoodRepo) CountFoods(ctx context.Context, filter domain.FoodFilter) (int64, error) {
+	var count int64
+	for _, f := range r.foods {
+		if matchesFoodFilter(f, filter) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// foodNewerThan reports whether f comes before cursor in the newest-first order of (createdAt, ID)
+func foodNewerThan(f *domain.FoodItem, cursor *domain.PageCursor) bool {
+	if !f.CreatedAt.Equal(cursor.CreatedAt) {
+		return f.CreatedAt.After(cursor.CreatedAt)
+	}
+	return f.ID.Hex() > cursor.ID.Hex()
+}
+
+// matchesFoodFilter mirrors the repository query for the fields of filter that are set
+func matchesFoodFilter(f *domain.FoodItem, filter domain.FoodFilter) bool {
+	if f.DeletedAt != nil {
+		return false
+	}
+	if filter.AccessibleTo != nil && f.Visibility != "public" && (filter.AccessibleTo.IsZero() || f.CreatedBy != *filter.AccessibleTo) {
+		return false
+	}
+	if filter.CreatedBy != nil && f.CreatedBy != *filter.CreatedBy {
+		return false
+	}
+	if filter.Source != "" && f.Source != filter.Source {
+		return false
+	}
+	if filter.Visibility != "" && f.Visibility != filter.Visibility {
+		return false
+	}
+	if len(filter.Categories) > 0 && !containsString(filter.Categories, f.Category) {
+		return false
+	}
+	if len(filter.Tags) > 0 {
+		tagged := false
+		for _, tag := range filter.Tags {
+			tagged = tagged || containsString(f.SearchTerms, tag)
+		}
+		if !tagged {
+			return false
+		}
+	}
+	for _, allergen := range filter.ExcludeAllergens {
+		if containsString(f.Allergens, allergen) {
+			return false
+		}
+	}
+	for _, tag := range filter.DietTags {
+		if !containsString(f.DietTags, tag) {
+			return false
+		}
+	}
+	if filter.Query != "" {
+		// Search terms are stored normalized and matched against the normalized query
+		normalizedQuery := textnorm.Normalize(filter.Query)
+		matched := false
+		for _, term := range f.SearchTerms {
+			matched = matched || strings.Contains(term, normalizedQuery)
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	ranges := []struct {
+		value float64
+		r     domain.NutrientRange
+	}{
+		{f.Calories, filter.Calories},
+		{f.Macros.Protein, filter.Protein},
+		{f.Macros.Carbohydrates, filter.Carbohydrates},
+		{f.Macros.Fat, filter.Fat},
+		{f.Macros.Fiber, filter.Fiber},
+	}
+	for _, nutrient := range ranges {
+		if (nutrient.r.Min != nil && nutrient.value < *nutrient.r.Min) || (nutrient.r.Max != nil && nutrient.value > *nutrient.r.Max) {
+			return false
+		}
+	}
+	return true
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *fakeFoodRepo) GetByCategory(ctx context.Context, category string, userID primitive.ObjectID, limit, offset int) ([]*domain.FoodItem, error) {
+	return nil, nil
+}
+
+func (r *fakeFoodRepo) GetPublicFoods(ctx context.Context, limit, offset int) ([]*domain.FoodItem, error) {
+	r.publicCalls++
+	var foods []*domain.FoodItem
+	for _, f := range r.foods {
+		if f.Visibility == "public" && f.DeletedAt == nil {
+			foods = append(foods, f)
+		}
+	}
+	return paginate(foods, limit, offset), nil
+}
+
+// MarkMerged stores a marked copy so a transaction snapshot still holds the unmarked food
+func (r *fakeFoodRepo) MarkMerged(ctx context.Context, id, keepID primitive.ObjectID) error {
+	f, ok := r.foods[id]
+	if !ok || f.DeletedAt != nil {
+		return fmt.Errorf("food item not found")
+	}
+	merged := *f
+	now := time.Now()
+	merged.MergedInto = &keepID
+	merged.DeletedAt = &now
+	r.foods[id] = &merged
+	return nil
+}
+
+func (r *fakeFoodRepo) SetVisibilityMatching(ctx context.Context, userID primitive.ObjectID, category string, searchTerms []string, visibility string) (int64, error) {
+	var updated int64
+	for _, f := range r.foods {
+		if f.CreatedBy != userID || f.Visibility == visibility || f.DeletedAt != nil {
+			continue
+		}
+		if category != "" && f.Category != category {
+			continue
+		}
+		if len(searchTerms) > 0 && !hasAnyTag(f.SearchTerms, searchTerms) {
+			continue
+		}
+		f.Visibility = visibility
+		updated++
+	}
+	return updated, nil
+}
+
+func (r *fakeFoodRepo) Update(ctx context.Context, food *domain.FoodItem) error {
+	r.foods[food.ID] = food
+	return nil
+}
+
+func (r *fakeFoodRepo) Delete(ctx context.Context, id primitive.ObjectID) error {
+	delete(r.foods, id)
+	return nil
+}
+
+func (r *fakeFoodRepo) DeleteByUser(ctx context.Context, userID primitive.ObjectID) error {
+	for id, f := range r.foods {
+		if f.CreatedBy == userID {
+			delete(r.foods, id)
+		}
+	}
+	return nil
+}
+
+func (r *fakeFoodRepo) AnonymizeByUser(ctx context.Context, userID primitive.ObjectID) error {
+	for id, f := range r.foods {
+		if f.CreatedBy != userID {
+			continue
+		}
+		if f.Visibility == "public" {
+			f.CreatedBy = primitive.NilObjectID
+		} else {
+			delete(r.foods, id)
+		}
+	}
+	return nil
+}
+
+// fakeMealTemplateRepo is an in-memory store of meal templates for service tests
+type fakeMealTemplateRepo struct {
+	templates map[primitive.ObjectID]*domain.MealTemplate
+}
+
+func newFakeMealTemplateRepo(templates ...*domain.MealTemplate) *fakeMealTemplateRepo {
+	r := &fakeMealTemplateRepo{templates: make(map[primitive.ObjectID]*domain.MealTemplate)}
+	for _, t := range templates {
+		r.templates[t.ID] = t
+	}
+	return r
+}
+
+func (r *fakeMealTemplateRepo) Create(ctx context.Context, template *domain.MealTemplate) error {
+	if template.ID.IsZero() {
+		template.ID = primitive.NewObjectID()
+	}
+	r.templates[template.ID] = template
+	return nil
+}
+
+func (r *fakeMealTemplateRepo) GetByID(ctx context.Context, id primitive.ObjectID) (*domain.MealTemplate, error) {
+	t, ok := r.templates[id]
+	if !ok {
+		return nil, fmt.Errorf("meal template not found")
+	}
+	return t, nil
+}
+
+func (r *fakeMealTemplateRepo) Update(ctx context.Context, template *domain.MealTemplate) error {
+	if _, ok := r.templates[template.ID]; !ok {
+		return fmt.Errorf("meal template not found")
+	}
+	r.templates[template.ID] = template
+	return nil
+}
+
+func (r *fakeMealTemplateRepo) Delete(ctx context.Context, id primitive.ObjectID) error {
+	delete(r.templates, id)
+	return nil
+}
+
+func (r *fakeMealTemplateRepo) GetByUser(ctx context.Context, userID primitive.ObjectID, mealType string, limit, offset int) ([]*domain.MealTemplate, error) {
+	var templates []*domain.MealTemplate
+	for _, t := range r.templates {
+		if t.UserID == userID && (mealType == "" || t.MealType == mealType) {
+			templates = append(templates, t)
+		}
+	}
+	// Newest first like the repository, so paging is stable
+	sort.Slice(templates, func(i, j int) bool { return templates[i].ID.Hex() > templates[j].ID.Hex() })
+	return paginate(templates, limit, offset), nil
+}
+
+func (r *fakeMealTemplateRepo) CountByUser(ctx context.Context, userID primitive.ObjectID, mealType string) (int64, error) {
+	templates, _ := r.GetByUser(ctx, userID, mealType, 0, 0)
+	return int64(len(templates)), nil
+}
+
+func (r *fakeMealTemplateRepo) TagCounts(ctx context.Context, userID primitive.ObjectID) ([]domain.TagCount, error) {
+	counts := make(map[string]int64)
+	for _, t := range r.templates {
+		if t.UserID != userID {
+			continue
+		}
+		for _, tag := range t.Tags {
+			counts[tag]++
+		}
+	}
+	tags := make([]domain.TagCount, 0, len(counts))
+	for tag, count := range counts {
+		tags = append(tags, domain.TagCount{Tag: tag, Count: count})
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		if tags[i].Count != tags[j].Count {
+			return tags[i].Count > tags[j].Count
+		}
+		return tags[i].Tag < tags[j].Tag
+	})
+	return tags, nil
+}
+
+func (r *fakeMealTemplateRepo) GetPublicTemplates(ctx context.Context, mealType string, limit, offset int) ([]*domain.MealTemplate, error) {
+	var templates []*domain.MealTemplate
+	for _, t := range r.templates {
+		if t.IsPublic && (mealType == "" || t.MealType == mealType) {
+			templates = append(templates, t)
+		}
+	}
+	return paginate(templates, limit, offset), nil
+}
+
+func (r *fakeMealTemplateRepo) GetWithinCalories(ctx context.Context, userID primitive.ObjectID, mealType string, maxCalories float64, limit int) ([]*domain.MealTemplate, error) {
+	var templates []*domain.MealTemplate
+	for _, t := range r.templates {
+		if (t.UserID == userID || t.IsPublic) && t.MealType == mealType && t.TotalCalories > 0 && t.TotalCalories <= maxCalories {
+			templates = append(templates, t)
+		}
+	}
+	return paginate(templates, limit, 0), nil
+}
+
+func (r *fakeMealTemplateRepo) DeleteMatching(ctx context.Context, userID primitive.ObjectID, mealType string, tags []string) (int64, error) {
+	var deleted int64
+	for id, t := range r.templates {
+		if t.UserID != userID || (mealType != "" && t.MealType != mealType) {
+			continue
+		}
+		if len(tags) > 0 && !hasAnyTag(t.Tags, tags) {
+			continue
+		}
+		delete(r.templates, id)
+		deleted++
+	}
+	return deleted, nil
+}
+
+// hasAnyTag reports whether tags contains any of wanted
+func hasAnyTag(tags, wanted []string) bool {
+	for _, tag := range tags {
+		for _, w := range wanted {
+			if tag == w {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (r *fakeMealTemplateRepo) DeleteByUser(ctx context.Context, userID primitive.ObjectID) error {
+	for id, t := range r.templates {
+		if t.UserID == userID {
+			delete(r.templates, id)
+		}
+	}
+	return nil
+}
+
+func (r *fakeMealTemplateRepo) AnonymizeByUser(ctx context.Context, userID primitive.ObjectID) error {
+	for id, t := range r.templates {
+		if t.UserID != userID {
+			continue
+		}
+		if t.IsPublic {
+			t.UserID = primitive.NilObjectID
+		} else {
+			delete(r.templates, id)
+		}
+	}
+	return nil
+}
+
+func (r *fakeMealTemplateRepo) ReplaceFoodReference(ctx context.Context, fromID, toID primitive.ObjectID) (int64, error) {
+	var modified int64
+	for _, t := range r.templates {
+		replaced := false
+		for i := range t.FoodItems {
+			if t.FoodItems[i].FoodItemID == fromID {
+				t.FoodItems[i].FoodItemID = toID
+				replaced = true
+			}
+		}
+		if replaced {
+			modified++
+		}
+	}
+	return modified, nil
+}
+
+// fakeShoppingListRepo is an in-memory store of shopping lists for service tests
+type fakeShoppingListRepo struct {
+	lists map[primitive.ObjectID]*domain.ShoppingList
+}
+
+func newFakeShoppingListRepo(lists ...*domain.ShoppingList) *fakeShoppingListRepo {
+	r := &fakeShoppingListRepo{lists: make(map[primitive.ObjectID]*domain.ShoppingList)}
+	for _, l := range lists {
+		r.lists[l.ID] = l
+	}
+	return r
+}
+
+func (r *fakeShoppingListRepo) GetByID(ctx context.Context, id primitive.ObjectID) (*domain.ShoppingList, error) {
+	l, ok := r.lists[id]
+	if !ok {
+		return nil, fmt.Errorf("shopping list not found")
+	}
+	cp := *l
+	cp.Items = append([]domain.ShoppingItem(nil), l.Items...)
+	return &cp, nil
+}
+
+func (r *fakeShoppingListRepo) Update(ctx context.Context, list *domain.ShoppingList) error {
+	if _, ok := r.lists[list.ID]; !ok {
+		return fmt.Errorf("shopping list not found")
+	}
+	r.lists[list.ID] = list
+	return nil
+}
+
+func (r *fakeShoppingListRepo) GetByUser(ctx context.Context, userID primitive.ObjectID, status string, limit, offset int) ([]*domain.ShoppingList, error) {
+	var lists []*domain.ShoppingList
+	for _, l := range r.lists {
+		if l.UserID == userID && (status == "" || l.Status == status) {
+			lists = append(lists, l)
+		}
+	}
+	// Newest first like the repository; object IDs increase with creation time
+	sort.Slice(lists, func(i, j int) bool { return lists[i].ID.Hex() > lists[j].ID.Hex() })
+	return paginate(lists, limit, offset), nil
+}
+
+func (r *fakeShoppingListRepo) DeleteByUser(ctx context.Context, userID primitive.ObjectID) error {
+	for id, l := range r.lists {
+		if l.UserID == userID {
+			delete(r.lists, id)
+		}
+	}
+	return nil
+}
+
+func (r *fakeShoppingListRepo) DeleteByMealPlan(ctx context.Context, mealPlanID primitive.ObjectID) error {
+	for id, l := range r.lists {
+		if l.MealPlanID == mealPlanID {
+			delete(r.lists, id)
+		}
+	}
+	return nil
+}
+
+// Create stores list under its own ID; like MongoDB with an omitempty _id, it does not fill in a missing ID on the struct
+func (r *fakeShoppingListRepo) Create(ctx context.Context, list *domain.ShoppingList) error {
+	if list.ID.IsZero() {
+		return fmt.Errorf("shopping list created without an ID")
+	}
+	r.lists[list.ID] = list
+	return nil
+}
+
+func (r *fakeShoppingListRepo) GetByMealPlan(ctx context.Context, mealPlanID primitive.ObjectID) (*domain.ShoppingList, error) {
+	for _, l := range r.lists {
+		if l.MealPlanID == mealPlanID {
+			return l, nil
+		}
+	}
+	return nil, fmt.Errorf("shopping list not found")
+}
+
+func (r *fakeShoppingListRepo) Delete(ctx context.Context, id primitive.ObjectID) error {
+	delete(r.lists, id)
+	return nil
+}
+
+func (r *fakeShoppingListRepo) ToggleItemsChecked(ctx context.Context, listID primitive.ObjectID, checked map[primitive.ObjectID]bool) error {
+	l, ok := r.lists[listID]
+	if !ok {
+		return fmt.Errorf("shopping list not found")
+	}
+	for i := range l.Items {
+		if isChecked, ok := checked[l.Items[i].ID]; ok {
+			l.Items[i].Checked = isChecked
+		}
+	}
+	return nil
+}
+
+func (r *fakeShoppingListRepo) ToggleItemChecked(ctx context.Context, listID primitive.ObjectID, itemID primitive.ObjectID, checked bool) error {
+	l, ok := r.lists[listID]
+	if !ok {
+		return fmt.Errorf("shopping list not found")
+	}
+	for i := range l.Items {
+		if l.Items[i].ID == itemID {
+			l.Items[i].Checked = checked
+		}
+	}
+	return nil
+}
+
+// fakeStorage keeps uploaded files in memory for service tests
+type fakeStorage struct {
+	files map[string][]byte
+}
+
+func newFakeStorage() *fakeStorage {
+	return &fakeStorage{files: make(map[string][]byte)}
+}
+
+func (s *fakeStorage) Save(ctx context.Context, key string, data []byte, contentType string) error {
+	s.files[key] = data
+	return nil
+}
+
+func (s *fakeStorage) Load(ctx context.Context, key string) ([]byte, error) {
+	data, ok := s.files[key]
+	if !ok {
+		return nil, storage.ErrNotFound
+	}
+	return data, nil
+}
+
+func (s *fakeStorage) Delete(ctx context.Context, key string) error {
+	delete(s.files, key)
+	return nil
+}
+
+// fakeTransactor emulates transactions over the in-memory repositories
+// Each snapshot function saves a repository's state and returns a function restoring it, which runs when fn fails
+type fakeTransactor struct {
+	snapshots []func() func()
+	calls     int
+}
+
+func newFakeTransactor(snapshots ...func() func()) *fakeTransactor {
+	return &fakeTransactor{snapshots: snapshots}
+}
+
+func (t *fakeTransactor) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	t.calls++
+	restores := make([]func(), len(t.snapshots))
+	for i, snapshot := range t.snapshots {
+		restores[i] = snapshot()
+	}
+	if err := fn(ctx); err != nil {
+		for _, restore := range restores {
+			restore()
+		}
+		return err
+	}
+	return nil
+}
+
+// snapshot saves every stored template so a failed transaction can restore them in place
+func (r *fakeMealTemplateRepo) snapshot() func() {
+	stored := make(map[primitive.ObjectID]*domain.MealTemplate, len(r.templates))
+	saved := make(map[primitive.ObjectID]domain.MealTemplate, len(r.templates))
+	for id, t := range r.templates {
+		copied := *t
+		copied.FoodItems = append([]domain.MealTemplateFoodItem(nil), t.FoodItems...)
+		stored[id], saved[id] = t, copied
+	}
+	return func() {
+		r.templates = stored
+		for id, t := range stored {
+			*t = saved[id]
+		}
+	}
+}
+
+// snapshot saves which foods are stored so a failed transaction can restore them
+func (r *fakeFoodRepo) snapshot() func() {
+	stored := make(map[primitive.ObjectID]*domain.FoodItem, len(r.foods))
+	for id, f := range r.foods {
+		stored[id] = f
+	}
+	return func() {
+		r.foods = stored
+	}
+}
+
+// failingReferenceRepo fails every reference replacement, e.g. to abort a merge halfway
+type failingReferenceRepo struct {
+	err error
+}
+
+func (r *failingReferenceRepo) ReplaceFoodReference(ctx context.Context, fromID, toID primitive.ObjectID) (int64, error) {
+	return 0, r.err
+}
+
+// float64Ptr returns a pointer to v for optional request fields
+func float64Ptr(v float64) *float64 {
+	return &v
+}
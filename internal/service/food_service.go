@@ -2,65 +2,275 @@ package service
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 
+	"nutrient_be/internal/config"
 	"nutrient_be/internal/domain"
 	"nutrient_be/internal/dto/request"
+	"nutrient_be/internal/pkg/cache"
+	"nutrient_be/internal/pkg/calculator"
 	"nutrient_be/internal/pkg/logger"
+	"nutrient_be/internal/pkg/storage"
+	"nutrient_be/internal/pkg/textnorm"
 	"nutrient_be/internal/pkg/validator"
 )
 
+// Cache key prefixes for public food reads
+const (
+	foodCacheKeyPrefix        = "food:"
+	publicFoodsCacheKeyPrefix = "foods:public:"
+)
+
 // FoodRepository defines the interface for food data operations used by FoodService
 type FoodRepository interface {
 	Create(ctx context.Context, food *domain.FoodItem) error
 	GetByID(ctx context.Context, id primitive.ObjectID) (*domain.FoodItem, error)
-	Search(ctx context.Context, query string, userID primitive.ObjectID, limit, offset int) ([]*domain.FoodItem, error)
+	FindFoods(ctx context.Context, filter domain.FoodFilter, page domain.Page, sortBy string) ([]*domain.FoodItem, error)
 	GetByCategory(ctx context.Context, category string, userID primitive.ObjectID, limit, offset int) ([]*domain.FoodItem, error)
 	GetByUser(ctx context.Context, userID primitive.ObjectID, limit, offset int) ([]*domain.FoodItem, error)
 	Update(ctx context.Context, food *domain.FoodItem) error
 	Delete(ctx context.Context, id primitive.ObjectID) error
+	MarkMerged(ctx context.Context, id, keepID primitive.ObjectID) error
 	GetPublicFoods(ctx context.Context, limit, offset int) ([]*domain.FoodItem, error)
+	SetVisibilityMatching(ctx context.Context, userID primitive.ObjectID, category string, searchTerms []string, visibility string) (int64, error)
+}
+
+// FoodReferenceRepository repoints references to a food stored in another collection
+// It is implemented by the meal template and meal plan repositories and used when merging duplicate foods
+type FoodReferenceRepository interface {
+	ReplaceFoodReference(ctx context.Context, fromID, toID primitive.ObjectID) (int64, error)
 }
 
+// imageExtensions maps the accepted image content types to file extensions
+var imageExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+}
+
+// foodImageURLFormat is the path uploaded food images are served from, after the same access check as the food
+const foodImageURLFormat = "/api/v1/foods/%s/image"
+
+// ErrImageTooLarge is returned when an uploaded image exceeds the configured maximum size
+var ErrImageTooLarge = errors.New("image exceeds the maximum size")
+
 // FoodService handles food-related business logic
 type FoodService struct {
-	foodRepo  FoodRepository
-	validator *validator.FoodValidator
-	logger    logger.Logger
+	foodRepo     FoodRepository
+	templateRefs FoodReferenceRepository
+	planRefs     FoodReferenceRepository
+	tx           Transactor
+	cache        cache.Cache
+	images       storage.Storage
+	maxImageSize int64
+	validator    *validator.FoodValidator
+	logger       logger.Logger
 }
 
 // NewFoodService creates a new food service
-func NewFoodService(foodRepo FoodRepository, log logger.Logger) *FoodService {
+// Only public foods are cached; pass cache.NewNoopCache() to disable caching.
+// Food images are written to images and may be at most maxImageSize bytes.
+// templateRefs and planRefs are repointed when duplicate foods are merged, inside a transaction run by tx.
+func NewFoodService(foodRepo FoodRepository, templateRefs, planRefs FoodReferenceRepository, tx Transactor, foodCache cache.Cache, images storage.Storage, maxImageSize int64, cfg config.FoodConfig, log logger.Logger) *FoodService {
+	foodValidator := validator.NewFoodValidator(log)
+	foodValidator.SetServingTolerance(cfg.ServingTolerance)
+	foodValidator.SetRequireGramBase(cfg.RequireGramBase)
+	foodValidator.SetMaxServingCalories(cfg.MaxServingCalories)
+	foodValidator.SetAllowedImageHosts(cfg.AllowedImageHosts)
+
 	return &FoodService{
-		foodRepo:  foodRepo,
-		validator: validator.NewFoodValidator(log),
-		logger:    log,
+		foodRepo:     foodRepo,
+		templateRefs: templateRefs,
+		planRefs:     planRefs,
+		tx:           tx,
+		cache:        foodCache,
+		images:       images,
+		maxImageSize: maxImageSize,
+		validator:    foodValidator,
+		logger:       log,
 	}
 }
 
 // CreateFood creates a new food item with validation
-func (s *FoodService) CreateFood(ctx context.Context, userID string, req *request.CreateFoodRequest) error {
+// The returned warnings flag suspicious values that did not prevent saving the food
+func (s *FoodService) CreateFood(ctx context.Context, userID string, req *request.CreateFoodRequest) ([]validator.FoodValidationIssue, error) {
 	s.logger.Info(ctx, "Creating food", logger.String("food_name", req.Name.Get("en")))
 
+	// Calories are optional on create and default to the value implied by the macros; an explicit 0 is kept and validated
+	if req.Calories == nil {
+		estimated := s.EstimateCalories(req.Macros)
+		req.Calories = &estimated
+	}
+
 	// Validate request using centralized validator
 	if err := s.validator.ValidateCreateRequest(ctx, req); err != nil {
 		s.logger.Error(ctx, "Food validation failed", logger.Error(err))
-		return fmt.Errorf("validation failed: %w", err)
+		return nil, fmt.Errorf("validation failed: %w", err)
 	}
 
 	// Convert request to domain entity
 	foodDB := domain.FoodItemFromRequest(ctx, req, userID)
+	foodDB.SearchTerms = foodSearchTerms(req)
+
+	// A food without an owner can only be public, since nobody could ever see it otherwise
+	if foodDB.CreatedBy.IsZero() && foodDB.Visibility != "public" {
+		s.logger.Error(ctx, "Private food without an owner")
+		return nil, fmt.Errorf("invalid user ID: private foods need an owner")
+	}
 
 	// Save to database
 	if err := s.foodRepo.Create(ctx, foodDB); err != nil {
 		s.logger.Error(ctx, "Failed to create food", logger.Error(err))
-		return fmt.Errorf("failed to create food: %w", err)
+		return nil, fmt.Errorf("failed to create food: %w", err)
+	}
+
+	if foodDB.Visibility == "public" {
+		s.cache.DeletePrefix(ctx, publicFoodsCacheKeyPrefix)
 	}
 
 	s.logger.Info(ctx, "Food created successfully", logger.String("food_id", foodDB.ID.Hex()))
-	return nil
+	return s.validator.Warnings(req), nil
+}
+
+// CloneFood copies a food the user can see into a new private food owned by the user, so its values can be edited
+// Public foods and the user's own foods can be cloned; nameSuffix, when set, is appended to every name translation.
+// The copy keeps the source's values as they are and is not validated again.
+func (s *FoodService) CloneFood(ctx context.Context, userID, sourceFoodID, nameSuffix string) (*domain.FoodItem, error) {
+	s.logger.Info(ctx, "Cloning food", logger.String("food_id", sourceFoodID))
+
+	userIDObj, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+	sourceIDObj, err := primitive.ObjectIDFromHex(sourceFoodID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid food ID: %w", err)
+	}
+
+	// Missing and inaccessible foods are reported the same way so private foods cannot be probed
+	source, err := s.foodRepo.GetByID(ctx, sourceIDObj)
+	if err != nil || (source.Visibility != "public" && source.CreatedBy != userIDObj) {
+		s.logger.Error(ctx, "Food not found or access denied", logger.String("food_id", sourceFoodID))
+		return nil, fmt.Errorf("food item not found or access denied")
+	}
+
+	// foodToCreateRequest copies the serving sizes; the maps and slices it shares with the source are copied here
+	req := foodToCreateRequest(source)
+	req.Name = make(request.MultiLanguage, len(source.Name))
+	for lang, name := range source.Name {
+		req.Name[lang] = name + nameSuffix
+	}
+	req.Description = make(request.MultiLanguage, len(source.Description))
+	for lang, description := range source.Description {
+		req.Description[lang] = description
+	}
+	req.SearchTerms = append([]string(nil), source.SearchTerms...)
+	req.Visibility = "private"
+
+	clone := domain.FoodItemFromRequest(ctx, req, userID)
+	clone.ID = primitive.NewObjectID()
+	clone.SearchTerms = foodSearchTerms(req)
+	if err := s.foodRepo.Create(ctx, clone); err != nil {
+		s.logger.Error(ctx, "Failed to create food clone", logger.Error(err))
+		return nil, fmt.Errorf("failed to clone food: %w", err)
+	}
+
+	s.logger.Info(ctx, "Food cloned successfully",
+		logger.String("food_id", sourceFoodID),
+		logger.String("clone_id", clone.ID.Hex()))
+	return clone, nil
+}
+
+// EstimateCalories computes calories from macros with the same Atwater factors the food validator checks against
+func (s *FoodService) EstimateCalories(macros request.MacroNutrientsRequest) float64 {
+	return validator.EstimateCalories(macros)
+}
+
+// ValidateFood runs the creation rules on a food request without saving anything
+// The report lists the failed rule, the warnings creation would only log and the calorie consistency figures
+func (s *FoodService) ValidateFood(ctx context.Context, req *request.CreateFoodRequest) *validator.FoodValidationReport {
+	// Omitted calories are filled in as on create, so they are never reported as inconsistent; an explicit 0 is checked
+	if req.Calories == nil {
+		estimated := s.EstimateCalories(req.Macros)
+		req.Calories = &estimated
+	}
+
+	report := s.validator.Report(ctx, req)
+	s.logger.Info(ctx, "Food request validated", logger.Bool("valid", report.Valid), logger.Int("warnings", len(report.Warnings)))
+	return report
+}
+
+// FoodMacroBreakdown is the share of a food's calories per 100g coming from each macro
+type FoodMacroBreakdown struct {
+	FoodID               string
+	Calories             float64 // Stored calories the percentages are relative to
+	MacroCalories        float64 // Calories derived from the macros with the Atwater factors
+	ProteinPercent       float64
+	CarbohydratesPercent float64
+	FatPercent           float64
+	FiberCalories        float64
+	FiberPercent         float64
+	CaloriesMismatch     bool // Stored and macro-derived calories differ by more than the validator tolerance
+}
+
+// MacroBreakdown reports the percentage of a food's calories coming from protein, carbohydrates, fat and fiber
+// Percentages are relative to the stored calories so they match the label; when those disagree with the macros
+// the breakdown is flagged and the percentages may not sum to 100. Private foods are only visible to their creator.
+func (s *FoodService) MacroBreakdown(ctx context.Context, userID, foodID string) (*FoodMacroBreakdown, error) {
+	s.logger.Info(ctx, "Calculating food macro breakdown", logger.String("food_id", foodID))
+
+	userIDObj, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+	foodIDObj, err := primitive.ObjectIDFromHex(foodID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid food ID: %w", err)
+	}
+
+	// Missing and inaccessible foods are reported the same way so private foods cannot be probed
+	food, err := s.foodRepo.GetByID(ctx, foodIDObj)
+	if err != nil || (food.Visibility != "public" && food.CreatedBy != userIDObj) {
+		s.logger.Error(ctx, "Food not found or access denied", logger.String("food_id", foodID))
+		return nil, fmt.Errorf("food item not found or access denied")
+	}
+
+	return buildMacroBreakdown(food, s.validator.CaloriesTolerance()), nil
+}
+
+// buildMacroBreakdown splits the stored calories of food between its macros
+// Foods without stored calories fall back to the macro-derived total so the shares stay meaningful
+func buildMacroBreakdown(food *domain.FoodItem, tolerance float64) *FoodMacroBreakdown {
+	macroCalories := calculator.CaloriesFromMacros(food.Macros)
+	breakdown := &FoodMacroBreakdown{
+		FoodID:           food.ID.Hex(),
+		Calories:         food.Calories,
+		MacroCalories:    macroCalories,
+		FiberCalories:    food.Macros.Fiber * calculator.FiberCaloriesPerGram,
+		CaloriesMismatch: math.Abs(food.Calories-macroCalories) > tolerance,
+	}
+
+	base := food.Calories
+	if base <= 0 {
+		base = macroCalories
+	}
+	if base <= 0 {
+		return breakdown
+	}
+
+	breakdown.ProteinPercent = food.Macros.Protein * calculator.ProteinCaloriesPerGram / base * 100
+	breakdown.CarbohydratesPercent = food.Macros.Carbohydrates * calculator.CarbohydrateCaloriesPerGram / base * 100
+	breakdown.FatPercent = food.Macros.Fat * calculator.FatCaloriesPerGram / base * 100
+	breakdown.FiberPercent = breakdown.FiberCalories / base * 100
+	return breakdown
 }
 
 // SearchFood searches for food items based on query
@@ -88,7 +298,16 @@ func (s *FoodService) SearchFood(ctx context.Context, req *request.SearchFoodReq
 		}
 	}
 
-	foods, err := s.foodRepo.Search(ctx, req.Query, userIDObj, req.Limit, req.Offset)
+	filter, err := searchFoodFilter(req, userIDObj)
+	if err != nil {
+		return nil, err
+	}
+	sortBy := domain.FoodSortNewest
+	if req.ImportedFirst {
+		sortBy = domain.FoodSortImportedFirst
+	}
+
+	foods, err := s.foodRepo.FindFoods(ctx, filter, domain.Page{Limit: req.Limit, Offset: req.Offset}, sortBy)
 	if err != nil {
 		s.logger.Error(ctx, "Failed to search food", logger.Error(err))
 		return nil, fmt.Errorf("failed to search food: %w", err)
@@ -97,6 +316,46 @@ func (s *FoodService) SearchFood(ctx context.Context, req *request.SearchFoodReq
 	return foods, nil
 }
 
+// searchFoodFilter builds the repository filter for a search by the user
+// Tags are normalized like the stored search terms they are matched against
+func searchFoodFilter(req *request.SearchFoodRequest, userID primitive.ObjectID) (domain.FoodFilter, error) {
+	filter := domain.FoodFilter{
+		Query:            req.Query,
+		Categories:       req.Categories,
+		ExcludeAllergens: request.NormalizeFoodLabels(req.ExcludeAllergens),
+		DietTags:         request.NormalizeFoodLabels(req.DietTags),
+		Source:           req.Source,
+		AccessibleTo:     &userID,
+		Calories:         domain.NutrientRange{Min: req.MinCalories, Max: req.MaxCalories},
+		Protein:          domain.NutrientRange{Min: req.MinProtein, Max: req.MaxProtein},
+		Carbohydrates:    domain.NutrientRange{Min: req.MinCarbohydrates, Max: req.MaxCarbohydrates},
+		Fat:              domain.NutrientRange{Min: req.MinFat, Max: req.MaxFat},
+		Fiber:            domain.NutrientRange{Min: req.MinFiber, Max: req.MaxFiber},
+	}
+	for _, tag := range req.Tags {
+		if tag = textnorm.Normalize(tag); tag != "" {
+			filter.Tags = append(filter.Tags, tag)
+		}
+	}
+
+	ranges := []struct {
+		nutrient string
+		r        domain.NutrientRange
+	}{
+		{"calories", filter.Calories},
+		{"protein", filter.Protein},
+		{"carbohydrates", filter.Carbohydrates},
+		{"fat", filter.Fat},
+		{"fiber", filter.Fiber},
+	}
+	for _, bounds := range ranges {
+		if r := bounds.r; r.Min != nil && r.Max != nil && *r.Min > *r.Max {
+			return domain.FoodFilter{}, fmt.Errorf("validation failed: minimum %s (%v) is above the maximum (%v)", bounds.nutrient, *r.Min, *r.Max)
+		}
+	}
+	return filter, nil
+}
+
 func (s *FoodService) GetFoodByID(ctx context.Context, id string) (*domain.FoodItem, error) {
 	s.logger.Info(ctx, "Getting food by ID", logger.String("food_id", id))
 	foodID, err := primitive.ObjectIDFromHex(id)
@@ -104,11 +363,541 @@ func (s *FoodService) GetFoodByID(ctx context.Context, id string) (*domain.FoodI
 		s.logger.Error(ctx, "Failed to convert food ID to object ID", logger.Error(err))
 		return nil, fmt.Errorf("failed to convert food ID to object ID: %w", err)
 	}
+
+	var cached domain.FoodItem
+	if s.getCached(ctx, foodCacheKeyPrefix+id, &cached) {
+		s.logger.Debug(ctx, "Food cache hit", logger.String("food_id", id))
+		return &cached, nil
+	}
+
 	food, err := s.foodRepo.GetByID(ctx, foodID)
 	if err != nil {
 		s.logger.Error(ctx, "Failed to get food by ID", logger.Error(err))
 		return nil, fmt.Errorf("failed to get food by ID: %w", err)
 	}
+
+	// Private foods are never cached so they cannot be served to other users
+	if food.Visibility == "public" {
+		s.setCached(ctx, foodCacheKeyPrefix+id, food)
+	}
+
 	s.logger.Info(ctx, "Food retrieved successfully", logger.String("food_id", food.ID.Hex()))
 	return food, nil
 }
+
+// ListPublicFoods lists public food items, serving repeated pages from the cache
+func (s *FoodService) ListPublicFoods(ctx context.Context, limit, offset int) ([]*domain.FoodItem, error) {
+	s.logger.Info(ctx, "Listing public foods", logger.Int("limit", limit), logger.Int("offset", offset))
+
+	key := fmt.Sprintf("%s%d:%d", publicFoodsCacheKeyPrefix, limit, offset)
+	var cached []*domain.FoodItem
+	if s.getCached(ctx, key, &cached) {
+		s.logger.Debug(ctx, "Public foods cache hit", logger.String("key", key))
+		return cached, nil
+	}
+
+	foods, err := s.foodRepo.GetPublicFoods(ctx, limit, offset)
+	if err != nil {
+		s.logger.Error(ctx, "Failed to list public foods", logger.Error(err))
+		return nil, fmt.Errorf("failed to list public foods: %w", err)
+	}
+	s.setCached(ctx, key, foods)
+
+	s.logger.Info(ctx, "Public foods listed successfully", logger.Int("count", len(foods)))
+	return foods, nil
+}
+
+// UpdateFood updates a food item owned by the user
+// The merged result is validated with the same rules as creation and gets the same warnings
+func (s *FoodService) UpdateFood(ctx context.Context, userID, foodID string, req *request.UpdateFoodRequest) (*domain.FoodItem, []validator.FoodValidationIssue, error) {
+	s.logger.Info(ctx, "Updating food", logger.String("food_id", foodID))
+
+	food, err := s.getOwnedFood(ctx, userID, foodID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	merged := foodToCreateRequest(food)
+	applyFoodUpdate(merged, req)
+	if err := s.validator.ValidateCreateRequest(ctx, merged); err != nil {
+		s.logger.Error(ctx, "Food validation failed", logger.Error(err))
+		return nil, nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	updated := domain.FoodItemFromRequest(ctx, merged, userID)
+	updated.SearchTerms = foodSearchTerms(merged)
+	updated.ID = food.ID
+	updated.CreatedBy = food.CreatedBy
+	updated.Source = food.Source
+	updated.CreatedAt = food.CreatedAt
+
+	if err := s.foodRepo.Update(ctx, updated); err != nil {
+		s.logger.Error(ctx, "Failed to update food", logger.Error(err))
+		return nil, nil, fmt.Errorf("failed to update food: %w", err)
+	}
+	s.invalidateFood(ctx, foodID)
+
+	s.logger.Info(ctx, "Food updated successfully", logger.String("food_id", foodID))
+	return updated, s.validator.Warnings(merged), nil
+}
+
+// DeleteFood deletes a food item owned by the user
+func (s *FoodService) DeleteFood(ctx context.Context, userID, foodID string) error {
+	s.logger.Info(ctx, "Deleting food", logger.String("food_id", foodID))
+
+	food, err := s.getOwnedFood(ctx, userID, foodID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.foodRepo.Delete(ctx, food.ID); err != nil {
+		s.logger.Error(ctx, "Failed to delete food", logger.Error(err))
+		return fmt.Errorf("failed to delete food: %w", err)
+	}
+	s.invalidateFood(ctx, foodID)
+
+	s.logger.Info(ctx, "Food deleted successfully", logger.String("food_id", foodID))
+	return nil
+}
+
+// BulkSetVisibility sets the visibility of every food the user created matching the filter and returns how many changed
+// An empty filter is rejected so a missing body cannot publish every food
+func (s *FoodService) BulkSetVisibility(ctx context.Context, userID string, filter *request.BulkSetFoodVisibilityRequest) (int64, error) {
+	s.logger.Info(ctx, "Bulk setting food visibility",
+		logger.String("category", filter.Category),
+		logger.Int("tag_count", len(filter.Tags)),
+		logger.String("visibility", filter.Visibility))
+
+	userIDObj, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		s.logger.Error(ctx, "Invalid user ID", logger.Error(err))
+		return 0, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	// Search terms are stored normalized, so tags are matched the same way
+	category := strings.TrimSpace(filter.Category)
+	tags := make([]string, 0, len(filter.Tags))
+	for _, tag := range filter.Tags {
+		if tag = textnorm.Normalize(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	if category == "" && len(tags) == 0 {
+		return 0, fmt.Errorf("validation failed: at least one of category or tags is required")
+	}
+	if category != "" && !validator.IsValidFoodCategory(category) {
+		return 0, fmt.Errorf("invalid category: %s", category)
+	}
+	if filter.Visibility != "public" && filter.Visibility != "private" {
+		return 0, fmt.Errorf("invalid visibility: %s", filter.Visibility)
+	}
+
+	updated, err := s.foodRepo.SetVisibilityMatching(ctx, userIDObj, category, tags, filter.Visibility)
+	if err != nil {
+		s.logger.Error(ctx, "Failed to bulk set food visibility", logger.Error(err))
+		return 0, fmt.Errorf("failed to update food visibility: %w", err)
+	}
+	if updated > 0 {
+		s.cache.DeletePrefix(ctx, foodCacheKeyPrefix)
+		s.cache.DeletePrefix(ctx, publicFoodsCacheKeyPrefix)
+	}
+
+	s.logger.Info(ctx, "Food visibility bulk updated", logger.Int("updated", int(updated)))
+	return updated, nil
+}
+
+// FoodMergeResult summarizes a merge of two duplicate foods
+type FoodMergeResult struct {
+	KeptFoodID       string
+	MergedFoodID     string
+	TemplatesUpdated int64
+	MealPlansUpdated int64
+}
+
+// Merge folds the duplicate food mergeID into keepID
+// Meal templates and meal plans referencing mergeID are repointed to keepID before mergeID is soft-deleted,
+// so a merge interrupted part way can simply be run again. The merged record is kept, marked with keepID,
+// but no longer returned by any read. adminID is recorded in the log.
+func (s *FoodService) Merge(ctx context.Context, adminID, keepID, mergeID string) (*FoodMergeResult, error) {
+	s.logger.Info(ctx, "Merging foods",
+		logger.String("admin_id", adminID),
+		logger.String("keep_id", keepID),
+		logger.String("merge_id", mergeID))
+
+	keepIDObj, err := primitive.ObjectIDFromHex(keepID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid food ID: %w", err)
+	}
+	mergeIDObj, err := primitive.ObjectIDFromHex(mergeID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid food ID: %w", err)
+	}
+	if keepIDObj == mergeIDObj {
+		return nil, fmt.Errorf("invalid merge: a food cannot be merged into itself")
+	}
+
+	for _, id := range []primitive.ObjectID{keepIDObj, mergeIDObj} {
+		if _, err := s.foodRepo.GetByID(ctx, id); err != nil {
+			s.logger.Error(ctx, "Food not found", logger.String("food_id", id.Hex()))
+			return nil, fmt.Errorf("food item not found or access denied")
+		}
+	}
+
+	// Repointing and deleting run in one transaction so a failure cannot leave references to a deleted food
+	result := &FoodMergeResult{KeptFoodID: keepID, MergedFoodID: mergeID}
+	err = s.tx.WithTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		if result.TemplatesUpdated, err = s.templateRefs.ReplaceFoodReference(ctx, mergeIDObj, keepIDObj); err != nil {
+			s.logger.Error(ctx, "Failed to repoint meal templates", logger.Error(err))
+			return fmt.Errorf("failed to repoint meal templates: %w", err)
+		}
+		if result.MealPlansUpdated, err = s.planRefs.ReplaceFoodReference(ctx, mergeIDObj, keepIDObj); err != nil {
+			s.logger.Error(ctx, "Failed to repoint meal plans", logger.Error(err))
+			return fmt.Errorf("failed to repoint meal plans: %w", err)
+		}
+		if err := s.foodRepo.MarkMerged(ctx, mergeIDObj, keepIDObj); err != nil {
+			s.logger.Error(ctx, "Failed to mark merged food", logger.Error(err))
+			return fmt.Errorf("failed to mark merged food: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.invalidateFood(ctx, mergeID)
+
+	s.logger.Info(ctx, "Foods merged successfully",
+		logger.String("admin_id", adminID),
+		logger.Int("templates_updated", int(result.TemplatesUpdated)),
+		logger.Int("meal_plans_updated", int(result.MealPlansUpdated)))
+	return result, nil
+}
+
+// recalculatePageSize is how many foods are loaded at a time when recalculating stored calories
+const recalculatePageSize = 200
+
+// maxReportedCalorieChanges caps how many individual changes a calorie recalculation lists
+const maxReportedCalorieChanges = 100
+
+// FoodCalorieChange is the stored calories of one food before and after recalculation
+type FoodCalorieChange struct {
+	FoodID      string
+	OldCalories float64
+	NewCalories float64
+}
+
+// CalorieRecalculationResult summarizes a recalculation of stored food calories from macros
+type CalorieRecalculationResult struct {
+	DryRun        bool
+	Scanned       int
+	Changed       int
+	TotalDelta    float64             // Sum of new minus old calories per 100g over the changed foods
+	MaxAbsDelta   float64             // Largest change of a single food, in either direction
+	Changes       []FoodCalorieChange // The first maxReportedCalorieChanges changes
+	ChangesCapped bool                // More foods changed than are listed
+}
+
+// RecalculateAllCalories recomputes the stored calories of every food from its macros with the current Atwater model
+// Foods are read a page at a time, each continuing after the last food of the previous one so foods created
+// meanwhile cannot make a page skip or repeat foods. Calories are rounded to two decimals and only foods whose
+// value differs are updated; with dryRun nothing is written and the result reports what would change.
+func (s *FoodService) RecalculateAllCalories(ctx context.Context, dryRun bool) (*CalorieRecalculationResult, error) {
+	s.logger.Info(ctx, "Recalculating food calories", logger.Bool("dry_run", dryRun))
+
+	result := &CalorieRecalculationResult{DryRun: dryRun, Changes: []FoodCalorieChange{}}
+	var after *domain.PageCursor
+	for {
+		foods, err := s.foodRepo.FindFoods(ctx, domain.FoodFilter{}, domain.Page{Limit: recalculatePageSize, After: after}, domain.FoodSortNewest)
+		if err != nil {
+			s.logger.Error(ctx, "Failed to list foods", logger.Error(err))
+			return nil, fmt.Errorf("failed to list foods: %w", err)
+		}
+
+		for _, food := range foods {
+			result.Scanned++
+			calories := math.Round(calculator.CaloriesFromMacros(food.Macros)*100) / 100
+			if calories == food.Calories {
+				continue
+			}
+
+			delta := calories - food.Calories
+			result.Changed++
+			result.TotalDelta += delta
+			result.MaxAbsDelta = math.Max(result.MaxAbsDelta, math.Abs(delta))
+			if len(result.Changes) < maxReportedCalorieChanges {
+				result.Changes = append(result.Changes, FoodCalorieChange{FoodID: food.ID.Hex(), OldCalories: food.Calories, NewCalories: calories})
+			} else {
+				result.ChangesCapped = true
+			}
+			if dryRun {
+				continue
+			}
+
+			food.Calories = calories
+			if err := s.foodRepo.Update(ctx, food); err != nil {
+				s.logger.Error(ctx, "Failed to update food calories", logger.String("food_id", food.ID.Hex()), logger.Error(err))
+				return nil, fmt.Errorf("failed to update food %s: %w", food.ID.Hex(), err)
+			}
+			s.invalidateFood(ctx, food.ID.Hex())
+		}
+
+		if len(foods) < recalculatePageSize {
+			break
+		}
+		after = domain.FoodPageCursor(foods[len(foods)-1])
+	}
+
+	s.logger.Info(ctx, "Food calories recalculated",
+		logger.Bool("dry_run", dryRun),
+		logger.Int("scanned", result.Scanned),
+		logger.Int("changed", result.Changed),
+		logger.Float64("total_delta", result.TotalDelta))
+	return result, nil
+}
+
+// UploadImage stores an image for a food item owned by the user and points ImageURL at it
+// The type is detected from the content, so only real JPEG and PNG files are accepted
+func (s *FoodService) UploadImage(ctx context.Context, userID, foodID string, data []byte) (*domain.FoodItem, error) {
+	s.logger.Info(ctx, "Uploading food image", logger.String("food_id", foodID), logger.Int("size", len(data)))
+
+	if len(data) == 0 {
+		return nil, fmt.Errorf("validation failed: image is empty")
+	}
+	if int64(len(data)) > s.maxImageSize {
+		return nil, fmt.Errorf("%w of %d bytes", ErrImageTooLarge, s.maxImageSize)
+	}
+
+	contentType := http.DetectContentType(data)
+	ext, ok := imageExtensions[contentType]
+	if !ok {
+		s.logger.Error(ctx, "Rejected food image", logger.String("content_type", contentType))
+		return nil, fmt.Errorf("validation failed: image must be a JPEG or PNG, got %s", contentType)
+	}
+
+	food, err := s.getOwnedFood(ctx, userID, foodID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.images.Save(ctx, foodImageKey(food.ID, ext), data, contentType); err != nil {
+		s.logger.Error(ctx, "Failed to store food image", logger.Error(err))
+		return nil, fmt.Errorf("failed to store food image: %w", err)
+	}
+
+	// Remove an image stored under the other extension so it does not linger
+	for _, otherExt := range imageExtensions {
+		if otherExt == ext {
+			continue
+		}
+		if err := s.images.Delete(ctx, foodImageKey(food.ID, otherExt)); err != nil {
+			s.logger.Warn(ctx, "Failed to delete previous food image", logger.Error(err))
+		}
+	}
+
+	url := fmt.Sprintf(foodImageURLFormat, food.ID.Hex())
+	food.ImageURL = url
+	food.UpdatedAt = time.Now()
+	if err := s.foodRepo.Update(ctx, food); err != nil {
+		s.logger.Error(ctx, "Failed to update food", logger.Error(err))
+		return nil, fmt.Errorf("failed to update food: %w", err)
+	}
+	s.invalidateFood(ctx, foodID)
+
+	s.logger.Info(ctx, "Food image uploaded successfully", logger.String("food_id", foodID), logger.String("image_url", url))
+	return food, nil
+}
+
+// GetImage returns the uploaded image of a food and its content type
+// Private foods' images are only visible to their creator, like the foods themselves
+func (s *FoodService) GetImage(ctx context.Context, userID, foodID string) ([]byte, string, error) {
+	userIDObj, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid user ID: %w", err)
+	}
+	foodIDObj, err := primitive.ObjectIDFromHex(foodID)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid food ID: %w", err)
+	}
+
+	// Missing and inaccessible foods are reported the same way so private foods cannot be probed
+	food, err := s.foodRepo.GetByID(ctx, foodIDObj)
+	if err != nil || (food.Visibility != "public" && food.CreatedBy != userIDObj) {
+		s.logger.Error(ctx, "Food not found or access denied", logger.String("food_id", foodID))
+		return nil, "", fmt.Errorf("food item not found or access denied")
+	}
+
+	contentTypes := make([]string, 0, len(imageExtensions))
+	for contentType := range imageExtensions {
+		contentTypes = append(contentTypes, contentType)
+	}
+	sort.Strings(contentTypes)
+
+	// Uploading removes the image stored under the other extension, so at most one is found
+	for _, contentType := range contentTypes {
+		data, err := s.images.Load(ctx, foodImageKey(food.ID, imageExtensions[contentType]))
+		if errors.Is(err, storage.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			s.logger.Error(ctx, "Failed to load food image", logger.Error(err))
+			return nil, "", fmt.Errorf("failed to load food image: %w", err)
+		}
+		return data, contentType, nil
+	}
+	return nil, "", fmt.Errorf("food image not found")
+}
+
+// foodImageKey is the storage key of a food's image with the given extension
+func foodImageKey(foodID primitive.ObjectID, ext string) string {
+	return "foods/" + foodID.Hex() + ext
+}
+
+// getOwnedFood loads a food item directly from the repository and checks the user created it
+func (s *FoodService) getOwnedFood(ctx context.Context, userID, foodID string) (*domain.FoodItem, error) {
+	userIDObj, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		s.logger.Error(ctx, "Invalid user ID", logger.Error(err))
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+	foodIDObj, err := primitive.ObjectIDFromHex(foodID)
+	if err != nil {
+		s.logger.Error(ctx, "Invalid food ID", logger.Error(err))
+		return nil, fmt.Errorf("invalid food ID: %w", err)
+	}
+
+	food, err := s.foodRepo.GetByID(ctx, foodIDObj)
+	if err != nil || food.CreatedBy != userIDObj {
+		s.logger.Error(ctx, "Food not found or access denied", logger.String("food_id", foodID))
+		return nil, fmt.Errorf("food item not found or access denied")
+	}
+	return food, nil
+}
+
+// invalidateFood drops a food and every cached public list that may contain it
+func (s *FoodService) invalidateFood(ctx context.Context, foodID string) {
+	s.cache.Delete(ctx, foodCacheKeyPrefix+foodID)
+	s.cache.DeletePrefix(ctx, publicFoodsCacheKeyPrefix)
+}
+
+// getCached decodes the cached value under key into dest, reporting whether it was found
+func (s *FoodService) getCached(ctx context.Context, key string, dest interface{}) bool {
+	data, ok := s.cache.Get(ctx, key)
+	if !ok {
+		return false
+	}
+	if err := json.Unmarshal(data, dest); err != nil {
+		s.logger.Warn(ctx, "Failed to decode cached value", logger.String("key", key), logger.Error(err))
+		s.cache.Delete(ctx, key)
+		return false
+	}
+	return true
+}
+
+// setCached encodes value and stores it under key
+func (s *FoodService) setCached(ctx context.Context, key string, value interface{}) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		s.logger.Warn(ctx, "Failed to encode value for cache", logger.String("key", key), logger.Error(err))
+		return
+	}
+	s.cache.Set(ctx, key, data)
+}
+
+// foodToCreateRequest converts a stored food back into a create request so updates can be validated as a whole
+func foodToCreateRequest(food *domain.FoodItem) *request.CreateFoodRequest {
+	servingSizes := make([]request.ServingSizeRequest, len(food.ServingSizes))
+	for i, size := range food.ServingSizes {
+		servingSizes[i] = request.ServingSizeRequest{
+			Unit:           size.Unit,
+			Amount:         size.Amount,
+			Description:    size.Description,
+			GramEquivalent: size.GramEquivalent,
+		}
+	}
+	calories := food.Calories
+
+	return &request.CreateFoodRequest{
+		Name:        request.MultiLanguage(food.Name),
+		SearchTerms: food.SearchTerms,
+		Description: request.MultiLanguage(food.Description),
+		Category:    food.Category,
+		Macros: request.MacroNutrientsRequest{
+			Protein:       food.Macros.Protein,
+			Carbohydrates: food.Macros.Carbohydrates,
+			Fat:           food.Macros.Fat,
+			Fiber:         food.Macros.Fiber,
+			Sugar:         food.Macros.Sugar,
+		},
+		Micros: request.MicroNutrientsRequest{
+			VitaminA:  food.Micros.VitaminA,
+			VitaminC:  food.Micros.VitaminC,
+			Calcium:   food.Micros.Calcium,
+			Iron:      food.Micros.Iron,
+			Sodium:    food.Micros.Sodium,
+			Potassium: food.Micros.Potassium,
+		},
+		ServingSizes: servingSizes,
+		Calories:     &calories,
+		Visibility:   food.Visibility,
+		ImageURL:     food.ImageURL,
+		Allergens:    food.Allergens,
+		DietTags:     food.DietTags,
+	}
+}
+
+// foodSearchTerms builds the stored search terms from the request's terms and every name translation
+// Terms are stored without diacritics so "tao" finds "Táo"; the name itself keeps its accents for display
+func foodSearchTerms(req *request.CreateFoodRequest) []string {
+	langs := make([]string, 0, len(req.Name))
+	for lang := range req.Name {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+
+	terms := append([]string{}, req.SearchTerms...)
+	for _, lang := range langs {
+		terms = append(terms, req.Name[lang])
+	}
+	return textnorm.SearchTerms(terms...)
+}
+
+// applyFoodUpdate copies the fields set in an update request onto a create request
+func applyFoodUpdate(target *request.CreateFoodRequest, req *request.UpdateFoodRequest) {
+	if len(req.Name) > 0 {
+		target.Name = req.Name
+	}
+	if req.SearchTerms != nil {
+		target.SearchTerms = req.SearchTerms
+	}
+	if len(req.Description) > 0 {
+		target.Description = req.Description
+	}
+	if req.Category != "" {
+		target.Category = req.Category
+	}
+	if req.Macros != nil {
+		target.Macros = *req.Macros
+	}
+	if req.Micros != nil {
+		target.Micros = *req.Micros
+	}
+	if len(req.ServingSizes) > 0 {
+		target.ServingSizes = req.ServingSizes
+	}
+	if req.Calories != nil {
+		calories := *req.Calories
+		target.Calories = &calories
+	}
+	if req.Visibility != "" {
+		target.Visibility = req.Visibility
+	}
+	if req.ImageURL != "" {
+		target.ImageURL = req.ImageURL
+	}
+	if req.Allergens != nil {
+		target.Allergens = req.Allergens
+	}
+	if req.DietTags != nil {
+		target.DietTags = req.DietTags
+	}
+}
@@ -0,0 +1,972 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/png"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"nutrient_be/internal/config"
+	"nutrient_be/internal/domain"
+	"nutrient_be/internal/dto/request"
+	"nutrient_be/internal/pkg/cache"
+	"nutrient_be/internal/pkg/logger"
+	"nutrient_be/internal/pkg/validator"
+)
+
+func newTestFood(ownerID primitive.ObjectID, visibility string) *domain.FoodItem {
+	return &domain.FoodItem{
+		ID:         primitive.NewObjectID(),
+		Name:       map[string]string{"en": "Chicken breast"},
+		Category:   "protein",
+		Macros:     domain.MacroNutrients{Protein: 31, Fat: 3.6},
+		Calories:   156.4,
+		CreatedBy:  ownerID,
+		Visibility: visibility,
+		Source:     "user",
+		ServingSizes: []domain.ServingSize{
+			{Unit: "gram", Amount: 100, GramEquivalent: 100},
+		},
+	}
+}
+
+func newTestFoodService(foods *fakeFoodRepo) *FoodService {
+	return NewFoodService(foods, nil, nil, NewNoopTransactor(), cache.NewLRUCache(100, time.Minute), newFakeStorage(), 1024*1024, config.FoodConfig{ServingTolerance: 0.001}, logger.NewNoopLogger())
+}
+
+func TestFoodService_GetFoodByID_Cache(t *testing.T) {
+	ctx := context.Background()
+	owner := primitive.NewObjectID()
+	public := newTestFood(owner, "public")
+	private := newTestFood(owner, "private")
+	foods := newFakeFoodRepo(public, private)
+	svc := newTestFoodService(foods)
+
+	t.Run("miss then hit for public food", func(t *testing.T) {
+		for i := 0; i < 2; i++ {
+			food, err := svc.GetFoodByID(ctx, public.ID.Hex())
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			if food.Name["en"] != "Chicken breast" {
+				t.Errorf("Expected cached food name, got: %v", food.Name)
+			}
+		}
+		if foods.getByIDCalls != 1 {
+			t.Errorf("Expected 1 repository call, got: %d", foods.getByIDCalls)
+		}
+	})
+
+	t.Run("private food is never cached", func(t *testing.T) {
+		foods.getByIDCalls = 0
+		for i := 0; i < 2; i++ {
+			if _, err := svc.GetFoodByID(ctx, private.ID.Hex()); err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+		}
+		if foods.getByIDCalls != 2 {
+			t.Errorf("Expected 2 repository calls, got: %d", foods.getByIDCalls)
+		}
+	})
+}
+
+func TestFoodService_UpdateFood_InvalidatesCache(t *testing.T) {
+	ctx := context.Background()
+	owner := primitive.NewObjectID()
+	food := newTestFood(owner, "public")
+	foods := newFakeFoodRepo(food)
+	svc := newTestFoodService(foods)
+
+	if _, err := svc.GetFoodByID(ctx, food.ID.Hex()); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if _, err := svc.ListPublicFoods(ctx, 20, 0); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	name := request.MultiLanguage{"en": "Grilled chicken"}
+	if _, _, err := svc.UpdateFood(ctx, owner.Hex(), food.ID.Hex(), &request.UpdateFoodRequest{Name: name}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	got, err := svc.GetFoodByID(ctx, food.ID.Hex())
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if got.Name["en"] != "Grilled chicken" {
+		t.Errorf("Expected updated name after invalidation, got: %v", got.Name)
+	}
+
+	list, err := svc.ListPublicFoods(ctx, 20, 0)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if foods.publicCalls != 2 {
+		t.Errorf("Expected public list to be reloaded, got: %d repository calls", foods.publicCalls)
+	}
+	if len(list) != 1 || list[0].Name["en"] != "Grilled chicken" {
+		t.Errorf("Expected updated food in public list, got: %v", list)
+	}
+}
+
+func TestFoodService_DeleteFood_InvalidatesCache(t *testing.T) {
+	ctx := context.Background()
+	owner := primitive.NewObjectID()
+	food := newTestFood(owner, "public")
+	svc := newTestFoodService(newFakeFoodRepo(food))
+
+	if _, err := svc.GetFoodByID(ctx, food.ID.Hex()); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if err := svc.DeleteFood(ctx, owner.Hex(), food.ID.Hex()); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if _, err := svc.GetFoodByID(ctx, food.ID.Hex()); err == nil {
+		t.Error("Expected deleted food not to be served from cache")
+	}
+}
+
+func TestFoodService_ReturnsServingCaloriesWarning(t *testing.T) {
+	ctx := context.Background()
+	owner := primitive.NewObjectID()
+	foods := newFakeFoodRepo()
+	svc := NewFoodService(foods, nil, nil, NewNoopTransactor(), cache.NewNoopCache(), newFakeStorage(), 1024*1024, config.FoodConfig{ServingTolerance: 0.001, MaxServingCalories: 2000}, logger.NewNoopLogger())
+
+	// A 1 piece serving of 5000g of chicken breast implies 7820 kcal
+	req := &request.CreateFoodRequest{
+		Name:         request.MultiLanguage{"en": "Chicken breast"},
+		Category:     "protein",
+		Macros:       request.MacroNutrientsRequest{Protein: 31, Fat: 3.6},
+		ServingSizes: []request.ServingSizeRequest{{Unit: "gram", Amount: 100, GramEquivalent: 100}, {Unit: "piece", Amount: 1, GramEquivalent: 5000}},
+		Visibility:   "private",
+	}
+	warnings, err := svc.CreateFood(ctx, owner.Hex(), req)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(warnings) != 1 || warnings[0].Code != validator.FoodWarningServingCalories {
+		t.Fatalf("Expected a serving calories warning on create, got: %+v", warnings)
+	}
+
+	var food *domain.FoodItem
+	for _, f := range foods.foods {
+		food = f
+	}
+	reasonable := []request.ServingSizeRequest{{Unit: "gram", Amount: 100, GramEquivalent: 100}, {Unit: "piece", Amount: 1, GramEquivalent: 174}}
+	if _, warnings, err = svc.UpdateFood(ctx, owner.Hex(), food.ID.Hex(), &request.UpdateFoodRequest{ServingSizes: reasonable}); err != nil || len(warnings) != 0 {
+		t.Errorf("Expected a reasonable serving to have no warnings, got: %+v, %v", warnings, err)
+	}
+	if _, warnings, err = svc.UpdateFood(ctx, owner.Hex(), food.ID.Hex(), &request.UpdateFoodRequest{ServingSizes: req.ServingSizes}); err != nil || len(warnings) != 1 {
+		t.Errorf("Expected a serving calories warning on update, got: %+v, %v", warnings, err)
+	}
+}
+
+func TestFoodService_UpdateFood_RequiresOwner(t *testing.T) {
+	ctx := context.Background()
+	food := newTestFood(primitive.NewObjectID(), "public")
+	svc := newTestFoodService(newFakeFoodRepo(food))
+
+	_, _, err := svc.UpdateFood(ctx, primitive.NewObjectID().Hex(), food.ID.Hex(), &request.UpdateFoodRequest{})
+	if err == nil || err.Error() != "food item not found or access denied" {
+		t.Errorf("Expected access denied, got: %v", err)
+	}
+}
+
+func TestFoodService_CloneFood_PublicFoodBecomesEditablePrivateCopy(t *testing.T) {
+	ctx := context.Background()
+	source := newTestFood(primitive.NewObjectID(), "public")
+	source.Source = domain.FoodSourceImported
+	foods := newFakeFoodRepo(source)
+	svc := newTestFoodService(foods)
+	userID := primitive.NewObjectID()
+
+	clone, err := svc.CloneFood(ctx, userID.Hex(), source.ID.Hex(), " (copy)")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if clone.ID.IsZero() || clone.ID == source.ID {
+		t.Fatalf("Expected a fresh ID, got: %s", clone.ID.Hex())
+	}
+	if clone.CreatedBy != userID || clone.Visibility != "private" || clone.Source != domain.FoodSourceUser {
+		t.Errorf("Expected a private user food owned by the cloner, got: createdBy %s, %s, %s", clone.CreatedBy.Hex(), clone.Visibility, clone.Source)
+	}
+	if clone.Name["en"] != "Chicken breast (copy)" || clone.Calories != source.Calories || clone.Macros != source.Macros {
+		t.Errorf("Expected the source's values with the suffixed name, got: %v, %v kcal, %+v", clone.Name, clone.Calories, clone.Macros)
+	}
+	if _, ok := foods.foods[clone.ID]; !ok {
+		t.Fatal("Expected the clone to be stored")
+	}
+
+	calories := 200.0
+	if _, _, err := svc.UpdateFood(ctx, userID.Hex(), clone.ID.Hex(), &request.UpdateFoodRequest{Calories: &calories, Macros: &request.MacroNutrientsRequest{Protein: 35, Fat: 6.7}}); err != nil {
+		t.Fatalf("Expected the clone to be editable by its owner, got: %v", err)
+	}
+	if source.Name["en"] != "Chicken breast" || source.Calories != 156.4 || source.ServingSizes[0].Amount != 100 {
+		t.Errorf("Expected the source to stay unchanged, got: %v, %v kcal", source.Name, source.Calories)
+	}
+}
+
+func TestFoodService_CloneFood_RejectsInaccessiblePrivateFood(t *testing.T) {
+	source := newTestFood(primitive.NewObjectID(), "private")
+	foods := newFakeFoodRepo(source)
+	svc := newTestFoodService(foods)
+
+	_, err := svc.CloneFood(context.Background(), primitive.NewObjectID().Hex(), source.ID.Hex(), "")
+	if err == nil || err.Error() != "food item not found or access denied" {
+		t.Errorf("Expected access denied, got: %v", err)
+	}
+	if len(foods.foods) != 1 {
+		t.Errorf("Expected no food to be created, got: %d foods", len(foods.foods))
+	}
+
+	if _, err := svc.CloneFood(context.Background(), source.CreatedBy.Hex(), source.ID.Hex(), ""); err != nil {
+		t.Errorf("Expected the owner to clone their private food, got: %v", err)
+	}
+}
+
+func TestFoodService_UploadImage_ValidPNG(t *testing.T) {
+	owner := primitive.NewObjectID()
+	food := newTestFood(owner, "public")
+	images := newFakeStorage()
+	svc := NewFoodService(newFakeFoodRepo(food), nil, nil, NewNoopTransactor(), cache.NewLRUCache(100, time.Minute), images, 1024*1024, config.FoodConfig{ServingTolerance: 0.001}, logger.NewNoopLogger())
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 4, 4))); err != nil {
+		t.Fatalf("Failed to encode test image: %v", err)
+	}
+
+	updated, err := svc.UploadImage(context.Background(), owner.Hex(), food.ID.Hex(), buf.Bytes())
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	key := "foods/" + food.ID.Hex() + ".png"
+	if updated.ImageURL != "/api/v1/foods/"+food.ID.Hex()+"/image" {
+		t.Errorf("Expected the image to be served by the food image endpoint, got: %s", updated.ImageURL)
+	}
+	if !bytes.Equal(images.files[key], buf.Bytes()) {
+		t.Error("Expected image to be stored")
+	}
+
+	_, err = svc.UploadImage(context.Background(), primitive.NewObjectID().Hex(), food.ID.Hex(), buf.Bytes())
+	if err == nil || err.Error() != "food item not found or access denied" {
+		t.Errorf("Expected access denied error for non-owner, got: %v", err)
+	}
+}
+
+func TestFoodService_GetImage_PrivateFoodOnlyForOwner(t *testing.T) {
+	ctx := context.Background()
+	owner := primitive.NewObjectID()
+	food := newTestFood(owner, "private")
+	images := newFakeStorage()
+	svc := NewFoodService(newFakeFoodRepo(food), nil, nil, NewNoopTransactor(), cache.NewLRUCache(100, time.Minute), images, 1024*1024, config.FoodConfig{ServingTolerance: 0.001}, logger.NewNoopLogger())
+
+	if _, _, err := svc.GetImage(ctx, owner.Hex(), food.ID.Hex()); err == nil || err.Error() != "food image not found" {
+		t.Errorf("Expected a missing image error before any upload, got: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 4, 4))); err != nil {
+		t.Fatalf("Failed to encode test image: %v", err)
+	}
+	if _, err := svc.UploadImage(ctx, owner.Hex(), food.ID.Hex(), buf.Bytes()); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	data, contentType, err := svc.GetImage(ctx, owner.Hex(), food.ID.Hex())
+	if err != nil || contentType != "image/png" || !bytes.Equal(data, buf.Bytes()) {
+		t.Errorf("Expected the owner to get the PNG, got: %s, %v", contentType, err)
+	}
+	if _, _, err := svc.GetImage(ctx, primitive.NewObjectID().Hex(), food.ID.Hex()); err == nil || err.Error() != "food item not found or access denied" {
+		t.Errorf("Expected access denied for another user, got: %v", err)
+	}
+}
+
+func TestFoodService_UploadImage_RejectsOversizedImage(t *testing.T) {
+	owner := primitive.NewObjectID()
+	food := newTestFood(owner, "public")
+	svc := NewFoodService(newFakeFoodRepo(food), nil, nil, NewNoopTransactor(), cache.NewLRUCache(100, time.Minute), newFakeStorage(), 4, config.FoodConfig{ServingTolerance: 0.001}, logger.NewNoopLogger())
+
+	_, err := svc.UploadImage(context.Background(), owner.Hex(), food.ID.Hex(), []byte("12345"))
+	if !errors.Is(err, ErrImageTooLarge) {
+		t.Errorf("Expected ErrImageTooLarge, got: %v", err)
+	}
+}
+
+func TestFoodService_UploadImage_RejectsNonImage(t *testing.T) {
+	owner := primitive.NewObjectID()
+	food := newTestFood(owner, "public")
+	images := newFakeStorage()
+	svc := NewFoodService(newFakeFoodRepo(food), nil, nil, NewNoopTransactor(), cache.NewLRUCache(100, time.Minute), images, 1024*1024, config.FoodConfig{ServingTolerance: 0.001}, logger.NewNoopLogger())
+
+	// Content decides, not the file name; this is plain text
+	_, err := svc.UploadImage(context.Background(), owner.Hex(), food.ID.Hex(), []byte("definitely not a picture"))
+	if err == nil || !strings.HasPrefix(err.Error(), "validation failed: image must be a JPEG or PNG") {
+		t.Errorf("Expected content type validation error, got: %v", err)
+	}
+	if len(images.files) != 0 || food.ImageURL != "" {
+		t.Error("Expected nothing to be stored for a rejected upload")
+	}
+}
+
+func TestFoodService_SearchFood_IgnoresDiacritics(t *testing.T) {
+	ctx := context.Background()
+	owner := primitive.NewObjectID()
+	foods := newFakeFoodRepo()
+	svc := newTestFoodService(foods)
+
+	for _, name := range []request.MultiLanguage{
+		{"en": "Apple", "vi": "Táo"},
+		{"en": "Pork roll", "vi": "Chả lụa"},
+	} {
+		req := &request.CreateFoodRequest{
+			Name:         name,
+			Category:     "protein",
+			Macros:       request.MacroNutrientsRequest{Protein: 10},
+			ServingSizes: []request.ServingSizeRequest{{Unit: "gram", Amount: 100, GramEquivalent: 100}},
+			Calories:     float64Ptr(40),
+			Visibility:   "public",
+		}
+		if _, err := svc.CreateFood(ctx, owner.Hex(), req); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+	}
+
+	tests := []struct {
+		query    string
+		expected string
+	}{
+		{"tao", "Táo"},
+		{"cha", "Chả lụa"},
+		{"Táo", "Táo"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			results, err := svc.SearchFood(ctx, &request.SearchFoodRequest{Query: tt.query, Limit: 20})
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			if len(results) != 1 || results[0].Name["vi"] != tt.expected {
+				t.Fatalf("Expected %q to match %q, got: %v", tt.query, tt.expected, results)
+			}
+		})
+	}
+}
+
+func TestFoodService_SearchFood_SourceFilter(t *testing.T) {
+	ctx := context.Background()
+	owner := primitive.NewObjectID()
+
+	newSourcedFood := func(source string) *domain.FoodItem {
+		food := newTestFood(owner, "public")
+		food.Source = source
+		food.SearchTerms = []string{"chicken breast"}
+		return food
+	}
+	imported := newSourcedFood(domain.FoodSourceImported)
+	userFoods := []*domain.FoodItem{newSourcedFood(domain.FoodSourceUser), newSourcedFood(domain.FoodSourceUser)}
+	svc := newTestFoodService(newFakeFoodRepo(append(userFoods, imported)...))
+
+	results, err := svc.SearchFood(ctx, &request.SearchFoodRequest{Query: "chicken", Source: domain.FoodSourceImported, Limit: 20})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(results) != 1 || results[0].ID != imported.ID {
+		t.Fatalf("Expected only the imported food, got: %v", results)
+	}
+
+	results, err = svc.SearchFood(ctx, &request.SearchFoodRequest{Query: "chicken", ImportedFirst: true, Limit: 20})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(results) != 3 || results[0].ID != imported.ID {
+		t.Errorf("Expected all 3 foods with the imported one first, got: %v", results)
+	}
+}
+
+func TestFoodService_SearchFood_AnonymousOnlySeesPublic(t *testing.T) {
+	public := newTestFood(primitive.NewObjectID(), "public")
+	ownerless := newTestFood(primitive.NilObjectID, "public")
+	ownerlessPrivate := newTestFood(primitive.NilObjectID, "private")
+	private := newTestFood(primitive.NewObjectID(), "private")
+	svc := newTestFoodService(newFakeFoodRepo(public, ownerless, ownerlessPrivate, private))
+
+	results, err := svc.SearchFood(context.Background(), &request.SearchFoodRequest{Limit: 20})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("Expected the 2 public foods, got: %d", len(results))
+	}
+	for _, food := range results {
+		if food.Visibility != "public" {
+			t.Errorf("Expected only public foods, got %s food %s", food.Visibility, food.ID.Hex())
+		}
+	}
+}
+
+func TestFoodService_SearchFood_CombinesQueryCategoryAndMacroRange(t *testing.T) {
+	ctx := context.Background()
+	owner := primitive.NewObjectID()
+
+	newFood := func(category string, protein, fat float64) *domain.FoodItem {
+		food := newTestFood(owner, "public")
+		food.Category = category
+		food.Macros = domain.MacroNutrients{Protein: protein, Fat: fat}
+		food.SearchTerms = []string{"chicken"}
+		return food
+	}
+	lean := newFood("protein", 31, 3.6)
+	fatty := newFood("protein", 25, 15)
+	salad := newFood("vegetable", 31, 3)
+	other := newTestFood(owner, "public") // Searched for under another name
+	other.SearchTerms = []string{"beef"}
+	foods := newFakeFoodRepo(lean, fatty, salad, other)
+	svc := newTestFoodService(foods)
+
+	minProtein, maxFat := 20.0, 5.0
+	results, err := svc.SearchFood(ctx, &request.SearchFoodRequest{
+		Query:      "chicken",
+		Categories: []string{"protein"},
+		MinProtein: &minProtein,
+		MaxFat:     &maxFat,
+		Tags:       []string{" Chicken "},
+		Limit:      20,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(results) != 1 || results[0].ID != lean.ID {
+		t.Fatalf("Expected only the lean chicken, got: %v", results)
+	}
+	if got := foods.lastFilter.Tags; len(got) != 1 || got[0] != "chicken" {
+		t.Errorf("Expected tags to be normalized, got: %v", got)
+	}
+}
+
+func TestFoodService_SearchFood_FiltersAllergensAndDietTags(t *testing.T) {
+	ctx := context.Background()
+	owner := primitive.NewObjectID()
+
+	newFood := func(allergens, dietTags []string) *domain.FoodItem {
+		food := newTestFood(owner, "public")
+		food.SearchTerms = []string{"bar"}
+		food.Allergens = allergens
+		food.DietTags = dietTags
+		return food
+	}
+	match := newFood(nil, []string{"vegan", "gluten_free"})
+	peanut := newFood([]string{"peanut"}, []string{"vegan", "gluten_free"})
+	veganOnly := newFood(nil, []string{"vegan"})
+	foods := newFakeFoodRepo(match, peanut, veganOnly)
+	svc := newTestFoodService(foods)
+
+	results, err := svc.SearchFood(ctx, &request.SearchFoodRequest{
+		Query:            "bar",
+		ExcludeAllergens: []string{" Peanut "},
+		DietTags:         []string{"Vegan", "gluten_free"},
+		Limit:            20,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(results) != 1 || results[0].ID != match.ID {
+		t.Fatalf("Expected only the peanut-free vegan gluten-free food, got: %v", results)
+	}
+	if got := foods.lastFilter.ExcludeAllergens; len(got) != 1 || got[0] != "peanut" {
+		t.Errorf("Expected allergens to be normalized, got: %v", got)
+	}
+}
+
+func TestFoodService_CreateFood_NormalizesLabels(t *testing.T) {
+	foods := newFakeFoodRepo()
+	svc := newTestFoodService(foods)
+
+	req := &request.CreateFoodRequest{
+		Name:         request.MultiLanguage{"en": "Peanut butter"},
+		Category:     "protein",
+		Macros:       request.MacroNutrientsRequest{Protein: 25, Fat: 50},
+		ServingSizes: []request.ServingSizeRequest{{Unit: "gram", Amount: 100, GramEquivalent: 100}},
+		Visibility:   "private",
+		Allergens:    []string{"Peanut", " peanut", ""},
+		DietTags:     []string{"Vegan"},
+	}
+	if _, err := svc.CreateFood(context.Background(), primitive.NewObjectID().Hex(), req); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	for _, food := range foods.foods {
+		if len(food.Allergens) != 1 || food.Allergens[0] != "peanut" || len(food.DietTags) != 1 || food.DietTags[0] != "vegan" {
+			t.Errorf("Expected lowercased labels without duplicates, got: %v, %v", food.Allergens, food.DietTags)
+		}
+	}
+}
+
+func TestFoodService_SearchFood_RejectsInvertedRange(t *testing.T) {
+	svc := newTestFoodService(newFakeFoodRepo())
+
+	minCalories, maxCalories := 300.0, 100.0
+	_, err := svc.SearchFood(context.Background(), &request.SearchFoodRequest{Query: "rice", MinCalories: &minCalories, MaxCalories: &maxCalories})
+	if err == nil || !strings.HasPrefix(err.Error(), "validation failed:") {
+		t.Errorf("Expected a validation error, got: %v", err)
+	}
+}
+
+func TestFoodService_CreateFood_StoresNormalizedSearchTerms(t *testing.T) {
+	ctx := context.Background()
+	foods := newFakeFoodRepo()
+	svc := newTestFoodService(foods)
+
+	req := &request.CreateFoodRequest{
+		Name:         request.MultiLanguage{"en": "Apple", "vi": "Táo"},
+		SearchTerms:  []string{"Trái Táo", "tao"},
+		Category:     "fruit",
+		Macros:       request.MacroNutrientsRequest{Carbohydrates: 14},
+		ServingSizes: []request.ServingSizeRequest{{Unit: "gram", Amount: 100, GramEquivalent: 100}},
+		Calories:     float64Ptr(56),
+		Visibility:   "private",
+	}
+	if _, err := svc.CreateFood(ctx, primitive.NewObjectID().Hex(), req); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	for _, food := range foods.foods {
+		if food.Name["vi"] != "Táo" {
+			t.Errorf("Expected the original name to be kept, got: %q", food.Name["vi"])
+		}
+		if strings.Join(food.SearchTerms, ",") != "trai tao,tao,apple" {
+			t.Errorf("Expected normalized search terms [trai tao tao apple], got: %v", food.SearchTerms)
+		}
+	}
+}
+
+func TestFoodService_CreateFood_RejectsPrivateFoodWithoutOwner(t *testing.T) {
+	foods := newFakeFoodRepo()
+	svc := newTestFoodService(foods)
+
+	req := &request.CreateFoodRequest{
+		Name:         request.MultiLanguage{"en": "Apple"},
+		Category:     "fruit",
+		Macros:       request.MacroNutrientsRequest{Carbohydrates: 14},
+		ServingSizes: []request.ServingSizeRequest{{Unit: "gram", Amount: 100, GramEquivalent: 100}},
+		Calories:     float64Ptr(56),
+		Visibility:   "private",
+	}
+	if _, err := svc.CreateFood(context.Background(), "", req); err == nil || !strings.HasPrefix(err.Error(), "invalid ") {
+		t.Fatalf("Expected an invalid owner error, got: %v", err)
+	}
+	if len(foods.foods) != 0 {
+		t.Errorf("Expected nothing to be stored, got: %d foods", len(foods.foods))
+	}
+}
+
+func TestFoodService_CreateFood_EstimatesOmittedCalories(t *testing.T) {
+	ctx := context.Background()
+	foods := newFakeFoodRepo()
+	svc := newTestFoodService(foods)
+
+	req := &request.CreateFoodRequest{
+		Name:         request.MultiLanguage{"en": "Chicken breast"},
+		Category:     "protein",
+		Macros:       request.MacroNutrientsRequest{Protein: 31, Fat: 3.6},
+		ServingSizes: []request.ServingSizeRequest{{Unit: "gram", Amount: 100, GramEquivalent: 100}},
+		Visibility:   "private",
+	}
+	if _, err := svc.CreateFood(ctx, primitive.NewObjectID().Hex(), req); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// 31*4 + 3.6*9
+	for _, food := range foods.foods {
+		if food.Calories < 156.39 || food.Calories > 156.41 {
+			t.Errorf("Expected calories 156.4 estimated from macros, got: %v", food.Calories)
+		}
+	}
+}
+
+func TestFoodService_CreateFood_ValidatesExplicitCalories(t *testing.T) {
+	ctx := context.Background()
+	foods := newFakeFoodRepo()
+	svc := newTestFoodService(foods)
+
+	req := &request.CreateFoodRequest{
+		Name:         request.MultiLanguage{"en": "Chicken breast"},
+		Category:     "protein",
+		Macros:       request.MacroNutrientsRequest{Protein: 31, Fat: 3.6},
+		ServingSizes: []request.ServingSizeRequest{{Unit: "gram", Amount: 100, GramEquivalent: 100}},
+		Calories:     float64Ptr(400),
+		Visibility:   "private",
+	}
+	_, err := svc.CreateFood(ctx, primitive.NewObjectID().Hex(), req)
+	if err == nil || !strings.Contains(err.Error(), "calories consistency") {
+		t.Fatalf("Expected a calories consistency error, got: %v", err)
+	}
+	if len(foods.foods) != 0 {
+		t.Errorf("Expected no food to be stored, got: %d", len(foods.foods))
+	}
+}
+
+func TestFoodService_ExplicitZeroCaloriesAreNotEstimated(t *testing.T) {
+	ctx := context.Background()
+	foods := newFakeFoodRepo()
+	svc := newTestFoodService(foods)
+
+	newRequest := func() *request.CreateFoodRequest {
+		return &request.CreateFoodRequest{
+			Name:         request.MultiLanguage{"en": "Chicken breast"},
+			Category:     "protein",
+			Macros:       request.MacroNutrientsRequest{Protein: 31, Fat: 3.6},
+			ServingSizes: []request.ServingSizeRequest{{Unit: "gram", Amount: 100, GramEquivalent: 100}},
+			Calories:     float64Ptr(0),
+			Visibility:   "private",
+		}
+	}
+
+	_, err := svc.CreateFood(ctx, primitive.NewObjectID().Hex(), newRequest())
+	if err == nil || !strings.Contains(err.Error(), "calories consistency") {
+		t.Fatalf("Expected a calories consistency error, got: %v", err)
+	}
+	if len(foods.foods) != 0 {
+		t.Errorf("Expected no food to be stored, got: %d", len(foods.foods))
+	}
+
+	req := newRequest()
+	report := svc.ValidateFood(ctx, req)
+	if report.Valid || *req.Calories != 0 {
+		t.Errorf("Expected explicit 0 calories to be checked as sent, got: valid %v, %v kcal", report.Valid, *req.Calories)
+	}
+}
+
+func TestFoodService_CreateFood_RejectsNaNMacro(t *testing.T) {
+	ctx := context.Background()
+	foods := newFakeFoodRepo()
+	svc := newTestFoodService(foods)
+
+	req := &request.CreateFoodRequest{
+		Name:         request.MultiLanguage{"en": "Chicken breast"},
+		Category:     "protein",
+		Macros:       request.MacroNutrientsRequest{Protein: math.NaN(), Fat: 3.6},
+		ServingSizes: []request.ServingSizeRequest{{Unit: "gram", Amount: 100, GramEquivalent: 100}},
+		Visibility:   "private",
+	}
+	_, err := svc.CreateFood(ctx, primitive.NewObjectID().Hex(), req)
+	if err == nil || !strings.HasPrefix(err.Error(), "validation failed:") || !strings.Contains(err.Error(), "protein must be a finite number") {
+		t.Fatalf("Expected a protein validation error, got: %v", err)
+	}
+	if len(foods.foods) != 0 {
+		t.Errorf("Expected no food to be stored, got: %d", len(foods.foods))
+	}
+}
+
+func TestFoodService_EstimateCalories(t *testing.T) {
+	svc := newTestFoodService(newFakeFoodRepo())
+
+	got := svc.EstimateCalories(request.MacroNutrientsRequest{Protein: 10, Carbohydrates: 20, Fat: 5, Fiber: 3})
+	if got != 171 {
+		t.Errorf("Expected 171 calories, got: %v", got)
+	}
+}
+
+func TestFoodService_MacroBreakdown_AllFat(t *testing.T) {
+	ctx := context.Background()
+	owner := primitive.NewObjectID()
+	oil := newTestFood(owner, "public")
+	oil.Macros = domain.MacroNutrients{Fat: 100}
+	oil.Calories = 900
+	svc := newTestFoodService(newFakeFoodRepo(oil))
+
+	breakdown, err := svc.MacroBreakdown(ctx, primitive.NewObjectID().Hex(), oil.ID.Hex())
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if math.Abs(breakdown.FatPercent-100) > 1e-9 {
+		t.Errorf("Expected 100%% of calories from fat, got: %v", breakdown.FatPercent)
+	}
+	if breakdown.ProteinPercent != 0 || breakdown.CarbohydratesPercent != 0 || breakdown.FiberPercent != 0 {
+		t.Errorf("Expected no calories from other macros, got: %+v", breakdown)
+	}
+	if breakdown.CaloriesMismatch {
+		t.Error("Expected matching calories not to be flagged")
+	}
+}
+
+func TestFoodService_MacroBreakdown_FlagsCalorieMismatch(t *testing.T) {
+	ctx := context.Background()
+	owner := primitive.NewObjectID()
+	food := newTestFood(owner, "private")
+	food.Macros = domain.MacroNutrients{Protein: 10, Fiber: 5}
+	food.Calories = 100 // Macros only account for 50 kcal
+	svc := newTestFoodService(newFakeFoodRepo(food))
+
+	breakdown, err := svc.MacroBreakdown(ctx, owner.Hex(), food.ID.Hex())
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if !breakdown.CaloriesMismatch {
+		t.Error("Expected the calorie mismatch to be flagged")
+	}
+	if breakdown.ProteinPercent != 40 || breakdown.FiberCalories != 10 || breakdown.FiberPercent != 10 {
+		t.Errorf("Expected shares of the stored 100 kcal, got: %+v", breakdown)
+	}
+
+	if _, err := svc.MacroBreakdown(ctx, primitive.NewObjectID().Hex(), food.ID.Hex()); err == nil || err.Error() != "food item not found or access denied" {
+		t.Errorf("Expected another user's private food to be hidden, got: %v", err)
+	}
+}
+
+func TestFoodService_Merge(t *testing.T) {
+	ctx := context.Background()
+	owner := primitive.NewObjectID()
+	keep := newTestFood(owner, "public")
+	duplicate := newTestFood(owner, "public")
+	other := newTestFood(owner, "public")
+	foods := newFakeFoodRepo(keep, duplicate, other)
+
+	template := &domain.MealTemplate{
+		ID:     primitive.NewObjectID(),
+		UserID: owner,
+		FoodItems: []domain.MealTemplateFoodItem{
+			{FoodItemID: duplicate.ID, FoodName: "Chicken breast"},
+			{FoodItemID: other.ID, FoodName: "Chicken breast"},
+		},
+	}
+	untouched := &domain.MealTemplate{
+		ID:        primitive.NewObjectID(),
+		UserID:    owner,
+		FoodItems: []domain.MealTemplateFoodItem{{FoodItemID: other.ID}},
+	}
+	plan := &domain.MealPlan{
+		ID:     primitive.NewObjectID(),
+		UserID: owner,
+		DailyMeals: []domain.DailyMeal{
+			{Meals: []domain.Meal{{ID: "m1", FoodItems: []domain.MealFoodItem{{FoodItemID: duplicate.ID}}}}},
+			{Meals: []domain.Meal{{ID: "m2", FoodItems: []domain.MealFoodItem{{FoodItemID: duplicate.ID}, {FoodItemID: keep.ID}}}}},
+		},
+	}
+	templates := newFakeMealTemplateRepo(template, untouched)
+	plans := newFakeMealPlanRepo(plan)
+	svc := NewFoodService(foods, templates, plans, NewNoopTransactor(), cache.NewLRUCache(100, time.Minute), newFakeStorage(), 1024*1024, config.FoodConfig{ServingTolerance: 0.001}, logger.NewNoopLogger())
+
+	result, err := svc.Merge(ctx, primitive.NewObjectID().Hex(), keep.ID.Hex(), duplicate.ID.Hex())
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if result.TemplatesUpdated != 1 || result.MealPlansUpdated != 1 {
+		t.Errorf("Expected 1 template and 1 meal plan updated, got: %d and %d", result.TemplatesUpdated, result.MealPlansUpdated)
+	}
+
+	if template.FoodItems[0].FoodItemID != keep.ID || template.FoodItems[1].FoodItemID != other.ID {
+		t.Errorf("Expected only the duplicate reference to be repointed, got: %v", template.FoodItems)
+	}
+	for _, day := range plan.DailyMeals {
+		for _, item := range day.Meals[0].FoodItems {
+			if item.FoodItemID == duplicate.ID {
+				t.Errorf("Expected no meal plan references to the merged food, got: %v", day.Meals[0].FoodItems)
+			}
+		}
+	}
+	merged, ok := foods.foods[duplicate.ID]
+	if !ok {
+		t.Fatal("Expected the merged food record to be kept")
+	}
+	if merged.DeletedAt == nil || merged.MergedInto == nil || *merged.MergedInto != keep.ID {
+		t.Errorf("Expected the merged food to be marked as merged into %s, got: %v", keep.ID.Hex(), merged.MergedInto)
+	}
+	if _, err := foods.GetByID(ctx, duplicate.ID); err == nil {
+		t.Error("Expected the merged food to be hidden from reads")
+	}
+	if _, ok := foods.foods[keep.ID]; !ok {
+		t.Error("Expected the kept food to remain")
+	}
+}
+
+func TestFoodService_Merge_Rejects(t *testing.T) {
+	ctx := context.Background()
+	keep := newTestFood(primitive.NewObjectID(), "public")
+	svc := NewFoodService(newFakeFoodRepo(keep), newFakeMealTemplateRepo(), newFakeMealPlanRepo(), NewNoopTransactor(), cache.NewNoopCache(), newFakeStorage(), 1024*1024, config.FoodConfig{}, logger.NewNoopLogger())
+
+	if _, err := svc.Merge(ctx, "", keep.ID.Hex(), keep.ID.Hex()); err == nil || !strings.HasPrefix(err.Error(), "invalid ") {
+		t.Errorf("Expected merging a food into itself to be invalid, got: %v", err)
+	}
+	if _, err := svc.Merge(ctx, "", keep.ID.Hex(), primitive.NewObjectID().Hex()); err == nil || err.Error() != "food item not found or access denied" {
+		t.Errorf("Expected a missing food to be reported as not found, got: %v", err)
+	}
+}
+
+func TestFoodService_Merge_RollsBackWhenAStepFails(t *testing.T) {
+	ctx := context.Background()
+	owner := primitive.NewObjectID()
+	keep := newTestFood(owner, "public")
+	duplicate := newTestFood(owner, "public")
+	foods := newFakeFoodRepo(keep, duplicate)
+	template := &domain.MealTemplate{
+		ID:        primitive.NewObjectID(),
+		UserID:    owner,
+		FoodItems: []domain.MealTemplateFoodItem{{FoodItemID: duplicate.ID}},
+	}
+	templates := newFakeMealTemplateRepo(template)
+	tx := newFakeTransactor(templates.snapshot, foods.snapshot)
+
+	// Templates are repointed first, then repointing meal plans fails
+	plans := &failingReferenceRepo{err: errors.New("connection reset")}
+	svc := NewFoodService(foods, templates, plans, tx, cache.NewNoopCache(), newFakeStorage(), 1024*1024, config.FoodConfig{}, logger.NewNoopLogger())
+
+	if _, err := svc.Merge(ctx, primitive.NewObjectID().Hex(), keep.ID.Hex(), duplicate.ID.Hex()); err == nil {
+		t.Fatal("Expected the merge to fail")
+	}
+	if tx.calls != 1 {
+		t.Errorf("Expected the merge to run in 1 transaction, got: %d", tx.calls)
+	}
+	if got := templates.templates[template.ID].FoodItems[0].FoodItemID; got != duplicate.ID {
+		t.Errorf("Expected the template reference to be rolled back to the merged food, got: %s", got.Hex())
+	}
+	if _, ok := foods.foods[duplicate.ID]; !ok {
+		t.Error("Expected the merged food to still exist")
+	}
+}
+
+func TestFoodService_BulkSetVisibility_PublishesCategory(t *testing.T) {
+	ctx := context.Background()
+	owner := primitive.NewObjectID()
+	chicken := newTestFood(owner, "private")
+	beef := newTestFood(owner, "private")
+	rice := newTestFood(owner, "private")
+	rice.Category = "grain"
+	othersChicken := newTestFood(primitive.NewObjectID(), "private")
+	foods := newFakeFoodRepo(chicken, beef, rice, othersChicken)
+	svc := newTestFoodService(foods)
+
+	// A cached public list must not keep serving the old visibility
+	if _, err := svc.ListPublicFoods(ctx, 10, 0); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	updated, err := svc.BulkSetVisibility(ctx, owner.Hex(), &request.BulkSetFoodVisibilityRequest{Category: "protein", Visibility: "public"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if updated != 2 {
+		t.Errorf("Expected 2 foods updated, got: %d", updated)
+	}
+	if chicken.Visibility != "public" || beef.Visibility != "public" {
+		t.Errorf("Expected the owner's protein foods to be public, got: %s and %s", chicken.Visibility, beef.Visibility)
+	}
+	if rice.Visibility != "private" || othersChicken.Visibility != "private" {
+		t.Errorf("Expected other categories and other users' foods to stay private")
+	}
+
+	public, err := svc.ListPublicFoods(ctx, 10, 0)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(public) != 2 {
+		t.Errorf("Expected 2 public foods after the update, got: %d", len(public))
+	}
+}
+
+func TestFoodService_BulkSetVisibility_RequiresFilter(t *testing.T) {
+	owner := primitive.NewObjectID()
+	food := newTestFood(owner, "private")
+	svc := newTestFoodService(newFakeFoodRepo(food))
+
+	_, err := svc.BulkSetVisibility(context.Background(), owner.Hex(), &request.BulkSetFoodVisibilityRequest{Tags: []string{" "}, Visibility: "public"})
+	if err == nil || !strings.HasPrefix(err.Error(), "validation failed:") {
+		t.Errorf("Expected a validation error for an empty filter, got: %v", err)
+	}
+	if food.Visibility != "private" {
+		t.Errorf("Expected the food to stay private, got: %s", food.Visibility)
+	}
+}
+
+func TestFoodService_RecalculateAllCalories(t *testing.T) {
+	ctx := context.Background()
+	owner := primitive.NewObjectID()
+	// 31g protein and 3.6g fat give 156.4 kcal, so only the stale food changes
+	current := newTestFood(owner, "public")
+	stale := newTestFood(owner, "private")
+	stale.Calories = 150
+
+	t.Run("dry run reports changes without persisting", func(t *testing.T) {
+		stale.Calories = 150
+		foods := newFakeFoodRepo(current, stale)
+		svc := newTestFoodService(foods)
+
+		result, err := svc.RecalculateAllCalories(ctx, true)
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if !result.DryRun || result.Scanned != 2 || result.Changed != 1 {
+			t.Errorf("Expected 1 of 2 foods to change in a dry run, got: %+v", result)
+		}
+		if math.Abs(result.TotalDelta-6.4) > 1e-9 || math.Abs(result.MaxAbsDelta-6.4) > 1e-9 {
+			t.Errorf("Expected a delta of 6.4, got total %v and max %v", result.TotalDelta, result.MaxAbsDelta)
+		}
+		if len(result.Changes) != 1 || result.Changes[0].FoodID != stale.ID.Hex() || result.Changes[0].OldCalories != 150 || result.Changes[0].NewCalories != 156.4 {
+			t.Errorf("Expected the stale food to be listed, got: %+v", result.Changes)
+		}
+		if foods.foods[stale.ID].Calories != 150 {
+			t.Errorf("Expected a dry run not to persist, got: %v", foods.foods[stale.ID].Calories)
+		}
+	})
+
+	t.Run("persists changes", func(t *testing.T) {
+		stale.Calories = 150
+		foods := newFakeFoodRepo(current, stale)
+		svc := newTestFoodService(foods)
+
+		result, err := svc.RecalculateAllCalories(ctx, false)
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if result.DryRun || result.Changed != 1 {
+			t.Errorf("Expected 1 food to change, got: %+v", result)
+		}
+		if foods.foods[stale.ID].Calories != 156.4 {
+			t.Errorf("Expected recalculated calories to be stored, got: %v", foods.foods[stale.ID].Calories)
+		}
+	})
+}
+
+// growingFoodRepo creates a newer food after every page it returns, like users adding foods during a recalculation
+type growingFoodRepo struct {
+	*fakeFoodRepo
+	owner primitive.ObjectID
+}
+
+func (r *growingFoodRepo) FindFoods(ctx context.Context, filter domain.FoodFilter, page domain.Page, sortBy string) ([]*domain.FoodItem, error) {
+	foods, err := r.fakeFoodRepo.FindFoods(ctx, filter, page, sortBy)
+	newer := newTestFood(r.owner, "public")
+	newer.CreatedAt = time.Now().Add(time.Hour)
+	r.foods[newer.ID] = newer
+	return foods, err
+}
+
+func TestFoodService_RecalculateAllCalories_FoodsCreatedMeanwhile(t *testing.T) {
+	ctx := context.Background()
+	owner := primitive.NewObjectID()
+	base := time.Now()
+	repo := newFakeFoodRepo()
+	const existing = 2*recalculatePageSize + 50
+	for i := 0; i < existing; i++ {
+		food := newTestFood(owner, "public")
+		food.CreatedAt = base.Add(-time.Duration(i) * time.Minute)
+		repo.foods[food.ID] = food
+	}
+	foods := &growingFoodRepo{fakeFoodRepo: repo, owner: owner}
+	svc := NewFoodService(foods, nil, nil, NewNoopTransactor(), cache.NewLRUCache(100, time.Minute), newFakeStorage(), 1024*1024, config.FoodConfig{ServingTolerance: 0.001}, logger.NewNoopLogger())
+
+	result, err := svc.RecalculateAllCalories(ctx, true)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	// With offsets every newer food would shift the next page back by one and rescan a food
+	if result.Scanned != existing {
+		t.Errorf("Expected each of the %d existing foods to be scanned once, got: %d", existing, result.Scanned)
+	}
+}
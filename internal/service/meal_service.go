@@ -3,31 +3,42 @@ package service
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 
+	"nutrient_be/internal/config"
 	"nutrient_be/internal/domain"
 	"nutrient_be/internal/dto/request"
 	"nutrient_be/internal/pkg/calculator"
 	"nutrient_be/internal/pkg/logger"
+	"nutrient_be/internal/pkg/validator"
 )
 
+// fittingTemplateLimit caps how many templates ListFittingTemplates returns
+const fittingTemplateLimit = 50
+
 // MealTemplateRepository defines the interface for meal template data operations used by MealService
 type MealTemplateRepository interface {
 	Create(ctx context.Context, template *domain.MealTemplate) error
 	GetByID(ctx context.Context, id primitive.ObjectID) (*domain.MealTemplate, error)
 	GetByUser(ctx context.Context, userID primitive.ObjectID, mealType string, limit, offset int) ([]*domain.MealTemplate, error)
 	GetPublicTemplates(ctx context.Context, mealType string, limit, offset int) ([]*domain.MealTemplate, error)
+	GetWithinCalories(ctx context.Context, userID primitive.ObjectID, mealType string, maxCalories float64, limit int) ([]*domain.MealTemplate, error)
 	Update(ctx context.Context, template *domain.MealTemplate) error
 	Delete(ctx context.Context, id primitive.ObjectID) error
+	DeleteMatching(ctx context.Context, userID primitive.ObjectID, mealType string, tags []string) (int64, error)
+	TagCounts(ctx context.Context, userID primitive.ObjectID) ([]domain.TagCount, error)
 }
 
 // MealFoodRepository defines the interface for food data operations used by MealService
 type MealFoodRepository interface {
 	Create(ctx context.Context, food *domain.FoodItem) error
 	GetByID(ctx context.Context, id primitive.ObjectID) (*domain.FoodItem, error)
-	Search(ctx context.Context, query string, userID primitive.ObjectID, limit, offset int) ([]*domain.FoodItem, error)
+	GetByIDs(ctx context.Context, ids []primitive.ObjectID) ([]*domain.FoodItem, error)
+	FindFoods(ctx context.Context, filter domain.FoodFilter, page domain.Page, sortBy string) ([]*domain.FoodItem, error)
 	GetByCategory(ctx context.Context, category string, userID primitive.ObjectID, limit, offset int) ([]*domain.FoodItem, error)
 	GetByUser(ctx context.Context, userID primitive.ObjectID, limit, offset int) ([]*domain.FoodItem, error)
 	Update(ctx context.Context, food *domain.FoodItem) error
@@ -39,34 +50,40 @@ type MealFoodRepository interface {
 type MealService struct {
 	mealTemplateRepo MealTemplateRepository
 	foodRepo         MealFoodRepository
+	tx               Transactor
+	config           config.MealConfig
 	logger           logger.Logger
 }
 
 // NewMealService creates a new meal service
-func NewMealService(mealTemplateRepo MealTemplateRepository, foodRepo MealFoodRepository, log logger.Logger) *MealService {
+// Multi-step template changes run inside transactions started by tx
+func NewMealService(mealTemplateRepo MealTemplateRepository, foodRepo MealFoodRepository, tx Transactor, cfg config.MealConfig, log logger.Logger) *MealService {
 	return &MealService{
 		mealTemplateRepo: mealTemplateRepo,
 		foodRepo:         foodRepo,
+		tx:               tx,
+		config:           cfg,
 		logger:           log,
 	}
 }
 
 // CreateTemplate creates a new meal template with food items and calculates totals
-func (s *MealService) CreateTemplate(ctx context.Context, userID string, req *request.CreateMealTemplateRequest) (*domain.MealTemplate, error) {
+// The returned warnings flag implausible totals, such as a serving entered in the wrong unit
+func (s *MealService) CreateTemplate(ctx context.Context, userID string, req *request.CreateMealTemplateRequest) (*domain.MealTemplate, []string, error) {
 	s.logger.Info(ctx, "Creating meal template", logger.String("name", req.Name), logger.String("meal_type", req.MealType))
 
 	// Convert userID to ObjectID
 	userIDObj, err := primitive.ObjectIDFromHex(userID)
 	if err != nil {
 		s.logger.Error(ctx, "Invalid user ID", logger.Error(err))
-		return nil, fmt.Errorf("invalid user ID: %w", err)
+		return nil, nil, fmt.Errorf("invalid user ID: %w", err)
 	}
 
 	// Process food items and calculate nutrients
 	foodItems, totalCalories, totalMacros, totalMicros, err := s.processFoodItems(ctx, req.FoodItems)
 	if err != nil {
 		s.logger.Error(ctx, "Failed to process food items", logger.Error(err))
-		return nil, fmt.Errorf("failed to process food items: %w", err)
+		return nil, nil, foodItemsError(err)
 	}
 
 	// Create template
@@ -80,20 +97,26 @@ func (s *MealService) CreateTemplate(ctx context.Context, userID string, req *re
 		TotalCalories: totalCalories,
 		TotalMacros:   totalMacros,
 		TotalMicros:   totalMicros,
-		Tags:          req.Tags,
+		Tags:          validator.NormalizeTags(req.Tags),
 		IsPublic:      req.IsPublic,
 		CreatedAt:     time.Now(),
 		UpdatedAt:     time.Now(),
 	}
 
+	// Totals are only known after processing, so plausibility is checked here rather than in the validator
+	warnings := s.checkTemplatePlausibility(template)
+	for _, warning := range warnings {
+		s.logger.Warn(ctx, "Implausible meal template totals", logger.String("warning", warning))
+	}
+
 	// Save to database
 	if err := s.mealTemplateRepo.Create(ctx, template); err != nil {
 		s.logger.Error(ctx, "Failed to create meal template", logger.Error(err))
-		return nil, fmt.Errorf("failed to create meal template: %w", err)
+		return nil, nil, fmt.Errorf("failed to create meal template: %w", err)
 	}
 
 	s.logger.Info(ctx, "Meal template created successfully", logger.String("template_id", template.ID.Hex()))
-	return template, nil
+	return template, warnings, nil
 }
 
 // AddFoodToTemplate adds food items to an existing template and recalculates totals
@@ -113,43 +136,103 @@ func (s *MealService) AddFoodToTemplate(ctx context.Context, userID string, temp
 		return nil, fmt.Errorf("invalid template ID: %w", err)
 	}
 
-	// Get existing template
-	template, err := s.mealTemplateRepo.GetByID(ctx, templateIDObj)
-	if err != nil {
-		s.logger.Error(ctx, "Failed to get template", logger.Error(err))
-		return nil, fmt.Errorf("failed to get template: %w", err)
-	}
+	// Reading and updating run in one transaction so concurrent additions cannot overwrite each other's totals
+	var updated *domain.MealTemplate
+	err = s.tx.WithTransaction(ctx, func(ctx context.Context) error {
+		// Get existing template
+		template, err := s.mealTemplateRepo.GetByID(ctx, templateIDObj)
+		if err != nil {
+			s.logger.Error(ctx, "Failed to get template", logger.Error(err))
+			return fmt.Errorf("failed to get template: %w", err)
+		}
 
-	// Verify ownership
-	if template.UserID != userIDObj {
-		s.logger.Error(ctx, "User does not own template")
-		return nil, fmt.Errorf("template not found or access denied")
-	}
+		// Verify ownership
+		if template.UserID != userIDObj {
+			s.logger.Error(ctx, "User does not own template")
+			return fmt.Errorf("template not found or access denied")
+		}
+
+		// Check the cap before any food lookups happen; foods merged into existing entries add none
+		if max := s.config.MaxTemplateFoodItems; max > 0 {
+			if total := len(template.FoodItems) + countNewTemplateFoodItems(template.FoodItems, req.FoodItems); total > max {
+				return fmt.Errorf("validation failed: template would have %d food items (maximum %d)", total, max)
+			}
+		}
 
-	// Process new food items
-	newFoodItems, newCalories, newMacros, newMicros, err := s.processFoodItems(ctx, req.FoodItems)
+		// Process new food items
+		newFoodItems, _, _, _, err := s.processFoodItems(ctx, req.FoodItems)
+		if err != nil {
+			s.logger.Error(ctx, "Failed to process food items", logger.Error(err))
+			return foodItemsError(err)
+		}
+
+		// A food already in the template with the same serving unit gets the added amount,
+		// matching the duplicate rule applied when templates are created
+		for _, item := range newFoodItems {
+			i := templateFoodItemIndex(template.FoodItems, item.FoodItemID, item.ServingUnit)
+			if i < 0 {
+				template.FoodItems = append(template.FoodItems, item)
+				continue
+			}
+
+			food, err := s.lookupFoodItem(ctx, item.FoodItemID.Hex())
+			if err != nil {
+				return fmt.Errorf("failed to process food items: %w", err)
+			}
+			amount := template.FoodItems[i].Amount + item.Amount
+			if err := s.checkFoodItemServing(food, item.ServingUnit, amount); err != nil {
+				return err
+			}
+			merged, err := mealFoodItemForServing(food, item.ServingUnit, amount)
+			if err != nil {
+				return fmt.Errorf("failed to process food items: %w", err)
+			}
+			template.FoodItems[i] = merged
+		}
+
+		// Recalculate totals
+		template.TotalCalories, template.TotalMacros, template.TotalMicros = sumTemplateFoodItems(template.FoodItems)
+		template.UpdatedAt = time.Now()
+
+		// Update in database
+		if err := s.mealTemplateRepo.Update(ctx, template); err != nil {
+			s.logger.Error(ctx, "Failed to update template", logger.Error(err))
+			return fmt.Errorf("failed to update template: %w", err)
+		}
+
+		updated = template
+		return nil
+	})
 	if err != nil {
-		s.logger.Error(ctx, "Failed to process food items", logger.Error(err))
-		return nil, fmt.Errorf("failed to process food items: %w", err)
+		return nil, err
 	}
 
-	// Add new food items to existing ones
-	template.FoodItems = append(template.FoodItems, newFoodItems...)
+	s.logger.Info(ctx, "Food items added to template successfully")
+	return updated, nil
+}
 
-	// Recalculate totals
-	template.TotalCalories += newCalories
-	template.TotalMacros = calculator.SumMacros(template.TotalMacros, newMacros)
-	template.TotalMicros = calculator.SumMicros(template.TotalMicros, newMicros)
-	template.UpdatedAt = time.Now()
+// ListTags returns the distinct tags on the user's templates with how many templates carry each
+// The most used tags come first, so the list can feed tag autocompletion directly
+func (s *MealService) ListTags(ctx context.Context, userID string) ([]domain.TagCount, error) {
+	s.logger.Info(ctx, "Listing meal template tags")
 
-	// Update in database
-	if err := s.mealTemplateRepo.Update(ctx, template); err != nil {
-		s.logger.Error(ctx, "Failed to update template", logger.Error(err))
-		return nil, fmt.Errorf("failed to update template: %w", err)
+	userIDObj, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		s.logger.Error(ctx, "Invalid user ID", logger.Error(err))
+		return nil, fmt.Errorf("invalid user ID: %w", err)
 	}
 
-	s.logger.Info(ctx, "Food items added to template successfully")
-	return template, nil
+	tags, err := s.mealTemplateRepo.TagCounts(ctx, userIDObj)
+	if err != nil {
+		s.logger.Error(ctx, "Failed to list template tags", logger.Error(err))
+		return nil, fmt.Errorf("failed to list template tags: %w", err)
+	}
+	if tags == nil {
+		tags = []domain.TagCount{}
+	}
+
+	s.logger.Info(ctx, "Meal template tags listed successfully", logger.Int("count", len(tags)))
+	return tags, nil
 }
 
 // GetTemplate retrieves a meal template with detailed macro and micro information
@@ -186,6 +269,51 @@ func (s *MealService) GetTemplate(ctx context.Context, userID string, templateID
 	return template, nil
 }
 
+// TemplateFoods holds the current foods of a template's items, keyed by food ID
+// A food in neither map no longer exists
+type TemplateFoods struct {
+	Foods        map[primitive.ObjectID]*domain.FoodItem // Foods the user can see
+	Inaccessible map[primitive.ObjectID]bool             // Foods that exist but are private to another user
+}
+
+// ExpandTemplateFoods loads the current food of each of the template's items with one batch query
+// Other users' private foods are only reported as inaccessible, so their details cannot be probed
+func (s *MealService) ExpandTemplateFoods(ctx context.Context, userID string, template *domain.MealTemplate) (*TemplateFoods, error) {
+	userIDObj, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		s.logger.Error(ctx, "Invalid user ID", logger.Error(err))
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	ids := make([]primitive.ObjectID, 0, len(template.FoodItems))
+	seen := make(map[primitive.ObjectID]bool, len(template.FoodItems))
+	for _, item := range template.FoodItems {
+		if !seen[item.FoodItemID] {
+			seen[item.FoodItemID] = true
+			ids = append(ids, item.FoodItemID)
+		}
+	}
+
+	foods, err := s.foodRepo.GetByIDs(ctx, ids)
+	if err != nil {
+		s.logger.Error(ctx, "Failed to get template foods", logger.Error(err))
+		return nil, fmt.Errorf("failed to get template foods: %w", err)
+	}
+
+	expanded := &TemplateFoods{
+		Foods:        make(map[primitive.ObjectID]*domain.FoodItem, len(foods)),
+		Inaccessible: make(map[primitive.ObjectID]bool),
+	}
+	for _, food := range foods {
+		if food.Visibility == "public" || food.CreatedBy == userIDObj {
+			expanded.Foods[food.ID] = food
+		} else {
+			expanded.Inaccessible[food.ID] = true
+		}
+	}
+	return expanded, nil
+}
+
 // ListTemplates lists meal templates for a user
 func (s *MealService) ListTemplates(ctx context.Context, userID string, mealType string, limit, offset int) ([]*domain.MealTemplate, error) {
 	s.logger.Info(ctx, "Listing meal templates", logger.String("meal_type", mealType))
@@ -208,6 +336,39 @@ func (s *MealService) ListTemplates(ctx context.Context, userID string, mealType
 	return templates, nil
 }
 
+// ListFittingTemplates lists the user's and public templates of a meal type whose total calories fit within maxCalories
+// Templates are sorted by how much of the budget they fill, closest to maxCalories first
+func (s *MealService) ListFittingTemplates(ctx context.Context, userID string, maxCalories float64, mealType string) ([]*domain.MealTemplate, error) {
+	s.logger.Info(ctx, "Listing fitting meal templates", logger.String("meal_type", mealType), logger.Float64("max_calories", maxCalories))
+
+	// Convert userID to ObjectID
+	userIDObj, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		s.logger.Error(ctx, "Invalid user ID", logger.Error(err))
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	if maxCalories <= 0 {
+		return nil, fmt.Errorf("invalid maxCalories: must be greater than 0")
+	}
+	if !validator.IsValidMealType(mealType) {
+		return nil, fmt.Errorf("invalid meal type: %q", mealType)
+	}
+
+	templates, err := s.mealTemplateRepo.GetWithinCalories(ctx, userIDObj, mealType, maxCalories, fittingTemplateLimit)
+	if err != nil {
+		s.logger.Error(ctx, "Failed to list fitting templates", logger.Error(err))
+		return nil, fmt.Errorf("failed to list templates: %w", err)
+	}
+
+	sort.SliceStable(templates, func(i, j int) bool {
+		return templates[i].TotalCalories > templates[j].TotalCalories
+	})
+
+	s.logger.Info(ctx, "Fitting meal templates listed successfully", logger.Int("count", len(templates)))
+	return templates, nil
+}
+
 // UpdateTemplate updates a meal template
 func (s *MealService) UpdateTemplate(ctx context.Context, userID string, templateID string, req *request.UpdateMealTemplateRequest) (*domain.MealTemplate, error) {
 	s.logger.Info(ctx, "Updating meal template", logger.String("template_id", templateID))
@@ -249,7 +410,7 @@ func (s *MealService) UpdateTemplate(ctx context.Context, userID string, templat
 		template.MealType = req.MealType
 	}
 	if req.Tags != nil {
-		template.Tags = req.Tags
+		template.Tags = validator.NormalizeTags(req.Tags)
 	}
 	if req.IsPublic != nil {
 		template.IsPublic = *req.IsPublic
@@ -260,7 +421,7 @@ func (s *MealService) UpdateTemplate(ctx context.Context, userID string, templat
 		foodItems, totalCalories, totalMacros, totalMicros, err := s.processFoodItems(ctx, req.FoodItems)
 		if err != nil {
 			s.logger.Error(ctx, "Failed to process food items", logger.Error(err))
-			return nil, fmt.Errorf("failed to process food items: %w", err)
+			return nil, foodItemsError(err)
 		}
 		template.FoodItems = foodItems
 		template.TotalCalories = totalCalories
@@ -320,6 +481,37 @@ func (s *MealService) DeleteTemplate(ctx context.Context, userID string, templat
 	return nil
 }
 
+// BulkDelete deletes the user's templates matching the filter in one operation and returns how many were deleted
+// An empty filter is rejected so a missing body cannot delete every template
+func (s *MealService) BulkDelete(ctx context.Context, userID string, filter *request.BulkDeleteTemplatesRequest) (int64, error) {
+	s.logger.Info(ctx, "Bulk deleting meal templates", logger.String("meal_type", filter.MealType), logger.Int("tag_count", len(filter.Tags)))
+
+	userIDObj, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		s.logger.Error(ctx, "Invalid user ID", logger.Error(err))
+		return 0, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	mealType := strings.TrimSpace(filter.MealType)
+	// Stored tags are normalized, so the filter is too
+	tags := validator.NormalizeTags(filter.Tags)
+	if mealType == "" && len(tags) == 0 {
+		return 0, fmt.Errorf("validation failed: at least one of mealType or tags is required")
+	}
+	if mealType != "" && !validator.IsValidMealType(mealType) {
+		return 0, fmt.Errorf("invalid meal type: %s", mealType)
+	}
+
+	deleted, err := s.mealTemplateRepo.DeleteMatching(ctx, userIDObj, mealType, tags)
+	if err != nil {
+		s.logger.Error(ctx, "Failed to bulk delete templates", logger.Error(err))
+		return 0, fmt.Errorf("failed to delete templates: %w", err)
+	}
+
+	s.logger.Info(ctx, "Meal templates bulk deleted", logger.Int("deleted", int(deleted)))
+	return deleted, nil
+}
+
 // processFoodItems processes food items from request, calculates nutrients, and returns totals
 func (s *MealService) processFoodItems(
 	ctx context.Context,
@@ -331,55 +523,24 @@ func (s *MealService) processFoodItems(
 	var allMicros []domain.MicroNutrients
 
 	for _, foodItemReq := range foodItemReqs {
-		// Convert food item ID to ObjectID
-		foodIDObj, err := primitive.ObjectIDFromHex(foodItemReq.FoodItemID)
+		food, err := s.lookupFoodItem(ctx, foodItemReq.FoodItemID)
 		if err != nil {
-			return nil, 0, domain.MacroNutrients{}, domain.MicroNutrients{},
-				fmt.Errorf("invalid food item ID '%s': %w", foodItemReq.FoodItemID, err)
+			return nil, 0, domain.MacroNutrients{}, domain.MicroNutrients{}, err
 		}
 
-		// Get food item from repository
-		food, err := s.foodRepo.GetByID(ctx, foodIDObj)
-		if err != nil {
-			return nil, 0, domain.MacroNutrients{}, domain.MicroNutrients{},
-				fmt.Errorf("food item not found: %w", err)
+		if err := s.checkFoodItemServing(food, foodItemReq.ServingUnit, foodItemReq.Amount); err != nil {
+			return nil, 0, domain.MacroNutrients{}, domain.MicroNutrients{}, err
 		}
 
-		// Calculate nutrients for the specified serving
-		calories, macros, micros, err := calculator.CalculateNutrientsForServing(
-			food,
-			foodItemReq.ServingUnit,
-			foodItemReq.Amount,
-		)
+		mealFoodItem, err := mealFoodItemForServing(food, foodItemReq.ServingUnit, foodItemReq.Amount)
 		if err != nil {
-			return nil, 0, domain.MacroNutrients{}, domain.MicroNutrients{},
-				fmt.Errorf("failed to calculate nutrients for food '%s': %w", foodItemReq.FoodItemID, err)
-		}
-
-		// Get food name (prefer English, fallback to first available)
-		foodName := food.Name["en"]
-		if foodName == "" {
-			for _, name := range food.Name {
-				foodName = name
-				break
-			}
-		}
-
-		// Create meal template food item
-		mealFoodItem := domain.MealTemplateFoodItem{
-			FoodItemID:  foodIDObj,
-			FoodName:    foodName,
-			ServingUnit: foodItemReq.ServingUnit,
-			Amount:      foodItemReq.Amount,
-			Calories:    calories,
-			Macros:      macros,
-			Micros:      micros,
+			return nil, 0, domain.MacroNutrients{}, domain.MicroNutrients{}, err
 		}
 
 		foodItems = append(foodItems, mealFoodItem)
-		totalCalories += calories
-		allMacros = append(allMacros, macros)
-		allMicros = append(allMicros, micros)
+		totalCalories += mealFoodItem.Calories
+		allMacros = append(allMacros, mealFoodItem.Macros)
+		allMicros = append(allMicros, mealFoodItem.Micros)
 	}
 
 	// Calculate totals
@@ -388,3 +549,194 @@ func (s *MealService) processFoodItems(
 
 	return foodItems, totalCalories, totalMacros, totalMicros, nil
 }
+
+// checkFoodItemServing is the check behind the validator's serving unit and amount checks, run once the food is loaded
+// The food must have the unit or one it converts into, and the serving may weigh at most MaxItemGrams
+func (s *MealService) checkFoodItemServing(food *domain.FoodItem, servingUnit string, amount float64) error {
+	grams, err := calculator.ConvertToGrams(food, servingUnit, amount)
+	if err != nil {
+		return fmt.Errorf("validation failed: %w", err)
+	}
+	if max := s.config.MaxItemGrams; max > 0 && grams > max {
+		return fmt.Errorf("validation failed: %g %s of food '%s' weighs %.0fg, more than the %.0fg allowed per item",
+			amount, servingUnit, food.ID.Hex(), grams, max)
+	}
+	return nil
+}
+
+// foodItemsError wraps an error from processing food items, leaving validation failures as they are so they reach the client as such
+func foodItemsError(err error) error {
+	if strings.HasPrefix(err.Error(), "validation failed:") {
+		return err
+	}
+	return fmt.Errorf("failed to process food items: %w", err)
+}
+
+// templateFoodItemIndex returns the position of the item for foodItemID in servingUnit, or -1
+func templateFoodItemIndex(items []domain.MealTemplateFoodItem, foodItemID primitive.ObjectID, servingUnit string) int {
+	for i, item := range items {
+		if item.FoodItemID == foodItemID && item.ServingUnit == servingUnit {
+			return i
+		}
+	}
+	return -1
+}
+
+// countNewTemplateFoodItems counts the distinct food and serving unit pairs of reqs not already in items
+func countNewTemplateFoodItems(items []domain.MealTemplateFoodItem, reqs []request.MealTemplateFoodItemRequest) int {
+	seen := make(map[string]bool, len(items)+len(reqs))
+	for _, item := range items {
+		seen[item.FoodItemID.Hex()+":"+item.ServingUnit] = true
+	}
+
+	count := 0
+	for _, req := range reqs {
+		key := strings.ToLower(req.FoodItemID) + ":" + req.ServingUnit
+		if !seen[key] {
+			seen[key] = true
+			count++
+		}
+	}
+	return count
+}
+
+// sumTemplateFoodItems totals the nutrients of template food items
+func sumTemplateFoodItems(items []domain.MealTemplateFoodItem) (float64, domain.MacroNutrients, domain.MicroNutrients) {
+	var totalCalories float64
+	allMacros := make([]domain.MacroNutrients, 0, len(items))
+	allMicros := make([]domain.MicroNutrients, 0, len(items))
+	for _, item := range items {
+		totalCalories += item.Calories
+		allMacros = append(allMacros, item.Macros)
+		allMicros = append(allMicros, item.Micros)
+	}
+	return totalCalories, calculator.SumMacros(allMacros...), calculator.SumMicros(allMicros...)
+}
+
+// NutritionCalculation holds the nutrients calculated for an ad-hoc food list
+// Totals only include the items that could be calculated
+type NutritionCalculation struct {
+	Items         []NutritionCalculationItem
+	TotalCalories float64
+	TotalMacros   domain.MacroNutrients
+	TotalMicros   domain.MicroNutrients
+}
+
+// NutritionCalculationItem is the result for one requested food; Err is set when it could not be calculated
+type NutritionCalculationItem struct {
+	Request  request.NutritionItemRequest
+	FoodItem domain.MealTemplateFoodItem
+	Err      error
+}
+
+// CalculateNutrition calculates nutrients for an ad-hoc list of food servings without saving anything
+// Each entry is checked on its own, so bad entries are reported per item and left out of the totals
+func (s *MealService) CalculateNutrition(ctx context.Context, userID string, req *request.CalculateNutritionRequest) (*NutritionCalculation, error) {
+	s.logger.Info(ctx, "Calculating nutrition", logger.Int("item_count", len(req.Items)))
+
+	userIDObj, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		s.logger.Error(ctx, "Invalid user ID", logger.Error(err))
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	result := &NutritionCalculation{Items: make([]NutritionCalculationItem, len(req.Items))}
+	var allMacros []domain.MacroNutrients
+	var allMicros []domain.MicroNutrients
+
+	for i, itemReq := range req.Items {
+		foodItem, err := s.calculateAccessibleFoodItem(ctx, userIDObj, itemReq)
+		result.Items[i] = NutritionCalculationItem{Request: itemReq, FoodItem: foodItem, Err: err}
+		if err != nil {
+			s.logger.Warn(ctx, "Skipping nutrition item", logger.Int("index", i), logger.Error(err))
+			continue
+		}
+
+		result.TotalCalories += foodItem.Calories
+		allMacros = append(allMacros, foodItem.Macros)
+		allMicros = append(allMicros, foodItem.Micros)
+	}
+
+	result.TotalMacros = calculator.SumMacros(allMacros...)
+	result.TotalMicros = calculator.SumMicros(allMicros...)
+
+	s.logger.Info(ctx, "Nutrition calculated successfully", logger.Int("item_count", len(req.Items)))
+	return result, nil
+}
+
+// calculateAccessibleFoodItem calculates one nutrition entry, requiring the food to be public or owned by the user
+func (s *MealService) calculateAccessibleFoodItem(ctx context.Context, userID primitive.ObjectID, itemReq request.NutritionItemRequest) (domain.MealTemplateFoodItem, error) {
+	if itemReq.Amount <= 0 {
+		return domain.MealTemplateFoodItem{}, fmt.Errorf("invalid amount: %v (must be greater than 0)", itemReq.Amount)
+	}
+
+	foodIDObj, err := primitive.ObjectIDFromHex(itemReq.FoodID)
+	if err != nil {
+		return domain.MealTemplateFoodItem{}, fmt.Errorf("invalid food item ID '%s'", itemReq.FoodID)
+	}
+
+	// Missing and inaccessible foods are reported the same way so private foods cannot be probed
+	food, err := s.foodRepo.GetByID(ctx, foodIDObj)
+	if err != nil || (food.Visibility != "public" && food.CreatedBy != userID) {
+		return domain.MealTemplateFoodItem{}, fmt.Errorf("food item not found or access denied")
+	}
+
+	return mealFoodItemForServing(food, itemReq.ServingUnit, itemReq.Amount)
+}
+
+// lookupFoodItem parses a requested food item ID and loads the food
+func (s *MealService) lookupFoodItem(ctx context.Context, foodItemID string) (*domain.FoodItem, error) {
+	foodIDObj, err := primitive.ObjectIDFromHex(foodItemID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid food item ID '%s': %w", foodItemID, err)
+	}
+
+	food, err := s.foodRepo.GetByID(ctx, foodIDObj)
+	if err != nil {
+		return nil, fmt.Errorf("food item not found: %w", err)
+	}
+
+	return food, nil
+}
+
+// mealFoodItemForServing calculates the nutrients of a food for the given serving
+func mealFoodItemForServing(food *domain.FoodItem, servingUnit string, amount float64) (domain.MealTemplateFoodItem, error) {
+	calories, macros, micros, err := calculator.CalculateNutrientsForServing(food, servingUnit, amount)
+	if err != nil {
+		return domain.MealTemplateFoodItem{}, fmt.Errorf("failed to calculate nutrients for food '%s': %w", food.ID.Hex(), err)
+	}
+
+	// Get food name (prefer English, fallback to first available)
+	foodName := food.Name["en"]
+	if foodName == "" {
+		for _, name := range food.Name {
+			foodName = name
+			break
+		}
+	}
+
+	return domain.MealTemplateFoodItem{
+		FoodItemID:  food.ID,
+		FoodName:    foodName,
+		ServingUnit: servingUnit,
+		Amount:      amount,
+		Calories:    calories,
+		Macros:      macros,
+		Micros:      micros,
+	}, nil
+}
+
+// checkTemplatePlausibility returns warnings for template totals that are unlikely to be real
+func (s *MealService) checkTemplatePlausibility(template *domain.MealTemplate) []string {
+	if s.config.MaxTemplateCalories <= 0 {
+		return nil
+	}
+
+	var warnings []string
+	if template.TotalCalories > s.config.MaxTemplateCalories {
+		warnings = append(warnings, fmt.Sprintf(
+			"total calories %.0f exceed the plausible maximum of %.0f per template; check food amounts and serving units",
+			template.TotalCalories, s.config.MaxTemplateCalories))
+	}
+	return warnings
+}
@@ -0,0 +1,447 @@
+package service
+
+import (
+	"context"
+	"math"
+	"strings"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"nutrient_be/internal/config"
+	"nutrient_be/internal/domain"
+	"nutrient_be/internal/dto/request"
+	"nutrient_be/internal/pkg/logger"
+)
+
+func newTestMealService(templates *fakeMealTemplateRepo, foods *fakeFoodRepo) *MealService {
+	return NewMealService(templates, foods, NewNoopTransactor(), config.MealConfig{MaxTemplateCalories: 5000}, logger.NewNoopLogger())
+}
+
+func TestMealService_CreateTemplate_PlausibleTotals(t *testing.T) {
+	oats := newSuggestionTestFood("Oats", 389, domain.MacroNutrients{Protein: 16.9, Carbohydrates: 66.3, Fat: 6.9})
+	templates := newFakeMealTemplateRepo()
+	svc := newTestMealService(templates, newFakeFoodRepo(oats))
+
+	template, warnings, err := svc.CreateTemplate(context.Background(), primitive.NewObjectID().Hex(), &request.CreateMealTemplateRequest{
+		Name:      "Porridge",
+		MealType:  "breakfast",
+		FoodItems: []request.MealTemplateFoodItemRequest{{FoodItemID: oats.ID.Hex(), ServingUnit: "gram", Amount: 80}},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(warnings) != 0 {
+		t.Errorf("Expected no warnings, got: %v", warnings)
+	}
+	if _, ok := templates.templates[template.ID]; !ok {
+		t.Error("Expected template to be saved")
+	}
+}
+
+func TestMealService_CreateTemplate_NormalizesTags(t *testing.T) {
+	oats := newSuggestionTestFood("Oats", 389, domain.MacroNutrients{Protein: 16.9, Carbohydrates: 66.3, Fat: 6.9})
+	templates := newFakeMealTemplateRepo()
+	svc := newTestMealService(templates, newFakeFoodRepo(oats))
+
+	template, _, err := svc.CreateTemplate(context.Background(), primitive.NewObjectID().Hex(), &request.CreateMealTemplateRequest{
+		Name:      "Porridge",
+		MealType:  "breakfast",
+		FoodItems: []request.MealTemplateFoodItemRequest{{FoodItemID: oats.ID.Hex(), ServingUnit: "gram", Amount: 80}},
+		Tags:      []string{"Keto", "keto", " KETO "},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if tags := templates.templates[template.ID].Tags; len(tags) != 1 || tags[0] != "keto" {
+		t.Errorf("Expected tags [keto], got: %v", tags)
+	}
+}
+
+func TestMealService_CreateTemplate_ImplausibleTotalsWarns(t *testing.T) {
+	oats := newSuggestionTestFood("Oats", 389, domain.MacroNutrients{Protein: 16.9, Carbohydrates: 66.3, Fat: 6.9})
+	templates := newFakeMealTemplateRepo()
+	svc := newTestMealService(templates, newFakeFoodRepo(oats))
+
+	// 5kg of oats, e.g. grams typed where servings were meant, is about 19450 calories
+	template, warnings, err := svc.CreateTemplate(context.Background(), primitive.NewObjectID().Hex(), &request.CreateMealTemplateRequest{
+		Name:      "Porridge",
+		MealType:  "breakfast",
+		FoodItems: []request.MealTemplateFoodItemRequest{{FoodItemID: oats.ID.Hex(), ServingUnit: "gram", Amount: 5000}},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "exceed the plausible maximum") {
+		t.Errorf("Expected a plausibility warning, got: %v", warnings)
+	}
+	if _, ok := templates.templates[template.ID]; !ok {
+		t.Error("Expected template to still be saved")
+	}
+}
+
+func TestMealService_CalculateNutrition_MixedItems(t *testing.T) {
+	userID := primitive.NewObjectID()
+	oats := newSuggestionTestFood("Oats", 389, domain.MacroNutrients{Protein: 16.9, Carbohydrates: 66.3, Fat: 6.9})
+	egg := newSuggestionTestFood("Egg", 155, domain.MacroNutrients{Protein: 13, Fat: 11})
+	egg.ServingSizes = append(egg.ServingSizes, domain.ServingSize{Unit: "piece", Amount: 1, GramEquivalent: 50})
+	ownPrivate := newSuggestionTestFood("My granola", 450, domain.MacroNutrients{Protein: 10})
+	ownPrivate.Visibility = "private"
+	ownPrivate.CreatedBy = userID
+	othersPrivate := newSuggestionTestFood("Someone's shake", 120, domain.MacroNutrients{Protein: 20})
+	othersPrivate.Visibility = "private"
+	othersPrivate.CreatedBy = primitive.NewObjectID()
+
+	svc := newTestMealService(newFakeMealTemplateRepo(), newFakeFoodRepo(oats, egg, ownPrivate, othersPrivate))
+
+	result, err := svc.CalculateNutrition(context.Background(), userID.Hex(), &request.CalculateNutritionRequest{
+		Items: []request.NutritionItemRequest{
+			{FoodID: oats.ID.Hex(), ServingUnit: "gram", Amount: 50},
+			{FoodID: egg.ID.Hex(), ServingUnit: "piece", Amount: 2},
+			{FoodID: ownPrivate.ID.Hex(), ServingUnit: "gram", Amount: 100},
+			{FoodID: othersPrivate.ID.Hex(), ServingUnit: "gram", Amount: 100},
+			{FoodID: oats.ID.Hex(), ServingUnit: "cup", Amount: 1},
+			{FoodID: "not-an-id", ServingUnit: "gram", Amount: 100},
+			{FoodID: primitive.NewObjectID().Hex(), ServingUnit: "gram", Amount: 100},
+			{FoodID: egg.ID.Hex(), ServingUnit: "gram", Amount: 0},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(result.Items) != 8 {
+		t.Fatalf("Expected 8 items, got: %d", len(result.Items))
+	}
+
+	for i := 0; i < 3; i++ {
+		if result.Items[i].Err != nil {
+			t.Errorf("Expected item %d to be calculated, got: %v", i, result.Items[i].Err)
+		}
+	}
+	expectedErrors := map[int]string{
+		3: "not found or access denied",
+		4: "serving unit 'cup' not found",
+		5: "invalid food item ID",
+		6: "not found or access denied",
+		7: "invalid amount",
+	}
+	for i, expected := range expectedErrors {
+		if err := result.Items[i].Err; err == nil || !strings.Contains(err.Error(), expected) {
+			t.Errorf("Expected item %d error containing %q, got: %v", i, expected, err)
+		}
+	}
+
+	// 50g oats (194.5) + 100g egg (155) + 100g granola (450)
+	if diff := result.TotalCalories - 799.5; diff > 0.001 || diff < -0.001 {
+		t.Errorf("Expected total calories 799.5, got: %v", result.TotalCalories)
+	}
+	if diff := result.TotalMacros.Protein - 31.45; diff > 0.001 || diff < -0.001 {
+		t.Errorf("Expected total protein 31.45, got: %v", result.TotalMacros.Protein)
+	}
+	if result.Items[1].FoodItem.FoodName != "Egg" || result.Items[1].FoodItem.Calories != 155 {
+		t.Errorf("Expected 2 eggs to be 155 calories, got: %+v", result.Items[1].FoodItem)
+	}
+}
+
+func TestMealService_ListFittingTemplates(t *testing.T) {
+	userID := primitive.NewObjectID()
+	otherUserID := primitive.NewObjectID()
+	newTemplate := func(name, mealType string, calories float64, owner primitive.ObjectID, public bool) *domain.MealTemplate {
+		return &domain.MealTemplate{ID: primitive.NewObjectID(), UserID: owner, Name: name, MealType: mealType, TotalCalories: calories, IsPublic: public}
+	}
+
+	salad := newTemplate("Salad", "dinner", 350, userID, false)
+	curry := newTemplate("Curry", "dinner", 580, otherUserID, true)
+	steak := newTemplate("Steak", "dinner", 900, userID, false)
+	otherPrivate := newTemplate("Soup", "dinner", 400, otherUserID, false)
+	breakfast := newTemplate("Oats", "breakfast", 300, userID, false)
+	svc := newTestMealService(newFakeMealTemplateRepo(salad, curry, steak, otherPrivate, breakfast), newFakeFoodRepo())
+
+	templates, err := svc.ListFittingTemplates(context.Background(), userID.Hex(), 600, "dinner")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// Steak is over budget, Soup is another user's private template and Oats is a breakfast
+	if len(templates) != 2 {
+		t.Fatalf("Expected 2 fitting templates, got: %d", len(templates))
+	}
+	if templates[0].ID != curry.ID || templates[1].ID != salad.ID {
+		t.Errorf("Expected Curry then Salad, got: %s then %s", templates[0].Name, templates[1].Name)
+	}
+}
+
+func TestMealService_ListFittingTemplates_RejectsInvalidInput(t *testing.T) {
+	svc := newTestMealService(newFakeMealTemplateRepo(), newFakeFoodRepo())
+	userID := primitive.NewObjectID().Hex()
+
+	if _, err := svc.ListFittingTemplates(context.Background(), userID, 0, "dinner"); err == nil || !strings.HasPrefix(err.Error(), "invalid ") {
+		t.Errorf("Expected invalid maxCalories error, got: %v", err)
+	}
+	if _, err := svc.ListFittingTemplates(context.Background(), userID, 600, "brunch"); err == nil || !strings.HasPrefix(err.Error(), "invalid ") {
+		t.Errorf("Expected invalid meal type error, got: %v", err)
+	}
+}
+
+func TestMealService_AddFoodToTemplate_EnforcesFoodItemCap(t *testing.T) {
+	userID := primitive.NewObjectID()
+	oats := newSuggestionTestFood("Oats", 389, domain.MacroNutrients{Protein: 16.9, Carbohydrates: 66.3, Fat: 6.9})
+	template := &domain.MealTemplate{
+		ID:        primitive.NewObjectID(),
+		UserID:    userID,
+		Name:      "Porridge",
+		MealType:  "breakfast",
+		FoodItems: []domain.MealTemplateFoodItem{{FoodItemID: oats.ID, ServingUnit: "gram", Amount: 40}},
+	}
+	foods := newFakeFoodRepo(oats)
+	svc := NewMealService(newFakeMealTemplateRepo(template), foods, NewNoopTransactor(), config.MealConfig{MaxTemplateFoodItems: 3}, logger.NewNoopLogger())
+
+	// Distinct foods, since re-adding a food already in the template merges into its entry
+	items := func(n int) []request.MealTemplateFoodItemRequest {
+		out := make([]request.MealTemplateFoodItemRequest, n)
+		for i := range out {
+			food := newSuggestionTestFood("Topping", 100, domain.MacroNutrients{Carbohydrates: 20})
+			foods.foods[food.ID] = food
+			out[i] = request.MealTemplateFoodItemRequest{FoodItemID: food.ID.Hex(), ServingUnit: "gram", Amount: 10}
+		}
+		return out
+	}
+
+	// 1 existing + 3 new is one above the cap
+	_, err := svc.AddFoodToTemplate(context.Background(), userID.Hex(), template.ID.Hex(), &request.AddFoodToTemplateRequest{FoodItems: items(3)})
+	if err == nil || !strings.HasPrefix(err.Error(), "validation failed:") {
+		t.Fatalf("Expected validation error above the cap, got: %v", err)
+	}
+	if len(template.FoodItems) != 1 {
+		t.Errorf("Expected template to be unchanged, got: %d food items", len(template.FoodItems))
+	}
+
+	// 1 existing + 2 new is exactly at the cap
+	updated, err := svc.AddFoodToTemplate(context.Background(), userID.Hex(), template.ID.Hex(), &request.AddFoodToTemplateRequest{FoodItems: items(2)})
+	if err != nil {
+		t.Fatalf("Expected no error at the cap, got: %v", err)
+	}
+	if len(updated.FoodItems) != 3 {
+		t.Errorf("Expected 3 food items, got: %d", len(updated.FoodItems))
+	}
+}
+
+func TestMealService_AddFoodToTemplate_MergesSameFoodAndUnit(t *testing.T) {
+	userID := primitive.NewObjectID()
+	oats := newSuggestionTestFood("Oats", 389, domain.MacroNutrients{Protein: 16.9, Carbohydrates: 66.3, Fat: 6.9})
+	oats.ServingSizes = append(oats.ServingSizes, domain.ServingSize{Unit: "cup", Amount: 1, GramEquivalent: 80})
+	svc := newTestMealService(newFakeMealTemplateRepo(), newFakeFoodRepo(oats))
+
+	template, _, err := svc.CreateTemplate(context.Background(), userID.Hex(), &request.CreateMealTemplateRequest{
+		Name:     "Porridge",
+		MealType: "breakfast",
+		FoodItems: []request.MealTemplateFoodItemRequest{
+			{FoodItemID: oats.ID.Hex(), ServingUnit: "gram", Amount: 40},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	updated, err := svc.AddFoodToTemplate(context.Background(), userID.Hex(), template.ID.Hex(), &request.AddFoodToTemplateRequest{
+		FoodItems: []request.MealTemplateFoodItemRequest{
+			{FoodItemID: oats.ID.Hex(), ServingUnit: "gram", Amount: 60},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(updated.FoodItems) != 1 {
+		t.Fatalf("Expected the same food and unit to merge into 1 item, got: %d", len(updated.FoodItems))
+	}
+	item := updated.FoodItems[0]
+	if item.Amount != 100 {
+		t.Errorf("Expected merged amount 100, got: %v", item.Amount)
+	}
+	if item.Calories != 389 || updated.TotalCalories != 389 {
+		t.Errorf("Expected item and total calories recomputed for 100g (389), got: %v and %v", item.Calories, updated.TotalCalories)
+	}
+	if math.Abs(updated.TotalMacros.Protein-16.9) > 1e-9 {
+		t.Errorf("Expected total protein 16.9, got: %v", updated.TotalMacros.Protein)
+	}
+}
+
+func TestMealService_AddFoodToTemplate_KeepsOtherUnitsSeparate(t *testing.T) {
+	userID := primitive.NewObjectID()
+	oats := newSuggestionTestFood("Oats", 389, domain.MacroNutrients{Protein: 16.9, Carbohydrates: 66.3, Fat: 6.9})
+	oats.ServingSizes = append(oats.ServingSizes, domain.ServingSize{Unit: "cup", Amount: 1, GramEquivalent: 80})
+	svc := newTestMealService(newFakeMealTemplateRepo(), newFakeFoodRepo(oats))
+
+	template, _, err := svc.CreateTemplate(context.Background(), userID.Hex(), &request.CreateMealTemplateRequest{
+		Name:     "Porridge",
+		MealType: "breakfast",
+		FoodItems: []request.MealTemplateFoodItemRequest{
+			{FoodItemID: oats.ID.Hex(), ServingUnit: "gram", Amount: 40},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	updated, err := svc.AddFoodToTemplate(context.Background(), userID.Hex(), template.ID.Hex(), &request.AddFoodToTemplateRequest{
+		FoodItems: []request.MealTemplateFoodItemRequest{
+			{FoodItemID: oats.ID.Hex(), ServingUnit: "cup", Amount: 1},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(updated.FoodItems) != 2 {
+		t.Fatalf("Expected a different unit to be added as a new item, got: %d items", len(updated.FoodItems))
+	}
+	// 40g plus one 80g cup
+	if math.Abs(updated.TotalCalories-389*1.2) > 1e-9 {
+		t.Errorf("Expected total calories %v, got: %v", 389*1.2, updated.TotalCalories)
+	}
+}
+
+func TestMealService_BulkDelete_ByTag(t *testing.T) {
+	userID := primitive.NewObjectID()
+	newTemplate := func(owner primitive.ObjectID, mealType string, tags ...string) *domain.MealTemplate {
+		return &domain.MealTemplate{ID: primitive.NewObjectID(), UserID: owner, MealType: mealType, Tags: tags}
+	}
+	oldBreakfast := newTemplate(userID, "breakfast", "old", "quick")
+	oldDinner := newTemplate(userID, "dinner", "old")
+	keeper := newTemplate(userID, "breakfast", "favorite")
+	othersOld := newTemplate(primitive.NewObjectID(), "breakfast", "old")
+	templates := newFakeMealTemplateRepo(oldBreakfast, oldDinner, keeper, othersOld)
+	svc := newTestMealService(templates, newFakeFoodRepo())
+
+	deleted, err := svc.BulkDelete(context.Background(), userID.Hex(), &request.BulkDeleteTemplatesRequest{Tags: []string{"old"}})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if deleted != 2 {
+		t.Errorf("Expected 2 templates deleted, got: %d", deleted)
+	}
+	for _, kept := range []*domain.MealTemplate{keeper, othersOld} {
+		if _, ok := templates.templates[kept.ID]; !ok {
+			t.Errorf("Expected template with tags %v to be kept", kept.Tags)
+		}
+	}
+
+	// Criteria combine, and no dinner template is tagged favorite
+	deleted, err = svc.BulkDelete(context.Background(), userID.Hex(), &request.BulkDeleteTemplatesRequest{MealType: "dinner", Tags: []string{"favorite"}})
+	if err != nil || deleted != 0 {
+		t.Errorf("Expected nothing deleted for dinner favorites, got: %d, %v", deleted, err)
+	}
+}
+
+func TestMealService_BulkDelete_RefusesEmptyFilter(t *testing.T) {
+	userID := primitive.NewObjectID()
+	template := &domain.MealTemplate{ID: primitive.NewObjectID(), UserID: userID, MealType: "lunch"}
+	templates := newFakeMealTemplateRepo(template)
+	svc := newTestMealService(templates, newFakeFoodRepo())
+
+	for _, filter := range []*request.BulkDeleteTemplatesRequest{{}, {Tags: []string{" ", ""}}} {
+		_, err := svc.BulkDelete(context.Background(), userID.Hex(), filter)
+		if err == nil || !strings.HasPrefix(err.Error(), "validation failed:") {
+			t.Errorf("Expected an empty filter to be refused, got: %v", err)
+		}
+	}
+	if _, err := svc.BulkDelete(context.Background(), userID.Hex(), &request.BulkDeleteTemplatesRequest{MealType: "supper"}); err == nil || !strings.HasPrefix(err.Error(), "invalid ") {
+		t.Errorf("Expected an unknown meal type to be invalid, got: %v", err)
+	}
+	if len(templates.templates) != 1 {
+		t.Errorf("Expected no template to be deleted, got: %d left", len(templates.templates))
+	}
+}
+
+func TestMealService_ExpandTemplateFoods_HidesOtherUsersPrivateFoods(t *testing.T) {
+	userID := primitive.NewObjectID()
+	public := &domain.FoodItem{ID: primitive.NewObjectID(), Visibility: "public", CreatedBy: primitive.NewObjectID()}
+	own := &domain.FoodItem{ID: primitive.NewObjectID(), Visibility: "private", CreatedBy: userID}
+	foreign := &domain.FoodItem{ID: primitive.NewObjectID(), Visibility: "private", CreatedBy: primitive.NewObjectID()}
+	template := &domain.MealTemplate{
+		ID:       primitive.NewObjectID(),
+		IsPublic: true,
+		FoodItems: []domain.MealTemplateFoodItem{
+			{FoodItemID: public.ID}, {FoodItemID: own.ID}, {FoodItemID: foreign.ID}, {FoodItemID: public.ID},
+		},
+	}
+	svc := newTestMealService(newFakeMealTemplateRepo(template), newFakeFoodRepo(public, own, foreign))
+
+	foods, err := svc.ExpandTemplateFoods(context.Background(), userID.Hex(), template)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(foods.Foods) != 2 || foods.Foods[public.ID] == nil || foods.Foods[own.ID] == nil {
+		t.Errorf("Expected the public and own foods, got: %v", foods.Foods)
+	}
+	if _, ok := foods.Foods[foreign.ID]; ok {
+		t.Errorf("Expected another user's private food to be left out")
+	}
+	if len(foods.Inaccessible) != 1 || !foods.Inaccessible[foreign.ID] {
+		t.Errorf("Expected another user's private food to be reported as inaccessible, got: %v", foods.Inaccessible)
+	}
+}
+
+func TestMealService_ListTags_AggregatesAcrossTemplates(t *testing.T) {
+	userID := primitive.NewObjectID()
+	newTemplate := func(owner primitive.ObjectID, tags ...string) *domain.MealTemplate {
+		return &domain.MealTemplate{ID: primitive.NewObjectID(), UserID: owner, MealType: "lunch", Tags: tags}
+	}
+	templates := newFakeMealTemplateRepo(
+		newTemplate(userID, "quick", "vegan"),
+		newTemplate(userID, "quick", "high-protein"),
+		newTemplate(userID, "quick"),
+		newTemplate(userID),
+		newTemplate(primitive.NewObjectID(), "vegan", "keto"),
+	)
+	svc := newTestMealService(templates, newFakeFoodRepo())
+
+	tags, err := svc.ListTags(context.Background(), userID.Hex())
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	want := []domain.TagCount{{Tag: "quick", Count: 3}, {Tag: "high-protein", Count: 1}, {Tag: "vegan", Count: 1}}
+	if len(tags) != len(want) {
+		t.Fatalf("Expected %d tags, got: %+v", len(want), tags)
+	}
+	for i := range want {
+		if tags[i] != want[i] {
+			t.Errorf("Expected tag %d to be %+v, got: %+v", i, want[i], tags[i])
+		}
+	}
+
+	empty, err := svc.ListTags(context.Background(), primitive.NewObjectID().Hex())
+	if err != nil || empty == nil || len(empty) != 0 {
+		t.Errorf("Expected an empty list for a user without templates, got: %v, %v", empty, err)
+	}
+}
+
+func TestMealService_CreateTemplate_RejectsAbsurdServing(t *testing.T) {
+	oats := newSuggestionTestFood("Oats", 389, domain.MacroNutrients{Protein: 16.9, Carbohydrates: 66.3, Fat: 6.9})
+	templates := newFakeMealTemplateRepo()
+	svc := NewMealService(templates, newFakeFoodRepo(oats), NewNoopTransactor(), config.MealConfig{MaxItemGrams: 5000}, logger.NewNoopLogger())
+
+	tests := []struct {
+		name     string
+		unit     string
+		amount   float64
+		expected string
+	}{
+		// 20 kg passes the per-unit amount check but not once converted
+		{"too heavy", "kg", 20, "weighs 20000g, more than the 5000g allowed per item"},
+		{"unit the food lacks", "piece", 1, "serving unit 'piece' not found"},
+	}
+	for _, tt := range tests {
+		_, _, err := svc.CreateTemplate(context.Background(), primitive.NewObjectID().Hex(), &request.CreateMealTemplateRequest{
+			Name:      "Porridge",
+			MealType:  "breakfast",
+			FoodItems: []request.MealTemplateFoodItemRequest{{FoodItemID: oats.ID.Hex(), ServingUnit: tt.unit, Amount: tt.amount}},
+		})
+		if err == nil || !strings.HasPrefix(err.Error(), "validation failed:") || !strings.Contains(err.Error(), tt.expected) {
+			t.Errorf("%s: expected a validation error containing %q, got: %v", tt.name, tt.expected, err)
+		}
+	}
+	if len(templates.templates) != 0 {
+		t.Errorf("Expected nothing to be saved, got: %d templates", len(templates.templates))
+	}
+}
@@ -2,19 +2,29 @@ package service
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 
+	"nutrient_be/internal/config"
 	"nutrient_be/internal/domain"
+	"nutrient_be/internal/dto/request"
+	"nutrient_be/internal/pkg/calculator"
+	"nutrient_be/internal/pkg/events"
 	"nutrient_be/internal/pkg/logger"
+	"nutrient_be/internal/pkg/validator"
 )
 
 // MealPlanRepository defines the interface for meal plan data operations used by MealPlanService
 type MealPlanRepository interface {
 	Create(ctx context.Context, plan *domain.MealPlan) error
 	GetByID(ctx context.Context, id primitive.ObjectID) (*domain.MealPlan, error)
-	GetByUser(ctx context.Context, userID primitive.ObjectID, planType string, limit, offset int) ([]*domain.MealPlan, error)
-	GetByUserAndDateRange(ctx context.Context, userID primitive.ObjectID, startDate, endDate string) ([]*domain.MealPlan, error)
+	GetByUser(ctx context.Context, userID primitive.ObjectID, planType, status string, limit, offset int) ([]*domain.MealPlan, error)
+	GetByUserAndDateRange(ctx context.Context, userID primitive.ObjectID, startDate, endDate time.Time) ([]*domain.MealPlan, error)
 	Update(ctx context.Context, plan *domain.MealPlan) error
 	Delete(ctx context.Context, id primitive.ObjectID) error
 	UpdateMealCompletion(ctx context.Context, planID primitive.ObjectID, mealID string, isCompleted bool) error
@@ -26,22 +36,800 @@ type MealPlanTemplateRepository interface {
 	GetByID(ctx context.Context, id primitive.ObjectID) (*domain.MealTemplate, error)
 	GetByUser(ctx context.Context, userID primitive.ObjectID, mealType string, limit, offset int) ([]*domain.MealTemplate, error)
 	GetPublicTemplates(ctx context.Context, mealType string, limit, offset int) ([]*domain.MealTemplate, error)
+	GetWithinCalories(ctx context.Context, userID primitive.ObjectID, mealType string, maxCalories float64, limit int) ([]*domain.MealTemplate, error)
 	Update(ctx context.Context, template *domain.MealTemplate) error
 	Delete(ctx context.Context, id primitive.ObjectID) error
 }
 
+// MealPlanShoppingListRepository defines the interface for shopping list data operations used by MealPlanService
+type MealPlanShoppingListRepository interface {
+	DeleteByMealPlan(ctx context.Context, mealPlanID primitive.ObjectID) error
+}
+
+// MealPlanUserRepository defines the user data operations used by MealPlanService
+type MealPlanUserRepository interface {
+	GetByID(ctx context.Context, id primitive.ObjectID) (*domain.User, error)
+}
+
 // MealPlanService handles meal plan business logic
 type MealPlanService struct {
 	mealPlanRepo     MealPlanRepository
 	mealTemplateRepo MealPlanTemplateRepository
+	shoppingRepo     MealPlanShoppingListRepository
+	userRepo         MealPlanUserRepository
+	userConfig       config.UserConfig
+	publisher        events.Publisher
+	validator        *validator.MealPlanValidator
 	logger           logger.Logger
 }
 
 // NewMealPlanService creates a new meal plan service
-func NewMealPlanService(mealPlanRepo MealPlanRepository, mealTemplateRepo MealPlanTemplateRepository, log logger.Logger) *MealPlanService {
+// A mealplan.updated event is published through publisher whenever a plan is updated;
+// pass events.NewNoopPublisher() when nothing consumes them. userConfig tells how the
+// macro targets in user preferences are stored when a plan takes its targets from them.
+func NewMealPlanService(mealPlanRepo MealPlanRepository, mealTemplateRepo MealPlanTemplateRepository, shoppingRepo MealPlanShoppingListRepository, userRepo MealPlanUserRepository, userConfig config.UserConfig, publisher events.Publisher, log logger.Logger) *MealPlanService {
 	return &MealPlanService{
 		mealPlanRepo:     mealPlanRepo,
 		mealTemplateRepo: mealTemplateRepo,
+		shoppingRepo:     shoppingRepo,
+		userRepo:         userRepo,
+		userConfig:       userConfig,
+		publisher:        publisher,
+		validator:        validator.NewMealPlanValidator(log),
 		logger:           log,
 	}
 }
+
+// Create creates a draft meal plan with a day for every date from the start to the end date
+// Each day gets an open meal for every supported meal type at its default time, which RegenerateDay fills
+// from templates. With UseProfileTargets and no TargetCalories, the daily targets are taken from the
+// user's preferences; either way they must be within the validator's range.
+func (s *MealPlanService) Create(ctx context.Context, userID string, req *request.CreateMealPlanRequest) (*domain.MealPlan, error) {
+	s.logger.Info(ctx, "Creating meal plan",
+		logger.String("name", req.Name),
+		logger.Bool("use_profile_targets", req.UseProfileTargets))
+
+	userIDObj, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		s.logger.Error(ctx, "Invalid user ID", logger.Error(err))
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	if err := s.validator.ValidateCreateRequest(req); err != nil {
+		s.logger.Error(ctx, "Meal plan validation failed", logger.Error(err))
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	calories := req.TargetCalories
+	var macros domain.MacroNutrients
+	if req.TargetMacros != nil {
+		macros = domain.MacroNutrients{
+			Protein:       req.TargetMacros.Protein,
+			Carbohydrates: req.TargetMacros.Carbohydrates,
+			Fat:           req.TargetMacros.Fat,
+			Fiber:         req.TargetMacros.Fiber,
+			Sugar:         req.TargetMacros.Sugar,
+		}
+	}
+	if req.UseProfileTargets && calories == 0 {
+		user, err := s.userRepo.GetByID(ctx, userIDObj)
+		if err != nil {
+			s.logger.Error(ctx, "Failed to get user", logger.Error(err))
+			return nil, fmt.Errorf("failed to get user: %w", err)
+		}
+		if user.Preferences.CalorieTarget <= 0 {
+			return nil, fmt.Errorf("validation failed: profile has no calorie target; complete the profile or set targetCalories")
+		}
+		calories = user.Preferences.CalorieTarget
+		if req.TargetMacros == nil {
+			if macros, err = dailyMacroTargets(s.userConfig, user); err != nil {
+				return nil, fmt.Errorf("validation failed: %w", err)
+			}
+		}
+	}
+
+	if err := s.validator.ValidateTargets(calories, macros); err != nil {
+		s.logger.Error(ctx, "Meal plan targets validation failed", logger.Error(err))
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	start, end := startOfDay(req.StartDate), startOfDay(req.EndDate)
+	plan := &domain.MealPlan{
+		ID:             primitive.NewObjectID(),
+		UserID:         userIDObj,
+		Name:           req.Name,
+		Description:    req.Description,
+		StartDate:      start,
+		EndDate:        end,
+		PlanType:       req.PlanType,
+		Goal:           req.Goal,
+		TargetCalories: calories,
+		TargetMacros:   macros,
+		Status:         domain.MealPlanStatusDraft,
+	}
+	for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+		plan.DailyMeals = append(plan.DailyMeals, domain.DailyMeal{
+			Date:      day,
+			DayOfWeek: day.Weekday().String(),
+			Meals:     newMealSlots(),
+		})
+	}
+
+	if err := s.mealPlanRepo.Create(ctx, plan); err != nil {
+		s.logger.Error(ctx, "Failed to create meal plan", logger.Error(err))
+		return nil, fmt.Errorf("failed to create meal plan: %w", err)
+	}
+
+	s.logger.Info(ctx, "Meal plan created successfully",
+		logger.String("plan_id", plan.ID.Hex()),
+		logger.Float64("target_calories", calories))
+	return plan, nil
+}
+
+// newMealSlots returns an open meal without foods for every supported meal type, in the order meals occur in a day
+func newMealSlots() []domain.Meal {
+	mealTypes := validator.MealTypes()
+	meals := make([]domain.Meal, len(mealTypes))
+	for i, mealType := range mealTypes {
+		meals[i] = newMealSlot(mealType)
+	}
+	return meals
+}
+
+// missingMealSlots returns an open meal for every supported meal type that meals has none of
+func missingMealSlots(meals []domain.Meal) []domain.Meal {
+	present := make(map[string]bool, len(meals))
+	for _, meal := range meals {
+		present[meal.MealType] = true
+	}
+
+	var missing []domain.Meal
+	for _, mealType := range validator.MealTypes() {
+		if !present[mealType] {
+			missing = append(missing, newMealSlot(mealType))
+		}
+	}
+	return missing
+}
+
+// newMealSlot returns an open meal of mealType at its default time
+func newMealSlot(mealType string) domain.Meal {
+	return domain.Meal{
+		ID:        primitive.NewObjectID().Hex(),
+		MealType:  mealType,
+		Time:      validator.DefaultMealTime(mealType),
+		FoodItems: []domain.MealFoodItem{},
+	}
+}
+
+// ListPlans lists a user's meal plans, optionally filtered by plan type and status
+func (s *MealPlanService) ListPlans(ctx context.Context, userID string, planType, status string, limit, offset int) ([]*domain.MealPlan, error) {
+	s.logger.Info(ctx, "Listing meal plans", logger.String("plan_type", planType), logger.String("status", status))
+
+	if err := s.validator.ValidateListFilters(planType, status); err != nil {
+		s.logger.Error(ctx, "Meal plan list filter validation failed", logger.Error(err))
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	userIDObj, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		s.logger.Error(ctx, "Invalid user ID", logger.Error(err))
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	plans, err := s.mealPlanRepo.GetByUser(ctx, userIDObj, planType, status, limit, offset)
+	if err != nil {
+		s.logger.Error(ctx, "Failed to list meal plans", logger.Error(err))
+		return nil, fmt.Errorf("failed to list meal plans: %w", err)
+	}
+
+	s.logger.Info(ctx, "Meal plans listed successfully", logger.Int("count", len(plans)))
+	return plans, nil
+}
+
+// GetPlansForDate lists the user's meal plans whose date range includes the given day
+func (s *MealPlanService) GetPlansForDate(ctx context.Context, userID string, date time.Time) ([]*domain.MealPlan, error) {
+	s.logger.Info(ctx, "Getting meal plans for date", logger.String("date", date.Format(dateLayout)))
+
+	userIDObj, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		s.logger.Error(ctx, "Invalid user ID", logger.Error(err))
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	plans, err := s.mealPlanRepo.GetByUserAndDateRange(ctx, userIDObj, date, date)
+	if err != nil {
+		s.logger.Error(ctx, "Failed to get meal plans for date", logger.Error(err))
+		return nil, fmt.Errorf("failed to get meal plans for date: %w", err)
+	}
+
+	s.logger.Info(ctx, "Meal plans for date retrieved successfully", logger.Int("count", len(plans)))
+	return plans, nil
+}
+
+// GetPlan retrieves a meal plan owned by the user
+func (s *MealPlanService) GetPlan(ctx context.Context, userID string, planID string) (*domain.MealPlan, error) {
+	s.logger.Info(ctx, "Getting meal plan", logger.String("plan_id", planID))
+
+	plan, err := s.getOwnedPlan(ctx, userID, planID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info(ctx, "Meal plan retrieved successfully")
+	return plan, nil
+}
+
+// UpdatePlan applies a partial update to a meal plan owned by the user
+func (s *MealPlanService) UpdatePlan(ctx context.Context, userID string, planID string, req *request.UpdateMealPlanRequest) (*domain.MealPlan, error) {
+	s.logger.Info(ctx, "Updating meal plan", logger.String("plan_id", planID))
+
+	if err := s.validator.ValidateUpdateRequest(req); err != nil {
+		s.logger.Error(ctx, "Meal plan update validation failed", logger.Error(err))
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	plan, err := s.getOwnedPlan(ctx, userID, planID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Update fields if provided
+	if req.Name != nil {
+		plan.Name = *req.Name
+	}
+	if req.Description != nil {
+		plan.Description = *req.Description
+	}
+	if req.Goal != nil {
+		plan.Goal = *req.Goal
+	}
+	if req.TargetCalories != nil {
+		plan.TargetCalories = *req.TargetCalories
+	}
+
+	plan.UpdatedAt = time.Now()
+	if err := s.mealPlanRepo.Update(ctx, plan); err != nil {
+		s.logger.Error(ctx, "Failed to update meal plan", logger.Error(err))
+		return nil, fmt.Errorf("failed to update meal plan: %w", err)
+	}
+
+	s.publishPlanUpdated(ctx, plan)
+
+	s.logger.Info(ctx, "Meal plan updated successfully")
+	return plan, nil
+}
+
+// UpdateTargets sets the daily calorie and macro targets of a meal plan owned by the user
+// Day and plan totals are re-derived from the meals so they compare against the new target.
+// With autoGenerate, portions of meals not yet completed are rescaled by the ratio of the new to the old calorie target.
+func (s *MealPlanService) UpdateTargets(ctx context.Context, userID string, planID string, calories float64, macros *domain.MacroNutrients, autoGenerate bool) (*domain.MealPlan, error) {
+	s.logger.Info(ctx, "Updating meal plan targets",
+		logger.String("plan_id", planID),
+		logger.Float64("target_calories", calories),
+		logger.Bool("auto_generate", autoGenerate))
+
+	plan, err := s.getOwnedPlan(ctx, userID, planID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Omitted macros keep the stored ones, which must still fit the new calories
+	targetMacros := plan.TargetMacros
+	if macros != nil {
+		targetMacros = *macros
+	}
+	if err := s.validator.ValidateTargets(calories, targetMacros); err != nil {
+		s.logger.Error(ctx, "Meal plan targets validation failed", logger.Error(err))
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	if autoGenerate && plan.TargetCalories > 0 {
+		rescaleOpenMeals(plan, calories/plan.TargetCalories)
+	}
+
+	plan.TargetCalories = calories
+	plan.TargetMacros = targetMacros
+	recalculatePlanTotals(plan)
+
+	plan.UpdatedAt = time.Now()
+	if err := s.mealPlanRepo.Update(ctx, plan); err != nil {
+		s.logger.Error(ctx, "Failed to update meal plan", logger.Error(err))
+		return nil, fmt.Errorf("failed to update meal plan: %w", err)
+	}
+
+	s.publishPlanUpdated(ctx, plan)
+
+	s.logger.Info(ctx, "Meal plan targets updated successfully")
+	return plan, nil
+}
+
+// PrintableMealPlan is a meal plan laid out for printing, one page per week
+type PrintableMealPlan struct {
+	Plan  *domain.MealPlan
+	Weeks []PrintableWeek
+}
+
+// PrintableWeek holds the days of one week of a printable meal plan
+type PrintableWeek struct {
+	Number    int
+	StartDate time.Time
+	EndDate   time.Time
+	Days      []domain.DailyMeal
+}
+
+// RenderPrintable lays out a meal plan owned by the user for printing
+// Days are ordered by date and split into weeks counted from the plan's start date,
+// so plans spanning several weeks print one page per week.
+func (s *MealPlanService) RenderPrintable(ctx context.Context, userID string, planID string) (*PrintableMealPlan, error) {
+	s.logger.Info(ctx, "Rendering printable meal plan", logger.String("plan_id", planID))
+
+	plan, err := s.getOwnedPlan(ctx, userID, planID)
+	if err != nil {
+		return nil, err
+	}
+
+	days := append([]domain.DailyMeal(nil), plan.DailyMeals...)
+	sort.SliceStable(days, func(i, j int) bool { return days[i].Date.Before(days[j].Date) })
+
+	planStart := startOfDay(plan.StartDate)
+	if len(days) > 0 && startOfDay(days[0].Date).Before(planStart) {
+		planStart = startOfDay(days[0].Date)
+	}
+
+	printable := &PrintableMealPlan{Plan: plan}
+	for _, day := range days {
+		number := int(startOfDay(day.Date).Sub(planStart).Hours()/24)/7 + 1
+		if len(printable.Weeks) == 0 || printable.Weeks[len(printable.Weeks)-1].Number != number {
+			weekStart := planStart.AddDate(0, 0, (number-1)*7)
+			printable.Weeks = append(printable.Weeks, PrintableWeek{
+				Number:    number,
+				StartDate: weekStart,
+				EndDate:   weekStart.AddDate(0, 0, 6),
+			})
+		}
+		week := &printable.Weeks[len(printable.Weeks)-1]
+		week.Days = append(week.Days, day)
+	}
+
+	// The last week ends with the plan rather than after seven days
+	if n := len(printable.Weeks); n > 0 && !plan.EndDate.IsZero() {
+		if planEnd := startOfDay(plan.EndDate); planEnd.Before(printable.Weeks[n-1].EndDate) && !planEnd.Before(printable.Weeks[n-1].StartDate) {
+			printable.Weeks[n-1].EndDate = planEnd
+		}
+	}
+
+	s.logger.Info(ctx, "Printable meal plan rendered", logger.Int("weeks", len(printable.Weeks)))
+	return printable, nil
+}
+
+// DeletePlan deletes a meal plan owned by the user together with its shopping lists
+func (s *MealPlanService) DeletePlan(ctx context.Context, userID string, planID string) error {
+	s.logger.Info(ctx, "Deleting meal plan", logger.String("plan_id", planID))
+
+	plan, err := s.getOwnedPlan(ctx, userID, planID)
+	if err != nil {
+		return err
+	}
+
+	// Shopping lists are derived from the plan and can be regenerated, so remove them first
+	if err := s.shoppingRepo.DeleteByMealPlan(ctx, plan.ID); err != nil {
+		s.logger.Error(ctx, "Failed to delete meal plan shopping lists", logger.Error(err))
+		return fmt.Errorf("failed to delete shopping lists: %w", err)
+	}
+
+	if err := s.mealPlanRepo.Delete(ctx, plan.ID); err != nil {
+		s.logger.Error(ctx, "Failed to delete meal plan", logger.Error(err))
+		return fmt.Errorf("failed to delete meal plan: %w", err)
+	}
+
+	s.logger.Info(ctx, "Meal plan deleted successfully")
+	return nil
+}
+
+// UpdateMealNotes sets the notes of a single meal in a meal plan
+func (s *MealPlanService) UpdateMealNotes(ctx context.Context, userID string, planID string, mealID string, notes string) (*domain.MealPlan, error) {
+	s.logger.Info(ctx, "Updating meal notes", logger.String("plan_id", planID), logger.String("meal_id", mealID))
+
+	if err := s.validator.ValidateNotes(notes); err != nil {
+		s.logger.Error(ctx, "Meal notes validation failed", logger.Error(err))
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	plan, err := s.getOwnedPlan(ctx, userID, planID)
+	if err != nil {
+		return nil, err
+	}
+
+	found := false
+	for i := range plan.DailyMeals {
+		for j := range plan.DailyMeals[i].Meals {
+			if plan.DailyMeals[i].Meals[j].ID == mealID {
+				plan.DailyMeals[i].Meals[j].Notes = notes
+				found = true
+			}
+		}
+	}
+	if !found {
+		s.logger.Error(ctx, "Meal not found in plan", logger.String("meal_id", mealID))
+		return nil, fmt.Errorf("meal not found in meal plan")
+	}
+
+	plan.UpdatedAt = time.Now()
+	if err := s.mealPlanRepo.Update(ctx, plan); err != nil {
+		s.logger.Error(ctx, "Failed to update meal plan", logger.Error(err))
+		return nil, fmt.Errorf("failed to update meal plan: %w", err)
+	}
+
+	s.logger.Info(ctx, "Meal notes updated successfully")
+	return plan, nil
+}
+
+// UpdateDayNotes sets the notes of a single day in a meal plan
+func (s *MealPlanService) UpdateDayNotes(ctx context.Context, userID string, planID string, date time.Time, notes string) (*domain.MealPlan, error) {
+	s.logger.Info(ctx, "Updating day notes", logger.String("plan_id", planID), logger.String("date", date.Format(dateLayout)))
+
+	if err := s.validator.ValidateNotes(notes); err != nil {
+		s.logger.Error(ctx, "Day notes validation failed", logger.Error(err))
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	plan, err := s.getOwnedPlan(ctx, userID, planID)
+	if err != nil {
+		return nil, err
+	}
+
+	found := false
+	for i := range plan.DailyMeals {
+		if sameDay(plan.DailyMeals[i].Date, date) {
+			plan.DailyMeals[i].Notes = notes
+			found = true
+			break
+		}
+	}
+	if !found {
+		s.logger.Error(ctx, "Day not found in plan", logger.String("date", date.Format(dateLayout)))
+		return nil, fmt.Errorf("day not found in meal plan")
+	}
+
+	plan.UpdatedAt = time.Now()
+	if err := s.mealPlanRepo.Update(ctx, plan); err != nil {
+		s.logger.Error(ctx, "Failed to update meal plan", logger.Error(err))
+		return nil, fmt.Errorf("failed to update meal plan: %w", err)
+	}
+
+	s.logger.Info(ctx, "Day notes updated successfully")
+	return plan, nil
+}
+
+// regenerateCandidateLimit caps how many templates of each meal type are considered when regenerating a day
+const regenerateCandidateLimit = 100
+
+// UnfilledReasonNoTemplates marks a slot left as it was because the user has no template of its meal type
+const UnfilledReasonNoTemplates = "no_templates"
+
+// UnfilledSlot is a meal slot that regeneration could not fill
+type UnfilledSlot struct {
+	Date     time.Time
+	MealType string
+	Reason   string
+}
+
+// RegenerateDay replaces the open meals of one day in a meal plan with templates fitting the plan's calorie target
+// Completed meals are kept and count against the target. The rest of the target is shared between the open meals
+// in proportion to their current calories, and each gets the template of its meal type closest to its share,
+// preferring a different template than it had. Other days are left untouched.
+// Every supported meal type is expected on the day, so a missing one is added as an open slot first.
+// Slots without a usable template keep their meal and are returned as unfilled, so the rest of the day is still saved.
+func (s *MealPlanService) RegenerateDay(ctx context.Context, userID string, planID string, date time.Time) (*domain.MealPlan, []UnfilledSlot, error) {
+	s.logger.Info(ctx, "Regenerating meal plan day", logger.String("plan_id", planID), logger.String("date", date.Format(dateLayout)))
+
+	plan, err := s.getOwnedPlan(ctx, userID, planID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if day := startOfDay(date); day.Before(startOfDay(plan.StartDate)) || day.After(startOfDay(plan.EndDate)) {
+		s.logger.Error(ctx, "Date outside meal plan", logger.String("date", date.Format(dateLayout)))
+		return nil, nil, fmt.Errorf("validation failed: date %s is outside the meal plan", date.Format(dateLayout))
+	}
+	if plan.TargetCalories <= 0 {
+		return nil, nil, fmt.Errorf("validation failed: meal plan has no calorie target")
+	}
+
+	var day *domain.DailyMeal
+	for i := range plan.DailyMeals {
+		if sameDay(plan.DailyMeals[i].Date, date) {
+			day = &plan.DailyMeals[i]
+			break
+		}
+	}
+	if day == nil {
+		s.logger.Error(ctx, "Day not found in plan", logger.String("date", date.Format(dateLayout)))
+		return nil, nil, fmt.Errorf("day not found in meal plan")
+	}
+
+	day.Meals = append(day.Meals, missingMealSlots(day.Meals)...)
+
+	remaining := plan.TargetCalories
+	var openCalories float64
+	var open []int
+	for i, meal := range day.Meals {
+		if meal.IsCompleted {
+			remaining -= meal.Calories
+			continue
+		}
+		open = append(open, i)
+		openCalories += meal.Calories
+	}
+	remaining = math.Max(remaining, 0)
+
+	unfilled := []UnfilledSlot{}
+	candidates := make(map[string][]*domain.MealTemplate)
+	meals := make([]domain.Meal, len(day.Meals))
+	copy(meals, day.Meals)
+	for _, i := range open {
+		meal := meals[i]
+		if _, ok := candidates[meal.MealType]; !ok {
+			templates, err := s.regenerateCandidates(ctx, plan.UserID, meal.MealType)
+			if err != nil {
+				return nil, nil, err
+			}
+			candidates[meal.MealType] = templates
+		}
+
+		share := remaining / float64(len(open))
+		if openCalories > 0 {
+			share = remaining * meal.Calories / openCalories
+		}
+		template := closestTemplate(candidates[meal.MealType], share, meal.TemplateID)
+		if template == nil {
+			unfilled = append(unfilled, UnfilledSlot{Date: day.Date, MealType: meal.MealType, Reason: UnfilledReasonNoTemplates})
+			continue
+		}
+		meals[i] = mealFromTemplate(meal, template)
+	}
+	day.Meals = meals
+	recalculatePlanTotals(plan)
+
+	plan.UpdatedAt = time.Now()
+	if err := s.mealPlanRepo.Update(ctx, plan); err != nil {
+		s.logger.Error(ctx, "Failed to update meal plan", logger.Error(err))
+		return nil, nil, fmt.Errorf("failed to update meal plan: %w", err)
+	}
+
+	s.publishPlanUpdated(ctx, plan)
+
+	s.logger.Info(ctx, "Meal plan day regenerated successfully", logger.Int("meals", len(open)), logger.Int("unfilled", len(unfilled)))
+	return plan, unfilled, nil
+}
+
+// regenerateCandidates returns the user's and public templates of a meal type, without duplicates
+func (s *MealPlanService) regenerateCandidates(ctx context.Context, userID primitive.ObjectID, mealType string) ([]*domain.MealTemplate, error) {
+	userTemplates, err := s.mealTemplateRepo.GetByUser(ctx, userID, mealType, regenerateCandidateLimit, 0)
+	if err != nil {
+		s.logger.Error(ctx, "Failed to get user templates", logger.Error(err))
+		return nil, fmt.Errorf("failed to get meal templates: %w", err)
+	}
+	publicTemplates, err := s.mealTemplateRepo.GetPublicTemplates(ctx, mealType, regenerateCandidateLimit, 0)
+	if err != nil {
+		s.logger.Error(ctx, "Failed to get public templates", logger.Error(err))
+		return nil, fmt.Errorf("failed to get meal templates: %w", err)
+	}
+
+	var templates []*domain.MealTemplate
+	seen := make(map[primitive.ObjectID]bool)
+	for _, template := range append(userTemplates, publicTemplates...) {
+		if !seen[template.ID] {
+			seen[template.ID] = true
+			templates = append(templates, template)
+		}
+	}
+	return templates, nil
+}
+
+// closestTemplate returns the template whose calories are closest to budget, ties going to the earliest
+// The current template is only returned when there is no other
+func closestTemplate(templates []*domain.MealTemplate, budget float64, current *primitive.ObjectID) *domain.MealTemplate {
+	var best, fallback *domain.MealTemplate
+	for _, template := range templates {
+		if current != nil && template.ID == *current {
+			fallback = template
+			continue
+		}
+		if best == nil || math.Abs(template.TotalCalories-budget) < math.Abs(best.TotalCalories-budget) {
+			best = template
+		}
+	}
+	if best == nil {
+		return fallback
+	}
+	return best
+}
+
+// mealFromTemplate fills a meal slot with the foods of a template, keeping the slot's ID, meal type and time
+func mealFromTemplate(slot domain.Meal, template *domain.MealTemplate) domain.Meal {
+	templateID := template.ID
+	meal := domain.Meal{
+		ID:         slot.ID,
+		MealType:   slot.MealType,
+		Time:       slot.Time,
+		TemplateID: &templateID,
+		FoodItems:  make([]domain.MealFoodItem, len(template.FoodItems)),
+		Calories:   template.TotalCalories,
+		Macros:     template.TotalMacros,
+	}
+	if meal.Time == "" {
+		meal.Time = validator.DefaultMealTime(meal.MealType)
+	}
+	for i, item := range template.FoodItems {
+		meal.FoodItems[i] = domain.MealFoodItem{
+			FoodItemID:  item.FoodItemID,
+			FoodName:    item.FoodName,
+			ServingUnit: item.ServingUnit,
+			Amount:      item.Amount,
+			Calories:    item.Calories,
+			Macros:      item.Macros,
+		}
+	}
+	return meal
+}
+
+// Swap suggestions are templates whose calories are within swapCalorieTolerance of the meal's, but at least swapMinCalorieTolerance
+const (
+	swapCalorieTolerance    = 0.15
+	swapMinCalorieTolerance = 50.0
+	swapSuggestionLimit     = 10
+)
+
+// SwapSuggestion is a template that could replace a meal in a plan
+type SwapSuggestion struct {
+	Template     *domain.MealTemplate
+	CalorieDelta float64 // Template calories minus the meal's
+}
+
+// SuggestSwaps lists the user's and public templates of a meal's type with calories close to the meal's, closest first
+// The meal's current template is left out. Nothing is changed; the client applies a swap through the usual update.
+func (s *MealPlanService) SuggestSwaps(ctx context.Context, userID string, planID string, mealID string) ([]SwapSuggestion, error) {
+	s.logger.Info(ctx, "Suggesting meal swaps", logger.String("plan_id", planID), logger.String("meal_id", mealID))
+
+	plan, err := s.getOwnedPlan(ctx, userID, planID)
+	if err != nil {
+		return nil, err
+	}
+
+	var meal *domain.Meal
+	for i := range plan.DailyMeals {
+		for j := range plan.DailyMeals[i].Meals {
+			if plan.DailyMeals[i].Meals[j].ID == mealID {
+				meal = &plan.DailyMeals[i].Meals[j]
+			}
+		}
+	}
+	if meal == nil {
+		s.logger.Error(ctx, "Meal not found in plan", logger.String("meal_id", mealID))
+		return nil, fmt.Errorf("meal not found in meal plan")
+	}
+
+	tolerance := math.Max(meal.Calories*swapCalorieTolerance, swapMinCalorieTolerance)
+	// Templates come back highest calories first, so the ones inside the band are not cut off by the limit
+	templates, err := s.mealTemplateRepo.GetWithinCalories(ctx, plan.UserID, meal.MealType, meal.Calories+tolerance, fittingTemplateLimit)
+	if err != nil {
+		s.logger.Error(ctx, "Failed to get swap candidates", logger.Error(err))
+		return nil, fmt.Errorf("failed to get meal templates: %w", err)
+	}
+
+	suggestions := []SwapSuggestion{}
+	for _, template := range templates {
+		if meal.TemplateID != nil && template.ID == *meal.TemplateID {
+			continue
+		}
+		if template.TotalCalories < meal.Calories-tolerance {
+			continue
+		}
+		suggestions = append(suggestions, SwapSuggestion{Template: template, CalorieDelta: template.TotalCalories - meal.Calories})
+	}
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		return math.Abs(suggestions[i].CalorieDelta) < math.Abs(suggestions[j].CalorieDelta)
+	})
+	if len(suggestions) > swapSuggestionLimit {
+		suggestions = suggestions[:swapSuggestionLimit]
+	}
+
+	s.logger.Info(ctx, "Meal swaps suggested successfully", logger.Int("count", len(suggestions)))
+	return suggestions, nil
+}
+
+// publishPlanUpdated announces a plan change so dependent data such as shopping lists can follow
+// Publishing is best effort; the update itself has already been saved
+func (s *MealPlanService) publishPlanUpdated(ctx context.Context, plan *domain.MealPlan) {
+	data, err := json.Marshal(events.MealPlanUpdated{
+		MealPlanID: plan.ID.Hex(),
+		UserID:     plan.UserID.Hex(),
+	})
+	if err != nil {
+		s.logger.Warn(ctx, "Failed to encode meal plan updated event", logger.Error(err))
+		return
+	}
+	if err := s.publisher.Publish(ctx, events.SubjectMealPlanUpdated, data); err != nil {
+		s.logger.Warn(ctx, "Failed to publish meal plan updated event", logger.Error(err))
+	}
+}
+
+// getOwnedPlan loads a meal plan and verifies that it belongs to the user
+func (s *MealPlanService) getOwnedPlan(ctx context.Context, userID string, planID string) (*domain.MealPlan, error) {
+	userIDObj, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		s.logger.Error(ctx, "Invalid user ID", logger.Error(err))
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	planIDObj, err := primitive.ObjectIDFromHex(planID)
+	if err != nil {
+		s.logger.Error(ctx, "Invalid meal plan ID", logger.Error(err))
+		return nil, fmt.Errorf("invalid meal plan ID: %w", err)
+	}
+
+	plan, err := s.mealPlanRepo.GetByID(ctx, planIDObj)
+	if err != nil {
+		s.logger.Error(ctx, "Failed to get meal plan", logger.Error(err))
+		if err.Error() == "meal plan not found" {
+			return nil, fmt.Errorf("meal plan not found or access denied")
+		}
+		return nil, fmt.Errorf("failed to get meal plan: %w", err)
+	}
+
+	// Meal plans are never public, so only the owner has access
+	if plan.UserID != userIDObj {
+		s.logger.Error(ctx, "User does not own meal plan")
+		return nil, fmt.Errorf("meal plan not found or access denied")
+	}
+
+	return plan, nil
+}
+
+// rescaleOpenMeals multiplies the portions of meals not yet completed by factor
+func rescaleOpenMeals(plan *domain.MealPlan, factor float64) {
+	for i := range plan.DailyMeals {
+		for j := range plan.DailyMeals[i].Meals {
+			meal := &plan.DailyMeals[i].Meals[j]
+			if meal.IsCompleted {
+				continue
+			}
+			for k := range meal.FoodItems {
+				item := &meal.FoodItems[k]
+				item.Amount *= factor
+				item.Calories *= factor
+				item.Macros = calculator.ScaleMacros(item.Macros, factor)
+			}
+			meal.Calories *= factor
+			meal.Macros = calculator.ScaleMacros(meal.Macros, factor)
+		}
+	}
+}
+
+// recalculatePlanTotals re-derives day and plan totals from the meals
+func recalculatePlanTotals(plan *domain.MealPlan) {
+	plan.TotalCalories = 0
+	for i := range plan.DailyMeals {
+		day := &plan.DailyMeals[i]
+		day.TotalCalories = 0
+		macros := make([]domain.MacroNutrients, len(day.Meals))
+		for j, meal := range day.Meals {
+			day.TotalCalories += meal.Calories
+			macros[j] = meal.Macros
+		}
+		day.TotalMacros = calculator.SumMacros(macros...)
+		plan.TotalCalories += day.TotalCalories
+	}
+}
+
+// dateLayout is the layout used for calendar dates in URLs and logs
+const dateLayout = "2006-01-02"
+
+// sameDay reports whether two times fall on the same calendar day (UTC)
+func sameDay(a, b time.Time) bool {
+	ay, am, ad := a.UTC().Date()
+	by, bm, bd := b.UTC().Date()
+	return ay == by && am == bm && ad == bd
+}
@@ -0,0 +1,753 @@
+package service
+
+import (
+	"context"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"nutrient_be/internal/config"
+	"nutrient_be/internal/domain"
+	"nutrient_be/internal/dto/request"
+	"nutrient_be/internal/pkg/events"
+	"nutrient_be/internal/pkg/logger"
+)
+
+// newTestPlan builds a two-day plan with one breakfast per day owned by userID
+func newTestPlan(userID primitive.ObjectID) *domain.MealPlan {
+	start := time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)
+	return &domain.MealPlan{
+		ID:        primitive.NewObjectID(),
+		UserID:    userID,
+		Name:      "Test plan",
+		StartDate: start,
+		EndDate:   start.AddDate(0, 0, 1),
+		PlanType:  "weekly",
+		Status:    "draft",
+		DailyMeals: []domain.DailyMeal{
+			{Date: start, DayOfWeek: "Monday", Meals: []domain.Meal{{ID: "meal_1", MealType: "breakfast"}}},
+			{Date: start.AddDate(0, 0, 1), DayOfWeek: "Tuesday", Meals: []domain.Meal{{ID: "meal_2", MealType: "breakfast"}}},
+		},
+	}
+}
+
+func TestMealPlanService_UpdateMealNotes(t *testing.T) {
+	userID := primitive.NewObjectID()
+	plan := newTestPlan(userID)
+	repo := newFakeMealPlanRepo(plan)
+	svc := NewMealPlanService(repo, nil, newFakeShoppingListRepo(), nil, config.UserConfig{}, events.NewNoopPublisher(), logger.NewNoopLogger())
+
+	updated, err := svc.UpdateMealNotes(context.Background(), userID.Hex(), plan.ID.Hex(), "meal_2", "Swap toast for oats")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if got := updated.DailyMeals[1].Meals[0].Notes; got != "Swap toast for oats" {
+		t.Errorf("Expected meal note to be saved, got %q", got)
+	}
+	if got := repo.plans[plan.ID].DailyMeals[0].Meals[0].Notes; got != "" {
+		t.Errorf("Expected other meals to be untouched, got %q", got)
+	}
+}
+
+func TestMealPlanService_UpdateMealNotes_TooLong(t *testing.T) {
+	userID := primitive.NewObjectID()
+	plan := newTestPlan(userID)
+	svc := NewMealPlanService(newFakeMealPlanRepo(plan), nil, newFakeShoppingListRepo(), nil, config.UserConfig{}, events.NewNoopPublisher(), logger.NewNoopLogger())
+
+	_, err := svc.UpdateMealNotes(context.Background(), userID.Hex(), plan.ID.Hex(), "meal_1", strings.Repeat("a", 501))
+	if err == nil || !strings.Contains(err.Error(), "notes exceed maximum length") {
+		t.Errorf("Expected notes length error, got: %v", err)
+	}
+}
+
+func TestMealPlanService_UpdateMealNotes_NotOwner(t *testing.T) {
+	plan := newTestPlan(primitive.NewObjectID())
+	svc := NewMealPlanService(newFakeMealPlanRepo(plan), nil, newFakeShoppingListRepo(), nil, config.UserConfig{}, events.NewNoopPublisher(), logger.NewNoopLogger())
+
+	_, err := svc.UpdateMealNotes(context.Background(), primitive.NewObjectID().Hex(), plan.ID.Hex(), "meal_1", "note")
+	if err == nil || err.Error() != "meal plan not found or access denied" {
+		t.Errorf("Expected access denied error, got: %v", err)
+	}
+}
+
+func TestMealPlanService_UpdateDayNotes(t *testing.T) {
+	userID := primitive.NewObjectID()
+	plan := newTestPlan(userID)
+	svc := NewMealPlanService(newFakeMealPlanRepo(plan), nil, newFakeShoppingListRepo(), nil, config.UserConfig{}, events.NewNoopPublisher(), logger.NewNoopLogger())
+
+	updated, err := svc.UpdateDayNotes(context.Background(), userID.Hex(), plan.ID.Hex(), time.Date(2025, 1, 7, 0, 0, 0, 0, time.UTC), "Rest day")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if updated.DailyMeals[1].Notes != "Rest day" {
+		t.Errorf("Expected day note to be saved, got %q", updated.DailyMeals[1].Notes)
+	}
+
+	_, err = svc.UpdateDayNotes(context.Background(), userID.Hex(), plan.ID.Hex(), time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC), "x")
+	if err == nil || err.Error() != "day not found in meal plan" {
+		t.Errorf("Expected day not found error, got: %v", err)
+	}
+}
+
+func TestMealPlanService_ListPlans_FilterByStatus(t *testing.T) {
+	userID := primitive.NewObjectID()
+	draft := newTestPlan(userID)
+	active := newTestPlan(userID)
+	active.Status = "active"
+	completed := newTestPlan(userID)
+	completed.Status = "completed"
+	otherUser := newTestPlan(primitive.NewObjectID())
+	otherUser.Status = "active"
+	svc := NewMealPlanService(newFakeMealPlanRepo(draft, active, completed, otherUser), nil, newFakeShoppingListRepo(), nil, config.UserConfig{}, events.NewNoopPublisher(), logger.NewNoopLogger())
+
+	for _, status := range []string{"draft", "active", "completed"} {
+		plans, err := svc.ListPlans(context.Background(), userID.Hex(), "", status, 20, 0)
+		if err != nil {
+			t.Fatalf("Expected no error for status %q, got: %v", status, err)
+		}
+		if len(plans) != 1 || plans[0].Status != status {
+			t.Errorf("Expected exactly one %q plan, got %d", status, len(plans))
+		}
+	}
+
+	all, err := svc.ListPlans(context.Background(), userID.Hex(), "", "", 20, 0)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(all) != 3 {
+		t.Errorf("Expected 3 plans without filter, got %d", len(all))
+	}
+}
+
+func TestMealPlanService_GetPlansForDate(t *testing.T) {
+	userID := primitive.NewObjectID()
+	spanning := newTestPlan(userID)
+	spanning.StartDate = time.Date(2024, 6, 10, 0, 0, 0, 0, time.UTC)
+	spanning.EndDate = time.Date(2024, 6, 16, 0, 0, 0, 0, time.UTC)
+	startsThatDay := newTestPlan(userID)
+	startsThatDay.StartDate = time.Date(2024, 6, 15, 9, 30, 0, 0, time.UTC)
+	startsThatDay.EndDate = time.Date(2024, 6, 21, 0, 0, 0, 0, time.UTC)
+	before := newTestPlan(userID)
+	before.StartDate = time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	before.EndDate = time.Date(2024, 6, 14, 0, 0, 0, 0, time.UTC)
+	otherUser := newTestPlan(primitive.NewObjectID())
+	otherUser.StartDate = spanning.StartDate
+	otherUser.EndDate = spanning.EndDate
+	svc := NewMealPlanService(newFakeMealPlanRepo(spanning, startsThatDay, before, otherUser), nil, nil, nil, config.UserConfig{}, events.NewNoopPublisher(), logger.NewNoopLogger())
+
+	plans, err := svc.GetPlansForDate(context.Background(), userID.Hex(), time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	found := make(map[primitive.ObjectID]bool, len(plans))
+	for _, plan := range plans {
+		found[plan.ID] = true
+	}
+	if len(plans) != 2 || !found[spanning.ID] || !found[startsThatDay.ID] {
+		t.Errorf("Expected the two plans covering 2024-06-15, got %d plans", len(plans))
+	}
+}
+
+func TestMealPlanService_ListPlans_InvalidFilters(t *testing.T) {
+	svc := NewMealPlanService(newFakeMealPlanRepo(), nil, newFakeShoppingListRepo(), nil, config.UserConfig{}, events.NewNoopPublisher(), logger.NewNoopLogger())
+	userID := primitive.NewObjectID().Hex()
+
+	if _, err := svc.ListPlans(context.Background(), userID, "", "archived", 20, 0); err == nil || !strings.HasPrefix(err.Error(), "validation failed:") {
+		t.Errorf("Expected validation error for invalid status, got: %v", err)
+	}
+	if _, err := svc.ListPlans(context.Background(), userID, "daily", "", 20, 0); err == nil || !strings.HasPrefix(err.Error(), "validation failed:") {
+		t.Errorf("Expected validation error for invalid plan type, got: %v", err)
+	}
+}
+
+func TestMealPlanService_GetUpdateDelete_NotOwner(t *testing.T) {
+	plan := newTestPlan(primitive.NewObjectID())
+	repo := newFakeMealPlanRepo(plan)
+	svc := NewMealPlanService(repo, nil, newFakeShoppingListRepo(), nil, config.UserConfig{}, events.NewNoopPublisher(), logger.NewNoopLogger())
+	otherUser := primitive.NewObjectID().Hex()
+	name := "Hijacked"
+
+	if _, err := svc.GetPlan(context.Background(), otherUser, plan.ID.Hex()); err == nil || err.Error() != "meal plan not found or access denied" {
+		t.Errorf("Expected access denied on get, got: %v", err)
+	}
+	if _, err := svc.UpdatePlan(context.Background(), otherUser, plan.ID.Hex(), &request.UpdateMealPlanRequest{Name: &name}); err == nil || err.Error() != "meal plan not found or access denied" {
+		t.Errorf("Expected access denied on update, got: %v", err)
+	}
+	if err := svc.DeletePlan(context.Background(), otherUser, plan.ID.Hex()); err == nil || err.Error() != "meal plan not found or access denied" {
+		t.Errorf("Expected access denied on delete, got: %v", err)
+	}
+	if _, ok := repo.plans[plan.ID]; !ok || repo.plans[plan.ID].Name != "Test plan" {
+		t.Error("Expected plan to be untouched")
+	}
+}
+
+func TestMealPlanService_UpdatePlan(t *testing.T) {
+	userID := primitive.NewObjectID()
+	plan := newTestPlan(userID)
+	svc := NewMealPlanService(newFakeMealPlanRepo(plan), nil, newFakeShoppingListRepo(), nil, config.UserConfig{}, events.NewNoopPublisher(), logger.NewNoopLogger())
+
+	name, calories := "Cutting week", 1800.0
+	updated, err := svc.UpdatePlan(context.Background(), userID.Hex(), plan.ID.Hex(), &request.UpdateMealPlanRequest{Name: &name, TargetCalories: &calories})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if updated.Name != name || updated.TargetCalories != calories {
+		t.Errorf("Expected name and calories to be updated, got %q / %v", updated.Name, updated.TargetCalories)
+	}
+
+	tooLow := 100.0
+	if _, err := svc.UpdatePlan(context.Background(), userID.Hex(), plan.ID.Hex(), &request.UpdateMealPlanRequest{TargetCalories: &tooLow}); err == nil || !strings.HasPrefix(err.Error(), "validation failed:") {
+		t.Errorf("Expected validation error, got: %v", err)
+	}
+}
+
+func TestMealPlanService_DeletePlan_CascadesShoppingList(t *testing.T) {
+	userID := primitive.NewObjectID()
+	plan := newTestPlan(userID)
+	other := newTestPlan(userID)
+	repo := newFakeMealPlanRepo(plan, other)
+	list := &domain.ShoppingList{ID: primitive.NewObjectID(), UserID: userID, MealPlanID: plan.ID}
+	otherList := &domain.ShoppingList{ID: primitive.NewObjectID(), UserID: userID, MealPlanID: other.ID}
+	shopping := newFakeShoppingListRepo(list, otherList)
+	svc := NewMealPlanService(repo, nil, shopping, nil, config.UserConfig{}, events.NewNoopPublisher(), logger.NewNoopLogger())
+
+	if err := svc.DeletePlan(context.Background(), userID.Hex(), plan.ID.Hex()); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if _, ok := repo.plans[plan.ID]; ok {
+		t.Error("Expected meal plan to be deleted")
+	}
+	if _, ok := shopping.lists[list.ID]; ok {
+		t.Error("Expected shopping list of the plan to be deleted")
+	}
+	if _, ok := shopping.lists[otherList.ID]; !ok {
+		t.Error("Expected shopping lists of other plans to be kept")
+	}
+}
+
+func TestMealPlanService_UpdateTargets(t *testing.T) {
+	newPlan := func(userID primitive.ObjectID) *domain.MealPlan {
+		plan := newTestPlan(userID)
+		plan.TargetCalories = 2000
+		plan.DailyMeals[0].Meals[0] = domain.Meal{
+			ID: "meal_1", Calories: 600, Macros: domain.MacroNutrients{Protein: 30}, IsCompleted: true,
+		}
+		plan.DailyMeals[1].Meals[0] = domain.Meal{
+			ID: "meal_2", Calories: 400, Macros: domain.MacroNutrients{Protein: 20},
+			FoodItems: []domain.MealFoodItem{{Amount: 100, Calories: 400, Macros: domain.MacroNutrients{Protein: 20}}},
+		}
+		return plan
+	}
+	macros := domain.MacroNutrients{Protein: 120, Carbohydrates: 150, Fat: 50}
+
+	t.Run("updates targets and re-derives totals", func(t *testing.T) {
+		userID := primitive.NewObjectID()
+		plan := newPlan(userID)
+		repo := newFakeMealPlanRepo(plan)
+		svc := NewMealPlanService(repo, nil, newFakeShoppingListRepo(), nil, config.UserConfig{}, events.NewNoopPublisher(), logger.NewNoopLogger())
+
+		updated, err := svc.UpdateTargets(context.Background(), userID.Hex(), plan.ID.Hex(), 1800, &macros, false)
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if updated.TargetCalories != 1800 || updated.TargetMacros != macros {
+			t.Errorf("Expected targets to be saved, got: %v %+v", updated.TargetCalories, updated.TargetMacros)
+		}
+		if updated.DailyMeals[0].TotalCalories != 600 || updated.DailyMeals[0].TotalMacros.Protein != 30 {
+			t.Errorf("Expected day totals to be re-derived, got: %v kcal, %v protein", updated.DailyMeals[0].TotalCalories, updated.DailyMeals[0].TotalMacros.Protein)
+		}
+		if updated.TotalCalories != 1000 {
+			t.Errorf("Expected plan total 1000, got: %v", updated.TotalCalories)
+		}
+		if updated.DailyMeals[1].Meals[0].Calories != 400 {
+			t.Errorf("Expected meals to be untouched without autoGenerate, got: %v", updated.DailyMeals[1].Meals[0].Calories)
+		}
+	})
+
+	t.Run("omitted macros keep the stored ones", func(t *testing.T) {
+		userID := primitive.NewObjectID()
+		plan := newPlan(userID)
+		plan.TargetMacros = macros
+		svc := NewMealPlanService(newFakeMealPlanRepo(plan), nil, newFakeShoppingListRepo(), nil, config.UserConfig{}, events.NewNoopPublisher(), logger.NewNoopLogger())
+
+		updated, err := svc.UpdateTargets(context.Background(), userID.Hex(), plan.ID.Hex(), 2200, nil, false)
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if updated.TargetCalories != 2200 || updated.TargetMacros != macros {
+			t.Errorf("Expected only the calories to change, got: %v %+v", updated.TargetCalories, updated.TargetMacros)
+		}
+	})
+
+	t.Run("autoGenerate rescales open meals", func(t *testing.T) {
+		userID := primitive.NewObjectID()
+		plan := newPlan(userID)
+		svc := NewMealPlanService(newFakeMealPlanRepo(plan), nil, newFakeShoppingListRepo(), nil, config.UserConfig{}, events.NewNoopPublisher(), logger.NewNoopLogger())
+
+		updated, err := svc.UpdateTargets(context.Background(), userID.Hex(), plan.ID.Hex(), 2500, &macros, true)
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if got := updated.DailyMeals[0].Meals[0].Calories; got != 600 {
+			t.Errorf("Expected completed meal to be kept, got: %v", got)
+		}
+		open := updated.DailyMeals[1].Meals[0]
+		if open.Calories != 500 || open.FoodItems[0].Amount != 125 || open.Macros.Protein != 25 {
+			t.Errorf("Expected open meal scaled by 1.25, got: %v kcal, %v amount, %v protein", open.Calories, open.FoodItems[0].Amount, open.Macros.Protein)
+		}
+		if updated.TotalCalories != 1100 {
+			t.Errorf("Expected plan total 1100, got: %v", updated.TotalCalories)
+		}
+	})
+
+	t.Run("rejects out-of-range values", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			calories float64
+			macros   domain.MacroNutrients
+			wantErr  string
+		}{
+			{name: "calories too low", calories: 100, wantErr: "below minimum"},
+			{name: "calories too high", calories: 9000, wantErr: "exceeds maximum"},
+			{name: "negative macro", calories: 2000, macros: domain.MacroNutrients{Fat: -1}, wantErr: "must not be negative"},
+			{name: "macros exceed calories", calories: 1000, macros: domain.MacroNutrients{Protein: 200, Fat: 50}, wantErr: "exceed target calories"},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				userID := primitive.NewObjectID()
+				plan := newPlan(userID)
+				repo := newFakeMealPlanRepo(plan)
+				svc := NewMealPlanService(repo, nil, newFakeShoppingListRepo(), nil, config.UserConfig{}, events.NewNoopPublisher(), logger.NewNoopLogger())
+
+				_, err := svc.UpdateTargets(context.Background(), userID.Hex(), plan.ID.Hex(), tt.calories, &tt.macros, false)
+				if err == nil || !strings.HasPrefix(err.Error(), "validation failed:") || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Expected validation error containing %q, got: %v", tt.wantErr, err)
+				}
+				if repo.plans[plan.ID].TargetCalories != 2000 {
+					t.Errorf("Expected stored target to be unchanged, got: %v", repo.plans[plan.ID].TargetCalories)
+				}
+			})
+		}
+	})
+}
+
+func TestMealPlanService_RenderPrintable_SplitsWeeks(t *testing.T) {
+	userID := primitive.NewObjectID()
+	plan := newTestPlan(userID)
+	start := plan.StartDate
+	plan.EndDate = start.AddDate(0, 0, 9)
+	// Days out of order, spanning two weeks
+	plan.DailyMeals = []domain.DailyMeal{
+		{Date: start.AddDate(0, 0, 8), DayOfWeek: "Tuesday"},
+		{Date: start, DayOfWeek: "Monday"},
+		{Date: start.AddDate(0, 0, 6), DayOfWeek: "Sunday"},
+		{Date: start.AddDate(0, 0, 7), DayOfWeek: "Monday"},
+	}
+	svc := NewMealPlanService(newFakeMealPlanRepo(plan), nil, newFakeShoppingListRepo(), nil, config.UserConfig{}, events.NewNoopPublisher(), logger.NewNoopLogger())
+
+	printable, err := svc.RenderPrintable(context.Background(), userID.Hex(), plan.ID.Hex())
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(printable.Weeks) != 2 {
+		t.Fatalf("Expected 2 weeks, got %d", len(printable.Weeks))
+	}
+
+	first, second := printable.Weeks[0], printable.Weeks[1]
+	if len(first.Days) != 2 || !first.Days[0].Date.Equal(start) || !first.Days[1].Date.Equal(start.AddDate(0, 0, 6)) {
+		t.Errorf("Unexpected first week days: %+v", first.Days)
+	}
+	if len(second.Days) != 2 || !second.Days[0].Date.Equal(start.AddDate(0, 0, 7)) {
+		t.Errorf("Unexpected second week days: %+v", second.Days)
+	}
+	if !second.StartDate.Equal(start.AddDate(0, 0, 7)) || !second.EndDate.Equal(plan.EndDate) {
+		t.Errorf("Expected second week to run from %v to the plan end %v, got %v to %v",
+			start.AddDate(0, 0, 7), plan.EndDate, second.StartDate, second.EndDate)
+	}
+
+	if _, err := svc.RenderPrintable(context.Background(), primitive.NewObjectID().Hex(), plan.ID.Hex()); err == nil {
+		t.Error("Expected error for a plan owned by another user")
+	}
+}
+
+func TestMealPlanService_RegenerateDay(t *testing.T) {
+	userID := primitive.NewObjectID()
+	newTemplate := func(mealType string, calories float64, owner primitive.ObjectID, public bool) *domain.MealTemplate {
+		return &domain.MealTemplate{
+			ID: primitive.NewObjectID(), UserID: owner, MealType: mealType, TotalCalories: calories, IsPublic: public,
+			TotalMacros: domain.MacroNutrients{Protein: calories / 20},
+			FoodItems:   []domain.MealTemplateFoodItem{{FoodItemID: primitive.NewObjectID(), FoodName: "Food", ServingUnit: "gram", Amount: 100, Calories: calories}},
+		}
+	}
+	currentLunch := newTemplate("lunch", 700, userID, false)
+	bigLunch := newTemplate("lunch", 750, userID, false)
+	smallLunch := newTemplate("lunch", 400, userID, false)
+	publicDinner := newTemplate("dinner", 800, primitive.NewObjectID(), true)
+	privateDinner := newTemplate("dinner", 750, primitive.NewObjectID(), false)
+	snack := newTemplate("snack", 150, userID, false)
+	templates := newFakeMealTemplateRepo(currentLunch, bigLunch, smallLunch, publicDinner, privateDinner, snack)
+
+	plan := newTestPlan(userID)
+	plan.TargetCalories = 2000
+	plan.DailyMeals[0].Meals = []domain.Meal{
+		{ID: "meal_1", MealType: "breakfast", Calories: 500, IsCompleted: true},
+		{ID: "meal_2", MealType: "lunch", Time: "12:30", TemplateID: &currentLunch.ID, Calories: 700},
+		{ID: "meal_3", MealType: "dinner", Calories: 700},
+	}
+	plan.DailyMeals[1].Meals = []domain.Meal{{ID: "meal_4", MealType: "lunch", Calories: 400}}
+	repo := newFakeMealPlanRepo(plan)
+	svc := NewMealPlanService(repo, templates, newFakeShoppingListRepo(), nil, config.UserConfig{}, events.NewNoopPublisher(), logger.NewNoopLogger())
+
+	updated, unfilled, err := svc.RegenerateDay(context.Background(), userID.Hex(), plan.ID.Hex(), plan.StartDate)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(unfilled) != 0 {
+		t.Errorf("Expected every slot to be filled, got: %+v", unfilled)
+	}
+
+	// 1500 kcal are left after breakfast, shared 750/750 between lunch and dinner; the added snack slot has no share
+	meals := updated.DailyMeals[0].Meals
+	if len(meals) != 4 || meals[3].MealType != "snack" || meals[3].TemplateID == nil || *meals[3].TemplateID != snack.ID {
+		t.Errorf("Expected the missing snack to be added and filled, got: %+v", meals)
+	}
+	if meals[0].Calories != 500 || !meals[0].IsCompleted {
+		t.Errorf("Expected the completed breakfast to be kept, got: %+v", meals[0])
+	}
+	if meals[1].TemplateID == nil || *meals[1].TemplateID != bigLunch.ID {
+		t.Errorf("Expected lunch to use the 750 kcal template, got: %v", meals[1].TemplateID)
+	}
+	if meals[1].ID != "meal_2" || meals[1].Time != "12:30" || len(meals[1].FoodItems) != 1 {
+		t.Errorf("Expected lunch to keep its slot and get the template's foods, got: %+v", meals[1])
+	}
+	if meals[2].TemplateID == nil || *meals[2].TemplateID != publicDinner.ID {
+		t.Errorf("Expected dinner to use the public template, got: %v", meals[2].TemplateID)
+	}
+
+	if updated.DailyMeals[0].TotalCalories != 2200 {
+		t.Errorf("Expected day total 2200, got: %v", updated.DailyMeals[0].TotalCalories)
+	}
+	if tuesday := updated.DailyMeals[1]; tuesday.Meals[0].TemplateID != nil || tuesday.Meals[0].Calories != 400 {
+		t.Errorf("Expected Tuesday to be untouched, got: %+v", tuesday.Meals[0])
+	}
+	if updated.TotalCalories != 2600 {
+		t.Errorf("Expected plan total 2600, got: %v", updated.TotalCalories)
+	}
+	if saved := repo.plans[plan.ID]; saved.TotalCalories != 2600 {
+		t.Errorf("Expected the regenerated plan to be saved, got total: %v", saved.TotalCalories)
+	}
+}
+
+func TestMealPlanService_RegenerateDay_OutsidePlan(t *testing.T) {
+	userID := primitive.NewObjectID()
+	plan := newTestPlan(userID)
+	plan.TargetCalories = 2000
+	svc := NewMealPlanService(newFakeMealPlanRepo(plan), newFakeMealTemplateRepo(), newFakeShoppingListRepo(), nil, config.UserConfig{}, events.NewNoopPublisher(), logger.NewNoopLogger())
+
+	_, _, err := svc.RegenerateDay(context.Background(), userID.Hex(), plan.ID.Hex(), plan.EndDate.AddDate(0, 0, 1))
+	if err == nil || !strings.HasPrefix(err.Error(), "validation failed:") {
+		t.Errorf("Expected validation error for a date outside the plan, got: %v", err)
+	}
+}
+
+func TestMealPlanService_RegenerateDay_ReportsUnfilledSlots(t *testing.T) {
+	userID := primitive.NewObjectID()
+	lunch := &domain.MealTemplate{
+		ID: primitive.NewObjectID(), UserID: userID, MealType: "lunch", TotalCalories: 600,
+		FoodItems: []domain.MealTemplateFoodItem{{FoodItemID: primitive.NewObjectID(), FoodName: "Rice", ServingUnit: "gram", Amount: 200, Calories: 600}},
+	}
+	breakfast := &domain.MealTemplate{ID: primitive.NewObjectID(), UserID: userID, MealType: "breakfast", TotalCalories: 300}
+	snack := &domain.MealTemplate{ID: primitive.NewObjectID(), UserID: userID, MealType: "snack", TotalCalories: 100}
+	// No dinner templates at all
+	templates := newFakeMealTemplateRepo(lunch, breakfast, snack)
+
+	plan := newTestPlan(userID)
+	plan.TargetCalories = 2000
+	plan.DailyMeals[0].Meals = []domain.Meal{
+		{ID: "meal_1", MealType: "lunch", Calories: 700},
+		{ID: "meal_2", MealType: "dinner", Calories: 900},
+	}
+	repo := newFakeMealPlanRepo(plan)
+	svc := NewMealPlanService(repo, templates, newFakeShoppingListRepo(), nil, config.UserConfig{}, events.NewNoopPublisher(), logger.NewNoopLogger())
+
+	updated, unfilled, err := svc.RegenerateDay(context.Background(), userID.Hex(), plan.ID.Hex(), plan.StartDate)
+	if err != nil {
+		t.Fatalf("Expected partial success, got: %v", err)
+	}
+
+	if len(unfilled) != 1 {
+		t.Fatalf("Expected 1 unfilled slot, got: %+v", unfilled)
+	}
+	if slot := unfilled[0]; slot.MealType != "dinner" || slot.Reason != UnfilledReasonNoTemplates || !slot.Date.Equal(plan.StartDate) {
+		t.Errorf("Expected the dinner slot to be unfilled for lack of templates, got: %+v", slot)
+	}
+
+	meals := updated.DailyMeals[0].Meals
+	if meals[0].TemplateID == nil || *meals[0].TemplateID != lunch.ID {
+		t.Errorf("Expected lunch to be filled, got: %v", meals[0].TemplateID)
+	}
+	if meals[1].TemplateID != nil || meals[1].Calories != 900 {
+		t.Errorf("Expected dinner to be left as it was, got: %+v", meals[1])
+	}
+	if saved := repo.plans[plan.ID]; saved.DailyMeals[0].TotalCalories != 1900 {
+		t.Errorf("Expected the partial plan to be saved, got day total: %v", saved.DailyMeals[0].TotalCalories)
+	}
+}
+
+func TestMealPlanService_RegenerateDay_EmptyDayReportsMissingTemplates(t *testing.T) {
+	userID := primitive.NewObjectID()
+	var templates []*domain.MealTemplate
+	for _, mealType := range []string{"breakfast", "lunch", "snack"} {
+		templates = append(templates, &domain.MealTemplate{ID: primitive.NewObjectID(), UserID: userID, MealType: mealType, TotalCalories: 400})
+	}
+	// A day without meals, like one stored before new days got a slot per meal type
+	plan := newTestPlan(userID)
+	plan.TargetCalories = 2000
+	plan.DailyMeals[0].Meals = []domain.Meal{}
+	repo := newFakeMealPlanRepo(plan)
+	svc := NewMealPlanService(repo, newFakeMealTemplateRepo(templates...), newFakeShoppingListRepo(), nil, config.UserConfig{}, events.NewNoopPublisher(), logger.NewNoopLogger())
+
+	updated, unfilled, err := svc.RegenerateDay(context.Background(), userID.Hex(), plan.ID.Hex(), plan.StartDate)
+	if err != nil {
+		t.Fatalf("Expected partial success, got: %v", err)
+	}
+
+	if len(unfilled) != 1 || unfilled[0].MealType != "dinner" || unfilled[0].Reason != UnfilledReasonNoTemplates || !unfilled[0].Date.Equal(plan.StartDate) {
+		t.Fatalf("Expected only the dinner slot to be unfilled, got: %+v", unfilled)
+	}
+	meals := updated.DailyMeals[0].Meals
+	if len(meals) != 4 {
+		t.Fatalf("Expected a slot for each meal type, got: %+v", meals)
+	}
+	for _, meal := range meals {
+		filled := meal.TemplateID != nil
+		if filled == (meal.MealType == "dinner") {
+			t.Errorf("Expected only the dinner slot to stay open, got %s filled: %v", meal.MealType, filled)
+		}
+	}
+	if saved := repo.plans[plan.ID]; saved.DailyMeals[0].TotalCalories != 1200 {
+		t.Errorf("Expected the filled slots to be saved, got day total: %v", saved.DailyMeals[0].TotalCalories)
+	}
+}
+
+func TestMealPlanService_SuggestSwaps(t *testing.T) {
+	userID := primitive.NewObjectID()
+	current := &domain.MealTemplate{ID: primitive.NewObjectID(), UserID: userID, MealType: "lunch", TotalCalories: 600}
+	near := &domain.MealTemplate{ID: primitive.NewObjectID(), UserID: userID, MealType: "lunch", TotalCalories: 620}
+	nearest := &domain.MealTemplate{ID: primitive.NewObjectID(), IsPublic: true, MealType: "lunch", TotalCalories: 595}
+	edge := &domain.MealTemplate{ID: primitive.NewObjectID(), UserID: userID, MealType: "lunch", TotalCalories: 520}
+	tooLow := &domain.MealTemplate{ID: primitive.NewObjectID(), UserID: userID, MealType: "lunch", TotalCalories: 450}
+	tooHigh := &domain.MealTemplate{ID: primitive.NewObjectID(), UserID: userID, MealType: "lunch", TotalCalories: 800}
+	otherType := &domain.MealTemplate{ID: primitive.NewObjectID(), UserID: userID, MealType: "dinner", TotalCalories: 600}
+	otherUser := &domain.MealTemplate{ID: primitive.NewObjectID(), UserID: primitive.NewObjectID(), MealType: "lunch", TotalCalories: 600}
+	templates := newFakeMealTemplateRepo(current, near, nearest, edge, tooLow, tooHigh, otherType, otherUser)
+
+	plan := newTestPlan(userID)
+	plan.DailyMeals[0].Meals = []domain.Meal{{ID: "meal_1", MealType: "lunch", Calories: 600, TemplateID: &current.ID}}
+	svc := NewMealPlanService(newFakeMealPlanRepo(plan), templates, newFakeShoppingListRepo(), nil, config.UserConfig{}, events.NewNoopPublisher(), logger.NewNoopLogger())
+
+	swaps, err := svc.SuggestSwaps(context.Background(), userID.Hex(), plan.ID.Hex(), "meal_1")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// The band is 600 ± 90 and the current template is left out
+	expected := []*domain.MealTemplate{nearest, near, edge}
+	if len(swaps) != len(expected) {
+		t.Fatalf("Expected %d swaps, got: %d", len(expected), len(swaps))
+	}
+	for i, swap := range swaps {
+		if swap.Template.ID != expected[i].ID {
+			t.Errorf("Swap %d: expected %v calories, got: %v", i, expected[i].TotalCalories, swap.Template.TotalCalories)
+		}
+		if swap.Template.MealType != "lunch" || math.Abs(swap.CalorieDelta) > 90 {
+			t.Errorf("Swap %d: expected a lunch within 90 calories, got: %s with delta %v", i, swap.Template.MealType, swap.CalorieDelta)
+		}
+	}
+	if swaps[0].CalorieDelta != -5 {
+		t.Errorf("Expected a delta of -5 for the closest swap, got: %v", swaps[0].CalorieDelta)
+	}
+}
+
+func TestMealPlanService_SuggestSwaps_MealNotFound(t *testing.T) {
+	userID := primitive.NewObjectID()
+	plan := newTestPlan(userID)
+	svc := NewMealPlanService(newFakeMealPlanRepo(plan), newFakeMealTemplateRepo(), newFakeShoppingListRepo(), nil, config.UserConfig{}, events.NewNoopPublisher(), logger.NewNoopLogger())
+
+	_, err := svc.SuggestSwaps(context.Background(), userID.Hex(), plan.ID.Hex(), "missing")
+	if err == nil || err.Error() != "meal not found in meal plan" {
+		t.Errorf("Expected a meal not found error, got: %v", err)
+	}
+}
+
+// newCreatePlanRequest builds a one-week plan request starting tomorrow without targets
+func newCreatePlanRequest() *request.CreateMealPlanRequest {
+	start := time.Now().UTC().AddDate(0, 0, 1)
+	return &request.CreateMealPlanRequest{
+		Name:      "Next week",
+		StartDate: start,
+		EndDate:   start.AddDate(0, 0, 6),
+		PlanType:  "weekly",
+		Goal:      "weight_loss",
+	}
+}
+
+func TestMealPlanService_Create_UsesProfileTargets(t *testing.T) {
+	user := &domain.User{
+		ID:      primitive.NewObjectID(),
+		Profile: domain.UserProfile{Weight: 70},
+		Preferences: domain.UserPreferences{
+			CalorieTarget: 1800,
+			MacroTargets:  domain.MacroNutrients{Protein: 1.6, Carbohydrates: 2.0, Fat: 0.8},
+		},
+	}
+	repo := newFakeMealPlanRepo()
+	svc := NewMealPlanService(repo, nil, nil, newFakeUserRepo(user), config.UserConfig{}, events.NewNoopPublisher(), logger.NewNoopLogger())
+
+	req := newCreatePlanRequest()
+	req.UseProfileTargets = true
+	plan, err := svc.Create(context.Background(), user.ID.Hex(), req)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if plan.TargetCalories != 1800 {
+		t.Errorf("Expected the 1800 kcal profile target, got: %v", plan.TargetCalories)
+	}
+	// Per-kg targets are scaled by the 70kg profile weight
+	macros := plan.TargetMacros
+	if math.Abs(macros.Protein-112) > 1e-9 || math.Abs(macros.Carbohydrates-140) > 1e-9 || math.Abs(macros.Fat-56) > 1e-9 {
+		t.Errorf("Expected 112g/140g/56g daily macros, got: %+v", macros)
+	}
+	if len(plan.DailyMeals) != 7 || plan.Status != domain.MealPlanStatusDraft {
+		t.Errorf("Expected a draft plan with 7 days, got %d days, status %q", len(plan.DailyMeals), plan.Status)
+	}
+	if _, ok := repo.plans[plan.ID]; !ok {
+		t.Error("Expected the plan to be stored")
+	}
+
+	// Explicit targets win over the profile
+	req = newCreatePlanRequest()
+	req.UseProfileTargets = true
+	req.TargetCalories = 2200
+	plan, err = svc.Create(context.Background(), user.ID.Hex(), req)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if plan.TargetCalories != 2200 || plan.TargetMacros != (domain.MacroNutrients{}) {
+		t.Errorf("Expected only the requested 2200 kcal target, got: %v kcal, %+v", plan.TargetCalories, plan.TargetMacros)
+	}
+}
+
+func TestMealPlanService_Create_DaysCanBeRegenerated(t *testing.T) {
+	userID := primitive.NewObjectID()
+	var templates []*domain.MealTemplate
+	for _, mealType := range []string{"breakfast", "lunch", "dinner", "snack"} {
+		templates = append(templates, &domain.MealTemplate{
+			ID: primitive.NewObjectID(), UserID: userID, MealType: mealType, TotalCalories: 500,
+			FoodItems: []domain.MealTemplateFoodItem{{FoodItemID: primitive.NewObjectID(), FoodName: "Food", ServingUnit: "gram", Amount: 100, Calories: 500}},
+		})
+	}
+	svc := NewMealPlanService(newFakeMealPlanRepo(), newFakeMealTemplateRepo(templates...), newFakeShoppingListRepo(), nil, config.UserConfig{}, events.NewNoopPublisher(), logger.NewNoopLogger())
+
+	req := newCreatePlanRequest()
+	req.TargetCalories = 2000
+	plan, err := svc.Create(context.Background(), userID.Hex(), req)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	meals := plan.DailyMeals[0].Meals
+	if len(meals) != 4 || meals[0].MealType != "breakfast" || meals[0].Time != "07:00" || len(meals[0].FoodItems) != 0 {
+		t.Fatalf("Expected an open slot for each meal type, got: %+v", meals)
+	}
+
+	updated, unfilled, err := svc.RegenerateDay(context.Background(), userID.Hex(), plan.ID.Hex(), plan.StartDate)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(unfilled) != 0 {
+		t.Errorf("Expected every slot to be filled, got: %+v", unfilled)
+	}
+	day := updated.DailyMeals[0]
+	if day.TotalCalories != 2000 {
+		t.Errorf("Expected the regenerated day to total 2000 kcal, got: %v", day.TotalCalories)
+	}
+	for _, meal := range day.Meals {
+		if meal.TemplateID == nil || len(meal.FoodItems) != 1 {
+			t.Errorf("Expected the %s slot to be filled from a template, got: %+v", meal.MealType, meal)
+		}
+	}
+}
+
+func TestMealPlanService_Create_ProfileTargetsUnits(t *testing.T) {
+	perKg := domain.MacroNutrients{Protein: 1.6, Carbohydrates: 2.0, Fat: 0.8}
+	noWeight := &domain.User{
+		ID:          primitive.NewObjectID(),
+		Preferences: domain.UserPreferences{CalorieTarget: 1800, MacroTargets: perKg, MacroTargetsUnit: domain.MacroUnitGramsPerKg},
+	}
+	entered := &domain.User{
+		ID:      primitive.NewObjectID(),
+		Profile: domain.UserProfile{Weight: 70},
+		Preferences: domain.UserPreferences{
+			CalorieTarget:    1800,
+			MacroTargets:     domain.MacroNutrients{Protein: 120, Carbohydrates: 180, Fat: 60},
+			MacroTargetsUnit: domain.MacroUnitGramsPerDay,
+		},
+	}
+	repo := newFakeMealPlanRepo()
+	svc := NewMealPlanService(repo, nil, nil, newFakeUserRepo(noWeight, entered), config.UserConfig{}, events.NewNoopPublisher(), logger.NewNoopLogger())
+
+	req := newCreatePlanRequest()
+	req.UseProfileTargets = true
+	if _, err := svc.Create(context.Background(), noWeight.ID.Hex(), req); err == nil || !strings.Contains(err.Error(), "profile has no weight") {
+		t.Errorf("Expected per-kg targets without a weight to fail validation, got: %v", err)
+	}
+	if len(repo.plans) != 0 {
+		t.Errorf("Expected no plan to be created, got: %d", len(repo.plans))
+	}
+
+	plan, err := svc.Create(context.Background(), entered.ID.Hex(), req)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if plan.TargetMacros != entered.Preferences.MacroTargets {
+		t.Errorf("Expected entered daily grams to be used as they are, got: %+v", plan.TargetMacros)
+	}
+}
+
+func TestMealPlanService_Create_ProfileWithoutTargets(t *testing.T) {
+	user := &domain.User{ID: primitive.NewObjectID()}
+	repo := newFakeMealPlanRepo()
+	svc := NewMealPlanService(repo, nil, nil, newFakeUserRepo(user), config.UserConfig{}, events.NewNoopPublisher(), logger.NewNoopLogger())
+
+	req := newCreatePlanRequest()
+	req.UseProfileTargets = true
+	_, err := svc.Create(context.Background(), user.ID.Hex(), req)
+	if err == nil || !strings.Contains(err.Error(), "profile has no calorie target") {
+		t.Errorf("Expected a missing profile target error, got: %v", err)
+	}
+	if len(repo.plans) != 0 {
+		t.Errorf("Expected no plan to be created, got: %d", len(repo.plans))
+	}
+}
+
+func TestMealPlanService_Create_ValidatesTargets(t *testing.T) {
+	user := &domain.User{ID: primitive.NewObjectID(), Preferences: domain.UserPreferences{CalorieTarget: 6000}}
+	split := config.UserConfig{MacroModel: config.MacroModelSplit}
+	svc := NewMealPlanService(newFakeMealPlanRepo(), nil, nil, newFakeUserRepo(user), split, events.NewNoopPublisher(), logger.NewNoopLogger())
+
+	req := newCreatePlanRequest()
+	req.UseProfileTargets = true
+	if _, err := svc.Create(context.Background(), user.ID.Hex(), req); err == nil || !strings.HasPrefix(err.Error(), "validation failed:") {
+		t.Errorf("Expected a profile target above the plan maximum to fail validation, got: %v", err)
+	}
+
+	if _, err := svc.Create(context.Background(), user.ID.Hex(), newCreatePlanRequest()); err == nil || !strings.HasPrefix(err.Error(), "validation failed:") {
+		t.Errorf("Expected omitted targets without useProfileTargets to fail validation, got: %v", err)
+	}
+}
@@ -2,10 +2,17 @@ package service
 
 import (
 	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 
+	"nutrient_be/internal/config"
 	"nutrient_be/internal/domain"
+	"nutrient_be/internal/dto/response"
+	"nutrient_be/internal/pkg/calculator"
 	"nutrient_be/internal/pkg/logger"
 )
 
@@ -13,23 +20,686 @@ import (
 type ReportMealPlanRepository interface {
 	Create(ctx context.Context, plan *domain.MealPlan) error
 	GetByID(ctx context.Context, id primitive.ObjectID) (*domain.MealPlan, error)
-	GetByUser(ctx context.Context, userID primitive.ObjectID, planType string, limit, offset int) ([]*domain.MealPlan, error)
-	GetByUserAndDateRange(ctx context.Context, userID primitive.ObjectID, startDate, endDate string) ([]*domain.MealPlan, error)
+	GetByUser(ctx context.Context, userID primitive.ObjectID, planType, status string, limit, offset int) ([]*domain.MealPlan, error)
+	GetByUserAndDateRange(ctx context.Context, userID primitive.ObjectID, startDate, endDate time.Time) ([]*domain.MealPlan, error)
 	Update(ctx context.Context, plan *domain.MealPlan) error
 	Delete(ctx context.Context, id primitive.ObjectID) error
 	UpdateMealCompletion(ctx context.Context, planID primitive.ObjectID, mealID string, isCompleted bool) error
 }
 
+// ReportUserRepository defines the interface for user data operations used by ReportService
+type ReportUserRepository interface {
+	GetByID(ctx context.Context, id primitive.ObjectID) (*domain.User, error)
+}
+
 // ReportService handles report business logic
 type ReportService struct {
 	mealPlanRepo ReportMealPlanRepository
+	userRepo     ReportUserRepository
+	dailyValues  domain.DailyReferenceValues
+	config       config.ReportConfig
+	now          func() time.Time
 	logger       logger.Logger
 }
 
 // NewReportService creates a new report service
-func NewReportService(mealPlanRepo ReportMealPlanRepository, log logger.Logger) *ReportService {
+// dailyValues are the reference values for %DV; users can override them in their preferences
+func NewReportService(mealPlanRepo ReportMealPlanRepository, userRepo ReportUserRepository, dailyValues config.DailyReferenceValues, cfg config.ReportConfig, log logger.Logger) *ReportService {
 	return &ReportService{
 		mealPlanRepo: mealPlanRepo,
+		userRepo:     userRepo,
+		dailyValues:  dailyReferenceValuesFromConfig(dailyValues),
+		config:       cfg,
+		now:          time.Now,
 		logger:       log,
 	}
 }
+
+// GenerateWeekly builds the report for the week containing date
+// The week boundary follows the user's WeekStart preference
+func (s *ReportService) GenerateWeekly(ctx context.Context, userID string, date time.Time) (*response.WeeklyReportResponse, error) {
+	s.logger.Info(ctx, "Generating weekly report", logger.String("date", date.Format(dateLayout)))
+
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	startsOn := weekStartOrDefault(user.Preferences.WeekStart)
+	weekStart := startOfWeek(date, weekdayFor(startsOn))
+	weekEnd := weekStart.AddDate(0, 0, 7)
+
+	days, consumedMacros, err := s.collectDays(ctx, user.ID, weekStart, weekEnd)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &response.WeeklyReportResponse{
+		WeekStart:      weekStart,
+		WeekEnd:        weekEnd.AddDate(0, 0, -1),
+		StartsOn:       startsOn,
+		TargetCalories: user.Preferences.CalorieTarget,
+		Days:           days,
+	}
+	for _, day := range days {
+		report.PlannedCalories += day.PlannedCalories
+		report.ConsumedCalories += day.ConsumedCalories
+	}
+	report.ConsumedMacros = macrosToReportResponse(consumedMacros)
+	report.AverageDailyCalories = report.ConsumedCalories / float64(len(days))
+	report.AveragePercentDailyValues = s.averagePercentDailyValues(user, report.ConsumedCalories, consumedMacros, len(days))
+	report.AverageMacroDistribution = macroDistributionToResponse(consumedMacros)
+	roundWeeklyReportCalories(report)
+
+	s.logger.Info(ctx, "Weekly report generated successfully")
+	return report, nil
+}
+
+// GenerateMonthly builds the report for the given month, grouped into weeks
+// Weeks follow the user's WeekStart preference and are clipped to the month
+func (s *ReportService) GenerateMonthly(ctx context.Context, userID string, year int, month time.Month) (*response.MonthlyReportResponse, error) {
+	s.logger.Info(ctx, "Generating monthly report", logger.Int("year", year), logger.Int("month", int(month)))
+
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	startsOn := weekStartOrDefault(user.Preferences.WeekStart)
+	monthStart := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+	monthEnd := monthStart.AddDate(0, 1, 0)
+
+	days, consumedMacros, err := s.collectDays(ctx, user.ID, monthStart, monthEnd)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &response.MonthlyReportResponse{
+		Month:          monthStart.Format("2006-01"),
+		StartsOn:       startsOn,
+		TargetCalories: user.Preferences.CalorieTarget,
+		Weeks:          groupDaysByWeek(days, weekdayFor(startsOn)),
+	}
+	for _, day := range days {
+		report.PlannedCalories += day.PlannedCalories
+		report.ConsumedCalories += day.ConsumedCalories
+	}
+	report.ConsumedMacros = macrosToReportResponse(consumedMacros)
+	report.AverageDailyCalories = report.ConsumedCalories / float64(len(days))
+	report.AveragePercentDailyValues = s.averagePercentDailyValues(user, report.ConsumedCalories, consumedMacros, len(days))
+	roundMonthlyReportCalories(report)
+
+	s.logger.Info(ctx, "Monthly report generated successfully")
+	return report, nil
+}
+
+// maxRangeSummaryDays is the longest span a range summary may cover
+const maxRangeSummaryDays = 366
+
+// RangeSummary totals the nutrition consumed over [startDate, endDate] and averages it over the days with logged meals
+// Unlike the weekly and monthly reports the range can be any span of up to maxRangeSummaryDays days
+func (s *ReportService) RangeSummary(ctx context.Context, userID string, startDate, endDate time.Time) (*response.RangeSummaryResponse, error) {
+	s.logger.Info(ctx, "Generating range summary",
+		logger.String("start_date", startDate.Format(dateLayout)),
+		logger.String("end_date", endDate.Format(dateLayout)))
+
+	start, end, days, err := reportRange(startDate, endDate, maxRangeSummaryDays)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	dailyReports, consumedMacros, err := s.collectDays(ctx, user.ID, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &response.RangeSummaryResponse{
+		StartDate:   start,
+		EndDate:     end.AddDate(0, 0, -1),
+		Days:        days,
+		TotalMacros: macrosToReportResponse(consumedMacros),
+	}
+	var consumed float64
+	for _, day := range dailyReports {
+		consumed += day.ConsumedCalories
+		if day.CompletedMeals > 0 {
+			summary.LoggedDays++
+		}
+	}
+	summary.TotalCalories = response.RoundCalories(consumed)
+	if summary.LoggedDays > 0 {
+		summary.AverageDailyCalories = response.RoundCalories(consumed / float64(summary.LoggedDays))
+		summary.AverageDailyMacros = macrosToReportResponse(calculator.ScaleMacros(consumedMacros, 1/float64(summary.LoggedDays)))
+	}
+
+	s.logger.Info(ctx, "Range summary generated successfully", logger.Int("logged_days", summary.LoggedDays))
+	return summary, nil
+}
+
+// Granularities of a calorie trend
+const (
+	TrendGranularityDay  = "day"
+	TrendGranularityWeek = "week"
+)
+
+// maxCalorieTrendDays is the longest span a calorie trend may cover
+const maxCalorieTrendDays = 366
+
+// CalorieTrend returns the calories consumed from completed meals over [startDate, endDate], one point per day or week
+// Meals from every plan in the range count, and periods without completed meals are 0 so the series has no gaps.
+// Weeks follow the user's WeekStart preference; the first and last week are cut to the range.
+func (s *ReportService) CalorieTrend(ctx context.Context, userID string, startDate, endDate time.Time, granularity string) (*response.CalorieTrendResponse, error) {
+	s.logger.Info(ctx, "Generating calorie trend",
+		logger.String("start_date", startDate.Format(dateLayout)),
+		logger.String("end_date", endDate.Format(dateLayout)),
+		logger.String("granularity", granularity))
+
+	if granularity != TrendGranularityDay && granularity != TrendGranularityWeek {
+		return nil, fmt.Errorf("invalid granularity: %q (must be %s or %s)", granularity, TrendGranularityDay, TrendGranularityWeek)
+	}
+	start, end, _, err := reportRange(startDate, endDate, maxCalorieTrendDays)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	dailyReports, _, err := s.collectDays(ctx, user.ID, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	trend := &response.CalorieTrendResponse{
+		StartDate:   start,
+		EndDate:     end.AddDate(0, 0, -1),
+		Granularity: granularity,
+		Points:      buildCalorieTrendPoints(dailyReports, granularity, weekdayFor(user.Preferences.WeekStart)),
+	}
+
+	s.logger.Info(ctx, "Calorie trend generated successfully", logger.Int("points", len(trend.Points)))
+	return trend, nil
+}
+
+// buildCalorieTrendPoints turns consecutive daily reports into one point per day or per week
+func buildCalorieTrendPoints(days []response.DailyReportResponse, granularity string, weekStart time.Weekday) []response.CalorieTrendPointResponse {
+	points := make([]response.CalorieTrendPointResponse, 0, len(days))
+	if granularity == TrendGranularityWeek {
+		for _, week := range groupDaysByWeek(days, weekStart) {
+			start := week.WeekEnd.AddDate(0, 0, 1-week.Days)
+			points = append(points, response.CalorieTrendPointResponse{
+				Date:     start,
+				Days:     week.Days,
+				Calories: response.RoundCalories(week.ConsumedCalories),
+			})
+		}
+		return points
+	}
+
+	for _, day := range days {
+		points = append(points, response.CalorieTrendPointResponse{
+			Date:     day.Date,
+			Days:     1,
+			Calories: response.RoundCalories(day.ConsumedCalories),
+		})
+	}
+	return points
+}
+
+// reportRange converts the inclusive dates of a report to the half-open range [start, end) of whole UTC days
+// It rejects ranges that end before they start or span more than maxDays days
+func reportRange(startDate, endDate time.Time, maxDays int) (start, end time.Time, days int, err error) {
+	start = startOfDay(startDate)
+	end = startOfDay(endDate).AddDate(0, 0, 1)
+	if !start.Before(end) {
+		return start, end, 0, fmt.Errorf("invalid date range: start date must not be after end date")
+	}
+	days = int(end.Sub(start).Hours() / 24)
+	if days > maxDays {
+		return start, end, days, fmt.Errorf("invalid date range: %d days (maximum %d)", days, maxDays)
+	}
+	return start, end, days, nil
+}
+
+// PlanAdherence compares the nutrition planned for each day of a meal plan with the plan's daily targets
+// Every planned meal counts whether or not it was completed, so it measures the plan itself rather than what was eaten
+func (s *ReportService) PlanAdherence(ctx context.Context, userID string, planID string) (*response.PlanAdherenceResponse, error) {
+	s.logger.Info(ctx, "Generating plan adherence report", logger.String("plan_id", planID))
+
+	userIDObj, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		s.logger.Error(ctx, "Invalid user ID", logger.Error(err))
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	planIDObj, err := primitive.ObjectIDFromHex(planID)
+	if err != nil {
+		s.logger.Error(ctx, "Invalid meal plan ID", logger.Error(err))
+		return nil, fmt.Errorf("invalid meal plan ID: %w", err)
+	}
+
+	plan, err := s.mealPlanRepo.GetByID(ctx, planIDObj)
+	if err != nil {
+		s.logger.Error(ctx, "Failed to get meal plan", logger.Error(err))
+		if err.Error() == "meal plan not found" {
+			return nil, fmt.Errorf("meal plan not found or access denied")
+		}
+		return nil, fmt.Errorf("failed to get meal plan: %w", err)
+	}
+	if plan.UserID != userIDObj {
+		s.logger.Error(ctx, "User does not own meal plan")
+		return nil, fmt.Errorf("meal plan not found or access denied")
+	}
+
+	adherence := buildPlanAdherence(plan)
+	s.logger.Info(ctx, "Plan adherence report generated successfully",
+		logger.Int("days_under_target", adherence.DaysUnderTarget),
+		logger.Int("days_over_target", adherence.DaysOverTarget))
+	return adherence, nil
+}
+
+// buildPlanAdherence computes the per day and average variance of a plan's planned nutrition from its targets
+func buildPlanAdherence(plan *domain.MealPlan) *response.PlanAdherenceResponse {
+	targetMacros := plan.TargetMacros
+	negativeTarget := calculator.ScaleMacros(targetMacros, -1)
+	adherence := &response.PlanAdherenceResponse{
+		PlanID:         plan.ID.Hex(),
+		StartDate:      plan.StartDate,
+		EndDate:        plan.EndDate,
+		TargetCalories: response.RoundCalories(plan.TargetCalories),
+		TargetMacros:   macrosToReportResponse(targetMacros),
+		Days:           make([]response.PlanAdherenceDayResponse, 0, len(plan.DailyMeals)),
+	}
+
+	var plannedCalories float64
+	var plannedMacros []domain.MacroNutrients
+	for _, day := range plan.DailyMeals {
+		variance := response.RoundCalories(day.TotalCalories - plan.TargetCalories)
+		switch {
+		case variance < 0:
+			adherence.DaysUnderTarget++
+		case variance > 0:
+			adherence.DaysOverTarget++
+		}
+		adherence.Days = append(adherence.Days, response.PlanAdherenceDayResponse{
+			Date:            day.Date,
+			DayOfWeek:       day.Date.Weekday().String(),
+			PlannedCalories: response.RoundCalories(day.TotalCalories),
+			PlannedMacros:   macrosToReportResponse(day.TotalMacros),
+			CalorieVariance: variance,
+			MacroVariance:   macrosToReportResponse(calculator.SumMacros(day.TotalMacros, negativeTarget)),
+		})
+		plannedCalories += day.TotalCalories
+		plannedMacros = append(plannedMacros, day.TotalMacros)
+	}
+
+	if days := len(plan.DailyMeals); days > 0 {
+		averageCalories := plannedCalories / float64(days)
+		averageMacros := calculator.ScaleMacros(calculator.SumMacros(plannedMacros...), 1/float64(days))
+		adherence.AverageDailyCalories = response.RoundCalories(averageCalories)
+		adherence.AverageDailyMacros = macrosToReportResponse(averageMacros)
+		adherence.AverageCalorieVariance = response.RoundCalories(averageCalories - plan.TargetCalories)
+		adherence.AverageMacroVariance = macrosToReportResponse(calculator.SumMacros(averageMacros, negativeTarget))
+	}
+	return adherence
+}
+
+// maxStreakDays is how far back streaks are looked for, today included
+const maxStreakDays = 366
+
+// defaultStreakTolerancePercent is used when no streak tolerance is configured
+const defaultStreakTolerancePercent = 10.0
+
+// AdherenceStreak counts the consecutive days, up to today, whose completed-meal calories were within the configured
+// percentage of the user's calorie target, and the longest such run in the last maxStreakDays days.
+// Meals from every plan count. A day without completed meals breaks the streak like an off-target day does,
+// except today, which does not end the current streak while it is still being logged.
+func (s *ReportService) AdherenceStreak(ctx context.Context, userID string) (*response.AdherenceStreakResponse, error) {
+	s.logger.Info(ctx, "Generating adherence streak")
+
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	target := user.Preferences.CalorieTarget
+	if target <= 0 {
+		return nil, fmt.Errorf("invalid calorie target: set a calorie target in your preferences first")
+	}
+
+	tolerance := s.config.StreakTolerancePercent
+	if tolerance <= 0 {
+		tolerance = defaultStreakTolerancePercent
+	}
+
+	end := startOfDay(s.now()).AddDate(0, 0, 1)
+	start := end.AddDate(0, 0, -maxStreakDays)
+	days, _, err := s.collectDays(ctx, user.ID, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	streak := &response.AdherenceStreakResponse{
+		TargetCalories:   response.RoundCalories(target),
+		TolerancePercent: tolerance,
+		Since:            start,
+	}
+	onTarget := make([]bool, len(days))
+	run := 0
+	for i, day := range days {
+		onTarget[i] = day.CompletedMeals > 0 && math.Abs(day.ConsumedCalories-target) <= target*tolerance/100
+		if !onTarget[i] {
+			run = 0
+			continue
+		}
+		run++
+		if run > streak.LongestStreak {
+			streak.LongestStreak = run
+		}
+	}
+
+	// Walk back from today; today only adds to the streak once it is on target
+	i := len(days) - 1
+	if !onTarget[i] {
+		i--
+	}
+	for ; i >= 0 && onTarget[i]; i-- {
+		streak.CurrentStreak++
+	}
+
+	s.logger.Info(ctx, "Adherence streak generated successfully",
+		logger.Int("current_streak", streak.CurrentStreak),
+		logger.Int("longest_streak", streak.LongestStreak))
+	return streak, nil
+}
+
+// minWeightEntries is the number of weight entries needed to estimate a trend
+const minWeightEntries = 2
+
+// GoalProgress reports how the weight and calorie trends over [startDate, endDate] compare to the user's goal
+// Only weight_loss and muscle_gain goals are estimated; other cases return a response explaining why
+func (s *ReportService) GoalProgress(ctx context.Context, userID string, startDate, endDate time.Time) (*response.GoalProgressResponse, error) {
+	s.logger.Info(ctx, "Generating goal progress report",
+		logger.String("start_date", startDate.Format(dateLayout)),
+		logger.String("end_date", endDate.Format(dateLayout)))
+
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	start := startOfDay(startDate)
+	end := startOfDay(endDate).AddDate(0, 0, 1)
+	progress := &response.GoalProgressResponse{
+		StartDate: start,
+		EndDate:   end.AddDate(0, 0, -1),
+		Goal:      user.Profile.Goal,
+	}
+
+	if user.Profile.Goal != "weight_loss" && user.Profile.Goal != "muscle_gain" {
+		progress.Message = "Goal progress is only estimated for weight_loss and muscle_gain goals"
+		return progress, nil
+	}
+
+	var entries []domain.WeightEntry
+	for _, entry := range user.WeightHistory {
+		if !entry.Date.Before(start) && entry.Date.Before(end) {
+			entries = append(entries, entry)
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Date.Before(entries[j].Date) })
+	progress.WeightEntries = len(entries)
+	if len(entries) < minWeightEntries {
+		progress.Message = fmt.Sprintf("Insufficient data: at least %d weight entries are needed in this date range, found %d", minWeightEntries, len(entries))
+		return progress, nil
+	}
+
+	progress.SufficientData = true
+	progress.StartWeight = entries[0].Weight
+	progress.EndWeight = entries[len(entries)-1].Weight
+	progress.WeightChange = progress.EndWeight - progress.StartWeight
+
+	days, _, err := s.collectDays(ctx, user.ID, start, end)
+	if err != nil {
+		return nil, err
+	}
+	var consumed float64
+	for _, day := range days {
+		if day.CompletedMeals > 0 {
+			progress.LoggedDays++
+			consumed += day.ConsumedCalories
+		}
+	}
+	if progress.LoggedDays > 0 {
+		progress.AverageDailyCalories = consumed / float64(progress.LoggedDays)
+	}
+
+	profile := user.Profile
+	if profile.Weight > 0 && profile.Height > 0 && profile.Age > 0 {
+		progress.MaintenanceCalories = calculateMaintenanceCalories(profile.Weight, profile.Height, profile.Age, profile.Gender, profile.ActivityLevel)
+	}
+
+	// Losing weight needs a falling trend, gaining needs a rising one
+	direction := -1.0
+	if profile.Goal == "muscle_gain" {
+		direction = 1.0
+	}
+	onTrack := progress.WeightChange*direction > 0
+	if progress.MaintenanceCalories > 0 && progress.LoggedDays > 0 {
+		progress.AverageCalorieBalance = progress.AverageDailyCalories - progress.MaintenanceCalories
+		onTrack = onTrack && progress.AverageCalorieBalance*direction > 0
+	}
+	progress.OnTrack = &onTrack
+	progress.AverageDailyCalories = response.RoundCalories(progress.AverageDailyCalories)
+	progress.MaintenanceCalories = response.RoundCalories(progress.MaintenanceCalories)
+	progress.AverageCalorieBalance = response.RoundCalories(progress.AverageCalorieBalance)
+
+	if onTrack {
+		progress.Message = fmt.Sprintf("Your trend is aligned with your %s goal", profile.Goal)
+	} else {
+		progress.Message = fmt.Sprintf("Your trend is not aligned with your %s goal", profile.Goal)
+	}
+
+	s.logger.Info(ctx, "Goal progress report generated successfully", logger.Bool("on_track", onTrack))
+	return progress, nil
+}
+
+// getUser loads the user a report is generated for
+func (s *ReportService) getUser(ctx context.Context, userID string) (*domain.User, error) {
+	userIDObj, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		s.logger.Error(ctx, "Invalid user ID", logger.Error(err))
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userIDObj)
+	if err != nil {
+		s.logger.Error(ctx, "Failed to get user", logger.Error(err))
+		return nil, fmt.Errorf("user not found: %w", err)
+	}
+	return user, nil
+}
+
+// averagePercentDailyValues returns the average daily consumption over days as %DV
+// The configured reference values are used, overridden by the user's own where set
+func (s *ReportService) averagePercentDailyValues(user *domain.User, calories float64, macros domain.MacroNutrients, days int) response.PercentDailyValuesResponse {
+	if days == 0 {
+		return response.PercentDailyValuesResponse{}
+	}
+
+	dv := calculator.NewDailyValues(s.dailyValues, user.Preferences.DailyValues)
+	perDay := float64(days)
+	return response.PercentDailyValuesResponse{
+		Calories:      dv.PercentDailyValue(calculator.NutrientCalories, calories/perDay),
+		Protein:       dv.PercentDailyValue(calculator.NutrientProtein, macros.Protein/perDay),
+		Carbohydrates: dv.PercentDailyValue(calculator.NutrientCarbohydrates, macros.Carbohydrates/perDay),
+		Fat:           dv.PercentDailyValue(calculator.NutrientFat, macros.Fat/perDay),
+		Fiber:         dv.PercentDailyValue(calculator.NutrientFiber, macros.Fiber/perDay),
+		Sugar:         dv.PercentDailyValue(calculator.NutrientSugar, macros.Sugar/perDay),
+	}
+}
+
+// dailyReferenceValuesFromConfig converts configured reference values, using the FDA default for any left unset
+func dailyReferenceValuesFromConfig(cfg config.DailyReferenceValues) domain.DailyReferenceValues {
+	return calculator.NewDailyValues(calculator.DefaultDailyReferenceValues(), domain.DailyReferenceValues{
+		Calories:      cfg.Calories,
+		Protein:       cfg.Protein,
+		Carbohydrates: cfg.Carbohydrates,
+		Fat:           cfg.Fat,
+		Fiber:         cfg.Fiber,
+		Sugar:         cfg.Sugar,
+		VitaminA:      cfg.VitaminA,
+		VitaminC:      cfg.VitaminC,
+		Calcium:       cfg.Calcium,
+		Iron:          cfg.Iron,
+		Sodium:        cfg.Sodium,
+		Potassium:     cfg.Potassium,
+	}).References()
+}
+
+// collectDays returns one entry per calendar day in [start, end) and the macros consumed over the whole range
+func (s *ReportService) collectDays(ctx context.Context, userID primitive.ObjectID, start, end time.Time) ([]response.DailyReportResponse, domain.MacroNutrients, error) {
+	plans, err := s.mealPlanRepo.GetByUserAndDateRange(ctx, userID, start, end.AddDate(0, 0, -1))
+	if err != nil {
+		s.logger.Error(ctx, "Failed to get meal plans", logger.Error(err))
+		return nil, domain.MacroNutrients{}, fmt.Errorf("failed to get meal plans: %w", err)
+	}
+
+	days, consumed := buildDailyReports(plans, start, end)
+	return days, consumed, nil
+}
+
+// buildDailyReports aggregates the plan days falling in [start, end) into one report per calendar day
+// Plan days outside the range are ignored; consumed values only count completed meals
+func buildDailyReports(plans []*domain.MealPlan, start, end time.Time) ([]response.DailyReportResponse, domain.MacroNutrients) {
+	var days []response.DailyReportResponse
+	index := make(map[string]int)
+	for d := start; d.Before(end); d = d.AddDate(0, 0, 1) {
+		index[d.Format(dateLayout)] = len(days)
+		days = append(days, response.DailyReportResponse{Date: d, DayOfWeek: d.Weekday().String()})
+	}
+
+	consumed := make([][]domain.MacroNutrients, len(days))
+	for _, plan := range plans {
+		for _, planDay := range plan.DailyMeals {
+			i, ok := index[planDay.Date.UTC().Format(dateLayout)]
+			if !ok {
+				continue
+			}
+			days[i].PlannedCalories += planDay.TotalCalories
+			for _, meal := range planDay.Meals {
+				days[i].TotalMeals++
+				if meal.IsCompleted {
+					days[i].CompletedMeals++
+					days[i].ConsumedCalories += meal.Calories
+					consumed[i] = append(consumed[i], meal.Macros)
+				}
+			}
+		}
+	}
+
+	var total []domain.MacroNutrients
+	for i := range days {
+		dayMacros := calculator.SumMacros(consumed[i]...)
+		days[i].ConsumedMacros = macrosToReportResponse(dayMacros)
+		days[i].MacroDistribution = macroDistributionToResponse(dayMacros)
+		total = append(total, dayMacros)
+	}
+	return days, calculator.SumMacros(total...)
+}
+
+// groupDaysByWeek buckets consecutive days into weeks starting on weekStart
+func groupDaysByWeek(days []response.DailyReportResponse, weekStart time.Weekday) []response.WeekSummaryResponse {
+	var weeks []response.WeekSummaryResponse
+	for _, day := range days {
+		bucket := startOfWeek(day.Date, weekStart)
+		if len(weeks) == 0 || !weeks[len(weeks)-1].WeekStart.Equal(bucket) {
+			weeks = append(weeks, response.WeekSummaryResponse{WeekStart: bucket})
+		}
+		week := &weeks[len(weeks)-1]
+		week.WeekEnd = day.Date
+		week.Days++
+		week.PlannedCalories += day.PlannedCalories
+		week.ConsumedCalories += day.ConsumedCalories
+	}
+	return weeks
+}
+
+// roundWeeklyReportCalories rounds the report's calories for display once every total has been summed
+func roundWeeklyReportCalories(report *response.WeeklyReportResponse) {
+	report.TargetCalories = response.RoundCalories(report.TargetCalories)
+	report.PlannedCalories = response.RoundCalories(report.PlannedCalories)
+	report.ConsumedCalories = response.RoundCalories(report.ConsumedCalories)
+	report.AverageDailyCalories = response.RoundCalories(report.AverageDailyCalories)
+	for i := range report.Days {
+		report.Days[i].PlannedCalories = response.RoundCalories(report.Days[i].PlannedCalories)
+		report.Days[i].ConsumedCalories = response.RoundCalories(report.Days[i].ConsumedCalories)
+	}
+}
+
+// roundMonthlyReportCalories rounds the report's calories for display once every total has been summed
+func roundMonthlyReportCalories(report *response.MonthlyReportResponse) {
+	report.TargetCalories = response.RoundCalories(report.TargetCalories)
+	report.PlannedCalories = response.RoundCalories(report.PlannedCalories)
+	report.ConsumedCalories = response.RoundCalories(report.ConsumedCalories)
+	report.AverageDailyCalories = response.RoundCalories(report.AverageDailyCalories)
+	for i := range report.Weeks {
+		report.Weeks[i].PlannedCalories = response.RoundCalories(report.Weeks[i].PlannedCalories)
+		report.Weeks[i].ConsumedCalories = response.RoundCalories(report.Weeks[i].ConsumedCalories)
+	}
+}
+
+// startOfDay returns midnight UTC of the day containing t
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// startOfWeek returns midnight UTC of the first day of the week containing t
+func startOfWeek(t time.Time, weekStart time.Weekday) time.Time {
+	day := startOfDay(t)
+	offset := (int(day.Weekday()) - int(weekStart) + 7) % 7
+	return day.AddDate(0, 0, -offset)
+}
+
+// weekStartOrDefault returns the week start preference, defaulting to monday
+func weekStartOrDefault(weekStart string) string {
+	if weekStart == "sunday" {
+		return "sunday"
+	}
+	return "monday"
+}
+
+// weekdayFor converts a week start preference to a time.Weekday
+func weekdayFor(weekStart string) time.Weekday {
+	if weekStart == "sunday" {
+		return time.Sunday
+	}
+	return time.Monday
+}
+
+// macrosToReportResponse converts domain MacroNutrients to a response MacroNutrientsResponse
+func macrosToReportResponse(macros domain.MacroNutrients) response.MacroNutrientsResponse {
+	return response.MacroNutrientsResponse{
+		Protein:       macros.Protein,
+		Carbohydrates: macros.Carbohydrates,
+		Fat:           macros.Fat,
+		Fiber:         macros.Fiber,
+		Sugar:         macros.Sugar,
+	}
+}
+
+// macroDistributionToResponse converts the macro calorie split of macros to a response MacroDistributionResponse
+func macroDistributionToResponse(macros domain.MacroNutrients) response.MacroDistributionResponse {
+	dist := calculator.CalculateMacroDistribution(macros)
+	return response.MacroDistributionResponse{
+		Protein:       dist.Protein,
+		Carbohydrates: dist.Carbohydrates,
+		Fat:           dist.Fat,
+	}
+}
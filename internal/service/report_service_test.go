@@ -0,0 +1,567 @@
+package service
+
+import (
+	"context"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"nutrient_be/internal/config"
+	"nutrient_be/internal/domain"
+	"nutrient_be/internal/dto/response"
+	"nutrient_be/internal/pkg/logger"
+)
+
+func newReportTestPlan(userID primitive.ObjectID, start time.Time, days int) *domain.MealPlan {
+	plan := &domain.MealPlan{
+		ID:        primitive.NewObjectID(),
+		UserID:    userID,
+		StartDate: start,
+		EndDate:   start.AddDate(0, 0, days-1),
+	}
+	for i := 0; i < days; i++ {
+		plan.DailyMeals = append(plan.DailyMeals, domain.DailyMeal{
+			Date:          start.AddDate(0, 0, i),
+			TotalCalories: 2000,
+			Meals: []domain.Meal{
+				{ID: "b", Calories: 500, Macros: domain.MacroNutrients{Protein: 20}, IsCompleted: true},
+				{ID: "d", Calories: 1500},
+			},
+		})
+	}
+	return plan
+}
+
+func TestReportService_GenerateWeekly_WeekStart(t *testing.T) {
+	// 2025-01-05 is a Sunday
+	sunday := time.Date(2025, 1, 5, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		weekStart     string
+		wantWeekStart time.Time
+		wantStartsOn  string
+	}{
+		{name: "default is monday", weekStart: "", wantWeekStart: time.Date(2024, 12, 30, 0, 0, 0, 0, time.UTC), wantStartsOn: "monday"},
+		{name: "monday", weekStart: "monday", wantWeekStart: time.Date(2024, 12, 30, 0, 0, 0, 0, time.UTC), wantStartsOn: "monday"},
+		{name: "sunday", weekStart: "sunday", wantWeekStart: sunday, wantStartsOn: "sunday"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := &domain.User{ID: primitive.NewObjectID(), Preferences: domain.UserPreferences{WeekStart: tt.weekStart}}
+			plans := newFakeMealPlanRepo(newReportTestPlan(user.ID, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), 10))
+			svc := NewReportService(plans, newFakeUserRepo(user), config.DailyReferenceValues{}, config.ReportConfig{}, logger.NewNoopLogger())
+
+			report, err := svc.GenerateWeekly(context.Background(), user.ID.Hex(), sunday)
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			if !report.WeekStart.Equal(tt.wantWeekStart) {
+				t.Errorf("Expected week start %s, got: %s", tt.wantWeekStart, report.WeekStart)
+			}
+			if report.StartsOn != tt.wantStartsOn {
+				t.Errorf("Expected startsOn %q, got: %q", tt.wantStartsOn, report.StartsOn)
+			}
+			if len(report.Days) != 7 {
+				t.Fatalf("Expected 7 days, got: %d", len(report.Days))
+			}
+			if !report.WeekEnd.Equal(tt.wantWeekStart.AddDate(0, 0, 6)) {
+				t.Errorf("Expected week end %s, got: %s", tt.wantWeekStart.AddDate(0, 0, 6), report.WeekEnd)
+			}
+		})
+	}
+}
+
+func TestReportService_GenerateWeekly_Totals(t *testing.T) {
+	user := &domain.User{ID: primitive.NewObjectID(), Preferences: domain.UserPreferences{WeekStart: "sunday"}}
+	// Plan covers Thu 2025-01-02 .. Sat 2025-01-04, inside the week starting Sun 2024-12-29
+	plans := newFakeMealPlanRepo(newReportTestPlan(user.ID, time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC), 3))
+	svc := NewReportService(plans, newFakeUserRepo(user), config.DailyReferenceValues{}, config.ReportConfig{}, logger.NewNoopLogger())
+
+	report, err := svc.GenerateWeekly(context.Background(), user.ID.Hex(), time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if report.PlannedCalories != 6000 {
+		t.Errorf("Expected planned calories 6000, got: %v", report.PlannedCalories)
+	}
+	if report.ConsumedCalories != 1500 {
+		t.Errorf("Expected consumed calories 1500, got: %v", report.ConsumedCalories)
+	}
+	if report.ConsumedMacros.Protein != 60 {
+		t.Errorf("Expected consumed protein 60, got: %v", report.ConsumedMacros.Protein)
+	}
+	if report.Days[4].CompletedMeals != 1 || report.Days[4].TotalMeals != 2 {
+		t.Errorf("Expected 1/2 meals completed on Thursday, got: %d/%d", report.Days[4].CompletedMeals, report.Days[4].TotalMeals)
+	}
+}
+
+func TestReportService_GenerateWeekly_RoundsCaloriesAfterSumming(t *testing.T) {
+	defer response.SetCaloriePrecision(response.DefaultCaloriePrecision)
+	if err := response.SetCaloriePrecision(1); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	user := &domain.User{ID: primitive.NewObjectID()}
+	plan := newReportTestPlan(user.ID, time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC), 2)
+	for i := range plan.DailyMeals {
+		plan.DailyMeals[i].Meals[0].Calories = 156.4000001
+	}
+	svc := NewReportService(newFakeMealPlanRepo(plan), newFakeUserRepo(user), config.DailyReferenceValues{}, config.ReportConfig{}, logger.NewNoopLogger())
+
+	report, err := svc.GenerateWeekly(context.Background(), user.ID.Hex(), time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if report.Days[0].ConsumedCalories != 156.4 {
+		t.Errorf("Expected daily consumed calories 156.4, got: %v", report.Days[0].ConsumedCalories)
+	}
+	if report.ConsumedCalories != 312.8 {
+		t.Errorf("Expected consumed calories 312.8, got: %v", report.ConsumedCalories)
+	}
+}
+
+func TestReportService_GenerateWeekly_PercentDailyValues(t *testing.T) {
+	// Plan covers the whole week starting Mon 2024-12-30; 500 kcal and 20g protein are consumed each day
+	start := time.Date(2024, 12, 30, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		override       domain.DailyReferenceValues
+		wantCaloriesDV float64
+		wantProteinDV  float64
+	}{
+		{name: "defaults", wantCaloriesDV: 25, wantProteinDV: 40},
+		{name: "user override", override: domain.DailyReferenceValues{Calories: 2500}, wantCaloriesDV: 20, wantProteinDV: 40},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := &domain.User{ID: primitive.NewObjectID(), Preferences: domain.UserPreferences{DailyValues: tt.override}}
+			plans := newFakeMealPlanRepo(newReportTestPlan(user.ID, start, 7))
+			svc := NewReportService(plans, newFakeUserRepo(user), config.DailyReferenceValues{Calories: 2000, Protein: 50}, config.ReportConfig{}, logger.NewNoopLogger())
+
+			report, err := svc.GenerateWeekly(context.Background(), user.ID.Hex(), start)
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			if got := report.AveragePercentDailyValues.Calories; got != tt.wantCaloriesDV {
+				t.Errorf("Expected %v%% DV for calories, got: %v", tt.wantCaloriesDV, got)
+			}
+			if got := report.AveragePercentDailyValues.Protein; got != tt.wantProteinDV {
+				t.Errorf("Expected %v%% DV for protein, got: %v", tt.wantProteinDV, got)
+			}
+		})
+	}
+}
+
+func TestReportService_GenerateWeekly_MacroDistribution(t *testing.T) {
+	user := &domain.User{ID: primitive.NewObjectID()}
+	monday := time.Date(2024, 12, 30, 0, 0, 0, 0, time.UTC)
+	plan := &domain.MealPlan{ID: primitive.NewObjectID(), UserID: user.ID, StartDate: monday, EndDate: monday.AddDate(0, 0, 1)}
+	plan.DailyMeals = []domain.DailyMeal{
+		// 300 kcal protein, 400 kcal carbs and 300 kcal fat
+		{Date: monday, Meals: []domain.Meal{
+			{ID: "l", Calories: 1000, Macros: domain.MacroNutrients{Protein: 75, Carbohydrates: 100, Fat: 300.0 / 9}, IsCompleted: true},
+		}},
+		// Only protein, planned but not eaten the next day
+		{Date: monday.AddDate(0, 0, 1), Meals: []domain.Meal{
+			{ID: "s", Calories: 400, Macros: domain.MacroNutrients{Protein: 100}, IsCompleted: true},
+			{ID: "d", Calories: 800, Macros: domain.MacroNutrients{Fat: 80}},
+		}},
+	}
+	svc := NewReportService(newFakeMealPlanRepo(plan), newFakeUserRepo(user), config.DailyReferenceValues{}, config.ReportConfig{}, logger.NewNoopLogger())
+
+	report, err := svc.GenerateWeekly(context.Background(), user.ID.Hex(), monday)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	day := report.Days[0].MacroDistribution
+	if sum := day.Protein + day.Carbohydrates + day.Fat; math.Abs(sum-100) > 1e-6 {
+		t.Errorf("Expected Monday's split to sum to 100%%, got: %v", sum)
+	}
+	if math.Abs(day.Protein-30) > 1e-6 || math.Abs(day.Carbohydrates-40) > 1e-6 || math.Abs(day.Fat-30) > 1e-6 {
+		t.Errorf("Expected a 30/40/30 split on Monday, got: %.1f/%.1f/%.1f", day.Protein, day.Carbohydrates, day.Fat)
+	}
+	if got := report.Days[1].MacroDistribution; got.Protein != 100 || got.Fat != 0 {
+		t.Errorf("Expected only completed meals to count on Tuesday, got: %+v", got)
+	}
+	if got := report.Days[2].MacroDistribution; got != (response.MacroDistributionResponse{}) {
+		t.Errorf("Expected a 0/0/0 split on a day without meals, got: %+v", got)
+	}
+
+	// Over the week 700 of 1400 macro kcal are protein
+	if got := report.AverageMacroDistribution.Protein; math.Abs(got-50) > 1e-6 {
+		t.Errorf("Expected 50%% protein over the week, got: %v", got)
+	}
+}
+
+func TestReportService_GenerateMonthly_WeekBuckets(t *testing.T) {
+	// February 2025 starts on a Saturday and has 28 days
+	tests := []struct {
+		name      string
+		weekStart string
+		wantWeeks []int
+	}{
+		{name: "monday", weekStart: "monday", wantWeeks: []int{2, 7, 7, 7, 5}},
+		{name: "sunday", weekStart: "sunday", wantWeeks: []int{1, 7, 7, 7, 6}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := &domain.User{ID: primitive.NewObjectID(), Preferences: domain.UserPreferences{WeekStart: tt.weekStart}}
+			svc := NewReportService(newFakeMealPlanRepo(), newFakeUserRepo(user), config.DailyReferenceValues{}, config.ReportConfig{}, logger.NewNoopLogger())
+
+			report, err := svc.GenerateMonthly(context.Background(), user.ID.Hex(), 2025, time.February)
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			if len(report.Weeks) != len(tt.wantWeeks) {
+				t.Fatalf("Expected %d weeks, got: %d", len(tt.wantWeeks), len(report.Weeks))
+			}
+			for i, want := range tt.wantWeeks {
+				if report.Weeks[i].Days != want {
+					t.Errorf("Expected week %d to have %d days, got: %d", i, want, report.Weeks[i].Days)
+				}
+			}
+			if report.Month != "2025-02" {
+				t.Errorf("Expected month 2025-02, got: %s", report.Month)
+			}
+		})
+	}
+}
+
+func TestReportService_GoalProgress(t *testing.T) {
+	jan := func(day int) time.Time { return time.Date(2025, 1, day, 0, 0, 0, 0, time.UTC) }
+	newUser := func(history ...domain.WeightEntry) *domain.User {
+		return &domain.User{
+			ID: primitive.NewObjectID(),
+			Profile: domain.UserProfile{
+				Age: 30, Weight: 78, Height: 175, Gender: "male",
+				Goal: "weight_loss", ActivityLevel: "moderate",
+			},
+			WeightHistory: history,
+		}
+	}
+
+	t.Run("weight loss trend is on track", func(t *testing.T) {
+		user := newUser(
+			domain.WeightEntry{Weight: 80, Date: jan(1)},
+			domain.WeightEntry{Weight: 79, Date: jan(5)},
+			domain.WeightEntry{Weight: 78, Date: jan(10)},
+		)
+		// 500 kcal eaten per day, well below maintenance
+		plans := newFakeMealPlanRepo(newReportTestPlan(user.ID, jan(1), 10))
+		svc := NewReportService(plans, newFakeUserRepo(user), config.DailyReferenceValues{}, config.ReportConfig{}, logger.NewNoopLogger())
+
+		progress, err := svc.GoalProgress(context.Background(), user.ID.Hex(), jan(1), jan(10))
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if !progress.SufficientData {
+			t.Fatalf("Expected sufficient data, got message: %s", progress.Message)
+		}
+		if progress.WeightChange != -2 {
+			t.Errorf("Expected weight change -2, got: %v", progress.WeightChange)
+		}
+		if progress.LoggedDays != 10 || progress.AverageDailyCalories != 500 {
+			t.Errorf("Expected 10 logged days averaging 500 kcal, got: %d days, %v kcal", progress.LoggedDays, progress.AverageDailyCalories)
+		}
+		if progress.AverageCalorieBalance >= 0 {
+			t.Errorf("Expected a calorie deficit, got: %v", progress.AverageCalorieBalance)
+		}
+		if progress.OnTrack == nil || !*progress.OnTrack {
+			t.Errorf("Expected on track, got: %v", progress.OnTrack)
+		}
+	})
+
+	t.Run("weight gain is not on track for weight loss", func(t *testing.T) {
+		user := newUser(
+			domain.WeightEntry{Weight: 78, Date: jan(1)},
+			domain.WeightEntry{Weight: 79, Date: jan(10)},
+		)
+		svc := NewReportService(newFakeMealPlanRepo(), newFakeUserRepo(user), config.DailyReferenceValues{}, config.ReportConfig{}, logger.NewNoopLogger())
+
+		progress, err := svc.GoalProgress(context.Background(), user.ID.Hex(), jan(1), jan(10))
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if progress.OnTrack == nil || *progress.OnTrack {
+			t.Errorf("Expected not on track, got: %v", progress.OnTrack)
+		}
+	})
+
+	t.Run("insufficient weight entries", func(t *testing.T) {
+		user := newUser(
+			domain.WeightEntry{Weight: 80, Date: jan(1)},
+			domain.WeightEntry{Weight: 78, Date: jan(20)},
+		)
+		svc := NewReportService(newFakeMealPlanRepo(), newFakeUserRepo(user), config.DailyReferenceValues{}, config.ReportConfig{}, logger.NewNoopLogger())
+
+		progress, err := svc.GoalProgress(context.Background(), user.ID.Hex(), jan(1), jan(10))
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if progress.SufficientData {
+			t.Error("Expected insufficient data")
+		}
+		if progress.WeightEntries != 1 {
+			t.Errorf("Expected 1 weight entry in range, got: %d", progress.WeightEntries)
+		}
+		if progress.OnTrack != nil {
+			t.Errorf("Expected onTrack to be nil, got: %v", *progress.OnTrack)
+		}
+		if progress.Message == "" {
+			t.Error("Expected an explanatory message")
+		}
+	})
+}
+
+func TestReportService_RangeSummary_MultiWeekRange(t *testing.T) {
+	user := &domain.User{ID: primitive.NewObjectID()}
+	// Two plans with a gap: Jan 3-12 and Jan 20-24, each day eating 500 kcal and 20 g protein
+	plans := newFakeMealPlanRepo(
+		newReportTestPlan(user.ID, time.Date(2025, 1, 3, 0, 0, 0, 0, time.UTC), 10),
+		newReportTestPlan(user.ID, time.Date(2025, 1, 20, 0, 0, 0, 0, time.UTC), 5),
+	)
+	svc := NewReportService(plans, newFakeUserRepo(user), config.DailyReferenceValues{}, config.ReportConfig{}, logger.NewNoopLogger())
+
+	summary, err := svc.RangeSummary(context.Background(), user.ID.Hex(), time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 1, 28, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if summary.Days != 28 {
+		t.Errorf("Expected 28 days, got: %d", summary.Days)
+	}
+	if summary.LoggedDays != 15 {
+		t.Errorf("Expected 15 logged days, got: %d", summary.LoggedDays)
+	}
+	if summary.TotalCalories != 7500 || summary.TotalMacros.Protein != 300 {
+		t.Errorf("Expected 7500 kcal and 300 g protein in total, got: %v kcal, %v g", summary.TotalCalories, summary.TotalMacros.Protein)
+	}
+	if summary.AverageDailyCalories != 500 || summary.AverageDailyMacros.Protein != 20 {
+		t.Errorf("Expected 500 kcal and 20 g protein per logged day, got: %v kcal, %v g", summary.AverageDailyCalories, summary.AverageDailyMacros.Protein)
+	}
+	if !summary.EndDate.Equal(time.Date(2025, 1, 28, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected an inclusive end date of 2025-01-28, got: %s", summary.EndDate)
+	}
+}
+
+func TestReportService_RangeSummary_RejectsInvalidRange(t *testing.T) {
+	user := &domain.User{ID: primitive.NewObjectID()}
+	svc := NewReportService(newFakeMealPlanRepo(), newFakeUserRepo(user), config.DailyReferenceValues{}, config.ReportConfig{}, logger.NewNoopLogger())
+	jan1 := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := map[string]struct{ start, end time.Time }{
+		"start after end":      {jan1.AddDate(0, 0, 1), jan1},
+		"longer than 366 days": {jan1, jan1.AddDate(0, 0, 366)},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := svc.RangeSummary(context.Background(), user.ID.Hex(), tt.start, tt.end); err == nil {
+				t.Errorf("Expected an error, got: nil")
+			}
+		})
+	}
+
+	if _, err := svc.RangeSummary(context.Background(), user.ID.Hex(), jan1, jan1.AddDate(0, 0, 365)); err != nil {
+		t.Errorf("Expected a 366 day range to be accepted, got: %v", err)
+	}
+}
+
+func TestReportService_CalorieTrend_DailyOverTwoWeeks(t *testing.T) {
+	user := &domain.User{ID: primitive.NewObjectID()}
+	// Two overlapping plans, Jan 1-5 and Jan 4-8, each eating 500 kcal a day, and nothing after Jan 8
+	plans := newFakeMealPlanRepo(
+		newReportTestPlan(user.ID, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), 5),
+		newReportTestPlan(user.ID, time.Date(2025, 1, 4, 0, 0, 0, 0, time.UTC), 5),
+	)
+	svc := NewReportService(plans, newFakeUserRepo(user), config.DailyReferenceValues{}, config.ReportConfig{}, logger.NewNoopLogger())
+
+	trend, err := svc.CalorieTrend(context.Background(), user.ID.Hex(), time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 1, 14, 0, 0, 0, 0, time.UTC), TrendGranularityDay)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(trend.Points) != 14 {
+		t.Fatalf("Expected 14 daily points, got: %d", len(trend.Points))
+	}
+
+	for i, point := range trend.Points {
+		day := time.Date(2025, 1, 1+i, 0, 0, 0, 0, time.UTC)
+		if !point.Date.Equal(day) || point.Days != 1 {
+			t.Errorf("Point %d: expected one day on %s, got: %d days on %s", i, day.Format(dateLayout), point.Days, point.Date.Format(dateLayout))
+		}
+
+		// Uncompleted meals do not count; days covered by both plans add up
+		expected := 0.0
+		switch {
+		case i >= 3 && i <= 4:
+			expected = 1000
+		case i <= 7:
+			expected = 500
+		}
+		if point.Calories != expected {
+			t.Errorf("Point %d: expected %v kcal, got: %v", i, expected, point.Calories)
+		}
+	}
+}
+
+func TestReportService_CalorieTrend_Weekly(t *testing.T) {
+	user := &domain.User{ID: primitive.NewObjectID(), Preferences: domain.UserPreferences{WeekStart: "monday"}}
+	// 2025-01-01 is a Wednesday
+	plans := newFakeMealPlanRepo(newReportTestPlan(user.ID, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), 14))
+	svc := NewReportService(plans, newFakeUserRepo(user), config.DailyReferenceValues{}, config.ReportConfig{}, logger.NewNoopLogger())
+
+	trend, err := svc.CalorieTrend(context.Background(), user.ID.Hex(), time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 1, 14, 0, 0, 0, 0, time.UTC), TrendGranularityWeek)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	expected := []struct {
+		date     time.Time
+		days     int
+		calories float64
+	}{
+		{time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), 5, 2500},
+		{time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC), 7, 3500},
+		{time.Date(2025, 1, 13, 0, 0, 0, 0, time.UTC), 2, 1000},
+	}
+	if len(trend.Points) != len(expected) {
+		t.Fatalf("Expected %d weekly points, got: %d", len(expected), len(trend.Points))
+	}
+	for i, want := range expected {
+		got := trend.Points[i]
+		if !got.Date.Equal(want.date) || got.Days != want.days || got.Calories != want.calories {
+			t.Errorf("Week %d: expected %s, %d days, %v kcal, got: %s, %d days, %v kcal",
+				i, want.date.Format(dateLayout), want.days, want.calories, got.Date.Format(dateLayout), got.Days, got.Calories)
+		}
+	}
+}
+
+func TestReportService_CalorieTrend_RejectsInvalidInput(t *testing.T) {
+	user := &domain.User{ID: primitive.NewObjectID()}
+	svc := NewReportService(newFakeMealPlanRepo(), newFakeUserRepo(user), config.DailyReferenceValues{}, config.ReportConfig{}, logger.NewNoopLogger())
+	jan1 := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := map[string]struct {
+		start, end  time.Time
+		granularity string
+	}{
+		"start after end":      {jan1.AddDate(0, 0, 1), jan1, TrendGranularityDay},
+		"longer than 366 days": {jan1, jan1.AddDate(0, 0, 366), TrendGranularityWeek},
+		"unknown granularity":  {jan1, jan1.AddDate(0, 0, 6), "month"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := svc.CalorieTrend(context.Background(), user.ID.Hex(), tt.start, tt.end, tt.granularity); err == nil {
+				t.Errorf("Expected an error, got: nil")
+			}
+		})
+	}
+}
+
+func TestReportService_PlanAdherence_UnderTargetDays(t *testing.T) {
+	user := &domain.User{ID: primitive.NewObjectID()}
+	plan := newReportTestPlan(user.ID, time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC), 3)
+	plan.TargetCalories = 2000
+	plan.TargetMacros = domain.MacroNutrients{Protein: 100}
+	// Completion is ignored, so only the planned day totals matter
+	plan.DailyMeals[0].TotalMacros = domain.MacroNutrients{Protein: 100}
+	plan.DailyMeals[1].TotalCalories = 1700
+	plan.DailyMeals[1].TotalMacros = domain.MacroNutrients{Protein: 70}
+	plan.DailyMeals[2].TotalCalories = 2150
+	plan.DailyMeals[2].TotalMacros = domain.MacroNutrients{Protein: 110}
+	svc := NewReportService(newFakeMealPlanRepo(plan), newFakeUserRepo(user), config.DailyReferenceValues{}, config.ReportConfig{}, logger.NewNoopLogger())
+
+	adherence, err := svc.PlanAdherence(context.Background(), user.ID.Hex(), plan.ID.Hex())
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(adherence.Days) != 3 {
+		t.Fatalf("Expected 3 days, got: %d", len(adherence.Days))
+	}
+	if adherence.Days[1].CalorieVariance != -300 || adherence.Days[1].MacroVariance.Protein != -30 {
+		t.Errorf("Expected day 2 to be 300 kcal and 30 g protein under target, got: %v kcal, %v g", adherence.Days[1].CalorieVariance, adherence.Days[1].MacroVariance.Protein)
+	}
+	if adherence.Days[0].CalorieVariance != 0 {
+		t.Errorf("Expected day 1 to be on target, got: %v", adherence.Days[0].CalorieVariance)
+	}
+	if adherence.DaysUnderTarget != 1 || adherence.DaysOverTarget != 1 {
+		t.Errorf("Expected 1 day under and 1 day over target, got: %d under, %d over", adherence.DaysUnderTarget, adherence.DaysOverTarget)
+	}
+	if adherence.AverageDailyCalories != 1950 || adherence.AverageCalorieVariance != -50 {
+		t.Errorf("Expected an average of 1950 kcal, 50 under target, got: %v kcal, %v variance", adherence.AverageDailyCalories, adherence.AverageCalorieVariance)
+	}
+	if math.Abs(adherence.AverageMacroVariance.Protein+6.67) > 0.01 {
+		t.Errorf("Expected average protein about 6.67 g under target, got: %v", adherence.AverageMacroVariance.Protein)
+	}
+}
+
+func TestReportService_PlanAdherence_NotOwner(t *testing.T) {
+	user := &domain.User{ID: primitive.NewObjectID()}
+	plan := newReportTestPlan(primitive.NewObjectID(), time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC), 1)
+	svc := NewReportService(newFakeMealPlanRepo(plan), newFakeUserRepo(user), config.DailyReferenceValues{}, config.ReportConfig{}, logger.NewNoopLogger())
+
+	if _, err := svc.PlanAdherence(context.Background(), user.ID.Hex(), plan.ID.Hex()); err == nil || err.Error() != "meal plan not found or access denied" {
+		t.Errorf("Expected not found or access denied, got: %v", err)
+	}
+}
+
+// newStreakTestPlan returns a plan starting on start with one day per entry of consumed; 0 leaves the day unlogged
+func newStreakTestPlan(userID primitive.ObjectID, start time.Time, consumed []float64) *domain.MealPlan {
+	plan := newReportTestPlan(userID, start, len(consumed))
+	for i, calories := range consumed {
+		plan.DailyMeals[i].Meals = []domain.Meal{{ID: "m", Calories: calories, IsCompleted: calories > 0}}
+	}
+	return plan
+}
+
+func TestReportService_AdherenceStreak(t *testing.T) {
+	user := &domain.User{ID: primitive.NewObjectID(), Preferences: domain.UserPreferences{CalorieTarget: 2000}}
+	start := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		consumed []float64 // Mar 1 to Mar 10, today
+		current  int
+		longest  int
+	}{
+		// 4 on target, over, unlogged, 3 on target including both band edges, today still short
+		{"today in progress", []float64{2000, 1900, 2100, 1850, 2500, 0, 2000, 1800, 2200, 500}, 3, 4},
+		{"today on target", []float64{2000, 1900, 2100, 1850, 2500, 0, 2000, 1800, 2200, 2050}, 4, 4},
+		{"gap yesterday", []float64{2000, 1900, 2100, 1850, 2000, 2000, 2000, 2000, 0, 2000}, 1, 8},
+		{"nothing on target", []float64{1000, 3000, 0, 0, 0, 0, 0, 0, 0, 0}, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plans := newFakeMealPlanRepo(newStreakTestPlan(user.ID, start, tt.consumed))
+			svc := NewReportService(plans, newFakeUserRepo(user), config.DailyReferenceValues{}, config.ReportConfig{StreakTolerancePercent: 10}, logger.NewNoopLogger())
+			svc.now = func() time.Time { return time.Date(2025, 3, 10, 18, 0, 0, 0, time.UTC) }
+
+			streak, err := svc.AdherenceStreak(context.Background(), user.ID.Hex())
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			if streak.CurrentStreak != tt.current || streak.LongestStreak != tt.longest {
+				t.Errorf("Expected current %d and longest %d, got: %d and %d", tt.current, tt.longest, streak.CurrentStreak, streak.LongestStreak)
+			}
+			if streak.TargetCalories != 2000 || streak.TolerancePercent != 10 {
+				t.Errorf("Expected a 2000 kcal target within 10%%, got: %v within %v%%", streak.TargetCalories, streak.TolerancePercent)
+			}
+		})
+	}
+}
+
+func TestReportService_AdherenceStreak_RequiresTarget(t *testing.T) {
+	user := &domain.User{ID: primitive.NewObjectID()}
+	svc := NewReportService(newFakeMealPlanRepo(), newFakeUserRepo(user), config.DailyReferenceValues{}, config.ReportConfig{}, logger.NewNoopLogger())
+
+	if _, err := svc.AdherenceStreak(context.Background(), user.ID.Hex()); err == nil || !strings.HasPrefix(err.Error(), "invalid calorie target") {
+		t.Errorf("Expected an invalid calorie target error, got: %v", err)
+	}
+}
@@ -2,10 +2,16 @@ package service
 
 import (
 	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 
 	"nutrient_be/internal/domain"
+	"nutrient_be/internal/dto/request"
+	"nutrient_be/internal/pkg/calculator"
 	"nutrient_be/internal/pkg/logger"
 )
 
@@ -13,36 +19,611 @@ import (
 type ShoppingListRepository interface {
 	Create(ctx context.Context, list *domain.ShoppingList) error
 	GetByID(ctx context.Context, id primitive.ObjectID) (*domain.ShoppingList, error)
-	GetByUser(ctx context.Context, userID primitive.ObjectID, limit, offset int) ([]*domain.ShoppingList, error)
+	GetByUser(ctx context.Context, userID primitive.ObjectID, status string, limit, offset int) ([]*domain.ShoppingList, error)
 	GetByMealPlan(ctx context.Context, mealPlanID primitive.ObjectID) (*domain.ShoppingList, error)
 	Update(ctx context.Context, list *domain.ShoppingList) error
 	Delete(ctx context.Context, id primitive.ObjectID) error
 	ToggleItemChecked(ctx context.Context, listID primitive.ObjectID, itemID primitive.ObjectID, checked bool) error
+	ToggleItemsChecked(ctx context.Context, listID primitive.ObjectID, checked map[primitive.ObjectID]bool) error
 }
 
 // ShoppingMealPlanRepository defines the interface for meal plan data operations used by ShoppingService
 type ShoppingMealPlanRepository interface {
 	Create(ctx context.Context, plan *domain.MealPlan) error
 	GetByID(ctx context.Context, id primitive.ObjectID) (*domain.MealPlan, error)
-	GetByUser(ctx context.Context, userID primitive.ObjectID, planType string, limit, offset int) ([]*domain.MealPlan, error)
-	GetByUserAndDateRange(ctx context.Context, userID primitive.ObjectID, startDate, endDate string) ([]*domain.MealPlan, error)
+	GetByUser(ctx context.Context, userID primitive.ObjectID, planType, status string, limit, offset int) ([]*domain.MealPlan, error)
+	GetByUserAndDateRange(ctx context.Context, userID primitive.ObjectID, startDate, endDate time.Time) ([]*domain.MealPlan, error)
 	Update(ctx context.Context, plan *domain.MealPlan) error
 	Delete(ctx context.Context, id primitive.ObjectID) error
 	UpdateMealCompletion(ctx context.Context, planID primitive.ObjectID, mealID string, isCompleted bool) error
 }
 
+// ShoppingFoodRepository defines the interface for food data operations used by ShoppingService
+type ShoppingFoodRepository interface {
+	GetByID(ctx context.Context, id primitive.ObjectID) (*domain.FoodItem, error)
+}
+
 // ShoppingService handles shopping list business logic
 type ShoppingService struct {
 	shoppingRepo ShoppingListRepository
 	mealPlanRepo ShoppingMealPlanRepository
+	foodRepo     ShoppingFoodRepository
 	logger       logger.Logger
 }
 
 // NewShoppingService creates a new shopping service
-func NewShoppingService(shoppingRepo ShoppingListRepository, mealPlanRepo ShoppingMealPlanRepository, log logger.Logger) *ShoppingService {
+func NewShoppingService(shoppingRepo ShoppingListRepository, mealPlanRepo ShoppingMealPlanRepository, foodRepo ShoppingFoodRepository, log logger.Logger) *ShoppingService {
 	return &ShoppingService{
 		shoppingRepo: shoppingRepo,
 		mealPlanRepo: mealPlanRepo,
+		foodRepo:     foodRepo,
 		logger:       log,
 	}
 }
+
+// GenerateFromMealPlan builds the shopping list for a meal plan owned by the user
+// Quantities are summed in grams and shown in each food's preferred unit.
+// Regenerating keeps manually added items and the checked state of foods still in the plan.
+func (s *ShoppingService) GenerateFromMealPlan(ctx context.Context, userID string, mealPlanID string) (*domain.ShoppingList, error) {
+	s.logger.Info(ctx, "Generating shopping list", logger.String("meal_plan_id", mealPlanID))
+
+	userIDObj, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		s.logger.Error(ctx, "Invalid user ID", logger.Error(err))
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	planIDObj, err := primitive.ObjectIDFromHex(mealPlanID)
+	if err != nil {
+		s.logger.Error(ctx, "Invalid meal plan ID", logger.Error(err))
+		return nil, fmt.Errorf("invalid meal plan ID: %w", err)
+	}
+
+	plan, err := s.mealPlanRepo.GetByID(ctx, planIDObj)
+	if err != nil {
+		s.logger.Error(ctx, "Failed to get meal plan", logger.Error(err))
+		if err.Error() == "meal plan not found" {
+			return nil, fmt.Errorf("meal plan not found or access denied")
+		}
+		return nil, fmt.Errorf("failed to get meal plan: %w", err)
+	}
+	if plan.UserID != userIDObj {
+		s.logger.Error(ctx, "User does not own meal plan")
+		return nil, fmt.Errorf("meal plan not found or access denied")
+	}
+
+	// Load each referenced food once
+	foods := make(map[primitive.ObjectID]*domain.FoodItem)
+	for _, day := range plan.DailyMeals {
+		for _, meal := range day.Meals {
+			for _, item := range meal.FoodItems {
+				if _, ok := foods[item.FoodItemID]; ok {
+					continue
+				}
+				food, err := s.foodRepo.GetByID(ctx, item.FoodItemID)
+				if err != nil {
+					s.logger.Error(ctx, "Failed to get food item", logger.String("food_id", item.FoodItemID.Hex()), logger.Error(err))
+					return nil, fmt.Errorf("failed to get food item '%s': %w", item.FoodName, err)
+				}
+				foods[item.FoodItemID] = food
+			}
+		}
+	}
+
+	items, err := buildShoppingItems(plan, foods)
+	if err != nil {
+		s.logger.Error(ctx, "Failed to aggregate shopping items", logger.Error(err))
+		return nil, fmt.Errorf("failed to aggregate shopping items: %w", err)
+	}
+
+	list, err := s.shoppingRepo.GetByMealPlan(ctx, plan.ID)
+	if err != nil && err.Error() != "shopping list not found" {
+		s.logger.Error(ctx, "Failed to get existing shopping list", logger.Error(err))
+		return nil, fmt.Errorf("failed to get shopping list: %w", err)
+	}
+
+	if list == nil {
+		list = &domain.ShoppingList{
+			ID:         primitive.NewObjectID(),
+			UserID:     userIDObj,
+			MealPlanID: plan.ID,
+			Items:      items,
+		}
+		recalculateShoppingTotals(list)
+		updateShoppingStatus(list)
+		if err := s.shoppingRepo.Create(ctx, list); err != nil {
+			s.logger.Error(ctx, "Failed to create shopping list", logger.Error(err))
+			return nil, fmt.Errorf("failed to create shopping list: %w", err)
+		}
+		s.logger.Info(ctx, "Shopping list generated successfully", logger.Int("items", len(list.Items)))
+		return list, nil
+	}
+
+	list.Items = mergeRegeneratedItems(list.Items, items)
+	recalculateShoppingTotals(list)
+	updateShoppingStatus(list)
+	list.UpdatedAt = time.Now()
+	if err := s.shoppingRepo.Update(ctx, list); err != nil {
+		s.logger.Error(ctx, "Failed to update shopping list", logger.Error(err))
+		return nil, fmt.Errorf("failed to update shopping list: %w", err)
+	}
+
+	s.logger.Info(ctx, "Shopping list regenerated successfully", logger.Int("items", len(list.Items)))
+	return list, nil
+}
+
+// AddItem adds a manual item to a shopping list owned by the user
+func (s *ShoppingService) AddItem(ctx context.Context, userID string, listID string, req *request.AddShoppingItemRequest) (*domain.ShoppingList, error) {
+	s.logger.Info(ctx, "Adding shopping list item", logger.String("list_id", listID))
+
+	list, err := s.getOwnedList(ctx, userID, listID)
+	if err != nil {
+		return nil, err
+	}
+
+	item := domain.ShoppingItem{
+		ID:          primitive.NewObjectID(),
+		FoodName:    strings.TrimSpace(req.Name),
+		TotalAmount: req.Amount,
+		Unit:        req.Unit,
+		IsManual:    true,
+	}
+	if item.FoodName == "" {
+		return nil, fmt.Errorf("validation failed: name cannot be empty")
+	}
+	if req.FoodItemID != "" {
+		foodID, err := primitive.ObjectIDFromHex(req.FoodItemID)
+		if err != nil {
+			s.logger.Error(ctx, "Invalid food item ID", logger.Error(err))
+			return nil, fmt.Errorf("invalid food item ID: %w", err)
+		}
+		item.FoodItemID = &foodID
+	}
+	if req.Cost != nil {
+		item.Cost = *req.Cost
+	}
+
+	list.Items = append(list.Items, item)
+	recalculateShoppingTotals(list)
+	updateShoppingStatus(list)
+
+	list.UpdatedAt = time.Now()
+	if err := s.shoppingRepo.Update(ctx, list); err != nil {
+		s.logger.Error(ctx, "Failed to update shopping list", logger.Error(err))
+		return nil, fmt.Errorf("failed to update shopping list: %w", err)
+	}
+
+	s.logger.Info(ctx, "Shopping list item added successfully", logger.String("item_id", item.ID.Hex()))
+	return list, nil
+}
+
+// RemoveItem removes an item from a shopping list owned by the user
+func (s *ShoppingService) RemoveItem(ctx context.Context, userID string, listID string, itemID string) (*domain.ShoppingList, error) {
+	s.logger.Info(ctx, "Removing shopping list item", logger.String("list_id", listID), logger.String("item_id", itemID))
+
+	itemIDObj, err := primitive.ObjectIDFromHex(itemID)
+	if err != nil {
+		s.logger.Error(ctx, "Invalid item ID", logger.Error(err))
+		return nil, fmt.Errorf("invalid item ID: %w", err)
+	}
+
+	list, err := s.getOwnedList(ctx, userID, listID)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]domain.ShoppingItem, 0, len(list.Items))
+	for _, item := range list.Items {
+		if item.ID != itemIDObj {
+			items = append(items, item)
+		}
+	}
+	if len(items) == len(list.Items) {
+		s.logger.Error(ctx, "Item not found in shopping list")
+		return nil, fmt.Errorf("item not found in shopping list")
+	}
+
+	list.Items = items
+	recalculateShoppingTotals(list)
+	updateShoppingStatus(list)
+
+	list.UpdatedAt = time.Now()
+	if err := s.shoppingRepo.Update(ctx, list); err != nil {
+		s.logger.Error(ctx, "Failed to update shopping list", logger.Error(err))
+		return nil, fmt.Errorf("failed to update shopping list: %w", err)
+	}
+
+	s.logger.Info(ctx, "Shopping list item removed successfully")
+	return list, nil
+}
+
+// SyncWithMealPlan regenerates the shopping list of a meal plan if one was already generated
+// It returns a nil list without error when the plan has no shopping list, so repeated calls are safe
+func (s *ShoppingService) SyncWithMealPlan(ctx context.Context, userID string, mealPlanID string) (*domain.ShoppingList, error) {
+	planIDObj, err := primitive.ObjectIDFromHex(mealPlanID)
+	if err != nil {
+		s.logger.Error(ctx, "Invalid meal plan ID", logger.Error(err))
+		return nil, fmt.Errorf("invalid meal plan ID: %w", err)
+	}
+
+	if _, err := s.shoppingRepo.GetByMealPlan(ctx, planIDObj); err != nil {
+		if err.Error() == "shopping list not found" {
+			s.logger.Debug(ctx, "No shopping list to sync", logger.String("meal_plan_id", mealPlanID))
+			return nil, nil
+		}
+		s.logger.Error(ctx, "Failed to get existing shopping list", logger.Error(err))
+		return nil, fmt.Errorf("failed to get shopping list: %w", err)
+	}
+
+	return s.GenerateFromMealPlan(ctx, userID, mealPlanID)
+}
+
+// ToggleItem sets the checked state of an item in a shopping list owned by the user
+func (s *ShoppingService) ToggleItem(ctx context.Context, userID string, listID string, itemID string, checked bool) (*domain.ShoppingList, error) {
+	s.logger.Info(ctx, "Toggling shopping list item",
+		logger.String("list_id", listID),
+		logger.String("item_id", itemID),
+		logger.Bool("checked", checked))
+
+	itemIDObj, err := primitive.ObjectIDFromHex(itemID)
+	if err != nil {
+		s.logger.Error(ctx, "Invalid item ID", logger.Error(err))
+		return nil, fmt.Errorf("invalid item ID: %w", err)
+	}
+
+	list, err := s.getOwnedList(ctx, userID, listID)
+	if err != nil {
+		return nil, err
+	}
+
+	found := false
+	for _, item := range list.Items {
+		if item.ID == itemIDObj {
+			found = true
+			break
+		}
+	}
+	if !found {
+		s.logger.Error(ctx, "Item not found in shopping list")
+		return nil, fmt.Errorf("item not found in shopping list")
+	}
+
+	if err := s.shoppingRepo.ToggleItemChecked(ctx, list.ID, itemIDObj, checked); err != nil {
+		s.logger.Error(ctx, "Failed to toggle shopping item", logger.Error(err))
+		return nil, fmt.Errorf("failed to toggle shopping item: %w", err)
+	}
+
+	// Reload so the response reflects the stored state
+	updated, err := s.shoppingRepo.GetByID(ctx, list.ID)
+	if err != nil {
+		s.logger.Error(ctx, "Failed to reload shopping list", logger.Error(err))
+		return nil, fmt.Errorf("failed to get shopping list: %w", err)
+	}
+
+	// Persist a status transition when the last item was checked or an item was unchecked
+	if updateShoppingStatus(updated) {
+		updated.UpdatedAt = time.Now()
+		if err := s.shoppingRepo.Update(ctx, updated); err != nil {
+			s.logger.Error(ctx, "Failed to update shopping list status", logger.Error(err))
+			return nil, fmt.Errorf("failed to update shopping list: %w", err)
+		}
+		s.logger.Info(ctx, "Shopping list status changed", logger.String("status", updated.Status))
+	}
+
+	s.logger.Info(ctx, "Shopping list item toggled successfully")
+	return updated, nil
+}
+
+// ToggleItems sets the checked state of several items of a shopping list in a single update
+// Ownership is verified once, and the list completion status is recomputed afterwards
+func (s *ShoppingService) ToggleItems(ctx context.Context, userID string, listID string, toggles []request.ShoppingItemToggle) (*domain.ShoppingList, error) {
+	s.logger.Info(ctx, "Toggling shopping list items",
+		logger.String("list_id", listID),
+		logger.Int("item_count", len(toggles)))
+
+	checked := make(map[primitive.ObjectID]bool, len(toggles))
+	for _, toggle := range toggles {
+		itemIDObj, err := primitive.ObjectIDFromHex(toggle.ItemID)
+		if err != nil {
+			s.logger.Error(ctx, "Invalid item ID", logger.Error(err))
+			return nil, fmt.Errorf("invalid item ID: %w", err)
+		}
+		if _, exists := checked[itemIDObj]; exists {
+			return nil, fmt.Errorf("validation failed: item %s is listed more than once", toggle.ItemID)
+		}
+		checked[itemIDObj] = toggle.Checked != nil && *toggle.Checked
+	}
+
+	list, err := s.getOwnedList(ctx, userID, listID)
+	if err != nil {
+		return nil, err
+	}
+
+	existing := make(map[primitive.ObjectID]bool, len(list.Items))
+	for _, item := range list.Items {
+		existing[item.ID] = true
+	}
+	for itemIDObj := range checked {
+		if !existing[itemIDObj] {
+			s.logger.Error(ctx, "Item not found in shopping list", logger.String("item_id", itemIDObj.Hex()))
+			return nil, fmt.Errorf("item not found in shopping list")
+		}
+	}
+
+	if err := s.shoppingRepo.ToggleItemsChecked(ctx, list.ID, checked); err != nil {
+		s.logger.Error(ctx, "Failed to toggle shopping items", logger.Error(err))
+		return nil, fmt.Errorf("failed to toggle shopping items: %w", err)
+	}
+
+	// Reload so the response reflects the stored state
+	updated, err := s.shoppingRepo.GetByID(ctx, list.ID)
+	if err != nil {
+		s.logger.Error(ctx, "Failed to reload shopping list", logger.Error(err))
+		return nil, fmt.Errorf("failed to get shopping list: %w", err)
+	}
+
+	if updateShoppingStatus(updated) {
+		updated.UpdatedAt = time.Now()
+		if err := s.shoppingRepo.Update(ctx, updated); err != nil {
+			s.logger.Error(ctx, "Failed to update shopping list status", logger.Error(err))
+			return nil, fmt.Errorf("failed to update shopping list: %w", err)
+		}
+		s.logger.Info(ctx, "Shopping list status changed", logger.String("status", updated.Status))
+	}
+
+	s.logger.Info(ctx, "Shopping list items toggled successfully")
+	return updated, nil
+}
+
+// ListLists lists a user's shopping lists, optionally filtered by status
+func (s *ShoppingService) ListLists(ctx context.Context, userID string, status string, limit, offset int) ([]*domain.ShoppingList, error) {
+	s.logger.Info(ctx, "Listing shopping lists", logger.String("status", status))
+
+	if status != "" && status != domain.ShoppingListStatusActive && status != domain.ShoppingListStatusCompleted {
+		return nil, fmt.Errorf("validation failed: invalid status '%s'. Valid statuses: active, completed", status)
+	}
+
+	userIDObj, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		s.logger.Error(ctx, "Invalid user ID", logger.Error(err))
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	lists, err := s.shoppingRepo.GetByUser(ctx, userIDObj, status, limit, offset)
+	if err != nil {
+		s.logger.Error(ctx, "Failed to list shopping lists", logger.Error(err))
+		return nil, fmt.Errorf("failed to list shopping lists: %w", err)
+	}
+
+	s.logger.Info(ctx, "Shopping lists listed successfully", logger.Int("count", len(lists)))
+	return lists, nil
+}
+
+// defaultMergedListName names merged lists created without a name
+const defaultMergedListName = "Merged shopping list"
+
+// MergeLists creates a new shopping list combining the items of several lists owned by the user
+// Items of the same food in the same unit become one item with the amounts, grams and costs summed, checked only
+// when every merged item was. Other items, such as free-text ones, are copied as they are. The source lists are not changed.
+func (s *ShoppingService) MergeLists(ctx context.Context, userID string, listIDs []string, name string) (*domain.ShoppingList, error) {
+	s.logger.Info(ctx, "Merging shopping lists", logger.Int("list_count", len(listIDs)))
+
+	userIDObj, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		s.logger.Error(ctx, "Invalid user ID", logger.Error(err))
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	var sources []*domain.ShoppingList
+	seen := make(map[string]bool, len(listIDs))
+	for _, listID := range listIDs {
+		key := strings.ToLower(listID)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		list, err := s.getOwnedList(ctx, userID, listID)
+		if err != nil {
+			return nil, err
+		}
+		sources = append(sources, list)
+	}
+	if len(sources) < 2 {
+		return nil, fmt.Errorf("validation failed: at least two different shopping lists are required")
+	}
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = defaultMergedListName
+	}
+
+	merged := &domain.ShoppingList{
+		ID:     primitive.NewObjectID(),
+		UserID: userIDObj,
+		Name:   name,
+		Items:  mergeShoppingItems(sources),
+	}
+	for _, list := range sources {
+		merged.SourceListIDs = append(merged.SourceListIDs, list.ID)
+	}
+	recalculateShoppingTotals(merged)
+	updateShoppingStatus(merged)
+
+	if err := s.shoppingRepo.Create(ctx, merged); err != nil {
+		s.logger.Error(ctx, "Failed to create shopping list", logger.Error(err))
+		return nil, fmt.Errorf("failed to create shopping list: %w", err)
+	}
+
+	s.logger.Info(ctx, "Shopping lists merged successfully",
+		logger.String("list_id", merged.ID.Hex()),
+		logger.Int("items", len(merged.Items)))
+	return merged, nil
+}
+
+// getOwnedList loads a shopping list and verifies that it belongs to the user
+func (s *ShoppingService) getOwnedList(ctx context.Context, userID string, listID string) (*domain.ShoppingList, error) {
+	userIDObj, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		s.logger.Error(ctx, "Invalid user ID", logger.Error(err))
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	listIDObj, err := primitive.ObjectIDFromHex(listID)
+	if err != nil {
+		s.logger.Error(ctx, "Invalid shopping list ID", logger.Error(err))
+		return nil, fmt.Errorf("invalid shopping list ID: %w", err)
+	}
+
+	list, err := s.shoppingRepo.GetByID(ctx, listIDObj)
+	if err != nil {
+		s.logger.Error(ctx, "Failed to get shopping list", logger.Error(err))
+		if err.Error() == "shopping list not found" {
+			return nil, fmt.Errorf("shopping list not found or access denied")
+		}
+		return nil, fmt.Errorf("failed to get shopping list: %w", err)
+	}
+
+	if list.UserID != userIDObj {
+		s.logger.Error(ctx, "User does not own shopping list")
+		return nil, fmt.Errorf("shopping list not found or access denied")
+	}
+
+	return list, nil
+}
+
+// recalculateShoppingTotals recomputes the list totals from its items
+func recalculateShoppingTotals(list *domain.ShoppingList) {
+	var totalCost float64
+	for _, item := range list.Items {
+		totalCost += item.Cost
+	}
+	list.TotalCost = totalCost
+}
+
+// updateShoppingStatus sets the list status from its items and reports whether it changed
+// A list is completed once every item is checked; empty lists stay active
+func updateShoppingStatus(list *domain.ShoppingList) bool {
+	status := domain.ShoppingListStatusActive
+	if len(list.Items) > 0 {
+		status = domain.ShoppingListStatusCompleted
+		for _, item := range list.Items {
+			if !item.Checked {
+				status = domain.ShoppingListStatusActive
+				break
+			}
+		}
+	}
+
+	changed := list.Status != status
+	list.Status = status
+	return changed
+}
+
+// buildShoppingItems sums the food items of a plan per food
+// Grams are the canonical amount; the display amount uses the food's preferred serving unit.
+func buildShoppingItems(plan *domain.MealPlan, foods map[primitive.ObjectID]*domain.FoodItem) ([]domain.ShoppingItem, error) {
+	var order []primitive.ObjectID
+	grams := make(map[primitive.ObjectID]float64)
+	names := make(map[primitive.ObjectID]string)
+
+	for _, day := range plan.DailyMeals {
+		for _, meal := range day.Meals {
+			for _, item := range meal.FoodItems {
+				food, ok := foods[item.FoodItemID]
+				if !ok {
+					return nil, fmt.Errorf("food item '%s' not loaded", item.FoodItemID.Hex())
+				}
+				itemGrams, err := calculator.ConvertToGrams(food, item.ServingUnit, item.Amount)
+				if err != nil {
+					return nil, err
+				}
+				if _, seen := grams[item.FoodItemID]; !seen {
+					order = append(order, item.FoodItemID)
+					names[item.FoodItemID] = item.FoodName
+				}
+				grams[item.FoodItemID] += itemGrams
+			}
+		}
+	}
+
+	items := make([]domain.ShoppingItem, 0, len(order))
+	for _, foodID := range order {
+		totalGrams := math.Round(grams[foodID]*100) / 100
+
+		amount, unit := totalGrams, "g"
+		if serving := calculator.PreferredDisplayServing(foods[foodID]); serving != nil {
+			amount, unit = calculator.ConvertFromGrams(serving, totalGrams), serving.Unit
+		}
+
+		items = append(items, domain.ShoppingItem{
+			ID:          primitive.NewObjectID(),
+			FoodItemID:  &foodID,
+			FoodName:    names[foodID],
+			TotalAmount: amount,
+			Unit:        unit,
+			TotalGrams:  totalGrams,
+			Display:     calculator.FormatQuantity(amount, unit),
+		})
+	}
+
+	return items, nil
+}
+
+// mergeShoppingItems combines the items of lists, summing items of the same food and unit in first-seen order
+// Every item gets a new ID so the merged list does not share item IDs with its sources
+func mergeShoppingItems(lists []*domain.ShoppingList) []domain.ShoppingItem {
+	var items []domain.ShoppingItem
+	index := make(map[string]int)
+	for _, list := range lists {
+		for _, item := range list.Items {
+			item.ID = primitive.NewObjectID()
+			if item.FoodItemID == nil {
+				items = append(items, item)
+				continue
+			}
+
+			key := item.FoodItemID.Hex() + ":" + item.Unit
+			i, ok := index[key]
+			if !ok {
+				index[key] = len(items)
+				items = append(items, item)
+				continue
+			}
+
+			existing := &items[i]
+			existing.TotalAmount += item.TotalAmount
+			existing.TotalGrams = math.Round((existing.TotalGrams+item.TotalGrams)*100) / 100
+			existing.Cost += item.Cost
+			existing.Checked = existing.Checked && item.Checked
+			existing.IsManual = existing.IsManual && item.IsManual
+			existing.Display = calculator.FormatQuantity(existing.TotalAmount, existing.Unit)
+		}
+	}
+	return items
+}
+
+// mergeRegeneratedItems replaces the generated items of a list with freshly built ones
+// Manual items are kept, and generated items keep their ID and checked state when their food is still present.
+func mergeRegeneratedItems(existing, generated []domain.ShoppingItem) []domain.ShoppingItem {
+	previous := make(map[primitive.ObjectID]domain.ShoppingItem)
+	var manual []domain.ShoppingItem
+	for _, item := range existing {
+		if item.IsManual || item.FoodItemID == nil {
+			manual = append(manual, item)
+			continue
+		}
+		previous[*item.FoodItemID] = item
+	}
+
+	merged := make([]domain.ShoppingItem, 0, len(generated)+len(manual))
+	for _, item := range generated {
+		if old, ok := previous[*item.FoodItemID]; ok {
+			item.ID = old.ID
+			item.Checked = old.Checked
+			item.Cost = old.Cost
+		}
+		merged = append(merged, item)
+	}
+	return append(merged, manual...)
+}
@@ -0,0 +1,409 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"nutrient_be/internal/domain"
+	"nutrient_be/internal/dto/request"
+	"nutrient_be/internal/pkg/logger"
+)
+
+// newTestShoppingList builds a list with one generated item owned by userID
+func newTestShoppingList(userID primitive.ObjectID) *domain.ShoppingList {
+	foodID := primitive.NewObjectID()
+	return &domain.ShoppingList{
+		ID:         primitive.NewObjectID(),
+		UserID:     userID,
+		MealPlanID: primitive.NewObjectID(),
+		Status:     domain.ShoppingListStatusActive,
+		Items: []domain.ShoppingItem{
+			{ID: primitive.NewObjectID(), FoodItemID: &foodID, FoodName: "Oats", TotalAmount: 500, Unit: "g", Cost: 3},
+		},
+		TotalCost: 3,
+	}
+}
+
+func TestShoppingService_AddItem_FreeText(t *testing.T) {
+	userID := primitive.NewObjectID()
+	list := newTestShoppingList(userID)
+	repo := newFakeShoppingListRepo(list)
+	svc := NewShoppingService(repo, newFakeMealPlanRepo(), newFakeFoodRepo(), logger.NewNoopLogger())
+
+	cost := 2.5
+	updated, err := svc.AddItem(context.Background(), userID.Hex(), list.ID.Hex(), &request.AddShoppingItemRequest{Name: "Paper towels", Cost: &cost})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(updated.Items) != 2 {
+		t.Fatalf("Expected 2 items, got %d", len(updated.Items))
+	}
+	added := updated.Items[1]
+	if added.FoodName != "Paper towels" || added.FoodItemID != nil || !added.IsManual || added.ID.IsZero() {
+		t.Errorf("Unexpected added item: %+v", added)
+	}
+	if updated.TotalCost != 5.5 {
+		t.Errorf("Expected total cost 5.5, got %v", updated.TotalCost)
+	}
+}
+
+func TestShoppingService_RemoveItem_Generated(t *testing.T) {
+	userID := primitive.NewObjectID()
+	list := newTestShoppingList(userID)
+	repo := newFakeShoppingListRepo(list)
+	svc := NewShoppingService(repo, newFakeMealPlanRepo(), newFakeFoodRepo(), logger.NewNoopLogger())
+
+	updated, err := svc.RemoveItem(context.Background(), userID.Hex(), list.ID.Hex(), list.Items[0].ID.Hex())
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(updated.Items) != 0 || updated.TotalCost != 0 {
+		t.Errorf("Expected empty list with zero cost, got %d items / %v", len(updated.Items), updated.TotalCost)
+	}
+
+	if _, err := svc.RemoveItem(context.Background(), userID.Hex(), list.ID.Hex(), primitive.NewObjectID().Hex()); err == nil || err.Error() != "item not found in shopping list" {
+		t.Errorf("Expected item not found error, got: %v", err)
+	}
+}
+
+func TestShoppingService_AddItem_NotOwner(t *testing.T) {
+	list := newTestShoppingList(primitive.NewObjectID())
+	svc := NewShoppingService(newFakeShoppingListRepo(list), newFakeMealPlanRepo(), newFakeFoodRepo(), logger.NewNoopLogger())
+
+	_, err := svc.AddItem(context.Background(), primitive.NewObjectID().Hex(), list.ID.Hex(), &request.AddShoppingItemRequest{Name: "Milk"})
+	if err == nil || err.Error() != "shopping list not found or access denied" {
+		t.Errorf("Expected access denied error, got: %v", err)
+	}
+}
+
+func TestShoppingService_ToggleItem(t *testing.T) {
+	userID := primitive.NewObjectID()
+	list := newTestShoppingList(userID)
+	repo := newFakeShoppingListRepo(list)
+	svc := NewShoppingService(repo, newFakeMealPlanRepo(), newFakeFoodRepo(), logger.NewNoopLogger())
+	itemID := list.Items[0].ID.Hex()
+
+	updated, err := svc.ToggleItem(context.Background(), userID.Hex(), list.ID.Hex(), itemID, true)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if !updated.Items[0].Checked {
+		t.Error("Expected item to be checked")
+	}
+
+	updated, err = svc.ToggleItem(context.Background(), userID.Hex(), list.ID.Hex(), itemID, false)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if updated.Items[0].Checked {
+		t.Error("Expected item to be unchecked")
+	}
+}
+
+func TestShoppingService_ToggleItem_NotOwner(t *testing.T) {
+	list := newTestShoppingList(primitive.NewObjectID())
+	repo := newFakeShoppingListRepo(list)
+	svc := NewShoppingService(repo, newFakeMealPlanRepo(), newFakeFoodRepo(), logger.NewNoopLogger())
+
+	_, err := svc.ToggleItem(context.Background(), primitive.NewObjectID().Hex(), list.ID.Hex(), list.Items[0].ID.Hex(), true)
+	if err == nil || err.Error() != "shopping list not found or access denied" {
+		t.Errorf("Expected access denied error, got: %v", err)
+	}
+	if repo.lists[list.ID].Items[0].Checked {
+		t.Error("Expected item to stay unchecked")
+	}
+}
+
+func TestShoppingService_ToggleItem_CompletesList(t *testing.T) {
+	userID := primitive.NewObjectID()
+	list := newTestShoppingList(userID)
+	list.Items = append(list.Items, domain.ShoppingItem{ID: primitive.NewObjectID(), FoodName: "Milk", Checked: true})
+	repo := newFakeShoppingListRepo(list)
+	svc := NewShoppingService(repo, newFakeMealPlanRepo(), newFakeFoodRepo(), logger.NewNoopLogger())
+	lastItemID := list.Items[0].ID.Hex()
+
+	updated, err := svc.ToggleItem(context.Background(), userID.Hex(), list.ID.Hex(), lastItemID, true)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if updated.Status != domain.ShoppingListStatusCompleted {
+		t.Errorf("Expected list to be completed, got %q", updated.Status)
+	}
+	if repo.lists[list.ID].Status != domain.ShoppingListStatusCompleted {
+		t.Error("Expected completed status to be persisted")
+	}
+
+	updated, err = svc.ToggleItem(context.Background(), userID.Hex(), list.ID.Hex(), lastItemID, false)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if updated.Status != domain.ShoppingListStatusActive {
+		t.Errorf("Expected list to be active again, got %q", updated.Status)
+	}
+
+	// Adding an item to a completed list reopens it
+	repo.lists[list.ID].Items[0].Checked = true
+	repo.lists[list.ID].Status = domain.ShoppingListStatusCompleted
+	updated, err = svc.AddItem(context.Background(), userID.Hex(), list.ID.Hex(), &request.AddShoppingItemRequest{Name: "Bread"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if updated.Status != domain.ShoppingListStatusActive {
+		t.Errorf("Expected list to be active after adding an item, got %q", updated.Status)
+	}
+}
+
+func TestShoppingService_ToggleItems_MixedCheckAndUncheck(t *testing.T) {
+	userID := primitive.NewObjectID()
+	list := newTestShoppingList(userID)
+	list.Items = append(list.Items,
+		domain.ShoppingItem{ID: primitive.NewObjectID(), FoodName: "Milk", Checked: true},
+		domain.ShoppingItem{ID: primitive.NewObjectID(), FoodName: "Eggs"},
+	)
+	repo := newFakeShoppingListRepo(list)
+	svc := NewShoppingService(repo, newFakeMealPlanRepo(), newFakeFoodRepo(), logger.NewNoopLogger())
+	checked, unchecked := true, false
+
+	updated, err := svc.ToggleItems(context.Background(), userID.Hex(), list.ID.Hex(), []request.ShoppingItemToggle{
+		{ItemID: list.Items[0].ID.Hex(), Checked: &checked},
+		{ItemID: list.Items[1].ID.Hex(), Checked: &unchecked},
+		{ItemID: list.Items[2].ID.Hex(), Checked: &checked},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if !updated.Items[0].Checked || updated.Items[1].Checked || !updated.Items[2].Checked {
+		t.Errorf("Unexpected checked states: %v, %v, %v", updated.Items[0].Checked, updated.Items[1].Checked, updated.Items[2].Checked)
+	}
+	if updated.Status != domain.ShoppingListStatusActive {
+		t.Errorf("Expected list to stay active, got %q", updated.Status)
+	}
+
+	updated, err = svc.ToggleItems(context.Background(), userID.Hex(), list.ID.Hex(), []request.ShoppingItemToggle{
+		{ItemID: list.Items[1].ID.Hex(), Checked: &checked},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if updated.Status != domain.ShoppingListStatusCompleted {
+		t.Errorf("Expected list to be completed, got %q", updated.Status)
+	}
+	if repo.lists[list.ID].Status != domain.ShoppingListStatusCompleted {
+		t.Error("Expected completed status to be persisted")
+	}
+}
+
+func TestShoppingService_ToggleItems_RejectsUnknownItem(t *testing.T) {
+	userID := primitive.NewObjectID()
+	list := newTestShoppingList(userID)
+	repo := newFakeShoppingListRepo(list)
+	svc := NewShoppingService(repo, newFakeMealPlanRepo(), newFakeFoodRepo(), logger.NewNoopLogger())
+	checked := true
+
+	_, err := svc.ToggleItems(context.Background(), userID.Hex(), list.ID.Hex(), []request.ShoppingItemToggle{
+		{ItemID: list.Items[0].ID.Hex(), Checked: &checked},
+		{ItemID: primitive.NewObjectID().Hex(), Checked: &checked},
+	})
+	if err == nil || err.Error() != "item not found in shopping list" {
+		t.Errorf("Expected item not found error, got: %v", err)
+	}
+	if repo.lists[list.ID].Items[0].Checked {
+		t.Error("Expected no item to be toggled")
+	}
+}
+
+func TestShoppingService_ListLists_FilterByStatus(t *testing.T) {
+	userID := primitive.NewObjectID()
+	active := newTestShoppingList(userID)
+	completed := newTestShoppingList(userID)
+	completed.Status = domain.ShoppingListStatusCompleted
+	svc := NewShoppingService(newFakeShoppingListRepo(active, completed), newFakeMealPlanRepo(), newFakeFoodRepo(), logger.NewNoopLogger())
+
+	lists, err := svc.ListLists(context.Background(), userID.Hex(), domain.ShoppingListStatusCompleted, 20, 0)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(lists) != 1 || lists[0].ID != completed.ID {
+		t.Errorf("Expected only the completed list, got %d lists", len(lists))
+	}
+
+	if _, err := svc.ListLists(context.Background(), userID.Hex(), "pending", 20, 0); err == nil {
+		t.Error("Expected invalid status to be rejected")
+	}
+}
+
+func TestShoppingService_ListLists_Paginates(t *testing.T) {
+	userID := primitive.NewObjectID()
+	repo := newFakeShoppingListRepo(newTestShoppingList(userID), newTestShoppingList(userID), newTestShoppingList(userID), newTestShoppingList(primitive.NewObjectID()))
+	svc := NewShoppingService(repo, newFakeMealPlanRepo(), newFakeFoodRepo(), logger.NewNoopLogger())
+
+	first, err := svc.ListLists(context.Background(), userID.Hex(), "", 2, 0)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	second, err := svc.ListLists(context.Background(), userID.Hex(), "", 2, 2)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(first) != 2 || len(second) != 1 {
+		t.Fatalf("Expected pages of 2 and 1 lists, got: %d and %d", len(first), len(second))
+	}
+	for _, list := range first {
+		if list.ID == second[0].ID {
+			t.Error("Expected pages not to overlap")
+		}
+	}
+}
+
+func TestShoppingService_GenerateFromMealPlan_UnitAware(t *testing.T) {
+	userID := primitive.NewObjectID()
+	egg := &domain.FoodItem{
+		ID:   primitive.NewObjectID(),
+		Name: map[string]string{"en": "Egg"},
+		ServingSizes: []domain.ServingSize{
+			{Unit: "gram", Amount: 100, GramEquivalent: 100},
+			{Unit: "piece", Amount: 1, GramEquivalent: 50},
+		},
+	}
+	rice := &domain.FoodItem{
+		ID:           primitive.NewObjectID(),
+		Name:         map[string]string{"en": "Rice"},
+		ServingSizes: []domain.ServingSize{{Unit: "gram", Amount: 100, GramEquivalent: 100}},
+	}
+
+	plan := newTestPlan(userID)
+	plan.DailyMeals[0].Meals[0].FoodItems = []domain.MealFoodItem{
+		{FoodItemID: egg.ID, FoodName: "Egg", ServingUnit: "piece", Amount: 2},
+		{FoodItemID: rice.ID, FoodName: "Rice", ServingUnit: "gram", Amount: 150},
+	}
+	plan.DailyMeals[1].Meals[0].FoodItems = []domain.MealFoodItem{
+		{FoodItemID: egg.ID, FoodName: "Egg", ServingUnit: "gram", Amount: 50},
+	}
+
+	shopping := newFakeShoppingListRepo()
+	svc := NewShoppingService(shopping, newFakeMealPlanRepo(plan), newFakeFoodRepo(egg, rice), logger.NewNoopLogger())
+
+	list, err := svc.GenerateFromMealPlan(context.Background(), userID.Hex(), plan.ID.Hex())
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(list.Items) != 2 {
+		t.Fatalf("Expected 2 aggregated items, got %d", len(list.Items))
+	}
+
+	eggs := list.Items[0]
+	if eggs.TotalGrams != 150 || eggs.TotalAmount != 3 || eggs.Unit != "piece" || eggs.Display != "3 pieces" {
+		t.Errorf("Unexpected egg item: %+v", eggs)
+	}
+	riceItem := list.Items[1]
+	if riceItem.TotalGrams != 150 || riceItem.Unit != "g" || riceItem.Display != "150 g" {
+		t.Errorf("Unexpected rice item: %+v", riceItem)
+	}
+	if list.Status != domain.ShoppingListStatusActive {
+		t.Errorf("Expected new list to be active, got %q", list.Status)
+	}
+	if list.ID.IsZero() {
+		t.Fatal("Expected the new list to be returned with its ID")
+	}
+	if stored, ok := shopping.lists[list.ID]; !ok || stored.MealPlanID != plan.ID {
+		t.Errorf("Expected the list to be stored under the returned ID")
+	}
+}
+
+func TestShoppingService_GenerateFromMealPlan_NotOwner(t *testing.T) {
+	plan := newTestPlan(primitive.NewObjectID())
+	svc := NewShoppingService(newFakeShoppingListRepo(), newFakeMealPlanRepo(plan), newFakeFoodRepo(), logger.NewNoopLogger())
+
+	_, err := svc.GenerateFromMealPlan(context.Background(), primitive.NewObjectID().Hex(), plan.ID.Hex())
+	if err == nil || err.Error() != "meal plan not found or access denied" {
+		t.Errorf("Expected access denied error, got: %v", err)
+	}
+}
+
+func TestShoppingService_MergeLists_SumsOverlappingFood(t *testing.T) {
+	userID := primitive.NewObjectID()
+	oatsID, milkID := primitive.NewObjectID(), primitive.NewObjectID()
+	first := &domain.ShoppingList{
+		ID: primitive.NewObjectID(), UserID: userID, MealPlanID: primitive.NewObjectID(), Status: domain.ShoppingListStatusActive,
+		Items: []domain.ShoppingItem{
+			{ID: primitive.NewObjectID(), FoodItemID: &oatsID, FoodName: "Oats", TotalAmount: 500, Unit: "g", TotalGrams: 500, Cost: 3, Checked: true},
+			{ID: primitive.NewObjectID(), FoodName: "Paper towels", TotalAmount: 1, Unit: "piece", Cost: 2, IsManual: true},
+		},
+	}
+	second := &domain.ShoppingList{
+		ID: primitive.NewObjectID(), UserID: userID, MealPlanID: primitive.NewObjectID(), Status: domain.ShoppingListStatusActive,
+		Items: []domain.ShoppingItem{
+			{ID: primitive.NewObjectID(), FoodItemID: &oatsID, FoodName: "Oats", TotalAmount: 250, Unit: "g", TotalGrams: 250, Cost: 1.5},
+			{ID: primitive.NewObjectID(), FoodItemID: &milkID, FoodName: "Milk", TotalAmount: 1, Unit: "l", Cost: 1, Checked: true},
+		},
+	}
+	repo := newFakeShoppingListRepo(first, second)
+	svc := NewShoppingService(repo, newFakeMealPlanRepo(), newFakeFoodRepo(), logger.NewNoopLogger())
+
+	merged, err := svc.MergeLists(context.Background(), userID.Hex(), []string{first.ID.Hex(), second.ID.Hex()}, "")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(merged.Items) != 3 {
+		t.Fatalf("Expected 3 items, got %d", len(merged.Items))
+	}
+	oats := merged.Items[0]
+	if oats.TotalAmount != 750 || oats.TotalGrams != 750 || oats.Cost != 4.5 {
+		t.Errorf("Expected oats summed to 750g costing 4.5, got: %+v", oats)
+	}
+	if oats.Checked {
+		t.Error("Expected oats unchecked since only one source item was checked")
+	}
+	if !merged.Items[2].Checked {
+		t.Error("Expected milk to stay checked")
+	}
+	if merged.TotalCost != 7.5 {
+		t.Errorf("Expected total cost 7.5, got %v", merged.TotalCost)
+	}
+	if merged.Name != defaultMergedListName || !merged.MealPlanID.IsZero() || len(merged.SourceListIDs) != 2 {
+		t.Errorf("Unexpected merged list: %+v", merged)
+	}
+	if _, ok := repo.lists[merged.ID]; !ok {
+		t.Error("Expected merged list to be stored")
+	}
+	if merged.Items[0].ID == first.Items[0].ID {
+		t.Error("Expected merged items to get new IDs")
+	}
+
+	if got := repo.lists[first.ID].Items[0]; got.TotalAmount != 500 || !got.Checked {
+		t.Errorf("Expected source list to be unchanged, got: %+v", got)
+	}
+	if got := repo.lists[second.ID].Items[0]; got.TotalAmount != 250 {
+		t.Errorf("Expected source list to be unchanged, got: %+v", got)
+	}
+}
+
+func TestShoppingService_MergeLists_NotOwner(t *testing.T) {
+	userID := primitive.NewObjectID()
+	own := newTestShoppingList(userID)
+	other := newTestShoppingList(primitive.NewObjectID())
+	repo := newFakeShoppingListRepo(own, other)
+	svc := NewShoppingService(repo, newFakeMealPlanRepo(), newFakeFoodRepo(), logger.NewNoopLogger())
+
+	_, err := svc.MergeLists(context.Background(), userID.Hex(), []string{own.ID.Hex(), other.ID.Hex()}, "Week")
+	if err == nil || err.Error() != "shopping list not found or access denied" {
+		t.Errorf("Expected access denied error, got: %v", err)
+	}
+	if len(repo.lists) != 2 {
+		t.Errorf("Expected no list to be created, got %d lists", len(repo.lists))
+	}
+}
+
+func TestShoppingService_MergeLists_RequiresTwoLists(t *testing.T) {
+	userID := primitive.NewObjectID()
+	list := newTestShoppingList(userID)
+	svc := NewShoppingService(newFakeShoppingListRepo(list), newFakeMealPlanRepo(), newFakeFoodRepo(), logger.NewNoopLogger())
+
+	_, err := svc.MergeLists(context.Background(), userID.Hex(), []string{list.ID.Hex(), list.ID.Hex()}, "")
+	if err == nil || !strings.HasPrefix(err.Error(), "validation failed:") {
+		t.Errorf("Expected validation error, got: %v", err)
+	}
+}
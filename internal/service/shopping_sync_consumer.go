@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"nutrient_be/internal/config"
+	"nutrient_be/internal/domain"
+	"nutrient_be/internal/pkg/events"
+	"nutrient_be/internal/pkg/logger"
+)
+
+// ShoppingListSyncer defines the shopping list operation used by ShoppingSyncConsumer
+type ShoppingListSyncer interface {
+	SyncWithMealPlan(ctx context.Context, userID string, mealPlanID string) (*domain.ShoppingList, error)
+}
+
+// ShoppingSyncConsumer regenerates shopping lists in the background when their meal plan is updated
+type ShoppingSyncConsumer struct {
+	syncer         ShoppingListSyncer
+	bus            events.Bus
+	retryAttempts  int
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+	logger         logger.Logger
+	unsubscribe    func()
+}
+
+// NewShoppingSyncConsumer creates a consumer of mealplan.updated events
+// Transient failures are retried with exponential backoff as configured in cfg
+func NewShoppingSyncConsumer(syncer ShoppingListSyncer, bus events.Bus, cfg config.ShoppingConfig, log logger.Logger) *ShoppingSyncConsumer {
+	attempts := cfg.RetryAttempts
+	if attempts < 1 {
+		attempts = 1
+	}
+	return &ShoppingSyncConsumer{
+		syncer:         syncer,
+		bus:            bus,
+		retryAttempts:  attempts,
+		initialBackoff: cfg.RetryInitialBackoff * time.Millisecond,
+		maxBackoff:     cfg.RetryMaxBackoff * time.Millisecond,
+		logger:         log,
+	}
+}
+
+// Start subscribes to mealplan.updated events
+func (c *ShoppingSyncConsumer) Start() error {
+	unsubscribe, err := c.bus.Subscribe(events.SubjectMealPlanUpdated, c.handle)
+	if err != nil {
+		return fmt.Errorf("failed to subscribe to %s: %w", events.SubjectMealPlanUpdated, err)
+	}
+	c.unsubscribe = unsubscribe
+	c.logger.Info(context.Background(), "Shopping list sync consumer started")
+	return nil
+}
+
+// Stop unsubscribes from events; closing the bus waits for regenerations in progress
+func (c *ShoppingSyncConsumer) Stop() {
+	if c.unsubscribe != nil {
+		c.unsubscribe()
+		c.unsubscribe = nil
+	}
+}
+
+// handle regenerates the shopping list of the updated meal plan, retrying transient failures
+func (c *ShoppingSyncConsumer) handle(ctx context.Context, data []byte) error {
+	var event events.MealPlanUpdated
+	if err := json.Unmarshal(data, &event); err != nil {
+		c.logger.Error(ctx, "Invalid meal plan updated event", logger.Error(err))
+		return fmt.Errorf("invalid meal plan updated event: %w", err)
+	}
+
+	backoff := c.initialBackoff
+	var err error
+	for attempt := 1; attempt <= c.retryAttempts; attempt++ {
+		var list *domain.ShoppingList
+		list, err = c.syncer.SyncWithMealPlan(ctx, event.UserID, event.MealPlanID)
+		if err == nil {
+			if list != nil {
+				c.logger.Info(ctx, "Shopping list synced with meal plan",
+					logger.String("meal_plan_id", event.MealPlanID),
+					logger.String("shopping_list_id", list.ID.Hex()))
+			}
+			return nil
+		}
+		if !isTransientSyncError(err) || attempt == c.retryAttempts {
+			break
+		}
+
+		c.logger.Warn(ctx, "Shopping list sync failed, retrying",
+			logger.String("meal_plan_id", event.MealPlanID),
+			logger.Int("attempt", attempt),
+			logger.Error(err))
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		backoff *= 2
+		if c.maxBackoff > 0 && backoff > c.maxBackoff {
+			backoff = c.maxBackoff
+		}
+	}
+
+	c.logger.Error(ctx, "Failed to sync shopping list with meal plan",
+		logger.String("meal_plan_id", event.MealPlanID),
+		logger.Error(err))
+	return err
+}
+
+// isTransientSyncError reports whether a sync failure may succeed when retried
+func isTransientSyncError(err error) bool {
+	msg := err.Error()
+	return !strings.HasSuffix(msg, "not found or access denied") && !strings.HasPrefix(msg, "invalid ")
+}
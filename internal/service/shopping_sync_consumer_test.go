@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"nutrient_be/internal/config"
+	"nutrient_be/internal/domain"
+	"nutrient_be/internal/pkg/events"
+	"nutrient_be/internal/pkg/logger"
+)
+
+var testShoppingSyncConfig = config.ShoppingConfig{
+	AutoRegenerate:      true,
+	RetryAttempts:       3,
+	RetryInitialBackoff: 1,
+	RetryMaxBackoff:     2,
+}
+
+func TestShoppingSyncConsumer_RegeneratesOnPlanUpdate(t *testing.T) {
+	ctx := context.Background()
+	userID := primitive.NewObjectID()
+	rice := &domain.FoodItem{
+		ID:           primitive.NewObjectID(),
+		Name:         map[string]string{"en": "Rice"},
+		ServingSizes: []domain.ServingSize{{Unit: "gram", Amount: 100, GramEquivalent: 100}},
+	}
+	plan := newTestPlan(userID)
+	plan.TargetCalories = 2000
+	plan.DailyMeals[0].Meals[0].FoodItems = []domain.MealFoodItem{
+		{FoodItemID: rice.ID, FoodName: "Rice", ServingUnit: "gram", Amount: 150, Calories: 195},
+	}
+
+	plans := newFakeMealPlanRepo(plan)
+	lists := newFakeShoppingListRepo()
+	shoppingService := NewShoppingService(lists, plans, newFakeFoodRepo(rice), logger.NewNoopLogger())
+	if _, err := shoppingService.GenerateFromMealPlan(ctx, userID.Hex(), plan.ID.Hex()); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	bus := events.NewMemoryBus()
+	consumer := NewShoppingSyncConsumer(shoppingService, bus, testShoppingSyncConfig, logger.NewNoopLogger())
+	if err := consumer.Start(); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	mealPlanService := NewMealPlanService(plans, nil, lists, nil, config.UserConfig{}, bus, logger.NewNoopLogger())
+
+	// Doubling the target rescales the open meal to 300g of rice
+	if _, err := mealPlanService.UpdateTargets(ctx, userID.Hex(), plan.ID.Hex(), 4000, nil, true); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	consumer.Stop()
+	if err := bus.Close(ctx); err != nil {
+		t.Fatalf("Expected event handlers to finish, got: %v", err)
+	}
+
+	list, err := lists.GetByMealPlan(ctx, plan.ID)
+	if err != nil {
+		t.Fatalf("Expected shopping list, got: %v", err)
+	}
+	if len(list.Items) != 1 || list.Items[0].TotalAmount != 300 {
+		t.Errorf("Expected regenerated list with 300g of rice, got: %+v", list.Items)
+	}
+}
+
+func TestShoppingSyncConsumer_SkipsPlanWithoutList(t *testing.T) {
+	ctx := context.Background()
+	userID := primitive.NewObjectID()
+	plan := newTestPlan(userID)
+	lists := newFakeShoppingListRepo()
+	shoppingService := NewShoppingService(lists, newFakeMealPlanRepo(plan), newFakeFoodRepo(), logger.NewNoopLogger())
+	consumer := NewShoppingSyncConsumer(shoppingService, events.NewMemoryBus(), testShoppingSyncConfig, logger.NewNoopLogger())
+
+	data := []byte(fmt.Sprintf(`{"mealPlanId":%q,"userId":%q}`, plan.ID.Hex(), userID.Hex()))
+	if err := consumer.handle(ctx, data); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(lists.lists) != 0 {
+		t.Errorf("Expected no shopping list to be created, got %d", len(lists.lists))
+	}
+}
+
+// flakySyncer fails a number of times before succeeding
+type flakySyncer struct {
+	failures int
+	calls    int
+	err      error
+}
+
+func (s *flakySyncer) SyncWithMealPlan(ctx context.Context, userID string, mealPlanID string) (*domain.ShoppingList, error) {
+	s.calls++
+	if s.calls <= s.failures {
+		return nil, s.err
+	}
+	return &domain.ShoppingList{ID: primitive.NewObjectID()}, nil
+}
+
+func TestShoppingSyncConsumer_RetriesTransientFailures(t *testing.T) {
+	data := []byte(`{"mealPlanId":"507f1f77bcf86cd799439011","userId":"507f1f77bcf86cd799439012"}`)
+
+	transient := &flakySyncer{failures: 2, err: fmt.Errorf("failed to update shopping list: connection reset")}
+	consumer := NewShoppingSyncConsumer(transient, events.NewMemoryBus(), testShoppingSyncConfig, logger.NewNoopLogger())
+	if err := consumer.handle(context.Background(), data); err != nil {
+		t.Errorf("Expected success after retries, got: %v", err)
+	}
+	if transient.calls != 3 {
+		t.Errorf("Expected 3 attempts, got: %d", transient.calls)
+	}
+
+	permanent := &flakySyncer{failures: 5, err: fmt.Errorf("meal plan not found or access denied")}
+	consumer = NewShoppingSyncConsumer(permanent, events.NewMemoryBus(), testShoppingSyncConfig, logger.NewNoopLogger())
+	if err := consumer.handle(context.Background(), data); err == nil {
+		t.Error("Expected permanent failure to be returned")
+	}
+	if permanent.calls != 1 {
+		t.Errorf("Expected no retries for a permanent failure, got %d attempts", permanent.calls)
+	}
+}
@@ -0,0 +1,276 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"nutrient_be/internal/domain"
+	"nutrient_be/internal/dto/response"
+	"nutrient_be/internal/pkg/calculator"
+	"nutrient_be/internal/pkg/logger"
+)
+
+const (
+	// suggestionCandidateLimit caps how many foods and templates are read from each source
+	suggestionCandidateLimit = 200
+	// suggestionResultLimit caps how many suggestions are returned
+	suggestionResultLimit = 10
+)
+
+// SuggestionFoodRepository defines the food data operations used by MealSuggestionService
+type SuggestionFoodRepository interface {
+	GetByUser(ctx context.Context, userID primitive.ObjectID, limit, offset int) ([]*domain.FoodItem, error)
+	GetPublicFoods(ctx context.Context, limit, offset int) ([]*domain.FoodItem, error)
+}
+
+// SuggestionMealTemplateRepository defines the meal template data operations used by MealSuggestionService
+type SuggestionMealTemplateRepository interface {
+	GetByUser(ctx context.Context, userID primitive.ObjectID, mealType string, limit, offset int) ([]*domain.MealTemplate, error)
+	GetPublicTemplates(ctx context.Context, mealType string, limit, offset int) ([]*domain.MealTemplate, error)
+}
+
+// SuggestionMealPlanRepository defines the meal plan data operations used by MealSuggestionService
+type SuggestionMealPlanRepository interface {
+	GetByUserAndDateRange(ctx context.Context, userID primitive.ObjectID, startDate, endDate time.Time) ([]*domain.MealPlan, error)
+}
+
+// SuggestionUserRepository defines the user data operations used by MealSuggestionService
+type SuggestionUserRepository interface {
+	GetByID(ctx context.Context, id primitive.ObjectID) (*domain.User, error)
+}
+
+// MealSuggestionService suggests foods and templates that fit a remaining daily budget
+type MealSuggestionService struct {
+	foodRepo         SuggestionFoodRepository
+	mealTemplateRepo SuggestionMealTemplateRepository
+	mealPlanRepo     SuggestionMealPlanRepository
+	userRepo         SuggestionUserRepository
+	logger           logger.Logger
+}
+
+// NewMealSuggestionService creates a new meal suggestion service
+func NewMealSuggestionService(
+	foodRepo SuggestionFoodRepository,
+	mealTemplateRepo SuggestionMealTemplateRepository,
+	mealPlanRepo SuggestionMealPlanRepository,
+	userRepo SuggestionUserRepository,
+	log logger.Logger,
+) *MealSuggestionService {
+	return &MealSuggestionService{
+		foodRepo:         foodRepo,
+		mealTemplateRepo: mealTemplateRepo,
+		mealPlanRepo:     mealPlanRepo,
+		userRepo:         userRepo,
+		logger:           log,
+	}
+}
+
+// suggestionCandidate is a food serving or template evaluated against the remaining budget
+type suggestionCandidate struct {
+	suggestion     response.MealSuggestionResponse
+	proteinDensity float64 // Protein grams per 100 kcal, used to break ties
+}
+
+// SuggestForDate derives the remaining budget for date from the user's targets and completed meals, then suggests
+func (s *MealSuggestionService) SuggestForDate(ctx context.Context, userID string, date time.Time) (*response.MealSuggestionsResponse, error) {
+	s.logger.Info(ctx, "Suggesting meals for date", logger.String("date", date.Format(dateLayout)))
+
+	userIDObj, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		s.logger.Error(ctx, "Invalid user ID", logger.Error(err))
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userIDObj)
+	if err != nil {
+		s.logger.Error(ctx, "Failed to get user", logger.Error(err))
+		return nil, fmt.Errorf("user not found: %w", err)
+	}
+
+	day := startOfDay(date)
+	plans, err := s.mealPlanRepo.GetByUserAndDateRange(ctx, userIDObj, day, day)
+	if err != nil {
+		s.logger.Error(ctx, "Failed to get meal plans", logger.Error(err))
+		return nil, fmt.Errorf("failed to get meal plans: %w", err)
+	}
+	days, consumedMacros := buildDailyReports(plans, day, day.AddDate(0, 0, 1))
+
+	remainingCalories, remainingMacros := remainingBudget(user, days[0].ConsumedCalories, consumedMacros)
+
+	suggestions, err := s.Suggest(ctx, userID, remainingCalories, remainingMacros)
+	if err != nil {
+		return nil, err
+	}
+
+	return &response.MealSuggestionsResponse{
+		Date:              day,
+		RemainingCalories: remainingCalories,
+		RemainingMacros:   macrosToReportResponse(remainingMacros),
+		Suggestions:       suggestions,
+	}, nil
+}
+
+// Suggest ranks foods and templates by how much of the remaining protein they cover without exceeding the remaining calories
+// Foods are evaluated at their standard serving; ties are broken by protein per calorie
+func (s *MealSuggestionService) Suggest(ctx context.Context, userID string, remainingCalories float64, remainingMacros domain.MacroNutrients) ([]response.MealSuggestionResponse, error) {
+	s.logger.Info(ctx, "Suggesting meals",
+		logger.Float64("remaining_calories", remainingCalories),
+		logger.Float64("remaining_protein", remainingMacros.Protein))
+
+	userIDObj, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		s.logger.Error(ctx, "Invalid user ID", logger.Error(err))
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	suggestions := []response.MealSuggestionResponse{}
+	if remainingCalories <= 0 {
+		return suggestions, nil
+	}
+
+	foods, templates, err := s.loadCandidates(ctx, userIDObj)
+	if err != nil {
+		return nil, err
+	}
+
+	var candidates []suggestionCandidate
+	for _, food := range foods {
+		unit, amount, ok := standardServing(food)
+		if !ok {
+			continue
+		}
+		calories, macros, _, err := calculator.CalculateNutrientsForServing(food, unit, amount)
+		if err != nil {
+			continue
+		}
+		candidates = append(candidates, newSuggestionCandidate(response.MealSuggestionResponse{
+			Type:          "food",
+			ID:            food.ID.Hex(),
+			Name:          food.Name["en"],
+			ServingUnit:   unit,
+			ServingAmount: amount,
+		}, calories, macros, remainingMacros.Protein))
+	}
+	for _, template := range templates {
+		candidates = append(candidates, newSuggestionCandidate(response.MealSuggestionResponse{
+			Type: "template",
+			ID:   template.ID.Hex(),
+			Name: template.Name,
+		}, template.TotalCalories, template.TotalMacros, remainingMacros.Protein))
+	}
+
+	fitting := candidates[:0]
+	for _, candidate := range candidates {
+		if candidate.suggestion.Calories > 0 && candidate.suggestion.Calories <= remainingCalories {
+			fitting = append(fitting, candidate)
+		}
+	}
+
+	sort.SliceStable(fitting, func(i, j int) bool {
+		if fitting[i].suggestion.ProteinFill != fitting[j].suggestion.ProteinFill {
+			return fitting[i].suggestion.ProteinFill > fitting[j].suggestion.ProteinFill
+		}
+		return fitting[i].proteinDensity > fitting[j].proteinDensity
+	})
+
+	for i := 0; i < len(fitting) && i < suggestionResultLimit; i++ {
+		suggestions = append(suggestions, fitting[i].suggestion)
+	}
+
+	s.logger.Info(ctx, "Meal suggestions generated", logger.Int("count", len(suggestions)))
+	return suggestions, nil
+}
+
+// loadCandidates returns the public foods and templates plus the user's own, without duplicates
+func (s *MealSuggestionService) loadCandidates(ctx context.Context, userID primitive.ObjectID) ([]*domain.FoodItem, []*domain.MealTemplate, error) {
+	publicFoods, err := s.foodRepo.GetPublicFoods(ctx, suggestionCandidateLimit, 0)
+	if err != nil {
+		s.logger.Error(ctx, "Failed to get public foods", logger.Error(err))
+		return nil, nil, fmt.Errorf("failed to get foods: %w", err)
+	}
+	userFoods, err := s.foodRepo.GetByUser(ctx, userID, suggestionCandidateLimit, 0)
+	if err != nil {
+		s.logger.Error(ctx, "Failed to get user foods", logger.Error(err))
+		return nil, nil, fmt.Errorf("failed to get foods: %w", err)
+	}
+
+	publicTemplates, err := s.mealTemplateRepo.GetPublicTemplates(ctx, "", suggestionCandidateLimit, 0)
+	if err != nil {
+		s.logger.Error(ctx, "Failed to get public templates", logger.Error(err))
+		return nil, nil, fmt.Errorf("failed to get meal templates: %w", err)
+	}
+	userTemplates, err := s.mealTemplateRepo.GetByUser(ctx, userID, "", suggestionCandidateLimit, 0)
+	if err != nil {
+		s.logger.Error(ctx, "Failed to get user templates", logger.Error(err))
+		return nil, nil, fmt.Errorf("failed to get meal templates: %w", err)
+	}
+
+	var foods []*domain.FoodItem
+	seenFoods := make(map[primitive.ObjectID]bool)
+	for _, food := range append(userFoods, publicFoods...) {
+		if !seenFoods[food.ID] {
+			seenFoods[food.ID] = true
+			foods = append(foods, food)
+		}
+	}
+
+	var templates []*domain.MealTemplate
+	seenTemplates := make(map[primitive.ObjectID]bool)
+	for _, template := range append(userTemplates, publicTemplates...) {
+		if !seenTemplates[template.ID] {
+			seenTemplates[template.ID] = true
+			templates = append(templates, template)
+		}
+	}
+
+	return foods, templates, nil
+}
+
+// newSuggestionCandidate fills in the nutrition and protein scores of a suggestion
+func newSuggestionCandidate(suggestion response.MealSuggestionResponse, calories float64, macros domain.MacroNutrients, remainingProtein float64) suggestionCandidate {
+	suggestion.Calories = calories
+	suggestion.Macros = macrosToReportResponse(macros)
+	if remainingProtein > 0 {
+		suggestion.ProteinFill = math.Min(macros.Protein, remainingProtein) / remainingProtein * 100
+	}
+
+	candidate := suggestionCandidate{suggestion: suggestion}
+	if calories > 0 {
+		candidate.proteinDensity = macros.Protein / calories * 100
+	}
+	return candidate
+}
+
+// standardServing returns the serving used to evaluate a food: its preferred display serving, or its first serving size
+func standardServing(food *domain.FoodItem) (string, float64, bool) {
+	if serving := calculator.PreferredDisplayServing(food); serving != nil {
+		return serving.Unit, serving.Amount, true
+	}
+	if len(food.ServingSizes) > 0 {
+		return food.ServingSizes[0].Unit, food.ServingSizes[0].Amount, true
+	}
+	return "", 0, false
+}
+
+// remainingBudget subtracts consumed calories and macros from the user's daily targets
+// Macro targets stored in grams per kg of body weight, the default, are converted using the profile weight
+func remainingBudget(user *domain.User, consumedCalories float64, consumedMacros domain.MacroNutrients) (float64, domain.MacroNutrients) {
+	weight := user.Profile.Weight
+	if user.Preferences.MacroTargetsUnit == domain.MacroUnitGramsPerDay {
+		weight = 1
+	}
+	targets := user.Preferences.MacroTargets
+
+	remaining := domain.MacroNutrients{
+		Protein:       math.Max(targets.Protein*weight-consumedMacros.Protein, 0),
+		Carbohydrates: math.Max(targets.Carbohydrates*weight-consumedMacros.Carbohydrates, 0),
+		Fat:           math.Max(targets.Fat*weight-consumedMacros.Fat, 0),
+		Fiber:         math.Max(targets.Fiber*weight-consumedMacros.Fiber, 0),
+	}
+	return math.Max(user.Preferences.CalorieTarget-consumedCalories, 0), remaining
+}
@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"nutrient_be/internal/domain"
+	"nutrient_be/internal/pkg/logger"
+)
+
+func newSuggestionTestFood(name string, calories float64, macros domain.MacroNutrients) *domain.FoodItem {
+	return &domain.FoodItem{
+		ID:         primitive.NewObjectID(),
+		Name:       map[string]string{"en": name},
+		Macros:     macros,
+		Calories:   calories,
+		Visibility: "public",
+		ServingSizes: []domain.ServingSize{
+			{Unit: "gram", Amount: 100, GramEquivalent: 100},
+		},
+	}
+}
+
+func TestMealSuggestionService_Suggest(t *testing.T) {
+	ctx := context.Background()
+	userID := primitive.NewObjectID()
+
+	chicken := newSuggestionTestFood("Chicken breast", 165, domain.MacroNutrients{Protein: 31, Fat: 3.6})
+	rice := newSuggestionTestFood("White rice", 130, domain.MacroNutrients{Protein: 2.7, Carbohydrates: 28})
+	cheesecake := newSuggestionTestFood("Cheesecake", 321, domain.MacroNutrients{Protein: 5.5, Fat: 22.5})
+	// Egg is evaluated at its preferred "piece" serving (50g)
+	egg := newSuggestionTestFood("Egg", 155, domain.MacroNutrients{Protein: 13, Fat: 11})
+	egg.ServingSizes = append(egg.ServingSizes, domain.ServingSize{Unit: "piece", Amount: 1, GramEquivalent: 50})
+
+	svc := NewMealSuggestionService(
+		newFakeFoodRepo(rice, cheesecake, chicken, egg),
+		newFakeMealTemplateRepo(),
+		newFakeMealPlanRepo(),
+		newFakeUserRepo(),
+		logger.NewNoopLogger(),
+	)
+
+	suggestions, err := svc.Suggest(ctx, userID.Hex(), 300, domain.MacroNutrients{Protein: 40})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(suggestions) != 3 {
+		t.Fatalf("Expected 3 suggestions within the calorie budget, got: %d", len(suggestions))
+	}
+	if suggestions[0].ID != chicken.ID.Hex() {
+		t.Errorf("Expected protein-heavy food first, got: %s", suggestions[0].Name)
+	}
+	if suggestions[1].ID != egg.ID.Hex() || suggestions[1].ServingUnit != "piece" {
+		t.Errorf("Expected egg per piece second, got: %s per %s", suggestions[1].Name, suggestions[1].ServingUnit)
+	}
+	for _, suggestion := range suggestions {
+		if suggestion.ID == cheesecake.ID.Hex() {
+			t.Error("Expected food exceeding remaining calories to be excluded")
+		}
+	}
+	if suggestions[0].ProteinFill != 77.5 {
+		t.Errorf("Expected protein fill 77.5, got: %v", suggestions[0].ProteinFill)
+	}
+}
+
+func TestMealSuggestionService_SuggestForDate(t *testing.T) {
+	ctx := context.Background()
+	day := time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)
+	user := &domain.User{
+		ID:      primitive.NewObjectID(),
+		Profile: domain.UserProfile{Weight: 50},
+		Preferences: domain.UserPreferences{
+			CalorieTarget: 2000,
+			MacroTargets:  domain.MacroNutrients{Protein: 2},
+		},
+	}
+	chicken := newSuggestionTestFood("Chicken breast", 165, domain.MacroNutrients{Protein: 31, Fat: 3.6})
+
+	svc := NewMealSuggestionService(
+		newFakeFoodRepo(chicken),
+		newFakeMealTemplateRepo(),
+		newFakeMealPlanRepo(newReportTestPlan(user.ID, day, 1)),
+		newFakeUserRepo(user),
+		logger.NewNoopLogger(),
+	)
+
+	result, err := svc.SuggestForDate(ctx, user.ID.Hex(), day)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	// One completed meal of 500 kcal and 20g protein against 2000 kcal and 2g/kg * 50kg protein
+	if result.RemainingCalories != 1500 {
+		t.Errorf("Expected remaining calories 1500, got: %v", result.RemainingCalories)
+	}
+	if result.RemainingMacros.Protein != 80 {
+		t.Errorf("Expected remaining protein 80, got: %v", result.RemainingMacros.Protein)
+	}
+	if len(result.Suggestions) != 1 {
+		t.Errorf("Expected 1 suggestion, got: %d", len(result.Suggestions))
+	}
+}
@@ -0,0 +1,22 @@
+package service
+
+import "context"
+
+// Transactor runs multi-step mutations so their repository writes are applied together or not at all
+// It is implemented by database.MongoDB; fn must pass the context it receives to the repositories
+type Transactor interface {
+	WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// noopTransactor runs fn directly without a transaction
+type noopTransactor struct{}
+
+// NewNoopTransactor creates a transactor without transactions, used when the store has none
+func NewNoopTransactor() Transactor {
+	return noopTransactor{}
+}
+
+// WithTransaction runs fn with ctx unchanged
+func (noopTransactor) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
@@ -3,27 +3,90 @@ package service
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strings"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/crypto/bcrypt"
 
+	"nutrient_be/internal/config"
+	"nutrient_be/internal/domain"
 	"nutrient_be/internal/dto/request"
 	"nutrient_be/internal/dto/response"
-	"nutrient_be/internal/domain"
+	"nutrient_be/internal/pkg/calculator"
 	"nutrient_be/internal/pkg/logger"
-	"golang.org/x/crypto/bcrypt"
 )
 
+// UserFoodRepository defines the food data operations used by UserService
+type UserFoodRepository interface {
+	GetByUser(ctx context.Context, userID primitive.ObjectID, limit, offset int) ([]*domain.FoodItem, error)
+	DeleteByUser(ctx context.Context, userID primitive.ObjectID) error
+	AnonymizeByUser(ctx context.Context, userID primitive.ObjectID) error
+}
+
+// UserMealTemplateRepository defines the meal template data operations used by UserService
+type UserMealTemplateRepository interface {
+	GetByUser(ctx context.Context, userID primitive.ObjectID, mealType string, limit, offset int) ([]*domain.MealTemplate, error)
+	DeleteByUser(ctx context.Context, userID primitive.ObjectID) error
+	AnonymizeByUser(ctx context.Context, userID primitive.ObjectID) error
+}
+
+// UserMealPlanRepository defines the meal plan data operations used by UserService
+type UserMealPlanRepository interface {
+	GetByUser(ctx context.Context, userID primitive.ObjectID, planType, status string, limit, offset int) ([]*domain.MealPlan, error)
+	DeleteByUser(ctx context.Context, userID primitive.ObjectID) error
+}
+
+// UserShoppingListRepository defines the shopping list data operations used by UserService
+type UserShoppingListRepository interface {
+	GetByUser(ctx context.Context, userID primitive.ObjectID, status string, limit, offset int) ([]*domain.ShoppingList, error)
+	DeleteByUser(ctx context.Context, userID primitive.ObjectID) error
+}
+
+// exportPageSize is the number of documents fetched per query when exporting user data
+const exportPageSize = 100
+
+// UserDataExport is a portable copy of everything stored for a user
+type UserDataExport struct {
+	ExportedAt    time.Time              `json:"exportedAt"`
+	User          *response.UserResponse `json:"user"`
+	WeightHistory []domain.WeightEntry   `json:"weightHistory"` // Oldest first
+	Foods         []*domain.FoodItem     `json:"foods"`
+	MealTemplates []*domain.MealTemplate `json:"mealTemplates"`
+	MealPlans     []*domain.MealPlan     `json:"mealPlans"`
+	ShoppingLists []*domain.ShoppingList `json:"shoppingLists"`
+}
+
 // UserService handles user profile and preferences management
 type UserService struct {
-	userRepo UserRepository
-	logger   logger.Logger
+	userRepo         UserRepository
+	foodRepo         UserFoodRepository
+	mealTemplateRepo UserMealTemplateRepository
+	mealPlanRepo     UserMealPlanRepository
+	shoppingRepo     UserShoppingListRepository
+	config           config.UserConfig
+	logger           logger.Logger
 }
 
 // NewUserService creates a new user service
-func NewUserService(userRepo UserRepository, log logger.Logger) *UserService {
+func NewUserService(
+	userRepo UserRepository,
+	foodRepo UserFoodRepository,
+	mealTemplateRepo UserMealTemplateRepository,
+	mealPlanRepo UserMealPlanRepository,
+	shoppingRepo UserShoppingListRepository,
+	cfg config.UserConfig,
+	log logger.Logger,
+) *UserService {
 	return &UserService{
-		userRepo: userRepo,
-		logger:   log,
+		userRepo:         userRepo,
+		foodRepo:         foodRepo,
+		mealTemplateRepo: mealTemplateRepo,
+		mealPlanRepo:     mealPlanRepo,
+		shoppingRepo:     shoppingRepo,
+		config:           cfg,
+		logger:           log,
 	}
 }
 
@@ -44,59 +107,47 @@ func (s *UserService) GetProfile(ctx context.Context, userID string) (*response.
 }
 
 // UpdateProfile updates user profile information
+// Age, weight or height outside the configured ranges fail with a *ProfileValidationError
 func (s *UserService) UpdateProfile(ctx context.Context, userID string, req *request.UpdateProfileRequest) (*response.UserResponse, error) {
 	userIDObj, err := primitive.ObjectIDFromHex(userID)
 	if err != nil {
 		return nil, fmt.Errorf("invalid user ID format: %w", err)
 	}
 
+	if err := s.checkMeasurements(req.Age, req.Height, req.Weight); err != nil {
+		return nil, err
+	}
+
 	// Get existing user
 	user, err := s.userRepo.GetByID(ctx, userIDObj)
 	if err != nil {
 		return nil, fmt.Errorf("user not found: %w", err)
 	}
 
-	// Update profile fields
-	if req.Name != nil {
-		user.Profile.Name = *req.Name
-	}
-	if req.Age != nil {
-		user.Profile.Age = *req.Age
-	}
+	// Only the profile is reachable from the request; email, password and any other account fields are never touched
+	bodyChanged := applyProfileUpdate(&user.Profile, req)
 	if req.Weight != nil {
-		user.Profile.Weight = *req.Weight
-	}
-	if req.Height != nil {
-		user.Profile.Height = *req.Height
-	}
-	if req.Gender != nil {
-		user.Profile.Gender = *req.Gender
-	}
-	if req.Goal != nil {
-		user.Profile.Goal = *req.Goal
-		// Recalculate calorie target and macro targets when goal changes
-		if user.Profile.Weight > 0 && user.Profile.Height > 0 && user.Profile.Age > 0 {
-			user.Preferences.CalorieTarget = calculateCalorieTarget(
-				user.Profile.Weight,
-				user.Profile.Height,
-				user.Profile.Age,
-				user.Profile.Gender,
-				user.Profile.Goal,
-			)
-			user.Preferences.MacroTargets = calculateMacroTargets(user.Profile.Goal)
-		}
+		user.WeightHistory = append(user.WeightHistory, domain.WeightEntry{Weight: *req.Weight, Date: time.Now().UTC()})
 	}
 
-	// Also recalculate if weight/height/age changes and goal is set
-	if (req.Weight != nil || req.Height != nil || req.Age != nil) && user.Profile.Goal != "" {
-		if user.Profile.Weight > 0 && user.Profile.Height > 0 && user.Profile.Age > 0 {
-			user.Preferences.CalorieTarget = calculateCalorieTarget(
+	// Recalculate targets when the goal changes, or when a value the BMR depends on changes and a goal is set
+	// A stored profile still missing a measurement, e.g. one registered without it, keeps its targets until it is set
+	if req.Goal != nil || (bodyChanged && user.Profile.Goal != "") {
+		if s.checkMeasurements(&user.Profile.Age, &user.Profile.Height, &user.Profile.Weight) == nil {
+			calorieTarget := calculateCalorieTarget(
 				user.Profile.Weight,
 				user.Profile.Height,
 				user.Profile.Age,
 				user.Profile.Gender,
 				user.Profile.Goal,
+				user.Profile.ActivityLevel,
 			)
+			user.Preferences.CalorieTarget = clampCalorieTarget(ctx, s.logger, s.config, calorieTarget, user.Profile.Gender)
+			// Per-kg macro targets only depend on the goal; split targets follow the calorie target too
+			if req.Goal != nil || s.config.MacroModel == config.MacroModelSplit {
+				user.Preferences.MacroTargets = calculateMacroTargets(s.config, user.Profile.Goal, user.Preferences.CalorieTarget, user.Profile.Weight)
+				user.Preferences.MacroTargetsUnit = macroTargetsUnit(s.config)
+			}
 		}
 	}
 
@@ -109,6 +160,106 @@ func (s *UserService) UpdateProfile(ctx context.Context, userID string, req *req
 	return domainUserToResponse(user), nil
 }
 
+// applyProfileUpdate copies the fields set in req onto profile, which is the only part of the user it can change
+// It reports whether weight, height, age, gender or activity level was set
+func applyProfileUpdate(profile *domain.UserProfile, req *request.UpdateProfileRequest) (bodyChanged bool) {
+	if req.Name != nil {
+		profile.Name = *req.Name
+	}
+	if req.Goal != nil {
+		profile.Goal = *req.Goal
+	}
+	if req.Age != nil {
+		profile.Age = *req.Age
+		bodyChanged = true
+	}
+	if req.Weight != nil {
+		profile.Weight = *req.Weight
+		bodyChanged = true
+	}
+	if req.Height != nil {
+		profile.Height = *req.Height
+		bodyChanged = true
+	}
+	if req.Gender != nil {
+		profile.Gender = *req.Gender
+		bodyChanged = true
+	}
+	if req.ActivityLevel != nil {
+		profile.ActivityLevel = *req.ActivityLevel
+		bodyChanged = true
+	}
+	return bodyChanged
+}
+
+// CalculateTargets computes the calorie and macro targets for a profile without reading or saving any user
+// The values match what UpdateProfile stores for the same profile, including the safe range clamp.
+// Age, weight or height outside the configured ranges fail with a *ProfileValidationError.
+func (s *UserService) CalculateTargets(ctx context.Context, req *request.CalculateTargetsRequest) (*response.CalorieTargetsResponse, error) {
+	if err := s.checkMeasurements(&req.Age, &req.Height, &req.Weight); err != nil {
+		return nil, err
+	}
+
+	bmr := calculateBMR(req.Weight, req.Height, req.Age, req.Gender)
+	maintenance := calculateMaintenanceCalories(req.Weight, req.Height, req.Age, req.Gender, req.ActivityLevel)
+
+	calorieTarget := clampCalorieTarget(ctx, s.logger, s.config, calculateCalorieTarget(req.Weight, req.Height, req.Age, req.Gender, req.Goal, req.ActivityLevel), req.Gender)
+
+	s.logger.Info(ctx, "Calorie targets calculated", logger.String("goal", req.Goal))
+	return &response.CalorieTargetsResponse{
+		BMR:                 bmr,
+		MaintenanceCalories: maintenance,
+		CalorieTarget:       calorieTarget,
+		MacroTargets:        macrosToReportResponse(calculateMacroTargets(s.config, req.Goal, calorieTarget, req.Weight)),
+	}, nil
+}
+
+// ProfileValidationError is a profile with measurements outside the configured ranges
+// Fields maps each failing field by its JSON name to what is wrong with it, e.g. {"age": "must be between 1 and 120"}
+type ProfileValidationError struct {
+	Fields map[string]string
+}
+
+func (e *ProfileValidationError) Error() string {
+	names := make([]string, 0, len(e.Fields))
+	for name := range e.Fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	problems := make([]string, len(names))
+	for i, name := range names {
+		problems[i] = name + " " + e.Fields[name]
+	}
+	return "validation failed: " + strings.Join(problems, ", ")
+}
+
+// checkMeasurements checks the set measurements against the configured ranges; nil values are skipped
+// The BMR formula gives meaningless targets outside them, e.g. for a zero height or a negative weight
+func (s *UserService) checkMeasurements(age *int, height, weight *float64) error {
+	fields := make(map[string]string)
+	if age != nil {
+		if valid := s.config.AgeRange(); !valid.Contains(float64(*age)) {
+			fields["age"] = fmt.Sprintf("must be between %g and %g", valid.Min, valid.Max)
+		}
+	}
+	if height != nil {
+		if valid := s.config.HeightRange(); !valid.Contains(*height) {
+			fields["height"] = fmt.Sprintf("must be between %g and %g cm", valid.Min, valid.Max)
+		}
+	}
+	if weight != nil {
+		if valid := s.config.WeightRange(); !valid.Contains(*weight) {
+			fields["weight"] = fmt.Sprintf("must be between %g and %g kg", valid.Min, valid.Max)
+		}
+	}
+
+	if len(fields) > 0 {
+		return &ProfileValidationError{Fields: fields}
+	}
+	return nil
+}
+
 // UpdatePreferences updates user preferences
 func (s *UserService) UpdatePreferences(ctx context.Context, userID string, req *request.UpdatePreferencesRequest) (*response.UserResponse, error) {
 	userIDObj, err := primitive.ObjectIDFromHex(userID)
@@ -129,6 +280,25 @@ func (s *UserService) UpdatePreferences(ctx context.Context, userID string, req
 	if req.CalorieTarget != nil {
 		user.Preferences.CalorieTarget = *req.CalorieTarget
 	}
+	if req.WeekStart != nil {
+		user.Preferences.WeekStart = *req.WeekStart
+	}
+	if req.DailyValues != nil {
+		user.Preferences.DailyValues = domain.DailyReferenceValues{
+			Calories:      req.DailyValues.Calories,
+			Protein:       req.DailyValues.Protein,
+			Carbohydrates: req.DailyValues.Carbohydrates,
+			Fat:           req.DailyValues.Fat,
+			Fiber:         req.DailyValues.Fiber,
+			Sugar:         req.DailyValues.Sugar,
+			VitaminA:      req.DailyValues.VitaminA,
+			VitaminC:      req.DailyValues.VitaminC,
+			Calcium:       req.DailyValues.Calcium,
+			Iron:          req.DailyValues.Iron,
+			Sodium:        req.DailyValues.Sodium,
+			Potassium:     req.DailyValues.Potassium,
+		}
+	}
 	if req.MacroTargets != nil {
 		user.Preferences.MacroTargets = domain.MacroNutrients{
 			Protein:       req.MacroTargets.Protein,
@@ -137,6 +307,8 @@ func (s *UserService) UpdatePreferences(ctx context.Context, userID string, req
 			Fiber:         req.MacroTargets.Fiber,
 			Sugar:         req.MacroTargets.Sugar,
 		}
+		// Entered targets are daily grams, whatever the configured model calculates
+		user.Preferences.MacroTargetsUnit = domain.MacroUnitGramsPerDay
 	}
 
 	// Save updated user
@@ -184,19 +356,179 @@ func (s *UserService) ChangePassword(ctx context.Context, userID string, req *re
 	return nil
 }
 
-// calculateCalorieTarget calculates daily calorie target based on user profile
-func calculateCalorieTarget(weight, height float64, age int, gender, goal string) float64 {
-	// Basic BMR calculation (Mifflin-St Jeor Equation)
-	var bmr float64
-	if gender == "male" {
-		bmr = 10*weight + 6.25*height - 5*float64(age) + 5
+// activityFactors maps activity levels to TDEE multipliers
+var activityFactors = map[string]float64{
+	"sedentary":   1.2,
+	"light":       1.375,
+	"moderate":    1.55,
+	"active":      1.725,
+	"very_active": 1.9,
+}
+
+// DeleteAccount permanently deletes a user's account after confirming their password
+// Owned data is deleted or anonymized according to the configured delete mode
+func (s *UserService) DeleteAccount(ctx context.Context, userID, password string) error {
+	userIDObj, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return fmt.Errorf("invalid user ID format: %w", err)
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userIDObj)
+	if err != nil {
+		return fmt.Errorf("user not found: %w", err)
+	}
+
+	// Require password confirmation so a stolen access token alone cannot delete the account
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
+		return fmt.Errorf("invalid password")
+	}
+
+	// Personal data is always removed
+	if err := s.mealPlanRepo.DeleteByUser(ctx, userIDObj); err != nil {
+		return fmt.Errorf("failed to delete meal plans: %w", err)
+	}
+	if err := s.shoppingRepo.DeleteByUser(ctx, userIDObj); err != nil {
+		return fmt.Errorf("failed to delete shopping lists: %w", err)
+	}
+
+	// Foods and templates may be shared with other users
+	if s.config.DeleteMode == "anonymize" {
+		if err := s.foodRepo.AnonymizeByUser(ctx, userIDObj); err != nil {
+			return fmt.Errorf("failed to anonymize foods: %w", err)
+		}
+		if err := s.mealTemplateRepo.AnonymizeByUser(ctx, userIDObj); err != nil {
+			return fmt.Errorf("failed to anonymize meal templates: %w", err)
+		}
 	} else {
-		bmr = 10*weight + 6.25*height - 5*float64(age) - 161
+		if err := s.foodRepo.DeleteByUser(ctx, userIDObj); err != nil {
+			return fmt.Errorf("failed to delete foods: %w", err)
+		}
+		if err := s.mealTemplateRepo.DeleteByUser(ctx, userIDObj); err != nil {
+			return fmt.Errorf("failed to delete meal templates: %w", err)
+		}
+	}
+
+	// Delete the user last so a failure above can be retried
+	if err := s.userRepo.Delete(ctx, userIDObj); err != nil {
+		return fmt.Errorf("failed to delete user: %w", err)
+	}
+
+	s.logger.Info(ctx, "User account deleted",
+		logger.String("userID", userID),
+		logger.String("deleteMode", s.config.DeleteMode))
+	return nil
+}
+
+// ExportUserData gathers the user's profile, preferences and owned data into a single document
+// Each collection is read page by page so heavy users don't require a single huge query
+func (s *UserService) ExportUserData(ctx context.Context, userID string) (*UserDataExport, error) {
+	userIDObj, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID format: %w", err)
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userIDObj)
+	if err != nil {
+		return nil, fmt.Errorf("user not found: %w", err)
+	}
+
+	// domainUserToResponse never includes the password hash
+	export := &UserDataExport{
+		ExportedAt:    time.Now().UTC(),
+		User:          domainUserToResponse(user),
+		WeightHistory: append([]domain.WeightEntry{}, user.WeightHistory...),
+	}
+	sort.SliceStable(export.WeightHistory, func(i, j int) bool {
+		return export.WeightHistory[i].Date.Before(export.WeightHistory[j].Date)
+	})
+
+	export.Foods, err = fetchAllPages(func(limit, offset int) ([]*domain.FoodItem, error) {
+		return s.foodRepo.GetByUser(ctx, userIDObj, limit, offset)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to export foods: %w", err)
+	}
+
+	export.MealTemplates, err = fetchAllPages(func(limit, offset int) ([]*domain.MealTemplate, error) {
+		return s.mealTemplateRepo.GetByUser(ctx, userIDObj, "", limit, offset)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to export meal templates: %w", err)
+	}
+
+	export.MealPlans, err = fetchAllPages(func(limit, offset int) ([]*domain.MealPlan, error) {
+		return s.mealPlanRepo.GetByUser(ctx, userIDObj, "", "", limit, offset)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to export meal plans: %w", err)
+	}
+
+	export.ShoppingLists, err = fetchAllPages(func(limit, offset int) ([]*domain.ShoppingList, error) {
+		return s.shoppingRepo.GetByUser(ctx, userIDObj, "", limit, offset)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to export shopping lists: %w", err)
 	}
 
-	// Activity factor (sedentary)
-	activityFactor := 1.2
-	maintenanceCalories := bmr * activityFactor
+	s.logger.Info(ctx, "User data exported",
+		logger.String("userID", userID),
+		logger.Int("foods", len(export.Foods)),
+		logger.Int("mealTemplates", len(export.MealTemplates)),
+		logger.Int("mealPlans", len(export.MealPlans)),
+		logger.Int("shoppingLists", len(export.ShoppingLists)))
+	return export, nil
+}
+
+// fetchAllPages calls fetch with increasing offsets until a short page is returned
+func fetchAllPages[T any](fetch func(limit, offset int) ([]T, error)) ([]T, error) {
+	all := make([]T, 0)
+	for offset := 0; ; offset += exportPageSize {
+		page, err := fetch(exportPageSize, offset)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, page...)
+		if len(page) < exportPageSize {
+			return all, nil
+		}
+	}
+}
+
+// calculateBMR estimates basal metabolic rate with the Mifflin-St Jeor equation
+// The equation only has male and female constants, so "other" uses their midpoint
+// An unset gender keeps the female constant, the lower estimate
+func calculateBMR(weight, height float64, age int, gender string) float64 {
+	bmr := 10*weight + 6.25*height - 5*float64(age)
+	switch normalizeGender(gender) {
+	case "male":
+		bmr += 5
+	case "other":
+		bmr -= 78
+	default:
+		bmr -= 161
+	}
+	return bmr
+}
+
+// normalizeGender matches a stored gender the way requests are normalized
+// Profiles saved before request normalization may still hold values such as "Male "
+func normalizeGender(gender string) string {
+	return strings.ToLower(strings.TrimSpace(gender))
+}
+
+// calculateMaintenanceCalories estimates daily energy expenditure (TDEE) from the user profile
+// Unknown or empty activity levels fall back to sedentary
+func calculateMaintenanceCalories(weight, height float64, age int, gender, activityLevel string) float64 {
+	activityFactor, ok := activityFactors[activityLevel]
+	if !ok {
+		activityFactor = activityFactors["sedentary"]
+	}
+	return calculateBMR(weight, height, age, gender) * activityFactor
+}
+
+// calculateCalorieTarget calculates daily calorie target based on user profile
+func calculateCalorieTarget(weight, height float64, age int, gender, goal, activityLevel string) float64 {
+	maintenanceCalories := calculateMaintenanceCalories(weight, height, age, gender, activityLevel)
 
 	// Adjust based on goal
 	switch goal {
@@ -209,8 +541,62 @@ func calculateCalorieTarget(weight, height float64, age int, gender, goal string
 	}
 }
 
-// calculateMacroTargets calculates macro targets based on goal
-func calculateMacroTargets(goal string) domain.MacroNutrients {
+// clampCalorieTarget keeps a calculated calorie target within the configured safe range
+// The floor depends on gender and a zero limit is disabled. Clamping is logged so the profile can be reviewed.
+func clampCalorieTarget(ctx context.Context, log logger.Logger, cfg config.UserConfig, target float64, gender string) float64 {
+	floor := cfg.MinCalorieTargetFemale
+	if normalizeGender(gender) == "male" {
+		floor = cfg.MinCalorieTargetMale
+	}
+
+	clamped := target
+	if floor > 0 && clamped < floor {
+		clamped = floor
+	}
+	if cfg.MaxCalorieTarget > 0 && clamped > cfg.MaxCalorieTarget {
+		clamped = cfg.MaxCalorieTarget
+	}
+
+	if clamped != target {
+		log.Info(ctx, "Calorie target clamped to the safe range",
+			logger.Float64("calculated", target),
+			logger.Float64("clamped", clamped),
+			logger.String("gender", gender))
+	}
+	return clamped
+}
+
+// fiberGramsPer1000Calories is the fiber target of the split macro model, following the dietary guideline of 14g per 1000 kcal
+const fiberGramsPer1000Calories = 14
+
+// calculateMacroTargets calculates macro targets for a goal with the configured macro model
+// The split model shares calorieTarget between the macros by the goal's percentages, falling back to the
+// maintenance split for an unset goal, and to the per-kg coefficients times weight when neither split is
+// configured or there is no calorie target, so its targets stay daily grams. The per_kg model, the default,
+// uses fixed coefficients per goal.
+func calculateMacroTargets(cfg config.UserConfig, goal string, calorieTarget, weight float64) domain.MacroNutrients {
+	if cfg.MacroModel != config.MacroModelSplit {
+		return perKgMacroTargets(goal)
+	}
+
+	split, ok := cfg.MacroSplits[goal]
+	if !ok {
+		split, ok = cfg.MacroSplits["maintenance"]
+	}
+	if !ok || calorieTarget <= 0 {
+		return scaleMacros(perKgMacroTargets(goal), weight)
+	}
+	macros := calculator.MacroGramsFromDistribution(calorieTarget, calculator.MacroDistribution{
+		Protein:       split.Protein,
+		Carbohydrates: split.Carbohydrates,
+		Fat:           split.Fat,
+	})
+	macros.Fiber = calorieTarget / 1000 * fiberGramsPer1000Calories
+	return macros
+}
+
+// perKgMacroTargets returns the fixed macro coefficients of a goal
+func perKgMacroTargets(goal string) domain.MacroNutrients {
 	switch goal {
 	case "weight_loss":
 		return domain.MacroNutrients{
@@ -236,3 +622,39 @@ func calculateMacroTargets(goal string) domain.MacroNutrients {
 	}
 }
 
+// scaleMacros multiplies every macro by factor, e.g. per-kg coefficients by body weight
+func scaleMacros(macros domain.MacroNutrients, factor float64) domain.MacroNutrients {
+	return domain.MacroNutrients{
+		Protein:       macros.Protein * factor,
+		Carbohydrates: macros.Carbohydrates * factor,
+		Fat:           macros.Fat * factor,
+		Fiber:         macros.Fiber * factor,
+		Sugar:         macros.Sugar * factor,
+	}
+}
+
+// macroTargetsUnit is the unit calculateMacroTargets returns targets in under cfg's macro model
+func macroTargetsUnit(cfg config.UserConfig) string {
+	if cfg.MacroModel == config.MacroModelSplit {
+		return domain.MacroUnitGramsPerDay
+	}
+	return domain.MacroUnitGramsPerKg
+}
+
+// dailyMacroTargets returns a user's macro targets in grams per day
+// Targets stored per kg of body weight are scaled by the profile weight, so they need one;
+// targets stored before their unit was recorded are taken to be in the configured model's unit
+func dailyMacroTargets(cfg config.UserConfig, user *domain.User) (domain.MacroNutrients, error) {
+	unit := user.Preferences.MacroTargetsUnit
+	if unit == "" {
+		unit = macroTargetsUnit(cfg)
+	}
+	if unit == domain.MacroUnitGramsPerDay {
+		return user.Preferences.MacroTargets, nil
+	}
+
+	if user.Profile.Weight <= 0 {
+		return domain.MacroNutrients{}, fmt.Errorf("profile has no weight to convert per-kg macro targets; set the weight or targetMacros")
+	}
+	return scaleMacros(user.Preferences.MacroTargets, user.Profile.Weight), nil
+}
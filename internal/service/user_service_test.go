@@ -0,0 +1,431 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/crypto/bcrypt"
+
+	"nutrient_be/internal/config"
+	"nutrient_be/internal/domain"
+	"nutrient_be/internal/dto/request"
+	"nutrient_be/internal/pkg/logger"
+)
+
+// userServiceFixture bundles a UserService with the fakes backing it
+type userServiceFixture struct {
+	svc       *UserService
+	users     *fakeUserRepo
+	foods     *fakeFoodRepo
+	templates *fakeMealTemplateRepo
+	plans     *fakeMealPlanRepo
+	lists     *fakeShoppingListRepo
+}
+
+func newUserServiceFixture(t *testing.T, deleteMode string, user *domain.User) *userServiceFixture {
+	t.Helper()
+	f := &userServiceFixture{
+		users:     newFakeUserRepo(user),
+		foods:     newFakeFoodRepo(),
+		templates: newFakeMealTemplateRepo(),
+		plans:     newFakeMealPlanRepo(),
+		lists:     newFakeShoppingListRepo(),
+	}
+	cfg := config.UserConfig{DeleteMode: deleteMode}
+	f.svc = NewUserService(f.users, f.foods, f.templates, f.plans, f.lists, cfg, logger.NewNoopLogger())
+	return f
+}
+
+func newTestUser(t *testing.T, password string) *domain.User {
+	t.Helper()
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("failed to hash password: %v", err)
+	}
+	return &domain.User{
+		ID:           primitive.NewObjectID(),
+		Email:        "user@example.com",
+		PasswordHash: string(hash),
+	}
+}
+
+func TestUserService_DeleteAccount(t *testing.T) {
+	user := newTestUser(t, "secret123")
+	f := newUserServiceFixture(t, "cascade", user)
+
+	food := &domain.FoodItem{ID: primitive.NewObjectID(), CreatedBy: user.ID, Visibility: "public"}
+	f.foods.foods[food.ID] = food
+	plan := newTestPlan(user.ID)
+	f.plans.plans[plan.ID] = plan
+
+	if err := f.svc.DeleteAccount(context.Background(), user.ID.Hex(), "secret123"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(f.users.users) != 0 {
+		t.Error("expected user to be deleted")
+	}
+	if len(f.foods.foods) != 0 {
+		t.Error("expected foods to be deleted in cascade mode")
+	}
+	if len(f.plans.plans) != 0 {
+		t.Error("expected meal plans to be deleted")
+	}
+}
+
+func TestUserService_DeleteAccount_Anonymize(t *testing.T) {
+	user := newTestUser(t, "secret123")
+	f := newUserServiceFixture(t, "anonymize", user)
+
+	public := &domain.FoodItem{ID: primitive.NewObjectID(), CreatedBy: user.ID, Visibility: "public"}
+	private := &domain.FoodItem{ID: primitive.NewObjectID(), CreatedBy: user.ID, Visibility: "private"}
+	f.foods.foods[public.ID] = public
+	f.foods.foods[private.ID] = private
+
+	if err := f.svc.DeleteAccount(context.Background(), user.ID.Hex(), "secret123"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, ok := f.foods.foods[private.ID]; ok {
+		t.Error("expected private food to be deleted")
+	}
+	kept, ok := f.foods.foods[public.ID]
+	if !ok {
+		t.Fatal("expected public food to be kept")
+	}
+	if kept.CreatedBy != primitive.NilObjectID {
+		t.Error("expected public food to be detached from the account")
+	}
+}
+
+func TestUserService_DeleteAccount_WrongPassword(t *testing.T) {
+	user := newTestUser(t, "secret123")
+	f := newUserServiceFixture(t, "cascade", user)
+
+	err := f.svc.DeleteAccount(context.Background(), user.ID.Hex(), "wrong")
+	if err == nil || err.Error() != "invalid password" {
+		t.Fatalf("expected invalid password error, got %v", err)
+	}
+	if len(f.users.users) != 1 {
+		t.Error("expected user to be kept")
+	}
+}
+
+func TestUserService_ExportUserData(t *testing.T) {
+	user := newTestUser(t, "secret123")
+	f := newUserServiceFixture(t, "cascade", user)
+
+	now := time.Now().UTC()
+	user.WeightHistory = []domain.WeightEntry{{Weight: 72, Date: now}, {Weight: 75, Date: now.AddDate(0, -1, 0)}}
+
+	// More foods than one page to exercise paging
+	for i := 0; i < exportPageSize+5; i++ {
+		food := &domain.FoodItem{ID: primitive.NewObjectID(), CreatedBy: user.ID}
+		f.foods.foods[food.ID] = food
+	}
+	plan := newTestPlan(user.ID)
+	f.plans.plans[plan.ID] = plan
+
+	export, err := f.svc.ExportUserData(context.Background(), user.ID.Hex())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	exported := make(map[primitive.ObjectID]bool, len(export.Foods))
+	for _, food := range export.Foods {
+		exported[food.ID] = true
+	}
+	if len(export.Foods) != exportPageSize+5 || len(exported) != exportPageSize+5 {
+		t.Errorf("exported %d foods (%d distinct), want each of %d once", len(export.Foods), len(exported), exportPageSize+5)
+	}
+	if len(export.WeightHistory) != 2 || export.WeightHistory[0].Weight != 75 || export.WeightHistory[1].Weight != 72 {
+		t.Errorf("exported weight history %+v, want 75kg then 72kg", export.WeightHistory)
+	}
+	if len(export.MealPlans) != 1 {
+		t.Errorf("exported %d meal plans, want 1", len(export.MealPlans))
+	}
+
+	data, err := json.Marshal(export)
+	if err != nil {
+		t.Fatalf("failed to marshal export: %v", err)
+	}
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("failed to unmarshal export: %v", err)
+	}
+	for _, section := range []string{"exportedAt", "user", "weightHistory", "foods", "mealTemplates", "mealPlans", "shoppingLists"} {
+		if _, ok := doc[section]; !ok {
+			t.Errorf("export is missing %q section", section)
+		}
+	}
+	if strings.Contains(string(data), user.PasswordHash) {
+		t.Error("export must not contain the password hash")
+	}
+}
+
+func TestCalculateMaintenanceCalories_Gender(t *testing.T) {
+	// 70kg, 175cm, 30 years old and sedentary gives 1643.75 before the gender constant
+	tests := []struct {
+		gender   string
+		expected float64
+	}{
+		{"male", (1643.75 + 5) * 1.2},
+		{"female", (1643.75 - 161) * 1.2},
+		{"other", (1643.75 - 78) * 1.2},
+		{"", (1643.75 - 161) * 1.2},
+		{"Male ", (1643.75 + 5) * 1.2}, // Stored before requests were normalized
+		{"OTHER", (1643.75 - 78) * 1.2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.gender, func(t *testing.T) {
+			got := calculateMaintenanceCalories(70, 175, 30, tt.gender, "sedentary")
+			if math.Abs(got-tt.expected) > 1e-9 {
+				t.Errorf("Expected %.2f kcal, got: %.2f", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestClampCalorieTarget(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.UserConfig{MinCalorieTargetFemale: 1200, MinCalorieTargetMale: 1500, MaxCalorieTarget: 4000}
+	log := logger.NewNoopLogger()
+
+	tests := []struct {
+		name     string
+		target   float64
+		gender   string
+		expected float64
+	}{
+		{"female below floor", 900, "female", 1200},
+		{"male below floor", 900, "male", 1500},
+		{"unnormalized male below floor", 900, " Male", 1500},
+		{"other uses female floor", 900, "other", 1200},
+		{"within range", 2100, "male", 2100},
+		{"above ceiling", 4800, "male", 4000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := clampCalorieTarget(ctx, log, cfg, tt.target, tt.gender); got != tt.expected {
+				t.Errorf("Expected %.0f kcal, got: %.0f", tt.expected, got)
+			}
+		})
+	}
+
+	if got := clampCalorieTarget(ctx, log, config.UserConfig{}, 900, "female"); got != 900 {
+		t.Errorf("Expected zero limits to leave the target alone, got: %.0f", got)
+	}
+}
+
+func TestUserService_CalculateTargets_RaisesLowTargetToFloor(t *testing.T) {
+	users := newFakeUserRepo()
+	cfg := config.UserConfig{MinCalorieTargetFemale: 1200, MinCalorieTargetMale: 1500, MaxCalorieTarget: 5000}
+	svc := NewUserService(users, nil, nil, nil, nil, cfg, logger.NewNoopLogger())
+
+	// 45kg, 150cm, 60 years old and sedentary maintains at 1111.8 kcal, so weight loss would be 611.8 kcal
+	targets, err := svc.CalculateTargets(context.Background(), &request.CalculateTargetsRequest{
+		Weight:        45,
+		Height:        150,
+		Age:           60,
+		Gender:        "female",
+		Goal:          "weight_loss",
+		ActivityLevel: "sedentary",
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if targets.CalorieTarget != 1200 {
+		t.Errorf("Expected the calorie target to be raised to 1200 kcal, got: %v", targets.CalorieTarget)
+	}
+}
+
+func TestCalculateMacroTargets_Split(t *testing.T) {
+	cfg := config.UserConfig{
+		MacroModel: config.MacroModelSplit,
+		MacroSplits: map[string]config.MacroSplit{
+			"weight_loss": {Protein: 40, Carbohydrates: 30, Fat: 30},
+			"maintenance": {Protein: 30, Carbohydrates: 40, Fat: 30},
+		},
+	}
+
+	// 40/30/30 of 2000 kcal is 800, 600 and 600 kcal
+	macros := calculateMacroTargets(cfg, "weight_loss", 2000, 70)
+	if math.Abs(macros.Protein-200) > 1e-9 || math.Abs(macros.Carbohydrates-150) > 1e-9 || math.Abs(macros.Fat-600.0/9) > 1e-9 {
+		t.Errorf("Expected 200g/150g/66.7g, got: %.1f/%.1f/%.1f", macros.Protein, macros.Carbohydrates, macros.Fat)
+	}
+	if macros.Fiber != 28 {
+		t.Errorf("Expected 14g of fiber per 1000 kcal, got: %v", macros.Fiber)
+	}
+
+	if unset := calculateMacroTargets(cfg, "", 2000, 70); math.Abs(unset.Carbohydrates-200) > 1e-9 {
+		t.Errorf("Expected an unset goal to use the maintenance split, got: %+v", unset)
+	}
+	if perKg := calculateMacroTargets(config.UserConfig{}, "weight_loss", 2000, 70); perKg != perKgMacroTargets("weight_loss") {
+		t.Errorf("Expected the default model to keep the per-kg coefficients, got: %+v", perKg)
+	}
+}
+
+func TestCalculateMacroTargets_SplitModelWithoutSplitUsesPerKgGrams(t *testing.T) {
+	cfg := config.UserConfig{
+		MacroModel:  config.MacroModelSplit,
+		MacroSplits: map[string]config.MacroSplit{"weight_loss": {Protein: 40, Carbohydrates: 30, Fat: 30}},
+	}
+
+	// Neither a muscle_gain nor a maintenance split: 2.2 g protein per kg of 80 kg
+	macros := calculateMacroTargets(cfg, "muscle_gain", 2500, 80)
+	if math.Abs(macros.Protein-176) > 1e-9 || math.Abs(macros.Carbohydrates-320) > 1e-9 || math.Abs(macros.Fat-80) > 1e-9 {
+		t.Errorf("Expected 176g/320g/80g, got: %.1f/%.1f/%.1f", macros.Protein, macros.Carbohydrates, macros.Fat)
+	}
+	if unset := calculateMacroTargets(cfg, "", 2500, 80); unset.Protein == 0 {
+		t.Errorf("Expected an unset goal to fall back to the per-kg targets, got: %+v", unset)
+	}
+}
+
+func TestCalculateMacroTargets_SplitModelWithoutCalorieTargetUsesPerKgGrams(t *testing.T) {
+	cfg := config.UserConfig{
+		MacroModel:  config.MacroModelSplit,
+		MacroSplits: map[string]config.MacroSplit{"weight_loss": {Protein: 40, Carbohydrates: 30, Fat: 30}},
+	}
+
+	// Targets are stored as grams per day under split, so 1.6 g protein per kg of 70 kg is 112 g
+	macros := calculateMacroTargets(cfg, "weight_loss", 0, 70)
+	if math.Abs(macros.Protein-112) > 1e-9 || math.Abs(macros.Carbohydrates-140) > 1e-9 || math.Abs(macros.Fat-56) > 1e-9 {
+		t.Errorf("Expected 112g/140g/56g, got: %.1f/%.1f/%.1f", macros.Protein, macros.Carbohydrates, macros.Fat)
+	}
+}
+
+func TestUserService_CalculateTargets_UsesMacroSplit(t *testing.T) {
+	cfg := config.UserConfig{
+		MinCalorieTargetFemale: 1200,
+		MinCalorieTargetMale:   1500,
+		MacroModel:             config.MacroModelSplit,
+		MacroSplits:            map[string]config.MacroSplit{"weight_loss": {Protein: 40, Carbohydrates: 30, Fat: 30}},
+	}
+	svc := NewUserService(newFakeUserRepo(), nil, nil, nil, nil, cfg, logger.NewNoopLogger())
+
+	// The target is raised to the 1200 kcal floor, so the macros are 480, 360 and 360 kcal of it
+	targets, err := svc.CalculateTargets(context.Background(), &request.CalculateTargetsRequest{
+		Weight: 45, Height: 150, Age: 60, Gender: "female", Goal: "weight_loss", ActivityLevel: "sedentary",
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if targets.CalorieTarget != 1200 {
+		t.Fatalf("Expected a 1200 kcal target, got: %v", targets.CalorieTarget)
+	}
+	macros := targets.MacroTargets
+	if math.Abs(macros.Protein-120) > 0.01 || math.Abs(macros.Carbohydrates-90) > 0.01 || math.Abs(macros.Fat-40) > 0.01 {
+		t.Errorf("Expected 120g/90g/40g, got: %v/%v/%v", macros.Protein, macros.Carbohydrates, macros.Fat)
+	}
+}
+
+func TestUserService_CheckMeasurements_Boundaries(t *testing.T) {
+	svc := NewUserService(newFakeUserRepo(), nil, nil, nil, nil, config.UserConfig{}, logger.NewNoopLogger())
+	intPtr := func(v int) *int { return &v }
+	floatPtr := func(v float64) *float64 { return &v }
+
+	tests := []struct {
+		name      string
+		age       *int
+		height    *float64
+		weight    *float64
+		wantField string
+	}{
+		{name: "age at min", age: intPtr(1)},
+		{name: "age at max", age: intPtr(120)},
+		{name: "age below min", age: intPtr(0), wantField: "age"},
+		{name: "age above max", age: intPtr(121), wantField: "age"},
+		{name: "height at min", height: floatPtr(50)},
+		{name: "height at max", height: floatPtr(300)},
+		{name: "height below min", height: floatPtr(49.9), wantField: "height"},
+		{name: "height above max", height: floatPtr(300.1), wantField: "height"},
+		{name: "weight at min", weight: floatPtr(20)},
+		{name: "weight at max", weight: floatPtr(500)},
+		{name: "weight below min", weight: floatPtr(19.9), wantField: "weight"},
+		{name: "weight above max", weight: floatPtr(500.1), wantField: "weight"},
+		{name: "negative weight", weight: floatPtr(-70), wantField: "weight"},
+		{name: "NaN height", height: floatPtr(math.NaN()), wantField: "height"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := svc.checkMeasurements(tt.age, tt.height, tt.weight)
+			if tt.wantField == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got: %v", err)
+				}
+				return
+			}
+			profileErr, ok := err.(*ProfileValidationError)
+			if !ok {
+				t.Fatalf("Expected a *ProfileValidationError, got: %v", err)
+			}
+			if len(profileErr.Fields) != 1 || profileErr.Fields[tt.wantField] == "" {
+				t.Errorf("Expected only %s to fail, got: %v", tt.wantField, profileErr.Fields)
+			}
+		})
+	}
+}
+
+func TestUserService_CheckMeasurements_ConfiguredRanges(t *testing.T) {
+	cfg := config.UserConfig{MinAge: 18, MaxAge: 99, MaxWeight: 300}
+	svc := NewUserService(newFakeUserRepo(), nil, nil, nil, nil, cfg, logger.NewNoopLogger())
+	age, height, weight := 17, 40.0, 301.0
+
+	err := svc.checkMeasurements(&age, &height, &weight)
+	profileErr, ok := err.(*ProfileValidationError)
+	if !ok {
+		t.Fatalf("Expected a *ProfileValidationError, got: %v", err)
+	}
+	expected := map[string]string{
+		"age":    "must be between 18 and 99",
+		"height": "must be between 50 and 300 cm",
+		"weight": "must be between 20 and 300 kg",
+	}
+	for field, message := range expected {
+		if profileErr.Fields[field] != message {
+			t.Errorf("Expected %s to %s, got: %q", field, message, profileErr.Fields[field])
+		}
+	}
+	if !strings.HasPrefix(err.Error(), "validation failed: age must be between 18 and 99, height") {
+		t.Errorf("Expected the fields in order in the message, got: %v", err)
+	}
+}
+
+func TestUserService_UpdateProfile_RejectsOutOfRangeAndKeepsProfile(t *testing.T) {
+	user := &domain.User{ID: primitive.NewObjectID(), Profile: domain.UserProfile{Age: 30, Weight: 70, Height: 175}}
+	users := newFakeUserRepo(user)
+	svc := NewUserService(users, nil, nil, nil, nil, config.UserConfig{}, logger.NewNoopLogger())
+
+	height := 0.5
+	_, err := svc.UpdateProfile(context.Background(), user.ID.Hex(), &request.UpdateProfileRequest{Height: &height})
+	if _, ok := err.(*ProfileValidationError); !ok {
+		t.Fatalf("Expected a *ProfileValidationError, got: %v", err)
+	}
+	if stored, _ := users.GetByID(context.Background(), user.ID); stored.Profile.Height != 175 {
+		t.Errorf("Expected the stored height to stay 175, got: %v", stored.Profile.Height)
+	}
+}
+
+func TestUserService_UpdateProfile_ComputesTargetsForValidProfile(t *testing.T) {
+	user := &domain.User{ID: primitive.NewObjectID(), Profile: domain.UserProfile{Gender: "male"}}
+	users := newFakeUserRepo(user)
+	svc := NewUserService(users, nil, nil, nil, nil, config.UserConfig{}, logger.NewNoopLogger())
+
+	age, height, weight, goal := 1, 50.0, 20.0, "maintenance"
+	updated, err := svc.UpdateProfile(context.Background(), user.ID.Hex(), &request.UpdateProfileRequest{
+		Age: &age, Height: &height, Weight: &weight, Goal: &goal,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if updated.Preferences.CalorieTarget <= 0 {
+		t.Errorf("Expected a calorie target for a profile at the range limits, got: %v", updated.Preferences.CalorieTarget)
+	}
+}